@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -31,6 +32,12 @@ type APIClient struct {
 	self    lib.NodeID
 	logChan chan LoggerEvent
 	log     lib.Logger
+	dflush  chan struct{} // closed once discoveries are flushed (see DiscoveryInit)
+	dstop   chan struct{} // closed to stop taking discoveries, and flush them (see Drain)
+
+	mmutex   sync.Mutex
+	mstopped bool           // no more mutations are delivered once it's set (see Drain)
+	inflight sync.WaitGroup // mutations delivered to the module that it isn't done with
 }
 
 func NewAPIClient(sock string) *APIClient {
@@ -142,6 +149,101 @@ func (a *APIClient) QueryReadAllDsc() (r []lib.Node, e error) {
 	return
 }
 
+// QueryReadGroup reads the nodes in a group
+func (a *APIClient) QueryReadGroup(group string) (r []lib.Node, e error) {
+	q := &pb.Query{URL: group}
+	rvs, e := a.oneshot("QueryReadGroup", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
+// QueryReadGroupDsc reads the discovered state of the nodes in a group
+func (a *APIClient) QueryReadGroupDsc(group string) (r []lib.Node, e error) {
+	q := &pb.Query{URL: group}
+	rvs, e := a.oneshot("QueryReadGroupDsc", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
+// QueryTransact sets several (node qualified) URLs in the configuration state at once; either all are set, or none are
+func (a *APIClient) QueryTransact(vs map[string]reflect.Value) (e error) {
+	q, e := newTransaction(vs)
+	if e != nil {
+		return
+	}
+	_, e = a.oneshot("QueryTransact", reflect.ValueOf(q))
+	return
+}
+
+// QueryTransactDsc sets several (node qualified) URLs in the discoverable state at once; either all are set, or none are
+func (a *APIClient) QueryTransactDsc(vs map[string]reflect.Value) (e error) {
+	q, e := newTransaction(vs)
+	if e != nil {
+		return
+	}
+	_, e = a.oneshot("QueryTransactDsc", reflect.ValueOf(q))
+	return
+}
+
+// QuerySelect reads the nodes that match a selector expression, e.g. "/PhysState == POWER_OFF && /Platform == powerman"
+func (a *APIClient) QuerySelect(expr string) (r []lib.Node, e error) {
+	q := &pb.Query{URL: expr}
+	rvs, e := a.oneshot("QuerySelect", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
+// QuerySelectDsc reads the discovered state of the nodes that match a selector expression
+func (a *APIClient) QuerySelectDsc(expr string) (r []lib.Node, e error) {
+	q := &pb.Query{URL: expr}
+	rvs, e := a.oneshot("QuerySelectDsc", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
+// QueryUpdateGroup sets the values set in n on every node in a group
+func (a *APIClient) QueryUpdateGroup(group string, n lib.Node) (r []lib.Node, e error) {
+	q := &pb.Query{
+		URL: group,
+		Payload: &pb.Query_Node{
+			Node: n.Message().(*pb.Node),
+		},
+	}
+	rvs, e := a.oneshot("QueryUpdateGroup", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
 func (a *APIClient) QueryMutationNodes() (r pb.MutationNodeList, e error) {
 	q := &empty.Empty{}
 	rv, e := a.oneshot("QueryMutationNodes", reflect.ValueOf(q))
@@ -192,6 +294,154 @@ func (a *APIClient) QueryNodeMutationPath(id string) (r pb.MutationPath, e error
 	return
 }
 
+// QueryMutationPlan asks what mutations would be made to take a node from its current discoverable state to cfg
+func (a *APIClient) QueryMutationPlan(cfg lib.Node) (r pb.MutationPath, e error) {
+	q := &pb.Query{
+		URL: lib.NodeURLJoin(cfg.ID().String(), "/graph/plan"),
+		Payload: &pb.Query_Node{
+			Node: cfg.Message().(*pb.Node),
+		},
+	}
+	rv, e := a.oneshot("QueryMutationPlan", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = *rv.Interface().(*pb.Query).GetMutationPath()
+	return
+}
+
+// QueryEventLog gets event log records for a node (or every node, if id is empty) between from and to
+// zero times leave that end of the range open
+func (a *APIClient) QueryEventLog(id string, from, to time.Time) (r []*pb.EventRecord, e error) {
+	q := &pb.EventLogQuery{Node: id}
+	if !from.IsZero() {
+		if q.From, e = ptypes.TimestampProto(from); e != nil {
+			return
+		}
+	}
+	if !to.IsZero() {
+		if q.To, e = ptypes.TimestampProto(to); e != nil {
+			return
+		}
+	}
+	rv, e := a.oneshot("QueryEventLog", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.EventRecordList).GetRecords()
+	return
+}
+
+// QueryAudit records state changing API calls in the audit log
+func (a *APIClient) QueryAudit(rs []*pb.AuditRecord) (e error) {
+	_, e = a.oneshot("QueryAudit", reflect.ValueOf(&pb.AuditRecordList{Records: rs}))
+	return
+}
+
+// QueryAuditLog gets audit log records that match q
+func (a *APIClient) QueryAuditLog(q *pb.AuditLogQuery) (r []*pb.AuditRecord, e error) {
+	rv, e := a.oneshot("QueryAuditLog", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.AuditRecordList).GetRecords()
+	return
+}
+
+// QueryServiceStatus gets how the processes of the services on our kraken are doing
+func (a *APIClient) QueryServiceStatus() (r []*pb.ServiceStatus, e error) {
+	rv, e := a.oneshot("QueryServiceStatus", reflect.ValueOf(&empty.Empty{}))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.ServiceStatusList).GetServices()
+	return
+}
+
+// QueryConvergence gets whether each node's discovered state matches its configuration, and since when it hasn't
+func (a *APIClient) QueryConvergence() (r []*pb.NodeConvergence, e error) {
+	rv, e := a.oneshot("QueryConvergence", reflect.ValueOf(&empty.Empty{}))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.ConvergenceReport).GetNodes()
+	return
+}
+
+// QueryMutationMetrics gets the SME's mutation metrics
+func (a *APIClient) QueryMutationMetrics() (r pb.MutationMetrics, e error) {
+	rv, e := a.oneshot("QueryMutationMetrics", reflect.ValueOf(&empty.Empty{}))
+	if e != nil {
+		return
+	}
+	r = *rv.Interface().(*pb.MutationMetrics)
+	return
+}
+
+// QueryDecommission starts retiring a node, see QueryEngine.Decommission
+func (a *APIClient) QueryDecommission(id string, final map[string]string, remove bool) (r lib.Node, e error) {
+	q := &pb.DecommissionRequest{
+		Id:     id,
+		Final:  final,
+		Remove: remove,
+	}
+	rv, e := a.oneshot("QueryDecommission", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = NewNodeFromMessage(rv.Interface().(*pb.Query).GetNode())
+	return
+}
+
+// QueryDiscover asks modules to re-discover nodes now, rather than at their next poll.
+// nodes are node IDs (all nodes if empty); module limits the request to one module (all that can re-discover if empty).
+// It returns the IDs of the services that were asked.
+func (a *APIClient) QueryDiscover(nodes []string, module string) (r []string, e error) {
+	q := &pb.DiscoverRequest{
+		Nodes:  nodes,
+		Module: module,
+	}
+	rv, e := a.oneshot("QueryDiscover", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.DiscoverResponse).GetServices()
+	return
+}
+
+// QueryRollups gets summaries of the discovered state of nodes at location, and below it
+func (a *APIClient) QueryRollups(location string) (r []*pb.Rollup, e error) {
+	q := &pb.Query{URL: location}
+	rv, e := a.oneshot("QueryRollups", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	r = rv.Interface().(*pb.RollupList).GetRollups()
+	return
+}
+
+// QueryRestore replaces the whole configuration state with ns (except for the kraken we talk to)
+func (a *APIClient) QueryRestore(ns []lib.Node) (r []lib.Node, e error) {
+	q := &pb.QueryMulti{}
+	for _, n := range ns {
+		q.Queries = append(q.Queries, &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		})
+	}
+	rvs, e := a.oneshot("QueryRestore", reflect.ValueOf(q))
+	if e != nil {
+		return
+	}
+	mquery := rvs.Interface().(*pb.QueryMulti)
+	for _, q := range mquery.Queries {
+		r = append(r, NewNodeFromMessage(q.GetNode()))
+	}
+	return
+}
+
 func (a *APIClient) QueryDeleteAll() (r []lib.Node, e error) {
 	q := &empty.Empty{}
 	rvs, e := a.oneshot("QueryDeleteAll", reflect.ValueOf(q))
@@ -229,13 +479,77 @@ func (a *APIClient) ServiceInit(id string, module string) (c <-chan lib.ServiceC
 			if e != nil {
 				return
 			}
-			cc <- lib.ServiceControl{Command: lib.ServiceControl_Command(ctl.Command), Config: ctl.Config}
+			sc := lib.ServiceControl{Command: lib.ServiceControl_Command(ctl.Command), Config: ctl.Config, Nodes: ctl.Nodes}
+			if ctl.Deadline != nil {
+				sc.Deadline, _ = ptypes.Timestamp(ctl.Deadline)
+			}
+			cc <- sc
+		}
+	}()
+	c = cc
+	return
+}
+
+// Watch subscribes to state changes that match w.  The channel is closed if the watch ends.
+func (a *APIClient) Watch(w *pb.WatchRequest) (c <-chan *pb.StateChange, e error) {
+	return a.WatchContext(context.Background(), w)
+}
+
+// WatchContext is Watch, but the watch ends (and the channel is closed) when ctx is done
+func (a *APIClient) WatchContext(ctx context.Context, w *pb.WatchRequest) (c <-chan *pb.StateChange, e error) {
+	var stream grpc.ClientStream
+	if stream, e = a.serverStreamContext(ctx, "Watch", reflect.ValueOf(w)); e != nil {
+		return
+	}
+	cc := make(chan *pb.StateChange)
+	go func() {
+		defer close(cc)
+		for {
+			sc, e := stream.(pb.API_WatchClient).Recv()
+			if e != nil {
+				a.Logf(INFO, "watch stream closed: %v", e)
+				return
+			}
+			select {
+			case cc <- sc:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 	c = cc
 	return
 }
 
+// mutationAcks keeps track of which queued mutations a module is done with.
+// Acknowledging a mutation acknowledges everything before it, so it waits for the oldest one still in flight.
+type mutationAcks struct {
+	mutex   sync.Mutex
+	pending []uint64 // in the order they were received
+	done    map[uint64]bool
+}
+
+func (m *mutationAcks) add(seq uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pending = append(m.pending, seq)
+}
+
+// finish marks seq done, and returns the newest seq that can now be acknowledged, or 0 if there isn't one
+func (m *mutationAcks) finish(seq uint64) (ack uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.done[seq] = true
+	for len(m.pending) > 0 && m.done[m.pending[0]] {
+		ack = m.pending[0]
+		delete(m.done, ack)
+		m.pending = m.pending[1:]
+	}
+	return
+}
+
+// MutationInit sets up the stream of mutations for a module.
+// Queued mutations are acknowledged once the module calls Done on them (see MutationEvent.Done).
 func (a *APIClient) MutationInit(id string, module string) (c <-chan lib.Event, e error) {
 	var stream grpc.ClientStream
 	if stream, e = a.serverStream("MutationInit", reflect.ValueOf(&pb.ServiceInitRequest{Id: id, Module: module})); e != nil {
@@ -243,23 +557,71 @@ func (a *APIClient) MutationInit(id string, module string) (c <-chan lib.Event,
 	}
 	cc := make(chan lib.Event)
 	go func() {
+		// a node's mutation is canceled by an interrupt, or the node's next mutation
+		cancels := make(map[string]context.CancelFunc)
+		acks := &mutationAcks{done: make(map[uint64]bool)}
+		defer func() {
+			for _, cancel := range cancels {
+				cancel()
+			}
+		}()
 		for {
-			var mc *pb.MutationControl
-			if mc, e = stream.(pb.API_MutationInitClient).Recv(); e != nil {
+			mc, e := stream.(pb.API_MutationInitClient).Recv()
+			if e != nil {
 				fmt.Printf("got stream read error on mutation stream: %v\n", e)
 				return
 			}
 			cfg := NewNodeFromMessage(mc.GetCfg())
 			dsc := NewNodeFromMessage(mc.GetDsc())
+			me := &MutationEvent{
+				Type:     mc.GetType(),
+				NodeCfg:  cfg,
+				NodeDsc:  dsc,
+				Mutation: [2]string{mc.GetModule(), mc.GetId()},
+				Context:  context.Background(),
+			}
+			nid := cfg.ID().String()
+			if cancel, ok := cancels[nid]; ok {
+				cancel()
+				delete(cancels, nid)
+			}
+			if me.Type == MutationEvent_MUTATE {
+				me.Context, cancels[nid] = context.WithCancel(context.Background())
+			}
+			a.mmutex.Lock()
+			if a.mstopped {
+				// we're shutting down; a queued mutation will be sent again when we're back
+				// we don't return, that would cancel the mutations we're letting finish
+				a.mmutex.Unlock()
+				continue
+			}
+			a.inflight.Add(1)
+			a.mmutex.Unlock()
+			seq := mc.GetSeq()
+			if seq != 0 {
+				// it's queued; let the queue know once the module is done with it
+				acks.add(seq)
+			}
+			var once sync.Once
+			me.done = func() {
+				once.Do(func() {
+					defer a.inflight.Done()
+					if seq == 0 {
+						return
+					}
+					ack := acks.finish(seq)
+					if ack == 0 {
+						return
+					}
+					if _, e := a.oneshot("MutationAck", reflect.ValueOf(&pb.MutationAckRequest{Module: module, Seq: ack})); e != nil {
+						a.Logf(ERROR, "failed to acknowledge mutation %d: %v", ack, e)
+					}
+				})
+			}
 			cc <- NewEvent(
 				lib.Event_STATE_MUTATION,
-				cfg.ID().String(),
-				&MutationEvent{
-					Type:     mc.GetType(),
-					NodeCfg:  cfg,
-					NodeDsc:  dsc,
-					Mutation: [2]string{mc.GetModule(), mc.GetId()},
-				})
+				nid,
+				me)
 		}
 	}()
 	c = cc
@@ -277,9 +639,21 @@ func (a *APIClient) DiscoveryInit() (c chan<- lib.Event, e error) {
 		return
 	}
 	cc := make(chan lib.Event)
+	a.dflush = make(chan struct{})
+	a.dstop = make(chan struct{})
 	go func() {
+		// closing the channel (or dstop) flushes the stream
+		defer close(a.dflush)
 		for {
-			v := <-cc
+			var v lib.Event
+			ok := false
+			select {
+			case v, ok = <-cc:
+			case <-a.dstop:
+			}
+			if !ok {
+				break
+			}
 			de, ok := v.Data().(*DiscoveryEvent)
 			if !ok {
 				a.Logf(ERROR, "got event that is not *DiscoveryEvent: %v", v.Data())
@@ -290,16 +664,50 @@ func (a *APIClient) DiscoveryInit() (c chan<- lib.Event, e error) {
 				Url:     de.URL,
 				ValueId: de.ValueID,
 			}
+			if !de.Time.IsZero() {
+				d.Time, _ = ptypes.TimestampProto(de.Time)
+			}
 			if e = stream.Send(d); e != nil {
 				a.Logf(CRITICAL, "got stream send error on discovery stream: %v\n", e)
 				return
 			}
 		}
+		if _, e := stream.CloseAndRecv(); e != nil {
+			a.Logf(ERROR, "failed to flush discovery stream: %v", e)
+		}
 	}()
 	c = cc
 	return
 }
 
+// Drain stops delivering mutations, waits for the module to be done with the ones it has, then flushes its discoveries.
+// It returns an error if that isn't finished before ctx is.  Discoveries sent after it returns may be lost.
+func (a *APIClient) Drain(ctx context.Context) error {
+	a.mmutex.Lock()
+	a.mstopped = true
+	a.mmutex.Unlock()
+	done := make(chan struct{})
+	go func() {
+		a.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("mutations still in progress: %v", ctx.Err())
+	}
+	if a.dstop == nil { // no discovery stream
+		return nil
+	}
+	close(a.dstop)
+	select {
+	case <-a.dflush:
+	case <-ctx.Done():
+		return fmt.Errorf("discoveries not flushed: %v", ctx.Err())
+	}
+	return nil
+}
+
 func (a *APIClient) LoggerInit(si string) (e error) {
 	var stream pb.API_LoggerInitClient
 	var conn *grpc.ClientConn
@@ -362,20 +770,54 @@ func (a *APIClient) oneshot(call string, in reflect.Value) (out reflect.Value, e
 	return
 }
 
+// newTransaction packs values into a Transaction
+func newTransaction(vs map[string]reflect.Value) (q *pb.Transaction, e error) {
+	q = &pb.Transaction{}
+	ns := make(map[string]*Node)
+	for u, v := range vs {
+		node, url := lib.NodeURLSplit(u)
+		nid := NewNodeID(node)
+		n, ok := ns[nid.String()]
+		if !ok {
+			n = NewNodeWithID(nid.String())
+			ns[nid.String()] = n
+		}
+		if _, e = n.SetValue(url, v); e != nil {
+			return nil, fmt.Errorf("bad transaction value for %s: %v", u, e)
+		}
+		q.Urls = append(q.Urls, u)
+	}
+	for _, n := range ns {
+		q.Nodes = append(q.Nodes, n.Message().(*pb.Node))
+	}
+	return
+}
+
 func (a *APIClient) serverStream(call string, in reflect.Value) (out grpc.ClientStream, e error) {
+	return a.serverStreamContext(context.Background(), call, in)
+}
+
+// serverStreamContext opens a server stream that's closed, along with its connection, when ctx is done
+func (a *APIClient) serverStreamContext(ctx context.Context, call string, in reflect.Value) (out grpc.ClientStream, e error) {
 	var conn *grpc.ClientConn
 	conn, e = grpc.Dial(a.sock, grpc.WithInsecure())
 	if e != nil {
 		return
 	}
 	//defer conn.Close()
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+	}
 	c := pb.NewAPIClient(conn)
 	fv := reflect.ValueOf(c).MethodByName(call)
 	if fv.IsNil() {
 		e = fmt.Errorf("no such API call: %s", call)
 		return
 	}
-	r := fv.Call([]reflect.Value{reflect.ValueOf(context.Background()), in})
+	r := fv.Call([]reflect.Value{reflect.ValueOf(ctx), in})
 	if len(r) != 2 {
 		// ?!
 		e = fmt.Errorf("bad API call result: %s", call)
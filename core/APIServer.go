@@ -15,7 +15,10 @@ package core
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"reflect"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 
@@ -37,6 +40,7 @@ type DiscoveryEvent struct {
 	Module  string
 	URL     string // fully qualified, with node
 	ValueID string
+	Time    time.Time // when the value was observed, so out of date discoveries can be ignored; zero means now
 }
 
 func (de *DiscoveryEvent) String() string {
@@ -59,6 +63,12 @@ type APIServer struct {
 	sm    lib.ServiceManager
 	schan chan<- lib.EventListener
 	self  lib.NodeID
+	elog  EventLog
+	alog  AuditLog
+	qcfg  ContextEventQueue
+	// queues are by module; they're opened by Run, and notify wakes up the module's mutation stream
+	queues map[string]EventQueue
+	notify map[string]chan struct{}
 }
 
 // NewAPIServer creates a new, initialized API
@@ -72,6 +82,9 @@ func NewAPIServer(ctx Context) *APIServer {
 		sm:    ctx.Services,
 		schan: ctx.SubChan,
 		self:  ctx.Self,
+		elog:  ctx.EventLog.Log,
+		alog:  ctx.AuditLog.Log,
+		qcfg:  ctx.EventQueue,
 	}
 	api.log.SetModule("API")
 	return api
@@ -195,6 +208,116 @@ func (s *APIServer) QueryReadAllDsc(ctx context.Context, in *empty.Empty) (out *
 	return
 }
 
+func (s *APIServer) QueryReadGroup(ctx context.Context, in *pb.Query) (out *pb.QueryMulti, e error) {
+	var nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	nout, e = s.query.ReadGroup(in.URL)
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
+func (s *APIServer) QueryReadGroupDsc(ctx context.Context, in *pb.Query) (out *pb.QueryMulti, e error) {
+	var nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	nout, e = s.query.ReadGroupDsc(in.URL)
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
+func (s *APIServer) QueryTransact(ctx context.Context, in *pb.Transaction) (out *empty.Empty, e error) {
+	out = &empty.Empty{}
+	vs, e := transactionValues(in)
+	if e != nil {
+		return
+	}
+	e = s.query.Transact(vs)
+	return
+}
+
+func (s *APIServer) QueryTransactDsc(ctx context.Context, in *pb.Transaction) (out *empty.Empty, e error) {
+	out = &empty.Empty{}
+	vs, e := transactionValues(in)
+	if e != nil {
+		return
+	}
+	e = s.query.TransactDsc(vs)
+	return
+}
+
+func (s *APIServer) QuerySelect(ctx context.Context, in *pb.Query) (out *pb.QueryMulti, e error) {
+	var nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	nout, e = s.query.Select(in.URL)
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
+func (s *APIServer) QuerySelectDsc(ctx context.Context, in *pb.Query) (out *pb.QueryMulti, e error) {
+	var nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	nout, e = s.query.SelectDsc(in.URL)
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
+func (s *APIServer) QueryUpdateGroup(ctx context.Context, in *pb.Query) (out *pb.QueryMulti, e error) {
+	var nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	pbin := in.GetNode()
+	if pbin == nil {
+		e = fmt.Errorf("update group query must contain a valid node")
+		return
+	}
+	nout, e = s.query.UpdateGroup(in.URL, NewNodeFromMessage(pbin))
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
 func (s *APIServer) QueryMutationNodes(ctx context.Context, in *empty.Empty) (out *pb.Query, e error) {
 	var mnlout pb.MutationNodeList
 	url := "/graph/nodes"
@@ -262,6 +385,168 @@ func (s *APIServer) QueryNodeMutationPath(ctx context.Context, in *pb.Query) (ou
 	return
 }
 
+func (s *APIServer) QueryMutationPlan(ctx context.Context, in *pb.Query) (out *pb.Query, e error) {
+	var mpout pb.MutationPath
+	pbin := in.GetNode()
+	out = &pb.Query{}
+	if pbin == nil {
+		e = fmt.Errorf("plan query must contain a valid node")
+		return
+	}
+	mpout, e = s.query.ReadMutationPlan(NewNodeFromMessage(pbin))
+	out.URL = in.URL
+	if e == nil {
+		out.Payload = &pb.Query_MutationPath{
+			MutationPath: &mpout,
+		}
+	}
+	return
+}
+
+func (s *APIServer) QueryEventLog(ctx context.Context, in *pb.EventLogQuery) (out *pb.EventRecordList, e error) {
+	out = &pb.EventRecordList{}
+	if s.elog == nil {
+		e = fmt.Errorf("the event log is not enabled")
+		return
+	}
+	var from, to time.Time
+	if in.From != nil {
+		if from, e = ptypes.Timestamp(in.From); e != nil {
+			return
+		}
+	}
+	if in.To != nil {
+		if to, e = ptypes.Timestamp(in.To); e != nil {
+			return
+		}
+	}
+	out.Records, e = s.elog.Query(in.Node, from, to)
+	return
+}
+
+// QueryAudit appends records to the audit log; they're dropped if there isn't one
+func (s *APIServer) QueryAudit(ctx context.Context, in *pb.AuditRecordList) (out *empty.Empty, e error) {
+	out = &empty.Empty{}
+	if s.alog == nil {
+		return
+	}
+	for _, r := range in.GetRecords() {
+		if r.Time == nil {
+			r.Time = ptypes.TimestampNow()
+		}
+		if e = s.alog.Append(r); e != nil {
+			s.Logf(ERROR, "failed to record audit record: %v", e)
+			return
+		}
+	}
+	return
+}
+
+func (s *APIServer) QueryAuditLog(ctx context.Context, in *pb.AuditLogQuery) (out *pb.AuditRecordList, e error) {
+	out = &pb.AuditRecordList{}
+	if s.alog == nil {
+		e = fmt.Errorf("the audit log is not enabled")
+		return
+	}
+	out.Records, e = s.alog.Query(in)
+	return
+}
+
+// QueryServiceStatus reports how the processes of our services are doing
+func (s *APIServer) QueryServiceStatus(ctx context.Context, in *empty.Empty) (out *pb.ServiceStatusList, e error) {
+	return &pb.ServiceStatusList{Services: s.sm.Status()}, nil
+}
+
+// QueryConvergence reports whether each node's discovered state matches its configuration
+func (s *APIServer) QueryConvergence(ctx context.Context, in *empty.Empty) (out *pb.ConvergenceReport, e error) {
+	out = &pb.ConvergenceReport{}
+	out.Nodes, e = s.query.ReadConvergence()
+	return
+}
+
+func (s *APIServer) QueryMutationMetrics(ctx context.Context, in *empty.Empty) (out *pb.MutationMetrics, e error) {
+	mm, e := s.query.ReadMutationMetrics()
+	return &mm, e
+}
+
+func (s *APIServer) QueryDecommission(ctx context.Context, in *pb.DecommissionRequest) (out *pb.Query, e error) {
+	var nout lib.Node
+	out = &pb.Query{URL: in.Id}
+	nout, e = s.query.Decommission(NewNodeID(in.Id), in.Final, in.Remove)
+	if nout != nil {
+		out.Payload = &pb.Query_Node{Node: nout.Message().(*pb.Node)}
+	}
+	return
+}
+
+func (s *APIServer) QueryRollups(ctx context.Context, in *pb.Query) (out *pb.RollupList, e error) {
+	out = &pb.RollupList{}
+	out.Rollups, e = s.query.ReadRollups(in.URL)
+	return
+}
+
+// QueryDiscover sends a DISCOVER control to each ready service whose module can re-discover (or just to those of in.Module)
+func (s *APIServer) QueryDiscover(ctx context.Context, in *pb.DiscoverRequest) (out *pb.DiscoverResponse, e error) {
+	out = &pb.DiscoverResponse{}
+	if in.Module != "" {
+		m, ok := Registry.Modules[in.Module]
+		if !ok {
+			return nil, fmt.Errorf("no such module: %s", in.Module)
+		}
+		if _, ok := m.(lib.ModuleWithRediscover); !ok {
+			return nil, fmt.Errorf("module can't re-discover: %s", in.Module)
+		}
+	}
+	for _, id := range in.Nodes {
+		if NewNodeID(id).Nil() {
+			return nil, fmt.Errorf("invalid node ID: %s", id)
+		}
+	}
+	for _, sid := range s.sm.GetServiceIDs() {
+		srv := s.sm.Service(sid)
+		if srv == nil || !srv.Ready() {
+			continue
+		}
+		if in.Module != "" && srv.Module() != in.Module {
+			continue
+		}
+		if _, ok := Registry.Modules[srv.Module()].(lib.ModuleWithRediscover); !ok {
+			continue
+		}
+		srv.Discover(in.Nodes)
+		out.Services = append(out.Services, sid)
+	}
+	if in.Module != "" && len(out.Services) == 0 {
+		return nil, fmt.Errorf("module has no running services: %s", in.Module)
+	}
+	return
+}
+
+func (s *APIServer) QueryRestore(ctx context.Context, in *pb.QueryMulti) (out *pb.QueryMulti, e error) {
+	var nin, nout []lib.Node
+	out = &pb.QueryMulti{}
+	out.Queries = []*pb.Query{}
+	for _, q := range in.Queries {
+		pbin := q.GetNode()
+		if pbin == nil {
+			e = fmt.Errorf("restore query must only contain valid nodes")
+			return
+		}
+		nin = append(nin, NewNodeFromMessage(pbin))
+	}
+	nout, e = s.query.Restore(nin)
+	for _, n := range nout {
+		q := &pb.Query{
+			URL: n.ID().String(),
+			Payload: &pb.Query_Node{
+				Node: n.Message().(*pb.Node),
+			},
+		}
+		out.Queries = append(out.Queries, q)
+	}
+	return
+}
+
 func (s *APIServer) QueryDeleteAll(ctx context.Context, in *empty.Empty) (out *pb.QueryMulti, e error) {
 	var nout []lib.Node
 	out = &pb.QueryMulti{}
@@ -285,6 +570,9 @@ func (s *APIServer) QueryDeleteAll(ctx context.Context, in *empty.Empty) (out *p
 
 func (s *APIServer) ServiceInit(sir *pb.ServiceInitRequest, stream pb.API_ServiceInitServer) (e error) {
 	srv := s.sm.Service(sir.GetId())
+	if srv == nil {
+		return fmt.Errorf("no such service: %s", sir.GetId())
+	}
 
 	self, _ := s.query.Read(s.self)
 	any, _ := ptypes.MarshalAny(self.Message())
@@ -300,12 +588,22 @@ func (s *APIServer) ServiceInit(sir *pb.ServiceInitRequest, stream pb.API_Servic
 	}
 	c := make(chan lib.ServiceControl)
 	srv.SetCtl(c)
+	// it's called in, so it's ready; anything waiting on it can start
+	url := lib.NodeURLJoin(s.self.String(), lib.URLPush(lib.URLPush("/Services", srv.ID()), "State"))
+	if _, e := s.query.SetValueDsc(url, reflect.ValueOf(pb.ServiceInstance_RUN)); e != nil {
+		s.Logf(ERROR, "couldn't mark %s running: %v", srv.ID(), e)
+	}
 	for {
 		ctl := <-c
-		stream.Send(&pb.ServiceControl{
+		sc := &pb.ServiceControl{
 			Command: pb.ServiceControl_Command(ctl.Command),
 			Config:  ctl.Config,
-		})
+			Nodes:   ctl.Nodes,
+		}
+		if !ctl.Deadline.IsZero() {
+			sc.Deadline, _ = ptypes.TimestampProto(ctl.Deadline)
+		}
+		stream.Send(sc)
 	}
 }
 
@@ -317,6 +615,9 @@ func (s *APIServer) ServiceInit(sir *pb.ServiceInitRequest, stream pb.API_Servic
 // This just caputures (filtered) mutation events and sends them over the stream
 func (s *APIServer) MutationInit(sir *pb.ServiceInitRequest, stream pb.API_MutationInitServer) (e error) {
 	module := sir.GetModule()
+	if q, ok := s.queues[module]; ok {
+		return s.queuedMutationStream(module, q, stream)
+	}
 	echan := make(chan lib.Event)
 	list := NewEventListener("MutationFor:"+module, lib.Event_STATE_MUTATION,
 		func(e lib.Event) bool {
@@ -332,14 +633,7 @@ func (s *APIServer) MutationInit(sir *pb.ServiceInitRequest, stream pb.API_Mutat
 
 	for {
 		v := <-echan
-		smev := v.Data().(*MutationEvent)
-		mc := &pb.MutationControl{
-			Module: smev.Mutation[0],
-			Id:     smev.Mutation[1],
-			Type:   smev.Type,
-			Cfg:    smev.NodeCfg.Message().(*pb.Node),
-			Dsc:    smev.NodeDsc.Message().(*pb.Node),
-		}
+		mc := mutationControl(v.Data().(*MutationEvent))
 		if e := stream.Send(mc); e != nil {
 			s.Logf(INFO, "mutation stream closed: %v", e)
 			break
@@ -352,11 +646,179 @@ func (s *APIServer) MutationInit(sir *pb.ServiceInitRequest, stream pb.API_Mutat
 	return
 }
 
+// MutationAck acknowledges the mutations a module has taken from its queue
+func (s *APIServer) MutationAck(ctx context.Context, in *pb.MutationAckRequest) (out *empty.Empty, e error) {
+	out = &empty.Empty{}
+	q, ok := s.queues[in.Module]
+	if !ok {
+		return out, fmt.Errorf("mutations for %s aren't queued", in.Module)
+	}
+	return out, q.Ack(in.Seq)
+}
+
+// queuedMutationStream sends a module's queued mutations over the stream, starting with any that haven't
+// been acknowledged (e.g. because the module restarted before it was done with them).  A module should have one stream.
+// Mutations that were waiting for the module are only sent if their node is still on them (see stillWanted).
+func (s *APIServer) queuedMutationStream(module string, q EventQueue, stream pb.API_MutationInitServer) (e error) {
+	done := stream.Context().Done()
+	var sent uint64 // everything up to here has been sent on this stream
+	backlog := true
+	for {
+		ms, e := q.Pending()
+		if e != nil {
+			return e
+		}
+		oldest := backlog // nothing before this mutation is still pending
+		for _, mc := range ms {
+			if mc.Seq <= sent {
+				continue
+			}
+			sent = mc.Seq
+			if backlog && !s.stillWanted(mc) {
+				s.Logf(INFO, "not resending queued mutation %s:%s, its node has moved on", mc.Module, mc.Id)
+				if oldest {
+					// otherwise, it's acknowledged with whatever comes after it
+					q.Ack(mc.Seq)
+				}
+				continue
+			}
+			oldest = false
+			if e := stream.Send(mc); e != nil {
+				s.Logf(INFO, "mutation stream closed: %v", e)
+				return nil
+			}
+		}
+		backlog = false
+		select {
+		case <-s.notify[module]:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// stillWanted reports whether a queued mutation is still what its node's active mutation path is waiting on.
+// Paths are dropped (and new ones started) when nodes change, and when kraken restarts, so older mutations
+// may no longer be wanted.  Interrupts are always sent; interrupting something that isn't happening does nothing.
+func (s *APIServer) stillWanted(mc *pb.MutationControl) bool {
+	if mc.Type != pb.MutationControl_MUTATE {
+		return true
+	}
+	nid := NewNodeIDFromBinary(mc.GetCfg().GetId())
+	p, e := s.query.ReadNodeMutationPath(lib.NodeURLJoin(nid.String(), ""))
+	if e != nil || p.Cur < 0 || p.Cur >= int64(len(p.Chain)) {
+		return false
+	}
+	cur := p.Chain[p.Cur]
+	return cur.Module == mc.Module && cur.Mutation == mc.Id
+}
+
+// openQueues opens a queue for each module that has mutations, and subscribes them to mutation events.
+// They're subscribed for as long as we run, so mutations are kept while modules aren't listening.
+func (s *APIServer) openQueues() {
+	s.queues = make(map[string]EventQueue)
+	s.notify = make(map[string]chan struct{})
+	for module := range Registry.Mutations {
+		q, e := OpenEventQueue(s.qcfg.Backend, s.qcfg.Location, module)
+		if e != nil {
+			s.Logf(ERROR, "mutations for %s won't be queued: %v", module, e)
+			continue
+		}
+		notify := make(chan struct{}, 1)
+		s.queues[module], s.notify[module] = q, notify
+		m := module
+		s.schan <- NewEventListener("MutationQueue:"+m, lib.Event_STATE_MUTATION,
+			func(v lib.Event) bool { return v.Data().(*MutationEvent).Mutation[0] == m },
+			func(v lib.Event) error {
+				if e := q.Push(mutationControl(v.Data().(*MutationEvent))); e != nil {
+					return e
+				}
+				select {
+				case notify <- struct{}{}:
+				default: // already woken up
+				}
+				return nil
+			})
+	}
+}
+
+// mutationControl makes the message for a mutation event
+func mutationControl(smev *MutationEvent) *pb.MutationControl {
+	return &pb.MutationControl{
+		Module: smev.Mutation[0],
+		Id:     smev.Mutation[1],
+		Type:   smev.Type,
+		Cfg:    smev.NodeCfg.Message().(*pb.Node),
+		Dsc:    smev.NodeDsc.Message().(*pb.Node),
+	}
+}
+
+// Watch streams the state changes that match a WatchRequest until the client goes away
+func (s *APIServer) Watch(in *pb.WatchRequest, stream pb.API_WatchServer) (e error) {
+	done := stream.Context().Done()
+	echan := make(chan lib.Event, 1024)
+	// values are only known once we look them up, so the listener can't filter on them
+	pre := &pb.WatchRequest{Node: in.Node, Url: in.Url, Types: in.Types}
+	list := NewEventListener(fmt.Sprintf("Watch:%p", stream), lib.Event_STATE_CHANGE,
+		func(v lib.Event) bool {
+			return WatchMatch(pre, NewStateChange(v.Data().(*StateChangeEvent), reflect.Value{}))
+		},
+		func(v lib.Event) error {
+			// we can't block the dispatcher once we've gone away
+			select {
+			case echan <- v:
+			case <-done:
+			}
+			return nil
+		})
+	// subscribe our listener
+	s.schan <- list
+
+	for {
+		var v lib.Event
+		select {
+		case v = <-echan:
+		case <-done:
+		}
+		if v == nil {
+			s.Log(DEBUG, "watch stream closed")
+			break
+		}
+		sce := v.Data().(*StateChangeEvent)
+		val := sce.Value
+		if !val.IsValid() {
+			switch sce.Type {
+			case StateChange_UPDATE:
+				val, _ = s.query.GetValueDsc(sce.URL)
+			case StateChange_CFG_UPDATE:
+				val, _ = s.query.GetValue(sce.URL)
+			}
+		}
+		c := NewStateChange(sce, val)
+		if !WatchMatch(in, c) {
+			continue
+		}
+		if e = stream.Send(c); e != nil {
+			s.Logf(INFO, "watch stream closed: %v", e)
+			break
+		}
+	}
+
+	// politely unsubscribe
+	list.SetState(lib.EventListener_UNSUBSCRIBE)
+	s.schan <- list
+	return
+}
+
 // DiscoveryInit handles discoveries from nodes
 // This dispatches nodes
 func (s *APIServer) DiscoveryInit(stream pb.API_DiscoveryInitServer) (e error) {
 	for {
 		dc, e := stream.Recv()
+		if e == io.EOF {
+			// the module is flushing its discoveries; they've all been emitted
+			return stream.SendAndClose(&empty.Empty{})
+		}
 		if e != nil {
 			s.Logf(INFO, "discovery stream closed: %v", e)
 			break
@@ -366,6 +828,9 @@ func (s *APIServer) DiscoveryInit(stream pb.API_DiscoveryInitServer) (e error) {
 			URL:     dc.GetUrl(),
 			ValueID: dc.GetValueId(),
 		}
+		if dc.GetTime() != nil {
+			dv.Time, _ = ptypes.Timestamp(dc.GetTime())
+		}
 		v := NewEvent(
 			lib.Event_DISCOVERY,
 			dc.GetUrl(),
@@ -388,9 +853,33 @@ func (s *APIServer) LoggerInit(stream pb.API_LoggerInitServer) (e error) {
 	return
 }
 
+// transactionValues gets the values to set from a Transaction
+func transactionValues(in *pb.Transaction) (vs map[string]reflect.Value, e error) {
+	ns := make(map[string]lib.Node)
+	for _, m := range in.Nodes {
+		n := NewNodeFromMessage(m)
+		ns[n.ID().String()] = n
+	}
+	vs = make(map[string]reflect.Value)
+	for _, u := range in.Urls {
+		node, url := lib.NodeURLSplit(u)
+		n, ok := ns[NewNodeID(node).String()]
+		if !ok {
+			return nil, fmt.Errorf("transaction has no node for %s", u)
+		}
+		if vs[u], e = n.GetValue(url); e != nil {
+			return nil, fmt.Errorf("transaction has no value for %s: %v", u, e)
+		}
+	}
+	return
+}
+
 // Run starts the API service listener
 func (s *APIServer) Run() {
 	s.Log(INFO, "starting API")
+	if s.qcfg.Backend != "" {
+		s.openQueues()
+	}
 	srv := grpc.NewServer()
 	pb.RegisterAPIServer(srv, s)
 	reflection.Register(srv)
@@ -0,0 +1,188 @@
+/* AuditLog.go: the AuditLog keeps an append-only record of who changed what through the APIs
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// An AuditLog is an append-only record of state changing API calls
+type AuditLog interface {
+	Append(r *pb.AuditRecord) error
+	// Query gets the records that match q
+	Query(q *pb.AuditLogQuery) ([]*pb.AuditRecord, error)
+	Close() error
+}
+
+// An AuditLogOpener opens an AuditLog at a backend specific location (e.g. a file)
+type AuditLogOpener func(location string) (AuditLog, error)
+
+// AuditLogs maps backend names to their openers
+var AuditLogs = map[string]AuditLogOpener{}
+
+// RegisterAuditLog makes an AuditLog backend available by name
+// It's probably a good idea for this to be done in init()
+func RegisterAuditLog(name string, o AuditLogOpener) {
+	if _, ok := AuditLogs[name]; !ok {
+		AuditLogs[name] = o
+	}
+}
+
+// OpenAuditLog opens an AuditLog with a registered backend
+func OpenAuditLog(backend, location string) (AuditLog, error) {
+	o, ok := AuditLogs[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown audit log backend: %s", backend)
+	}
+	return o(location)
+}
+
+// NewAuditChanges finds the values that differ between the old and new versions of a node.
+// old is nil if the node was created, and new is nil if it was deleted.
+func NewAuditChanges(old, new lib.Node) (cs []*pb.AuditChange) {
+	if old == nil && new == nil {
+		return
+	}
+	if old == nil {
+		old = NewNodeFromMessage(&pb.Node{Id: new.ID().Binary()})
+	}
+	if new == nil {
+		new = NewNodeFromMessage(&pb.Node{Id: old.ID().Binary()})
+	}
+	diff, _ := old.Diff(new, "")
+	for _, u := range diff {
+		cs = append(cs, &pb.AuditChange{
+			Url:      u,
+			OldValue: auditValue(old, u),
+			NewValue: auditValue(new, u),
+		})
+	}
+	return
+}
+
+// auditValue gets a value of a node as it would be printed; values that can't be read are empty
+func auditValue(n lib.Node, url string) string {
+	v, e := n.GetValue(url)
+	if e != nil || !v.IsValid() {
+		return ""
+	}
+	return lib.ValueToString(v)
+}
+
+// auditRecordMatch is a helper for AuditLog implementations
+func auditRecordMatch(r *pb.AuditRecord, q *pb.AuditLogQuery) bool {
+	if q.GetNode() != "" && !NewNodeID(r.Node).Equal(NewNodeID(q.GetNode())) {
+		return false
+	}
+	if q.GetUser() != "" && r.User != q.GetUser() {
+		return false
+	}
+	t, e := ptypes.Timestamp(r.Time)
+	if e != nil {
+		return false
+	}
+	if q.GetFrom() != nil {
+		if from, e := ptypes.Timestamp(q.GetFrom()); e == nil && t.Before(from) {
+			return false
+		}
+	}
+	if q.GetTo() != nil {
+		if to, e := ptypes.Timestamp(q.GetTo()); e == nil && t.After(to) {
+			return false
+		}
+	}
+	return true
+}
+
+////////////////////////////
+// FileAuditLog Object /
+//////////////////////////
+
+var _ AuditLog = (*FileAuditLog)(nil)
+
+// A FileAuditLog appends records to a file, one JSON object per line.
+// As with a FileEventLog, queries read the whole file.
+type FileAuditLog struct {
+	path  string
+	f     *os.File
+	mutex sync.Mutex
+}
+
+// NewFileAuditLog opens (creating if needed) a FileAuditLog at path
+func NewFileAuditLog(path string) (AuditLog, error) {
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if e != nil {
+		return nil, fmt.Errorf("could not open audit log: %v", e)
+	}
+	return &FileAuditLog{path: path, f: f}, nil
+}
+
+// Append writes a record, and syncs it to disk so it isn't lost
+func (l *FileAuditLog) Append(r *pb.AuditRecord) error {
+	m := jsonpb.Marshaler{}
+	s, e := m.MarshalToString(r)
+	if e != nil {
+		return e
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, e = l.f.WriteString(s + "\n"); e != nil {
+		return e
+	}
+	return l.f.Sync()
+}
+
+// Query reads through the log for matching records
+// lines that can't be read as a record are skipped
+func (l *FileAuditLog) Query(q *pb.AuditLogQuery) (rs []*pb.AuditRecord, e error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	f, e := os.Open(l.path)
+	if e != nil {
+		return
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024) // records of big changes can be long
+	for s.Scan() {
+		r := &pb.AuditRecord{}
+		if jsonpb.Unmarshal(strings.NewReader(s.Text()), r) != nil {
+			continue
+		}
+		if auditRecordMatch(r, q) {
+			rs = append(rs, r)
+		}
+	}
+	return rs, s.Err()
+}
+
+// Close closes the file
+func (l *FileAuditLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.f.Close()
+}
+
+func init() {
+	RegisterAuditLog("file", NewFileAuditLog)
+}
@@ -0,0 +1,167 @@
+/* Convergence.go: keeps track of which nodes' discovered states match their configurations, and since when they haven't
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+////////////////////////
+// convergence Object /
+//////////////////////
+
+// convergence remembers when nodes' discovered states stopped matching their configurations
+type convergence struct {
+	mutex    *sync.Mutex
+	diverged map[string]time.Time // by node ID; nodes that match aren't in it
+}
+
+func newConvergence() *convergence {
+	return &convergence{
+		mutex:    &sync.Mutex{},
+		diverged: make(map[string]time.Time),
+	}
+}
+
+// update records whether a node matches, as of now.  A node that already didn't match keeps the time it stopped.
+func (c *convergence) update(node string, diverged bool, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !diverged {
+		delete(c.diverged, node)
+		return
+	}
+	if _, ok := c.diverged[node]; !ok {
+		c.diverged[node] = now
+	}
+}
+
+// since gets when a node stopped matching, if it doesn't
+func (c *convergence) since(node string) (t time.Time, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t, ok = c.diverged[node]
+	return
+}
+
+// forget drops a node, e.g. when it's deleted
+func (c *convergence) forget(node string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.diverged, node)
+}
+
+//////////////////////////////////////
+// StateMutationEngine Convergence /
+////////////////////////////////////
+
+// deltas gets the URLs that mutate and differ between cfg and dsc, sorted
+// URLs that aren't set in cfg (i.e. are zero) don't count.
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) deltas(cfg, dsc lib.Node) (r []*pb.ConvergenceDelta) {
+	sme.graphMutex.RLock()
+	defer sme.graphMutex.RUnlock()
+	for u := range sme.mutators {
+		cv, e := cfg.GetValue(u)
+		if e != nil || cv.Interface() == reflect.Zero(cv.Type()).Interface() {
+			continue
+		}
+		dv, e := dsc.GetValue(u)
+		if e != nil {
+			r = append(r, &pb.ConvergenceDelta{Url: u, Cfg: lib.ValueToString(cv)})
+			continue
+		}
+		if cv.Interface() != dv.Interface() {
+			r = append(r, &pb.ConvergenceDelta{Url: u, Cfg: lib.ValueToString(cv), Dsc: lib.ValueToString(dv)})
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Url < r[j].Url })
+	return
+}
+
+// trackConvergence notes whether the node a state change is for still matches its configuration
+func (sme *StateMutationEngine) trackConvergence(v lib.Event) {
+	sce, ok := v.Data().(*StateChangeEvent)
+	if !ok {
+		return
+	}
+	node, _ := lib.NodeURLSplit(sce.URL)
+	if sce.Type == StateChange_DELETE {
+		sme.convergence.forget(node)
+		return
+	}
+	nid := NewNodeID(node)
+	cfg, e := sme.query.Read(nid)
+	if e != nil {
+		return
+	}
+	dsc, e := sme.query.ReadDsc(nid)
+	if e != nil {
+		return
+	}
+	sme.convergence.update(node, sme.drifted(cfg, dsc), time.Now())
+}
+
+// Convergence reports, for each node (sorted by ID), whether its discovered state matches its configuration, how it doesn't, and since when
+// LOCKS: graphMutex (R); activeMutex; path.mutex
+func (sme *StateMutationEngine) Convergence() (r []*pb.NodeConvergence, e error) {
+	cfgs, e := sme.query.ReadAll()
+	if e != nil {
+		return
+	}
+	dscs, e := sme.query.ReadAllDsc()
+	if e != nil {
+		return
+	}
+	dsc := make(map[string]lib.Node)
+	for _, n := range dscs {
+		dsc[n.ID().String()] = n
+	}
+	now := time.Now()
+	for _, cfg := range cfgs {
+		id := cfg.ID().String()
+		d, ok := dsc[id]
+		if !ok {
+			// nothing has been discovered about it
+			d = NewNodeFromMessage(&pb.Node{Id: cfg.ID().Binary()})
+		}
+		name, _ := cfg.GetValue("/Nodename")
+		nc := &pb.NodeConvergence{
+			Id:       id,
+			Nodename: name.String(),
+			Deltas:   sme.deltas(cfg, d),
+			Frozen:   cfg.Frozen(),
+		}
+		nc.Converged = len(nc.Deltas) == 0
+		// we may have missed it changing, e.g. if it diverged before we started
+		sme.convergence.update(id, !nc.Converged, now)
+		if t, ok := sme.convergence.since(id); ok {
+			nc.Diverged, _ = ptypes.TimestampProto(t)
+		}
+		sme.activeMutex.Lock()
+		m, ok := sme.active[id]
+		sme.activeMutex.Unlock()
+		if ok {
+			m.mutex.Lock()
+			nc.Mutating = !m.idle
+			m.mutex.Unlock()
+		}
+		r = append(r, nc)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Id < r[j].Id })
+	return
+}
@@ -0,0 +1,530 @@
+/* EtcdStateStore.go: a StateStore kept in etcd, so multiple kraken head nodes can share state
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////////
+// EtcdStateStore Object /
+/////////////////////////
+
+var _ StateStore = (*EtcdStateStore)(nil)
+var _ WatchableStateStore = (*EtcdStateStore)(nil)
+var _ LeaderElector = (*EtcdStateStore)(nil)
+
+// EtcdLeaseTTL is how long (in seconds) a leader stays the leader if it can't reach etcd
+var EtcdLeaseTTL int64 = 10
+
+// EtcdTimeout is how long a request to an etcd endpoint can take before we give up on it, and try the next
+var EtcdTimeout = 5 * time.Second
+
+// An EtcdStateStore keeps nodes in etcd, using the etcd v3 JSON gateway.
+// Keys are <prefix>/cfg/<node id>, values are nodes in their binary (proto) form.
+// Only configuration is kept; every head node discovers state for itself, so there's no one discoverable state to share.
+// The location is a comma separated list of endpoints; the path of the first is used as the prefix,
+// e.g. http://etcd1:2379,http://etcd2:2379/kraken
+type EtcdStateStore struct {
+	endpoints []string
+	prefix    string
+	client    *http.Client
+	mutex     sync.Mutex
+	wmutex    sync.RWMutex     // held (read) by writes until they've recorded their revision
+	cur       int              // the endpoint we're currently using
+	rev       int64            // the revision we last loaded or watched
+	own       map[int64]bool   // revisions of our own writes, so we don't watch them come back
+	known     map[string]int64 // mod revisions of the nodes we've loaded, watched or written, by ID; see resync
+	ctx       context.Context
+	cancel    context.CancelFunc // called by Close, which stops every request we're making
+}
+
+// NewEtcdStateStore creates an EtcdStateStore and checks that etcd is reachable
+func NewEtcdStateStore(location string) (StateStore, error) {
+	// requests are timed out by their contexts (see call), but a watch can't have an overall timeout
+	dialer := &net.Dialer{Timeout: EtcdTimeout}
+	s := &EtcdStateStore{
+		client: &http.Client{Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ResponseHeaderTimeout: EtcdTimeout,
+		}},
+		own:   make(map[int64]bool),
+		known: make(map[string]int64),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for i, ep := range strings.Split(location, ",") {
+		u, e := url.Parse(ep)
+		if e != nil || u.Host == "" {
+			return nil, fmt.Errorf("bad etcd endpoint: %s", ep)
+		}
+		if i == 0 {
+			s.prefix = strings.TrimRight(u.Path, "/")
+		}
+		s.endpoints = append(s.endpoints, u.Scheme+"://"+u.Host)
+	}
+	if s.prefix == "" {
+		s.prefix = "/kraken"
+	}
+	// make sure we can talk to etcd before we rely on it
+	if _, e := s.rangeKeys(s.key()); e != nil {
+		return nil, fmt.Errorf("could not reach etcd: %v", e)
+	}
+	return s, nil
+}
+
+// Load reads all of the nodes in a state; the discoverable state is always empty
+func (s *EtcdStateStore) Load(dsc bool) (r []lib.Node, e error) {
+	if dsc {
+		return
+	}
+	kvs, e := s.rangeKeys(s.key())
+	if e != nil {
+		return
+	}
+	known := make(map[string]int64)
+	for _, kv := range kvs {
+		if n := NewNodeFromBinary(kv.Value); n != nil {
+			r = append(r, n)
+			known[strings.TrimPrefix(string(kv.Key), s.key())] = kv.ModRevision
+		}
+	}
+	s.mutex.Lock()
+	s.known = known
+	s.mutex.Unlock()
+	return
+}
+
+// Save writes a node's configuration; discoverable state isn't kept
+func (s *EtcdStateStore) Save(dsc bool, n lib.Node) error {
+	if dsc {
+		return nil
+	}
+	req := map[string][]byte{
+		"key":   []byte(s.key() + n.ID().String()),
+		"value": n.Binary(),
+	}
+	return s.write("/v3/kv/put", n.ID().String(), req)
+}
+
+// Delete removes a node's configuration
+func (s *EtcdStateStore) Delete(dsc bool, nid lib.NodeID) error {
+	if dsc {
+		return nil
+	}
+	req := map[string][]byte{
+		"key": []byte(s.key() + nid.String()),
+	}
+	return s.write("/v3/kv/deleterange", nid.String(), req)
+}
+
+// Close stops any watches, campaigns and requests
+func (s *EtcdStateStore) Close() error {
+	s.cancel()
+	return nil
+}
+
+// Watch sends changes to the configuration state on c, starting after the last Load
+// Only configuration is watched; every head node discovers state for itself.
+// The watch reconnects (to the next endpoint, if there are several) until Close is called.
+func (s *EtcdStateStore) Watch(c chan<- StateStoreEvent) error {
+	go func() {
+		for {
+			e := s.watch(c)
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			if e != nil {
+				s.failover()
+			}
+		}
+	}()
+	return nil
+}
+
+// Campaign makes id the leader by creating <prefix>/leader, attached to a lease that we keep alive.
+// If the key is taken, we keep trying until its holder lets its lease run out.
+// If we can't renew our own lease (e.g. we lose etcd), we give up leadership before it would run out.
+func (s *EtcdStateStore) Campaign(id string) (<-chan bool, error) {
+	tick := time.Duration(EtcdLeaseTTL) * time.Second / 3
+	var lease int64
+	for {
+		var e error
+		if lease, e = s.takeLeader(id); e == nil && lease != 0 {
+			break
+		}
+		select {
+		case <-s.ctx.Done():
+			return nil, fmt.Errorf("state store closed")
+		case <-time.After(tick):
+		}
+	}
+	lost := make(chan bool)
+	go func() {
+		defer close(lost)
+		t := time.NewTicker(tick)
+		defer t.Stop()
+		expires := time.Now().Add(time.Duration(EtcdLeaseTTL) * time.Second)
+		for {
+			select {
+			case <-s.ctx.Done():
+				// we're closed, so this can't use our context
+				ctx, cancel := context.WithTimeout(context.Background(), EtcdTimeout)
+				s.call(ctx, "/v3/lease/revoke", map[string]int64{"ID": lease}, nil)
+				cancel()
+				return
+			case <-t.C:
+			}
+			var resp struct {
+				Result struct {
+					TTL int64 `json:"TTL,string"`
+				} `json:"result"`
+			}
+			sent := time.Now()
+			// a keepalive that's still waiting at the next tick is no use
+			ctx, cancel := context.WithTimeout(s.ctx, tick)
+			e := s.call(ctx, "/v3/lease/keepalive", map[string]int64{"ID": lease}, &resp)
+			cancel()
+			if e == nil {
+				if resp.Result.TTL <= 0 {
+					return // the lease is gone
+				}
+				expires = sent.Add(time.Duration(resp.Result.TTL) * time.Second)
+			}
+			if time.Until(expires) < tick {
+				return
+			}
+		}
+	}()
+	return lost, nil
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// takeLeader tries to create the leader key with a new lease
+// it gives the lease if we got the key, and 0 if someone else has it
+func (s *EtcdStateStore) takeLeader(id string) (int64, error) {
+	var lease struct {
+		ID int64 `json:"ID,string"`
+	}
+	if e := s.call(s.ctx, "/v3/lease/grant", map[string]int64{"TTL": EtcdLeaseTTL}, &lease); e != nil {
+		return 0, e
+	}
+	key := []byte(s.prefix + "/leader")
+	req := map[string]interface{}{
+		"compare": []map[string]interface{}{
+			{"key": key, "target": "CREATE", "result": "EQUAL", "create_revision": 0},
+		},
+		"success": []map[string]interface{}{
+			{"request_put": map[string]interface{}{"key": key, "value": []byte(id), "lease": lease.ID}},
+		},
+	}
+	var resp struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	if e := s.call(s.ctx, "/v3/kv/txn", req, &resp); e != nil || !resp.Succeeded {
+		// we don't need the lease
+		s.call(s.ctx, "/v3/lease/revoke", map[string]int64{"ID": lease.ID}, nil)
+		return 0, e
+	}
+	return lease.ID, nil
+}
+
+type etcdKV struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+type etcdHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+// key is the prefix of the configuration keys
+func (s *EtcdStateStore) key() string {
+	return s.prefix + "/cfg/"
+}
+
+// prefixEnd gives the range_end that selects every key starting with p
+func prefixEnd(p string) []byte {
+	end := []byte(p)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0}
+}
+
+func (s *EtcdStateStore) endpoint() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.endpoints[s.cur]
+}
+
+func (s *EtcdStateStore) failover() {
+	s.mutex.Lock()
+	s.cur = (s.cur + 1) % len(s.endpoints)
+	s.mutex.Unlock()
+}
+
+// call makes a JSON gateway request, failing over once through every endpoint
+// Each endpoint gets EtcdTimeout to answer, so a partitioned etcd can't hold us up for long.
+func (s *EtcdStateStore) call(ctx context.Context, path string, req, resp interface{}) (e error) {
+	b, _ := json.Marshal(req)
+	for i := 0; i < len(s.endpoints); i++ {
+		var unreachable bool
+		if unreachable, e = s.post(ctx, path, b, resp); !unreachable || ctx.Err() != nil {
+			return
+		}
+		s.failover()
+	}
+	return
+}
+
+// post makes one request to the current endpoint; unreachable is set if it didn't answer in time
+func (s *EtcdStateStore) post(ctx context.Context, path string, b []byte, resp interface{}) (unreachable bool, e error) {
+	ctx, cancel := context.WithTimeout(ctx, EtcdTimeout)
+	defer cancel()
+	req, e := http.NewRequest(http.MethodPost, s.endpoint()+path, bytes.NewReader(b))
+	if e != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	r, e := s.client.Do(req.WithContext(ctx))
+	if e != nil {
+		return true, e
+	}
+	defer r.Body.Close()
+	if r.StatusCode != 200 {
+		var ee struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&ee)
+		return false, fmt.Errorf("etcd %s: %s %s", path, r.Status, ee.Message)
+	}
+	if resp != nil {
+		if e = json.NewDecoder(r.Body).Decode(resp); e != nil {
+			return ctx.Err() != nil, e
+		}
+	}
+	return
+}
+
+// write makes a put or delete of the node id
+// the write will come back on our watch, so we remember its revision as our own
+func (s *EtcdStateStore) write(path, id string, req interface{}) error {
+	s.wmutex.RLock()
+	defer s.wmutex.RUnlock()
+	var resp struct {
+		Header  etcdHeader `json:"header"`
+		Deleted int64      `json:"deleted,string"`
+	}
+	if e := s.call(s.ctx, path, req, &resp); e != nil {
+		return e
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// deleting nothing doesn't make a new revision
+	if path != "/v3/kv/deleterange" || resp.Deleted > 0 {
+		s.own[resp.Header.Revision] = true
+	}
+	if path == "/v3/kv/deleterange" {
+		delete(s.known, id)
+	} else {
+		s.known[id] = resp.Header.Revision
+	}
+	return nil
+}
+
+// rangeKeys gets every key with a prefix, and remembers the revision we saw them at
+func (s *EtcdStateStore) rangeKeys(p string) ([]etcdKV, error) {
+	req := map[string][]byte{
+		"key":       []byte(p),
+		"range_end": prefixEnd(p),
+	}
+	var resp struct {
+		Header etcdHeader `json:"header"`
+		KVs    []etcdKV   `json:"kvs"`
+	}
+	if e := s.call(s.ctx, "/v3/kv/range", req, &resp); e != nil {
+		return nil, e
+	}
+	s.mutex.Lock()
+	if resp.Header.Revision > s.rev {
+		s.rev = resp.Header.Revision
+	}
+	s.mutex.Unlock()
+	return resp.KVs, nil
+}
+
+// watch runs one watch stream until it fails
+func (s *EtcdStateStore) watch(c chan<- StateStoreEvent) error {
+	p := s.key()
+	s.mutex.Lock()
+	start := s.rev + 1
+	s.mutex.Unlock()
+	req := map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key":            []byte(p),
+			"range_end":      prefixEnd(p),
+			"start_revision": start,
+		},
+	}
+	b, _ := json.Marshal(req)
+	// the watch lasts until it fails, or we're closed
+	hr, e := http.NewRequest(http.MethodPost, s.endpoint()+"/v3/watch", bytes.NewReader(b))
+	if e != nil {
+		return e
+	}
+	hr.Header.Set("Content-Type", "application/json")
+	r, e := s.client.Do(hr.WithContext(s.ctx))
+	if e != nil {
+		return e
+	}
+	defer r.Body.Close()
+	if r.StatusCode != 200 {
+		return fmt.Errorf("etcd /v3/watch: %s", r.Status)
+	}
+	dec := json.NewDecoder(r.Body)
+	for {
+		var resp struct {
+			Result struct {
+				Canceled        bool   `json:"canceled"`
+				CancelReason    string `json:"cancel_reason"`
+				CompactRevision int64  `json:"compact_revision,string"`
+				Events          []struct {
+					Type string `json:"type"` // PUT is the default, so it's usually left out
+					KV   etcdKV `json:"kv"`
+				} `json:"events"`
+			} `json:"result"`
+		}
+		if e = dec.Decode(&resp); e != nil {
+			if e == io.EOF {
+				e = fmt.Errorf("watch closed")
+			}
+			return e
+		}
+		if resp.Result.CompactRevision != 0 {
+			// the revisions we'd watch from are gone, so we can't tell what happened in them
+			return s.resync(c)
+		}
+		if resp.Result.Canceled {
+			return fmt.Errorf("watch canceled: %s", resp.Result.CancelReason)
+		}
+		for _, ev := range resp.Result.Events {
+			// a write of ours may be back before it's recorded its revision
+			s.wmutex.Lock()
+			s.wmutex.Unlock()
+			id := strings.TrimPrefix(string(ev.KV.Key), p)
+			s.mutex.Lock()
+			if ev.KV.ModRevision > s.rev {
+				s.rev = ev.KV.ModRevision
+			}
+			own := s.own[ev.KV.ModRevision]
+			delete(s.own, ev.KV.ModRevision)
+			if ev.Type == "DELETE" {
+				delete(s.known, id)
+			} else {
+				s.known[id] = ev.KV.ModRevision
+			}
+			s.mutex.Unlock()
+			if own {
+				continue
+			}
+			se := StateStoreEvent{
+				ID: NewNodeID(id),
+			}
+			if ev.Type == "DELETE" {
+				se.Delete = true
+			} else {
+				n := NewNodeFromBinary(ev.KV.Value)
+				if n == nil {
+					continue
+				}
+				se.Node = n
+			}
+			select {
+			case c <- se:
+			case <-s.ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// resync catches up after the revisions we'd watch from have been compacted away:
+// the state is read again, and whatever differs from what we last knew of it is sent on c.
+// The next watch starts from the revision it was read at.
+func (s *EtcdStateStore) resync(c chan<- StateStoreEvent) error {
+	// our writes are held back until we're done, so we can't mistake them for anyone else's
+	s.wmutex.Lock()
+	kvs, e := s.rangeKeys(s.key())
+	if e != nil {
+		s.wmutex.Unlock()
+		return e
+	}
+	var ses []StateStoreEvent
+	s.mutex.Lock()
+	seen := make(map[string]bool)
+	for _, kv := range kvs {
+		id := strings.TrimPrefix(string(kv.Key), s.key())
+		seen[id] = true
+		if s.known[id] == kv.ModRevision {
+			continue
+		}
+		if n := NewNodeFromBinary(kv.Value); n != nil {
+			s.known[id] = kv.ModRevision
+			ses = append(ses, StateStoreEvent{ID: NewNodeID(id), Node: n})
+		}
+	}
+	for id := range s.known {
+		if !seen[id] {
+			delete(s.known, id)
+			ses = append(ses, StateStoreEvent{ID: NewNodeID(id), Delete: true})
+		}
+	}
+	// our writes before now won't come back
+	for rev := range s.own {
+		if rev <= s.rev {
+			delete(s.own, rev)
+		}
+	}
+	s.mutex.Unlock()
+	s.wmutex.Unlock()
+	for _, se := range ses {
+		select {
+		case c <- se:
+		case <-s.ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+func init() {
+	RegisterStateStore("etcd", NewEtcdStateStore)
+}
@@ -11,6 +11,7 @@ package core
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/hpc/kraken/lib"
 )
@@ -24,22 +25,26 @@ var _ lib.EventEmitter = (*EventEmitter)(nil)
 // EventEmitter is really just a helper object, and not a core type.
 // It simplifies making an engine that Emits events to event dispatch.
 type EventEmitter struct {
-	subs map[string]chan<- []lib.Event
-	t    lib.EventType
+	subs  map[string]chan<- []lib.Event
+	mutex *sync.RWMutex // engines can emit (e.g. from their constructors) before they're subscribed
+	t     lib.EventType
 }
 
 // NewEventEmitter creates a new initialized EventEmitter.
 // It must be Subscribed to do anything interesting.
 func NewEventEmitter(t lib.EventType) *EventEmitter {
 	ne := &EventEmitter{
-		subs: make(map[string]chan<- []lib.Event),
-		t:    t,
+		subs:  make(map[string]chan<- []lib.Event),
+		mutex: &sync.RWMutex{},
+		t:     t,
 	}
 	return ne
 }
 
 // Subscribe links the Emitter to an Event chan, allowing it to actually send events.
 func (m *EventEmitter) Subscribe(id string, c chan<- []lib.Event) (e error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if _, ok := m.subs[id]; ok {
 		e = fmt.Errorf("subscription id already in use: %s", id)
 		return
@@ -50,6 +55,8 @@ func (m *EventEmitter) Subscribe(id string, c chan<- []lib.Event) (e error) {
 
 // Unsubscribe removes an event chan from the subscriber list
 func (m *EventEmitter) Unsubscribe(id string) (e error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if _, ok := m.subs[id]; !ok {
 		e = fmt.Errorf("cannot unsubscribe, no such subscription: %s", id)
 		return
@@ -77,7 +84,14 @@ func (m *EventEmitter) EmitOne(v lib.Event) {
 //////////////////////
 
 func (m *EventEmitter) emit(v []lib.Event) {
+	// we don't hold the lock while we send; a slow subscriber shouldn't hold up subscribing
+	m.mutex.RLock()
+	subs := make([]chan<- []lib.Event, 0, len(m.subs))
 	for _, s := range m.subs {
+		subs = append(subs, s)
+	}
+	m.mutex.RUnlock()
+	for _, s := range subs {
 		s <- v // should we introduce timeouts?
 	}
 }
@@ -0,0 +1,231 @@
+/* EventLog.go: the EventLog keeps an append-only record of events for later analysis
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// An EventLog is an append-only record of events
+type EventLog interface {
+	Append(r *pb.EventRecord) error
+	// Query gets the records for a node (or every node, if node is empty) between from and to.
+	// Zero times leave that end of the range open.
+	Query(node string, from, to time.Time) ([]*pb.EventRecord, error)
+	Close() error
+}
+
+// An EventLogOpener opens an EventLog at a backend specific location (e.g. a file)
+type EventLogOpener func(location string) (EventLog, error)
+
+// EventLogs maps backend names to their openers
+var EventLogs = map[string]EventLogOpener{}
+
+// RegisterEventLog makes an EventLog backend available by name
+// It's probably a good idea for this to be done in init()
+func RegisterEventLog(name string, o EventLogOpener) {
+	if _, ok := EventLogs[name]; !ok {
+		EventLogs[name] = o
+	}
+}
+
+// OpenEventLog opens an EventLog with a registered backend
+func OpenEventLog(backend, location string) (EventLog, error) {
+	o, ok := EventLogs[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown event log backend: %s", backend)
+	}
+	return o(location)
+}
+
+// NewEventRecord makes an EventRecord describing an event
+func NewEventRecord(ev lib.Event) *pb.EventRecord {
+	node, _ := lib.NodeURLSplit(ev.URL())
+	r := &pb.EventRecord{
+		Time: ptypes.TimestampNow(),
+		Type: lib.EventTypeString[ev.Type()],
+		Node: node,
+		Url:  ev.URL(),
+		Data: fmt.Sprintf("%v", ev.Data()),
+	}
+	switch d := ev.Data().(type) {
+	case *MutationEvent:
+		r.Module = d.Mutation[0]
+	case *DiscoveryEvent:
+		r.Module = d.Module
+	}
+	return r
+}
+
+////////////////////////////
+// FileEventLog Object /
+//////////////////////////
+
+var _ EventLog = (*FileEventLog)(nil)
+
+// A FileEventLog appends records to a file, one JSON object per line.
+// Queries read the whole file, so it should be rotated (e.g. by logrotate with copytruncate) if it gets large.
+type FileEventLog struct {
+	path  string
+	f     *os.File
+	mutex sync.Mutex
+}
+
+// NewFileEventLog opens (creating if needed) a FileEventLog at path
+func NewFileEventLog(path string) (EventLog, error) {
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if e != nil {
+		return nil, fmt.Errorf("could not open event log: %v", e)
+	}
+	return &FileEventLog{path: path, f: f}, nil
+}
+
+// Append writes a record
+func (l *FileEventLog) Append(r *pb.EventRecord) error {
+	m := jsonpb.Marshaler{}
+	s, e := m.MarshalToString(r)
+	if e != nil {
+		return e
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, e = l.f.WriteString(s + "\n")
+	return e
+}
+
+// Query reads through the log for matching records
+// lines that can't be read as a record are skipped
+func (l *FileEventLog) Query(node string, from, to time.Time) (rs []*pb.EventRecord, e error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	f, e := os.Open(l.path)
+	if e != nil {
+		return
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024) // mutation events can be long
+	for s.Scan() {
+		r := &pb.EventRecord{}
+		if jsonpb.Unmarshal(strings.NewReader(s.Text()), r) != nil {
+			continue
+		}
+		if eventRecordMatch(r, node, from, to) {
+			rs = append(rs, r)
+		}
+	}
+	return rs, s.Err()
+}
+
+// Close closes the file
+func (l *FileEventLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.f.Close()
+}
+
+// eventRecordMatch is a helper for EventLog implementations
+func eventRecordMatch(r *pb.EventRecord, node string, from, to time.Time) bool {
+	if node != "" && !NewNodeID(r.Node).Equal(NewNodeID(node)) {
+		return false
+	}
+	t, e := ptypes.Timestamp(r.Time)
+	if e != nil {
+		return false
+	}
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && t.After(to) {
+		return false
+	}
+	return true
+}
+
+////////////////////////////
+// EventRecorder Object /
+//////////////////////////
+
+// EventRecorder listens for mutation, discovery and state change events and records them in an EventLog
+type EventRecorder struct {
+	elog  EventLog
+	echan chan lib.Event
+	schan chan<- lib.EventListener // subscription channel
+	log   lib.Logger
+}
+
+// NewEventRecorder creates an initialized EventRecorder that records to ctx.EventLog.Log
+func NewEventRecorder(ctx Context) *EventRecorder {
+	r := &EventRecorder{
+		elog:  ctx.EventLog.Log,
+		echan: make(chan lib.Event, 1024),
+		schan: ctx.SubChan,
+		log:   &ctx.Logger,
+	}
+	r.log.SetModule("EventRecorder")
+	return r
+}
+
+// Run is a goroutine that subscribes to events and records them
+func (r *EventRecorder) Run() {
+	r.Log(INFO, "starting EventRecorder")
+	for _, t := range []lib.EventType{lib.Event_STATE_CHANGE, lib.Event_STATE_MUTATION, lib.Event_DISCOVERY} {
+		r.schan <- NewEventListener(
+			"EventRecorder:"+lib.EventTypeString[t],
+			t,
+			func(v lib.Event) bool { return true },
+			func(v lib.Event) error { return ChanSender(v, r.echan) },
+		)
+	}
+	for v := range r.echan {
+		if e := r.elog.Append(NewEventRecord(v)); e != nil {
+			r.Logf(ERROR, "failed to record event: %v", e)
+		}
+	}
+}
+
+////////////////////////////
+// Passthrough Interfaces /
+//////////////////////////
+
+/*
+ * Consume Logger
+ */
+var _ lib.Logger = (*EventRecorder)(nil)
+
+func (r *EventRecorder) Log(level lib.LoggerLevel, m string) { r.log.Log(level, m) }
+func (r *EventRecorder) Logf(level lib.LoggerLevel, fmt string, v ...interface{}) {
+	r.log.Logf(level, fmt, v...)
+}
+func (r *EventRecorder) SetModule(name string)                { r.log.SetModule(name) }
+func (r *EventRecorder) GetModule() string                    { return r.log.GetModule() }
+func (r *EventRecorder) SetLoggerLevel(level lib.LoggerLevel) { r.log.SetLoggerLevel(level) }
+func (r *EventRecorder) GetLoggerLevel() lib.LoggerLevel      { return r.log.GetLoggerLevel() }
+func (r *EventRecorder) IsEnabledFor(level lib.LoggerLevel) bool {
+	return r.log.IsEnabledFor(level)
+}
+
+func init() {
+	RegisterEventLog("file", NewFileEventLog)
+}
@@ -0,0 +1,263 @@
+/* EventQueue.go: EventQueues hold mutations for modules until they acknowledge them, so they survive module restarts
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/jsonpb"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// An EventQueue holds the mutations for a module until the module acknowledges them.
+// Mutations are numbered (MutationControl.Seq) in the order they are pushed, starting at 1.
+type EventQueue interface {
+	// Push adds a mutation to the queue, and sets its Seq
+	Push(mc *pb.MutationControl) error
+	// Pending gets the mutations that haven't been acknowledged, in order
+	Pending() ([]*pb.MutationControl, error)
+	// Ack acknowledges seq, and everything before it
+	Ack(seq uint64) error
+	Close() error
+}
+
+// An EventQueueOpener opens the EventQueue for a module (name) at a backend specific location (e.g. a directory)
+type EventQueueOpener func(location, name string) (EventQueue, error)
+
+// EventQueues maps backend names to their openers
+var EventQueues = map[string]EventQueueOpener{}
+
+// RegisterEventQueue makes an EventQueue backend available by name
+// It's probably a good idea for this to be done in init()
+func RegisterEventQueue(name string, o EventQueueOpener) {
+	if _, ok := EventQueues[name]; !ok {
+		EventQueues[name] = o
+	}
+}
+
+// OpenEventQueue opens the EventQueue for a module with a registered backend
+func OpenEventQueue(backend, location, name string) (EventQueue, error) {
+	o, ok := EventQueues[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown event queue backend: %s", backend)
+	}
+	return o(location, name)
+}
+
+////////////////////////////
+// MemoryEventQueue Object /
+//////////////////////////
+
+var _ EventQueue = (*MemoryEventQueue)(nil)
+
+// A MemoryEventQueue keeps mutations in memory.  They survive module restarts, but not kraken restarts.
+type MemoryEventQueue struct {
+	seq     uint64
+	pending []*pb.MutationControl
+	mutex   sync.Mutex
+}
+
+// NewMemoryEventQueue creates an empty MemoryEventQueue; location and name are ignored
+func NewMemoryEventQueue(location, name string) (EventQueue, error) {
+	return &MemoryEventQueue{}, nil
+}
+
+// Push adds a mutation
+func (q *MemoryEventQueue) Push(mc *pb.MutationControl) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.push(mc)
+	return nil
+}
+
+// Pending gets the mutations that haven't been acknowledged
+func (q *MemoryEventQueue) Pending() ([]*pb.MutationControl, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return append([]*pb.MutationControl{}, q.pending...), nil
+}
+
+// Ack drops mutations up to seq
+func (q *MemoryEventQueue) Ack(seq uint64) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.ack(seq)
+	return nil
+}
+
+// Close does nothing
+func (q *MemoryEventQueue) Close() error { return nil }
+
+// push assumes q.mutex is locked
+func (q *MemoryEventQueue) push(mc *pb.MutationControl) {
+	q.seq++
+	mc.Seq = q.seq
+	q.pending = append(q.pending, mc)
+}
+
+// ack assumes q.mutex is locked
+func (q *MemoryEventQueue) ack(seq uint64) {
+	i := 0
+	for i < len(q.pending) && q.pending[i].Seq <= seq {
+		i++
+	}
+	q.pending = q.pending[i:]
+	if seq > q.seq {
+		q.seq = seq
+	}
+}
+
+////////////////////////////
+// FileEventQueue Object /
+//////////////////////////
+
+var _ EventQueue = (*FileEventQueue)(nil)
+
+// A FileEventQueue is a MemoryEventQueue that is journaled to a file (<location>/<name>.queue), so it survives restarts.
+// Each line of the journal is a pushed mutation ("+" and its JSON), or an ack ("-" and a seq).
+// The journal is emptied whenever everything has been acknowledged.
+type FileEventQueue struct {
+	MemoryEventQueue
+	f *os.File
+}
+
+// NewFileEventQueue opens (creating if needed) the FileEventQueue for name in the directory location
+func NewFileEventQueue(location, name string) (EventQueue, error) {
+	path := filepath.Join(location, name+".queue")
+	q := &FileEventQueue{}
+	if e := q.replay(path); e != nil {
+		return nil, fmt.Errorf("could not read event queue: %v", e)
+	}
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if e != nil {
+		return nil, fmt.Errorf("could not open event queue: %v", e)
+	}
+	q.f = f
+	return q, nil
+}
+
+// Push journals and adds a mutation; it's only added once it's journaled
+func (q *FileEventQueue) Push(mc *pb.MutationControl) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	mc.Seq = q.seq + 1
+	m := jsonpb.Marshaler{}
+	s, e := m.MarshalToString(mc)
+	if e != nil {
+		return e
+	}
+	if _, e = q.f.WriteString("+" + s + "\n"); e != nil {
+		return e
+	}
+	q.push(mc)
+	return nil
+}
+
+// Ack journals an ack, and drops mutations up to seq
+func (q *FileEventQueue) Ack(seq uint64) (e error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.ack(seq)
+	if len(q.pending) == 0 {
+		// start over, but remember where we're up to
+		if e = q.f.Truncate(0); e != nil {
+			return
+		}
+		seq = q.seq
+	}
+	_, e = q.f.WriteString("-" + strconv.FormatUint(seq, 10) + "\n")
+	return
+}
+
+// Close closes the journal
+func (q *FileEventQueue) Close() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.f.Close()
+}
+
+// replay rebuilds the queue from its journal, if there is one
+// If we crashed while writing the last line, it's dropped; the mutation it had wasn't pushed, or its ack wasn't made.
+func (q *FileEventQueue) replay(path string) error {
+	f, e := os.Open(path)
+	if os.IsNotExist(e) {
+		return nil
+	} else if e != nil {
+		return e
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024) // mutations carry whole nodes
+	// off is where the line we're reading starts
+	var off int64
+	for s.Scan() {
+		l := s.Text()
+		if e := q.replayLine(l); e != nil {
+			if s.Scan() {
+				// it's not the last line, so it's not one we were writing when we crashed
+				return e
+			}
+			// the next line is written where this one started
+			return os.Truncate(path, off)
+		}
+		off += int64(len(l)) + 1
+	}
+	if e := s.Err(); e != nil {
+		return e
+	}
+	if fi, e := f.Stat(); e == nil && off > fi.Size() {
+		// the last line was written whole, but not its newline
+		w, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		if e != nil {
+			return e
+		}
+		defer w.Close()
+		_, e = w.WriteString("\n")
+		return e
+	}
+	return nil
+}
+
+// replayLine replays one line of the journal
+func (q *FileEventQueue) replayLine(l string) error {
+	switch {
+	case strings.HasPrefix(l, "+"):
+		mc := &pb.MutationControl{}
+		if e := jsonpb.Unmarshal(strings.NewReader(l[1:]), mc); e != nil {
+			return e
+		}
+		q.pending = append(q.pending, mc)
+		if mc.Seq > q.seq {
+			q.seq = mc.Seq
+		}
+	case strings.HasPrefix(l, "-"):
+		seq, e := strconv.ParseUint(l[1:], 10, 64)
+		if e != nil {
+			return e
+		}
+		q.ack(seq)
+	}
+	return nil
+}
+
+func init() {
+	RegisterEventQueue("memory", NewMemoryEventQueue)
+	RegisterEventQueue("file", NewFileEventQueue)
+}
@@ -0,0 +1,103 @@
+/* Hostlist.go: expands hostlist expressions, e.g. node[001-100,200]
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxHostlist is the most names a hostlist can expand to
+const MaxHostlist = 1 << 20
+
+// ExpandHostlist expands a hostlist expression into the names it describes, in order.
+// A hostlist is a comma separated list of names, where a name can have bracketed ranges in it, e.g.
+// node[001-003,7],login1 is node001, node002, node003, node7 and login1.  Numbers keep the width of the
+// start of their range if it's zero padded.  Names can have more than one range, e.g. rack[1-2]n[1-4].
+func ExpandHostlist(hl string) (r []string, e error) {
+	for _, item := range splitHostlist(hl) {
+		if item == "" {
+			continue
+		}
+		names, e := expandHostlistItem(item, MaxHostlist-len(r))
+		if e != nil {
+			return nil, e
+		}
+		r = append(r, names...)
+	}
+	return
+}
+
+// splitHostlist splits on commas that aren't in brackets
+func splitHostlist(hl string) (r []string) {
+	depth, start := 0, 0
+	for i, c := range hl {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				r = append(r, strings.TrimSpace(hl[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(r, strings.TrimSpace(hl[start:]))
+}
+
+// expandHostlistItem expands one name, with up to max results
+func expandHostlistItem(item string, max int) (r []string, e error) {
+	open := strings.Index(item, "[")
+	if open < 0 {
+		if strings.Contains(item, "]") {
+			return nil, fmt.Errorf("unbalanced brackets in hostlist: %s", item)
+		}
+		return []string{item}, nil
+	}
+	cls := strings.Index(item, "]")
+	if cls < open {
+		return nil, fmt.Errorf("unbalanced brackets in hostlist: %s", item)
+	}
+	prefix, ranges := item[:open], item[open+1:cls]
+	// the rest of the name may have more ranges
+	rest, e := expandHostlistItem(item[cls+1:], max)
+	if e != nil {
+		return nil, e
+	}
+	for _, rng := range strings.Split(ranges, ",") {
+		sp := strings.SplitN(strings.TrimSpace(rng), "-", 2)
+		lo, e := strconv.ParseUint(sp[0], 10, 32)
+		if e != nil {
+			return nil, fmt.Errorf("bad range in hostlist: %s", rng)
+		}
+		hi := lo
+		if len(sp) == 2 {
+			if hi, e = strconv.ParseUint(sp[1], 10, 32); e != nil || hi < lo {
+				return nil, fmt.Errorf("bad range in hostlist: %s", rng)
+			}
+		}
+		width := 0
+		if len(sp[0]) > 1 && sp[0][0] == '0' {
+			width = len(sp[0])
+		}
+		if uint64(len(r))+(hi-lo+1)*uint64(len(rest)) > uint64(max) {
+			return nil, fmt.Errorf("hostlist expands to more than %d names", MaxHostlist)
+		}
+		for i := lo; i <= hi; i++ {
+			for _, s := range rest {
+				r = append(r, fmt.Sprintf("%s%0*d%s", prefix, width, i, s))
+			}
+		}
+	}
+	return
+}
@@ -28,17 +28,23 @@ import (
 // Context contains information about the current running context
 // such as who we are, and to whom we belong.
 type Context struct {
-	Services *ServiceManager
-	Logger   ServiceLogger
-	Query    QueryEngine
-	SubChan  chan<- lib.EventListener
-	Self     lib.NodeID
-	Parents  []string
-	SSE      ContextSSE
-	SME      ContextSME
-	RPC      ContextRPC
-	sdqChan  chan lib.Query
-	smqChan  chan lib.Query
+	Services   *ServiceManager
+	StopTime   time.Duration // how long services get to stop gracefully before they're killed; 0 for DefaultStopTimeout
+	Logger     ServiceLogger
+	Query      QueryEngine
+	SubChan    chan<- lib.EventListener
+	Self       lib.NodeID
+	Parents    []string
+	SSE        ContextSSE
+	SDE        ContextSDE
+	SME        ContextSME
+	RPC        ContextRPC
+	Store      ContextStore
+	EventLog   ContextEventLog
+	AuditLog   ContextAuditLog
+	EventQueue ContextEventQueue
+	sdqChan    chan lib.Query
+	smqChan    chan lib.Query
 }
 
 type ContextSSE struct {
@@ -50,8 +56,22 @@ type ContextSSE struct {
 	DeadTime  time.Duration
 }
 
+type ContextSDE struct {
+	// StaleTTL is how long discovered values of URLs (e.g. /PhysState) are good for.
+	// If a URL isn't discovered again in time, it reverts to unknown (its zero value), which gets it rediscovered.
+	StaleTTL map[string]time.Duration
+}
+
 type ContextSME struct {
 	RootSpec lib.StateSpec
+	Rollback bool // if a mutation chain fails part way, mutate back to where it started
+	// Throttles caps how many mutations we fire can be executing at once.
+	// Keys are a module name (all of its mutations) or module:mutation; the most specific one applies.
+	Throttles map[string]int
+	// Reconcile is how often to look for nodes that have drifted from their configuration
+	// (e.g. because a discovery was missed) and start them mutating again; 0 disables it.
+	Reconcile time.Duration
+	Archive   string // a directory where nodes are archived (as snapshots) before decommissioned nodes are removed
 }
 
 type ContextRPC struct {
@@ -63,6 +83,30 @@ type ContextRPC struct {
 	UNIXListener net.Listener
 }
 
+type ContextStore struct {
+	Backend  string     // a registered StateStore backend, e.g. "file"; empty disables persistence
+	Location string     // backend specific, e.g. a directory for "file"
+	Store    StateStore // opened by Bootstrap
+	HA       bool       // we're one of several head nodes sharing the store and our ID; only the elected leader mutates (see Lead)
+}
+
+type ContextEventLog struct {
+	Backend  string   // a registered EventLog backend, e.g. "file"; empty disables the event log
+	Location string   // backend specific, e.g. a file for "file"
+	Log      EventLog // opened by Bootstrap
+}
+
+type ContextAuditLog struct {
+	Backend  string   // a registered AuditLog backend, e.g. "file"; empty disables the audit log
+	Location string   // backend specific, e.g. a file for "file"
+	Log      AuditLog // opened by Bootstrap
+}
+
+type ContextEventQueue struct {
+	Backend  string // a registered EventQueue backend, e.g. "file"; empty sends mutations to modules without queuing them
+	Location string // backend specific, e.g. a directory for "file"
+}
+
 ///////////////////
 // Kraken Object /
 /////////////////
@@ -77,6 +121,8 @@ type Kraken struct {
 	Sse *StateSyncEngine
 	Sme *StateMutationEngine
 	Api *APIServer
+	Elr *EventRecorder // nil unless we have an event log
+	Sch *Scheduler
 
 	// Un-exported
 	em  *EventEmitter
@@ -174,18 +220,82 @@ func (k *Kraken) Bootstrap() {
 	k.Logf(INFO, "RPC is listening on %s:%s:%d", k.Ctx.RPC.Network, k.Ctx.RPC.Addr, k.Ctx.RPC.Port)
 	k.Logf(INFO, "RPC is listening on socket %s", k.Ctx.RPC.Path)
 
+	// open the state store, if we're persisting state
+	if k.Ctx.Store.Backend != "" {
+		s, e := OpenStateStore(k.Ctx.Store.Backend, k.Ctx.Store.Location)
+		if e != nil {
+			k.Logf(FATAL, "%v", e)
+			os.Exit(1)
+			return
+		}
+		if _, ok := s.(LeaderElector); k.Ctx.Store.HA && !ok {
+			k.Logf(FATAL, "the %s state store doesn't support leader election, so it can't be used for HA", k.Ctx.Store.Backend)
+			os.Exit(1)
+			return
+		}
+		k.Ctx.Store.Store = s
+		k.Logf(INFO, "persisting state with the %s state store at %s", k.Ctx.Store.Backend, k.Ctx.Store.Location)
+	}
+
+	if k.Ctx.Store.HA && k.Ctx.Store.Store == nil {
+		k.Log(FATAL, "HA requires a shared state store")
+		os.Exit(1)
+		return
+	}
+
+	// open the event log, if we're keeping one
+	if k.Ctx.EventLog.Backend != "" {
+		l, e := OpenEventLog(k.Ctx.EventLog.Backend, k.Ctx.EventLog.Location)
+		if e != nil {
+			k.Logf(FATAL, "%v", e)
+			os.Exit(1)
+			return
+		}
+		k.Ctx.EventLog.Log = l
+		k.Logf(INFO, "recording events with the %s event log at %s", k.Ctx.EventLog.Backend, k.Ctx.EventLog.Location)
+	}
+
+	// open the audit log, if we're keeping one
+	if k.Ctx.AuditLog.Backend != "" {
+		l, e := OpenAuditLog(k.Ctx.AuditLog.Backend, k.Ctx.AuditLog.Location)
+		if e != nil {
+			k.Logf(FATAL, "%v", e)
+			os.Exit(1)
+			return
+		}
+		k.Ctx.AuditLog.Log = l
+		k.Logf(INFO, "recording API changes with the %s audit log at %s", k.Ctx.AuditLog.Backend, k.Ctx.AuditLog.Location)
+	}
+
+	// event queues are opened per module by the API, but we can check the backend now
+	if _, ok := EventQueues[k.Ctx.EventQueue.Backend]; k.Ctx.EventQueue.Backend != "" && !ok {
+		k.Logf(FATAL, "unknown event queue backend: %s", k.Ctx.EventQueue.Backend)
+		os.Exit(1)
+		return
+	}
+
 	k.Ctx.sdqChan = make(chan lib.Query)
 	k.Ctx.smqChan = make(chan lib.Query)
 
 	k.Ede = NewEventDispatchEngine(k.Ctx)
 	k.Ctx.SubChan = k.Ede.SubscriptionChan()
 	k.Sde = NewStateDifferenceEngine(k.Ctx, k.Ctx.sdqChan)
-	k.Ctx.Services = NewServiceManager("unix:" + k.Ctx.RPC.Path)
+	k.Ctx.Services = NewServiceManager("unix:"+k.Ctx.RPC.Path, k.Ctx.StopTime)
+	slogger := k.Ctx.Logger
+	slogger.SetModule("ServiceManager")
+	k.Ctx.Services.SetLogger(&slogger)
 	k.Ctx.Query = *NewQueryEngine(k.Ctx.sdqChan, k.Ctx.smqChan)
 
 	k.Sse = NewStateSyncEngine(k.Ctx)
 	k.Sme = NewStateMutationEngine(k.Ctx, k.Ctx.smqChan)
 	k.Api = NewAPIServer(k.Ctx)
+	if k.Ctx.EventLog.Log != nil {
+		k.Elr = NewEventRecorder(k.Ctx)
+	}
+	k.Sch = NewScheduler(k.Ctx)
+	// these only act while we lead
+	k.Sde.SetFrozen(k.Sme.Frozen)
+	k.Sch.SetFrozen(k.Sme.Frozen)
 
 	k.Sde.Subscribe("SDE", k.Ede.EventChan())
 	k.Sme.Subscribe("SME", k.Ede.EventChan())
@@ -202,6 +312,10 @@ func (k *Kraken) Run() {
 	go k.Sse.Run()
 	go k.Sme.Run()
 	go k.Api.Run()
+	if k.Elr != nil {
+		go k.Elr.Run()
+	}
+	go k.Sch.Run()
 
 	if len(k.Ctx.Parents) < 1 {
 		// set our own runstate and phystate, should we discover these instead?
@@ -216,6 +330,35 @@ func (k *Kraken) Run() {
 	}
 }
 
+// Lead starts the SME mutating, unless we're in HA mode.
+// In HA mode, we campaign to lead the head nodes sharing our state store, and only mutate while we're the leader.
+// Standby head nodes keep mirroring the configuration state through the store, so they're ready to take over.
+// HA head nodes are run with the same ID: they're one node, the parent of the nodes they manage,
+// so whichever of them leads can mutate its children. Each keeps its own address (see ContextStore.HA).
+func (k *Kraken) Lead() {
+	if !k.Ctx.Store.HA {
+		k.Sme.Thaw()
+		return
+	}
+	le := k.Ctx.Store.Store.(LeaderElector) // checked by Bootstrap
+	go func() {
+		for {
+			k.Log(INFO, "standing by to lead")
+			// every head node has our ID, so we're told apart by our address
+			lost, e := le.Campaign(k.Ctx.Self.String() + "@" + k.Ctx.SSE.Addr)
+			if e != nil {
+				k.Logf(ERROR, "leader election stopped: %v", e)
+				return
+			}
+			k.Log(NOTICE, "we are the leader")
+			k.Sme.Thaw()
+			<-lost
+			k.Log(NOTICE, "we are no longer the leader")
+			k.Sme.Freeze()
+		}
+	}()
+}
+
 ////////////////////////
 // Unexported methods /
 //////////////////////
@@ -0,0 +1,130 @@
+/* Metrics.go: counters and histograms the StateMutationEngine keeps about mutations
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// ConvergeBuckets are the bucket bounds (in seconds) of the time-to-converge histogram
+var ConvergeBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// A Histogram counts observations in buckets
+// It is not safe for concurrent use.
+type Histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a Histogram with buckets bounded above by bounds (which must be sorted)
+// there is always one more bucket for observations above every bound
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe counts an observation
+func (h *Histogram) Observe(v float64) {
+	h.counts[sort.SearchFloat64s(h.bounds, v)]++
+	h.count++
+	h.sum += v
+}
+
+// Proto gets a copy of the Histogram as a protobuf message
+func (h *Histogram) Proto() *pb.Histogram {
+	return &pb.Histogram{
+		Bounds: append([]float64{}, h.bounds...),
+		Counts: append([]uint64{}, h.counts...),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
+
+////////////////////////////
+// mutationMetrics Object /
+//////////////////////////
+
+// mutationMetrics are what the SME counts about mutations
+type mutationMetrics struct {
+	mutex       *sync.Mutex
+	since       time.Time
+	edges       map[[2]string]*pb.MutationEdgeMetrics // keyed by module/mutation id
+	converge    *Histogram
+	discoveries uint64
+}
+
+func newMutationMetrics() *mutationMetrics {
+	return &mutationMetrics{
+		mutex:    &sync.Mutex{},
+		since:    time.Now(),
+		edges:    make(map[[2]string]*pb.MutationEdgeMetrics),
+		converge: NewHistogram(ConvergeBuckets),
+	}
+}
+
+// count updates the counters for a mutation with f
+func (m *mutationMetrics) count(mr [2]string, f func(*pb.MutationEdgeMetrics)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	em, ok := m.edges[mr]
+	if !ok {
+		em = &pb.MutationEdgeMetrics{Module: mr[0], Mutation: mr[1]}
+		m.edges[mr] = em
+	}
+	f(em)
+}
+
+// converged records how long a node took to get through a mutation chain
+func (m *mutationMetrics) converged(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.converge.Observe(d.Seconds())
+}
+
+// discovered counts a discovery
+func (m *mutationMetrics) discovered() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.discoveries++
+}
+
+// proto gets a copy of the metrics as a protobuf message, with edges sorted by module and mutation
+func (m *mutationMetrics) proto(active int) (r pb.MutationMetrics) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	r.Since, _ = ptypes.TimestampProto(m.since)
+	for _, em := range m.edges {
+		c := *em
+		r.Edges = append(r.Edges, &c)
+	}
+	sort.Slice(r.Edges, func(i, j int) bool {
+		if r.Edges[i].Module != r.Edges[j].Module {
+			return r.Edges[i].Module < r.Edges[j].Module
+		}
+		return r.Edges[i].Mutation < r.Edges[j].Mutation
+	})
+	r.Converge = m.converge.Proto()
+	r.Discoveries = m.discoveries
+	r.Active = uint32(active)
+	return
+}
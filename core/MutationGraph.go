@@ -0,0 +1,99 @@
+/* MutationGraph.go: renders the StateMutationEngine's mutation graph for humans (e.g. as Graphviz DOT)
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// MutationGraphDOT renders a mutation graph (as returned by the MUTATIONNODES/MUTATIONEDGES queries) in Graphviz DOT format.
+// Nodes are labeled with their requires/excludes, edges with the owning module, mutation id, what they mutate, their timeout
+// and their cost (if it isn't the default).
+// If a node or edge has a color set (e.g. to show a mutation path) it is used.
+func MutationGraphDOT(nodes *pb.MutationNodeList, edges *pb.MutationEdgeList) []byte {
+	var b bytes.Buffer
+	b.WriteString("digraph mutations {\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, n := range nodes.MutationNodeList {
+		lines := append(
+			mapLines(n.Requires, " = "),
+			mapLines(n.Excludes, " != ")...,
+		)
+		if len(lines) == 0 {
+			lines = []string{"(any)"}
+		}
+		attrs := []string{"label=" + dotQuote(strings.Join(lines, "\n"))}
+		if n.Color != "" {
+			attrs = append(attrs, "color="+dotQuote(n.Color))
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(n.Id), strings.Join(attrs, ", "))
+	}
+	for _, e := range edges.MutationEdgeList {
+		lines := []string{e.Module + ": " + e.Mutation}
+		lines = append(lines, mapLines(e.Mutates, ": ")...)
+		if e.Timeout != "" {
+			lines = append(lines, "timeout: "+e.Timeout)
+		}
+		if e.Cost != DefaultMutationCost {
+			lines = append(lines, fmt.Sprintf("cost: %d", e.Cost))
+		}
+		// requires/excludes are already visible on the from node, so they go in the tooltip
+		tips := append(
+			mapLines(e.Requires, " = "),
+			mapLines(e.Excludes, " != ")...,
+		)
+		attrs := []string{"label=" + dotQuote(strings.Join(lines, "\n"))}
+		if len(tips) > 0 {
+			attrs = append(attrs, "tooltip="+dotQuote(strings.Join(tips, "\n")))
+		}
+		if e.Color != nil && e.Color.Color != "" {
+			attrs = append(attrs, "color="+dotQuote(e.Color.Color))
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// valueMapToStrings converts a map of values to printable strings
+func valueMapToStrings(m map[string]reflect.Value) (r map[string]string) {
+	r = make(map[string]string)
+	for u, v := range m {
+		r[u] = lib.ValueToString(v)
+	}
+	return
+}
+
+// mapLines gives sorted "key<sep>value" lines for a map
+func mapLines(m map[string]string, sep string) (r []string) {
+	for k, v := range m {
+		r = append(r, k+sep+v)
+	}
+	sort.Strings(r)
+	return
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote makes a quoted DOT ID
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,65 @@
+/* MutationHook.go: hooks run before or after mutations, e.g. to drain a node from a scheduler before it's powered off
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// A MutationHook runs before or after a mutation.
+// mutation is the module and mutation ID; cfg and dsc are the node's configuration and discoverable state.
+// If a pre hook fails, the mutation isn't sent, and fails as if it had timed out.
+// Pre hooks have to finish within the mutation's timeout; ctx is done when it runs out.
+type MutationHook func(ctx context.Context, mutation [2]string, cfg, dsc lib.Node) error
+
+// MutationHookPoint is when a MutationHook runs
+type MutationHookPoint uint8
+
+const (
+	MutationHook_PRE  MutationHookPoint = 0 // before the mutation is sent
+	MutationHook_POST MutationHookPoint = 1 // once the mutation's changes have been discovered
+)
+
+var MutationHookPointString = map[MutationHookPoint]string{
+	MutationHook_PRE:  "pre",
+	MutationHook_POST: "post",
+}
+
+// NewScriptMutationHook makes a MutationHook that runs an external command.
+// The command gets the node's configuration state as JSON on stdin, and these environment variables:
+// KRAKEN_HOOK (pre or post), KRAKEN_NODE, KRAKEN_MODULE and KRAKEN_MUTATION.
+// The hook fails if the command exits non-zero; it's killed if ctx is done first.
+func NewScriptMutationHook(point MutationHookPoint, path string, args ...string) MutationHook {
+	return func(ctx context.Context, mutation [2]string, cfg, dsc lib.Node) error {
+		cmd := exec.CommandContext(ctx, path, args...)
+		cmd.Env = append(os.Environ(),
+			"KRAKEN_HOOK="+MutationHookPointString[point],
+			"KRAKEN_NODE="+cfg.ID().String(),
+			"KRAKEN_MODULE="+mutation[0],
+			"KRAKEN_MUTATION="+mutation[1],
+		)
+		cmd.Stdin = bytes.NewReader(cfg.JSON())
+		if out, e := cmd.CombinedOutput(); e != nil {
+			return fmt.Errorf("%s hook %s failed: %v: %s", MutationHookPointString[point], path, e, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+}
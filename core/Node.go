@@ -150,7 +150,7 @@ func (n *Node) GetValue(url string) (v reflect.Value, e error) {
 		}
 		n.mutex.RLock()
 		defer n.mutex.RUnlock()
-		return lib.ResolveURL(sub, reflect.ValueOf(ext))
+		return copyScalar(lib.ResolveURL(sub, reflect.ValueOf(ext)))
 	case "Services": // resolve service
 		p, sub := lib.URLShift(sub)
 		srv := n.GetService(p)
@@ -160,14 +160,32 @@ func (n *Node) GetValue(url string) (v reflect.Value, e error) {
 		}
 		n.mutex.RLock()
 		defer n.mutex.RUnlock()
-		return lib.ResolveURL(sub, reflect.ValueOf(srv.Message()))
+		return copyScalar(lib.ResolveURL(sub, reflect.ValueOf(srv.Message())))
 	default: // everything else
 		n.mutex.RLock()
 		defer n.mutex.RUnlock()
-		return lib.ResolveURL(url, reflect.ValueOf(n.pb))
+		return copyScalar(lib.ResolveURL(url, reflect.ValueOf(n.pb)))
 	}
 }
 
+// copyScalar copies a resolved value if it's a scalar, so it can be read once the node's lock is released.
+// Anything else still refers to the node.
+func copyScalar(v reflect.Value, e error) (reflect.Value, error) {
+	if e != nil || !v.IsValid() {
+		return v, e
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return c, nil
+	}
+	return v, nil
+}
+
 // SetValue sets a specific value (reflect.Value) by URL
 // Returns the value, post-set (same if input if all went well)
 // note: we can't just wrap everything in a lock because n.GetService will lock too
@@ -346,6 +364,67 @@ func (n *Node) HasService(id string) bool {
 	return false
 }
 
+// Frozen reports whether the node is in maintenance mode, i.e. it shouldn't be mutated
+func (n *Node) Frozen() bool {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.pb.Frozen
+}
+
+// Priority is how urgently the SME should dispatch mutations of the node
+func (n *Node) Priority() pb.Node_Priority {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.pb.Priority
+}
+
+// Lifecycle is whether the node is in service, or being (or has been) decommissioned
+func (n *Node) Lifecycle() pb.Node_Lifecycle {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.pb.Lifecycle
+}
+
+func (n *Node) AddGroup(group string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, g := range n.pb.Groups {
+		if g == group {
+			return
+		}
+	}
+	n.pb.Groups = append(n.pb.Groups, group)
+}
+
+func (n *Node) DelGroup(group string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for i, g := range n.pb.Groups {
+		if g == group {
+			n.pb.Groups = append(n.pb.Groups[:i], n.pb.Groups[i+1:]...)
+			return
+		}
+	}
+}
+
+func (n *Node) GetGroups() (r []string) {
+	n.mutex.RLock()
+	r = append(r, n.pb.Groups...)
+	n.mutex.RUnlock()
+	return
+}
+
+func (n *Node) InGroup(group string) bool {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	for _, g := range n.pb.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
 // Diff finds URLs that are different between this Node and another
 // prefix allows a string prefix to be prepended to diffs
 // note: we have to be especially careful about locking in this function
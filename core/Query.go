@@ -190,6 +190,29 @@ func (q *QueryEngine) ReadNodeMutationPath(url string) (mc pb.MutationPath, e er
 	return v[0].Interface().(pb.MutationPath), e
 }
 
+// ReadMutationPlan gets the mutation path the SME would take a node on to reach the configuration n,
+// starting from the node's current discoverable state.  Nothing is actually mutated.
+func (q *QueryEngine) ReadMutationPlan(n lib.Node) (mc pb.MutationPath, e error) {
+	url := lib.NodeURLJoin(n.ID().String(), "/graph/plan")
+	query, r := NewQuery(lib.Query_MUTATIONPLAN, lib.QueryState_BOTH, url, []reflect.Value{reflect.ValueOf(n)})
+	v, e := q.blockingQuery(query, r)
+	if len(v) < 1 || !v[0].IsValid() {
+		return
+	}
+	return v[0].Interface().(pb.MutationPath), e
+}
+
+// ReadMutationMetrics gets the SME's counts of mutations started, succeeded, failed and retried,
+// how long nodes take to converge, and how many discoveries it has seen
+func (q *QueryEngine) ReadMutationMetrics() (mm pb.MutationMetrics, e error) {
+	query, r := NewQuery(lib.Query_MUTATIONMETRICS, lib.QueryState_BOTH, "", []reflect.Value{})
+	v, e := q.blockingQuery(query, r)
+	if len(v) < 1 || !v[0].IsValid() {
+		return
+	}
+	return v[0].Interface().(pb.MutationMetrics), e
+}
+
 // Update will update a node in the Engine's Cfg store
 func (q *QueryEngine) Update(n lib.Node) (nc lib.Node, e error) {
 	query, r := NewQuery(
@@ -260,6 +283,148 @@ func (q *QueryEngine) ReadAllDsc() (nc []lib.Node, e error) {
 	return
 }
 
+// ReadGroup will get a slice of the nodes in a group from the Cfg state
+func (q *QueryEngine) ReadGroup(group string) (nc []lib.Node, e error) {
+	query, r := NewQuery(
+		lib.Query_READGROUP,
+		lib.QueryState_CONFIG,
+		group,
+		[]reflect.Value{})
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
+// ReadGroupDsc will get a slice of the nodes in a group from the Dsc state
+func (q *QueryEngine) ReadGroupDsc(group string) (nc []lib.Node, e error) {
+	query, r := NewQuery(
+		lib.Query_READGROUP,
+		lib.QueryState_DISCOVER,
+		group,
+		[]reflect.Value{})
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
+// Transact will set several (node qualified) URLs in the Cfg state at once, see StateDifferenceEngine.Transact
+func (q *QueryEngine) Transact(vs map[string]reflect.Value) (e error) {
+	query, r := NewQuery(
+		lib.Query_TRANSACT,
+		lib.QueryState_CONFIG,
+		"",
+		[]reflect.Value{reflect.ValueOf(vs)})
+	_, e = q.blockingQuery(query, r)
+	return
+}
+
+// ReadConvergence gets whether each node's discovered state matches its configuration, and since when it hasn't (see StateMutationEngine.Convergence)
+func (q *QueryEngine) ReadConvergence() (r []*pb.NodeConvergence, e error) {
+	query, qr := NewQuery(lib.Query_CONVERGENCE, lib.QueryState_BOTH, "", []reflect.Value{})
+	v, e := q.blockingQuery(query, qr)
+	if len(v) < 1 || !v[0].IsValid() {
+		return
+	}
+	return v[0].Interface().([]*pb.NodeConvergence), e
+}
+
+// ReadRollups gets summaries of the discovered state of nodes at a location, and every location below it
+// (see Rollups); an empty location is the whole cluster
+func (q *QueryEngine) ReadRollups(location string) (r []*pb.Rollup, e error) {
+	query, qr := NewQuery(lib.Query_ROLLUPS, lib.QueryState_BOTH, location, []reflect.Value{})
+	v, e := q.blockingQuery(query, qr)
+	if len(v) < 1 || !v[0].IsValid() {
+		return
+	}
+	return v[0].Interface().([]*pb.Rollup), e
+}
+
+// Decommission starts retiring a node.  final sets values in its configuration (as they would be printed,
+// e.g. "/PhysState": "POWER_OFF") to take it to its final state, and the node is marked DECOMMISSIONING.
+// Once the SME gets it there, it's marked DECOMMISSIONED and left alone; if remove is set, it is then archived and removed.
+// The changes are made as one transaction, so there's a single mutation chain to the final state.
+func (q *QueryEngine) Decommission(nid lib.NodeID, final map[string]string, remove bool) (nc lib.Node, e error) {
+	n, e := q.Read(nid)
+	if e != nil {
+		return
+	}
+	node := nid.String()
+	vs := map[string]reflect.Value{
+		lib.NodeURLJoin(node, "/Lifecycle"):            reflect.ValueOf(pb.Node_DECOMMISSIONING),
+		lib.NodeURLJoin(node, "/RemoveDecommissioned"): reflect.ValueOf(remove),
+	}
+	for u, s := range final {
+		cur, e := n.GetValue(u)
+		if e != nil {
+			return nil, e
+		}
+		if vs[lib.NodeURLJoin(node, u)], e = ValueFromString(cur.Type(), s); e != nil {
+			return nil, e
+		}
+	}
+	if e = q.Transact(vs); e != nil {
+		return
+	}
+	return q.Read(nid)
+}
+
+// TransactDsc will set several (node qualified) URLs in the Dsc state at once, see StateDifferenceEngine.Transact
+func (q *QueryEngine) TransactDsc(vs map[string]reflect.Value) (e error) {
+	query, r := NewQuery(
+		lib.Query_TRANSACT,
+		lib.QueryState_DISCOVER,
+		"",
+		[]reflect.Value{reflect.ValueOf(vs)})
+	_, e = q.blockingQuery(query, r)
+	return
+}
+
+// Select will get a slice of the nodes in the Cfg state that match a selector expression (see NodeSelector)
+func (q *QueryEngine) Select(expr string) (nc []lib.Node, e error) {
+	query, r := NewQuery(
+		lib.Query_SELECT,
+		lib.QueryState_CONFIG,
+		expr,
+		[]reflect.Value{})
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
+// SelectDsc will get a slice of the nodes in the Dsc state that match a selector expression (see NodeSelector)
+func (q *QueryEngine) SelectDsc(expr string) (nc []lib.Node, e error) {
+	query, r := NewQuery(
+		lib.Query_SELECT,
+		lib.QueryState_DISCOVER,
+		expr,
+		[]reflect.Value{})
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
+// UpdateGroup will set the values set in n on every node in a group, see StateDifferenceEngine.UpdateGroup
+func (q *QueryEngine) UpdateGroup(group string, n lib.Node) (nc []lib.Node, e error) {
+	query, r := NewQuery(
+		lib.Query_UPDATEGROUP,
+		lib.QueryState_CONFIG,
+		group,
+		[]reflect.Value{reflect.ValueOf(n)})
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
 // DeleteAll will delete all nodes from the Engine.  !!!DANGEROUS!!!
 func (q *QueryEngine) DeleteAll() (nc []lib.Node, e error) {
 	query, r := NewQuery(
@@ -274,6 +439,24 @@ func (q *QueryEngine) DeleteAll() (nc []lib.Node, e error) {
 	return
 }
 
+// Restore replaces the Engine's Cfg state with ns, see StateDifferenceEngine.Restore
+func (q *QueryEngine) Restore(ns []lib.Node) (nc []lib.Node, e error) {
+	var vs []reflect.Value
+	for _, n := range ns {
+		vs = append(vs, reflect.ValueOf(n))
+	}
+	query, r := NewQuery(
+		lib.Query_RESTORE,
+		lib.QueryState_CONFIG,
+		"",
+		vs)
+	v, e := q.blockingQuery(query, r)
+	for _, i := range v {
+		nc = append(nc, i.Interface().(lib.Node))
+	}
+	return
+}
+
 // GetValue will get a value from the Cfg state via a URL
 func (q *QueryEngine) GetValue(url string) (v reflect.Value, e error) {
 	query, r := NewQuery(
@@ -62,9 +62,10 @@ var _ jsonpb.AnyResolver = (*KrakenRegistry)(nil)
 type KrakenRegistry struct {
 	Modules          map[string]lib.Module
 	Extensions       map[string]lib.Extension
-	Discoverables    map[string]map[string]map[string]reflect.Value // d["module"]["property_url"]["value_id"]
-	Mutations        map[string]map[string]lib.StateMutation        // m["module"]["mutation_id"]
-	ServiceInstances map[string]map[string]lib.ServiceInstance      // s["module"]["instance_id"]
+	Discoverables    map[string]map[string]map[string]reflect.Value  // d["module"]["property_url"]["value_id"]
+	Mutations        map[string]map[string]lib.StateMutation         // m["module"]["mutation_id"]
+	ServiceInstances map[string]map[string]lib.ServiceInstance       // s["module"]["instance_id"]
+	MutationHooks    map[MutationHookPoint]map[string][]MutationHook // h[point]["module" or "module:mutation_id"]
 }
 
 func NewKrakenRegistry() *KrakenRegistry {
@@ -74,6 +75,10 @@ func NewKrakenRegistry() *KrakenRegistry {
 		Discoverables:    make(map[string]map[string]map[string]reflect.Value),
 		Mutations:        make(map[string]map[string]lib.StateMutation),
 		ServiceInstances: make(map[string]map[string]lib.ServiceInstance),
+		MutationHooks: map[MutationHookPoint]map[string][]MutationHook{
+			MutationHook_PRE:  make(map[string][]MutationHook),
+			MutationHook_POST: make(map[string][]MutationHook),
+		},
 	}
 	return r
 }
@@ -111,6 +116,12 @@ func (r *KrakenRegistry) RegisterServiceInstance(m lib.Module, d map[string]lib.
 	r.ServiceInstances[m.Name()] = d
 }
 
+// RegisterMutationHook adds a hook to run before or after a module's mutations
+// mutation is either a module (all of its mutations) or module:mutation_id
+func (r *KrakenRegistry) RegisterMutationHook(p MutationHookPoint, mutation string, h MutationHook) {
+	r.MutationHooks[p][mutation] = append(r.MutationHooks[p][mutation], h)
+}
+
 func (r *KrakenRegistry) Resolve(url string) (proto.Message, error) {
 	if e, ok := r.Extensions[url]; ok {
 		return e.New(), nil
@@ -0,0 +1,85 @@
+/* Rollup.go: rolls the discovered state of nodes up to the racks/chassis/etc. they're in
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"sort"
+	"strings"
+
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// Rollups summarizes the discovered state of nodes by their location.
+// A node is counted at its location, and at every location above it; e.g. a node in rack12/chassis3
+// is counted in rack12/chassis3, rack12 and the whole cluster (the empty location).
+// Only the rollups for location, and those below it, are returned, sorted by location.
+// cfg nodes give the locations, dsc nodes give the state; nodes missing from dsc are counted as unknown.
+func Rollups(cfg, dsc []lib.Node, location string) (r []*pb.Rollup) {
+	location = strings.Trim(location, "/")
+	states := make(map[string]lib.Node)
+	for _, n := range dsc {
+		states[n.ID().String()] = n
+	}
+	rs := make(map[string]*pb.Rollup)
+	for _, n := range cfg {
+		v, _ := n.GetValue("/Location")
+		loc := strings.Trim(v.String(), "/")
+		if location != "" && loc != location && !strings.HasPrefix(loc, location+"/") {
+			continue
+		}
+		phys, run := pb.Node_PHYS_UNKNOWN, pb.Node_UNKNOWN
+		if d, ok := states[n.ID().String()]; ok {
+			if v, e := d.GetValue("/PhysState"); e == nil {
+				phys = v.Interface().(pb.Node_PhysState)
+			}
+			if v, e := d.GetValue("/RunState"); e == nil {
+				run = v.Interface().(pb.Node_RunState)
+			}
+		}
+		for _, l := range rollupLocations(loc, location) {
+			ru, ok := rs[l]
+			if !ok {
+				ru = &pb.Rollup{
+					Location:  l,
+					PhysState: make(map[string]uint32),
+					RunState:  make(map[string]uint32),
+				}
+				rs[l] = ru
+			}
+			ru.Nodes++
+			ru.PhysState[phys.String()]++
+			ru.RunState[run.String()]++
+		}
+	}
+	for _, ru := range rs {
+		r = append(r, ru)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Location < r[j].Location })
+	return
+}
+
+// rollupLocations gives loc and every location above it, up to (and including) top
+func rollupLocations(loc, top string) (r []string) {
+	for {
+		r = append(r, loc)
+		if loc == top {
+			return
+		}
+		i := strings.LastIndex(loc, "/")
+		if i < 0 {
+			if top == "" {
+				r = append(r, "")
+			}
+			return
+		}
+		loc = loc[:i]
+	}
+}
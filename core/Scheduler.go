@@ -0,0 +1,281 @@
+/* Scheduler.go: the Scheduler makes changes to the configuration state at scheduled times
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// A CronSchedule is a parsed cron expression: minute hour day-of-month month day-of-week.
+// Fields can be *, a number, a range (a-b), a list (a,b), and any of these with a step (*/15, 1-5/2).
+// As in cron, if both day-of-month and day-of-week are restricted, a time matches if either does.
+type CronSchedule struct {
+	fields [5]uint64 // bit sets
+	dom    bool      // day-of-month is restricted
+	dow    bool      // day-of-week is restricted
+}
+
+var cronBounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// ParseCron parses a cron expression
+func ParseCron(expr string) (c *CronSchedule, e error) {
+	fs := strings.Fields(expr)
+	if len(fs) != 5 {
+		return nil, fmt.Errorf("cron expression needs 5 fields: %s", expr)
+	}
+	c = &CronSchedule{}
+	for i, f := range fs {
+		if c.fields[i], e = parseCronField(f, cronBounds[i][0], cronBounds[i][1]); e != nil {
+			return nil, fmt.Errorf("bad cron expression %s: %v", expr, e)
+		}
+	}
+	// 7 is also Sunday
+	if c.fields[4]&(1<<7) != 0 {
+		c.fields[4] |= 1
+	}
+	c.dom = fs[2] != "*"
+	c.dow = fs[4] != "*"
+	return
+}
+
+// Match reports whether the minute of t is in the schedule
+func (c *CronSchedule) Match(t time.Time) bool {
+	in := func(i, v int) bool { return c.fields[i]&(1<<uint(v)) != 0 }
+	if !in(0, t.Minute()) || !in(1, t.Hour()) || !in(3, int(t.Month())) {
+		return false
+	}
+	dom, dow := in(2, t.Day()), in(4, int(t.Weekday()))
+	if c.dom && c.dow {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// ValueFromString makes a value of type t from the way it would be printed (see lib.ValueToString)
+// e.g. "POWER_OFF" for a PhysState, or "true" for a bool
+func ValueFromString(t reflect.Type, s string) (v reflect.Value, e error) {
+	v = reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, e = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int32:
+		// protobuf enums are int32s, and are registered by their (go) type name
+		if m := proto.EnumValueMap(t.String()); m != nil {
+			if i, ok := m[s]; ok {
+				v.SetInt(int64(i))
+				return
+			}
+		}
+		fallthrough
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		var i int64
+		i, e = strconv.ParseInt(s, 10, t.Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, e = strconv.ParseUint(s, 10, t.Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, e = strconv.ParseFloat(s, t.Bits())
+		v.SetFloat(f)
+	default:
+		e = fmt.Errorf("can't make a %s from a string", t.String())
+	}
+	if e != nil {
+		e = fmt.Errorf("bad value for %s: %s", t.String(), s)
+	}
+	return
+}
+
+//////////////////////
+// Scheduler Object /
+////////////////////
+
+// The Scheduler makes the changes in nodes' schedules (see ScheduledChange) when they come due.
+// It checks once a minute, so that's the finest resolution of a schedule.
+// Changes that come due while kraken isn't running, or while we're frozen (e.g. an HA standby), are skipped.
+type Scheduler struct {
+	query  *QueryEngine
+	log    lib.Logger
+	frozen func() bool // nil if we're never frozen; see SetFrozen
+}
+
+// NewScheduler creates an initialized Scheduler
+func NewScheduler(ctx Context) *Scheduler {
+	s := &Scheduler{
+		query: &ctx.Query,
+		log:   &ctx.Logger,
+	}
+	s.log.SetModule("Scheduler")
+	return s
+}
+
+// SetFrozen tells the Scheduler how to check if we're frozen (see StateMutationEngine.Frozen)
+// Every head node shares the configuration, so only the one that isn't frozen, i.e. the leader, makes changes.
+func (s *Scheduler) SetFrozen(frozen func() bool) {
+	s.frozen = frozen
+}
+
+// Run is a goroutine that makes scheduled changes
+func (s *Scheduler) Run() {
+	s.Log(INFO, "starting Scheduler")
+	last := time.Now()
+	for {
+		// wake up at the start of the next minute
+		time.Sleep(time.Until(time.Now().Truncate(time.Minute).Add(time.Minute)))
+		now := time.Now()
+		if s.frozen == nil || !s.frozen() {
+			s.apply(last, now)
+		}
+		last = now
+	}
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// apply makes every change that came due after from, up to and including to
+func (s *Scheduler) apply(from, to time.Time) {
+	ns, e := s.query.ReadAll()
+	if e != nil {
+		s.Logf(ERROR, "couldn't read nodes: %v", e)
+		return
+	}
+	for _, n := range ns {
+		if n.Lifecycle() != pb.Node_ACTIVE {
+			// decommissioning nodes get their final state, and nothing more
+			continue
+		}
+		v, e := n.GetValue("/Schedule")
+		if e != nil {
+			continue
+		}
+		cs, _ := v.Interface().([]*pb.ScheduledChange)
+		for _, c := range cs {
+			due, e := scheduledChangeDue(c, from, to)
+			if e != nil {
+				s.Logf(ERROR, "bad schedule for %s: %v", n.ID().String(), e)
+				continue
+			}
+			if !due {
+				continue
+			}
+			url := lib.NodeURLJoin(n.ID().String(), c.Url)
+			cur, e := n.GetValue(c.Url)
+			if e != nil {
+				s.Logf(ERROR, "bad scheduled change for %s: %v", url, e)
+				continue
+			}
+			val, e := ValueFromString(cur.Type(), c.Value)
+			if e == nil {
+				_, e = s.query.SetValue(url, val)
+			}
+			if e != nil {
+				s.Logf(ERROR, "scheduled change for %s failed: %v", url, e)
+				continue
+			}
+			s.Logf(INFO, "scheduled change: %s = %s", url, c.Value)
+		}
+	}
+}
+
+// scheduledChangeDue reports whether a change came due after from, up to and including to
+func scheduledChangeDue(c *pb.ScheduledChange, from, to time.Time) (bool, error) {
+	if c.At != "" {
+		at, e := time.Parse(time.RFC3339, c.At)
+		if e != nil {
+			return false, e
+		}
+		return at.After(from) && !at.After(to), nil
+	}
+	cron, e := ParseCron(c.Cron)
+	if e != nil {
+		return false, e
+	}
+	for t := from.Truncate(time.Minute).Add(time.Minute); !t.After(to); t = t.Add(time.Minute) {
+		if cron.Match(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseCronField(f string, min, max int) (r uint64, e error) {
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if sp := strings.SplitN(part, "/", 2); len(sp) == 2 {
+			if step, e = strconv.Atoi(sp[1]); e != nil || step < 1 {
+				return 0, fmt.Errorf("bad step: %s", part)
+			}
+			part = sp[0]
+		}
+		lo, hi := min, max
+		if part != "*" {
+			sp := strings.SplitN(part, "-", 2)
+			if lo, e = strconv.Atoi(sp[0]); e != nil {
+				return 0, fmt.Errorf("bad value: %s", part)
+			}
+			hi = lo
+			if len(sp) == 2 {
+				if hi, e = strconv.Atoi(sp[1]); e != nil {
+					return 0, fmt.Errorf("bad value: %s", part)
+				}
+			} else if step > 1 {
+				hi = max // e.g. 5/15 is 5-max/15
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("out of range: %s", part)
+		}
+		for i := lo; i <= hi; i += step {
+			r |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+////////////////////////////
+// Passthrough Interfaces /
+//////////////////////////
+
+/*
+ * Consume Logger
+ */
+var _ lib.Logger = (*Scheduler)(nil)
+
+func (s *Scheduler) Log(level lib.LoggerLevel, m string) { s.log.Log(level, m) }
+func (s *Scheduler) Logf(level lib.LoggerLevel, fmt string, v ...interface{}) {
+	s.log.Logf(level, fmt, v...)
+}
+func (s *Scheduler) SetModule(name string)                { s.log.SetModule(name) }
+func (s *Scheduler) GetModule() string                    { return s.log.GetModule() }
+func (s *Scheduler) SetLoggerLevel(level lib.LoggerLevel) { s.log.SetLoggerLevel(level) }
+func (s *Scheduler) GetLoggerLevel() lib.LoggerLevel      { return s.log.GetLoggerLevel() }
+func (s *Scheduler) IsEnabledFor(level lib.LoggerLevel) bool {
+	return s.log.IsEnabledFor(level)
+}
@@ -0,0 +1,227 @@
+/* Select.go: selectors pick out nodes by predicates on their values, so filtering can happen server-side
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+type selectTerm struct {
+	url   string
+	value string
+	not   bool
+}
+
+// ExpandURL resolves wildcards in a URL against a node.  A "*" element matches any element
+// (a field name, slice index, service ID or extension), e.g. /Services/*/State.
+// Only URLs that exist in the node are returned.
+func ExpandURL(n lib.Node, url string) (r []string) {
+	urls := []string{""}
+	for _, s := range strings.Split(strings.Trim(url, "/"), "/") {
+		var next []string
+		for _, u := range urls {
+			if s == "*" {
+				next = append(next, urlChildren(n, u)...)
+			} else {
+				next = append(next, lib.URLPush(u, s))
+			}
+		}
+		urls = next
+	}
+	for _, u := range urls {
+		if _, e := n.GetValue(u); e == nil {
+			r = append(r, u)
+		}
+	}
+	return
+}
+
+// SelectValues gets the (printed) values of URLs, which may have wildcards, in a node
+func SelectValues(n lib.Node, urls []string) (r map[string]string) {
+	r = make(map[string]string)
+	for _, url := range urls {
+		for _, u := range ExpandURL(n, url) {
+			if v, e := n.GetValue(u); e == nil {
+				r[u] = lib.ValueToString(v)
+			}
+		}
+	}
+	return
+}
+
+// SortNodes sorts nodes by their value at a URL, in ascending order unless desc is set.
+// Numbers (including enums, e.g. /PhysState) sort by value, everything else by how it's printed.
+// Nodes without the URL sort last; ties are broken by ID, so the order is stable for paging.
+func SortNodes(ns []lib.Node, url string, desc bool) {
+	type key struct {
+		ok  bool
+		num float64
+		str string
+	}
+	keys := make(map[lib.Node]key, len(ns))
+	for _, n := range ns {
+		v, e := n.GetValue(url)
+		if e != nil {
+			keys[n] = key{}
+			continue
+		}
+		k := key{ok: true, str: lib.ValueToString(v)}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			k.num = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			k.num = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			k.num = v.Float()
+		}
+		keys[n] = k
+	}
+	sort.SliceStable(ns, func(i, j int) bool {
+		a, b := keys[ns[i]], keys[ns[j]]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		if a.num != b.num {
+			return (a.num < b.num) != desc
+		}
+		if a.str != b.str {
+			return (a.str < b.str) != desc
+		}
+		return ns[i].ID().String() < ns[j].ID().String()
+	})
+}
+
+////////////////////////////
+// NodeSelector Object /
+//////////////////////////
+
+// A NodeSelector matches nodes with a predicate on their values, e.g.
+//   /PhysState == POWER_OFF && /Platform == powerman || /Services/*/State == ERROR
+// Terms compare the (printed) value at a URL with == or !=; && binds tighter than ||.
+// A URL with wildcards (see ExpandURL) is equal if any value it expands to is.
+// An empty selector matches every node.
+type NodeSelector struct {
+	any [][]selectTerm // OR of ANDs
+}
+
+// ParseNodeSelector parses a selector expression
+func ParseNodeSelector(expr string) (s *NodeSelector, e error) {
+	s = &NodeSelector{}
+	if strings.TrimSpace(expr) == "" {
+		return
+	}
+	for _, or := range strings.Split(expr, "||") {
+		var all []selectTerm
+		for _, and := range strings.Split(or, "&&") {
+			var t selectTerm
+			sp := strings.SplitN(and, "==", 2)
+			if len(sp) != 2 {
+				sp = strings.SplitN(and, "!=", 2)
+				t.not = true
+			}
+			if len(sp) != 2 {
+				return nil, fmt.Errorf("bad selector term, expected <url> == <value> or <url> != <value>: %s", strings.TrimSpace(and))
+			}
+			t.url = strings.TrimSpace(sp[0])
+			t.value = strings.TrimSpace(sp[1])
+			if uq, err := strconv.Unquote(t.value); err == nil {
+				t.value = uq
+			}
+			if t.url == "" {
+				return nil, fmt.Errorf("bad selector term, missing url: %s", strings.TrimSpace(and))
+			}
+			all = append(all, t)
+		}
+		s.any = append(s.any, all)
+	}
+	return
+}
+
+// Match reports whether a node matches the selector
+func (s *NodeSelector) Match(n lib.Node) bool {
+	if len(s.any) == 0 {
+		return true
+	}
+	for _, all := range s.any {
+		match := true
+		for _, t := range all {
+			if t.match(n) == t.not {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// match reports whether any value at the term's URL is equal to its value
+func (t selectTerm) match(n lib.Node) bool {
+	for _, u := range ExpandURL(n, t.url) {
+		if v, e := n.GetValue(u); e == nil && lib.ValueToString(v) == t.value {
+			return true
+		}
+	}
+	return false
+}
+
+// urlChildren lists the URLs of the elements directly below a URL in a node
+func urlChildren(n lib.Node, url string) (r []string) {
+	switch url {
+	case "/Services":
+		for _, id := range n.GetServiceIDs() {
+			r = append(r, lib.URLPush(url, id))
+		}
+		return
+	case "/type.googleapis.com":
+		for _, x := range n.GetExtensionURLs() {
+			r = append(r, "/"+x)
+		}
+		return
+	}
+	v, e := n.GetValue(url)
+	if e != nil {
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") || (url == "" && f.Name == "Extensions") {
+				continue
+			}
+			r = append(r, lib.URLPush(url, f.Name))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			r = append(r, lib.URLPush(url, strconv.Itoa(i)))
+		}
+	}
+	return
+}
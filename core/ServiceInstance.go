@@ -10,9 +10,11 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 
@@ -95,6 +97,11 @@ func (ss *ServiceInstance) GetState() lib.ServiceState {
 	return lib.Service_RUN
 }
 
+// Ready reports whether the service is running, and has called in for its control stream (see APIServer.ServiceInit)
+func (ss *ServiceInstance) Ready() bool {
+	return ss.state == lib.Service_RUN && ss.ctl != nil
+}
+
 // Name returns the service name (i.e. Args[0])
 func (ss *ServiceInstance) Module() string { return ss.module }
 
@@ -133,11 +140,18 @@ func (ss *ServiceInstance) Message() *pb.ServiceInstance {
 	return si
 }
 
-func (ss *ServiceInstance) Stop() {
+// Stop asks the service to exit by deadline.  It doesn't wait for it to; see ServiceManager.StopService
+func (ss *ServiceInstance) Stop(deadline time.Time) {
 	ss.SetState(lib.Service_STOP)
 	if ss.ctl != nil {
-		ss.ctl <- lib.ServiceControl{Command: lib.ServiceControl_STOP}
-		ss.cmd.Process.Wait()
+		ss.ctl <- lib.ServiceControl{Command: lib.ServiceControl_STOP, Deadline: deadline}
+	}
+}
+
+// Discover asks the service to re-discover nodes now, rather than at its next poll.  It's a no-op if the service isn't running.
+func (ss *ServiceInstance) Discover(nodes []string) {
+	if ss.ctl != nil {
+		ss.ctl <- lib.ServiceControl{Command: lib.ServiceControl_DISCOVER, Nodes: nodes}
 	}
 }
 
@@ -189,14 +203,15 @@ func ModuleExecute(id, module, sock string) {
 	}
 
 	// Setup discovery stream if we need it
+	var dc chan<- lib.Event
 	md, ok := m.(lib.ModuleWithDiscovery)
 	if ok {
-		cc, e := api.DiscoveryInit()
+		dc, e = api.DiscoveryInit()
 		if e != nil {
 			api.Logf(ERROR, "failed to create discovery stream: %v\n", e)
 			return
 		}
-		md.SetDiscoveryChan(cc)
+		md.SetDiscoveryChan(dc)
 	}
 
 	go func() {
@@ -207,6 +222,7 @@ func ModuleExecute(id, module, sock string) {
 			}
 			switch cmd.Command {
 			case lib.ServiceControl_STOP:
+				moduleShutdown(api, m, cmd.Deadline, dc)
 				os.Exit(0)
 				break
 			case lib.ServiceControl_UPDATE:
@@ -224,7 +240,17 @@ func ModuleExecute(id, module, sock string) {
 					api.Logf(ERROR, "unmarshal config failure: %v\n", e)
 					break
 				}
-				mc.UpdateConfig(p)
+				if e = mc.UpdateConfig(p); e != nil {
+					api.Logf(ERROR, "update config failure: %v\n", e)
+				}
+				break
+			case lib.ServiceControl_DISCOVER:
+				mr, ok := m.(lib.ModuleWithRediscover)
+				if !ok {
+					api.Logf(ERROR, "tried to re-discover on module that can't re-discover")
+					break
+				}
+				go mr.Rediscover(cmd.Nodes)
 				break
 			default:
 			}
@@ -232,3 +258,36 @@ func ModuleExecute(id, module, sock string) {
 	}()
 	mss.Entry()
 }
+
+// moduleShutdown gives a module that implements lib.ModuleWithShutdown until deadline to finish what it's doing,
+// then flushes its discoveries (dc) to the API.  Other modules that take mutations get until deadline to finish
+// the ones they have (see APIClient.Drain).  Anything else just exits.
+func moduleShutdown(api *APIClient, m lib.Module, deadline time.Time, dc chan<- lib.Event) {
+	if deadline.IsZero() {
+		deadline = time.Now().Add(DefaultStopTimeout)
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ms, ok := m.(lib.ModuleWithShutdown)
+	if !ok {
+		if _, ok := m.(lib.ModuleWithMutations); ok {
+			if e := api.Drain(ctx); e != nil {
+				api.Logf(ERROR, "module did not shut down cleanly: %v", e)
+			}
+		}
+		return
+	}
+	if e := ms.Shutdown(ctx); e != nil {
+		api.Logf(ERROR, "module did not shut down cleanly: %v", e)
+		return
+	}
+	if dc == nil {
+		return
+	}
+	close(dc) // Shutdown promises there won't be any more discoveries
+	select {
+	case <-api.dflush:
+	case <-ctx.Done():
+		api.Log(ERROR, "timed out flushing discoveries")
+	}
+}
@@ -13,10 +13,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
+	pb "github.com/hpc/kraken/core/proto"
 	"github.com/hpc/kraken/lib"
 )
 
@@ -26,20 +30,66 @@ import (
 
 var _ lib.ServiceManager = (*ServiceManager)(nil)
 
+// DefaultStopTimeout is how long services get to stop gracefully before they're killed, unless the ServiceManager is told otherwise
+const DefaultStopTimeout = 3 * time.Second
+
+// Services that exit when they shouldn't are restarted, waiting ServiceRestartBackoff, then twice as long each time, up to ServiceRestartMaxBackoff.
+// After ServiceRestartLimit restarts in a row, they're given up on (crashed) until they're started again.
+// A service that stays up for ServiceRestartReset is doing fine, and the count starts over.
+var (
+	ServiceRestartLimit      uint32 = 5
+	ServiceRestartBackoff           = time.Second
+	ServiceRestartMaxBackoff        = time.Minute
+	ServiceRestartReset             = time.Minute
+)
+
+// A serviceProc keeps track of the process of a service we started
+type serviceProc struct {
+	cmd      *exec.Cmd
+	exited   chan struct{} // closed when cmd exits
+	status   pb.ServiceStatus_Status
+	restarts uint32
+	lastExit string
+	started  time.Time
+	since    time.Time
+}
+
 type ServiceManager struct {
-	srv  map[string]lib.ServiceInstance
-	sock string
+	srv   map[string]lib.ServiceInstance
+	smtx  sync.RWMutex // guards srv; the API reads it (e.g. for /readyz) while the main loop syncs services
+	sock  string
+	stop  time.Duration
+	procs map[string]*serviceProc
+	mutex sync.Mutex
+	log   lib.Logger
 }
 
-func NewServiceManager(sock string) *ServiceManager {
+// NewServiceManager creates a ServiceManager; services get stop to exit gracefully before they're killed (0 for DefaultStopTimeout)
+func NewServiceManager(sock string, stop time.Duration) *ServiceManager {
+	if stop <= 0 {
+		stop = DefaultStopTimeout
+	}
 	sm := &ServiceManager{
-		srv:  make(map[string]lib.ServiceInstance),
-		sock: sock,
+		srv:   make(map[string]lib.ServiceInstance),
+		sock:  sock,
+		stop:  stop,
+		procs: make(map[string]*serviceProc),
 	}
 	return sm
 }
 
+// SetLogger sets where the ServiceManager logs about services exiting when they shouldn't
+func (sm *ServiceManager) SetLogger(l lib.Logger) { sm.log = l }
+
+func (sm *ServiceManager) logf(lv lib.LoggerLevel, f string, v ...interface{}) {
+	if sm.log != nil {
+		sm.log.Logf(lv, f, v...)
+	}
+}
+
 func (sm *ServiceManager) AddService(s lib.ServiceInstance) (e error) {
+	sm.smtx.Lock()
+	defer sm.smtx.Unlock()
 	if _, ok := sm.srv[s.ID()]; ok {
 		return fmt.Errorf("service by this ID already exists: %s", s.ID())
 	}
@@ -60,6 +110,8 @@ func (sm *ServiceManager) AddServiceByModule(id, module string, cfg *any.Any) (e
 }
 
 func (sm *ServiceManager) DelService(id string) (e error) {
+	sm.smtx.Lock()
+	defer sm.smtx.Unlock()
 	if s, ok := sm.srv[id]; ok {
 		if s.State() == lib.Service_RUN {
 			return fmt.Errorf("service is running, stop before deleting: %s", id)
@@ -71,6 +123,8 @@ func (sm *ServiceManager) DelService(id string) (e error) {
 
 func (sm *ServiceManager) Service(id string) (si lib.ServiceInstance) {
 	var ok bool
+	sm.smtx.RLock()
+	defer sm.smtx.RUnlock()
 	if si, ok = sm.srv[id]; ok {
 		return
 	}
@@ -78,7 +132,7 @@ func (sm *ServiceManager) Service(id string) (si lib.ServiceInstance) {
 }
 
 func (sm *ServiceManager) RunService(id string) (e error) {
-	if s, ok := sm.srv[id]; ok {
+	if s := sm.Service(id); s != nil {
 		if s.State() != lib.Service_RUN {
 			if e = sm.start(s); e == nil {
 				s.SetState(lib.Service_RUN)
@@ -91,9 +145,9 @@ func (sm *ServiceManager) RunService(id string) (e error) {
 }
 
 func (sm *ServiceManager) StopService(id string) (e error) {
-	if s, ok := sm.srv[id]; ok {
+	if s := sm.Service(id); s != nil {
 		if s.State() == lib.Service_RUN {
-			s.Stop()
+			sm.shutdown(s)
 			s.SetCmd(nil)
 			return
 		}
@@ -102,7 +156,39 @@ func (sm *ServiceManager) StopService(id string) (e error) {
 	return fmt.Errorf("cannot stop non-existent service: %s", id)
 }
 
+// Status reports how the process of each service is doing, sorted by ID
+func (sm *ServiceManager) Status() (r []*pb.ServiceStatus) {
+	srvs := sm.services()
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	for _, s := range srvs {
+		id := s.ID()
+		st := &pb.ServiceStatus{
+			Id:     id,
+			Module: s.Module(),
+			Status: pb.ServiceStatus_STOPPED,
+		}
+		if p, ok := sm.procs[id]; ok {
+			st.Status = p.status
+			if p.status == pb.ServiceStatus_STARTING && s.Ready() {
+				st.Status = pb.ServiceStatus_RUNNING
+			}
+			if p.status == pb.ServiceStatus_STARTING {
+				st.Pid = int32(p.cmd.Process.Pid)
+			}
+			st.Restarts = p.restarts
+			st.LastExit = p.lastExit
+			st.Since, _ = ptypes.TimestampProto(p.since)
+		}
+		r = append(r, st)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Id < r[j].Id })
+	return
+}
+
 func (sm *ServiceManager) GetServiceIDs() []string {
+	sm.smtx.RLock()
+	defer sm.smtx.RUnlock()
 	r := []string{}
 	for k := range sm.srv {
 		r = append(r, k)
@@ -110,22 +196,32 @@ func (sm *ServiceManager) GetServiceIDs() []string {
 	return r
 }
 
+// services gets a snapshot of our service instances, so they can be looked at without holding smtx
+func (sm *ServiceManager) services() []lib.ServiceInstance {
+	sm.smtx.RLock()
+	defer sm.smtx.RUnlock()
+	r := make([]lib.ServiceInstance, 0, len(sm.srv))
+	for _, s := range sm.srv {
+		r = append(r, s)
+	}
+	return r
+}
+
 func (sm *ServiceManager) syncState(s lib.ServiceInstance, state lib.ServiceState) lib.ServiceState {
 	r := lib.Service_UNKNOWN
 	if s.GetState() != state {
 		switch state {
 		case lib.Service_RUN:
+			if !sm.dependenciesReady(s) {
+				break
+			}
 			e := sm.RunService(s.ID())
 			if e != nil {
 			}
 			r = lib.Service_INIT
 			break
 		case lib.Service_STOP:
-			s.Stop() // we'll have 3 seconds to gracefully stop
-			go func() {
-				time.Sleep(3 * time.Second)
-				sm.kill(s)
-			}()
+			sm.shutdown(s)
 			r = lib.Service_STOP
 			break
 		default: // don't do anything
@@ -136,18 +232,20 @@ func (sm *ServiceManager) syncState(s lib.ServiceInstance, state lib.ServiceStat
 
 // SyncNode synchronizes service info between the state store of self and the service manager
 // It reports the current discoverable state of all services
+// Services are started after the services they depend on (see lib.ModuleWithDependencies) are ready,
+// and stopped before them.  A service that's waiting on its dependencies is started by a later sync.
 // NOTE: This is probablly very inefficient
 // the returned map is what we have "discovered"
 func (sm *ServiceManager) SyncNode(n lib.Node) map[string]lib.ServiceState {
 	r := make(map[string]lib.ServiceState)
 	sids := sm.GetServiceIDs()
-	for _, srv := range n.GetServices() {
+	sync := func(srv lib.ServiceInstance) bool {
 		for i, s := range sids {
 			if s == srv.ID() {
 				sids = append(sids[:i], sids[i+1:]...)
 			}
 		}
-		if s, ok := sm.srv[srv.ID()]; ok {
+		if s := sm.Service(srv.ID()); s != nil {
 			if !proto.Equal(s.Config(), srv.Config()) {
 				s.UpdateConfig(srv.Config())
 			}
@@ -157,25 +255,97 @@ func (sm *ServiceManager) SyncNode(n lib.Node) map[string]lib.ServiceState {
 		} else {
 			e := sm.AddServiceByModule(srv.ID(), srv.Module(), srv.Config())
 			if e != nil {
-				return r
+				return false
 			}
-			if ss := sm.syncState(sm.srv[srv.ID()], srv.State()); ss != lib.Service_UNKNOWN {
+			if ss := sm.syncState(sm.Service(srv.ID()), srv.State()); ss != lib.Service_UNKNOWN {
 				r[srv.ID()] = ss
 			}
 		}
+		return true
+	}
+	srvs := ServiceOrder(n.GetServices())
+	// stop in reverse order, so nothing loses a dependency while it's still running
+	for i := len(srvs) - 1; i >= 0; i-- {
+		if srvs[i].State() == lib.Service_STOP && !sync(srvs[i]) {
+			return r
+		}
+	}
+	for _, srv := range srvs {
+		if srv.State() != lib.Service_STOP && !sync(srv) {
+			return r
+		}
 	}
 	// sids is now a list of services we're not supposed to have anymore
 	for _, id := range sids {
-		sm.srv[id].Stop()
-		go func() {
-			time.Sleep(3 * time.Second)
-			sm.kill(sm.srv[id])
-		}()
+		if s := sm.Service(id); s != nil {
+			sm.shutdown(s)
+		}
 		sm.DelService(id)
 	}
 	return r
 }
 
+// ServiceOrder sorts services so that each comes after the services it depends on (see lib.ModuleWithDependencies).
+// Otherwise, the order is kept.  Services in a dependency loop are left at the end.
+func ServiceOrder(srvs []lib.ServiceInstance) (r []lib.ServiceInstance) {
+	done := make(map[string]bool) // by module
+	for len(srvs) > 0 {
+		rest := []lib.ServiceInstance{}
+		for _, s := range srvs {
+			waiting := false
+			for _, d := range moduleDependencies(s.Module()) {
+				if d == s.Module() || done[d] { // a module can't wait on itself
+					continue
+				}
+				for _, o := range srvs {
+					if o.Module() == d {
+						waiting = true
+					}
+				}
+			}
+			if waiting {
+				rest = append(rest, s)
+			} else {
+				r = append(r, s)
+			}
+		}
+		if len(rest) == len(srvs) {
+			// a loop; we can't do any better
+			return append(r, rest...)
+		}
+		for _, s := range r {
+			done[s.Module()] = true
+		}
+		srvs = rest
+	}
+	return
+}
+
+// moduleDependencies gets the modules a module depends on, if any
+func moduleDependencies(module string) []string {
+	if m, ok := Registry.Modules[module].(lib.ModuleWithDependencies); ok {
+		return m.Dependencies()
+	}
+	return nil
+}
+
+// dependenciesReady reports whether every module s depends on has a ready service instance
+func (sm *ServiceManager) dependenciesReady(s lib.ServiceInstance) bool {
+	for _, d := range moduleDependencies(s.Module()) {
+		ready := false
+		for _, o := range sm.services() {
+			if o.Module() == d && o.Ready() {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+	return true
+}
+
 func (sm *ServiceManager) start(s lib.ServiceInstance) (e error) {
 	if s.State() == lib.Service_RUN {
 		return fmt.Errorf("service is already running")
@@ -184,6 +354,19 @@ func (sm *ServiceManager) start(s lib.ServiceInstance) (e error) {
 		return e
 	}
 	// TODO: we should probably do more sanity checks here...
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	if e = sm.exec(s, 0); e != nil {
+		return
+	}
+	s.SetState(lib.Service_RUN)
+	return
+}
+
+// exec starts the process of s, and watches it; restarts is how many times in a row it's been restarted
+// sm.mutex must be held
+func (sm *ServiceManager) exec(s lib.ServiceInstance, restarts uint32) (e error) {
+	s.SetCtl(nil) // we aren't ready until we call in again
 	cmd := exec.Command(s.Exe())
 	cmd.Args = []string{"[kraken:" + s.ID() + "]"}
 	cmd.Stdin = os.Stdin
@@ -193,24 +376,105 @@ func (sm *ServiceManager) start(s lib.ServiceInstance) (e error) {
 		"KRAKEN_SOCK="+sm.sock,
 		"KRAKEN_MODULE="+s.Module(),
 		"KRAKEN_ID="+s.ID())
-	e = cmd.Start()
+	if e = cmd.Start(); e != nil {
+		return
+	}
 	s.SetCmd(cmd)
-	s.SetState(lib.Service_RUN)
+	now := time.Now()
+	p := &serviceProc{
+		cmd:      cmd,
+		exited:   make(chan struct{}),
+		status:   pb.ServiceStatus_STARTING,
+		restarts: restarts,
+		started:  now,
+		since:    now,
+	}
+	if old, ok := sm.procs[s.ID()]; ok {
+		p.lastExit = old.lastExit
+	}
+	sm.procs[s.ID()] = p
+	go sm.watch(s, p)
 	return
 }
 
-func (sm *ServiceManager) kill(s lib.ServiceInstance) (e error) {
+// watch waits for the process of s to exit.  If it wasn't asked to stop, it's restarted after a while, unless it's been restarted too many times in a row.
+func (sm *ServiceManager) watch(s lib.ServiceInstance, p *serviceProc) {
+	err := p.cmd.Wait()
+	close(p.exited)
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	p.since = time.Now()
+	if s.State() != lib.Service_RUN || sm.procs[s.ID()] != p {
+		// we asked for this
+		p.status = pb.ServiceStatus_STOPPED
+		return
+	}
+	p.lastExit = "exit status 0"
+	if err != nil {
+		p.lastExit = err.Error()
+	}
+	if p.since.Sub(p.started) >= ServiceRestartReset {
+		p.restarts = 0
+	}
+	if p.restarts >= ServiceRestartLimit {
+		sm.logf(lib.LLERROR, "service %s exited (%s), and has been restarted %d times; giving up on it", s.ID(), p.lastExit, p.restarts)
+		p.status = pb.ServiceStatus_CRASHED
+		s.SetCmd(nil)
+		s.SetCtl(nil)
+		s.SetState(lib.Service_ERROR)
+		return
+	}
+	wait := ServiceRestartBackoff << p.restarts
+	if wait > ServiceRestartMaxBackoff || wait <= 0 {
+		wait = ServiceRestartMaxBackoff
+	}
+	sm.logf(lib.LLERROR, "service %s exited (%s), restarting it in %v", s.ID(), p.lastExit, wait)
+	p.status = pb.ServiceStatus_RESTARTING
+	s.SetCtl(nil)
+	time.AfterFunc(wait, func() {
+		sm.mutex.Lock()
+		defer sm.mutex.Unlock()
+		if s.State() != lib.Service_RUN || sm.procs[s.ID()] != p {
+			// it was stopped (or started) while we were waiting
+			p.status = pb.ServiceStatus_STOPPED
+			return
+		}
+		if e := sm.exec(s, p.restarts+1); e != nil {
+			sm.logf(lib.LLERROR, "failed to restart service %s: %v", s.ID(), e)
+			p.status = pb.ServiceStatus_CRASHED
+			p.lastExit = e.Error()
+			s.SetCmd(nil)
+			s.SetState(lib.Service_ERROR)
+		}
+	})
+}
+
+// shutdown asks s to stop, and waits for it to exit; if it hasn't by the deadline, it's killed
+func (sm *ServiceManager) shutdown(s lib.ServiceInstance) {
+	s.Stop(time.Now().Add(sm.stop))
 	cmd := s.Cmd()
 	if cmd == nil || cmd.Process == nil {
 		return
 	}
-	/*
-		if s.State() != lib.Service_RUN {
-			return fmt.Errorf("cannot kill a process that isn't running")
-		}
-	*/
-	s.SetState(lib.Service_STOP)
-	cmd.Process.Kill()
-	cmd.Process.Wait()
-	return cmd.Process.Release()
+	sm.mutex.Lock()
+	p, ok := sm.procs[s.ID()]
+	sm.mutex.Unlock()
+	var exited chan struct{}
+	if ok && p.cmd == cmd {
+		// we're already waiting for it
+		exited = p.exited
+	} else {
+		exited = make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(exited)
+		}()
+	}
+	select {
+	case <-exited:
+	case <-time.After(sm.stop):
+		cmd.Process.Kill()
+		<-exited
+	}
+	cmd.Process.Release()
 }
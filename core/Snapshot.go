@@ -0,0 +1,51 @@
+/* Snapshot.go: snapshots save the whole configuration state to a file so that it can be restored later
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// WriteSnapshot writes nodes as a snapshot.
+// Snapshots are NodeList JSON, the same as the ReST API uses for /cfg/nodes, so extensions and
+// service configs are written by type URL and can be read back by anything that registers them.
+func WriteSnapshot(w io.Writer, ns []lib.Node) (e error) {
+	var nl pb.NodeList
+	for _, n := range ns {
+		nl.Nodes = append(nl.Nodes, n.Message().(*pb.Node))
+	}
+	b, e := MarshalJSON(&nl)
+	if e != nil {
+		return fmt.Errorf("could not marshal snapshot: %v", e)
+	}
+	_, e = w.Write(append(b, '\n'))
+	return
+}
+
+// ReadSnapshot reads the nodes in a snapshot written by WriteSnapshot
+func ReadSnapshot(r io.Reader) (ns []lib.Node, e error) {
+	b, e := ioutil.ReadAll(r)
+	if e != nil {
+		return
+	}
+	var nl pb.NodeList
+	if e = UnmarshalJSON(b, &nl); e != nil {
+		return nil, fmt.Errorf("could not unmarshal snapshot: %v", e)
+	}
+	for _, m := range nl.Nodes {
+		ns = append(ns, NewNodeFromMessage(m))
+	}
+	return
+}
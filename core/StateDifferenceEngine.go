@@ -14,7 +14,10 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sync"
+	"time"
 
+	pb "github.com/hpc/kraken/core/proto"
 	"github.com/hpc/kraken/lib"
 )
 
@@ -48,6 +51,9 @@ type StateChangeEvent struct {
 	Type  StateChangeType
 	URL   string
 	Value reflect.Value
+	// Txn is set if the change was made by a transaction, and lists every URL the transaction changed.
+	// A transaction's changes are emitted together, after all of them have been made.
+	Txn []string
 }
 
 func (sce *StateChangeEvent) String() string {
@@ -85,6 +91,26 @@ type StateDifferenceEngine struct {
 	cfg   *State
 	qc    chan lib.Query
 	schan chan<- lib.EventListener
+	store StateStore // nil if state isn't persisted
+	wchan chan StateStoreEvent
+	self  lib.NodeID
+	// dtimes is when each discovered value (by fully qualified url) was observed
+	dtimes map[string]time.Time
+	ttls   map[string]time.Duration // by url; see ContextSDE.StaleTTL
+	frozen func() bool              // nil if we're never frozen; see SetFrozen
+	// writes to the store are made by persister, so a slow (or unreachable) store can't hold up queries
+	pmutex  sync.Mutex
+	pending map[storeKey]lib.Node // the latest version of each node to write; nil to delete it
+	pwake   chan struct{}
+	// HA head nodes share our ID, but each has its own address (at addrURL), which isn't shared through the store
+	addrURL string // empty unless we're HA
+	addr    reflect.Value
+}
+
+// storeKey is a node in one of the states in a StateStore
+type storeKey struct {
+	dsc bool
+	id  string
 }
 
 // NewStateDifferenceEngine initializes a new StateDifferenceEngine object given a Context
@@ -93,13 +119,41 @@ func NewStateDifferenceEngine(ctx Context, qc chan lib.Query) *StateDifferenceEn
 	n.dsc = NewState()
 	n.cfg = NewState()
 	n.qc = qc
+	n.dtimes = make(map[string]time.Time)
+	n.ttls = ctx.SDE.StaleTTL
 	n.em = NewEventEmitter(lib.Event_STATE_CHANGE)
 	n.schan = ctx.SubChan
+	n.self = ctx.Self
 	n.log = &ctx.Logger
 	n.log.SetModule("StateDifferenceEngine")
+	n.store = ctx.Store.Store
+	if n.store != nil {
+		n.pending = make(map[storeKey]lib.Node)
+		n.pwake = make(chan struct{}, 1)
+		go n.persister()
+		n.load()
+		if ws, ok := n.store.(WatchableStateStore); ok {
+			// someone else can change our state; keep up with them
+			n.wchan = make(chan StateStoreEvent)
+			if e := ws.Watch(n.wchan); e != nil {
+				n.Logf(ERROR, "failed to watch the state store: %v", e)
+			}
+		}
+	}
 	// every engine should know a little something about itself
+	ip := reflect.ValueOf([]byte(net.ParseIP(ctx.SSE.Addr).To4()))
+	if ctx.Store.HA {
+		n.addrURL, n.addr = ctx.SSE.AddrURL, ip
+	}
+	if _, e := n.cfg.Read(ctx.Self); e == nil {
+		// we were restored from the store, but our IP may have changed
+		if _, e := n.SetValue(lib.NodeURLJoin(ctx.Self.String(), ctx.SSE.AddrURL), ip); e != nil {
+			n.Logf(CRITICAL, "failed to set our own IP: %v\n", e)
+		}
+		return n
+	}
 	me := NewNodeWithID(ctx.Self.String())
-	if _, e := me.SetValue(ctx.SSE.AddrURL, reflect.ValueOf([]byte(net.ParseIP(ctx.SSE.Addr).To4()))); e != nil {
+	if _, e := me.SetValue(ctx.SSE.AddrURL, ip); e != nil {
 		n.Logf(CRITICAL, "failed to set our own IP: %v\n", e)
 	}
 	n.Create(me)
@@ -118,6 +172,8 @@ func (n *StateDifferenceEngine) Create(m lib.Node) (r lib.Node, e error) {
 		r = nil
 		return
 	}
+	n.persist(false, m.ID())
+	n.persist(true, m.ID())
 	go n.EmitOne(NewStateChangeEvent(StateChange_CREATE, lib.NodeURLJoin(m.ID().String(), ""), reflect.ValueOf(r)))
 	return
 }
@@ -152,6 +208,8 @@ func (n *StateDifferenceEngine) Delete(m lib.Node) (r lib.Node, e error) {
 func (n *StateDifferenceEngine) DeleteByID(nid lib.NodeID) (r lib.Node, e error) {
 	n.dsc.DeleteByID(nid)
 	r, e = n.cfg.DeleteByID(nid)
+	n.unpersist(nid)
+	n.forget(nid)
 	go n.EmitOne(NewStateChangeEvent(StateChange_DELETE, lib.NodeURLJoin(nid.String(), ""), reflect.ValueOf(r)))
 	return
 }
@@ -170,7 +228,7 @@ func (n *StateDifferenceEngine) GetValueDsc(url string) (r reflect.Value, e erro
 func (n *StateDifferenceEngine) SetValue(url string, v reflect.Value) (r reflect.Value, e error) {
 	var cur reflect.Value
 	cur, e = n.cfg.GetValue(url)
-	if e == nil && cur.Interface() == v.Interface() { // nothing new to set
+	if e == nil && reflect.DeepEqual(cur.Interface(), v.Interface()) { // nothing new to set
 		n.Logf(DDEBUG, "SetValue called, but it's not a change: %s", url)
 		r = v
 		return
@@ -178,6 +236,8 @@ func (n *StateDifferenceEngine) SetValue(url string, v reflect.Value) (r reflect
 	r, e = n.cfg.SetValue(url, v)
 	if e != nil {
 		n.Logf(ERROR, "failed to set value (cfg): %v", e)
+	} else {
+		n.persist(false, NewNodeIDFromURL(url))
 	}
 	go n.EmitOne(NewStateChangeEvent(StateChange_CFG_UPDATE, url, reflect.ValueOf(r)))
 	return
@@ -187,7 +247,7 @@ func (n *StateDifferenceEngine) SetValue(url string, v reflect.Value) (r reflect
 func (n *StateDifferenceEngine) SetValueDsc(url string, v reflect.Value) (r reflect.Value, e error) {
 	var cur reflect.Value
 	cur, e = n.dsc.GetValue(url)
-	if e == nil && cur.Interface() == v.Interface() { // nothing new to set
+	if e == nil && reflect.DeepEqual(cur.Interface(), v.Interface()) { // nothing new to set
 		n.Logf(DDEBUG, "SetValueDsc called, but it's not a change: %s", url)
 		r = v
 		return
@@ -195,6 +255,8 @@ func (n *StateDifferenceEngine) SetValueDsc(url string, v reflect.Value) (r refl
 	r, e = n.dsc.SetValue(url, v)
 	if e != nil {
 		n.Logf(ERROR, "failed to set value (dsc): %v", e)
+	} else {
+		n.persist(true, NewNodeIDFromURL(url))
 	}
 	go n.EmitOne(NewStateChangeEvent(StateChange_UPDATE, url, reflect.ValueOf(r)))
 	return
@@ -219,6 +281,10 @@ func (n *StateDifferenceEngine) BulkCreate(ms []lib.Node) (r []lib.Node, e error
 		e = fmt.Errorf("failed to add nodes to both dsc & cfg, rolling back: %s, %s", e.Error(), de.Error())
 		return
 	}
+	for _, v := range r {
+		n.persist(false, v.ID())
+		n.persist(true, v.ID())
+	}
 	go n.Emit(evs)
 	return
 }
@@ -248,9 +314,13 @@ func (n *StateDifferenceEngine) BulkDelete(ms []lib.Node) (r []lib.Node, e error
 	r, e = n.cfg.BulkDelete(ms)
 	_, de := n.dsc.BulkDelete(ms)
 	var evs []lib.Event
+	var ids []lib.NodeID
 	for _, v := range r {
+		n.unpersist(v.ID())
+		ids = append(ids, v.ID())
 		evs = append(evs, NewStateChangeEvent(StateChange_DELETE, lib.NodeURLJoin(v.ID().String(), ""), reflect.ValueOf(v)))
 	}
+	n.forget(ids...)
 	go n.Emit(evs)
 	if de != nil && e == nil {
 		e = de
@@ -263,9 +333,13 @@ func (n *StateDifferenceEngine) BulkDeleteByID(nids []lib.NodeID) (r []lib.Node,
 	r, e = n.cfg.BulkDeleteByID(nids)
 	_, de := n.dsc.BulkDeleteByID(nids)
 	var evs []lib.Event
+	var ids []lib.NodeID
 	for _, v := range r {
+		n.unpersist(v.ID())
+		ids = append(ids, v.ID())
 		evs = append(evs, NewStateChangeEvent(StateChange_DELETE, lib.NodeURLJoin(v.ID().String(), ""), reflect.ValueOf(v)))
 	}
+	n.forget(ids...)
 	go n.Emit(evs)
 	if de != nil && e == nil { // if dr errors, but e does not, pass the error on
 		e = de
@@ -286,11 +360,15 @@ func (n *StateDifferenceEngine) ReadAllDsc() (r []lib.Node, e error) {
 // DeleteAll deletes all nodes in the engine, careful!
 func (n *StateDifferenceEngine) DeleteAll() (r []lib.Node, e error) {
 	r, e = n.cfg.DeleteAll()
-	_, de := n.cfg.DeleteAll()
+	_, de := n.dsc.DeleteAll()
 	var evs []lib.Event
+	var ids []lib.NodeID
 	for _, v := range r {
+		n.unpersist(v.ID())
+		ids = append(ids, v.ID())
 		evs = append(evs, NewStateChangeEvent(StateChange_DELETE, lib.NodeURLJoin(v.ID().String(), ""), reflect.ValueOf(v)))
 	}
+	n.forget(ids...)
 	go n.Emit(evs)
 	if de != nil && e == nil { // if dr errors, but e does not, pass the error on
 		e = de
@@ -298,6 +376,226 @@ func (n *StateDifferenceEngine) DeleteAll() (r []lib.Node, e error) {
 	return
 }
 
+// ReadGroup returns a slice of all nodes in Cfg that are in group
+func (n *StateDifferenceEngine) ReadGroup(group string) (r []lib.Node, e error) {
+	return n.readGroupByType(false, group)
+}
+
+// ReadGroupDsc returns a slice of all nodes in Dsc that are in group
+// Group membership is configuration, so this uses the groups in Cfg.
+func (n *StateDifferenceEngine) ReadGroupDsc(group string) (r []lib.Node, e error) {
+	return n.readGroupByType(true, group)
+}
+
+// Transact sets several (node qualified) URLs in Cfg at once, with all-or-nothing semantics:
+// if any value can't be set, nothing is changed.  The changes are emitted as one batch of events.
+func (n *StateDifferenceEngine) Transact(vs map[string]reflect.Value) (e error) {
+	return n.transactByType(false, vs)
+}
+
+// TransactDsc sets several (node qualified) URLs in Dsc at once, see Transact
+func (n *StateDifferenceEngine) TransactDsc(vs map[string]reflect.Value) (e error) {
+	return n.transactByType(true, vs)
+}
+
+// Select returns a slice of the nodes in Cfg that match a selector expression (see NodeSelector)
+func (n *StateDifferenceEngine) Select(expr string) (r []lib.Node, e error) {
+	return n.selectByType(false, expr)
+}
+
+// SelectDsc returns a slice of the nodes in Dsc that match a selector expression (see NodeSelector)
+func (n *StateDifferenceEngine) SelectDsc(expr string) (r []lib.Node, e error) {
+	return n.selectByType(true, expr)
+}
+
+// UpdateGroup sets the values that m sets on every node (in Cfg) in group.
+// m is a template: only values that differ from an empty node are used, so e.g. a node with only
+// PhysState set will power a whole group on or off.  m's ID and groups are ignored.
+func (n *StateDifferenceEngine) UpdateGroup(group string, m lib.Node) (r []lib.Node, e error) {
+	tmpl := m.(*Node)
+	empty := NewNodeFromMessage(&pb.Node{Id: tmpl.ID().Binary()})
+	d, e := empty.Diff(tmpl, "")
+	if e != nil {
+		return
+	}
+	var urls []string
+	for _, u := range d {
+		if root, _ := lib.URLShift(u); root == "Id" || root == "Groups" {
+			continue
+		}
+		urls = append(urls, u)
+	}
+	ms, e := n.cfg.ReadAll()
+	if e != nil {
+		return
+	}
+	for _, old := range ms {
+		if !old.InGroup(group) {
+			continue
+		}
+		nn := NewNodeFromBinary(old.Binary())
+		for _, u := range urls {
+			v, _ := tmpl.GetValue(u)
+			if _, e = nn.SetValue(u, v); e != nil {
+				return r, fmt.Errorf("cannot update node %s: %v", nn.ID().String(), e)
+			}
+		}
+		if _, e = n.Update(nn); e != nil {
+			return
+		}
+		r = append(r, nn)
+	}
+	return
+}
+
+// Rollups summarizes the discovered state of nodes at location, and below it (see Rollups)
+func (n *StateDifferenceEngine) Rollups(location string) (r []*pb.Rollup, e error) {
+	cfg, e := n.cfg.ReadAll()
+	if e != nil {
+		return
+	}
+	dsc, e := n.dsc.ReadAll()
+	if e != nil {
+		return
+	}
+	return Rollups(cfg, dsc, location), nil
+}
+
+// SetFrozen tells the engine how to check if we're frozen, e.g. as an HA standby (see StateMutationEngine.Frozen)
+// Stale values are only expired while we aren't.
+func (n *StateDifferenceEngine) SetFrozen(frozen func() bool) {
+	n.frozen = frozen
+}
+
+// ExpireStale reverts discovered values that haven't been discovered again within their TTL
+// (see ContextSDE.StaleTTL) to unknown, i.e. their zero value.  The change gets them rediscovered.
+// Nothing expires while we're frozen; only the leader should act on stale values.
+// It returns the (fully qualified) URLs that expired.
+func (n *StateDifferenceEngine) ExpireStale(now time.Time) (r []string) {
+	if n.frozen != nil && n.frozen() {
+		return
+	}
+	for u, t := range n.dtimes {
+		_, url := lib.NodeURLSplit(u)
+		ttl, ok := n.ttls[url]
+		if !ok || now.Sub(t) < ttl {
+			continue
+		}
+		delete(n.dtimes, u)
+		cur, e := n.dsc.GetValue(u)
+		if e != nil {
+			continue // the node is gone
+		}
+		zero := reflect.Zero(cur.Type())
+		if reflect.DeepEqual(cur.Interface(), zero.Interface()) {
+			continue
+		}
+		n.Logf(INFO, "%s hasn't been discovered for %s, it's now unknown", u, ttl.String())
+		n.SetValueDsc(u, zero)
+		r = append(r, u)
+	}
+	return
+}
+
+// Discover sets a discovered value
+// Discoveries that were observed before the value we already have (e.g. a slow poll) are out of date, and are rejected.
+// A discovery without a time, or with a time ahead of ours (e.g. a module with a fast clock), is taken to be observed now.
+func (n *StateDifferenceEngine) Discover(d *DiscoveryEvent) (e error) {
+	_, url := lib.NodeURLSplit(d.URL)
+	val, ok := Registry.Discoverables[d.Module][url][d.ValueID]
+	if !ok {
+		return fmt.Errorf("got discover, but can't lookup value: mod (%s) url (%s) id(%s)", d.Module, url, d.ValueID)
+	}
+	// otherwise a time in the future would hold off every discovery until then
+	t, now := d.Time, time.Now()
+	if t.IsZero() || t.After(now) {
+		t = now
+	}
+	if last, ok := n.dtimes[d.URL]; ok && t.Before(last) {
+		return fmt.Errorf("ignoring out of order discovery: %s was observed at %s, but we have a value from %s", d.String(), t.Format(time.RFC3339Nano), last.Format(time.RFC3339Nano))
+	}
+	vset, e := n.SetValueDsc(d.URL, val)
+	if e != nil {
+		return fmt.Errorf("failed to set discovered value: url (%s) val (%v)", d.URL, val.Interface())
+	}
+	n.dtimes[d.URL] = t
+	n.Logf(DEBUG, "discovered %s is %v\n", d.URL, vset.Interface())
+	return
+}
+
+// Restore replaces the whole configuration state with ms, e.g. from a snapshot.
+// Nodes in ms are created or updated, and nodes that aren't in ms are deleted.
+// Our own node is left alone, so a restore can't cut us off from the cluster.
+// Everything is checked before anything is changed, so on error nothing has been restored.
+func (n *StateDifferenceEngine) Restore(ms []lib.Node) (r []lib.Node, e error) {
+	keep := make(map[string]bool)
+	var creates, updates []lib.Node
+	var diff []string
+	for _, m := range ms {
+		id := m.ID().String()
+		if m.ID().Nil() {
+			return nil, fmt.Errorf("cannot restore a node without an ID")
+		}
+		if keep[id] {
+			return nil, fmt.Errorf("cannot restore node %s: it is duplicated", id)
+		}
+		keep[id] = true
+		if m.ID().Equal(n.self) {
+			continue
+		}
+		old, err := n.cfg.Read(m.ID())
+		if err != nil {
+			creates = append(creates, m)
+			continue
+		}
+		d, err := old.(*Node).Diff(m.(*Node), lib.NodeURLJoin(id, ""))
+		if err != nil {
+			return nil, fmt.Errorf("cannot restore node %s: %v", id, err)
+		}
+		if len(d) > 0 {
+			updates = append(updates, m)
+			diff = append(diff, d...)
+		}
+	}
+	all, e := n.cfg.ReadAll()
+	if e != nil {
+		return
+	}
+	var deletes []lib.NodeID
+	for _, o := range all {
+		if !keep[o.ID().String()] && !o.ID().Equal(n.self) {
+			deletes = append(deletes, o.ID())
+		}
+	}
+
+	// the in-memory states can't fail us now, so we don't have to worry about partial restores
+	var evs []lib.Event
+	for _, nid := range deletes {
+		old, _ := n.cfg.DeleteByID(nid)
+		n.dsc.DeleteByID(nid)
+		n.unpersist(nid)
+		evs = append(evs, NewStateChangeEvent(StateChange_DELETE, lib.NodeURLJoin(nid.String(), ""), reflect.ValueOf(old)))
+	}
+	n.forget(deletes...)
+	for _, m := range creates {
+		n.cfg.Create(m)
+		n.dsc.Create(n.makeDscNode(m.(*Node)))
+		n.persist(false, m.ID())
+		n.persist(true, m.ID())
+		evs = append(evs, NewStateChangeEvent(StateChange_CREATE, lib.NodeURLJoin(m.ID().String(), ""), reflect.ValueOf(m)))
+	}
+	for _, m := range updates {
+		n.cfg.Update(m)
+		n.persist(false, m.ID())
+	}
+	for _, u := range diff {
+		evs = append(evs, NewStateChangeEvent(StateChange_CFG_UPDATE, u, reflect.Value{}))
+	}
+	n.Logf(INFO, "restored configuration state: %d created, %d updated, %d deleted", len(creates), len(updates), len(deletes))
+	go n.Emit(evs)
+	return n.cfg.ReadAll()
+}
+
 // QueryChan returns a chanel that Queries can be sent on
 func (n *StateDifferenceEngine) QueryChan() chan<- lib.Query {
 	return n.qc
@@ -316,6 +614,23 @@ func (n *StateDifferenceEngine) Run() {
 	)
 	// subscribe our discovery listener
 	n.schan <- list
+
+	var stale <-chan time.Time // nil never fires
+	if len(n.ttls) > 0 {
+		// values we had before we started are good for one TTL
+		n.seedStale(time.Now())
+		check := time.Duration(0)
+		for _, ttl := range n.ttls {
+			if check == 0 || ttl/2 < check {
+				check = ttl / 2
+			}
+		}
+		if check < time.Second {
+			check = time.Second
+		}
+		stale = time.NewTicker(check).C
+	}
+
 	for {
 		select {
 		case q := <-n.qc:
@@ -421,24 +736,108 @@ func (n *StateDifferenceEngine) Run() {
 				}
 				go n.sendQueryResponse(NewQueryResponse(vs, e), q.ResponseChan())
 				break
+			case lib.Query_READGROUP:
+				var v []lib.Node
+				var e error
+				switch q.State() {
+				case lib.QueryState_CONFIG:
+					v, e = n.ReadGroup(q.URL())
+					break
+				case lib.QueryState_DISCOVER:
+					v, e = n.ReadGroupDsc(q.URL())
+					break
+				default:
+					e = fmt.Errorf("unknown state for Query_READGROUP")
+				}
+				var vs []reflect.Value
+				for _, i := range v {
+					vs = append(vs, reflect.ValueOf(i))
+				}
+				go n.sendQueryResponse(NewQueryResponse(vs, e), q.ResponseChan())
+				break
+			case lib.Query_TRANSACT:
+				var e error
+				if len(q.Value()) < 1 || !q.Value()[0].IsValid() {
+					e = fmt.Errorf("malformed values in transaction query")
+				} else {
+					vs := q.Value()[0].Interface().(map[string]reflect.Value)
+					switch q.State() {
+					case lib.QueryState_CONFIG:
+						e = n.Transact(vs)
+						break
+					case lib.QueryState_DISCOVER:
+						e = n.TransactDsc(vs)
+						break
+					default:
+						e = fmt.Errorf("unknown state for Query_TRANSACT")
+					}
+				}
+				go n.sendQueryResponse(NewQueryResponse([]reflect.Value{}, e), q.ResponseChan())
+				break
+			case lib.Query_SELECT:
+				var v []lib.Node
+				var e error
+				switch q.State() {
+				case lib.QueryState_CONFIG:
+					v, e = n.Select(q.URL())
+					break
+				case lib.QueryState_DISCOVER:
+					v, e = n.SelectDsc(q.URL())
+					break
+				default:
+					e = fmt.Errorf("unknown state for Query_SELECT")
+				}
+				var vs []reflect.Value
+				for _, i := range v {
+					vs = append(vs, reflect.ValueOf(i))
+				}
+				go n.sendQueryResponse(NewQueryResponse(vs, e), q.ResponseChan())
+				break
+			case lib.Query_ROLLUPS:
+				v, e := n.Rollups(q.URL())
+				go n.sendQueryResponse(NewQueryResponse(
+					[]reflect.Value{reflect.ValueOf(v)}, e), q.ResponseChan())
+				break
+			case lib.Query_UPDATEGROUP:
+				if len(q.Value()) < 1 || !q.Value()[0].IsValid() {
+					go n.sendQueryResponse(NewQueryResponse([]reflect.Value{}, fmt.Errorf("malformed node in update group query")), q.ResponseChan())
+					break
+				}
+				v, e := n.UpdateGroup(q.URL(), q.Value()[0].Interface().(lib.Node))
+				var vs []reflect.Value
+				for _, i := range v {
+					vs = append(vs, reflect.ValueOf(i))
+				}
+				go n.sendQueryResponse(NewQueryResponse(vs, e), q.ResponseChan())
+				break
+			case lib.Query_RESTORE:
+				var ms []lib.Node
+				for _, v := range q.Value() {
+					ms = append(ms, v.Interface().(lib.Node))
+				}
+				v, e := n.Restore(ms)
+				var vs []reflect.Value
+				for _, i := range v {
+					vs = append(vs, reflect.ValueOf(i))
+				}
+				go n.sendQueryResponse(NewQueryResponse(vs, e), q.ResponseChan())
+				break
 			default:
 				n.Logf(NOTICE, "unsupported query type: %d", q.Type())
 			}
 			break
+		case se := <-n.wchan: // someone else changed a shared state store
+			n.applyStoreEvent(se)
+			break
 		case v := <-dchan: // got a discovery
 			data := v.Data().(*DiscoveryEvent)
-			_, url := lib.NodeURLSplit(data.URL)
-			val, ok := Registry.Discoverables[data.Module][url][data.ValueID]
-			n.Logf(DDEBUG, "processing discovery: mod (%s) url (%s) id(%s)", data.Module, url, data.ValueID)
-			if !ok {
-				n.Logf(ERROR, "got discover, but can't lookup value: mod (%s) url (%s) id(%s)", data.Module, url, data.ValueID)
-				break
-			}
-			vset, e := n.SetValueDsc(data.URL, val)
-			if e != nil {
-				n.Logf(ERROR, "failed to set discovered value: url (%s) val (%v)", data.URL, val.Interface())
+			n.Logf(DDEBUG, "processing discovery: %s", data.String())
+			if e := n.Discover(data); e != nil {
+				n.Log(ERROR, e.Error())
 			}
-			n.Logf(DEBUG, "discovered %s is %v\n", data.URL, vset.Interface())
+			break
+		case now := <-stale:
+			n.ExpireStale(now)
 			break
 		}
 	}
@@ -448,6 +847,35 @@ func (n *StateDifferenceEngine) Run() {
 // Unexported methods /
 //////////////////////
 
+// seedStale starts the clock on every discovered value we have for a URL with a TTL
+func (n *StateDifferenceEngine) seedStale(now time.Time) {
+	ns, _ := n.dsc.ReadAll()
+	for _, node := range ns {
+		for url := range n.ttls {
+			u := lib.NodeURLJoin(node.ID().String(), url)
+			if _, ok := n.dtimes[u]; !ok {
+				n.dtimes[u] = now
+			}
+		}
+	}
+}
+
+// forget drops the discovery times of deleted nodes
+func (n *StateDifferenceEngine) forget(nids ...lib.NodeID) {
+	if len(nids) == 0 {
+		return
+	}
+	gone := make(map[string]bool)
+	for _, nid := range nids {
+		gone[nid.String()] = true
+	}
+	for u := range n.dtimes {
+		if id, _ := lib.NodeURLSplit(u); gone[id] {
+			delete(n.dtimes, u)
+		}
+	}
+}
+
 func (n *StateDifferenceEngine) makeDscNode(m *Node) (r *Node) {
 	r = NewNodeWithID(m.ID().String())
 	return r
@@ -472,6 +900,9 @@ func (n *StateDifferenceEngine) updateByType(dsc bool, m lib.Node) (r lib.Node,
 	} else {
 		r, e = n.cfg.Update(m)
 	}
+	if e == nil {
+		n.persist(dsc, m.ID())
+	}
 	if e == nil && len(diff) > 0 {
 		var evs []lib.Event
 		utype := StateChange_UPDATE
@@ -487,6 +918,109 @@ func (n *StateDifferenceEngine) updateByType(dsc bool, m lib.Node) (r lib.Node,
 	return
 }
 
+func (n *StateDifferenceEngine) transactByType(dsc bool, vs map[string]reflect.Value) (e error) {
+	state := n.cfg
+	utype := StateChange_CFG_UPDATE
+	if dsc {
+		state = n.dsc
+		utype = StateChange_UPDATE
+	}
+	// make all of the changes to copies first
+	ns := make(map[string]*Node)
+	olds := make(map[string]lib.Node) // to roll back to
+	var txn []string
+	for u, v := range vs {
+		node, url := lib.NodeURLSplit(u)
+		nid := NewNodeID(node)
+		nn, ok := ns[nid.String()]
+		if !ok {
+			old, err := state.Read(nid)
+			if err != nil {
+				return fmt.Errorf("transaction failed: %v", err)
+			}
+			nn = NewNodeFromBinary(old.Binary())
+			ns[nid.String()] = nn
+			olds[nid.String()] = NewNodeFromBinary(old.Binary())
+		}
+		cur, err := nn.GetValue(url)
+		if err == nil && cur.IsValid() && v.IsValid() && cur.Type() == v.Type() && reflect.DeepEqual(cur.Interface(), v.Interface()) {
+			continue // not a change
+		}
+		if _, err := nn.SetValue(url, v); err != nil {
+			return fmt.Errorf("transaction failed to set %s: %v", u, err)
+		}
+		txn = append(txn, lib.NodeURLJoin(nn.ID().String(), url))
+	}
+	if len(txn) == 0 {
+		return
+	}
+	// commit; if a node can't be updated, the ones that were are put back
+	var done []string
+	for id, nn := range ns {
+		if _, err := state.Update(nn); err != nil {
+			for _, d := range done {
+				state.Update(olds[d])
+			}
+			return fmt.Errorf("transaction failed to update %s: %v", id, err)
+		}
+		done = append(done, id)
+	}
+	var evs []lib.Event
+	for _, nn := range ns {
+		n.persist(dsc, nn.ID())
+	}
+	for _, u := range txn {
+		v, _ := state.GetValue(u)
+		ev := NewStateChangeEvent(utype, u, v)
+		ev.Data().(*StateChangeEvent).Txn = txn
+		evs = append(evs, ev)
+	}
+	go n.Emit(evs)
+	return
+}
+
+func (n *StateDifferenceEngine) selectByType(dsc bool, expr string) (r []lib.Node, e error) {
+	s, e := ParseNodeSelector(expr)
+	if e != nil {
+		return
+	}
+	var ms []lib.Node
+	if dsc {
+		ms, e = n.dsc.ReadAll()
+	} else {
+		ms, e = n.cfg.ReadAll()
+	}
+	if e != nil {
+		return
+	}
+	for _, m := range ms {
+		if s.Match(m) {
+			r = append(r, m)
+		}
+	}
+	return
+}
+
+func (n *StateDifferenceEngine) readGroupByType(dsc bool, group string) (r []lib.Node, e error) {
+	ms, e := n.cfg.ReadAll()
+	if e != nil {
+		return
+	}
+	for _, m := range ms {
+		if !m.InGroup(group) {
+			continue
+		}
+		if dsc {
+			m, e = n.dsc.Read(m.ID())
+			if e != nil {
+				return
+			}
+		}
+		r = append(r, m)
+	}
+	return
+}
+
 func (n *StateDifferenceEngine) bulkUpdateByType(dsc bool, ms []lib.Node) (r []lib.Node, e error) {
 	var old []lib.Node
 	var diff []string
@@ -520,6 +1054,9 @@ func (n *StateDifferenceEngine) bulkUpdateByType(dsc bool, ms []lib.Node) (r []l
 		/// but we still get an error
 		return
 	}
+	for _, v := range ms {
+		n.persist(dsc, v.ID())
+	}
 	var evs []lib.Event
 	utype := StateChange_UPDATE
 	if !dsc {
@@ -532,6 +1069,161 @@ func (n *StateDifferenceEngine) bulkUpdateByType(dsc bool, ms []lib.Node) (r []l
 	return
 }
 
+// load restores both states from the StateStore
+// discovered state is only restored for nodes that have configuration
+func (n *StateDifferenceEngine) load() {
+	cfg, e := n.store.Load(false)
+	if e != nil {
+		n.Logf(ERROR, "failed to load configuration state: %v", e)
+		return
+	}
+	dsc, e := n.store.Load(true)
+	if e != nil {
+		n.Logf(ERROR, "failed to load discoverable state: %v", e)
+	}
+	dscs := make(map[string]*Node)
+	for _, d := range dsc {
+		dscs[d.ID().String()] = d.(*Node)
+	}
+	for _, c := range cfg {
+		if _, e := n.cfg.Create(c); e != nil {
+			n.Logf(ERROR, "failed to restore node %s: %v", c.ID().String(), e)
+			continue
+		}
+		d, ok := dscs[c.ID().String()]
+		if !ok {
+			d = n.makeDscNode(c.(*Node))
+		}
+		n.dsc.Create(d)
+	}
+	n.Logf(INFO, "restored %d nodes from the state store", len(cfg))
+}
+
+// applyStoreEvent applies a configuration change made by someone else sharing our StateStore
+// the change is already in the store, so it isn't persisted again
+func (n *StateDifferenceEngine) applyStoreEvent(se StateStoreEvent) {
+	url := lib.NodeURLJoin(se.ID.String(), "")
+	if n.addrURL != "" && !se.Delete && se.ID.Equal(n.self) {
+		// the store doesn't have our address; see persist
+		se.Node.SetValue(n.addrURL, n.addr)
+	}
+	old, e := n.cfg.Read(se.ID)
+	switch {
+	case se.Delete:
+		if e != nil { // we never had it
+			return
+		}
+		n.cfg.DeleteByID(se.ID)
+		n.dsc.DeleteByID(se.ID)
+		n.forget(se.ID)
+		go n.EmitOne(NewStateChangeEvent(StateChange_DELETE, url, reflect.ValueOf(old)))
+	case e != nil: // new to us
+		if _, e = n.cfg.Create(se.Node); e != nil {
+			n.Logf(ERROR, "failed to create node from the state store: %v", e)
+			return
+		}
+		n.dsc.Create(n.makeDscNode(se.Node.(*Node)))
+		go n.EmitOne(NewStateChangeEvent(StateChange_CREATE, url, reflect.ValueOf(se.Node)))
+	default:
+		var diff []string
+		if diff, e = old.(*Node).Diff(se.Node.(*Node), url); e != nil || len(diff) == 0 {
+			return
+		}
+		n.cfg.Update(se.Node)
+		var evs []lib.Event
+		for _, u := range diff {
+			evs = append(evs, NewStateChangeEvent(StateChange_CFG_UPDATE, u, reflect.Value{}))
+		}
+		go n.Emit(evs)
+	}
+	n.Logf(DEBUG, "applied state store change to node %s", se.ID.String())
+}
+
+// persist queues the current version of a node to be saved to the StateStore, if we have one
+// The node is copied, since the persister can't read our state.
+// In HA mode, our own address is left out of the copy of our own node, so we don't overwrite the other head nodes'.
+func (n *StateDifferenceEngine) persist(dsc bool, nid lib.NodeID) {
+	if n.store == nil {
+		return
+	}
+	var m lib.Node
+	var e error
+	if dsc {
+		m, e = n.dsc.Read(nid)
+	} else {
+		m, e = n.cfg.Read(nid)
+	}
+	if e != nil {
+		return
+	}
+	c := NewNodeFromBinary(m.Binary())
+	if n.addrURL != "" && !dsc && nid.Equal(n.self) {
+		c.SetValue(n.addrURL, reflect.ValueOf([]byte(nil)))
+	}
+	n.queueWrite(storeKey{dsc, nid.String()}, c)
+}
+
+// unpersist queues a node to be removed from the StateStore, if we have one
+func (n *StateDifferenceEngine) unpersist(nid lib.NodeID) {
+	if n.store == nil {
+		return
+	}
+	for _, dsc := range []bool{false, true} {
+		n.queueWrite(storeKey{dsc, nid.String()}, nil)
+	}
+}
+
+// queueWrite replaces any write of k that's still pending with m
+func (n *StateDifferenceEngine) queueWrite(k storeKey, m lib.Node) {
+	n.pmutex.Lock()
+	n.pending[k] = m
+	n.pmutex.Unlock()
+	select {
+	case n.pwake <- struct{}{}:
+	default: // it's already been woken
+	}
+}
+
+// goroutine
+// persister makes the writes queued by persist and unpersist.
+// Writes that fail are retried, unless the node has changed again since, until the store takes them.
+func (n *StateDifferenceEngine) persister() {
+	for range n.pwake {
+		for {
+			n.pmutex.Lock()
+			ws := n.pending
+			n.pending = make(map[storeKey]lib.Node)
+			n.pmutex.Unlock()
+			if len(ws) == 0 {
+				break
+			}
+			failed := 0
+			var last error
+			for k, m := range ws {
+				var e error
+				if m == nil {
+					e = n.store.Delete(k.dsc, NewNodeID(k.id))
+				} else {
+					e = n.store.Save(k.dsc, m)
+				}
+				if e == nil {
+					continue
+				}
+				failed, last = failed+1, e
+				n.pmutex.Lock()
+				if _, ok := n.pending[k]; !ok {
+					n.pending[k] = m
+				}
+				n.pmutex.Unlock()
+			}
+			if failed > 0 {
+				n.Logf(ERROR, "failed to write %d nodes to the state store, will retry: %v", failed, last)
+				time.Sleep(time.Second)
+			}
+		}
+	}
+}
+
 // goroutine
 func (n *StateDifferenceEngine) sendQueryResponse(qr lib.QueryResponse, r chan<- lib.QueryResponse) {
 	r <- qr
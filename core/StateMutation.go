@@ -31,8 +31,17 @@ type StateMutation struct {
 	base    *StateSpec // this is the spec, less the mutation value
 	timeout time.Duration
 	failto  [3]string
+	cost    uint32
+	retries uint32
+	backoff time.Duration
 }
 
+// DefaultMutationCost is the cost a StateMutation has unless SetCost is used
+const DefaultMutationCost uint32 = 1
+
+// MutationRetryMaxBackoff caps how long the SME waits between retries, however many there have been
+var MutationRetryMaxBackoff = 10 * time.Minute
+
 // NewStateMutation creates an initialized, specified StateMutation object
 func NewStateMutation(mut map[string][2]reflect.Value, req map[string]reflect.Value, exc map[string]reflect.Value, context lib.StateMutationContext, timeout time.Duration, failto [3]string) *StateMutation {
 	for u := range mut {
@@ -51,6 +60,7 @@ func NewStateMutation(mut map[string][2]reflect.Value, req map[string]reflect.Va
 	r.context = context
 	r.timeout = timeout
 	r.failto = failto
+	r.cost = DefaultMutationCost
 	return r
 }
 
@@ -142,3 +152,24 @@ func (s *StateMutation) SpecCompatWithMutators(sp lib.StateSpec, muts map[string
 func (s *StateMutation) Timeout() time.Duration { return s.timeout }
 
 func (s *StateMutation) FailTo() [3]string { return s.failto }
+
+// Cost is how expensive this mutation is relative to others; the SME takes the cheapest path
+func (s *StateMutation) Cost() uint32 { return s.cost }
+
+// SetCost declares how expensive this mutation is
+// e.g. a module may make itself a fallback by declaring costs higher than DefaultMutationCost
+func (s *StateMutation) SetCost(c uint32) { s.cost = c }
+
+// Retries is how many times the SME re-emits this mutation after a timeout before it fails
+func (s *StateMutation) Retries() uint32 { return s.retries }
+
+// RetryBackoff is how long the SME waits before the first retry; the wait doubles with each retry after that,
+// up to MutationRetryMaxBackoff
+func (s *StateMutation) RetryBackoff() time.Duration { return s.backoff }
+
+// SetRetry declares that a timeout of this mutation is worth retrying before falling to the failure target
+// e.g. for hardware controllers that occasionally drop requests
+func (s *StateMutation) SetRetry(retries uint32, backoff time.Duration) {
+	s.retries = retries
+	s.backoff = backoff
+}
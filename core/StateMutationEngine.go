@@ -10,13 +10,17 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	pb "github.com/hpc/kraken/core/proto"
 	"github.com/hpc/kraken/lib"
 )
@@ -42,12 +46,25 @@ type MutationEvent struct {
 	NodeCfg  lib.Node
 	NodeDsc  lib.Node
 	Mutation [2]string // [0] = module, [1] = mutid
+	// Context is canceled if the mutation is interrupted (or the node gets another mutation), so modules
+	// can stop work in progress, e.g. with exec.CommandContext.  It's set on mutations received through the API.
+	Context context.Context
+	done    func() // set on mutations received through the API, see Done
 }
 
 func (me *MutationEvent) String() string {
 	return fmt.Sprintf("(%s) %s : %s -> %s", MutationEventString[me.Type], me.NodeCfg.ID().String(), me.Mutation[0], me.Mutation[1])
 }
 
+// Done tells the API a module has finished handling a mutation (or interrupt) it received through the API,
+// whether or not it succeeded.  Queued mutations aren't acknowledged until they're done.
+// It's safe to call more than once.
+func (me *MutationEvent) Done() {
+	if me.done != nil {
+		me.done()
+	}
+}
+
 type mutationEdge struct {
 	cost uint32
 	mut  lib.StateMutation
@@ -73,6 +90,27 @@ type mutationPath struct {
 	gend    *mutationNode
 	chain   []*mutationEdge
 	timer   *time.Timer
+	// tries is how many times the current mutation has been retried
+	tries uint32
+	// rollback is set if this path failed and end has been replaced by the (stable) state we started in
+	rollback bool
+	// throttle is the throttle we hold a slot in; queued is the one we're waiting on (if any)
+	// these are protected by the SME's throttleMutex, not the path mutex
+	throttle *mutationThrottle
+	queued   *mutationThrottle
+	priority pb.Node_Priority // the priority we queued with
+	started  time.Time        // when the chain started, for metrics
+	// curStarted is when the current mutation started, to tell how long it's been waiting
+	curStarted time.Time
+	// idle is set if the chain isn't going anywhere: it completed, or we got lost
+	idle bool
+}
+
+// A mutationThrottle limits how many of a class of mutations can be executing at once
+type mutationThrottle struct {
+	max     int
+	running int
+	waiting []*mutationPath // FIFO
 }
 
 // DefaultRootSpec provides a sensible root StateSpec to build the mutation graph off of
@@ -104,12 +142,21 @@ type StateMutationEngine struct {
 	selist      *EventListener
 	run         bool                     // are we running?
 	active      map[string]*mutationPath // active mutations
-	activeMutex *sync.Mutex              // active needs some synchronization, or we can get in bad places
+	activeMutex *sync.Mutex              // always taken last: paths lock it to check they're active, so never lock a path while holding it
 	query       *QueryEngine
 	log         lib.Logger
 	self        lib.NodeID
 	root        lib.StateSpec
 	freeze      bool
+	rollback    bool          // roll back failed mutation chains?
+	reconcile   time.Duration // how often to look for drifted nodes; 0 for never
+	archive     string        // where to archive decommissioned nodes before removing them
+	// throttles are keyed by module or module:mutation
+	throttleCfg   map[string]int
+	throttles     map[string]*mutationThrottle
+	throttleMutex *sync.Mutex
+	metrics       *mutationMetrics
+	convergence   *convergence
 }
 
 // NewStateMutationEngine creates an initialized StateMutationEngine
@@ -134,9 +181,18 @@ func NewStateMutationEngine(ctx Context, qc chan lib.Query) *StateMutationEngine
 		log:         &ctx.Logger,
 		self:        ctx.Self,
 		root:        ctx.SME.RootSpec,
+		rollback:    ctx.SME.Rollback,
+		reconcile:   ctx.SME.Reconcile,
+		archive:     ctx.SME.Archive,
 		freeze:      true,
+
+		throttleCfg:   ctx.SME.Throttles,
+		throttleMutex: &sync.Mutex{},
+		metrics:       newMutationMetrics(),
+		convergence:   newConvergence(),
 	}
 	sme.log.SetModule("StateMutationEngine")
+	sme.resetThrottles()
 	return sme
 }
 
@@ -251,39 +307,69 @@ func (sme *StateMutationEngine) mutationNodesToProto(nodes []*mutationNode) (r p
 		}
 
 		nmn.Label = label
+		nmn.Requires = valueMapToStrings(mn.spec.Requires())
+		nmn.Excludes = valueMapToStrings(mn.spec.Excludes())
 		r.MutationNodeList = append(r.MutationNodeList, &nmn)
 	}
 	return
 }
 
+// Converts an sme mutation edge to a protobuf MutationEdge
+// Should be called with graphMutex (R) held, for mutResolver
+func (sme *StateMutationEngine) mutationEdgeToProto(me *mutationEdge) *pb.MutationEdge {
+	nme := &pb.MutationEdge{
+		From:     fmt.Sprintf("%p", me.from),
+		To:       fmt.Sprintf("%p", me.to),
+		Id:       fmt.Sprintf("%p", me),
+		Module:   sme.mutResolver[me.mut][0],
+		Mutation: sme.mutResolver[me.mut][1],
+		Mutates:  make(map[string]string),
+		Requires: valueMapToStrings(me.mut.Requires()),
+		Excludes: valueMapToStrings(me.mut.Excludes()),
+		Cost:     me.cost,
+	}
+	if me.mut.Timeout() != 0 {
+		nme.Timeout = me.mut.Timeout().String()
+	}
+	for u, v := range me.mut.Mutates() {
+		nme.Mutates[u] = lib.ValueToString(v[0]) + " -> " + lib.ValueToString(v[1])
+	}
+	return nme
+}
+
 // Converts a slice of sme mutation edges to a protobuf MutationEdgeList
-func mutationEdgesToProto(edges []*mutationEdge) (r pb.MutationEdgeList) {
+// Should be called with graphMutex (R) held
+func (sme *StateMutationEngine) mutationEdgesToProto(edges []*mutationEdge) (r pb.MutationEdgeList) {
 	for _, me := range edges {
-		var nme pb.MutationEdge
-		nme.From = fmt.Sprintf("%p", me.from)
-		nme.To = fmt.Sprintf("%p", me.to)
-		nme.Id = fmt.Sprintf("%p", me)
-
-		r.MutationEdgeList = append(r.MutationEdgeList, &nme)
+		r.MutationEdgeList = append(r.MutationEdgeList, sme.mutationEdgeToProto(me))
 	}
 	return
 }
 
 // Converts an sme mutation path to a protobuf MutationPath
-// LOCKS: path.mutex
-func mutationPathToProto(path *mutationPath) (r pb.MutationPath, e error) {
-	path.mutex.Lock()
-	defer path.mutex.Unlock()
+// Should be called with graphMutex (R) held
+// LOCKS: path.mutex; throttleMutex
+func (sme *StateMutationEngine) mutationPathToProto(path *mutationPath) (r pb.MutationPath, e error) {
 	if path != nil {
+		path.mutex.Lock()
+		defer path.mutex.Unlock()
 		r.Cur = int64(path.cur)
 		for _, me := range path.chain {
-			var nme pb.MutationEdge
-			nme.From = fmt.Sprintf("%p", me.from)
-			nme.To = fmt.Sprintf("%p", me.to)
-			nme.Id = fmt.Sprintf("%p", me)
-
-			r.Chain = append(r.Chain, &nme)
+			r.Chain = append(r.Chain, sme.mutationEdgeToProto(me))
+		}
+		if !path.started.IsZero() {
+			r.Started, _ = ptypes.TimestampProto(path.started)
+			r.CurStarted, _ = ptypes.TimestampProto(path.curStarted)
+		}
+		r.Tries = path.tries
+		r.Rollback = path.rollback
+		sme.throttleMutex.Lock()
+		for k, t := range sme.throttles {
+			if path.queued != nil && t == path.queued {
+				r.Throttle = k
+			}
 		}
+		sme.throttleMutex.Unlock()
 	} else {
 		e = fmt.Errorf("Mutation path is nil")
 	}
@@ -426,17 +512,7 @@ func (sme *StateMutationEngine) Run() {
 		lib.Event_STATE_CHANGE,
 		func(v lib.Event) bool {
 			_, url := lib.NodeURLSplit(v.URL())
-			sme.graphMutex.RLock()
-			defer sme.graphMutex.RUnlock()
-			for m := range sme.mutators { // NOTE: doesn't fix beginning slashes, etc
-				if url == m {
-					return true
-				}
-			}
-			if url == "" { // this should mean we got CREATE/DELETE
-				return true
-			}
-			return false
+			return sme.watching(url)
 		},
 		func(v lib.Event) error { return ChanSender(v, sme.echan) })
 
@@ -453,6 +529,11 @@ func (sme *StateMutationEngine) Run() {
 		}()
 	}
 
+	var reconcile <-chan time.Time // nil never fires
+	if sme.reconcile > 0 {
+		reconcile = time.NewTicker(sme.reconcile).C
+	}
+
 	for {
 		select {
 		case q := <-sme.qc:
@@ -483,7 +564,7 @@ func (sme *StateMutationEngine) Run() {
 				// If url is empty then assume we want all mutation edges
 				if u == "" {
 					sme.graphMutex.RLock()
-					v := mutationEdgesToProto(sme.edges)
+					v := sme.mutationEdgesToProto(sme.edges)
 					sme.graphMutex.RUnlock()
 					go sme.sendQueryResponse(NewQueryResponse(
 						[]reflect.Value{reflect.ValueOf(v)}, e), q.ResponseChan())
@@ -491,7 +572,7 @@ func (sme *StateMutationEngine) Run() {
 					n := NewNodeIDFromURL(q.URL())
 					sme.graphMutex.RLock()
 					fme, e := sme.filterMutEdgesFromNode(*n)
-					mel := mutationEdgesToProto(fme)
+					mel := sme.mutationEdgesToProto(fme)
 					sme.graphMutex.RUnlock()
 					go sme.sendQueryResponse(NewQueryResponse(
 						[]reflect.Value{reflect.ValueOf(mel)}, e), q.ResponseChan())
@@ -502,23 +583,64 @@ func (sme *StateMutationEngine) Run() {
 				sme.activeMutex.Lock()
 				mp := sme.active[n.String()]
 				sme.activeMutex.Unlock()
-				pmp, e := mutationPathToProto(mp)
+				sme.graphMutex.RLock()
+				pmp, e := sme.mutationPathToProto(mp)
+				sme.graphMutex.RUnlock()
 				go sme.sendQueryResponse(NewQueryResponse(
 					[]reflect.Value{reflect.ValueOf(pmp)}, e), q.ResponseChan())
 				break
+			case lib.Query_MUTATIONPLAN:
+				var pmp pb.MutationPath
+				mp, e := sme.planPath(q.Value()[0].Interface().(lib.Node))
+				if e == nil {
+					sme.graphMutex.RLock()
+					pmp, e = sme.mutationPathToProto(mp)
+					sme.graphMutex.RUnlock()
+				}
+				go sme.sendQueryResponse(NewQueryResponse(
+					[]reflect.Value{reflect.ValueOf(pmp)}, e), q.ResponseChan())
+				break
+			case lib.Query_MUTATIONMETRICS:
+				sme.activeMutex.Lock()
+				active := len(sme.active)
+				sme.activeMutex.Unlock()
+				mm := sme.metrics.proto(active)
+				go sme.sendQueryResponse(NewQueryResponse(
+					[]reflect.Value{reflect.ValueOf(mm)}, nil), q.ResponseChan())
+				break
+			case lib.Query_CONVERGENCE:
+				// this reads every node, so we don't hold up events while it does
+				go func(q lib.Query) {
+					r, e := sme.Convergence()
+					sme.sendQueryResponse(NewQueryResponse(
+						[]reflect.Value{reflect.ValueOf(r)}, e), q.ResponseChan())
+				}(q)
+				break
 			default:
 				sme.Logf(DEBUG, "unsupported query type: %d", q.Type())
 			}
 			break
 		case v := <-sme.echan:
+			// we keep track of convergence even when we're frozen, e.g. as an HA standby
+			sme.trackConvergence(v)
 			// FIXME: event processing can be expensive;
 			// we should make them concurrent with a queue
 			if !sme.Frozen() {
 				sme.handleEvent(v)
 			}
 			break
+		case <-reconcile:
+			if !sme.Frozen() {
+				sme.reconcileNodes()
+			}
+			break
 		case <-debugchan:
 			sme.Logf(DDEBUG, "There are %d active mutations.", len(sme.active))
+			sme.throttleMutex.Lock()
+			for k, t := range sme.throttles {
+				sme.Logf(DDEBUG, "throttle %s: %d/%d running, %d waiting", k, t.running, t.max, len(t.waiting))
+			}
+			sme.throttleMutex.Unlock()
 			break
 		}
 	}
@@ -543,6 +665,7 @@ func (sme *StateMutationEngine) Thaw() {
 	sme.active = make(map[string]*mutationPath)
 	sme.freeze = false
 	sme.activeMutex.Unlock()
+	sme.resetThrottles()
 	ns, _ := sme.query.ReadAll()
 	for _, n := range ns {
 		sme.startNewMutation(n.ID().String())
@@ -629,7 +752,7 @@ func (sme *StateMutationEngine) buildGraph(root *mutationNode, seenNode map[lib.
 				return []*mutationNode{}, []*mutationEdge{}
 			}
 			nme := &mutationEdge{
-				cost: 1,
+				cost: m.Cost(),
 				mut:  m,
 				from: root,
 			}
@@ -715,10 +838,31 @@ func (sme *StateMutationEngine) boundarySearch(start lib.Node, end lib.Node) (gs
 	return
 }
 
+// edgeCost gives the cost of an edge, with per-node overrides applied
+// overrides for a specific mutation take precedence over overrides for a whole module
+// Should be called with graphMutex (R) held, for mutResolver
+func (sme *StateMutationEngine) edgeCost(me *mutationEdge, overrides []*pb.MutationCost) (c uint32) {
+	c = me.cost
+	res := sme.mutResolver[me.mut]
+	for _, o := range overrides {
+		if o.Module != res[0] {
+			continue
+		}
+		if o.Mutation == res[1] {
+			return o.Cost
+		}
+		if o.Mutation == "" {
+			c = o.Cost
+		}
+	}
+	return
+}
+
 // drijkstra implements the Drijkstra shortest path graph algorithm.
+// overrides are per-node costs that replace the costs declared by mutations
 // NOTE: An alternative would be to pre-compute trees for every node
 // LOCKS: graphMutex (R)
-func (sme *StateMutationEngine) drijkstra(gstart *mutationNode, gend []*mutationNode) *mutationPath {
+func (sme *StateMutationEngine) drijkstra(gstart *mutationNode, gend []*mutationNode, overrides []*pb.MutationCost) *mutationPath {
 	sme.graphMutex.RLock()
 	defer sme.graphMutex.RUnlock()
 	isEnd := func(i *mutationNode) (r bool) {
@@ -734,8 +878,9 @@ func (sme *StateMutationEngine) drijkstra(gstart *mutationNode, gend []*mutation
 	prev := make(map[*mutationNode]*mutationEdge)
 	queue := make(map[*mutationNode]*mutationNode)
 
+	unreachable := ^uint32(0) - 1 // max uint32 - 1, a total hack
 	for _, n := range sme.nodes {
-		dist[n] = ^uint32(0) - 1 // max uint32 - 1, a total hack
+		dist[n] = unreachable
 		prev[n] = nil
 		queue[n] = n
 	}
@@ -773,11 +918,19 @@ func (sme *StateMutationEngine) drijkstra(gstart *mutationNode, gend []*mutation
 
 		delete(queue, idx)
 
+		if dist[u] == unreachable {
+			// everything left is unreachable; adding costs to this could overflow
+			continue
+		}
+
 		for _, v := range u.out {
 			if _, ok := queue[v.to]; !ok { // v should be in queue
 				continue
 			}
-			alt := dist[u] + v.cost
+			alt := dist[u] + sme.edgeCost(v, overrides)
+			if alt < dist[u] || alt >= unreachable { // overflow
+				continue
+			}
 			if alt < dist[v.to] {
 				dist[v.to] = alt
 				prev[v.to] = v
@@ -843,17 +996,34 @@ func (sme *StateMutationEngine) findPath(start lib.Node, end lib.Node) (path *mu
 		}
 	}
 	*/
-	path = sme.drijkstra(gs[0], ge) // we require a unique start, but not a unique end
-	path.start = start
-	path.end = end
-	path.cur = 0
-	if path.chain == nil {
+	// per-node cost overrides come from the configuration
+	var overrides []*pb.MutationCost
+	if v, err := end.GetValue("/MutationCosts"); err == nil && v.IsValid() {
+		overrides, _ = v.Interface().([]*pb.MutationCost)
+	}
+	path = sme.drijkstra(gs[0], ge, overrides) // we require a unique start, but not a unique end
+	if path == nil || path.chain == nil {
 		e = fmt.Errorf("path not found: you can't get there from here")
 		path = nil
+		return
 	}
+	path.start = start
+	path.end = end
+	path.cur = 0
 	return
 }
 
+// planPath finds the path we would take a node on from its current discoverable state to end
+// This is a dry run; nothing is started
+// LOCKS: graphMutex (R) via findPath
+func (sme *StateMutationEngine) planPath(end lib.Node) (p *mutationPath, e error) {
+	start, e := sme.query.ReadDsc(end.ID())
+	if e != nil {
+		return
+	}
+	return sme.findPath(start, end)
+}
+
 // startNewMutation sees if we need a new mutation
 // if we do, it starts it
 // if we don't already have a mutation object, it creates it
@@ -871,6 +1041,15 @@ func (sme *StateMutationEngine) startNewMutation(node string) {
 		sme.Log(ERROR, e.Error())
 		return
 	}
+	if end.Frozen() {
+		// someone is working on this node; we'll start again when it's thawed
+		sme.Logf(DEBUG, "%s is frozen, not mutating", nid.String())
+		return
+	}
+	if end.Lifecycle() == pb.Node_DECOMMISSIONED {
+		sme.Logf(DEBUG, "%s is decommissioned, not mutating", nid.String())
+		return
+	}
 	p, e := sme.findPath(start, end)
 	if e != nil {
 		sme.Log(ERROR, e.Error())
@@ -878,6 +1057,7 @@ func (sme *StateMutationEngine) startNewMutation(node string) {
 	}
 	if len(p.chain) == 0 { // we're already there
 		sme.Logf(DEBUG, "%s discovered that we're already where we want to be", nid.String())
+		sme.retire(end)
 		return
 	}
 	// new mutation, record it, and start it in motion
@@ -885,29 +1065,53 @@ func (sme *StateMutationEngine) startNewMutation(node string) {
 	// we need to hold the path mutex for the rest of this function
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
+	p.started = time.Now()
+	p.curStarted = p.started
 
 	sme.activeMutex.Lock()
 	sme.active[node] = p
 	sme.activeMutex.Unlock()
 	sme.Logf(DEBUG, "started new mutation for %s (1/%d).", nid.String(), len(p.chain))
 	if sme.mutationInContext(end, p.chain[p.cur].mut) {
-		sme.Logf(DDEBUG, "firing mutation in context, timeout %s.", p.chain[p.cur].mut.Timeout().String())
-		sme.emitMutation(end, start, p.chain[p.cur].mut)
-		if p.chain[p.cur].mut.Timeout() != 0 {
-			p.timer = time.AfterFunc(p.chain[p.cur].mut.Timeout(), func() { sme.emitFail(start, p) })
-		}
+		sme.fireMutation(p)
 	} else {
 		sme.Log(DDEBUG, "mutation is not in our context.")
 	}
 }
 
-// LOCKS: graphMutex (R); path.mutex
+// LOCKS: graphMutex (R); path.mutex; activeMutex (while holding path.mutex, see activeMutex)
 func (sme *StateMutationEngine) emitFail(start lib.Node, p *mutationPath) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
+	sme.activeMutex.Lock()
+	stale := sme.freeze || sme.active[p.start.ID().String()] != p
+	sme.activeMutex.Unlock()
+	if stale {
+		// we were frozen (e.g. we lost the lead), or the path was dropped, since the timer was started;
+		// whoever mutates the node now decides whether it failed
+		return
+	}
+	sme.releaseMutation(p)
 
 	nid := p.start.ID()
-	d := p.chain[p.cur].mut.FailTo()
+	cfg := p.end
+	mut := p.chain[p.cur].mut
+	if p.tries < mut.Retries() {
+		// give the same mutation another go before we declare failure
+		// checked before shifting, so it can't overflow
+		wait := MutationRetryMaxBackoff
+		if p.tries < 63 && mut.RetryBackoff() <= MutationRetryMaxBackoff>>p.tries {
+			wait = mut.RetryBackoff() << p.tries
+		}
+		p.tries++
+		sme.countMutation(mut, func(em *pb.MutationEdgeMetrics) { em.Retried++ })
+		sme.Logf(INFO, "mutation timeout for %s, retrying (%d/%d) in %s", nid.String(), p.tries, mut.Retries(), wait.String())
+		cur := p.cur
+		p.timer = time.AfterFunc(wait, func() { sme.retryMutation(p, cur) })
+		return
+	}
+	sme.countMutation(mut, func(em *pb.MutationEdgeMetrics) { em.Failed++ })
+	d := mut.FailTo()
 	sme.Logf(INFO, "mutation timeout for %s, emitting: %s:%s:%s", nid.String(), d[0], d[1], d[2])
 
 	// reset all mutators to zero, except the failure mutator
@@ -924,6 +1128,21 @@ func (sme *StateMutationEngine) emitFail(start lib.Node, p *mutationPath) {
 	sme.graphMutex.RUnlock()
 	sme.query.UpdateDsc(node)
 
+	if sme.rollback && !p.rollback && p.cur > 0 {
+		// we got part way; rather than push on from wherever the failure leaves us,
+		// we make where we started our new end.  handleUnexpected will find the way back.
+		sme.Logf(INFO, "rolling back mutation chain for %s (failed at %d/%d)", nid.String(), p.cur+1, len(p.chain))
+		end := NewNodeFromMessage(p.end.Message().(*pb.Node))
+		sme.graphMutex.RLock()
+		for m := range sme.mutators {
+			v, _ := p.start.GetValue(m)
+			end.SetValue(m, v)
+		}
+		sme.graphMutex.RUnlock()
+		p.end = end
+		p.rollback = true
+	}
+
 	// now send a discover to whatever failed state
 	url := lib.NodeURLJoin(nid.String(), d[1])
 	dv := NewEvent(
@@ -942,7 +1161,7 @@ func (sme *StateMutationEngine) emitFail(start lib.Node, p *mutationPath) {
 		url,
 		&MutationEvent{
 			Type:     pb.MutationControl_INTERRUPT,
-			NodeCfg:  p.end,
+			NodeCfg:  cfg,
 			NodeDsc:  start,
 			Mutation: sme.mutResolver[p.chain[p.cur].mut],
 		},
@@ -957,13 +1176,12 @@ func (sme *StateMutationEngine) advanceMutation(node string, m *mutationPath) {
 	nid := NewNodeIDFromURL(node)
 	m.cur++
 	m.curSeen = []string{}
+	m.tries = 0
+	m.idle = false
+	m.curStarted = time.Now()
 	sme.Logf(DEBUG, "resuming mutation for %s (%d/%d).", nid.String(), m.cur+1, len(m.chain))
 	if sme.mutationInContext(m.end, m.chain[m.cur].mut) {
-		sme.Logf(DDEBUG, "firing mutation in context, timeout %s.", m.chain[m.cur].mut.Timeout().String())
-		sme.emitMutation(m.end, m.start, m.chain[m.cur].mut)
-		if m.chain[m.cur].mut.Timeout() != 0 {
-			m.timer = time.AfterFunc(m.chain[m.cur].mut.Timeout(), func() { sme.emitFail(m.start, m) })
-		}
+		sme.fireMutation(m)
 	} else {
 		sme.Logf(DDEBUG, "node (%s) mutation is not in our context", node)
 	}
@@ -986,13 +1204,16 @@ func (sme *StateMutationEngine) handleUnexpected(node, url string, val reflect.V
 		sme.startNewMutation(node)
 		return
 	}
+	// whatever we were doing is over
+	sme.releaseMutation(m)
 
 	rewind := make(map[string]reflect.Value)
 	// starting from the current position, look backwards in the chain
 	// have we seen this value before?  Maybe we need to reset to that point...
+	// (unless we're rolling back; going forward again is what we don't want)
 	found := false
 	var i int
-	for i = m.cur; i >= 0; i-- {
+	for i = m.cur; i >= 0 && !m.rollback; i-- {
 		// is there a mutation with this url?
 		for murl, mvs := range m.chain[i].mut.Mutates() {
 			if murl == url {
@@ -1032,15 +1253,21 @@ func (sme *StateMutationEngine) handleUnexpected(node, url string, val reflect.V
 	}
 
 	// not a devolution, can we find a path?
-	end, e := sme.query.Read(nid)
-	if e != nil {
-		sme.Log(ERROR, e.Error())
-		return
+	end := m.end
+	if !m.rollback {
+		// a rolled back path stays where it started until the cfg changes
+		if end, e = sme.query.Read(nid); e != nil {
+			sme.Log(ERROR, e.Error())
+			return
+		}
 	}
 	p, e := sme.findPath(n, end)
 	if e == nil {
 		if len(p.chain) == 0 { // we're already there
 			sme.Logf(DEBUG, "%s discovered that we're already where we want to be", nid.String())
+			if !m.rollback {
+				sme.retire(end)
+			}
 			return
 		}
 		// update the chain & increment
@@ -1051,6 +1278,7 @@ func (sme *StateMutationEngine) handleUnexpected(node, url string, val reflect.V
 	}
 
 	sme.Logf(DEBUG, "%s could neither find a path, nor devolve.  We're lost.", node)
+	m.idle = true
 
 	sme.graphMutex.RLock()
 	defer sme.graphMutex.RUnlock()
@@ -1131,11 +1359,21 @@ func (sme *StateMutationEngine) updateMutation(node string, url string, val refl
 			}
 		}
 		m.curSeen = []string{} // possibly redundant
-		m.timer.Stop()
+		if m.timer != nil {
+			m.timer.Stop()
+		}
+		sme.releaseMutation(m)
+		sme.countMutation(m.chain[m.cur].mut, func(em *pb.MutationEdgeMetrics) { em.Succeeded++ })
+		sme.runPostHooks(m)
 		// are we done?
 		if len(m.chain) == m.cur+1 {
 			// all done!
 			sme.Logf(DEBUG, "mutation chain completed for %s (%d/%d)", node, m.cur+1, len(m.chain))
+			sme.metrics.converged(time.Since(m.started))
+			m.idle = true
+			if !m.rollback {
+				sme.retire(m.end)
+			}
 			return
 		}
 		sme.Logf(DEBUG, "mutation for %s progressing as normal, moving to next (%d/%d)", node, m.cur+1, len(m.chain))
@@ -1151,6 +1389,51 @@ func (sme *StateMutationEngine) updateMutation(node string, url string, val refl
 	}
 }
 
+// reconcileNodes looks for nodes whose discoverable state has drifted from their configuration
+// and starts a new mutation chain for them.  Nodes with a chain that's still working are left alone;
+// if it doesn't get there, we'll catch it next time.
+// LOCKS: activeMutex; path.mutex; graphMutex (R)
+func (sme *StateMutationEngine) reconcileNodes() {
+	cfgs, e := sme.query.ReadAll()
+	if e != nil {
+		sme.Logf(ERROR, "reconcile couldn't read nodes: %v", e)
+		return
+	}
+	for _, cfg := range cfgs {
+		if cfg.Frozen() || cfg.Lifecycle() == pb.Node_DECOMMISSIONED {
+			continue
+		}
+		node := cfg.ID().String()
+		sme.activeMutex.Lock()
+		m, ok := sme.active[node]
+		sme.activeMutex.Unlock()
+		if ok {
+			m.mutex.Lock()
+			idle := m.idle
+			m.mutex.Unlock()
+			if !idle {
+				continue
+			}
+		}
+		dsc, e := sme.query.ReadDsc(cfg.ID())
+		if e != nil || !sme.drifted(cfg, dsc) {
+			continue
+		}
+		sme.Logf(INFO, "%s has drifted from its configuration, reconciling", node)
+		if ok && !sme.removeActive(node, m) {
+			// it was replaced while we looked, so it's already being handled
+			continue
+		}
+		sme.startNewMutation(node)
+	}
+}
+
+// drifted reports whether any URL that mutates differs between cfg and dsc (see deltas)
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) drifted(cfg, dsc lib.Node) bool {
+	return len(sme.deltas(cfg, dsc)) > 0
+}
+
 // Assumes you already hold a lock
 func (sme *StateMutationEngine) mutationInContext(n lib.Node, m lib.StateMutation) (r bool) {
 	switch m.Context() {
@@ -1170,6 +1453,26 @@ func (sme *StateMutationEngine) mutationInContext(n lib.Node, m lib.StateMutatio
 	return
 }
 
+// removeActive takes a node's active mutation path out of active, and releases it.
+// If p isn't nil, the path is only removed if it's still p.  It reports whether a path was removed.
+// LOCKS: activeMutex; path.mutex
+func (sme *StateMutationEngine) removeActive(node string, p *mutationPath) bool {
+	sme.activeMutex.Lock()
+	m, ok := sme.active[node]
+	if !ok || (p != nil && m != p) {
+		sme.activeMutex.Unlock()
+		return false
+	}
+	delete(sme.active, node)
+	sme.activeMutex.Unlock()
+	// m is no longer active, so anything that gets its lock before us will leave it alone
+	m.mutex.Lock()
+	sme.releaseMutation(m)
+	m.mutex.Unlock()
+	return true
+}
+
+// LOCKS: activeMutex; path.mutex via removeActive
 func (sme *StateMutationEngine) handleEvent(v lib.Event) {
 	sce := v.Data().(*StateChangeEvent)
 	node, url := lib.NodeURLSplit(sce.URL)
@@ -1182,31 +1485,31 @@ func (sme *StateMutationEngine) handleEvent(v lib.Event) {
 			// what?! how do we have an active mutation for a node that was just created?
 			// let's print something, and then pretend it *is* new
 			sme.Log(DEBUG, "what?! we got a CREATE event for a node with an existing mutation")
-			sme.activeMutex.Lock()
-			delete(sme.active, node)
-			sme.activeMutex.Unlock()
+			sme.removeActive(node, nil)
 		}
 		sme.startNewMutation(node)
 	case StateChange_DELETE:
 		if ok {
-			sme.activeMutex.Lock()
-			m := sme.active[node]
-			m.mutex.Lock()
-			delete(sme.active, node)
-			m.mutex.Unlock()
-			sme.activeMutex.Unlock()
+			sme.removeActive(node, nil)
 		}
 	case StateChange_UPDATE:
+		sme.metrics.discovered()
 		sme.updateMutation(node, url, sce.Value)
 	case StateChange_CFG_UPDATE:
+		// a transaction's changes are all made before any are emitted,
+		// so we only need to start one new chain per node for it
+		for _, u := range sce.Txn {
+			if n, turl := lib.NodeURLSplit(u); n == node && sme.watching(turl) {
+				if u != sce.URL {
+					sme.Logf(DDEBUG, "%s is part of a transaction we already handled", sce.URL)
+					return
+				}
+				break
+			}
+		}
 		// for a cfg update, we need to create a new chain
 		if ok {
-			sme.activeMutex.Lock()
-			m := sme.active[node]
-			m.mutex.Lock()
-			delete(sme.active, node)
-			m.mutex.Unlock()
-			sme.activeMutex.Unlock()
+			sme.removeActive(node, nil)
 		}
 		sme.Logf(DEBUG, "our cfg has changed, creating new mutaiton path: %s:%s", node, url)
 		sme.startNewMutation(node)
@@ -1214,6 +1517,308 @@ func (sme *StateMutationEngine) handleEvent(v lib.Event) {
 	}
 }
 
+// fireMutation emits the current mutation of a path, and starts its timeout.
+// If the mutation is throttled and the throttle is full, the path waits for a slot instead;
+// paths wait in order of their node's priority, and EMERGENCY nodes don't wait at all.
+// assumes p.mutex is locked by surrounding func
+// LOCKS: throttleMutex; graphMutex (R)
+func (sme *StateMutationEngine) fireMutation(p *mutationPath) {
+	mut := p.chain[p.cur].mut
+	if t := sme.throttleFor(mut); t != nil {
+		sme.throttleMutex.Lock()
+		p.priority = p.end.Priority()
+		if t.running >= t.max && p.priority != pb.Node_EMERGENCY {
+			// wait in line behind anything of the same or higher priority
+			i := len(t.waiting)
+			for i > 0 && t.waiting[i-1].priority < p.priority {
+				i--
+			}
+			t.waiting = append(t.waiting, nil)
+			copy(t.waiting[i+1:], t.waiting[i:])
+			t.waiting[i] = p
+			p.queued = t
+			waiting := len(t.waiting)
+			sme.throttleMutex.Unlock()
+			sme.Logf(DEBUG, "throttling mutation for %s, %d waiting", p.start.ID().String(), waiting)
+			return
+		}
+		t.running++
+		p.throttle = t
+		sme.throttleMutex.Unlock()
+	}
+	sme.emitCurrent(p)
+}
+
+// emitCurrent emits the current mutation of a path, and starts its timeout
+// If the mutation has pre hooks, they run first, and the mutation is only emitted if they succeed
+// within the timeout; the timeout then starts over for the mutation itself.
+// assumes p.mutex is locked by surrounding func
+// LOCKS: graphMutex (R) via hooksFor; path.mutex then activeMutex once the hooks have run (goroutine)
+func (sme *StateMutationEngine) emitCurrent(p *mutationPath) {
+	mut := p.chain[p.cur].mut
+	sme.Logf(DDEBUG, "firing mutation in context, timeout %s.", mut.Timeout().String())
+	if mut.Timeout() != 0 {
+		p.timer = time.AfterFunc(mut.Timeout(), func() { sme.emitFail(p.start, p) })
+	}
+	hooks := sme.hooksFor(MutationHook_PRE, mut)
+	if len(hooks) == 0 {
+		sme.emitMutation(p.end, p.start, mut)
+		return
+	}
+	cur, cfg, dsc := p.cur, p.end, p.start
+	// the hooks are stopped when the mutation would time out
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if mut.Timeout() != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), mut.Timeout())
+	}
+	go func() {
+		e := sme.runHooks(ctx, MutationHook_PRE, hooks, mut, cfg, dsc)
+		cancel()
+		p.mutex.Lock()
+		sme.activeMutex.Lock()
+		active := sme.active[p.start.ID().String()] == p
+		sme.activeMutex.Unlock()
+		if !active || p.cur != cur {
+			// the path was dropped or moved on while the hooks ran
+			p.mutex.Unlock()
+			return
+		}
+		if p.timer != nil && !p.timer.Stop() {
+			// we already timed out
+			p.mutex.Unlock()
+			return
+		}
+		if e == nil {
+			sme.emitMutation(cfg, dsc, mut)
+			if p.timer != nil {
+				p.timer.Reset(mut.Timeout())
+			}
+			p.mutex.Unlock()
+			return
+		}
+		sme.Logf(ERROR, "not mutating %s: %v", cfg.ID().String(), e)
+		p.mutex.Unlock()
+		sme.emitFail(dsc, p)
+	}()
+}
+
+// runPostHooks runs the post hooks for a mutation that has completed
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) runPostHooks(p *mutationPath) {
+	mut := p.chain[p.cur].mut
+	hooks := sme.hooksFor(MutationHook_POST, mut)
+	if len(hooks) == 0 {
+		return
+	}
+	cfg := p.end
+	go func() {
+		dsc, e := sme.query.ReadDsc(cfg.ID())
+		if e == nil {
+			e = sme.runHooks(context.Background(), MutationHook_POST, hooks, mut, cfg, dsc)
+		}
+		if e != nil {
+			sme.Logf(ERROR, "post mutation hook for %s: %v", cfg.ID().String(), e)
+		}
+	}()
+}
+
+// hooksFor gets the hooks for a mutation; a module's hooks run before those for the specific mutation
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) hooksFor(point MutationHookPoint, m lib.StateMutation) (r []MutationHook) {
+	sme.graphMutex.RLock()
+	mr := sme.mutResolver[m]
+	sme.graphMutex.RUnlock()
+	r = append(r, Registry.MutationHooks[point][mr[0]]...)
+	return append(r, Registry.MutationHooks[point][mr[0]+":"+mr[1]]...)
+}
+
+// runHooks runs hooks in order, stopping at the first that fails
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) runHooks(ctx context.Context, point MutationHookPoint, hooks []MutationHook, m lib.StateMutation, cfg, dsc lib.Node) error {
+	sme.graphMutex.RLock()
+	mr := sme.mutResolver[m]
+	sme.graphMutex.RUnlock()
+	for _, h := range hooks {
+		sme.Logf(DEBUG, "running %s mutation hook for %s: %s:%s", MutationHookPointString[point], cfg.ID().String(), mr[0], mr[1])
+		if e := h(ctx, mr, cfg, dsc); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// retryMutation re-fires the mutation a path was on when it timed out, if the path is still waiting on it
+// LOCKS: path.mutex; activeMutex (while holding path.mutex, see activeMutex); throttleMutex via fireMutation
+func (sme *StateMutationEngine) retryMutation(p *mutationPath, cur int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	sme.activeMutex.Lock()
+	active := !sme.freeze && sme.active[p.start.ID().String()] == p
+	sme.activeMutex.Unlock()
+	if !active || p.cur != cur {
+		// the path was dropped or moved on (or we were frozen) while we waited
+		return
+	}
+	sme.fireMutation(p)
+}
+
+// retire marks a node that has reached the end of its final mutations as decommissioned.
+// If the node has RemoveDecommissioned set, it is then archived and removed from the state.
+// Nothing happens unless the node is being decommissioned.
+func (sme *StateMutationEngine) retire(cfg lib.Node) {
+	if cfg.Lifecycle() != pb.Node_DECOMMISSIONING {
+		return
+	}
+	nid := cfg.ID()
+	// this makes state changes that come back to us, so it can't block
+	go func() {
+		_, e := sme.query.SetValue(lib.NodeURLJoin(nid.String(), "/Lifecycle"), reflect.ValueOf(pb.Node_DECOMMISSIONED))
+		if e != nil {
+			sme.Logf(ERROR, "couldn't mark %s decommissioned: %v", nid.String(), e)
+			return
+		}
+		sme.Logf(INFO, "%s is decommissioned", nid.String())
+		n, e := sme.query.Read(nid)
+		if e != nil {
+			return
+		}
+		if !n.Message().(*pb.Node).RemoveDecommissioned {
+			return
+		}
+		if sme.archive == "" {
+			sme.Logf(ERROR, "not removing %s: no archive is configured", nid.String())
+			return
+		}
+		if e = sme.archiveNode(n); e != nil {
+			sme.Logf(ERROR, "not removing %s: couldn't archive it: %v", nid.String(), e)
+			return
+		}
+		if _, e = sme.query.Delete(nid); e != nil {
+			sme.Logf(ERROR, "couldn't remove %s: %v", nid.String(), e)
+			return
+		}
+		sme.Logf(INFO, "%s has been archived and removed", nid.String())
+	}()
+}
+
+// archiveNode writes a snapshot of a node to <archive>/<id>.json
+func (sme *StateMutationEngine) archiveNode(n lib.Node) (e error) {
+	f, e := os.Create(filepath.Join(sme.archive, n.ID().String()+".json"))
+	if e != nil {
+		return
+	}
+	if e = WriteSnapshot(f, []lib.Node{n}); e != nil {
+		f.Close()
+		return
+	}
+	return f.Close()
+}
+
+// countMutation updates the metrics for a mutation with f
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) countMutation(mut lib.StateMutation, f func(*pb.MutationEdgeMetrics)) {
+	sme.graphMutex.RLock()
+	mr := sme.mutResolver[mut]
+	sme.graphMutex.RUnlock()
+	sme.metrics.count(mr, f)
+}
+
+// releaseMutation gives up a path's throttle slot (or its place in line) because its current mutation is done
+// assumes p.mutex is locked by surrounding func
+// LOCKS: throttleMutex; the next waiting path's mutex then activeMutex (goroutine)
+func (sme *StateMutationEngine) releaseMutation(p *mutationPath) {
+	sme.throttleMutex.Lock()
+	t := p.throttle
+	p.throttle = nil
+	if p.queued != nil {
+		q := p.queued
+		for i, w := range q.waiting {
+			if w == p {
+				q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+				break
+			}
+		}
+		p.queued = nil
+	}
+	if t == nil {
+		sme.throttleMutex.Unlock()
+		return
+	}
+	t.running--
+	if len(t.waiting) == 0 || t.running >= t.max { // emergencies may have pushed us over
+		sme.throttleMutex.Unlock()
+		return
+	}
+	// hand our slot to the next in line
+	w := t.waiting[0]
+	t.waiting = t.waiting[1:]
+	w.queued = nil
+	w.throttle = t
+	t.running++
+	sme.throttleMutex.Unlock()
+	// we can't take w's lock while we hold p's
+	go func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		sme.activeMutex.Lock()
+		active := sme.active[w.start.ID().String()] == w
+		sme.activeMutex.Unlock()
+		if !active || w.cur >= len(w.chain) {
+			// this path was dropped while it waited
+			sme.releaseMutation(w)
+			return
+		}
+		sme.Logf(DEBUG, "mutation for %s is no longer throttled", w.start.ID().String())
+		sme.emitCurrent(w)
+	}()
+}
+
+// throttleFor finds the throttle that applies to a mutation, or nil if it isn't throttled
+// LOCKS: graphMutex (R); throttleMutex
+func (sme *StateMutationEngine) throttleFor(m lib.StateMutation) *mutationThrottle {
+	sme.graphMutex.RLock()
+	r := sme.mutResolver[m]
+	sme.graphMutex.RUnlock()
+	sme.throttleMutex.Lock()
+	defer sme.throttleMutex.Unlock()
+	if t, ok := sme.throttles[r[0]+":"+r[1]]; ok {
+		return t
+	}
+	return sme.throttles[r[0]]
+}
+
+// resetThrottles empties all throttles, e.g. when active mutations are forgotten
+// LOCKS: throttleMutex
+func (sme *StateMutationEngine) resetThrottles() {
+	sme.throttleMutex.Lock()
+	defer sme.throttleMutex.Unlock()
+	sme.throttles = make(map[string]*mutationThrottle)
+	for k, max := range sme.throttleCfg {
+		if max > 0 {
+			sme.throttles[k] = &mutationThrottle{max: max}
+		}
+	}
+}
+
+// watching reports whether we care about state changes to a URL
+// LOCKS: graphMutex (R)
+func (sme *StateMutationEngine) watching(url string) bool {
+	sme.graphMutex.RLock()
+	defer sme.graphMutex.RUnlock()
+	for m := range sme.mutators { // NOTE: doesn't fix beginning slashes, etc
+		if url == m {
+			return true
+		}
+	}
+	if url == "" { // this should mean we got CREATE/DELETE
+		return true
+	}
+	if url == "/Frozen" || url == "/Lifecycle" { // we need to start (or stop) mutating
+		return true
+	}
+	return false
+}
+
 // LOCKS: graphMutex (R)
 func (sme *StateMutationEngine) emitMutation(cfg lib.Node, dsc lib.Node, sm lib.StateMutation) {
 	sme.graphMutex.RLock()
@@ -1224,6 +1829,7 @@ func (sme *StateMutationEngine) emitMutation(cfg lib.Node, dsc lib.Node, sm lib.
 		Mutation: sme.mutResolver[sm],
 	}
 	sme.graphMutex.RUnlock()
+	sme.metrics.count(smee.Mutation, func(em *pb.MutationEdgeMetrics) { em.Started++ })
 	v := NewEvent(
 		lib.Event_STATE_MUTATION,
 		cfg.ID().String(),
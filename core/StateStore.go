@@ -0,0 +1,188 @@
+/* StateStore.go: StateStores persist node state so that it survives a restart
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hpc/kraken/lib"
+)
+
+///////////////////////
+// Auxiliary Objects /
+/////////////////////
+
+// A StateStore is a persistence backend for the StateDifferenceEngine.
+// The SDE saves whole nodes on every change, and loads everything back once at startup.
+// dsc selects the Discoverable state; otherwise the Configuration state is meant.
+type StateStore interface {
+	Load(dsc bool) ([]lib.Node, error)
+	Save(dsc bool, n lib.Node) error
+	Delete(dsc bool, nid lib.NodeID) error
+	Close() error
+}
+
+// A StateStoreEvent describes a change someone else made to a shared StateStore
+type StateStoreEvent struct {
+	ID     lib.NodeID
+	Node   lib.Node // the new version of the node; nil if Delete
+	Delete bool
+}
+
+// A WatchableStateStore can be shared, and tells us about changes to the configuration state.
+// Watch should only send events for changes made after the last Load.
+type WatchableStateStore interface {
+	StateStore
+	Watch(c chan<- StateStoreEvent) error
+}
+
+// A LeaderElector is a shared StateStore that can elect one of the kraken instances sharing it as the leader.
+// Only the leader mutates state; the others stand by, mirroring the configuration state.
+type LeaderElector interface {
+	WatchableStateStore
+	// Campaign blocks until id is the leader.  The returned channel is closed when we stop being the leader.
+	Campaign(id string) (<-chan bool, error)
+}
+
+// A StateStoreOpener opens a StateStore at a backend specific location (e.g. a path)
+type StateStoreOpener func(location string) (StateStore, error)
+
+// StateStores maps backend names to their openers
+var StateStores = map[string]StateStoreOpener{}
+
+// RegisterStateStore makes a StateStore backend available by name
+// It's probably a good idea for this to be done in init()
+func RegisterStateStore(name string, o StateStoreOpener) {
+	if _, ok := StateStores[name]; !ok {
+		StateStores[name] = o
+	}
+}
+
+// OpenStateStore opens a StateStore with a registered backend
+func OpenStateStore(backend, location string) (StateStore, error) {
+	o, ok := StateStores[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown state store backend: %s", backend)
+	}
+	return o(location)
+}
+
+////////////////////////////
+// FileStateStore Object /
+//////////////////////////
+
+var _ StateStore = (*FileStateStore)(nil)
+
+// A FileStateStore keeps one file per node, per state, in a directory:
+// <dir>/cfg/<node id> and <dir>/dsc/<node id>, each holding the node in its binary (proto) form.
+// Files are replaced by rename, so a crash leaves either the old or the new version of a node.
+type FileStateStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileStateStore opens (creating if needed) a FileStateStore in dir
+func NewFileStateStore(dir string) (StateStore, error) {
+	s := &FileStateStore{dir: dir}
+	for _, d := range []string{s.subdir(false), s.subdir(true)} {
+		if e := os.MkdirAll(d, 0700); e != nil {
+			return nil, fmt.Errorf("could not create state store: %v", e)
+		}
+	}
+	return s, nil
+}
+
+// Load reads all of the nodes in a state
+// files that can't be read as a node (e.g. they were truncated by a crash) are skipped
+func (s *FileStateStore) Load(dsc bool) (r []lib.Node, e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fs, e := ioutil.ReadDir(s.subdir(dsc))
+	if e != nil {
+		return
+	}
+	for _, f := range fs {
+		if f.IsDir() || filepath.Ext(f.Name()) == ".tmp" {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(s.subdir(dsc), f.Name()))
+		if err != nil {
+			continue
+		}
+		// an empty file unmarshals to a node with no ID
+		if n := NewNodeFromBinary(b); n != nil && !n.ID().Nil() {
+			r = append(r, n)
+		}
+	}
+	return
+}
+
+// Save writes a node
+// The node is synced to disk before it replaces the old version, so a crash leaves one or the other.
+func (s *FileStateStore) Save(dsc bool, n lib.Node) (e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	p := s.path(dsc, n.ID())
+	f, e := os.OpenFile(p+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if e != nil {
+		return
+	}
+	if _, e = f.Write(n.Binary()); e == nil {
+		e = f.Sync()
+	}
+	if ce := f.Close(); e == nil {
+		e = ce
+	}
+	if e != nil {
+		return
+	}
+	if e = os.Rename(p+".tmp", p); e != nil {
+		return
+	}
+	// the rename isn't durable until the directory is synced
+	d, e := os.Open(s.subdir(dsc))
+	if e != nil {
+		return
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Delete removes a node; it is not an error if the node isn't there
+func (s *FileStateStore) Delete(dsc bool, nid lib.NodeID) (e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e = os.Remove(s.path(dsc, nid)); os.IsNotExist(e) {
+		e = nil
+	}
+	return
+}
+
+// Close is a no-op, files are written synchronously
+func (s *FileStateStore) Close() error { return nil }
+
+func (s *FileStateStore) subdir(dsc bool) string {
+	if dsc {
+		return filepath.Join(s.dir, "dsc")
+	}
+	return filepath.Join(s.dir, "cfg")
+}
+
+func (s *FileStateStore) path(dsc bool, nid lib.NodeID) string {
+	return filepath.Join(s.subdir(dsc), nid.String())
+}
+
+func init() {
+	RegisterStateStore("file", NewFileStateStore)
+}
@@ -140,7 +140,8 @@ type StateSyncEngine struct {
 	em      *EventEmitter
 	log     lib.Logger
 	self    lib.NodeID
-	parents []string
+	parents []string // candidates, in order of preference; we sync with one at a time
+	parent  int      // index in parents of the one we're homed to (protected by lock)
 	conn    net.PacketConn
 	rpc     ContextRPC
 }
@@ -189,7 +190,10 @@ func (sse *StateSyncEngine) RPCPhoneHome(ctx context.Context, in *pb.PhoneHomeRe
 	if e != nil {
 		return
 	}
-	if v.Interface() != pb.Node_INIT {
+	if in.GetRehome() {
+		// a node that lost its parent won't be INIT, and we may not have been expecting it
+		sse.Logf(NOTICE, "re-homing orphaned node: %s was %v", id.String(), v.Interface())
+	} else if v.Interface() != pb.Node_INIT {
 		e = fmt.Errorf("attempted phone home out-of-turn: %s is %v", id.String(), v.Interface())
 		sse.Logf(NOTICE, "attempted phone home out-of-turn: %s is %v", id.String(), v.Interface())
 		return
@@ -275,11 +279,10 @@ func (sse *StateSyncEngine) Run() {
 		}
 	}(sse.rpc.NetListner, s)
 
-	for _, p := range sse.parents {
+	if len(sse.parents) > 0 {
 		// phone home
-		sse.callParent(p)
-	}
-	if len(sse.parents) < 1 {
+		sse.callParent(0, false)
+	} else {
 		sse.Log(INFO, "no parents specified, I will run as a full-state node")
 	}
 
@@ -326,26 +329,29 @@ func (sse *StateSyncEngine) Run() {
 //////////////////////
 
 // this is an important one!  phone home to your parent, setup upward sync
-func (sse *StateSyncEngine) callParent(p string) {
+// i is the index of the parent to try in sse.parents; if it doesn't answer, we move on to the next one.
+// rehome is set if we're looking for a new parent after losing one.
+func (sse *StateSyncEngine) callParent(i int, rehome bool) {
+	p := sse.parents[i]
 	retryCall := func() {
-		sse.Logf(INFO, "retrying phone home in 10s to: %s", p)
+		next := (i + 1) % len(sse.parents)
+		sse.Logf(INFO, "retrying phone home in 10s to: %s", sse.parents[next])
 		time.Sleep(10 * time.Second)
-		sse.callParent(p)
+		sse.callParent(next, rehome)
 	}
 
 	sse.Logf(INFO, "attempting to phone home to: %s", p)
 	conn, e := grpc.Dial(p+":"+strconv.Itoa(sse.rpc.Port), grpc.WithInsecure())
 	if e != nil {
-		sse.Logf(CRITICAL, "phone home to (%s) failed: p, %v", e)
+		sse.Logf(CRITICAL, "phone home to (%s) failed: %v", p, e)
 		go retryCall()
-		// TODO: retry phone home?
 		return
 	}
 	defer conn.Close()
 	c := pb.NewStateSyncClient(conn)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	r, e := c.RPCPhoneHome(ctx, &pb.PhoneHomeRequest{Id: sse.self.Binary()})
+	r, e := c.RPCPhoneHome(ctx, &pb.PhoneHomeRequest{Id: sse.self.Binary(), Rehome: rehome})
 	if e != nil {
 		sse.Logf(CRITICAL, "could not phone home: %v", e)
 		go retryCall()
@@ -392,6 +398,9 @@ func (sse *StateSyncEngine) callParent(p string) {
 	pn.SetValue(sse.cfg.AddrURL, reflect.ValueOf([]byte(net.ParseIP(p).To4())))
 	sse.query.Create(pn)
 
+	sse.lock.Lock()
+	sse.parent = i
+	sse.lock.Unlock()
 	n.recv()
 }
 
@@ -634,6 +643,12 @@ func (sse *StateSyncEngine) sync(n *stateSyncNeighbor) {
 			)
 			sse.EmitOne(ev)
 			sse.delNeighbor(n.getID())
+			// find a new home; our state is re-synced from whoever takes us
+			sse.lock.RLock()
+			next := (sse.parent + 1) % len(sse.parents)
+			sse.lock.RUnlock()
+			sse.Logf(INFO, "re-homing to: %s", sse.parents[next])
+			go sse.callParent(next, true)
 		} else {
 
 			// before we assume this node went to error, make sure it's actually in SYNC
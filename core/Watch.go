@@ -0,0 +1,62 @@
+/* Watch.go: watches stream state changes that match a filter, so consumers don't have to poll
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"reflect"
+	"strings"
+
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// NewStateChange makes the StateChange (delta) for a state change event
+// v is the new value; state change events don't always carry it, so it may have been looked up
+func NewStateChange(sce *StateChangeEvent, v reflect.Value) *pb.StateChange {
+	node, url := lib.NodeURLSplit(sce.URL)
+	c := &pb.StateChange{
+		Type: StateChangeTypeString[sce.Type],
+		Node: node,
+		Url:  url,
+	}
+	if v.IsValid() && sce.Type != StateChange_CREATE && sce.Type != StateChange_DELETE {
+		c.Value = lib.ValueToString(v)
+	}
+	return c
+}
+
+// WatchMatch reports whether a StateChange is one a watch asked for
+func WatchMatch(w *pb.WatchRequest, c *pb.StateChange) bool {
+	if w.Node != "" && !NewNodeID(w.Node).Equal(NewNodeID(c.Node)) {
+		return false
+	}
+	if w.Url != "" {
+		p := strings.TrimRight(w.Url, "/")
+		if c.Url != p && !strings.HasPrefix(c.Url, p+"/") {
+			return false
+		}
+	}
+	if w.Value != "" && c.Value != w.Value {
+		return false
+	}
+	if len(w.Types) > 0 {
+		found := false
+		for _, t := range w.Types {
+			if t == c.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
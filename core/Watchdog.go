@@ -0,0 +1,91 @@
+/* Watchdog.go: tells systemd when we're ready, and keeps its watchdog fed while we're healthy
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package core
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SystemdNotify sends a state (e.g. READY=1) to systemd, if we were started by it with Type=notify.
+// It's a no-op if we weren't.
+func SystemdNotify(state string) error {
+	sock := os.Getenv("NOTIFY_SOCKET")
+	if sock == "" {
+		return nil
+	}
+	if sock[0] == '@' { // an abstract socket
+		sock = "\x00" + sock[1:]
+	}
+	c, e := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if e != nil {
+		return fmt.Errorf("could not connect to systemd: %v", e)
+	}
+	defer c.Close()
+	_, e = c.Write([]byte(state))
+	return e
+}
+
+// systemdWatchdog gets how often systemd wants to hear from us (WatchdogSec), or 0 if it doesn't
+func systemdWatchdog() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, e := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if e != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Healthy checks that the core is answering queries, by reading ourself within timeout.
+// It's what the systemd watchdog goes by.
+func (k *Kraken) Healthy(timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, e := k.Ctx.Query.Read(k.Ctx.Self)
+		done <- e
+	}()
+	select {
+	case e := <-done:
+		return e
+	case <-time.After(timeout):
+		return fmt.Errorf("core did not answer a query in %v", timeout)
+	}
+}
+
+// Watchdog tells systemd we're ready, and, if it has a watchdog on us, lets it know we're alive twice as often as it asks, as long as we're healthy.
+// If we stop being healthy, systemd will notice, and restart us (depending on the unit's Restart=).
+func (k *Kraken) Watchdog() {
+	if e := SystemdNotify("READY=1"); e != nil {
+		k.Logf(ERROR, "failed to notify systemd that we're ready: %v", e)
+		return
+	}
+	wd := systemdWatchdog()
+	if wd == 0 {
+		return
+	}
+	k.Logf(INFO, "feeding the systemd watchdog every %v", wd/2)
+	go func() {
+		for {
+			time.Sleep(wd / 2)
+			if e := k.Healthy(wd / 2); e != nil {
+				k.Logf(ERROR, "not feeding the systemd watchdog: %v", e)
+				continue
+			}
+			if e := SystemdNotify("WATCHDOG=1"); e != nil {
+				k.Logf(ERROR, "failed to feed the systemd watchdog: %v", e)
+			}
+		}
+	}()
+}
@@ -8,6 +8,7 @@ import fmt "fmt"
 import math "math"
 import any "github.com/golang/protobuf/ptypes/any"
 import empty "github.com/golang/protobuf/ptypes/empty"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -28,27 +29,30 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type ServiceControl_Command int32
 
 const (
-	ServiceControl_STOP   ServiceControl_Command = 0
-	ServiceControl_UPDATE ServiceControl_Command = 1
-	ServiceControl_INIT   ServiceControl_Command = 2
+	ServiceControl_STOP     ServiceControl_Command = 0
+	ServiceControl_UPDATE   ServiceControl_Command = 1
+	ServiceControl_INIT     ServiceControl_Command = 2
+	ServiceControl_DISCOVER ServiceControl_Command = 3
 )
 
 var ServiceControl_Command_name = map[int32]string{
 	0: "STOP",
 	1: "UPDATE",
 	2: "INIT",
+	3: "DISCOVER",
 }
 var ServiceControl_Command_value = map[string]int32{
-	"STOP":   0,
-	"UPDATE": 1,
-	"INIT":   2,
+	"STOP":     0,
+	"UPDATE":   1,
+	"INIT":     2,
+	"DISCOVER": 3,
 }
 
 func (x ServiceControl_Command) String() string {
 	return proto.EnumName(ServiceControl_Command_name, int32(x))
 }
 func (ServiceControl_Command) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{3, 0}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{3, 0}
 }
 
 type MutationControl_Type int32
@@ -71,7 +75,39 @@ func (x MutationControl_Type) String() string {
 	return proto.EnumName(MutationControl_Type_name, int32(x))
 }
 func (MutationControl_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{4, 0}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{4, 0}
+}
+
+type ServiceStatus_Status int32
+
+const (
+	ServiceStatus_STOPPED    ServiceStatus_Status = 0
+	ServiceStatus_STARTING   ServiceStatus_Status = 1
+	ServiceStatus_RUNNING    ServiceStatus_Status = 2
+	ServiceStatus_RESTARTING ServiceStatus_Status = 3
+	ServiceStatus_CRASHED    ServiceStatus_Status = 4
+)
+
+var ServiceStatus_Status_name = map[int32]string{
+	0: "STOPPED",
+	1: "STARTING",
+	2: "RUNNING",
+	3: "RESTARTING",
+	4: "CRASHED",
+}
+var ServiceStatus_Status_value = map[string]int32{
+	"STOPPED":    0,
+	"STARTING":   1,
+	"RUNNING":    2,
+	"RESTARTING": 3,
+	"CRASHED":    4,
+}
+
+func (x ServiceStatus_Status) String() string {
+	return proto.EnumName(ServiceStatus_Status_name, int32(x))
+}
+func (ServiceStatus_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{32, 0}
 }
 
 type Query struct {
@@ -92,7 +128,7 @@ func (m *Query) Reset()         { *m = Query{} }
 func (m *Query) String() string { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()    {}
 func (*Query) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{0}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{0}
 }
 func (m *Query) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Query.Unmarshal(m, b)
@@ -333,7 +369,7 @@ func (m *QueryMulti) Reset()         { *m = QueryMulti{} }
 func (m *QueryMulti) String() string { return proto.CompactTextString(m) }
 func (*QueryMulti) ProtoMessage()    {}
 func (*QueryMulti) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{1}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{1}
 }
 func (m *QueryMulti) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMulti.Unmarshal(m, b)
@@ -372,7 +408,7 @@ func (m *ServiceInitRequest) Reset()         { *m = ServiceInitRequest{} }
 func (m *ServiceInitRequest) String() string { return proto.CompactTextString(m) }
 func (*ServiceInitRequest) ProtoMessage()    {}
 func (*ServiceInitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{2}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{2}
 }
 func (m *ServiceInitRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServiceInitRequest.Unmarshal(m, b)
@@ -409,6 +445,8 @@ func (m *ServiceInitRequest) GetModule() string {
 type ServiceControl struct {
 	Command              ServiceControl_Command `protobuf:"varint,1,opt,name=command,proto3,enum=proto.ServiceControl_Command" json:"command,omitempty"`
 	Config               *any.Any               `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Deadline             *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	Nodes                []string               `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
 	XXX_unrecognized     []byte                 `json:"-"`
 	XXX_sizecache        int32                  `json:"-"`
@@ -418,7 +456,7 @@ func (m *ServiceControl) Reset()         { *m = ServiceControl{} }
 func (m *ServiceControl) String() string { return proto.CompactTextString(m) }
 func (*ServiceControl) ProtoMessage()    {}
 func (*ServiceControl) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{3}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{3}
 }
 func (m *ServiceControl) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServiceControl.Unmarshal(m, b)
@@ -452,12 +490,27 @@ func (m *ServiceControl) GetConfig() *any.Any {
 	return nil
 }
 
+func (m *ServiceControl) GetDeadline() *timestamp.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+func (m *ServiceControl) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
 type MutationControl struct {
 	Module               string               `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Id                   string               `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	Type                 MutationControl_Type `protobuf:"varint,3,opt,name=type,proto3,enum=proto.MutationControl_Type" json:"type,omitempty"`
 	Cfg                  *Node                `protobuf:"bytes,4,opt,name=cfg,proto3" json:"cfg,omitempty"`
 	Dsc                  *Node                `protobuf:"bytes,5,opt,name=dsc,proto3" json:"dsc,omitempty"`
+	Seq                  uint64               `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -467,7 +520,7 @@ func (m *MutationControl) Reset()         { *m = MutationControl{} }
 func (m *MutationControl) String() string { return proto.CompactTextString(m) }
 func (*MutationControl) ProtoMessage()    {}
 func (*MutationControl) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{4}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{4}
 }
 func (m *MutationControl) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationControl.Unmarshal(m, b)
@@ -522,20 +575,75 @@ func (m *MutationControl) GetDsc() *Node {
 	return nil
 }
 
-type DiscoveryEvent struct {
+func (m *MutationControl) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+// A MutationAckRequest tells a mutation queue that a module has taken everything up to seq
+type MutationAckRequest struct {
 	Module               string   `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
-	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	ValueId              string   `protobuf:"bytes,3,opt,name=value_id,json=valueId,proto3" json:"value_id,omitempty"`
+	Seq                  uint64   `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *MutationAckRequest) Reset()         { *m = MutationAckRequest{} }
+func (m *MutationAckRequest) String() string { return proto.CompactTextString(m) }
+func (*MutationAckRequest) ProtoMessage()    {}
+func (*MutationAckRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{5}
+}
+func (m *MutationAckRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutationAckRequest.Unmarshal(m, b)
+}
+func (m *MutationAckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutationAckRequest.Marshal(b, m, deterministic)
+}
+func (dst *MutationAckRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutationAckRequest.Merge(dst, src)
+}
+func (m *MutationAckRequest) XXX_Size() int {
+	return xxx_messageInfo_MutationAckRequest.Size(m)
+}
+func (m *MutationAckRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutationAckRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MutationAckRequest proto.InternalMessageInfo
+
+func (m *MutationAckRequest) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *MutationAckRequest) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+type DiscoveryEvent struct {
+	Module               string               `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Url                  string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	ValueId              string               `protobuf:"bytes,3,opt,name=value_id,json=valueId,proto3" json:"value_id,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
 func (m *DiscoveryEvent) Reset()         { *m = DiscoveryEvent{} }
 func (m *DiscoveryEvent) String() string { return proto.CompactTextString(m) }
 func (*DiscoveryEvent) ProtoMessage()    {}
 func (*DiscoveryEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{5}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{6}
 }
 func (m *DiscoveryEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DiscoveryEvent.Unmarshal(m, b)
@@ -576,6 +684,13 @@ func (m *DiscoveryEvent) GetValueId() string {
 	return ""
 }
 
+func (m *DiscoveryEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
 type MutationNodeList struct {
 	MutationNodeList     []*MutationNode `protobuf:"bytes,1,rep,name=MutationNodeList,proto3" json:"MutationNodeList,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
@@ -587,7 +702,7 @@ func (m *MutationNodeList) Reset()         { *m = MutationNodeList{} }
 func (m *MutationNodeList) String() string { return proto.CompactTextString(m) }
 func (*MutationNodeList) ProtoMessage()    {}
 func (*MutationNodeList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{6}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{7}
 }
 func (m *MutationNodeList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationNodeList.Unmarshal(m, b)
@@ -625,7 +740,7 @@ func (m *MutationEdgeList) Reset()         { *m = MutationEdgeList{} }
 func (m *MutationEdgeList) String() string { return proto.CompactTextString(m) }
 func (*MutationEdgeList) ProtoMessage()    {}
 func (*MutationEdgeList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{7}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{8}
 }
 func (m *MutationEdgeList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationEdgeList.Unmarshal(m, b)
@@ -653,18 +768,23 @@ func (m *MutationEdgeList) GetMutationEdgeList() []*MutationEdge {
 }
 
 type MutationPath struct {
-	Cur                  int64           `protobuf:"varint,1,opt,name=cur,proto3" json:"cur,omitempty"`
-	Chain                []*MutationEdge `protobuf:"bytes,2,rep,name=chain,proto3" json:"chain,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Cur                  int64                `protobuf:"varint,1,opt,name=cur,proto3" json:"cur,omitempty"`
+	Chain                []*MutationEdge      `protobuf:"bytes,2,rep,name=chain,proto3" json:"chain,omitempty"`
+	Started              *timestamp.Timestamp `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	CurStarted           *timestamp.Timestamp `protobuf:"bytes,4,opt,name=cur_started,json=curStarted,proto3" json:"cur_started,omitempty"`
+	Tries                uint32               `protobuf:"varint,5,opt,name=tries,proto3" json:"tries,omitempty"`
+	Throttle             string               `protobuf:"bytes,6,opt,name=throttle,proto3" json:"throttle,omitempty"`
+	Rollback             bool                 `protobuf:"varint,7,opt,name=rollback,proto3" json:"rollback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *MutationPath) Reset()         { *m = MutationPath{} }
 func (m *MutationPath) String() string { return proto.CompactTextString(m) }
 func (*MutationPath) ProtoMessage()    {}
 func (*MutationPath) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{8}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{9}
 }
 func (m *MutationPath) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationPath.Unmarshal(m, b)
@@ -698,20 +818,57 @@ func (m *MutationPath) GetChain() []*MutationEdge {
 	return nil
 }
 
+func (m *MutationPath) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *MutationPath) GetCurStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.CurStarted
+	}
+	return nil
+}
+
+func (m *MutationPath) GetTries() uint32 {
+	if m != nil {
+		return m.Tries
+	}
+	return 0
+}
+
+func (m *MutationPath) GetThrottle() string {
+	if m != nil {
+		return m.Throttle
+	}
+	return ""
+}
+
+func (m *MutationPath) GetRollback() bool {
+	if m != nil {
+		return m.Rollback
+	}
+	return false
+}
+
 type MutationNode struct {
-	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Color                string   `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Label                string            `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Id                   string            `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Color                string            `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	Requires             map[string]string `protobuf:"bytes,4,rep,name=requires,proto3" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Excludes             map[string]string `protobuf:"bytes,5,rep,name=excludes,proto3" json:"excludes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *MutationNode) Reset()         { *m = MutationNode{} }
 func (m *MutationNode) String() string { return proto.CompactTextString(m) }
 func (*MutationNode) ProtoMessage()    {}
 func (*MutationNode) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{9}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{10}
 }
 func (m *MutationNode) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationNode.Unmarshal(m, b)
@@ -752,21 +909,42 @@ func (m *MutationNode) GetColor() string {
 	return ""
 }
 
+func (m *MutationNode) GetRequires() map[string]string {
+	if m != nil {
+		return m.Requires
+	}
+	return nil
+}
+
+func (m *MutationNode) GetExcludes() map[string]string {
+	if m != nil {
+		return m.Excludes
+	}
+	return nil
+}
+
 type MutationEdge struct {
-	From                 string     `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	To                   string     `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
-	Id                   string     `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
-	Color                *EdgeColor `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	From                 string            `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string            `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Id                   string            `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Color                *EdgeColor        `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Module               string            `protobuf:"bytes,5,opt,name=module,proto3" json:"module,omitempty"`
+	Mutation             string            `protobuf:"bytes,6,opt,name=mutation,proto3" json:"mutation,omitempty"`
+	Timeout              string            `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Mutates              map[string]string `protobuf:"bytes,8,rep,name=mutates,proto3" json:"mutates,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Requires             map[string]string `protobuf:"bytes,9,rep,name=requires,proto3" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Excludes             map[string]string `protobuf:"bytes,10,rep,name=excludes,proto3" json:"excludes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Cost                 uint32            `protobuf:"varint,11,opt,name=cost,proto3" json:"cost,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *MutationEdge) Reset()         { *m = MutationEdge{} }
 func (m *MutationEdge) String() string { return proto.CompactTextString(m) }
 func (*MutationEdge) ProtoMessage()    {}
 func (*MutationEdge) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{10}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{11}
 }
 func (m *MutationEdge) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MutationEdge.Unmarshal(m, b)
@@ -814,6 +992,55 @@ func (m *MutationEdge) GetColor() *EdgeColor {
 	return nil
 }
 
+func (m *MutationEdge) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *MutationEdge) GetMutation() string {
+	if m != nil {
+		return m.Mutation
+	}
+	return ""
+}
+
+func (m *MutationEdge) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+func (m *MutationEdge) GetMutates() map[string]string {
+	if m != nil {
+		return m.Mutates
+	}
+	return nil
+}
+
+func (m *MutationEdge) GetRequires() map[string]string {
+	if m != nil {
+		return m.Requires
+	}
+	return nil
+}
+
+func (m *MutationEdge) GetExcludes() map[string]string {
+	if m != nil {
+		return m.Excludes
+	}
+	return nil
+}
+
+func (m *MutationEdge) GetCost() uint32 {
+	if m != nil {
+		return m.Cost
+	}
+	return 0
+}
+
 // This is only nessessary for the json mutation edge color to output in the correct format for the dashboard
 type EdgeColor struct {
 	Color                string   `protobuf:"bytes,1,opt,name=color,proto3" json:"color,omitempty"`
@@ -828,7 +1055,7 @@ func (m *EdgeColor) Reset()         { *m = EdgeColor{} }
 func (m *EdgeColor) String() string { return proto.CompactTextString(m) }
 func (*EdgeColor) ProtoMessage()    {}
 func (*EdgeColor) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{11}
+	return fileDescriptor_API_d34ab7906403fbbd, []int{12}
 }
 func (m *EdgeColor) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EdgeColor.Unmarshal(m, b)
@@ -869,666 +1096,2639 @@ func (m *EdgeColor) GetInherit() bool {
 	return false
 }
 
-type LogMessage struct {
-	Origin               string   `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
-	Level                uint32   `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
-	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+// An EventRecord is an entry in the event log
+type EventRecord struct {
+	Time                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Type                 string               `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Node                 string               `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	Url                  string               `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Module               string               `protobuf:"bytes,5,opt,name=module,proto3" json:"module,omitempty"`
+	Data                 string               `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *LogMessage) Reset()         { *m = LogMessage{} }
-func (m *LogMessage) String() string { return proto.CompactTextString(m) }
-func (*LogMessage) ProtoMessage()    {}
-func (*LogMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_API_29aad8d6be19b05f, []int{12}
+func (m *EventRecord) Reset()         { *m = EventRecord{} }
+func (m *EventRecord) String() string { return proto.CompactTextString(m) }
+func (*EventRecord) ProtoMessage()    {}
+func (*EventRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{13}
 }
-func (m *LogMessage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogMessage.Unmarshal(m, b)
+func (m *EventRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventRecord.Unmarshal(m, b)
 }
-func (m *LogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogMessage.Marshal(b, m, deterministic)
+func (m *EventRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventRecord.Marshal(b, m, deterministic)
 }
-func (dst *LogMessage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogMessage.Merge(dst, src)
+func (dst *EventRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventRecord.Merge(dst, src)
 }
-func (m *LogMessage) XXX_Size() int {
-	return xxx_messageInfo_LogMessage.Size(m)
+func (m *EventRecord) XXX_Size() int {
+	return xxx_messageInfo_EventRecord.Size(m)
 }
-func (m *LogMessage) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogMessage.DiscardUnknown(m)
+func (m *EventRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventRecord.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LogMessage proto.InternalMessageInfo
+var xxx_messageInfo_EventRecord proto.InternalMessageInfo
 
-func (m *LogMessage) GetOrigin() string {
+func (m *EventRecord) GetTime() *timestamp.Timestamp {
 	if m != nil {
-		return m.Origin
+		return m.Time
 	}
-	return ""
+	return nil
 }
 
-func (m *LogMessage) GetLevel() uint32 {
+func (m *EventRecord) GetType() string {
 	if m != nil {
-		return m.Level
+		return m.Type
 	}
-	return 0
+	return ""
 }
 
-func (m *LogMessage) GetMsg() string {
+func (m *EventRecord) GetNode() string {
 	if m != nil {
-		return m.Msg
+		return m.Node
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*Query)(nil), "proto.Query")
-	proto.RegisterType((*QueryMulti)(nil), "proto.QueryMulti")
-	proto.RegisterType((*ServiceInitRequest)(nil), "proto.ServiceInitRequest")
-	proto.RegisterType((*ServiceControl)(nil), "proto.ServiceControl")
-	proto.RegisterType((*MutationControl)(nil), "proto.MutationControl")
-	proto.RegisterType((*DiscoveryEvent)(nil), "proto.DiscoveryEvent")
-	proto.RegisterType((*MutationNodeList)(nil), "proto.MutationNodeList")
-	proto.RegisterType((*MutationEdgeList)(nil), "proto.MutationEdgeList")
-	proto.RegisterType((*MutationPath)(nil), "proto.MutationPath")
-	proto.RegisterType((*MutationNode)(nil), "proto.MutationNode")
-	proto.RegisterType((*MutationEdge)(nil), "proto.MutationEdge")
-	proto.RegisterType((*EdgeColor)(nil), "proto.EdgeColor")
-	proto.RegisterType((*LogMessage)(nil), "proto.LogMessage")
-	proto.RegisterEnum("proto.ServiceControl_Command", ServiceControl_Command_name, ServiceControl_Command_value)
-	proto.RegisterEnum("proto.MutationControl_Type", MutationControl_Type_name, MutationControl_Type_value)
+func (m *EventRecord) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *EventRecord) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
 
-// APIClient is the client API for API service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type APIClient interface {
-	// Query language
-	// TODO: create API for bulk CRUD operations
-	QueryCreate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryRead(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryReadDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryUpdate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryUpdateDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryDelete(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryReadAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
-	QueryReadAllDsc(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
-	QueryMutationNodes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error)
-	QueryMutationEdges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error)
-	QueryNodeMutationNodes(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryNodeMutationEdges(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryNodeMutationPath(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
-	QueryDeleteAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
-	// Service management
-	ServiceInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_ServiceInitClient, error)
-	// Mutation/Discover management
-	MutationInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_MutationInitClient, error)
-	// Discovery management
-	DiscoveryInit(ctx context.Context, opts ...grpc.CallOption) (API_DiscoveryInitClient, error)
-	// Logging
-	LoggerInit(ctx context.Context, opts ...grpc.CallOption) (API_LoggerInitClient, error)
+func (m *EventRecord) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
 }
 
-type aPIClient struct {
-	cc *grpc.ClientConn
+type EventRecordList struct {
+	Records              []*EventRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func NewAPIClient(cc *grpc.ClientConn) APIClient {
-	return &aPIClient{cc}
+func (m *EventRecordList) Reset()         { *m = EventRecordList{} }
+func (m *EventRecordList) String() string { return proto.CompactTextString(m) }
+func (*EventRecordList) ProtoMessage()    {}
+func (*EventRecordList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{14}
+}
+func (m *EventRecordList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventRecordList.Unmarshal(m, b)
+}
+func (m *EventRecordList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventRecordList.Marshal(b, m, deterministic)
+}
+func (dst *EventRecordList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventRecordList.Merge(dst, src)
+}
+func (m *EventRecordList) XXX_Size() int {
+	return xxx_messageInfo_EventRecordList.Size(m)
+}
+func (m *EventRecordList) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventRecordList.DiscardUnknown(m)
 }
 
-func (c *aPIClient) QueryCreate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryCreate", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_EventRecordList proto.InternalMessageInfo
+
+func (m *EventRecordList) GetRecords() []*EventRecord {
+	if m != nil {
+		return m.Records
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) QueryRead(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryRead", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type EventLogQuery struct {
+	Node                 string               `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	From                 *timestamp.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (c *aPIClient) QueryReadDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryReadDsc", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *EventLogQuery) Reset()         { *m = EventLogQuery{} }
+func (m *EventLogQuery) String() string { return proto.CompactTextString(m) }
+func (*EventLogQuery) ProtoMessage()    {}
+func (*EventLogQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{15}
+}
+func (m *EventLogQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventLogQuery.Unmarshal(m, b)
+}
+func (m *EventLogQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventLogQuery.Marshal(b, m, deterministic)
+}
+func (dst *EventLogQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventLogQuery.Merge(dst, src)
+}
+func (m *EventLogQuery) XXX_Size() int {
+	return xxx_messageInfo_EventLogQuery.Size(m)
+}
+func (m *EventLogQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventLogQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventLogQuery proto.InternalMessageInfo
+
+func (m *EventLogQuery) GetNode() string {
+	if m != nil {
+		return m.Node
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryUpdate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryUpdate", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *EventLogQuery) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) QueryUpdateDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryUpdateDsc", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *EventLogQuery) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) QueryDelete(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryDelete", in, out, opts...)
-	if err != nil {
-		return nil, err
+// An AuditRecord is an entry in the audit log: who made a state changing API call, and what it did to a node
+type AuditRecord struct {
+	Time                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	User                 string               `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Source               string               `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Module               string               `protobuf:"bytes,4,opt,name=module,proto3" json:"module,omitempty"`
+	Action               string               `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	Node                 string               `protobuf:"bytes,6,opt,name=node,proto3" json:"node,omitempty"`
+	Changes              []*AuditChange       `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`
+	Result               string               `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditRecord) Reset()         { *m = AuditRecord{} }
+func (m *AuditRecord) String() string { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()    {}
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{16}
+}
+func (m *AuditRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecord.Unmarshal(m, b)
+}
+func (m *AuditRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecord.Marshal(b, m, deterministic)
+}
+func (dst *AuditRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecord.Merge(dst, src)
+}
+func (m *AuditRecord) XXX_Size() int {
+	return xxx_messageInfo_AuditRecord.Size(m)
+}
+func (m *AuditRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecord proto.InternalMessageInfo
+
+func (m *AuditRecord) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) QueryReadAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
-	out := new(QueryMulti)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryReadAll", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetUser() string {
+	if m != nil {
+		return m.User
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryReadAllDsc(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
-	out := new(QueryMulti)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryReadAllDsc", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetSource() string {
+	if m != nil {
+		return m.Source
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryMutationNodes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationNodes", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetModule() string {
+	if m != nil {
+		return m.Module
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryMutationEdges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationEdges", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetAction() string {
+	if m != nil {
+		return m.Action
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryNodeMutationNodes(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationNodes", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetNode() string {
+	if m != nil {
+		return m.Node
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryNodeMutationEdges(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationEdges", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetChanges() []*AuditChange {
+	if m != nil {
+		return m.Changes
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) QueryNodeMutationPath(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
-	out := new(Query)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationPath", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditRecord) GetResult() string {
+	if m != nil {
+		return m.Result
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryDeleteAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
-	out := new(QueryMulti)
-	err := c.cc.Invoke(ctx, "/proto.API/QueryDeleteAll", in, out, opts...)
-	if err != nil {
-		return nil, err
+// An AuditChange is a value that changed
+type AuditChange struct {
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	OldValue             string   `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue             string   `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditChange) Reset()         { *m = AuditChange{} }
+func (m *AuditChange) String() string { return proto.CompactTextString(m) }
+func (*AuditChange) ProtoMessage()    {}
+func (*AuditChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{17}
+}
+func (m *AuditChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditChange.Unmarshal(m, b)
+}
+func (m *AuditChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditChange.Marshal(b, m, deterministic)
+}
+func (dst *AuditChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditChange.Merge(dst, src)
+}
+func (m *AuditChange) XXX_Size() int {
+	return xxx_messageInfo_AuditChange.Size(m)
+}
+func (m *AuditChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditChange proto.InternalMessageInfo
+
+func (m *AuditChange) GetUrl() string {
+	if m != nil {
+		return m.Url
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) ServiceInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_ServiceInitClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[0], "/proto.API/ServiceInit", opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditChange) GetOldValue() string {
+	if m != nil {
+		return m.OldValue
 	}
-	x := &aPIServiceInitClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return ""
+}
+
+func (m *AuditChange) GetNewValue() string {
+	if m != nil {
+		return m.NewValue
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return ""
+}
+
+type AuditRecordList struct {
+	Records              []*AuditRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuditRecordList) Reset()         { *m = AuditRecordList{} }
+func (m *AuditRecordList) String() string { return proto.CompactTextString(m) }
+func (*AuditRecordList) ProtoMessage()    {}
+func (*AuditRecordList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{18}
+}
+func (m *AuditRecordList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecordList.Unmarshal(m, b)
+}
+func (m *AuditRecordList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecordList.Marshal(b, m, deterministic)
+}
+func (dst *AuditRecordList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecordList.Merge(dst, src)
+}
+func (m *AuditRecordList) XXX_Size() int {
+	return xxx_messageInfo_AuditRecordList.Size(m)
+}
+func (m *AuditRecordList) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecordList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecordList proto.InternalMessageInfo
+
+func (m *AuditRecordList) GetRecords() []*AuditRecord {
+	if m != nil {
+		return m.Records
 	}
-	return x, nil
+	return nil
 }
 
-type API_ServiceInitClient interface {
-	Recv() (*ServiceControl, error)
-	grpc.ClientStream
+type AuditLogQuery struct {
+	Node                 string               `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	User                 string               `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	From                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-type aPIServiceInitClient struct {
-	grpc.ClientStream
+func (m *AuditLogQuery) Reset()         { *m = AuditLogQuery{} }
+func (m *AuditLogQuery) String() string { return proto.CompactTextString(m) }
+func (*AuditLogQuery) ProtoMessage()    {}
+func (*AuditLogQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{19}
+}
+func (m *AuditLogQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditLogQuery.Unmarshal(m, b)
+}
+func (m *AuditLogQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditLogQuery.Marshal(b, m, deterministic)
+}
+func (dst *AuditLogQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditLogQuery.Merge(dst, src)
+}
+func (m *AuditLogQuery) XXX_Size() int {
+	return xxx_messageInfo_AuditLogQuery.Size(m)
+}
+func (m *AuditLogQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditLogQuery.DiscardUnknown(m)
 }
 
-func (x *aPIServiceInitClient) Recv() (*ServiceControl, error) {
-	m := new(ServiceControl)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_AuditLogQuery proto.InternalMessageInfo
+
+func (m *AuditLogQuery) GetNode() string {
+	if m != nil {
+		return m.Node
 	}
-	return m, nil
+	return ""
 }
 
-func (c *aPIClient) MutationInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_MutationInitClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[1], "/proto.API/MutationInit", opts...)
-	if err != nil {
-		return nil, err
+func (m *AuditLogQuery) GetUser() string {
+	if m != nil {
+		return m.User
 	}
-	x := &aPIMutationInitClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return ""
+}
+
+func (m *AuditLogQuery) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return nil
+}
+
+func (m *AuditLogQuery) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
 	}
-	return x, nil
+	return nil
 }
 
-type API_MutationInitClient interface {
-	Recv() (*MutationControl, error)
-	grpc.ClientStream
+// MutationMetrics are what the SME has counted since it started
+type MutationMetrics struct {
+	Since                *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	Edges                []*MutationEdgeMetrics `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	Converge             *Histogram             `protobuf:"bytes,3,opt,name=converge,proto3" json:"converge,omitempty"`
+	Discoveries          uint64                 `protobuf:"varint,4,opt,name=discoveries,proto3" json:"discoveries,omitempty"`
+	Active               uint32                 `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
 }
 
-type aPIMutationInitClient struct {
-	grpc.ClientStream
+func (m *MutationMetrics) Reset()         { *m = MutationMetrics{} }
+func (m *MutationMetrics) String() string { return proto.CompactTextString(m) }
+func (*MutationMetrics) ProtoMessage()    {}
+func (*MutationMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{20}
+}
+func (m *MutationMetrics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutationMetrics.Unmarshal(m, b)
+}
+func (m *MutationMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutationMetrics.Marshal(b, m, deterministic)
+}
+func (dst *MutationMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutationMetrics.Merge(dst, src)
+}
+func (m *MutationMetrics) XXX_Size() int {
+	return xxx_messageInfo_MutationMetrics.Size(m)
+}
+func (m *MutationMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutationMetrics.DiscardUnknown(m)
 }
 
-func (x *aPIMutationInitClient) Recv() (*MutationControl, error) {
-	m := new(MutationControl)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_MutationMetrics proto.InternalMessageInfo
+
+func (m *MutationMetrics) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
 	}
-	return m, nil
+	return nil
 }
 
-func (c *aPIClient) DiscoveryInit(ctx context.Context, opts ...grpc.CallOption) (API_DiscoveryInitClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[2], "/proto.API/DiscoveryInit", opts...)
-	if err != nil {
-		return nil, err
+func (m *MutationMetrics) GetEdges() []*MutationEdgeMetrics {
+	if m != nil {
+		return m.Edges
 	}
-	x := &aPIDiscoveryInitClient{stream}
-	return x, nil
+	return nil
 }
 
-type API_DiscoveryInitClient interface {
-	Send(*DiscoveryEvent) error
-	CloseAndRecv() (*empty.Empty, error)
-	grpc.ClientStream
+func (m *MutationMetrics) GetConverge() *Histogram {
+	if m != nil {
+		return m.Converge
+	}
+	return nil
 }
 
-type aPIDiscoveryInitClient struct {
-	grpc.ClientStream
+func (m *MutationMetrics) GetDiscoveries() uint64 {
+	if m != nil {
+		return m.Discoveries
+	}
+	return 0
 }
 
-func (x *aPIDiscoveryInitClient) Send(m *DiscoveryEvent) error {
-	return x.ClientStream.SendMsg(m)
+func (m *MutationMetrics) GetActive() uint32 {
+	if m != nil {
+		return m.Active
+	}
+	return 0
+}
+
+type MutationEdgeMetrics struct {
+	Module               string   `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Mutation             string   `protobuf:"bytes,2,opt,name=mutation,proto3" json:"mutation,omitempty"`
+	Started              uint64   `protobuf:"varint,3,opt,name=started,proto3" json:"started,omitempty"`
+	Succeeded            uint64   `protobuf:"varint,4,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed               uint64   `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	Retried              uint64   `protobuf:"varint,6,opt,name=retried,proto3" json:"retried,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MutationEdgeMetrics) Reset()         { *m = MutationEdgeMetrics{} }
+func (m *MutationEdgeMetrics) String() string { return proto.CompactTextString(m) }
+func (*MutationEdgeMetrics) ProtoMessage()    {}
+func (*MutationEdgeMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{21}
+}
+func (m *MutationEdgeMetrics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutationEdgeMetrics.Unmarshal(m, b)
+}
+func (m *MutationEdgeMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutationEdgeMetrics.Marshal(b, m, deterministic)
+}
+func (dst *MutationEdgeMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutationEdgeMetrics.Merge(dst, src)
+}
+func (m *MutationEdgeMetrics) XXX_Size() int {
+	return xxx_messageInfo_MutationEdgeMetrics.Size(m)
+}
+func (m *MutationEdgeMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutationEdgeMetrics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MutationEdgeMetrics proto.InternalMessageInfo
+
+func (m *MutationEdgeMetrics) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *MutationEdgeMetrics) GetMutation() string {
+	if m != nil {
+		return m.Mutation
+	}
+	return ""
+}
+
+func (m *MutationEdgeMetrics) GetStarted() uint64 {
+	if m != nil {
+		return m.Started
+	}
+	return 0
+}
+
+func (m *MutationEdgeMetrics) GetSucceeded() uint64 {
+	if m != nil {
+		return m.Succeeded
+	}
+	return 0
+}
+
+func (m *MutationEdgeMetrics) GetFailed() uint64 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *MutationEdgeMetrics) GetRetried() uint64 {
+	if m != nil {
+		return m.Retried
+	}
+	return 0
+}
+
+type Histogram struct {
+	Bounds               []float64 `protobuf:"fixed64,1,rep,packed,name=bounds,proto3" json:"bounds,omitempty"`
+	Counts               []uint64  `protobuf:"varint,2,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+	Count                uint64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Sum                  float64   `protobuf:"fixed64,4,opt,name=sum,proto3" json:"sum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Histogram) Reset()         { *m = Histogram{} }
+func (m *Histogram) String() string { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()    {}
+func (*Histogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{22}
+}
+func (m *Histogram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Histogram.Unmarshal(m, b)
+}
+func (m *Histogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Histogram.Marshal(b, m, deterministic)
+}
+func (dst *Histogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Histogram.Merge(dst, src)
+}
+func (m *Histogram) XXX_Size() int {
+	return xxx_messageInfo_Histogram.Size(m)
+}
+func (m *Histogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_Histogram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Histogram proto.InternalMessageInfo
+
+func (m *Histogram) GetBounds() []float64 {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+func (m *Histogram) GetCounts() []uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *Histogram) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+// A Transaction sets several URLs at once
+type Transaction struct {
+	Nodes                []*Node  `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Urls                 []string `protobuf:"bytes,2,rep,name=urls,proto3" json:"urls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{23}
+}
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (dst *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(dst, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *Transaction) GetUrls() []string {
+	if m != nil {
+		return m.Urls
+	}
+	return nil
+}
+
+// A Rollup summarizes the discovered state of the nodes at a location (see Node.location), and everything below it
+type Rollup struct {
+	Location             string            `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Nodes                uint32            `protobuf:"varint,2,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	PhysState            map[string]uint32 `protobuf:"bytes,3,rep,name=phys_state,json=physState,proto3" json:"phys_state,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	RunState             map[string]uint32 `protobuf:"bytes,4,rep,name=run_state,json=runState,proto3" json:"run_state,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Rollup) Reset()         { *m = Rollup{} }
+func (m *Rollup) String() string { return proto.CompactTextString(m) }
+func (*Rollup) ProtoMessage()    {}
+func (*Rollup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{24}
+}
+func (m *Rollup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rollup.Unmarshal(m, b)
+}
+func (m *Rollup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rollup.Marshal(b, m, deterministic)
+}
+func (dst *Rollup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rollup.Merge(dst, src)
+}
+func (m *Rollup) XXX_Size() int {
+	return xxx_messageInfo_Rollup.Size(m)
+}
+func (m *Rollup) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rollup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rollup proto.InternalMessageInfo
+
+func (m *Rollup) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+func (m *Rollup) GetNodes() uint32 {
+	if m != nil {
+		return m.Nodes
+	}
+	return 0
+}
+
+func (m *Rollup) GetPhysState() map[string]uint32 {
+	if m != nil {
+		return m.PhysState
+	}
+	return nil
+}
+
+func (m *Rollup) GetRunState() map[string]uint32 {
+	if m != nil {
+		return m.RunState
+	}
+	return nil
+}
+
+type RollupList struct {
+	Rollups              []*Rollup `protobuf:"bytes,1,rep,name=rollups,proto3" json:"rollups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RollupList) Reset()         { *m = RollupList{} }
+func (m *RollupList) String() string { return proto.CompactTextString(m) }
+func (*RollupList) ProtoMessage()    {}
+func (*RollupList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{25}
+}
+func (m *RollupList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollupList.Unmarshal(m, b)
+}
+func (m *RollupList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollupList.Marshal(b, m, deterministic)
+}
+func (dst *RollupList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollupList.Merge(dst, src)
+}
+func (m *RollupList) XXX_Size() int {
+	return xxx_messageInfo_RollupList.Size(m)
+}
+func (m *RollupList) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollupList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollupList proto.InternalMessageInfo
+
+func (m *RollupList) GetRollups() []*Rollup {
+	if m != nil {
+		return m.Rollups
+	}
+	return nil
+}
+
+// A DecommissionRequest starts retiring a node
+type DecommissionRequest struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Final                map[string]string `protobuf:"bytes,2,rep,name=final,proto3" json:"final,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Remove               bool              `protobuf:"varint,3,opt,name=remove,proto3" json:"remove,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DecommissionRequest) Reset()         { *m = DecommissionRequest{} }
+func (m *DecommissionRequest) String() string { return proto.CompactTextString(m) }
+func (*DecommissionRequest) ProtoMessage()    {}
+func (*DecommissionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{26}
+}
+func (m *DecommissionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionRequest.Unmarshal(m, b)
+}
+func (m *DecommissionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionRequest.Marshal(b, m, deterministic)
+}
+func (dst *DecommissionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionRequest.Merge(dst, src)
+}
+func (m *DecommissionRequest) XXX_Size() int {
+	return xxx_messageInfo_DecommissionRequest.Size(m)
+}
+func (m *DecommissionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DecommissionRequest proto.InternalMessageInfo
+
+func (m *DecommissionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DecommissionRequest) GetFinal() map[string]string {
+	if m != nil {
+		return m.Final
+	}
+	return nil
+}
+
+func (m *DecommissionRequest) GetRemove() bool {
+	if m != nil {
+		return m.Remove
+	}
+	return false
+}
+
+// A DiscoverRequest asks modules to re-discover nodes immediately
+type DiscoverRequest struct {
+	Nodes                []string `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Module               string   `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscoverRequest) Reset()         { *m = DiscoverRequest{} }
+func (m *DiscoverRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscoverRequest) ProtoMessage()    {}
+func (*DiscoverRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{27}
+}
+func (m *DiscoverRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverRequest.Unmarshal(m, b)
+}
+func (m *DiscoverRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverRequest.Marshal(b, m, deterministic)
+}
+func (dst *DiscoverRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverRequest.Merge(dst, src)
+}
+func (m *DiscoverRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscoverRequest.Size(m)
+}
+func (m *DiscoverRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverRequest proto.InternalMessageInfo
+
+func (m *DiscoverRequest) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *DiscoverRequest) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+// A DiscoverResponse lists the services that were asked to re-discover
+type DiscoverResponse struct {
+	Services             []string `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscoverResponse) Reset()         { *m = DiscoverResponse{} }
+func (m *DiscoverResponse) String() string { return proto.CompactTextString(m) }
+func (*DiscoverResponse) ProtoMessage()    {}
+func (*DiscoverResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{28}
+}
+func (m *DiscoverResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverResponse.Unmarshal(m, b)
+}
+func (m *DiscoverResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverResponse.Marshal(b, m, deterministic)
+}
+func (dst *DiscoverResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverResponse.Merge(dst, src)
+}
+func (m *DiscoverResponse) XXX_Size() int {
+	return xxx_messageInfo_DiscoverResponse.Size(m)
+}
+func (m *DiscoverResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverResponse proto.InternalMessageInfo
+
+func (m *DiscoverResponse) GetServices() []string {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+// A ConvergenceDelta is a value that's discovered to be different than it's configured
+type ConvergenceDelta struct {
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Cfg                  string   `protobuf:"bytes,2,opt,name=cfg,proto3" json:"cfg,omitempty"`
+	Dsc                  string   `protobuf:"bytes,3,opt,name=dsc,proto3" json:"dsc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvergenceDelta) Reset()         { *m = ConvergenceDelta{} }
+func (m *ConvergenceDelta) String() string { return proto.CompactTextString(m) }
+func (*ConvergenceDelta) ProtoMessage()    {}
+func (*ConvergenceDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{29}
+}
+func (m *ConvergenceDelta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvergenceDelta.Unmarshal(m, b)
+}
+func (m *ConvergenceDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvergenceDelta.Marshal(b, m, deterministic)
+}
+func (dst *ConvergenceDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvergenceDelta.Merge(dst, src)
+}
+func (m *ConvergenceDelta) XXX_Size() int {
+	return xxx_messageInfo_ConvergenceDelta.Size(m)
+}
+func (m *ConvergenceDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvergenceDelta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvergenceDelta proto.InternalMessageInfo
+
+func (m *ConvergenceDelta) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *ConvergenceDelta) GetCfg() string {
+	if m != nil {
+		return m.Cfg
+	}
+	return ""
+}
+
+func (m *ConvergenceDelta) GetDsc() string {
+	if m != nil {
+		return m.Dsc
+	}
+	return ""
+}
+
+// A NodeConvergence is whether a node's discovered state matches its configuration, in the values that mutations change
+type NodeConvergence struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Nodename             string               `protobuf:"bytes,2,opt,name=nodename,proto3" json:"nodename,omitempty"`
+	Converged            bool                 `protobuf:"varint,3,opt,name=converged,proto3" json:"converged,omitempty"`
+	Deltas               []*ConvergenceDelta  `protobuf:"bytes,4,rep,name=deltas,proto3" json:"deltas,omitempty"`
+	Diverged             *timestamp.Timestamp `protobuf:"bytes,5,opt,name=diverged,proto3" json:"diverged,omitempty"`
+	Mutating             bool                 `protobuf:"varint,6,opt,name=mutating,proto3" json:"mutating,omitempty"`
+	Frozen               bool                 `protobuf:"varint,7,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *NodeConvergence) Reset()         { *m = NodeConvergence{} }
+func (m *NodeConvergence) String() string { return proto.CompactTextString(m) }
+func (*NodeConvergence) ProtoMessage()    {}
+func (*NodeConvergence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{30}
+}
+func (m *NodeConvergence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeConvergence.Unmarshal(m, b)
+}
+func (m *NodeConvergence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeConvergence.Marshal(b, m, deterministic)
+}
+func (dst *NodeConvergence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeConvergence.Merge(dst, src)
+}
+func (m *NodeConvergence) XXX_Size() int {
+	return xxx_messageInfo_NodeConvergence.Size(m)
+}
+func (m *NodeConvergence) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeConvergence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeConvergence proto.InternalMessageInfo
+
+func (m *NodeConvergence) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeConvergence) GetNodename() string {
+	if m != nil {
+		return m.Nodename
+	}
+	return ""
+}
+
+func (m *NodeConvergence) GetConverged() bool {
+	if m != nil {
+		return m.Converged
+	}
+	return false
+}
+
+func (m *NodeConvergence) GetDeltas() []*ConvergenceDelta {
+	if m != nil {
+		return m.Deltas
+	}
+	return nil
+}
+
+func (m *NodeConvergence) GetDiverged() *timestamp.Timestamp {
+	if m != nil {
+		return m.Diverged
+	}
+	return nil
+}
+
+func (m *NodeConvergence) GetMutating() bool {
+	if m != nil {
+		return m.Mutating
+	}
+	return false
+}
+
+func (m *NodeConvergence) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+type ConvergenceReport struct {
+	Nodes                []*NodeConvergence `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ConvergenceReport) Reset()         { *m = ConvergenceReport{} }
+func (m *ConvergenceReport) String() string { return proto.CompactTextString(m) }
+func (*ConvergenceReport) ProtoMessage()    {}
+func (*ConvergenceReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{31}
+}
+func (m *ConvergenceReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvergenceReport.Unmarshal(m, b)
+}
+func (m *ConvergenceReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvergenceReport.Marshal(b, m, deterministic)
+}
+func (dst *ConvergenceReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvergenceReport.Merge(dst, src)
+}
+func (m *ConvergenceReport) XXX_Size() int {
+	return xxx_messageInfo_ConvergenceReport.Size(m)
+}
+func (m *ConvergenceReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvergenceReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvergenceReport proto.InternalMessageInfo
+
+func (m *ConvergenceReport) GetNodes() []*NodeConvergence {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+// A ServiceStatus is how a service's process is doing on the node that runs it
+type ServiceStatus struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Module               string               `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Status               ServiceStatus_Status `protobuf:"varint,3,opt,name=status,proto3,enum=proto.ServiceStatus_Status" json:"status,omitempty"`
+	Pid                  int32                `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	Restarts             uint32               `protobuf:"varint,5,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	LastExit             string               `protobuf:"bytes,6,opt,name=last_exit,json=lastExit,proto3" json:"last_exit,omitempty"`
+	Since                *timestamp.Timestamp `protobuf:"bytes,7,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ServiceStatus) Reset()         { *m = ServiceStatus{} }
+func (m *ServiceStatus) String() string { return proto.CompactTextString(m) }
+func (*ServiceStatus) ProtoMessage()    {}
+func (*ServiceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{32}
+}
+func (m *ServiceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceStatus.Unmarshal(m, b)
+}
+func (m *ServiceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceStatus.Marshal(b, m, deterministic)
+}
+func (dst *ServiceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceStatus.Merge(dst, src)
+}
+func (m *ServiceStatus) XXX_Size() int {
+	return xxx_messageInfo_ServiceStatus.Size(m)
+}
+func (m *ServiceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceStatus proto.InternalMessageInfo
+
+func (m *ServiceStatus) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ServiceStatus) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *ServiceStatus) GetStatus() ServiceStatus_Status {
+	if m != nil {
+		return m.Status
+	}
+	return ServiceStatus_STOPPED
+}
+
+func (m *ServiceStatus) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *ServiceStatus) GetRestarts() uint32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *ServiceStatus) GetLastExit() string {
+	if m != nil {
+		return m.LastExit
+	}
+	return ""
+}
+
+func (m *ServiceStatus) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+type ServiceStatusList struct {
+	Services             []*ServiceStatus `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ServiceStatusList) Reset()         { *m = ServiceStatusList{} }
+func (m *ServiceStatusList) String() string { return proto.CompactTextString(m) }
+func (*ServiceStatusList) ProtoMessage()    {}
+func (*ServiceStatusList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{33}
+}
+func (m *ServiceStatusList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceStatusList.Unmarshal(m, b)
+}
+func (m *ServiceStatusList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceStatusList.Marshal(b, m, deterministic)
+}
+func (dst *ServiceStatusList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceStatusList.Merge(dst, src)
+}
+func (m *ServiceStatusList) XXX_Size() int {
+	return xxx_messageInfo_ServiceStatusList.Size(m)
+}
+func (m *ServiceStatusList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceStatusList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceStatusList proto.InternalMessageInfo
+
+func (m *ServiceStatusList) GetServices() []*ServiceStatus {
+	if m != nil {
+		return m.Services
+	}
+	return nil
+}
+
+// A WatchRequest subscribes to state changes; empty fields match anything
+type WatchRequest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Types                []string `protobuf:"bytes,4,rep,name=types,proto3" json:"types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{34}
+}
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(dst, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+// A StateChange is a delta sent to a watch
+type StateChange struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Node                 string   `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Url                  string   `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Value                string   `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateChange) Reset()         { *m = StateChange{} }
+func (m *StateChange) String() string { return proto.CompactTextString(m) }
+func (*StateChange) ProtoMessage()    {}
+func (*StateChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{35}
+}
+func (m *StateChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateChange.Unmarshal(m, b)
+}
+func (m *StateChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateChange.Marshal(b, m, deterministic)
+}
+func (dst *StateChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateChange.Merge(dst, src)
+}
+func (m *StateChange) XXX_Size() int {
+	return xxx_messageInfo_StateChange.Size(m)
+}
+func (m *StateChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateChange proto.InternalMessageInfo
+
+func (m *StateChange) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *StateChange) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *StateChange) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *StateChange) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type LogMessage struct {
+	Origin               string   `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Level                uint32   `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogMessage) Reset()         { *m = LogMessage{} }
+func (m *LogMessage) String() string { return proto.CompactTextString(m) }
+func (*LogMessage) ProtoMessage()    {}
+func (*LogMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_API_d34ab7906403fbbd, []int{36}
+}
+func (m *LogMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogMessage.Unmarshal(m, b)
+}
+func (m *LogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogMessage.Marshal(b, m, deterministic)
+}
+func (dst *LogMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogMessage.Merge(dst, src)
+}
+func (m *LogMessage) XXX_Size() int {
+	return xxx_messageInfo_LogMessage.Size(m)
+}
+func (m *LogMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogMessage proto.InternalMessageInfo
+
+func (m *LogMessage) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *LogMessage) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *LogMessage) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Query)(nil), "proto.Query")
+	proto.RegisterType((*QueryMulti)(nil), "proto.QueryMulti")
+	proto.RegisterType((*ServiceInitRequest)(nil), "proto.ServiceInitRequest")
+	proto.RegisterType((*ServiceControl)(nil), "proto.ServiceControl")
+	proto.RegisterType((*MutationControl)(nil), "proto.MutationControl")
+	proto.RegisterType((*MutationAckRequest)(nil), "proto.MutationAckRequest")
+	proto.RegisterType((*DiscoveryEvent)(nil), "proto.DiscoveryEvent")
+	proto.RegisterType((*MutationNodeList)(nil), "proto.MutationNodeList")
+	proto.RegisterType((*MutationEdgeList)(nil), "proto.MutationEdgeList")
+	proto.RegisterType((*MutationPath)(nil), "proto.MutationPath")
+	proto.RegisterType((*MutationNode)(nil), "proto.MutationNode")
+	proto.RegisterMapType((map[string]string)(nil), "proto.MutationNode.RequiresEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.MutationNode.ExcludesEntry")
+	proto.RegisterType((*MutationEdge)(nil), "proto.MutationEdge")
+	proto.RegisterMapType((map[string]string)(nil), "proto.MutationEdge.MutatesEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.MutationEdge.RequiresEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.MutationEdge.ExcludesEntry")
+	proto.RegisterType((*EdgeColor)(nil), "proto.EdgeColor")
+	proto.RegisterType((*EventRecord)(nil), "proto.EventRecord")
+	proto.RegisterType((*EventRecordList)(nil), "proto.EventRecordList")
+	proto.RegisterType((*EventLogQuery)(nil), "proto.EventLogQuery")
+	proto.RegisterType((*AuditRecord)(nil), "proto.AuditRecord")
+	proto.RegisterType((*AuditChange)(nil), "proto.AuditChange")
+	proto.RegisterType((*AuditRecordList)(nil), "proto.AuditRecordList")
+	proto.RegisterType((*AuditLogQuery)(nil), "proto.AuditLogQuery")
+	proto.RegisterType((*MutationMetrics)(nil), "proto.MutationMetrics")
+	proto.RegisterType((*MutationEdgeMetrics)(nil), "proto.MutationEdgeMetrics")
+	proto.RegisterType((*Histogram)(nil), "proto.Histogram")
+	proto.RegisterType((*Transaction)(nil), "proto.Transaction")
+	proto.RegisterType((*Rollup)(nil), "proto.Rollup")
+	proto.RegisterMapType((map[string]uint32)(nil), "proto.Rollup.PhysStateEntry")
+	proto.RegisterMapType((map[string]uint32)(nil), "proto.Rollup.RunStateEntry")
+	proto.RegisterType((*RollupList)(nil), "proto.RollupList")
+	proto.RegisterType((*DecommissionRequest)(nil), "proto.DecommissionRequest")
+	proto.RegisterMapType((map[string]string)(nil), "proto.DecommissionRequest.FinalEntry")
+	proto.RegisterType((*DiscoverRequest)(nil), "proto.DiscoverRequest")
+	proto.RegisterType((*DiscoverResponse)(nil), "proto.DiscoverResponse")
+	proto.RegisterType((*ConvergenceDelta)(nil), "proto.ConvergenceDelta")
+	proto.RegisterType((*NodeConvergence)(nil), "proto.NodeConvergence")
+	proto.RegisterType((*ConvergenceReport)(nil), "proto.ConvergenceReport")
+	proto.RegisterType((*ServiceStatus)(nil), "proto.ServiceStatus")
+	proto.RegisterType((*ServiceStatusList)(nil), "proto.ServiceStatusList")
+	proto.RegisterType((*WatchRequest)(nil), "proto.WatchRequest")
+	proto.RegisterType((*StateChange)(nil), "proto.StateChange")
+	proto.RegisterType((*LogMessage)(nil), "proto.LogMessage")
+	proto.RegisterEnum("proto.ServiceControl_Command", ServiceControl_Command_name, ServiceControl_Command_value)
+	proto.RegisterEnum("proto.MutationControl_Type", MutationControl_Type_name, MutationControl_Type_value)
+	proto.RegisterEnum("proto.ServiceStatus_Status", ServiceStatus_Status_name, ServiceStatus_Status_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// APIClient is the client API for API service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type APIClient interface {
+	// Query language
+	// TODO: create API for bulk CRUD operations
+	QueryCreate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryRead(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryReadDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryUpdate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryUpdateDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryDelete(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryReadAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryReadAllDsc(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryMutationNodes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error)
+	QueryMutationEdges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error)
+	QueryNodeMutationNodes(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryNodeMutationEdges(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryNodeMutationPath(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryMutationPlan(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error)
+	QueryEventLog(ctx context.Context, in *EventLogQuery, opts ...grpc.CallOption) (*EventRecordList, error)
+	QueryMutationMetrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MutationMetrics, error)
+	QueryDecommission(ctx context.Context, in *DecommissionRequest, opts ...grpc.CallOption) (*Query, error)
+	QueryRollups(ctx context.Context, in *Query, opts ...grpc.CallOption) (*RollupList, error)
+	QueryDiscover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error)
+	QueryAudit(ctx context.Context, in *AuditRecordList, opts ...grpc.CallOption) (*empty.Empty, error)
+	QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecordList, error)
+	QueryServiceStatus(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ServiceStatusList, error)
+	QueryConvergence(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ConvergenceReport, error)
+	QueryDeleteAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryRestore(ctx context.Context, in *QueryMulti, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryReadGroup(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryReadGroupDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryUpdateGroup(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error)
+	QueryTransact(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*empty.Empty, error)
+	QueryTransactDsc(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*empty.Empty, error)
+	QuerySelect(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error)
+	QuerySelectDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (API_WatchClient, error)
+	// Service management
+	ServiceInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_ServiceInitClient, error)
+	// Mutation/Discover management
+	MutationInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_MutationInitClient, error)
+	MutationAck(ctx context.Context, in *MutationAckRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Discovery management
+	DiscoveryInit(ctx context.Context, opts ...grpc.CallOption) (API_DiscoveryInitClient, error)
+	// Logging
+	LoggerInit(ctx context.Context, opts ...grpc.CallOption) (API_LoggerInitClient, error)
+}
+
+type aPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAPIClient(cc *grpc.ClientConn) APIClient {
+	return &aPIClient{cc}
+}
+
+func (c *aPIClient) QueryCreate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryRead(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryReadDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryReadDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryUpdate(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryUpdateDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryUpdateDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryDelete(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryReadAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryReadAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryReadAllDsc(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryReadAllDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryMutationNodes(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryMutationEdges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationEdges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryNodeMutationNodes(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryNodeMutationEdges(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationEdges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryNodeMutationPath(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryNodeMutationPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryMutationPlan(ctx context.Context, in *Query, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationPlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryEventLog(ctx context.Context, in *EventLogQuery, opts ...grpc.CallOption) (*EventRecordList, error) {
+	out := new(EventRecordList)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryEventLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryMutationMetrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MutationMetrics, error) {
+	out := new(MutationMetrics)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryMutationMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryDecommission(ctx context.Context, in *DecommissionRequest, opts ...grpc.CallOption) (*Query, error) {
+	out := new(Query)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryDecommission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryRollups(ctx context.Context, in *Query, opts ...grpc.CallOption) (*RollupList, error) {
+	out := new(RollupList)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryRollups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryDiscover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error) {
+	out := new(DiscoverResponse)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryDiscover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryAudit(ctx context.Context, in *AuditRecordList, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryAudit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecordList, error) {
+	out := new(AuditRecordList)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryServiceStatus(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ServiceStatusList, error) {
+	out := new(ServiceStatusList)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryServiceStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryConvergence(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ConvergenceReport, error) {
+	out := new(ConvergenceReport)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryConvergence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryDeleteAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryDeleteAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryRestore(ctx context.Context, in *QueryMulti, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryRestore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryReadGroup(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryReadGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryReadGroupDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryReadGroupDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryUpdateGroup(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryUpdateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryTransact(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryTransact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryTransactDsc(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.API/QueryTransactDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QuerySelect(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QuerySelect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QuerySelectDsc(ctx context.Context, in *Query, opts ...grpc.CallOption) (*QueryMulti, error) {
+	out := new(QueryMulti)
+	err := c.cc.Invoke(ctx, "/proto.API/QuerySelectDsc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (API_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[0], "/proto.API/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_WatchClient interface {
+	Recv() (*StateChange, error)
+	grpc.ClientStream
+}
+
+type aPIWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIWatchClient) Recv() (*StateChange, error) {
+	m := new(StateChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) ServiceInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_ServiceInitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[1], "/proto.API/ServiceInit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIServiceInitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_ServiceInitClient interface {
+	Recv() (*ServiceControl, error)
+	grpc.ClientStream
+}
+
+type aPIServiceInitClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIServiceInitClient) Recv() (*ServiceControl, error) {
+	m := new(ServiceControl)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) MutationInit(ctx context.Context, in *ServiceInitRequest, opts ...grpc.CallOption) (API_MutationInitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[2], "/proto.API/MutationInit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIMutationInitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_MutationInitClient interface {
+	Recv() (*MutationControl, error)
+	grpc.ClientStream
+}
+
+type aPIMutationInitClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIMutationInitClient) Recv() (*MutationControl, error) {
+	m := new(MutationControl)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) MutationAck(ctx context.Context, in *MutationAckRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.API/MutationAck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DiscoveryInit(ctx context.Context, opts ...grpc.CallOption) (API_DiscoveryInitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[3], "/proto.API/DiscoveryInit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIDiscoveryInitClient{stream}
+	return x, nil
+}
+
+type API_DiscoveryInitClient interface {
+	Send(*DiscoveryEvent) error
+	CloseAndRecv() (*empty.Empty, error)
+	grpc.ClientStream
+}
+
+type aPIDiscoveryInitClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIDiscoveryInitClient) Send(m *DiscoveryEvent) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *aPIDiscoveryInitClient) CloseAndRecv() (*empty.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(empty.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) LoggerInit(ctx context.Context, opts ...grpc.CallOption) (API_LoggerInitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[4], "/proto.API/LoggerInit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPILoggerInitClient{stream}
+	return x, nil
+}
+
+type API_LoggerInitClient interface {
+	Send(*LogMessage) error
+	CloseAndRecv() (*empty.Empty, error)
+	grpc.ClientStream
+}
+
+type aPILoggerInitClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPILoggerInitClient) Send(m *LogMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *aPILoggerInitClient) CloseAndRecv() (*empty.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(empty.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// APIServer is the server API for API service.
+type APIServer interface {
+	// Query language
+	// TODO: create API for bulk CRUD operations
+	QueryCreate(context.Context, *Query) (*Query, error)
+	QueryRead(context.Context, *Query) (*Query, error)
+	QueryReadDsc(context.Context, *Query) (*Query, error)
+	QueryUpdate(context.Context, *Query) (*Query, error)
+	QueryUpdateDsc(context.Context, *Query) (*Query, error)
+	QueryDelete(context.Context, *Query) (*Query, error)
+	QueryReadAll(context.Context, *empty.Empty) (*QueryMulti, error)
+	QueryReadAllDsc(context.Context, *empty.Empty) (*QueryMulti, error)
+	QueryMutationNodes(context.Context, *empty.Empty) (*Query, error)
+	QueryMutationEdges(context.Context, *empty.Empty) (*Query, error)
+	QueryNodeMutationNodes(context.Context, *Query) (*Query, error)
+	QueryNodeMutationEdges(context.Context, *Query) (*Query, error)
+	QueryNodeMutationPath(context.Context, *Query) (*Query, error)
+	QueryMutationPlan(context.Context, *Query) (*Query, error)
+	QueryEventLog(context.Context, *EventLogQuery) (*EventRecordList, error)
+	QueryMutationMetrics(context.Context, *empty.Empty) (*MutationMetrics, error)
+	QueryDecommission(context.Context, *DecommissionRequest) (*Query, error)
+	QueryRollups(context.Context, *Query) (*RollupList, error)
+	QueryDiscover(context.Context, *DiscoverRequest) (*DiscoverResponse, error)
+	QueryAudit(context.Context, *AuditRecordList) (*empty.Empty, error)
+	QueryAuditLog(context.Context, *AuditLogQuery) (*AuditRecordList, error)
+	QueryServiceStatus(context.Context, *empty.Empty) (*ServiceStatusList, error)
+	QueryConvergence(context.Context, *empty.Empty) (*ConvergenceReport, error)
+	QueryDeleteAll(context.Context, *empty.Empty) (*QueryMulti, error)
+	QueryRestore(context.Context, *QueryMulti) (*QueryMulti, error)
+	QueryReadGroup(context.Context, *Query) (*QueryMulti, error)
+	QueryReadGroupDsc(context.Context, *Query) (*QueryMulti, error)
+	QueryUpdateGroup(context.Context, *Query) (*QueryMulti, error)
+	QueryTransact(context.Context, *Transaction) (*empty.Empty, error)
+	QueryTransactDsc(context.Context, *Transaction) (*empty.Empty, error)
+	QuerySelect(context.Context, *Query) (*QueryMulti, error)
+	QuerySelectDsc(context.Context, *Query) (*QueryMulti, error)
+	Watch(*WatchRequest, API_WatchServer) error
+	// Service management
+	ServiceInit(*ServiceInitRequest, API_ServiceInitServer) error
+	// Mutation/Discover management
+	MutationInit(*ServiceInitRequest, API_MutationInitServer) error
+	MutationAck(context.Context, *MutationAckRequest) (*empty.Empty, error)
+	// Discovery management
+	DiscoveryInit(API_DiscoveryInitServer) error
+	// Logging
+	LoggerInit(API_LoggerInitServer) error
+}
+
+func RegisterAPIServer(s *grpc.Server, srv APIServer) {
+	s.RegisterService(&_API_serviceDesc, srv)
+}
+
+func _API_QueryCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryCreate(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryRead(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryReadDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryReadDsc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryReadDsc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryReadDsc(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryUpdate(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryUpdateDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryUpdateDsc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryUpdateDsc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryUpdateDsc(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryDelete(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryReadAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryReadAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryReadAll(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryReadAllDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryReadAllDsc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryReadAllDsc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryReadAllDsc(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryMutationNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryMutationNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryMutationNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryMutationNodes(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryMutationEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryMutationEdges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryMutationEdges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryMutationEdges(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryNodeMutationNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryNodeMutationNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryNodeMutationNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryNodeMutationNodes(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryNodeMutationEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryNodeMutationEdges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryNodeMutationEdges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryNodeMutationEdges(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryNodeMutationPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryNodeMutationPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryNodeMutationPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryNodeMutationPath(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *aPIDiscoveryInitClient) CloseAndRecv() (*empty.Empty, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func _API_QueryMutationPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(empty.Empty)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(APIServer).QueryMutationPlan(ctx, in)
 	}
-	return m, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryMutationPlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryMutationPlan(ctx, req.(*Query))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) LoggerInit(ctx context.Context, opts ...grpc.CallOption) (API_LoggerInitClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_API_serviceDesc.Streams[3], "/proto.API/LoggerInit", opts...)
-	if err != nil {
+func _API_QueryEventLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventLogQuery)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &aPILoggerInitClient{stream}
-	return x, nil
-}
-
-type API_LoggerInitClient interface {
-	Send(*LogMessage) error
-	CloseAndRecv() (*empty.Empty, error)
-	grpc.ClientStream
-}
-
-type aPILoggerInitClient struct {
-	grpc.ClientStream
-}
-
-func (x *aPILoggerInitClient) Send(m *LogMessage) error {
-	return x.ClientStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(APIServer).QueryEventLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryEventLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryEventLog(ctx, req.(*EventLogQuery))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *aPILoggerInitClient) CloseAndRecv() (*empty.Empty, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func _API_QueryMutationMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(empty.Empty)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(APIServer).QueryMutationMetrics(ctx, in)
 	}
-	return m, nil
-}
-
-// APIServer is the server API for API service.
-type APIServer interface {
-	// Query language
-	// TODO: create API for bulk CRUD operations
-	QueryCreate(context.Context, *Query) (*Query, error)
-	QueryRead(context.Context, *Query) (*Query, error)
-	QueryReadDsc(context.Context, *Query) (*Query, error)
-	QueryUpdate(context.Context, *Query) (*Query, error)
-	QueryUpdateDsc(context.Context, *Query) (*Query, error)
-	QueryDelete(context.Context, *Query) (*Query, error)
-	QueryReadAll(context.Context, *empty.Empty) (*QueryMulti, error)
-	QueryReadAllDsc(context.Context, *empty.Empty) (*QueryMulti, error)
-	QueryMutationNodes(context.Context, *empty.Empty) (*Query, error)
-	QueryMutationEdges(context.Context, *empty.Empty) (*Query, error)
-	QueryNodeMutationNodes(context.Context, *Query) (*Query, error)
-	QueryNodeMutationEdges(context.Context, *Query) (*Query, error)
-	QueryNodeMutationPath(context.Context, *Query) (*Query, error)
-	QueryDeleteAll(context.Context, *empty.Empty) (*QueryMulti, error)
-	// Service management
-	ServiceInit(*ServiceInitRequest, API_ServiceInitServer) error
-	// Mutation/Discover management
-	MutationInit(*ServiceInitRequest, API_MutationInitServer) error
-	// Discovery management
-	DiscoveryInit(API_DiscoveryInitServer) error
-	// Logging
-	LoggerInit(API_LoggerInitServer) error
-}
-
-func RegisterAPIServer(s *grpc.Server, srv APIServer) {
-	s.RegisterService(&_API_serviceDesc, srv)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryMutationMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryMutationMetrics(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Query)
+func _API_QueryDecommission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryCreate(ctx, in)
+		return srv.(APIServer).QueryDecommission(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryCreate",
+		FullMethod: "/proto.API/QueryDecommission",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryCreate(ctx, req.(*Query))
+		return srv.(APIServer).QueryDecommission(ctx, req.(*DecommissionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryRollups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryRead(ctx, in)
+		return srv.(APIServer).QueryRollups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryRead",
+		FullMethod: "/proto.API/QueryRollups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryRead(ctx, req.(*Query))
+		return srv.(APIServer).QueryRollups(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryReadDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Query)
+func _API_QueryDiscover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryReadDsc(ctx, in)
+		return srv.(APIServer).QueryDiscover(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryReadDsc",
+		FullMethod: "/proto.API/QueryDiscover",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryReadDsc(ctx, req.(*Query))
+		return srv.(APIServer).QueryDiscover(ctx, req.(*DiscoverRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Query)
+func _API_QueryAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditRecordList)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryUpdate(ctx, in)
+		return srv.(APIServer).QueryAudit(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryUpdate",
+		FullMethod: "/proto.API/QueryAudit",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryUpdate(ctx, req.(*Query))
+		return srv.(APIServer).QueryAudit(ctx, req.(*AuditRecordList))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryUpdateDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Query)
+func _API_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogQuery)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryUpdateDsc(ctx, in)
+		return srv.(APIServer).QueryAuditLog(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryUpdateDsc",
+		FullMethod: "/proto.API/QueryAuditLog",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryUpdateDsc(ctx, req.(*Query))
+		return srv.(APIServer).QueryAuditLog(ctx, req.(*AuditLogQuery))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Query)
+func _API_QueryServiceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryDelete(ctx, in)
+		return srv.(APIServer).QueryServiceStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryDelete",
+		FullMethod: "/proto.API/QueryServiceStatus",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryDelete(ctx, req.(*Query))
+		return srv.(APIServer).QueryServiceStatus(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryConvergence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryReadAll(ctx, in)
+		return srv.(APIServer).QueryConvergence(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryReadAll",
+		FullMethod: "/proto.API/QueryConvergence",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryReadAll(ctx, req.(*empty.Empty))
+		return srv.(APIServer).QueryConvergence(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryReadAllDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryDeleteAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryReadAllDsc(ctx, in)
+		return srv.(APIServer).QueryDeleteAll(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryReadAllDsc",
+		FullMethod: "/proto.API/QueryDeleteAll",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryReadAllDsc(ctx, req.(*empty.Empty))
+		return srv.(APIServer).QueryDeleteAll(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryMutationNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _API_QueryRestore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMulti)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryMutationNodes(ctx, in)
+		return srv.(APIServer).QueryRestore(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryMutationNodes",
+		FullMethod: "/proto.API/QueryRestore",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryMutationNodes(ctx, req.(*empty.Empty))
+		return srv.(APIServer).QueryRestore(ctx, req.(*QueryMulti))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryMutationEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _API_QueryReadGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryMutationEdges(ctx, in)
+		return srv.(APIServer).QueryReadGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryMutationEdges",
+		FullMethod: "/proto.API/QueryReadGroup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryMutationEdges(ctx, req.(*empty.Empty))
+		return srv.(APIServer).QueryReadGroup(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryNodeMutationNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryReadGroupDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryNodeMutationNodes(ctx, in)
+		return srv.(APIServer).QueryReadGroupDsc(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryNodeMutationNodes",
+		FullMethod: "/proto.API/QueryReadGroupDsc",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryNodeMutationNodes(ctx, req.(*Query))
+		return srv.(APIServer).QueryReadGroupDsc(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryNodeMutationEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryUpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryNodeMutationEdges(ctx, in)
+		return srv.(APIServer).QueryUpdateGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryNodeMutationEdges",
+		FullMethod: "/proto.API/QueryUpdateGroup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryNodeMutationEdges(ctx, req.(*Query))
+		return srv.(APIServer).QueryUpdateGroup(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryNodeMutationPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_QueryTransact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryTransact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryTransact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryTransact(ctx, req.(*Transaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryTransactDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryTransactDsc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/QueryTransactDsc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryTransactDsc(ctx, req.(*Transaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QuerySelect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryNodeMutationPath(ctx, in)
+		return srv.(APIServer).QuerySelect(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryNodeMutationPath",
+		FullMethod: "/proto.API/QuerySelect",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryNodeMutationPath(ctx, req.(*Query))
+		return srv.(APIServer).QuerySelect(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_QueryDeleteAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _API_QuerySelectDsc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Query)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).QueryDeleteAll(ctx, in)
+		return srv.(APIServer).QuerySelectDsc(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.API/QueryDeleteAll",
+		FullMethod: "/proto.API/QuerySelectDsc",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryDeleteAll(ctx, req.(*empty.Empty))
+		return srv.(APIServer).QuerySelectDsc(ctx, req.(*Query))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).Watch(m, &aPIWatchServer{stream})
+}
+
+type API_WatchServer interface {
+	Send(*StateChange) error
+	grpc.ServerStream
+}
+
+type aPIWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIWatchServer) Send(m *StateChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _API_ServiceInit_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ServiceInitRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1571,6 +3771,24 @@ func (x *aPIMutationInitServer) Send(m *MutationControl) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _API_MutationAck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MutationAckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).MutationAck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/MutationAck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).MutationAck(ctx, req.(*MutationAckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_DiscoveryInit_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(APIServer).DiscoveryInit(&aPIDiscoveryInitServer{stream})
 }
@@ -1679,12 +3897,93 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryNodeMutationPath",
 			Handler:    _API_QueryNodeMutationPath_Handler,
 		},
+		{
+			MethodName: "QueryMutationPlan",
+			Handler:    _API_QueryMutationPlan_Handler,
+		},
+		{
+			MethodName: "QueryEventLog",
+			Handler:    _API_QueryEventLog_Handler,
+		},
+		{
+			MethodName: "QueryMutationMetrics",
+			Handler:    _API_QueryMutationMetrics_Handler,
+		},
+		{
+			MethodName: "QueryDecommission",
+			Handler:    _API_QueryDecommission_Handler,
+		},
+		{
+			MethodName: "QueryRollups",
+			Handler:    _API_QueryRollups_Handler,
+		},
+		{
+			MethodName: "QueryDiscover",
+			Handler:    _API_QueryDiscover_Handler,
+		},
+		{
+			MethodName: "QueryAudit",
+			Handler:    _API_QueryAudit_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _API_QueryAuditLog_Handler,
+		},
+		{
+			MethodName: "QueryServiceStatus",
+			Handler:    _API_QueryServiceStatus_Handler,
+		},
+		{
+			MethodName: "QueryConvergence",
+			Handler:    _API_QueryConvergence_Handler,
+		},
 		{
 			MethodName: "QueryDeleteAll",
 			Handler:    _API_QueryDeleteAll_Handler,
 		},
+		{
+			MethodName: "QueryRestore",
+			Handler:    _API_QueryRestore_Handler,
+		},
+		{
+			MethodName: "QueryReadGroup",
+			Handler:    _API_QueryReadGroup_Handler,
+		},
+		{
+			MethodName: "QueryReadGroupDsc",
+			Handler:    _API_QueryReadGroupDsc_Handler,
+		},
+		{
+			MethodName: "QueryUpdateGroup",
+			Handler:    _API_QueryUpdateGroup_Handler,
+		},
+		{
+			MethodName: "QueryTransact",
+			Handler:    _API_QueryTransact_Handler,
+		},
+		{
+			MethodName: "QueryTransactDsc",
+			Handler:    _API_QueryTransactDsc_Handler,
+		},
+		{
+			MethodName: "QuerySelect",
+			Handler:    _API_QuerySelect_Handler,
+		},
+		{
+			MethodName: "QuerySelectDsc",
+			Handler:    _API_QuerySelectDsc_Handler,
+		},
+		{
+			MethodName: "MutationAck",
+			Handler:    _API_MutationAck_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _API_Watch_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ServiceInit",
 			Handler:       _API_ServiceInit_Handler,
@@ -1709,68 +4008,167 @@ var _API_serviceDesc = grpc.ServiceDesc{
 	Metadata: "API.proto",
 }
 
-func init() { proto.RegisterFile("API.proto", fileDescriptor_API_29aad8d6be19b05f) }
-
-var fileDescriptor_API_29aad8d6be19b05f = []byte{
-	// 946 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4b, 0x6f, 0xdb, 0x46,
-	0x10, 0x26, 0xf5, 0xb4, 0x46, 0xb6, 0xc2, 0x6e, 0x1c, 0x57, 0x56, 0x1a, 0xc0, 0xe1, 0x21, 0xb0,
-	0xd1, 0x40, 0x2e, 0xd4, 0x17, 0x8a, 0xa4, 0x0d, 0x14, 0x59, 0x80, 0xd5, 0xda, 0xae, 0xba, 0x96,
-	0x0e, 0x3d, 0x15, 0x34, 0xb9, 0xa6, 0xb6, 0xa0, 0xb8, 0x0a, 0xb9, 0x14, 0xaa, 0x63, 0xff, 0x4a,
-	0x0f, 0xfd, 0x33, 0xfd, 0x53, 0xc5, 0x3e, 0x28, 0x51, 0xef, 0xe4, 0xc4, 0x9d, 0xd7, 0x37, 0x33,
-	0xbb, 0x1f, 0x67, 0xa0, 0xd2, 0xee, 0xf7, 0x9a, 0x93, 0x88, 0x71, 0x86, 0x8a, 0xf2, 0xd3, 0x80,
-	0x3b, 0xe6, 0x11, 0xa5, 0x6a, 0x9c, 0xfa, 0x8c, 0xf9, 0x01, 0xb9, 0x94, 0xd2, 0x43, 0xf2, 0x78,
-	0xe9, 0x84, 0x33, 0x6d, 0x7a, 0xbe, 0x6a, 0xea, 0x8e, 0x27, 0x5c, 0x1b, 0xed, 0x7f, 0x73, 0x50,
-	0xfc, 0x2d, 0x21, 0xd1, 0x0c, 0x59, 0x90, 0x1f, 0xe2, 0x9b, 0xba, 0x79, 0x66, 0x9e, 0x57, 0xb0,
-	0x38, 0xa2, 0x97, 0x50, 0x08, 0x99, 0x47, 0xea, 0xb9, 0x33, 0xf3, 0xbc, 0xda, 0xaa, 0xaa, 0x88,
-	0xa6, 0x48, 0x7a, 0x6d, 0x60, 0x69, 0x42, 0xc7, 0x50, 0xe0, 0xe4, 0x2f, 0x5e, 0xcf, 0x8b, 0x28,
-	0xa1, 0x15, 0x12, 0xea, 0x82, 0x35, 0x4e, 0xb8, 0xc3, 0x29, 0x0b, 0x85, 0xf7, 0x0d, 0x8d, 0x79,
-	0xbd, 0x20, 0x41, 0x3e, 0xd7, 0x20, 0xb7, 0x2b, 0xe6, 0x6b, 0x03, 0xaf, 0x85, 0x64, 0x61, 0xba,
-	0x9e, 0xaf, 0x60, 0x8a, 0x1b, 0x61, 0x52, 0x73, 0x16, 0x26, 0xd5, 0xa1, 0x1f, 0xe0, 0x30, 0xd5,
-	0xf5, 0x1d, 0x3e, 0xaa, 0x97, 0x24, 0xc4, 0xd3, 0x15, 0x08, 0x61, 0xba, 0x36, 0xf0, 0x92, 0xeb,
-	0xfb, 0x0a, 0x94, 0x27, 0xce, 0x2c, 0x60, 0x8e, 0x67, 0x7f, 0x03, 0x20, 0xef, 0xe9, 0x36, 0x09,
-	0x38, 0x45, 0xaf, 0xa0, 0xfc, 0x21, 0x21, 0x11, 0x25, 0x71, 0xdd, 0x3c, 0xcb, 0x9f, 0x57, 0x5b,
-	0x87, 0x1a, 0x4e, 0xfa, 0xe0, 0xd4, 0x68, 0xbf, 0x05, 0x74, 0x4f, 0xa2, 0x29, 0x75, 0x49, 0x2f,
-	0xa4, 0x1c, 0x93, 0x0f, 0x09, 0x89, 0x39, 0xaa, 0x41, 0x8e, 0x7a, 0xfa, 0xa6, 0x73, 0xd4, 0x43,
-	0x27, 0x50, 0x1a, 0x33, 0x2f, 0x09, 0xd4, 0x55, 0x57, 0xb0, 0x96, 0xec, 0x7f, 0x4c, 0xa8, 0xe9,
-	0xf0, 0x0e, 0x0b, 0x79, 0xc4, 0x02, 0xf4, 0x3d, 0x94, 0x5d, 0x36, 0x1e, 0x3b, 0xa1, 0x8a, 0xaf,
-	0xb5, 0x5e, 0xe8, 0xc4, 0xcb, 0x7e, 0xcd, 0x8e, 0x72, 0xc2, 0xa9, 0x37, 0x7a, 0x0d, 0x25, 0x97,
-	0x85, 0x8f, 0xd4, 0xd7, 0xcf, 0x79, 0xdc, 0x54, 0xb4, 0x68, 0xa6, 0xb4, 0x68, 0xb6, 0xc3, 0x19,
-	0xd6, 0x3e, 0xf6, 0x05, 0x94, 0x35, 0x02, 0x3a, 0x80, 0xc2, 0xfd, 0xe0, 0xd7, 0xbe, 0x65, 0x20,
-	0x80, 0xd2, 0xb0, 0x7f, 0xd5, 0x1e, 0x74, 0x2d, 0x53, 0x68, 0x7b, 0x77, 0xbd, 0x81, 0x95, 0xb3,
-	0xff, 0x33, 0xe1, 0x49, 0x7a, 0x89, 0x69, 0x95, 0x8b, 0x86, 0xcc, 0x6c, 0x43, 0xba, 0xf1, 0xdc,
-	0xbc, 0xf1, 0x4b, 0x28, 0xf0, 0xd9, 0x84, 0x48, 0xfa, 0xd4, 0x5a, 0xcf, 0x57, 0x9e, 0x24, 0xed,
-	0x65, 0x30, 0x9b, 0x10, 0x2c, 0x1d, 0xd1, 0x0b, 0xc8, 0xbb, 0x8f, 0xbe, 0x26, 0x53, 0x96, 0x91,
-	0x58, 0xe8, 0x85, 0xd9, 0x8b, 0x5d, 0x4d, 0x92, 0x65, 0xb3, 0x17, 0xbb, 0xf6, 0x4b, 0x28, 0x08,
-	0x2c, 0xd1, 0xc8, 0xed, 0x70, 0x20, 0x1a, 0x31, 0xd0, 0x11, 0x54, 0x7a, 0x77, 0x83, 0x2e, 0xc6,
-	0xc3, 0xfe, 0xc0, 0x32, 0xed, 0x21, 0xd4, 0xae, 0x68, 0xec, 0xb2, 0x29, 0x89, 0x66, 0xdd, 0x29,
-	0x09, 0xf9, 0xd6, 0x5e, 0x2c, 0xc8, 0x27, 0x51, 0xa0, 0x9b, 0x11, 0x47, 0x74, 0x0a, 0x07, 0x53,
-	0x27, 0x48, 0xc8, 0x1f, 0xd4, 0x53, 0x3f, 0x04, 0x2e, 0x4b, 0xb9, 0xe7, 0xd9, 0xf7, 0x60, 0xad,
-	0x52, 0x1e, 0xbd, 0x5b, 0xd7, 0x69, 0x32, 0x3d, 0xdd, 0xf0, 0x97, 0xe0, 0x35, 0xe7, 0x2c, 0xe8,
-	0x9c, 0xec, 0xef, 0xd6, 0x75, 0x5b, 0x40, 0x85, 0x19, 0xaf, 0x39, 0xdb, 0xbf, 0xc0, 0x61, 0xf6,
-	0x97, 0x10, 0x6d, 0xba, 0x49, 0x24, 0x7b, 0xcf, 0x63, 0x71, 0x44, 0x17, 0x50, 0x74, 0x47, 0x0e,
-	0x0d, 0xeb, 0xb9, 0xed, 0xb8, 0xca, 0xc3, 0xfe, 0x79, 0x01, 0x76, 0xa7, 0xc6, 0x45, 0x31, 0x70,
-	0x1e, 0x48, 0xa0, 0xaf, 0x52, 0x09, 0x6b, 0xac, 0x38, 0x86, 0xa2, 0xcb, 0x02, 0x16, 0xe9, 0x4b,
-	0x54, 0x82, 0xfd, 0xe7, 0x02, 0x4b, 0xa4, 0x40, 0x08, 0x0a, 0x8f, 0x11, 0x1b, 0x6b, 0x28, 0x79,
-	0x16, 0x48, 0x9c, 0xa5, 0x48, 0x9c, 0x69, 0xe4, 0xfc, 0x1c, 0xf9, 0x55, 0x8a, 0xac, 0x08, 0x64,
-	0xe9, 0xd2, 0x05, 0x5e, 0x47, 0xe8, 0xd3, 0x5c, 0xbf, 0x43, 0x65, 0xae, 0x5b, 0x94, 0x63, 0x66,
-	0xca, 0x41, 0x5f, 0x40, 0x65, 0x44, 0xfd, 0x51, 0x40, 0xfd, 0x11, 0xd7, 0x19, 0x17, 0x0a, 0x54,
-	0x87, 0x32, 0x0d, 0x47, 0x24, 0xa2, 0x6a, 0x34, 0x1e, 0xe0, 0x54, 0xb4, 0x6f, 0x00, 0x6e, 0x98,
-	0x7f, 0x4b, 0xe2, 0xd8, 0xf1, 0x89, 0x20, 0x17, 0x8b, 0xa8, 0x4f, 0xc3, 0x94, 0x5c, 0x4a, 0x92,
-	0x17, 0x45, 0xa6, 0x44, 0xd1, 0xeb, 0x08, 0x2b, 0x41, 0xbc, 0xc5, 0x38, 0xf6, 0x75, 0x3f, 0xe2,
-	0xd8, 0xfa, 0xfb, 0x00, 0xf2, 0xed, 0x7e, 0x0f, 0x7d, 0x09, 0x55, 0x39, 0x79, 0x3a, 0x11, 0x71,
-	0x38, 0x41, 0x4b, 0xd3, 0xa8, 0xb1, 0x24, 0xd9, 0x06, 0xba, 0x80, 0x8a, 0x1a, 0x53, 0xc4, 0xf1,
-	0xf6, 0xb8, 0xbe, 0x86, 0xc3, 0xb9, 0xeb, 0x55, 0xec, 0xee, 0xf1, 0x4e, 0xab, 0x18, 0x4e, 0xbc,
-	0xfd, 0x55, 0x34, 0xa1, 0x96, 0x71, 0xfe, 0x78, 0xf0, 0x2b, 0x12, 0x90, 0xbd, 0xe0, 0x6f, 0x32,
-	0x75, 0xb7, 0x83, 0x00, 0x9d, 0xac, 0x4d, 0x3b, 0xb9, 0x04, 0x1b, 0x9f, 0x65, 0xe3, 0xe4, 0x68,
-	0xb7, 0x0d, 0xf4, 0x13, 0x3c, 0xc9, 0x06, 0x8b, 0xd2, 0x3e, 0x29, 0xfe, 0x2d, 0x20, 0x2d, 0x2f,
-	0xa8, 0x1f, 0x6f, 0x85, 0x58, 0x2d, 0x7d, 0x35, 0x5a, 0x10, 0xf1, 0xe3, 0xa3, 0xbf, 0x83, 0x13,
-	0x79, 0x14, 0x39, 0x97, 0xf3, 0xef, 0xbe, 0xb0, 0x4d, 0x71, 0x2a, 0xf3, 0xee, 0xb8, 0x6f, 0xe1,
-	0xd9, 0x5a, 0x9c, 0x9c, 0x1b, 0xbb, 0xc3, 0x7e, 0xd4, 0x8f, 0xaf, 0x1e, 0xf3, 0x93, 0x5f, 0xa8,
-	0x03, 0xd5, 0xcc, 0x5a, 0x45, 0xa7, 0xcb, 0x3b, 0x30, 0xb3, 0x6a, 0x1b, 0xcf, 0x36, 0xae, 0x47,
-	0xdb, 0xf8, 0xca, 0x44, 0xdd, 0xc5, 0x40, 0xd9, 0x87, 0x72, 0xb2, 0x79, 0x33, 0x49, 0x98, 0xf7,
-	0x70, 0x34, 0xdf, 0x18, 0x12, 0x27, 0x4d, 0xb9, 0xbc, 0x47, 0x1a, 0x5b, 0x1a, 0xb4, 0x8d, 0x73,
-	0x13, 0xbd, 0x91, 0x43, 0xc1, 0x27, 0x91, 0x04, 0x48, 0x5b, 0x5e, 0xcc, 0x89, 0x5d, 0xc1, 0x0f,
-	0x25, 0xa9, 0xfb, 0xfa, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8e, 0x91, 0x01, 0x1e, 0x21, 0x0a,
-	0x00, 0x00,
+func init() { proto.RegisterFile("API.proto", fileDescriptor_API_d34ab7906403fbbd) }
+
+var fileDescriptor_API_d34ab7906403fbbd = []byte{
+	// 2532 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x4b, 0x73, 0xdb, 0xc8,
+	0xf1, 0x17, 0x48, 0x50, 0x24, 0x9b, 0x92, 0x4c, 0x8d, 0xb5, 0x5a, 0x9a, 0xf6, 0xd6, 0x5f, 0x46,
+	0xd5, 0x7f, 0xa3, 0x4d, 0x5c, 0xb4, 0x23, 0x3f, 0xd6, 0xf1, 0x63, 0x1d, 0x59, 0xe2, 0xae, 0x54,
+	0x25, 0x69, 0x95, 0x11, 0xb5, 0xa9, 0x3d, 0xa4, 0x1c, 0x18, 0x18, 0x91, 0x28, 0x83, 0x18, 0x1a,
+	0x0f, 0xad, 0x99, 0x4b, 0x4e, 0xb9, 0xe5, 0x96, 0x8f, 0x90, 0xaa, 0x9c, 0x72, 0x4b, 0x0e, 0x39,
+	0xe4, 0x94, 0x53, 0xbe, 0x44, 0x3e, 0x45, 0x2e, 0x39, 0xa5, 0x52, 0xf3, 0x02, 0x06, 0x24, 0x28,
+	0x52, 0x9b, 0x43, 0x4e, 0x98, 0x9e, 0xe9, 0xee, 0xe9, 0xf9, 0x4d, 0x77, 0x4f, 0xcf, 0x00, 0xea,
+	0xbb, 0xa7, 0x87, 0x9d, 0x51, 0x48, 0x63, 0x8a, 0x2a, 0xfc, 0xd3, 0x86, 0x13, 0xea, 0x12, 0xd1,
+	0xd5, 0xbe, 0xd5, 0xa7, 0xb4, 0xef, 0x93, 0xfb, 0x9c, 0x7a, 0x9b, 0x5c, 0xdc, 0xb7, 0x83, 0xb1,
+	0x1c, 0xba, 0x3d, 0x39, 0xd4, 0x1d, 0x8e, 0x62, 0x35, 0xf8, 0x7f, 0x93, 0x83, 0xb1, 0x37, 0x24,
+	0x51, 0x6c, 0x0f, 0x47, 0x82, 0xc1, 0xfa, 0x43, 0x09, 0x2a, 0x3f, 0x4b, 0x48, 0x38, 0x46, 0x4d,
+	0x28, 0x9f, 0xe3, 0xa3, 0x96, 0xb1, 0x65, 0x6c, 0xd7, 0x31, 0x6b, 0xa2, 0xbb, 0x60, 0x06, 0xd4,
+	0x25, 0xad, 0xd2, 0x96, 0xb1, 0xdd, 0xd8, 0x69, 0x08, 0x89, 0x0e, 0xb3, 0xea, 0x60, 0x09, 0xf3,
+	0x21, 0xb4, 0x01, 0x66, 0x4c, 0x3e, 0xc4, 0xad, 0x32, 0x93, 0x62, 0xbd, 0x8c, 0x42, 0x5d, 0x68,
+	0x0e, 0x93, 0xd8, 0x8e, 0x3d, 0x1a, 0x30, 0xee, 0x23, 0x2f, 0x8a, 0x5b, 0x26, 0x57, 0xf2, 0xb1,
+	0x54, 0x72, 0x3c, 0x31, 0x7c, 0xb0, 0x84, 0xa7, 0x44, 0x74, 0x35, 0x5d, 0xb7, 0x2f, 0xd4, 0x54,
+	0x0a, 0xd5, 0xa8, 0x61, 0x5d, 0x8d, 0xea, 0x43, 0x3f, 0x81, 0x15, 0xd5, 0x77, 0x6a, 0xc7, 0x83,
+	0xd6, 0x32, 0x57, 0x71, 0x73, 0x42, 0x05, 0x1b, 0x3a, 0x58, 0xc2, 0x39, 0xd6, 0xd7, 0x75, 0xa8,
+	0x8e, 0xec, 0xb1, 0x4f, 0x6d, 0xd7, 0x7a, 0x04, 0xc0, 0x71, 0x3a, 0x4e, 0xfc, 0xd8, 0x43, 0x9f,
+	0x42, 0xf5, 0x7d, 0x42, 0x42, 0x8f, 0x44, 0x2d, 0x63, 0xab, 0xbc, 0xdd, 0xd8, 0x59, 0x91, 0xea,
+	0x38, 0x0f, 0x56, 0x83, 0xd6, 0x0b, 0x40, 0x67, 0x24, 0xbc, 0xf4, 0x1c, 0x72, 0x18, 0x78, 0x31,
+	0x26, 0xef, 0x13, 0x12, 0xc5, 0x68, 0x0d, 0x4a, 0x9e, 0x2b, 0x91, 0x2e, 0x79, 0x2e, 0xda, 0x84,
+	0xe5, 0x21, 0x75, 0x13, 0x5f, 0x40, 0x5d, 0xc7, 0x92, 0xb2, 0xfe, 0x6d, 0xc0, 0x9a, 0x14, 0xdf,
+	0xa3, 0x41, 0x1c, 0x52, 0x1f, 0x7d, 0x0e, 0x55, 0x87, 0x0e, 0x87, 0x76, 0x20, 0xe4, 0xd7, 0x76,
+	0x3e, 0x91, 0x13, 0xe7, 0xf9, 0x3a, 0x7b, 0x82, 0x09, 0x2b, 0x6e, 0x74, 0x0f, 0x96, 0x1d, 0x1a,
+	0x5c, 0x78, 0x7d, 0xb9, 0x9d, 0x1b, 0x1d, 0xe1, 0x1a, 0x1d, 0xe5, 0x1a, 0x9d, 0xdd, 0x60, 0x8c,
+	0x25, 0x0f, 0x7a, 0x02, 0x35, 0x97, 0xd8, 0xae, 0xef, 0x05, 0x84, 0xef, 0x6d, 0x63, 0xa7, 0x3d,
+	0xc5, 0xdf, 0x53, 0xae, 0x84, 0x53, 0x5e, 0xb4, 0x01, 0x15, 0xe6, 0x17, 0x51, 0xcb, 0xdc, 0x2a,
+	0x6f, 0xd7, 0xb1, 0x20, 0xac, 0xcf, 0xa1, 0x2a, 0xed, 0x41, 0x35, 0x30, 0xcf, 0x7a, 0x5f, 0x9f,
+	0x36, 0x97, 0x10, 0xc0, 0xf2, 0xf9, 0xe9, 0xfe, 0x6e, 0xaf, 0xdb, 0x34, 0x58, 0xef, 0xe1, 0xc9,
+	0x61, 0xaf, 0x59, 0x42, 0x2b, 0x50, 0xdb, 0x3f, 0x3c, 0xdb, 0xfb, 0xfa, 0x9b, 0x2e, 0x6e, 0x96,
+	0xad, 0x7f, 0x18, 0x70, 0x43, 0x6d, 0x90, 0x42, 0x20, 0x03, 0xcb, 0xd0, 0xc1, 0x92, 0xa0, 0x96,
+	0x52, 0x50, 0xef, 0x83, 0x19, 0x8f, 0x47, 0xc2, 0xfc, 0xb5, 0x9d, 0xdb, 0x13, 0xdb, 0xad, 0x70,
+	0xea, 0x8d, 0x47, 0x04, 0x73, 0x46, 0xf4, 0x09, 0x94, 0x9d, 0x8b, 0x7e, 0xcb, 0x9c, 0xf2, 0x76,
+	0xcc, 0xfa, 0xd9, 0xb0, 0x1b, 0x39, 0xad, 0x4a, 0xc1, 0xb0, 0x1b, 0x39, 0x2c, 0x7c, 0x22, 0xf2,
+	0x9e, 0x3b, 0x97, 0x89, 0x59, 0xd3, 0xba, 0x0b, 0x26, 0xd3, 0xce, 0x16, 0x7a, 0x7c, 0xde, 0x63,
+	0x0b, 0x5d, 0x42, 0xab, 0x50, 0x3f, 0x3c, 0xe9, 0x75, 0x31, 0x3e, 0x3f, 0xed, 0x35, 0x0d, 0xeb,
+	0x0b, 0x40, 0xca, 0xa0, 0x5d, 0xe7, 0x9d, 0x72, 0x8f, 0x59, 0x2b, 0x94, 0x53, 0x94, 0xb2, 0x29,
+	0x7e, 0x63, 0xc0, 0xda, 0xbe, 0x17, 0x39, 0xf4, 0x92, 0x84, 0xe3, 0xee, 0x25, 0x09, 0xae, 0x14,
+	0x4e, 0x42, 0x5f, 0xe2, 0xc3, 0x9a, 0xe8, 0x16, 0xd4, 0x2e, 0x6d, 0x3f, 0x21, 0x6f, 0x3c, 0x57,
+	0xc4, 0x2f, 0xae, 0x72, 0xfa, 0xd0, 0x45, 0x1d, 0x30, 0x59, 0xa2, 0x68, 0x99, 0x73, 0xb7, 0x9e,
+	0xf3, 0x59, 0x67, 0xd0, 0x9c, 0x8c, 0x68, 0xf4, 0x6a, 0xba, 0x4f, 0xc6, 0xca, 0xcd, 0x82, 0x24,
+	0x80, 0xa7, 0x98, 0x75, 0xa5, 0x69, 0x2c, 0xbf, 0x9a, 0xee, 0x9b, 0xa1, 0x94, 0x0d, 0xe3, 0x29,
+	0x66, 0xeb, 0xb7, 0x25, 0x58, 0xd1, 0x43, 0x9e, 0xe1, 0xe2, 0x24, 0x21, 0x07, 0xab, 0x8c, 0x59,
+	0x13, 0x7d, 0x06, 0x15, 0x67, 0x60, 0x7b, 0x41, 0xab, 0x34, 0x5b, 0xb1, 0xe0, 0x40, 0x8f, 0xa0,
+	0x1a, 0xc5, 0x76, 0x18, 0x13, 0x77, 0x81, 0x28, 0x51, 0xac, 0xe8, 0x39, 0x34, 0x9c, 0x24, 0x7c,
+	0xa3, 0x24, 0xe7, 0x83, 0x0c, 0x4e, 0x12, 0x9e, 0x49, 0xe1, 0x0d, 0xa8, 0xc4, 0x3c, 0xef, 0x30,
+	0x47, 0x5c, 0xc5, 0x82, 0x40, 0x6d, 0xa8, 0xc5, 0x83, 0x90, 0xc6, 0xb1, 0x4f, 0xb8, 0x0b, 0xd6,
+	0x71, 0x4a, 0xb3, 0xb1, 0x90, 0xfa, 0xfe, 0x5b, 0xdb, 0x79, 0xd7, 0xaa, 0x6e, 0x19, 0xdb, 0x35,
+	0x9c, 0xd2, 0xd6, 0xdf, 0x34, 0x38, 0x4e, 0x44, 0x42, 0xaf, 0xf8, 0xf6, 0x5b, 0xe2, 0x4b, 0xef,
+	0x11, 0xc4, 0x54, 0x6c, 0x6d, 0x40, 0xc5, 0xa1, 0x3e, 0x0d, 0xa5, 0xdf, 0x08, 0x02, 0xbd, 0x84,
+	0x5a, 0x48, 0xde, 0x27, 0x5e, 0x28, 0xe3, 0xbf, 0xb1, 0x73, 0xb7, 0x60, 0xa7, 0x3b, 0x58, 0xf2,
+	0x74, 0x83, 0x38, 0x1c, 0xe3, 0x54, 0x84, 0x89, 0x93, 0x0f, 0x8e, 0x9f, 0xb8, 0x7c, 0x71, 0x33,
+	0xc5, 0xbb, 0x92, 0x47, 0x8a, 0x2b, 0x91, 0xf6, 0x73, 0x58, 0xcd, 0x69, 0x66, 0x3b, 0xfb, 0x8e,
+	0x8c, 0xd5, 0x81, 0xf6, 0x8e, 0x8c, 0x99, 0xd9, 0xdc, 0xc3, 0xe5, 0x4a, 0x04, 0xf1, 0xac, 0xf4,
+	0xd4, 0x60, 0xc2, 0x39, 0xbd, 0xd7, 0x11, 0xb6, 0xfe, 0x6a, 0xc2, 0x8a, 0xee, 0x1d, 0x08, 0x81,
+	0x79, 0x11, 0xd2, 0xa1, 0x94, 0xe6, 0x6d, 0x06, 0x61, 0x4c, 0x15, 0x84, 0x31, 0x95, 0x90, 0x96,
+	0x53, 0x48, 0x3f, 0x55, 0x90, 0x0a, 0x77, 0x68, 0xca, 0xa5, 0x33, 0x7d, 0x7b, 0xac, 0x5f, 0x81,
+	0x9c, 0xc5, 0x77, 0x25, 0x17, 0xdf, 0x6d, 0xa8, 0xa9, 0xa3, 0x4b, 0x79, 0x80, 0xa2, 0x51, 0x0b,
+	0xaa, 0x2c, 0x4c, 0x69, 0x12, 0x73, 0x07, 0xa8, 0x63, 0x45, 0xa2, 0x67, 0x50, 0xe5, 0x5c, 0x24,
+	0x6a, 0xd5, 0x38, 0xe4, 0x5b, 0x05, 0xde, 0x2e, 0x08, 0x85, 0xb8, 0x12, 0xc8, 0x6d, 0x77, 0xbd,
+	0x70, 0xbf, 0xb8, 0xf0, 0x22, 0xdb, 0x0d, 0xb3, 0xc5, 0x67, 0x6c, 0x37, 0xc3, 0xd8, 0xa1, 0x51,
+	0xdc, 0x6a, 0xf0, 0x30, 0xe0, 0xed, 0xf6, 0x33, 0xb9, 0x0f, 0xdf, 0xd3, 0x03, 0xfe, 0x47, 0xee,
+	0xf3, 0x2d, 0xd4, 0xd3, 0x5d, 0xce, 0x22, 0xcb, 0xd0, 0x23, 0xeb, 0x0e, 0xd4, 0x07, 0x5e, 0x7f,
+	0xe0, 0x7b, 0xfd, 0x41, 0x2c, 0x15, 0x64, 0x1d, 0x6c, 0x7b, 0xbd, 0x60, 0x40, 0x42, 0x4f, 0xd4,
+	0x61, 0x35, 0xac, 0x48, 0xeb, 0xf7, 0x06, 0x34, 0xf8, 0xb1, 0x80, 0x89, 0x43, 0xc3, 0x2c, 0xaf,
+	0x1b, 0x8b, 0xe5, 0x75, 0x06, 0x32, 0x3f, 0x43, 0xc5, 0x94, 0xbc, 0xcd, 0xfa, 0x78, 0x55, 0x28,
+	0x5c, 0x97, 0xb7, 0xd5, 0xe1, 0x62, 0x66, 0x87, 0xcb, 0x2c, 0x37, 0x45, 0x60, 0xba, 0x76, 0x6c,
+	0x4b, 0x17, 0xe5, 0x6d, 0xeb, 0x15, 0xdc, 0xd0, 0x8c, 0xe4, 0x79, 0xfe, 0x1e, 0x54, 0x43, 0x4e,
+	0xa9, 0xfa, 0x0a, 0xa9, 0x78, 0xc8, 0x18, 0xb1, 0x62, 0xb1, 0x7e, 0x0d, 0xab, 0xbc, 0xff, 0x88,
+	0xf6, 0x45, 0x2d, 0xab, 0x6c, 0x34, 0x34, 0x1b, 0x3b, 0x32, 0x28, 0x4b, 0xf3, 0xd7, 0xce, 0x03,
+	0xf6, 0x87, 0x3c, 0x60, 0xe7, 0xa7, 0xf5, 0x52, 0x4c, 0xad, 0x7f, 0x1a, 0xd0, 0xd8, 0x4d, 0x5c,
+	0xef, 0xbf, 0xc0, 0x39, 0x89, 0x48, 0xa8, 0x70, 0x66, 0x6d, 0x86, 0x60, 0x44, 0x93, 0xd0, 0x51,
+	0x48, 0x4b, 0x4a, 0x43, 0xd6, 0xcc, 0x21, 0xbb, 0x09, 0xcb, 0xb6, 0xc3, 0xc3, 0x5f, 0x22, 0x2e,
+	0xa8, 0x14, 0x8b, 0x65, 0x0d, 0x8b, 0x7b, 0x50, 0x75, 0x06, 0x76, 0xd0, 0x27, 0x51, 0xab, 0x9a,
+	0x83, 0x97, 0x2f, 0x62, 0x8f, 0x0f, 0x61, 0xc5, 0xc2, 0x34, 0x87, 0x24, 0x4a, 0xfc, 0xb8, 0x55,
+	0x13, 0x9a, 0x05, 0x65, 0x7d, 0x0b, 0x0d, 0x8d, 0x5f, 0x39, 0x81, 0x91, 0x39, 0xc1, 0x6d, 0xa8,
+	0x53, 0xdf, 0x7d, 0xa3, 0xfb, 0x7d, 0x8d, 0xfa, 0xee, 0x37, 0x8c, 0x66, 0x83, 0x01, 0xf9, 0x4e,
+	0x0e, 0x8a, 0x25, 0xd6, 0x02, 0xf2, 0x1d, 0x1f, 0x64, 0x2e, 0xa1, 0xe1, 0x79, 0xb5, 0x4b, 0x68,
+	0x8c, 0x99, 0x4b, 0xfc, 0xce, 0x80, 0x55, 0x3e, 0x70, 0xa5, 0x4f, 0x14, 0xe1, 0xae, 0xfc, 0xa4,
+	0x7c, 0x2d, 0x3f, 0x31, 0x17, 0xf2, 0x13, 0xbd, 0x9e, 0x3d, 0x26, 0x71, 0xe8, 0x39, 0x11, 0x7a,
+	0x00, 0x95, 0xc8, 0x0b, 0x9c, 0x45, 0x9c, 0x45, 0x30, 0x32, 0x09, 0xe2, 0xb2, 0xbd, 0x13, 0x05,
+	0x4a, 0xbb, 0x20, 0x6d, 0x4a, 0xe5, 0x58, 0x30, 0xa2, 0x7b, 0x50, 0x73, 0x68, 0x70, 0x49, 0xc2,
+	0xbe, 0x2a, 0xe7, 0xd5, 0xf9, 0x72, 0xe0, 0x45, 0x31, 0xed, 0x87, 0xf6, 0x10, 0xa7, 0x1c, 0x68,
+	0x0b, 0x1a, 0xae, 0x2c, 0x2a, 0x3d, 0x7e, 0x94, 0xb3, 0x7a, 0x53, 0xef, 0x52, 0xbe, 0x76, 0x49,
+	0x64, 0x15, 0x22, 0x29, 0xeb, 0x4f, 0x06, 0xdc, 0x2c, 0x30, 0x63, 0x66, 0x51, 0xaa, 0x1f, 0x5a,
+	0xa5, 0xe9, 0x43, 0x4b, 0xaf, 0xad, 0xcc, 0xac, 0x7e, 0xba, 0x03, 0xf5, 0x28, 0x71, 0x1c, 0x42,
+	0x5c, 0x59, 0x3d, 0x99, 0x38, 0xeb, 0x60, 0x73, 0x5d, 0xd8, 0x9e, 0x4f, 0x5c, 0x6e, 0x9b, 0x89,
+	0x25, 0xc5, 0xf4, 0x85, 0xcc, 0x1c, 0xe2, 0xca, 0x22, 0x5d, 0x91, 0x96, 0x03, 0xf5, 0x14, 0x06,
+	0x26, 0xfe, 0x96, 0x26, 0x81, 0xf4, 0x32, 0x03, 0x4b, 0x8a, 0xf5, 0x3b, 0x34, 0x09, 0x62, 0x81,
+	0xba, 0x89, 0x25, 0x25, 0x12, 0x76, 0x12, 0xc4, 0xd2, 0x48, 0x41, 0xf0, 0x52, 0x3d, 0x19, 0x72,
+	0xe3, 0x0c, 0xcc, 0x9a, 0xd6, 0x3e, 0x34, 0x7a, 0xa1, 0x1d, 0x44, 0x32, 0x2a, 0xef, 0xaa, 0x8b,
+	0x92, 0xf0, 0xe5, 0xdc, 0x7d, 0x42, 0x8c, 0x70, 0xe7, 0x0c, 0x7d, 0x31, 0x1f, 0x73, 0xce, 0xd0,
+	0x8f, 0xac, 0x3f, 0x96, 0x60, 0x19, 0x53, 0xdf, 0x4f, 0x46, 0x0c, 0x3b, 0x9f, 0x3a, 0x02, 0x3b,
+	0x81, 0x6a, 0x4a, 0x67, 0xd7, 0xb0, 0x92, 0x28, 0x12, 0x85, 0xc2, 0xe7, 0x00, 0xa3, 0xc1, 0x38,
+	0x62, 0x85, 0x67, 0xcc, 0xfc, 0x80, 0x4d, 0x7c, 0x47, 0x4e, 0x2c, 0x94, 0x76, 0x4e, 0x07, 0xe3,
+	0xe8, 0x8c, 0x0d, 0x8b, 0xe3, 0xb6, 0x3e, 0x52, 0x34, 0x7a, 0x0a, 0xf5, 0x30, 0x09, 0xa4, 0xac,
+	0xa8, 0xee, 0x6e, 0xe7, 0x65, 0x71, 0x12, 0x68, 0xa2, 0xb5, 0x50, 0x92, 0xed, 0x17, 0xb0, 0x96,
+	0x57, 0x3b, 0xef, 0x74, 0x5c, 0x9d, 0x3c, 0x97, 0x93, 0xe0, 0xfb, 0x09, 0x5b, 0x8f, 0x01, 0x84,
+	0x71, 0x3c, 0x83, 0xfc, 0x00, 0xaa, 0x21, 0xa7, 0x14, 0xea, 0xab, 0xb9, 0x05, 0x60, 0x35, 0x6a,
+	0xfd, 0xd9, 0x80, 0x9b, 0xfb, 0x84, 0xdd, 0x9c, 0xbd, 0x28, 0xf2, 0x68, 0x30, 0xeb, 0xde, 0xfe,
+	0x1c, 0x2a, 0x17, 0x5e, 0x60, 0xfb, 0x32, 0x10, 0xff, 0x5f, 0xaa, 0x2b, 0x10, 0xed, 0x7c, 0xc9,
+	0xf8, 0x04, 0x32, 0x42, 0x46, 0x64, 0xd5, 0x21, 0xbd, 0x24, 0xf2, 0xd0, 0x96, 0x54, 0xfb, 0x29,
+	0x40, 0xc6, 0x7c, 0xad, 0x42, 0xe2, 0x15, 0xdc, 0x50, 0x97, 0x41, 0x65, 0xf1, 0x86, 0xee, 0x66,
+	0xea, 0x3e, 0x3e, 0xf3, 0xbd, 0xa1, 0x03, 0xcd, 0x4c, 0x41, 0x34, 0xa2, 0x41, 0xc4, 0x43, 0x34,
+	0x12, 0x4f, 0x0b, 0x4a, 0x49, 0x4a, 0x5b, 0x07, 0xd0, 0xdc, 0x93, 0x49, 0x23, 0x70, 0xc8, 0x3e,
+	0xf1, 0x63, 0xbb, 0xe0, 0x14, 0x68, 0x8a, 0x7b, 0xb5, 0xbc, 0x79, 0xb2, 0xab, 0x74, 0x53, 0x5c,
+	0xa5, 0x45, 0xd2, 0x67, 0x4d, 0xeb, 0x5f, 0x06, 0xdc, 0x60, 0xbe, 0xaf, 0xa9, 0x9b, 0x42, 0xbb,
+	0x0d, 0x35, 0x66, 0x7e, 0x60, 0x0f, 0xd3, 0xc3, 0x44, 0xd1, 0x2c, 0x25, 0xa8, 0xf4, 0xe5, 0x4a,
+	0x3c, 0xb3, 0x0e, 0x74, 0x1f, 0x96, 0x5d, 0x66, 0x9c, 0xba, 0x96, 0xa8, 0xe7, 0xa3, 0x49, 0xe3,
+	0xb1, 0x64, 0xe3, 0xcf, 0x1f, 0x9e, 0xd4, 0x56, 0x59, 0xe0, 0xf9, 0x43, 0xf2, 0x66, 0xf9, 0x2c,
+	0xe8, 0xf3, 0x24, 0x53, 0xc3, 0x29, 0xcd, 0xf3, 0x52, 0x48, 0x7f, 0x45, 0x02, 0x79, 0x09, 0x93,
+	0x94, 0xb5, 0x0b, 0xeb, 0x9a, 0x1d, 0x98, 0x8c, 0x68, 0xc8, 0x0e, 0xbb, 0x5c, 0x7a, 0xd8, 0xd4,
+	0xd2, 0x83, 0xce, 0x2c, 0x98, 0xac, 0xbf, 0x94, 0x60, 0x55, 0xbe, 0xff, 0xb0, 0x40, 0x49, 0xa2,
+	0x45, 0x5f, 0x98, 0xd0, 0x43, 0x58, 0x8e, 0xb8, 0xc4, 0xc4, 0x33, 0x49, 0x4e, 0x5b, 0x47, 0x7c,
+	0xb0, 0x64, 0x65, 0xdb, 0x37, 0xf2, 0x44, 0xe6, 0xad, 0x60, 0xd6, 0x64, 0xeb, 0x0e, 0x09, 0x4f,
+	0xcf, 0xea, 0x5e, 0x9a, 0xd2, 0xec, 0x9c, 0xf7, 0xed, 0x28, 0x7e, 0x43, 0x3e, 0x78, 0xb1, 0xba,
+	0x99, 0xb0, 0x8e, 0xee, 0x07, 0x2f, 0xce, 0x0e, 0xbf, 0xea, 0x82, 0x87, 0x9f, 0x75, 0x0c, 0xcb,
+	0x72, 0x8d, 0x0d, 0xa8, 0xb2, 0xa7, 0xa4, 0xd3, 0xee, 0x7e, 0x73, 0x89, 0xbd, 0x1b, 0x9d, 0xf5,
+	0x76, 0x71, 0xef, 0xf0, 0xe4, 0xab, 0xa6, 0xc1, 0x86, 0xf0, 0xf9, 0xc9, 0x09, 0x23, 0x4a, 0x68,
+	0x0d, 0x00, 0x77, 0xd3, 0xc1, 0x32, 0x1b, 0xdc, 0xc3, 0xbb, 0x67, 0x07, 0xdd, 0xfd, 0xa6, 0x69,
+	0x75, 0x61, 0x3d, 0xb7, 0x56, 0x9e, 0x28, 0x1e, 0x4c, 0xf8, 0x3c, 0x7b, 0x2d, 0x2b, 0xc0, 0x45,
+	0x8b, 0x84, 0x5f, 0xc2, 0xca, 0xcf, 0xed, 0xd8, 0x19, 0xa8, 0xb8, 0x2b, 0x2a, 0x36, 0xa6, 0x5f,
+	0x60, 0xd2, 0x50, 0x2e, 0x6b, 0xa1, 0xcc, 0x7a, 0x59, 0xa1, 0x9d, 0xbe, 0xa1, 0x71, 0xc2, 0xfa,
+	0x05, 0x34, 0xd8, 0xac, 0x44, 0x16, 0x5b, 0xaa, 0x32, 0x37, 0x0a, 0x2a, 0xf3, 0xd2, 0xf4, 0xa4,
+	0xe5, 0x82, 0x49, 0x4d, 0x6d, 0x52, 0xeb, 0x08, 0xe0, 0x88, 0xf6, 0x8f, 0x49, 0x14, 0xd9, 0x7d,
+	0x5e, 0x4b, 0xd2, 0xd0, 0xeb, 0x7b, 0xea, 0x64, 0x91, 0x14, 0x93, 0xf5, 0xc9, 0x25, 0xf1, 0x55,
+	0xa6, 0xe5, 0x04, 0x9b, 0x63, 0x18, 0xf5, 0xd5, 0x1c, 0xc3, 0xa8, 0xbf, 0xf3, 0xf7, 0x75, 0x28,
+	0xef, 0x9e, 0x1e, 0xa2, 0x1f, 0x41, 0x83, 0x17, 0x5f, 0x7b, 0x21, 0x61, 0x67, 0x48, 0xee, 0x91,
+	0xb4, 0x9d, 0xa3, 0xac, 0x25, 0xf4, 0x19, 0xd4, 0x79, 0x13, 0x13, 0xdb, 0x9d, 0xc3, 0x7a, 0x0f,
+	0x56, 0x52, 0xd6, 0xfd, 0xc8, 0x99, 0xc3, 0xad, 0xac, 0x38, 0x1f, 0xb9, 0xf3, 0xad, 0xe8, 0xc0,
+	0x9a, 0xc6, 0xbc, 0xb8, 0xf2, 0x7d, 0xe2, 0x93, 0xb9, 0xca, 0x9f, 0x6b, 0x76, 0xef, 0xfa, 0x3e,
+	0xda, 0x9c, 0xf2, 0x77, 0xfe, 0x78, 0xdf, 0x5e, 0xd7, 0xe5, 0xf8, 0x8b, 0xb3, 0xb5, 0x84, 0xbe,
+	0x80, 0x1b, 0xba, 0x30, 0x33, 0xed, 0x5a, 0xf2, 0x2f, 0x00, 0x49, 0x3a, 0x7b, 0x4d, 0x89, 0x66,
+	0xaa, 0x98, 0x34, 0x7d, 0x52, 0xba, 0xeb, 0xf6, 0xaf, 0x21, 0xfd, 0x04, 0x36, 0x79, 0x93, 0xcd,
+	0x99, 0x9f, 0xff, 0x6a, 0xc0, 0x8a, 0xe4, 0xc4, 0xcc, 0x57, 0xcb, 0x3d, 0x86, 0x8f, 0xa6, 0xe4,
+	0xf8, 0x73, 0xdf, 0xd5, 0x62, 0x3f, 0x86, 0xf5, 0xdc, 0x22, 0x4f, 0x7d, 0x3b, 0x98, 0x23, 0xf2,
+	0x0a, 0x56, 0x79, 0x53, 0x5d, 0x40, 0xd1, 0x86, 0x7e, 0x53, 0x55, 0xb7, 0x8f, 0xf6, 0xe6, 0xf4,
+	0xfd, 0x95, 0xbf, 0x47, 0x2e, 0xa1, 0x03, 0xd8, 0xc8, 0xcd, 0x99, 0xd6, 0xcc, 0x33, 0xa0, 0xdd,
+	0x9c, 0x28, 0xf7, 0x25, 0x3f, 0x37, 0x65, 0x5d, 0xba, 0x62, 0x56, 0x7f, 0xa0, 0xf6, 0xec, 0xa2,
+	0xa4, 0x60, 0xf9, 0xd2, 0x3d, 0x45, 0x1d, 0x34, 0xb1, 0xf2, 0xf5, 0x5c, 0xb5, 0x24, 0xad, 0x7f,
+	0x2d, 0x97, 0xaf, 0xea, 0x06, 0xa4, 0xcc, 0x9b, 0xa8, 0x44, 0xda, 0x1f, 0x4f, 0xf5, 0x8b, 0x02,
+	0x83, 0x3b, 0xb6, 0xf8, 0xb5, 0xc2, 0xef, 0x6b, 0xa9, 0x82, 0x89, 0xfb, 0x5f, 0x7b, 0x06, 0x1e,
+	0xda, 0x16, 0xa8, 0xfb, 0x5e, 0xba, 0x05, 0xb9, 0x0b, 0x60, 0x7b, 0x86, 0x62, 0xbe, 0x05, 0xc2,
+	0xb7, 0xf3, 0x67, 0xe8, 0xac, 0x0d, 0x68, 0x15, 0x9d, 0x05, 0x52, 0xd3, 0x97, 0xd0, 0x14, 0x09,
+	0x4f, 0xab, 0x63, 0xe6, 0xe9, 0x99, 0x3a, 0xfd, 0xad, 0x25, 0xf4, 0x12, 0xd6, 0xb4, 0xac, 0x72,
+	0xed, 0x54, 0xf1, 0x24, 0xcd, 0x33, 0x51, 0x4c, 0x43, 0x82, 0xa6, 0x99, 0x8a, 0xe5, 0x1e, 0xc2,
+	0x5a, 0x9a, 0x62, 0xbe, 0x0a, 0x69, 0x32, 0x9a, 0xe1, 0x02, 0x13, 0x93, 0xad, 0xe7, 0x85, 0xa6,
+	0x93, 0x66, 0xa1, 0xdc, 0x63, 0x68, 0x6a, 0x99, 0x76, 0xe1, 0xe9, 0x5e, 0xca, 0xdd, 0x56, 0xb7,
+	0x29, 0xa4, 0xde, 0x01, 0xb4, 0xeb, 0xd5, 0x15, 0xce, 0xf2, 0x53, 0x39, 0xab, 0xe2, 0x66, 0xc6,
+	0x5e, 0x4f, 0xc3, 0x03, 0x99, 0xf1, 0xcf, 0x88, 0x4f, 0x9c, 0x78, 0x11, 0x93, 0x15, 0xac, 0x42,
+	0x62, 0x41, 0x78, 0x1e, 0x41, 0x85, 0x97, 0x14, 0x48, 0xfd, 0x80, 0xd0, 0x0b, 0x8c, 0xb6, 0x32,
+	0x59, 0xab, 0x09, 0xac, 0xa5, 0x07, 0x06, 0xda, 0x83, 0x86, 0xf6, 0xc3, 0x11, 0xdd, 0xca, 0xfb,
+	0xaa, 0xf6, 0x13, 0xb2, 0xfd, 0x51, 0xe1, 0x8f, 0x43, 0xae, 0xa4, 0x9b, 0xbd, 0x67, 0xcf, 0xd3,
+	0xb2, 0x59, 0xfc, 0x5f, 0x8d, 0xab, 0x79, 0x0d, 0x0d, 0xed, 0xef, 0x56, 0xaa, 0x65, 0xfa, 0x8f,
+	0xd7, 0x15, 0x60, 0xbf, 0x86, 0xd5, 0xf4, 0x07, 0x17, 0xb7, 0xe5, 0xa3, 0x89, 0x3c, 0x22, 0x12,
+	0xef, 0x6c, 0x0d, 0xdb, 0x06, 0xbb, 0xf7, 0x1e, 0xd1, 0x7e, 0x9f, 0x84, 0x5c, 0x81, 0x02, 0x3b,
+	0x2b, 0x77, 0xae, 0x12, 0x7e, 0xbb, 0xcc, 0xfb, 0x1e, 0xfe, 0x67, 0x00, 0x40, 0xc2, 0x40, 0xd2,
+	0xa0, 0x1f, 0x00, 0x00,
 }
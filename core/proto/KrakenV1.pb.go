@@ -0,0 +1,860 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: KrakenV1.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import empty "github.com/golang/protobuf/ptypes/empty"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Which state of a node to read
+type StateKind int32
+
+const (
+	StateKind_CFG StateKind = 0
+	StateKind_DSC StateKind = 1
+)
+
+var StateKind_name = map[int32]string{
+	0: "CFG",
+	1: "DSC",
+}
+var StateKind_value = map[string]int32{
+	"CFG": 0,
+	"DSC": 1,
+}
+
+func (x StateKind) String() string {
+	return proto.EnumName(StateKind_name, int32(x))
+}
+func (StateKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{0}
+}
+
+type NodeRequest struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State                StateKind `protobuf:"varint,2,opt,name=state,proto3,enum=kraken.v1.StateKind" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *NodeRequest) Reset()         { *m = NodeRequest{} }
+func (m *NodeRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeRequest) ProtoMessage()    {}
+func (*NodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{0}
+}
+func (m *NodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeRequest.Unmarshal(m, b)
+}
+func (m *NodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeRequest.Merge(dst, src)
+}
+func (m *NodeRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeRequest.Size(m)
+}
+func (m *NodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeRequest proto.InternalMessageInfo
+
+func (m *NodeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeRequest) GetState() StateKind {
+	if m != nil {
+		return m.State
+	}
+	return StateKind_CFG
+}
+
+// A ListNodesRequest lists nodes in a state; nodes have to match all of group and select, if they're given
+type ListNodesRequest struct {
+	State                StateKind `protobuf:"varint,1,opt,name=state,proto3,enum=kraken.v1.StateKind" json:"state,omitempty"`
+	Group                string    `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Select               string    `protobuf:"bytes,3,opt,name=select,proto3" json:"select,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListNodesRequest) Reset()         { *m = ListNodesRequest{} }
+func (m *ListNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListNodesRequest) ProtoMessage()    {}
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{1}
+}
+func (m *ListNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNodesRequest.Unmarshal(m, b)
+}
+func (m *ListNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNodesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNodesRequest.Merge(dst, src)
+}
+func (m *ListNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListNodesRequest.Size(m)
+}
+func (m *ListNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNodesRequest proto.InternalMessageInfo
+
+func (m *ListNodesRequest) GetState() StateKind {
+	if m != nil {
+		return m.State
+	}
+	return StateKind_CFG
+}
+
+func (m *ListNodesRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListNodesRequest) GetSelect() string {
+	if m != nil {
+		return m.Select
+	}
+	return ""
+}
+
+// A SetValue sets a URL on a node to a value, as it would be printed, e.g. "/PhysState" to "POWER_ON"
+type SetValue struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetValue) Reset()         { *m = SetValue{} }
+func (m *SetValue) String() string { return proto.CompactTextString(m) }
+func (*SetValue) ProtoMessage()    {}
+func (*SetValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{2}
+}
+func (m *SetValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetValue.Unmarshal(m, b)
+}
+func (m *SetValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetValue.Marshal(b, m, deterministic)
+}
+func (dst *SetValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetValue.Merge(dst, src)
+}
+func (m *SetValue) XXX_Size() int {
+	return xxx_messageInfo_SetValue.Size(m)
+}
+func (m *SetValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetValue proto.InternalMessageInfo
+
+func (m *SetValue) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetValue) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *SetValue) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// A SetValuesRequest sets values in the configuration state of nodes; they're all set, or none are
+type SetValuesRequest struct {
+	Values               []*SetValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SetValuesRequest) Reset()         { *m = SetValuesRequest{} }
+func (m *SetValuesRequest) String() string { return proto.CompactTextString(m) }
+func (*SetValuesRequest) ProtoMessage()    {}
+func (*SetValuesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{3}
+}
+func (m *SetValuesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetValuesRequest.Unmarshal(m, b)
+}
+func (m *SetValuesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetValuesRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetValuesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetValuesRequest.Merge(dst, src)
+}
+func (m *SetValuesRequest) XXX_Size() int {
+	return xxx_messageInfo_SetValuesRequest.Size(m)
+}
+func (m *SetValuesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetValuesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetValuesRequest proto.InternalMessageInfo
+
+func (m *SetValuesRequest) GetValues() []*SetValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type GroupMembers struct {
+	Ids                  []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupMembers) Reset()         { *m = GroupMembers{} }
+func (m *GroupMembers) String() string { return proto.CompactTextString(m) }
+func (*GroupMembers) ProtoMessage()    {}
+func (*GroupMembers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{4}
+}
+func (m *GroupMembers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupMembers.Unmarshal(m, b)
+}
+func (m *GroupMembers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupMembers.Marshal(b, m, deterministic)
+}
+func (dst *GroupMembers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupMembers.Merge(dst, src)
+}
+func (m *GroupMembers) XXX_Size() int {
+	return xxx_messageInfo_GroupMembers.Size(m)
+}
+func (m *GroupMembers) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupMembers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupMembers proto.InternalMessageInfo
+
+func (m *GroupMembers) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+// A GroupList is the IDs of the nodes in each group
+type GroupList struct {
+	Groups               map[string]*GroupMembers `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *GroupList) Reset()         { *m = GroupList{} }
+func (m *GroupList) String() string { return proto.CompactTextString(m) }
+func (*GroupList) ProtoMessage()    {}
+func (*GroupList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{5}
+}
+func (m *GroupList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupList.Unmarshal(m, b)
+}
+func (m *GroupList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupList.Marshal(b, m, deterministic)
+}
+func (dst *GroupList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupList.Merge(dst, src)
+}
+func (m *GroupList) XXX_Size() int {
+	return xxx_messageInfo_GroupList.Size(m)
+}
+func (m *GroupList) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupList proto.InternalMessageInfo
+
+func (m *GroupList) GetGroups() map[string]*GroupMembers {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// A GroupRequest adds nodes to, or removes them from, a group
+type GroupRequest struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Ids                  []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupRequest) Reset()         { *m = GroupRequest{} }
+func (m *GroupRequest) String() string { return proto.CompactTextString(m) }
+func (*GroupRequest) ProtoMessage()    {}
+func (*GroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_KrakenV1_158691b709eca70e, []int{6}
+}
+func (m *GroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupRequest.Unmarshal(m, b)
+}
+func (m *GroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupRequest.Marshal(b, m, deterministic)
+}
+func (dst *GroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupRequest.Merge(dst, src)
+}
+func (m *GroupRequest) XXX_Size() int {
+	return xxx_messageInfo_GroupRequest.Size(m)
+}
+func (m *GroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupRequest proto.InternalMessageInfo
+
+func (m *GroupRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GroupRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*NodeRequest)(nil), "kraken.v1.NodeRequest")
+	proto.RegisterType((*ListNodesRequest)(nil), "kraken.v1.ListNodesRequest")
+	proto.RegisterType((*SetValue)(nil), "kraken.v1.SetValue")
+	proto.RegisterType((*SetValuesRequest)(nil), "kraken.v1.SetValuesRequest")
+	proto.RegisterType((*GroupMembers)(nil), "kraken.v1.GroupMembers")
+	proto.RegisterType((*GroupList)(nil), "kraken.v1.GroupList")
+	proto.RegisterMapType((map[string]*GroupMembers)(nil), "kraken.v1.GroupList.GroupsEntry")
+	proto.RegisterType((*GroupRequest)(nil), "kraken.v1.GroupRequest")
+	proto.RegisterEnum("kraken.v1.StateKind", StateKind_name, StateKind_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// KrakenClient is the client API for Kraken service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KrakenClient interface {
+	// Nodes
+	GetNode(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*Node, error)
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*NodeList, error)
+	CreateNode(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Node, error)
+	UpdateNode(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Node, error)
+	DeleteNode(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*Node, error)
+	// Desired state
+	SetValues(ctx context.Context, in *SetValuesRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetMutationPath(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*MutationPath, error)
+	// Groups
+	ListGroups(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GroupList, error)
+	AddToGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*NodeList, error)
+	RemoveFromGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*NodeList, error)
+	// Events
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Kraken_WatchClient, error)
+}
+
+type krakenClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewKrakenClient(cc *grpc.ClientConn) KrakenClient {
+	return &krakenClient{cc}
+}
+
+func (c *krakenClient) GetNode(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*Node, error) {
+	out := new(Node)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/GetNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*NodeList, error) {
+	out := new(NodeList)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/ListNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) CreateNode(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Node, error) {
+	out := new(Node)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/CreateNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) UpdateNode(ctx context.Context, in *Node, opts ...grpc.CallOption) (*Node, error) {
+	out := new(Node)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/UpdateNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) DeleteNode(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*Node, error) {
+	out := new(Node)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/DeleteNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) SetValues(ctx context.Context, in *SetValuesRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/SetValues", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) GetMutationPath(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*MutationPath, error) {
+	out := new(MutationPath)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/GetMutationPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) ListGroups(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GroupList, error) {
+	out := new(GroupList)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) AddToGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*NodeList, error) {
+	out := new(NodeList)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/AddToGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) RemoveFromGroup(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*NodeList, error) {
+	out := new(NodeList)
+	err := c.cc.Invoke(ctx, "/kraken.v1.Kraken/RemoveFromGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *krakenClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Kraken_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Kraken_serviceDesc.Streams[0], "/kraken.v1.Kraken/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &krakenWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Kraken_WatchClient interface {
+	Recv() (*StateChange, error)
+	grpc.ClientStream
+}
+
+type krakenWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *krakenWatchClient) Recv() (*StateChange, error) {
+	m := new(StateChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KrakenServer is the server API for Kraken service.
+type KrakenServer interface {
+	// Nodes
+	GetNode(context.Context, *NodeRequest) (*Node, error)
+	ListNodes(context.Context, *ListNodesRequest) (*NodeList, error)
+	CreateNode(context.Context, *Node) (*Node, error)
+	UpdateNode(context.Context, *Node) (*Node, error)
+	DeleteNode(context.Context, *NodeRequest) (*Node, error)
+	// Desired state
+	SetValues(context.Context, *SetValuesRequest) (*empty.Empty, error)
+	GetMutationPath(context.Context, *NodeRequest) (*MutationPath, error)
+	// Groups
+	ListGroups(context.Context, *empty.Empty) (*GroupList, error)
+	AddToGroup(context.Context, *GroupRequest) (*NodeList, error)
+	RemoveFromGroup(context.Context, *GroupRequest) (*NodeList, error)
+	// Events
+	Watch(*WatchRequest, Kraken_WatchServer) error
+}
+
+func RegisterKrakenServer(s *grpc.Server, srv KrakenServer) {
+	s.RegisterService(&_Kraken_serviceDesc, srv)
+}
+
+func _Kraken_GetNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).GetNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/GetNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).GetNode(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/ListNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_CreateNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Node)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).CreateNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/CreateNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).CreateNode(ctx, req.(*Node))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_UpdateNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Node)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).UpdateNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/UpdateNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).UpdateNode(ctx, req.(*Node))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_DeleteNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).DeleteNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/DeleteNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).DeleteNode(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_SetValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).SetValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/SetValues",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).SetValues(ctx, req.(*SetValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_GetMutationPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).GetMutationPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/GetMutationPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).GetMutationPath(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).ListGroups(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_AddToGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).AddToGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/AddToGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).AddToGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_RemoveFromGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KrakenServer).RemoveFromGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kraken.v1.Kraken/RemoveFromGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KrakenServer).RemoveFromGroup(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kraken_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KrakenServer).Watch(m, &krakenWatchServer{stream})
+}
+
+type Kraken_WatchServer interface {
+	Send(*StateChange) error
+	grpc.ServerStream
+}
+
+type krakenWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *krakenWatchServer) Send(m *StateChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Kraken_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "kraken.v1.Kraken",
+	HandlerType: (*KrakenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetNode",
+			Handler:    _Kraken_GetNode_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Kraken_ListNodes_Handler,
+		},
+		{
+			MethodName: "CreateNode",
+			Handler:    _Kraken_CreateNode_Handler,
+		},
+		{
+			MethodName: "UpdateNode",
+			Handler:    _Kraken_UpdateNode_Handler,
+		},
+		{
+			MethodName: "DeleteNode",
+			Handler:    _Kraken_DeleteNode_Handler,
+		},
+		{
+			MethodName: "SetValues",
+			Handler:    _Kraken_SetValues_Handler,
+		},
+		{
+			MethodName: "GetMutationPath",
+			Handler:    _Kraken_GetMutationPath_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _Kraken_ListGroups_Handler,
+		},
+		{
+			MethodName: "AddToGroup",
+			Handler:    _Kraken_AddToGroup_Handler,
+		},
+		{
+			MethodName: "RemoveFromGroup",
+			Handler:    _Kraken_RemoveFromGroup_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Kraken_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "KrakenV1.proto",
+}
+
+func init() { proto.RegisterFile("KrakenV1.proto", fileDescriptor_KrakenV1_158691b709eca70e) }
+
+var fileDescriptor_KrakenV1_158691b709eca70e = []byte{
+	// 572 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xdd, 0x6e, 0xda, 0x4c,
+	0x10, 0xb5, 0xe1, 0x03, 0x3e, 0x0f, 0x15, 0xa0, 0x0d, 0xa2, 0xc8, 0x51, 0x25, 0xb4, 0x57, 0x28,
+	0x55, 0x9d, 0x84, 0x54, 0x15, 0xea, 0x8f, 0xda, 0x40, 0x12, 0x14, 0xa5, 0xa9, 0x22, 0xd3, 0xa6,
+	0x52, 0xef, 0x9c, 0x78, 0x0a, 0x16, 0xc6, 0xa6, 0xf6, 0x1a, 0x89, 0x47, 0xe9, 0x73, 0xf6, 0x05,
+	0xaa, 0xfd, 0xb1, 0xb5, 0x25, 0x4d, 0xdb, 0x5c, 0x79, 0x66, 0x76, 0xe6, 0x9c, 0x39, 0xde, 0xb3,
+	0xd0, 0xb8, 0x48, 0xbc, 0x05, 0x46, 0xd7, 0x87, 0xce, 0x2a, 0x89, 0x59, 0x4c, 0xac, 0x85, 0xc8,
+	0x9d, 0xf5, 0xa1, 0x0d, 0x1f, 0x62, 0x1f, 0x65, 0xd9, 0xb6, 0x8e, 0xaf, 0xce, 0x55, 0xb8, 0x3b,
+	0x8b, 0xe3, 0x59, 0x88, 0xfb, 0x22, 0xbb, 0xc9, 0xbe, 0xee, 0x9f, 0x2e, 0x57, 0x6c, 0x23, 0x0f,
+	0xe9, 0x39, 0xd4, 0xf9, 0x94, 0x8b, 0xdf, 0x32, 0x4c, 0x19, 0x69, 0x40, 0x29, 0xf0, 0xbb, 0x66,
+	0xcf, 0xec, 0x5b, 0x6e, 0x29, 0xf0, 0xc9, 0x1e, 0x54, 0x52, 0xe6, 0x31, 0xec, 0x96, 0x7a, 0x66,
+	0xbf, 0x31, 0x68, 0x3b, 0x05, 0x9b, 0x33, 0xe5, 0xf5, 0x8b, 0x20, 0xf2, 0x5d, 0xd9, 0x42, 0x43,
+	0x68, 0xbd, 0x0f, 0x52, 0xc6, 0xe1, 0xd2, 0x1c, 0xaf, 0x98, 0x37, 0xff, 0x3a, 0x4f, 0xda, 0x50,
+	0x99, 0x25, 0x71, 0xb6, 0x12, 0x5c, 0x96, 0x2b, 0x13, 0xd2, 0x81, 0x6a, 0x8a, 0x21, 0xde, 0xb2,
+	0x6e, 0x59, 0x94, 0x55, 0x46, 0x47, 0xf0, 0xff, 0x14, 0xd9, 0xb5, 0x17, 0x66, 0x78, 0x67, 0xeb,
+	0x16, 0x94, 0xb3, 0x24, 0x54, 0x38, 0x3c, 0xe4, 0xd8, 0x6b, 0xde, 0xaa, 0x40, 0x64, 0x42, 0xdf,
+	0x42, 0x2b, 0xc7, 0x28, 0x36, 0x7e, 0x0a, 0x55, 0x71, 0x98, 0x76, 0xcd, 0x5e, 0xb9, 0x5f, 0x1f,
+	0xec, 0xe8, 0x2b, 0xab, 0x66, 0x57, 0xb5, 0xd0, 0x1e, 0x3c, 0x9a, 0xf0, 0x2d, 0x2f, 0x71, 0x79,
+	0x83, 0x49, 0xca, 0x89, 0x03, 0x5f, 0x4e, 0x5a, 0x2e, 0x0f, 0xe9, 0x77, 0x13, 0x2c, 0xd1, 0xc2,
+	0x7f, 0x0d, 0x19, 0x42, 0x55, 0xa8, 0xca, 0xc1, 0x7b, 0x1a, 0x78, 0xd1, 0x25, 0xa3, 0xf4, 0x34,
+	0x62, 0xc9, 0xc6, 0x55, 0xfd, 0xb6, 0x0b, 0x75, 0xad, 0xcc, 0x89, 0x16, 0xb8, 0x51, 0x92, 0x79,
+	0x48, 0x9e, 0xe5, 0x0a, 0xb9, 0xea, 0xfa, 0xe0, 0xf1, 0x36, 0xb2, 0x5a, 0x51, 0x49, 0x7f, 0x59,
+	0x1a, 0x9a, 0xf4, 0x85, 0xda, 0x3e, 0x97, 0x5e, 0x5c, 0x80, 0xa9, 0x5f, 0x80, 0xd2, 0x54, 0x2a,
+	0x34, 0xed, 0x3d, 0x01, 0xab, 0xb8, 0x3c, 0x52, 0x83, 0xf2, 0xf8, 0x6c, 0xd2, 0x32, 0x78, 0x70,
+	0x32, 0x1d, 0xb7, 0xcc, 0xc1, 0x8f, 0xff, 0xa0, 0x2a, 0x4d, 0x4a, 0x0e, 0xa0, 0x36, 0x41, 0xe1,
+	0x08, 0xd2, 0xd1, 0x16, 0xd2, 0x1c, 0x67, 0xd7, 0xa5, 0x11, 0x45, 0x8d, 0x1a, 0xe4, 0x15, 0x58,
+	0x85, 0x89, 0xc8, 0xae, 0x36, 0xb3, 0x6d, 0x2d, 0xbb, 0xa9, 0x0d, 0xf2, 0x43, 0x6a, 0x90, 0x3e,
+	0xc0, 0x38, 0x41, 0x8f, 0xa1, 0x60, 0xd4, 0x91, 0xb7, 0x69, 0xfa, 0x00, 0x9f, 0x56, 0xfe, 0xbf,
+	0x74, 0x1e, 0x01, 0x9c, 0x60, 0x88, 0x0c, 0x1f, 0xa2, 0x62, 0x04, 0x56, 0x61, 0xac, 0x5f, 0x54,
+	0x6c, 0xdb, 0xcd, 0xee, 0x38, 0xf2, 0x75, 0x3a, 0xf9, 0xeb, 0x74, 0xc4, 0xeb, 0xa4, 0x06, 0x79,
+	0x07, 0xcd, 0x09, 0xb2, 0xcb, 0x8c, 0x79, 0x2c, 0x88, 0xa3, 0x2b, 0x8f, 0xcd, 0xef, 0x65, 0xdf,
+	0x51, 0xec, 0x7a, 0x33, 0x35, 0xc8, 0x6b, 0x00, 0xfe, 0x63, 0xa4, 0x6f, 0xc8, 0x3d, 0x4c, 0x76,
+	0xfb, 0x77, 0x1e, 0xa4, 0x06, 0x19, 0x02, 0x1c, 0xfb, 0xfe, 0xc7, 0x58, 0xd4, 0xc8, 0x1d, 0x3f,
+	0xfd, 0xe1, 0x1a, 0xde, 0x40, 0xd3, 0xc5, 0x65, 0xbc, 0xc6, 0xb3, 0x24, 0x5e, 0x3e, 0x7c, 0xfc,
+	0x39, 0x54, 0x3e, 0x7b, 0xec, 0x76, 0x4e, 0x72, 0x59, 0x22, 0xcb, 0x07, 0x88, 0x2a, 0x0a, 0x07,
+	0x8e, 0xe7, 0x5e, 0x34, 0x43, 0x6a, 0x1c, 0x98, 0xa3, 0xda, 0x97, 0x8a, 0x14, 0x56, 0x15, 0x9f,
+	0xa3, 0x9f, 0x03, 0x00, 0x6a, 0x71, 0xac, 0x39, 0x29, 0x05, 0x00, 0x00,
+}
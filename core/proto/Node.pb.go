@@ -45,7 +45,7 @@ func (x Node_RunState) String() string {
 	return proto.EnumName(Node_RunState_name, int32(x))
 }
 func (Node_RunState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_Node_c9a63bf93232cad0, []int{1, 0}
+	return fileDescriptor_Node_92f83d90151de074, []int{1, 0}
 }
 
 type Node_PhysState int32
@@ -80,7 +80,59 @@ func (x Node_PhysState) String() string {
 	return proto.EnumName(Node_PhysState_name, int32(x))
 }
 func (Node_PhysState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_Node_c9a63bf93232cad0, []int{1, 1}
+	return fileDescriptor_Node_92f83d90151de074, []int{1, 1}
+}
+
+type Node_Priority int32
+
+const (
+	Node_ROUTINE   Node_Priority = 0
+	Node_URGENT    Node_Priority = 1
+	Node_EMERGENCY Node_Priority = 2
+)
+
+var Node_Priority_name = map[int32]string{
+	0: "ROUTINE",
+	1: "URGENT",
+	2: "EMERGENCY",
+}
+var Node_Priority_value = map[string]int32{
+	"ROUTINE":   0,
+	"URGENT":    1,
+	"EMERGENCY": 2,
+}
+
+func (x Node_Priority) String() string {
+	return proto.EnumName(Node_Priority_name, int32(x))
+}
+func (Node_Priority) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_Node_92f83d90151de074, []int{1, 2}
+}
+
+type Node_Lifecycle int32
+
+const (
+	Node_ACTIVE          Node_Lifecycle = 0
+	Node_DECOMMISSIONING Node_Lifecycle = 1
+	Node_DECOMMISSIONED  Node_Lifecycle = 2
+)
+
+var Node_Lifecycle_name = map[int32]string{
+	0: "ACTIVE",
+	1: "DECOMMISSIONING",
+	2: "DECOMMISSIONED",
+}
+var Node_Lifecycle_value = map[string]int32{
+	"ACTIVE":          0,
+	"DECOMMISSIONING": 1,
+	"DECOMMISSIONED":  2,
+}
+
+func (x Node_Lifecycle) String() string {
+	return proto.EnumName(Node_Lifecycle_name, int32(x))
+}
+func (Node_Lifecycle) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_Node_92f83d90151de074, []int{1, 3}
 }
 
 type NodeList struct {
@@ -94,7 +146,7 @@ func (m *NodeList) Reset()         { *m = NodeList{} }
 func (m *NodeList) String() string { return proto.CompactTextString(m) }
 func (*NodeList) ProtoMessage()    {}
 func (*NodeList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_Node_c9a63bf93232cad0, []int{0}
+	return fileDescriptor_Node_92f83d90151de074, []int{0}
 }
 func (m *NodeList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeList.Unmarshal(m, b)
@@ -131,6 +183,14 @@ type Node struct {
 	ParentId             []byte             `protobuf:"bytes,7,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
 	Services             []*ServiceInstance `protobuf:"bytes,14,rep,name=services,proto3" json:"services,omitempty"`
 	Extensions           []*any.Any         `protobuf:"bytes,15,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	MutationCosts        []*MutationCost    `protobuf:"bytes,16,rep,name=mutation_costs,json=mutationCosts,proto3" json:"mutation_costs,omitempty"`
+	Groups               []string           `protobuf:"bytes,17,rep,name=groups,proto3" json:"groups,omitempty"`
+	Frozen               bool               `protobuf:"varint,18,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	Priority             Node_Priority      `protobuf:"varint,19,opt,name=priority,proto3,enum=proto.Node_Priority" json:"priority,omitempty"`
+	Schedule             []*ScheduledChange `protobuf:"bytes,20,rep,name=schedule,proto3" json:"schedule,omitempty"`
+	Lifecycle            Node_Lifecycle     `protobuf:"varint,21,opt,name=lifecycle,proto3,enum=proto.Node_Lifecycle" json:"lifecycle,omitempty"`
+	RemoveDecommissioned bool               `protobuf:"varint,22,opt,name=remove_decommissioned,json=removeDecommissioned,proto3" json:"remove_decommissioned,omitempty"`
+	Location             string             `protobuf:"bytes,23,opt,name=location,proto3" json:"location,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -140,7 +200,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_Node_c9a63bf93232cad0, []int{1}
+	return fileDescriptor_Node_92f83d90151de074, []int{1}
 }
 func (m *Node) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Node.Unmarshal(m, b)
@@ -223,41 +283,240 @@ func (m *Node) GetExtensions() []*any.Any {
 	return nil
 }
 
+func (m *Node) GetMutationCosts() []*MutationCost {
+	if m != nil {
+		return m.MutationCosts
+	}
+	return nil
+}
+
+func (m *Node) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *Node) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+func (m *Node) GetPriority() Node_Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Node_ROUTINE
+}
+
+func (m *Node) GetSchedule() []*ScheduledChange {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+func (m *Node) GetLifecycle() Node_Lifecycle {
+	if m != nil {
+		return m.Lifecycle
+	}
+	return Node_ACTIVE
+}
+
+func (m *Node) GetRemoveDecommissioned() bool {
+	if m != nil {
+		return m.RemoveDecommissioned
+	}
+	return false
+}
+
+func (m *Node) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+// ScheduledChange sets a value in a node's configuration at a time (at), or on a schedule (cron).
+type ScheduledChange struct {
+	At                   string   `protobuf:"bytes,1,opt,name=at,proto3" json:"at,omitempty"`
+	Cron                 string   `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	Url                  string   `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Value                string   `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduledChange) Reset()         { *m = ScheduledChange{} }
+func (m *ScheduledChange) String() string { return proto.CompactTextString(m) }
+func (*ScheduledChange) ProtoMessage()    {}
+func (*ScheduledChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_Node_92f83d90151de074, []int{2}
+}
+func (m *ScheduledChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduledChange.Unmarshal(m, b)
+}
+func (m *ScheduledChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduledChange.Marshal(b, m, deterministic)
+}
+func (dst *ScheduledChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduledChange.Merge(dst, src)
+}
+func (m *ScheduledChange) XXX_Size() int {
+	return xxx_messageInfo_ScheduledChange.Size(m)
+}
+func (m *ScheduledChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduledChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduledChange proto.InternalMessageInfo
+
+func (m *ScheduledChange) GetAt() string {
+	if m != nil {
+		return m.At
+	}
+	return ""
+}
+
+func (m *ScheduledChange) GetCron() string {
+	if m != nil {
+		return m.Cron
+	}
+	return ""
+}
+
+func (m *ScheduledChange) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *ScheduledChange) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// MutationCost overrides the cost the StateMutationEngine uses for mutations when choosing a path.
+// Cheaper paths are preferred.
+type MutationCost struct {
+	Module               string   `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Mutation             string   `protobuf:"bytes,2,opt,name=mutation,proto3" json:"mutation,omitempty"`
+	Cost                 uint32   `protobuf:"varint,3,opt,name=cost,proto3" json:"cost,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MutationCost) Reset()         { *m = MutationCost{} }
+func (m *MutationCost) String() string { return proto.CompactTextString(m) }
+func (*MutationCost) ProtoMessage()    {}
+func (*MutationCost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_Node_92f83d90151de074, []int{3}
+}
+func (m *MutationCost) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutationCost.Unmarshal(m, b)
+}
+func (m *MutationCost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutationCost.Marshal(b, m, deterministic)
+}
+func (dst *MutationCost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutationCost.Merge(dst, src)
+}
+func (m *MutationCost) XXX_Size() int {
+	return xxx_messageInfo_MutationCost.Size(m)
+}
+func (m *MutationCost) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutationCost.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MutationCost proto.InternalMessageInfo
+
+func (m *MutationCost) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *MutationCost) GetMutation() string {
+	if m != nil {
+		return m.Mutation
+	}
+	return ""
+}
+
+func (m *MutationCost) GetCost() uint32 {
+	if m != nil {
+		return m.Cost
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*NodeList)(nil), "proto.NodeList")
 	proto.RegisterType((*Node)(nil), "proto.Node")
+	proto.RegisterType((*ScheduledChange)(nil), "proto.ScheduledChange")
+	proto.RegisterType((*MutationCost)(nil), "proto.MutationCost")
 	proto.RegisterEnum("proto.Node_RunState", Node_RunState_name, Node_RunState_value)
 	proto.RegisterEnum("proto.Node_PhysState", Node_PhysState_name, Node_PhysState_value)
+	proto.RegisterEnum("proto.Node_Priority", Node_Priority_name, Node_Priority_value)
+	proto.RegisterEnum("proto.Node_Lifecycle", Node_Lifecycle_name, Node_Lifecycle_value)
 }
 
-func init() { proto.RegisterFile("Node.proto", fileDescriptor_Node_c9a63bf93232cad0) }
-
-var fileDescriptor_Node_c9a63bf93232cad0 = []byte{
-	// 415 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x51, 0xcf, 0x6f, 0xd3, 0x30,
-	0x18, 0x25, 0xbf, 0x36, 0xe7, 0x6b, 0xc9, 0xac, 0x4f, 0x1b, 0x32, 0xe5, 0x52, 0x7a, 0xea, 0x85,
-	0x4c, 0x14, 0xc4, 0x7d, 0xaa, 0x3a, 0x56, 0x98, 0xd2, 0xca, 0x05, 0x4d, 0x3d, 0x55, 0x59, 0xe3,
-	0xad, 0x41, 0x9b, 0x13, 0xc5, 0x2e, 0xa2, 0xff, 0x3b, 0x07, 0x64, 0x3b, 0x2d, 0xd5, 0x4e, 0x79,
-	0x2f, 0xdf, 0x7b, 0xf6, 0xf3, 0xfb, 0x00, 0xb2, 0xaa, 0x10, 0x69, 0xdd, 0x54, 0xba, 0xc2, 0xc8,
-	0x7e, 0x7a, 0x6f, 0x1f, 0xab, 0xea, 0xf1, 0x49, 0x5c, 0x5a, 0x76, 0xbf, 0x7d, 0xb8, 0xcc, 0xe5,
-	0xce, 0x29, 0x7a, 0x17, 0x0b, 0xd1, 0xfc, 0x2e, 0xd7, 0x62, 0x2a, 0x95, 0xce, 0xe5, 0xba, 0x35,
-	0x0e, 0x3e, 0x00, 0x31, 0xc7, 0xdc, 0x96, 0x4a, 0xe3, 0x7b, 0x88, 0x64, 0x55, 0x08, 0xc5, 0xbc,
-	0x7e, 0x30, 0xec, 0x8c, 0x3a, 0x4e, 0x92, 0x9a, 0x39, 0x77, 0x93, 0xc1, 0xdf, 0x00, 0x42, 0xc3,
-	0x31, 0x01, 0xbf, 0x2c, 0x98, 0xd7, 0xf7, 0x86, 0x5d, 0xee, 0x97, 0x05, 0xf6, 0x80, 0x18, 0x85,
-	0xcc, 0x9f, 0x05, 0xf3, 0xfb, 0xde, 0x30, 0xe6, 0x07, 0x8e, 0x1f, 0x21, 0x6e, 0xb6, 0x72, 0xa5,
-	0x74, 0xae, 0x05, 0x0b, 0xfa, 0xde, 0x30, 0x19, 0x9d, 0x1f, 0x9d, 0x9d, 0xf2, 0xad, 0x5c, 0x98,
-	0x19, 0x27, 0x4d, 0x8b, 0xf0, 0x33, 0x40, 0xbd, 0xd9, 0xa9, 0xd6, 0x13, 0x5a, 0xcf, 0xc5, 0xb1,
-	0x67, 0xbe, 0xd9, 0x29, 0x67, 0x8a, 0xeb, 0x3d, 0x44, 0x84, 0x30, 0x6f, 0xd6, 0x1b, 0x16, 0xd9,
-	0x00, 0x16, 0x9b, 0x60, 0xf5, 0x53, 0xae, 0x1f, 0xaa, 0xe6, 0x99, 0x9d, 0xb8, 0x60, 0x7b, 0x8e,
-	0xef, 0x20, 0xae, 0xf3, 0x46, 0x48, 0xbd, 0x2a, 0x0b, 0x76, 0x6a, 0xdf, 0x42, 0xdc, 0x8f, 0x69,
-	0x81, 0x23, 0x20, 0xca, 0x55, 0xa6, 0x58, 0x62, 0x0b, 0x79, 0xd3, 0x06, 0x78, 0xd1, 0x24, 0x3f,
-	0xe8, 0x4c, 0x6c, 0xf1, 0x47, 0x0b, 0xa9, 0xca, 0x4a, 0x2a, 0x76, 0x66, 0x5d, 0xe7, 0xa9, 0x5b,
-	0x4a, 0xba, 0x5f, 0x4a, 0x7a, 0x25, 0x77, 0xfc, 0x48, 0x37, 0xf8, 0x02, 0x64, 0x5f, 0x01, 0x76,
-	0xe0, 0xf4, 0x67, 0xf6, 0x3d, 0x9b, 0xdd, 0x65, 0xf4, 0x15, 0x12, 0x08, 0xa7, 0xd9, 0xf4, 0x07,
-	0xf5, 0x0c, 0x5a, 0x2c, 0xb3, 0x31, 0xf5, 0x31, 0x86, 0x68, 0xc2, 0xf9, 0x8c, 0xd3, 0x60, 0xf0,
-	0x0b, 0xe2, 0x43, 0x0d, 0x48, 0xa1, 0x3b, 0xbf, 0x59, 0x2e, 0x56, 0xff, 0xdd, 0xaf, 0x21, 0x9e,
-	0xcf, 0xee, 0x26, 0x7c, 0x35, 0xbb, 0xbe, 0xa6, 0x1e, 0x76, 0x81, 0xb4, 0x34, 0xa3, 0x3e, 0x9e,
-	0x41, 0xc7, 0xb1, 0xf1, 0x72, 0x7c, 0x3b, 0xa1, 0x81, 0x55, 0x1b, 0xff, 0xcd, 0x55, 0xf6, 0x95,
-	0x86, 0x98, 0x00, 0x58, 0xea, 0xee, 0x8a, 0xbe, 0x85, 0x84, 0xd0, 0xe4, 0xfe, 0xc4, 0xbe, 0xe1,
-	0xd3, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x47, 0x3e, 0xda, 0x7b, 0x7b, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("Node.proto", fileDescriptor_Node_92f83d90151de074) }
+
+var fileDescriptor_Node_92f83d90151de074 = []byte{
+	// 725 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x54, 0x41, 0x73, 0xe2, 0x36,
+	0x14, 0x5e, 0x1b, 0x48, 0xec, 0x97, 0x00, 0xaa, 0x02, 0xa9, 0x9a, 0x5e, 0xa8, 0x4f, 0x5c, 0xca,
+	0xb6, 0xa4, 0xd3, 0x43, 0x4f, 0xcd, 0x38, 0xde, 0xac, 0xdb, 0x60, 0x18, 0x91, 0xec, 0x0e, 0x87,
+	0x0e, 0xe3, 0xb5, 0x05, 0xb8, 0x63, 0x5b, 0x8c, 0x25, 0x67, 0x4a, 0x7f, 0x62, 0x7f, 0x55, 0x47,
+	0xb2, 0xcd, 0xb2, 0x9b, 0x13, 0xef, 0xd3, 0xfb, 0x3e, 0xe9, 0xf9, 0x7b, 0xef, 0x01, 0x10, 0xf0,
+	0x98, 0x4d, 0xf6, 0x05, 0x97, 0x1c, 0x77, 0xf4, 0xcf, 0xcd, 0x77, 0x5b, 0xce, 0xb7, 0x29, 0x7b,
+	0xab, 0xd1, 0xa7, 0x72, 0xf3, 0x36, 0xcc, 0x0f, 0x15, 0xe3, 0x66, 0xb8, 0x64, 0xc5, 0x4b, 0x12,
+	0x31, 0x3f, 0x17, 0x32, 0xcc, 0xa3, 0x5a, 0xe8, 0xfc, 0x08, 0x96, 0xba, 0xe6, 0x31, 0x11, 0x12,
+	0xff, 0x00, 0x9d, 0x9c, 0xc7, 0x4c, 0x10, 0x63, 0xd4, 0x1a, 0x5f, 0x4c, 0x2f, 0x2a, 0xca, 0x44,
+	0xe5, 0x69, 0x95, 0x71, 0xfe, 0x3b, 0x87, 0xb6, 0xc2, 0xb8, 0x07, 0x66, 0x12, 0x13, 0x63, 0x64,
+	0x8c, 0x2f, 0xa9, 0x99, 0xc4, 0xf8, 0x06, 0x2c, 0xc5, 0xc8, 0xc3, 0x8c, 0x11, 0x73, 0x64, 0x8c,
+	0x6d, 0x7a, 0xc4, 0xf8, 0x67, 0xb0, 0x8b, 0x32, 0x5f, 0x0b, 0x19, 0x4a, 0x46, 0x5a, 0x23, 0x63,
+	0xdc, 0x9b, 0x0e, 0x4e, 0xee, 0x9e, 0xd0, 0x32, 0x5f, 0xaa, 0x1c, 0xb5, 0x8a, 0x3a, 0xc2, 0xbf,
+	0x00, 0xec, 0x77, 0x07, 0x51, 0x6b, 0xda, 0x5a, 0x33, 0x3c, 0xd5, 0x2c, 0x76, 0x07, 0x51, 0x89,
+	0xec, 0x7d, 0x13, 0x62, 0x0c, 0xed, 0xb0, 0x88, 0x76, 0xa4, 0xa3, 0x0b, 0xd0, 0xb1, 0x2a, 0x6c,
+	0x9f, 0x86, 0x72, 0xc3, 0x8b, 0x8c, 0x9c, 0x55, 0x85, 0x35, 0x18, 0x7f, 0x0f, 0xf6, 0x3e, 0x2c,
+	0x58, 0x2e, 0xd7, 0x49, 0x4c, 0xce, 0xf5, 0xb7, 0x58, 0xd5, 0x81, 0x1f, 0xe3, 0x29, 0x58, 0xa2,
+	0xb2, 0x4c, 0x90, 0x9e, 0x36, 0xe4, 0xba, 0x2e, 0xe0, 0x2b, 0x27, 0xe9, 0x91, 0xa7, 0xca, 0x66,
+	0xff, 0x48, 0x96, 0x8b, 0x84, 0xe7, 0x82, 0xf4, 0xb5, 0x6a, 0x30, 0xa9, 0x9a, 0x32, 0x69, 0x9a,
+	0x32, 0xb9, 0xcb, 0x0f, 0xf4, 0x84, 0x87, 0x7f, 0x83, 0x5e, 0x56, 0xca, 0x50, 0x26, 0x3c, 0x5f,
+	0x47, 0x5c, 0x48, 0x41, 0x90, 0x56, 0x5e, 0xd5, 0xef, 0xcd, 0xea, 0xa4, 0xcb, 0x85, 0xa4, 0xdd,
+	0xec, 0x04, 0x09, 0x7c, 0x0d, 0x67, 0xdb, 0x82, 0x97, 0x7b, 0x41, 0xbe, 0x19, 0xb5, 0xc6, 0x36,
+	0xad, 0x91, 0x3a, 0xdf, 0x14, 0xfc, 0x5f, 0x96, 0x13, 0x3c, 0x32, 0xc6, 0x16, 0xad, 0x11, 0xfe,
+	0x09, 0xac, 0x7d, 0x91, 0xf0, 0x22, 0x91, 0x07, 0x72, 0xf5, 0xba, 0x15, 0x8b, 0x3a, 0x47, 0x8f,
+	0x2c, 0xed, 0x43, 0xb4, 0x63, 0x71, 0x99, 0x32, 0x32, 0xf8, 0xd2, 0x87, 0xfa, 0x38, 0x76, 0x77,
+	0x61, 0xbe, 0x55, 0x3e, 0xd4, 0x07, 0xf8, 0x16, 0xec, 0x34, 0xd9, 0xb0, 0xe8, 0x10, 0xa5, 0x8c,
+	0x0c, 0x5f, 0x77, 0xef, 0xb1, 0x49, 0xd2, 0xcf, 0x3c, 0x7c, 0x0b, 0xc3, 0x82, 0x65, 0xfc, 0x85,
+	0xad, 0x63, 0x16, 0xf1, 0x2c, 0x4b, 0x84, 0xb2, 0x87, 0xc5, 0xe4, 0x5a, 0x7f, 0xc1, 0xa0, 0x4a,
+	0xde, 0x7f, 0x91, 0x53, 0xed, 0x4d, 0x79, 0xa4, 0x0d, 0x21, 0xdf, 0x56, 0xed, 0x6d, 0xb0, 0xf3,
+	0x2b, 0x58, 0xcd, 0x68, 0xe1, 0x0b, 0x38, 0x7f, 0x0e, 0xfe, 0x0c, 0xe6, 0x1f, 0x03, 0xf4, 0x06,
+	0x5b, 0xd0, 0xf6, 0x03, 0xff, 0x09, 0x19, 0x2a, 0x5a, 0xae, 0x02, 0x17, 0x99, 0xd8, 0x86, 0x8e,
+	0x47, 0xe9, 0x9c, 0xa2, 0x96, 0xf3, 0x37, 0xd8, 0xc7, 0xf1, 0xc2, 0x08, 0x2e, 0x17, 0xef, 0x57,
+	0xcb, 0xf5, 0x67, 0x75, 0x17, 0xec, 0xc5, 0xfc, 0xa3, 0x47, 0xd7, 0xf3, 0x77, 0xef, 0x90, 0x81,
+	0x2f, 0xc1, 0xaa, 0x61, 0x80, 0x4c, 0xdc, 0x87, 0x8b, 0x0a, 0xb9, 0x2b, 0xf7, 0xd1, 0x43, 0x2d,
+	0xcd, 0x56, 0xfa, 0xf7, 0x77, 0xc1, 0x03, 0x6a, 0xe3, 0x1e, 0x80, 0x86, 0xd5, 0x5b, 0x1d, 0x67,
+	0x0a, 0x56, 0xe3, 0xb9, 0xaa, 0x91, 0xce, 0x9f, 0x9f, 0xfc, 0xc0, 0x43, 0x6f, 0x30, 0xc0, 0xd9,
+	0x33, 0x7d, 0xf0, 0x02, 0x55, 0x65, 0x17, 0x6c, 0x6f, 0xe6, 0x29, 0xe4, 0xae, 0x90, 0xe9, 0xfc,
+	0x0e, 0xf6, 0xd1, 0x40, 0xc5, 0xbb, 0x73, 0x9f, 0xfc, 0x0f, 0x4a, 0x73, 0x05, 0xfd, 0x7b, 0xcf,
+	0x9d, 0xcf, 0x66, 0xfe, 0x72, 0xe9, 0xcf, 0x03, 0x3f, 0x78, 0x40, 0x06, 0xc6, 0xd0, 0x3b, 0x3d,
+	0xf4, 0xee, 0x91, 0xf9, 0x47, 0xdb, 0xb2, 0x50, 0xcf, 0xf9, 0x0b, 0xfa, 0x5f, 0xb5, 0x50, 0xad,
+	0x75, 0x28, 0xf5, 0x5a, 0xdb, 0xd4, 0x0c, 0xa5, 0xda, 0xa8, 0xa8, 0xe0, 0x79, 0xbd, 0xd2, 0x3a,
+	0xc6, 0x08, 0x5a, 0x65, 0x91, 0xea, 0x45, 0xb6, 0xa9, 0x0a, 0xf1, 0x00, 0x3a, 0x2f, 0x61, 0x5a,
+	0x56, 0x8b, 0x6a, 0xd3, 0x0a, 0x38, 0x1f, 0xe0, 0xf2, 0x74, 0x72, 0xd5, 0x48, 0x66, 0x5c, 0x8f,
+	0x51, 0x75, 0x7f, 0x8d, 0x54, 0x0b, 0x9b, 0x99, 0x6e, 0xfe, 0x3a, 0x1a, 0xac, 0xdf, 0xe7, 0x42,
+	0xea, 0xc7, 0xba, 0x54, 0xc7, 0x9f, 0xce, 0xf4, 0x20, 0xdd, 0xfe, 0x3f, 0x00, 0x29, 0x6f, 0xa8,
+	0xfa, 0x00, 0x05, 0x00, 0x00,
 }
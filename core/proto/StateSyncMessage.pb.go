@@ -37,7 +37,7 @@ func (m *StateSyncMessage) Reset()         { *m = StateSyncMessage{} }
 func (m *StateSyncMessage) String() string { return proto.CompactTextString(m) }
 func (*StateSyncMessage) ProtoMessage()    {}
 func (*StateSyncMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_StateSyncMessage_ef9f44bb84d20e95, []int{0}
+	return fileDescriptor_StateSyncMessage_1b3d97bba34650d3, []int{0}
 }
 func (m *StateSyncMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateSyncMessage.Unmarshal(m, b)
@@ -80,6 +80,7 @@ func (m *StateSyncMessage) GetMessage() []byte {
 
 type PhoneHomeRequest struct {
 	Id                   []byte   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rehome               bool     `protobuf:"varint,2,opt,name=rehome,proto3" json:"rehome,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -89,7 +90,7 @@ func (m *PhoneHomeRequest) Reset()         { *m = PhoneHomeRequest{} }
 func (m *PhoneHomeRequest) String() string { return proto.CompactTextString(m) }
 func (*PhoneHomeRequest) ProtoMessage()    {}
 func (*PhoneHomeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_StateSyncMessage_ef9f44bb84d20e95, []int{1}
+	return fileDescriptor_StateSyncMessage_1b3d97bba34650d3, []int{1}
 }
 func (m *PhoneHomeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PhoneHomeRequest.Unmarshal(m, b)
@@ -116,6 +117,13 @@ func (m *PhoneHomeRequest) GetId() []byte {
 	return nil
 }
 
+func (m *PhoneHomeRequest) GetRehome() bool {
+	if m != nil {
+		return m.Rehome
+	}
+	return false
+}
+
 type PhoneHomeReply struct {
 	Pid                  []byte            `protobuf:"bytes,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	Key                  []byte            `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
@@ -130,7 +138,7 @@ func (m *PhoneHomeReply) Reset()         { *m = PhoneHomeReply{} }
 func (m *PhoneHomeReply) String() string { return proto.CompactTextString(m) }
 func (*PhoneHomeReply) ProtoMessage()    {}
 func (*PhoneHomeReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_StateSyncMessage_ef9f44bb84d20e95, []int{2}
+	return fileDescriptor_StateSyncMessage_1b3d97bba34650d3, []int{2}
 }
 func (m *PhoneHomeReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PhoneHomeReply.Unmarshal(m, b)
@@ -257,23 +265,24 @@ var _StateSync_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("StateSyncMessage.proto", fileDescriptor_StateSyncMessage_ef9f44bb84d20e95)
+	proto.RegisterFile("StateSyncMessage.proto", fileDescriptor_StateSyncMessage_1b3d97bba34650d3)
 }
 
-var fileDescriptor_StateSyncMessage_ef9f44bb84d20e95 = []byte{
-	// 217 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_StateSyncMessage_1b3d97bba34650d3 = []byte{
+	// 229 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x0b, 0x2e, 0x49, 0x2c,
 	0x49, 0x0d, 0xae, 0xcc, 0x4b, 0xf6, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca,
 	0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53, 0x4a, 0x01, 0x5c, 0x02, 0xe8, 0x0a, 0x84, 0xf8, 0xb8, 0x98,
 	0x32, 0x53, 0x24, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x98, 0x32, 0x53, 0x84, 0x84, 0xb8, 0x58,
 	0x32, 0x72, 0x13, 0x93, 0x25, 0x98, 0xc0, 0x22, 0x60, 0xb6, 0x90, 0x04, 0x17, 0x7b, 0x2e, 0x44,
-	0xb9, 0x04, 0x33, 0x58, 0x18, 0xc6, 0x55, 0x52, 0xe2, 0x12, 0x08, 0xc8, 0xc8, 0xcf, 0x4b, 0xf5,
-	0xc8, 0xcf, 0x4d, 0x0d, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e, 0x41, 0x37, 0x51, 0xa9, 0x8b, 0x91,
-	0x8b, 0x0f, 0x49, 0x51, 0x41, 0x4e, 0xa5, 0x90, 0x00, 0x17, 0x73, 0x01, 0x5c, 0x0d, 0x88, 0x09,
-	0x12, 0xc9, 0x4e, 0xad, 0x84, 0xda, 0x0a, 0x62, 0x0a, 0x69, 0x72, 0x31, 0x27, 0xa7, 0xa5, 0x83,
-	0x2d, 0xe4, 0x36, 0x12, 0x87, 0x78, 0x44, 0x0f, 0xdd, 0xf9, 0x41, 0x20, 0x35, 0x20, 0xa5, 0x29,
-	0xc5, 0xc9, 0x12, 0x2c, 0x04, 0x94, 0xa6, 0x14, 0x27, 0x1b, 0xf9, 0x72, 0x71, 0xc2, 0x25, 0x84,
-	0x1c, 0xb8, 0x78, 0x82, 0x02, 0x9c, 0xe1, 0x6e, 0x13, 0x82, 0x69, 0x45, 0xf7, 0x92, 0x94, 0x28,
-	0xa6, 0x44, 0x41, 0x4e, 0xa5, 0x12, 0x43, 0x12, 0x1b, 0x58, 0xdc, 0x18, 0x10, 0x00, 0x00, 0xff,
-	0xff, 0xbd, 0xce, 0x19, 0x41, 0x79, 0x01, 0x00, 0x00,
+	0xb9, 0x04, 0x33, 0x58, 0x18, 0xc6, 0x55, 0xb2, 0xe2, 0x12, 0x08, 0xc8, 0xc8, 0xcf, 0x4b, 0xf5,
+	0xc8, 0xcf, 0x4d, 0x0d, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e, 0xc1, 0x30, 0x51, 0x8c, 0x8b, 0xad,
+	0x28, 0x35, 0x23, 0x3f, 0x37, 0x15, 0x6c, 0x26, 0x47, 0x10, 0x94, 0xa7, 0xd4, 0xc5, 0xc8, 0xc5,
+	0x87, 0xa4, 0xb9, 0x20, 0xa7, 0x52, 0x48, 0x80, 0x8b, 0xb9, 0x00, 0xae, 0x17, 0xc4, 0x04, 0x89,
+	0x64, 0xa7, 0x56, 0x42, 0x5d, 0x03, 0x62, 0x0a, 0x69, 0x72, 0x31, 0x27, 0xa7, 0xa5, 0x83, 0x1d,
+	0xc2, 0x6d, 0x24, 0x0e, 0xf1, 0xa0, 0x1e, 0xba, 0xb7, 0x82, 0x40, 0x6a, 0x40, 0x4a, 0x53, 0x8a,
+	0x93, 0x25, 0x58, 0x08, 0x28, 0x4d, 0x29, 0x4e, 0x36, 0xf2, 0xe5, 0xe2, 0x84, 0x4b, 0x08, 0x39,
+	0x70, 0xf1, 0x04, 0x05, 0x38, 0xc3, 0xdd, 0x26, 0x04, 0xd3, 0x8a, 0xee, 0x55, 0x29, 0x51, 0x4c,
+	0x89, 0x82, 0x9c, 0x4a, 0x25, 0x86, 0x24, 0x36, 0xb0, 0xb8, 0x31, 0x60, 0x00, 0xd4, 0xd1, 0x09,
+	0x1f, 0x91, 0x01, 0x00, 0x00,
 }
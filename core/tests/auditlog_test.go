@@ -0,0 +1,90 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestFileAuditLog(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-auditlog")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	l, e := OpenAuditLog("file", filepath.Join(dir, "audit.log"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer l.Close()
+
+	n1 := "123e4567-e89b-12d3-a456-426655440000"
+	n2 := "123e4567-e89b-12d3-a456-426655440001"
+	base := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) *timestamp.Timestamp {
+		t, _ := ptypes.TimestampProto(base.Add(d))
+		return t
+	}
+	for i, r := range []struct{ node, user string }{{n1, "alice"}, {n2, "bob"}, {n1, "bob"}} {
+		if e = l.Append(&pb.AuditRecord{Time: ts(time.Duration(i) * time.Minute), Node: r.node, User: r.user, Action: "PUT /cfg/node/{id}", Result: "ok"}); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, c := range []struct {
+		name  string
+		q     *pb.AuditLogQuery
+		count int
+	}{
+		{"all", &pb.AuditLogQuery{}, 3},
+		{"node", &pb.AuditLogQuery{Node: n1}, 2},
+		{"user", &pb.AuditLogQuery{User: "bob"}, 2},
+		{"node and user", &pb.AuditLogQuery{Node: n1, User: "bob"}, 1},
+		{"from", &pb.AuditLogQuery{From: ts(time.Minute)}, 2},
+		{"to", &pb.AuditLogQuery{To: ts(time.Minute)}, 2},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			rs, e := l.Query(c.q)
+			if e != nil {
+				t.Fatal(e)
+			}
+			if len(rs) != c.count {
+				t.Errorf("expected %d records, got %d", c.count, len(rs))
+			}
+		})
+	}
+}
+
+func TestNewAuditChanges(t *testing.T) {
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	old := NewNodeWithID(id)
+	old.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_ON))
+	new := NewNodeWithID(id)
+	new.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_OFF))
+
+	cs := NewAuditChanges(old, new)
+	if len(cs) != 1 {
+		t.Fatalf("expected 1 change, got %v", cs)
+	}
+	if cs[0].Url != "/PhysState" || cs[0].OldValue != "POWER_ON" || cs[0].NewValue != "POWER_OFF" {
+		t.Errorf("wrong change: %v", cs[0])
+	}
+
+	// deleting a node changes everything that was set
+	cs = NewAuditChanges(old, nil)
+	if len(cs) != 1 || cs[0].OldValue != "POWER_ON" {
+		t.Errorf("wrong changes for a deleted node: %v", cs)
+	}
+	if cs = NewAuditChanges(new, new); len(cs) != 0 {
+		t.Errorf("expected no changes, got %v", cs)
+	}
+}
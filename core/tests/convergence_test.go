@@ -0,0 +1,84 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestStateMutationEngine_Convergence(t *testing.T) {
+	sdqc, smqc := make(chan lib.Query), make(chan lib.Query)
+	ctx := Context{
+		Self:    NewNodeID("123e4567-e89b-12d3-a456-426655440009"),
+		SubChan: make(chan lib.EventListener, 1), // for the SDE's discovery listener
+	}
+	ctx.SME.RootSpec = NewStateSpec(map[string]reflect.Value{}, map[string]reflect.Value{})
+	ctx.Query = *NewQueryEngine(sdqc, smqc)
+	sde := NewStateDifferenceEngine(ctx, sdqc)
+	go sde.Run()
+	sme := NewStateMutationEngine(ctx, smqc)
+	sme.RegisterMutation("test", "on", NewStateMutation(
+		map[string][2]reflect.Value{
+			"/PhysState": {reflect.ValueOf(pb.Node_POWER_OFF), reflect.ValueOf(pb.Node_POWER_ON)},
+		},
+		map[string]reflect.Value{},
+		map[string]reflect.Value{},
+		lib.StateMutationContext_CHILD,
+		time.Second*10,
+		[3]string{"", "", ""},
+	))
+
+	on, off := "123e4567-e89b-12d3-a456-426655440000", "123e4567-e89b-12d3-a456-426655440001"
+	for _, id := range []string{on, off} {
+		n := NewNodeWithID(id)
+		n.SetValue("/Nodename", reflect.ValueOf(id[len(id)-1:]))
+		n.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_ON))
+		sde.Create(n)
+		d, _ := sde.ReadDsc(NewNodeID(id))
+		d.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_ON))
+		if id == off {
+			d.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_OFF))
+		}
+		sde.UpdateDsc(d)
+	}
+
+	ncs, e := sme.Convergence()
+	if e != nil {
+		t.Fatal(e)
+	}
+	// the SDE has ourself too, which sorts last
+	if len(ncs) != 3 || ncs[0].Id != on || ncs[1].Id != off {
+		t.Fatalf("wrong nodes in report: %v", ncs)
+	}
+	if !ncs[0].Converged || len(ncs[0].Deltas) != 0 || ncs[0].Diverged != nil {
+		t.Errorf("node should have converged: %v", ncs[0])
+	}
+	if ncs[1].Converged || len(ncs[1].Deltas) != 1 || ncs[1].Diverged == nil {
+		t.Fatalf("node should have diverged: %v", ncs[1])
+	}
+	if d := ncs[1].Deltas[0]; d.Url != "/PhysState" || d.Cfg != "POWER_ON" || d.Dsc != "POWER_OFF" {
+		t.Errorf("wrong delta: %v", d)
+	}
+	since, _ := ptypes.Timestamp(ncs[1].Diverged)
+
+	// it's still the same divergence
+	time.Sleep(10 * time.Millisecond)
+	ncs, _ = sme.Convergence()
+	if t2, _ := ptypes.Timestamp(ncs[1].Diverged); !t2.Equal(since) {
+		t.Errorf("divergence time changed: %v != %v", t2, since)
+	}
+
+	// once it matches, it's converged
+	d, _ := sde.ReadDsc(NewNodeID(off))
+	d.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_ON))
+	sde.UpdateDsc(d)
+	ncs, _ = sme.Convergence()
+	if !ncs[1].Converged || ncs[1].Diverged != nil {
+		t.Errorf("node should have converged: %v", ncs[1])
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	. "github.com/hpc/kraken/core"
 	pb "github.com/hpc/kraken/core/proto"
 )
@@ -88,6 +89,25 @@ func TestNode_JSON(t *testing.T) {
 	t.Logf("Proto message (JSON): \n%s\n", out)
 }
 
+// The JSON names of a node's fields are part of the ReST API's v1 contract; renamed fields must keep them
+func TestNode_JSONNames(t *testing.T) {
+	v1 := []string{"id", "nodename", "runState", "physState", "arch", "platform", "parentId", "services", "extensions",
+		"mutationCosts", "groups", "frozen", "priority", "schedule", "lifecycle", "removeDecommissioned", "location"}
+	names := make(map[string]bool)
+	for _, p := range proto.GetProperties(reflect.TypeOf(pb.Node{})).Prop {
+		if p.JSONName != "" {
+			names[p.JSONName] = true
+		} else {
+			names[p.OrigName] = true
+		}
+	}
+	for _, n := range v1 {
+		if !names[n] {
+			t.Errorf("node has no field with the JSON name %s", n)
+		}
+	}
+}
+
 func TestNewNodeFromJSON(t *testing.T) {
 	jin := []byte("{\"id\":\"Ej5FZ+ibEtOkVkJmVUQAAA==\",\"nodename\":\"noname\",\"run_state\":\"INIT\",\"phys_state\":1}")
 	t.Logf("in: %s", jin)
@@ -404,3 +424,51 @@ func TestSetValue(t *testing.T) {
 }
 
 */
+
+func TestNodeGroups(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	m := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	n.AddGroup("compute")
+	n.AddGroup("compute")
+	n.AddGroup("io")
+	if !reflect.DeepEqual(n.GetGroups(), []string{"compute", "io"}) {
+		t.Errorf("unexpected groups: %v", n.GetGroups())
+	}
+	if !n.InGroup("io") || m.InGroup("io") {
+		t.Error("InGroup gave the wrong answer")
+	}
+	d, e := m.Diff(n, "")
+	if e != nil || len(d) != 2 {
+		t.Errorf("expected 2 group diffs, got: %v, %v", d, e)
+	}
+	n.DelGroup("compute")
+	if !reflect.DeepEqual(n.GetGroups(), []string{"io"}) {
+		t.Errorf("unexpected groups after delete: %v", n.GetGroups())
+	}
+}
+
+func TestNodeFrozen(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	m := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	if n.Frozen() {
+		t.Error("new node is frozen")
+	}
+	n.SetValue("/Frozen", reflect.ValueOf(true))
+	if !n.Frozen() {
+		t.Error("failed to freeze node")
+	}
+	if d, e := m.Diff(n, ""); e != nil || len(d) != 1 || d[0] != "/Frozen" {
+		t.Errorf("expected a /Frozen diff, got: %v, %v", d, e)
+	}
+}
+
+func TestNodePriority(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	if n.Priority() != pb.Node_ROUTINE {
+		t.Errorf("new node has priority: %v", n.Priority())
+	}
+	n.SetValue("/Priority", reflect.ValueOf(pb.Node_EMERGENCY))
+	if n.Priority() != pb.Node_EMERGENCY {
+		t.Errorf("failed to set priority: %v", n.Priority())
+	}
+}
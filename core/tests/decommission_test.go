@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestQueryEngine_Decommission(t *testing.T) {
+	ctx := Context{
+		Self:    NewNodeID("123e4567-e89b-12d3-a456-426655440009"),
+		SubChan: make(chan lib.EventListener, 1), // for the SDE's discovery listener
+	}
+	qc := make(chan lib.Query)
+	sde := NewStateDifferenceEngine(ctx, qc)
+	go sde.Run()
+	qe := NewQueryEngine(qc, nil)
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+
+	n, e := qe.Decommission(NewNodeID(id), map[string]string{"/PhysState": "POWER_OFF"}, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n.Lifecycle() != pb.Node_DECOMMISSIONING {
+		t.Errorf("node isn't decommissioning: %s", n.Lifecycle())
+	}
+	if v, _ := n.GetValue("/PhysState"); v.Interface() != pb.Node_POWER_OFF {
+		t.Errorf("final state wasn't set: %v", v)
+	}
+	if v, _ := n.GetValue("/RemoveDecommissioned"); !v.Bool() {
+		t.Error("remove wasn't set")
+	}
+
+	if _, e = qe.Decommission(NewNodeID(id), map[string]string{"/PhysState": "NO_SUCH_STATE"}, false); e == nil {
+		t.Error("expected a bad final value to fail")
+	}
+}
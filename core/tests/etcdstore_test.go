@@ -0,0 +1,716 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+)
+
+type fakeEtcdKV struct {
+	value []byte
+	mod   int64
+	lease int64
+}
+
+type fakeEtcdEvent struct {
+	del   bool
+	key   string
+	value []byte
+	mod   int64
+}
+
+// fakeEtcd is just enough of the etcd v3 JSON gateway for an EtcdStateStore.
+// Leases only run out when expire is called, and revisions are only compacted when compact is.
+type fakeEtcd struct {
+	mutex     sync.Mutex
+	rev       int64
+	kvs       map[string]fakeEtcdKV
+	events    []fakeEtcdEvent
+	compacted int64 // events up to this revision are gone
+	nowatch   bool  // watches are refused
+	leases    map[int64]bool
+	lease     int64
+	changed   chan struct{} // closed and replaced on every change, to wake watches
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		rev:     1,
+		kvs:     make(map[string]fakeEtcdKV),
+		leases:  make(map[int64]bool),
+		changed: make(chan struct{}),
+	}
+}
+
+// fakeEtcdReq is the union of the requests we handle
+type fakeEtcdReq struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end"`
+	Value    []byte `json:"value"`
+	ID       int64  `json:"ID"`
+	TTL      int64  `json:"TTL"`
+	Compare  []struct {
+		Key            []byte `json:"key"`
+		CreateRevision int64  `json:"create_revision"`
+	} `json:"compare"`
+	Success []struct {
+		RequestPut struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+			Lease int64  `json:"lease"`
+		} `json:"request_put"`
+	} `json:"success"`
+	CreateRequest struct {
+		Key           []byte `json:"key"`
+		RangeEnd      []byte `json:"range_end"`
+		StartRevision int64  `json:"start_revision"`
+	} `json:"create_request"`
+}
+
+func inRange(k string, key, end []byte) bool {
+	return bytes.Compare([]byte(k), key) >= 0 && bytes.Compare([]byte(k), end) < 0
+}
+
+func (f *fakeEtcd) header() map[string]string {
+	return map[string]string{"revision": fmt.Sprint(f.rev)}
+}
+
+// the following must be called with the mutex held
+
+func (f *fakeEtcd) put(k string, v []byte, lease int64) {
+	f.rev++
+	f.kvs[k] = fakeEtcdKV{value: v, mod: f.rev, lease: lease}
+	f.events = append(f.events, fakeEtcdEvent{key: k, value: v, mod: f.rev})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeEtcd) del(k string) bool {
+	if _, ok := f.kvs[k]; !ok {
+		return false
+	}
+	f.rev++
+	delete(f.kvs, k)
+	f.events = append(f.events, fakeEtcdEvent{del: true, key: k, mod: f.rev})
+	close(f.changed)
+	f.changed = make(chan struct{})
+	return true
+}
+
+func (f *fakeEtcd) revoke(id int64) {
+	delete(f.leases, id)
+	for k, kv := range f.kvs {
+		if kv.lease == id {
+			f.del(k)
+		}
+	}
+}
+
+// expire lets the lease holding k run out
+func (f *fakeEtcd) expire(k string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.revoke(f.kvs[k].lease)
+}
+
+// compact forgets the events up to the current revision
+func (f *fakeEtcd) compact() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.events = nil
+	f.compacted = f.rev
+}
+
+// watches sets whether watches are served
+func (f *fakeEtcd) watches(on bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.nowatch = !on
+}
+
+func (f *fakeEtcd) get(k string) (v []byte, ok bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	kv, ok := f.kvs[k]
+	return kv.value, ok
+}
+
+func (f *fakeEtcd) keys() (ks []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for k := range f.kvs {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req fakeEtcdReq
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		http.Error(w, `{"message":"bad request"}`, http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path == "/v3/watch" {
+		f.watch(w, r, req)
+		return
+	}
+	f.mutex.Lock()
+	var resp interface{}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		var ks []string
+		for k := range f.kvs {
+			if inRange(k, req.Key, req.RangeEnd) {
+				ks = append(ks, k)
+			}
+		}
+		sort.Strings(ks)
+		var kvs []map[string]interface{}
+		for _, k := range ks {
+			kvs = append(kvs, map[string]interface{}{"key": []byte(k), "value": f.kvs[k].value, "mod_revision": fmt.Sprint(f.kvs[k].mod)})
+		}
+		resp = map[string]interface{}{"header": f.header(), "kvs": kvs}
+	case "/v3/kv/put":
+		f.put(string(req.Key), req.Value, 0)
+		resp = map[string]interface{}{"header": f.header()}
+	case "/v3/kv/deleterange":
+		n := 0
+		if f.del(string(req.Key)) {
+			n = 1
+		}
+		resp = map[string]interface{}{"header": f.header(), "deleted": fmt.Sprint(n)}
+	case "/v3/kv/txn":
+		ok := true
+		for _, c := range req.Compare {
+			if _, exists := f.kvs[string(c.Key)]; exists != (c.CreateRevision != 0) {
+				ok = false
+			}
+		}
+		if ok {
+			for _, s := range req.Success {
+				f.put(string(s.RequestPut.Key), s.RequestPut.Value, s.RequestPut.Lease)
+			}
+		}
+		resp = map[string]interface{}{"header": f.header(), "succeeded": ok}
+	case "/v3/lease/grant":
+		f.lease++
+		f.leases[f.lease] = true
+		resp = map[string]string{"ID": fmt.Sprint(f.lease), "TTL": fmt.Sprint(req.TTL)}
+	case "/v3/lease/keepalive":
+		// like etcd, a lease that's gone has no TTL
+		result := map[string]string{"ID": fmt.Sprint(req.ID)}
+		if f.leases[req.ID] {
+			result["TTL"] = fmt.Sprint(EtcdLeaseTTL)
+		}
+		resp = map[string]interface{}{"result": result}
+	case "/v3/lease/revoke":
+		f.revoke(req.ID)
+		resp = map[string]interface{}{"header": f.header()}
+	default:
+		f.mutex.Unlock()
+		http.NotFound(w, r)
+		return
+	}
+	f.mutex.Unlock()
+	json.NewEncoder(w).Encode(resp)
+}
+
+// watch streams events in the requested range, starting at its start revision, until the client goes away
+func (f *fakeEtcd) watch(w http.ResponseWriter, r *http.Request, req fakeEtcdReq) {
+	cr := req.CreateRequest
+	next := cr.StartRevision
+	f.mutex.Lock()
+	if f.nowatch {
+		f.mutex.Unlock()
+		http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+		return
+	}
+	if next == 0 {
+		next = f.rev + 1
+	}
+	compacted := f.compacted
+	f.mutex.Unlock()
+	enc := json.NewEncoder(w)
+	if next <= compacted {
+		// like etcd, the watch is created and canceled at once
+		enc.Encode(map[string]interface{}{"result": map[string]interface{}{
+			"created":          true,
+			"canceled":         true,
+			"compact_revision": fmt.Sprint(compacted),
+			"cancel_reason":    "mvcc: required revision has been compacted",
+		}})
+		return
+	}
+	enc.Encode(map[string]interface{}{"result": map[string]interface{}{"created": true}})
+	w.(http.Flusher).Flush()
+	for {
+		f.mutex.Lock()
+		var evs []map[string]interface{}
+		for _, ev := range f.events {
+			if ev.mod < next || !inRange(ev.key, cr.Key, cr.RangeEnd) {
+				continue
+			}
+			e := map[string]interface{}{
+				"kv": map[string]interface{}{"key": []byte(ev.key), "value": ev.value, "mod_revision": fmt.Sprint(ev.mod)},
+			}
+			if ev.del {
+				e["type"] = "DELETE"
+			}
+			evs = append(evs, e)
+		}
+		next = f.rev + 1
+		changed := f.changed
+		f.mutex.Unlock()
+		if len(evs) > 0 {
+			enc.Encode(map[string]interface{}{"result": map[string]interface{}{"events": evs}})
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func openEtcd(t *testing.T, location string) *EtcdStateStore {
+	s, e := OpenStateStore("etcd", location)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return s.(*EtcdStateStore)
+}
+
+func nextStoreEvent(t *testing.T, c <-chan StateStoreEvent) StateStoreEvent {
+	select {
+	case se := <-c:
+		return se
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a state store event")
+	}
+	return StateStoreEvent{}
+}
+
+func TestEtcdStateStore(t *testing.T) {
+	f := newFakeEtcd()
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	s := openEtcd(t, srv.URL+"/kraken")
+	defer s.Close()
+
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	n := NewNodeWithID(id)
+	n.SetValue("/Nodename", reflect.ValueOf("testing"))
+
+	t.Run("save and load", func(t *testing.T) {
+		if e := s.Save(false, n); e != nil {
+			t.Fatal(e)
+		}
+		ns, e := s.Load(false)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(ns) != 1 || !ns[0].ID().Equal(n.ID()) {
+			t.Fatalf("expected node %s, got %v", id, ns)
+		}
+		if v, e := ns[0].GetValue("/Nodename"); e != nil || v.String() != "testing" {
+			t.Errorf("value mismatch: %v, %v", v, e)
+		}
+		if _, ok := f.get("/kraken/cfg/" + id); !ok {
+			t.Errorf("node isn't under the prefix: %v", f.keys())
+		}
+	})
+
+	t.Run("discoverable state isn't shared", func(t *testing.T) {
+		if e := s.Save(true, n); e != nil {
+			t.Fatal(e)
+		}
+		if ks := f.keys(); len(ks) != 1 {
+			t.Errorf("discoverable state was written: %v", ks)
+		}
+		if ns, e := s.Load(true); e != nil || len(ns) != 0 {
+			t.Errorf("expected no dsc nodes, got %d, %v", len(ns), e)
+		}
+		if e := s.Delete(true, n.ID()); e != nil {
+			t.Error(e)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if e := s.Delete(false, n.ID()); e != nil {
+			t.Fatal(e)
+		}
+		if e := s.Delete(false, n.ID()); e != nil {
+			t.Errorf("deleting a missing node should not fail: %v", e)
+		}
+		if ns, _ := s.Load(false); len(ns) != 0 {
+			t.Errorf("expected no nodes after delete, got %d", len(ns))
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		dead := httptest.NewServer(f)
+		dead.Close()
+		if _, e := OpenStateStore("etcd", dead.URL); e == nil {
+			t.Error("expected an error when etcd can't be reached")
+		}
+	})
+}
+
+// TestEtcdStateStore_Watch shares a store between two head nodes
+func TestEtcdStateStore_Watch(t *testing.T) {
+	f := newFakeEtcd()
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	a, b := openEtcd(t, srv.URL+"/kraken"), openEtcd(t, srv.URL+"/kraken")
+	defer a.Close()
+	defer b.Close()
+
+	early := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	theirs := NewNodeWithID("123e4567-e89b-12d3-a456-426655440001")
+	ours := NewNodeWithID("123e4567-e89b-12d3-a456-426655440002")
+	theirs.SetValue("/Nodename", reflect.ValueOf("theirs"))
+	// made before a loads, so a shouldn't see it again
+	b.Save(false, early)
+	if ns, _ := a.Load(false); len(ns) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(ns))
+	}
+	c := make(chan StateStoreEvent, 10)
+	a.Watch(c)
+
+	a.Save(false, ours)
+	b.Save(false, theirs)
+	b.Save(true, theirs)
+	se := nextStoreEvent(t, c)
+	if !se.ID.Equal(theirs.ID()) || se.Delete {
+		t.Fatalf("expected %s to be saved, got: %+v; our own writes shouldn't come back", theirs.ID().String(), se)
+	}
+	if v, e := se.Node.GetValue("/Nodename"); e != nil || v.String() != "theirs" {
+		t.Errorf("value mismatch: %v, %v", v, e)
+	}
+	b.Delete(false, early.ID())
+	if se = nextStoreEvent(t, c); !se.ID.Equal(early.ID()) || !se.Delete {
+		t.Fatalf("expected %s to be deleted, got: %+v", early.ID().String(), se)
+	}
+	select {
+	case se = <-c:
+		t.Errorf("unexpected event: %+v", se)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestEtcdStateStore_Failover checks that requests and watches move to the next endpoint, without missing anything
+func TestEtcdStateStore_Failover(t *testing.T) {
+	f := newFakeEtcd()
+	first, second := httptest.NewServer(f), httptest.NewServer(f)
+	defer second.Close()
+	// the prefix is the first endpoint's path
+	s := openEtcd(t, first.URL+"/kraken,"+second.URL)
+	defer s.Close()
+	other := openEtcd(t, second.URL+"/kraken")
+	defer other.Close()
+	s.Load(false)
+	c := make(chan StateStoreEvent, 10)
+	s.Watch(c)
+	time.Sleep(100 * time.Millisecond)
+
+	first.CloseClientConnections()
+	first.Close()
+	// this is made while s has no watch
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	other.Save(false, n)
+	if se := nextStoreEvent(t, c); !se.ID.Equal(n.ID()) {
+		t.Fatalf("expected %s, got: %+v", n.ID().String(), se)
+	}
+
+	m := NewNodeWithID("123e4567-e89b-12d3-a456-426655440001")
+	if e := s.Save(false, m); e != nil {
+		t.Fatal(e)
+	}
+	if _, ok := f.get("/kraken/cfg/" + m.ID().String()); !ok {
+		t.Errorf("node wasn't saved under the first endpoint's prefix: %v", f.keys())
+	}
+	if ns, e := s.Load(false); e != nil || len(ns) != 2 {
+		t.Errorf("expected 2 nodes, got %d, %v", len(ns), e)
+	}
+}
+
+// TestEtcdStateStore_Compacted checks that a watch that falls behind a compaction catches up on what it missed
+func TestEtcdStateStore_Compacted(t *testing.T) {
+	f := newFakeEtcd()
+	srv, watched := httptest.NewServer(f), httptest.NewServer(f)
+	defer srv.Close()
+	defer watched.Close()
+	a, b := openEtcd(t, watched.URL+"/kraken"), openEtcd(t, srv.URL+"/kraken")
+	defer a.Close()
+	defer b.Close()
+
+	same := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	changed := NewNodeWithID("123e4567-e89b-12d3-a456-426655440001")
+	deleted := NewNodeWithID("123e4567-e89b-12d3-a456-426655440002")
+	added := NewNodeWithID("123e4567-e89b-12d3-a456-426655440003")
+	for _, n := range []lib.Node{same, changed, deleted} {
+		b.Save(false, n)
+	}
+	if ns, _ := a.Load(false); len(ns) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(ns))
+	}
+	c := make(chan StateStoreEvent, 10)
+	a.Watch(c)
+	time.Sleep(100 * time.Millisecond)
+
+	// a loses its watch, and the changes it would have seen are compacted away before it's back
+	f.watches(false)
+	watched.CloseClientConnections()
+	changed.SetValue("/Nodename", reflect.ValueOf("changed"))
+	b.Save(false, changed)
+	b.Delete(false, deleted.ID())
+	b.Save(false, added)
+	f.compact()
+	f.watches(true)
+
+	got := make(map[string]StateStoreEvent)
+	for i := 0; i < 3; i++ {
+		se := nextStoreEvent(t, c)
+		got[se.ID.String()] = se
+	}
+	if se, ok := got[changed.ID().String()]; !ok || se.Delete {
+		t.Errorf("expected %s to be saved, got: %+v", changed.ID().String(), got)
+	} else if v, e := se.Node.GetValue("/Nodename"); e != nil || v.String() != "changed" {
+		t.Errorf("value mismatch: %v, %v", v, e)
+	}
+	if se, ok := got[deleted.ID().String()]; !ok || !se.Delete {
+		t.Errorf("expected %s to be deleted, got: %+v", deleted.ID().String(), got)
+	}
+	if se, ok := got[added.ID().String()]; !ok || se.Delete {
+		t.Errorf("expected %s to be saved, got: %+v", added.ID().String(), got)
+	}
+
+	// the watch carries on from where it caught up to; nothing's sent again
+	later := NewNodeWithID("123e4567-e89b-12d3-a456-426655440004")
+	b.Save(false, later)
+	if se := nextStoreEvent(t, c); !se.ID.Equal(later.ID()) {
+		t.Errorf("expected %s, got: %+v", later.ID().String(), se)
+	}
+	select {
+	case se := <-c:
+		t.Errorf("unexpected event: %+v", se)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestEtcdStateStore_Campaign(t *testing.T) {
+	ttl := EtcdLeaseTTL
+	EtcdLeaseTTL = 1
+	defer func() { EtcdLeaseTTL = ttl }()
+	f := newFakeEtcd()
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	a, b := openEtcd(t, srv.URL+"/kraken"), openEtcd(t, srv.URL+"/kraken")
+	defer a.Close()
+	leader := func() string {
+		v, _ := f.get("/kraken/leader")
+		return string(v)
+	}
+	lost := func(l <-chan bool, what string) {
+		select {
+		case <-l:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("still the leader after %s", what)
+		}
+	}
+
+	aLost, e := a.Campaign("a")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if leader() != "a" {
+		t.Fatalf("expected a to lead, got %q", leader())
+	}
+	type result struct {
+		lost <-chan bool
+		e    error
+	}
+	bLeads := make(chan result)
+	go func() {
+		l, e := b.Campaign("b")
+		bLeads <- result{l, e}
+	}()
+	select {
+	case <-bLeads:
+		t.Fatal("b became the leader while a's lease was alive")
+	case <-time.After(time.Second):
+	}
+	if leader() != "a" {
+		t.Fatalf("a's lease was kept alive, but a isn't the leader: %q", leader())
+	}
+
+	t.Run("lease runs out", func(t *testing.T) {
+		f.expire("/kraken/leader")
+		lost(aLost, "its lease ran out")
+		var r result
+		select {
+		case r = <-bLeads:
+		case <-time.After(5 * time.Second):
+			t.Fatal("b didn't take over")
+		}
+		if r.e != nil {
+			t.Fatal(r.e)
+		}
+		if leader() != "b" {
+			t.Fatalf("expected b to lead, got %q", leader())
+		}
+		b.Close()
+		lost(r.lost, "closing")
+		if l := leader(); l != "" {
+			t.Errorf("closing didn't give up the leader key: %q", l)
+		}
+	})
+
+	t.Run("etcd unreachable", func(t *testing.T) {
+		down := httptest.NewServer(f)
+		c := openEtcd(t, down.URL+"/kraken")
+		defer c.Close()
+		cLost, e := c.Campaign("c")
+		if e != nil {
+			t.Fatal(e)
+		}
+		down.CloseClientConnections()
+		down.Close()
+		// we give up before the lease would run out, so no one else can lead at the same time
+		start := time.Now()
+		lost(cLost, "losing etcd")
+		if d := time.Since(start); d >= time.Duration(EtcdLeaseTTL)*time.Second {
+			t.Errorf("gave up leadership after %v, when the lease only lasts %ds", d, EtcdLeaseTTL)
+		}
+	})
+}
+
+// blackhole serves f until it's holed; then requests, other than watches, hang until they're released
+type blackhole struct {
+	f       *fakeEtcd
+	holed   chan struct{}
+	release chan struct{}
+}
+
+func newBlackhole(f *fakeEtcd) *blackhole {
+	return &blackhole{f: f, holed: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (b *blackhole) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-b.holed:
+		if r.URL.Path != "/v3/watch" {
+			select {
+			case <-b.release:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	default:
+	}
+	b.f.ServeHTTP(w, r)
+}
+
+func TestEtcdStateStore_Timeout(t *testing.T) {
+	timeout := EtcdTimeout
+	EtcdTimeout = 200 * time.Millisecond
+	defer func() { EtcdTimeout = timeout }()
+	bh := newBlackhole(newFakeEtcd())
+	srv := httptest.NewServer(bh)
+	defer srv.Close()
+	defer close(bh.release)
+	s := openEtcd(t, srv.URL+"/kraken")
+	defer s.Close()
+
+	close(bh.holed)
+	start := time.Now()
+	if e := s.Save(false, NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")); e == nil {
+		t.Error("a save to an etcd that doesn't answer succeeded")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("a save to an etcd that doesn't answer took %v", d)
+	}
+}
+
+// TestStateDifferenceEngine_BlackholedStore checks that the SDE keeps answering queries while its store doesn't answer,
+// and that its writes are made once the store comes back
+func TestStateDifferenceEngine_BlackholedStore(t *testing.T) {
+	timeout := EtcdTimeout
+	EtcdTimeout = time.Minute
+	defer func() { EtcdTimeout = timeout }()
+	f := newFakeEtcd()
+	bh := newBlackhole(f)
+	srv := httptest.NewServer(bh)
+	defer srv.Close()
+	released := false
+	defer func() {
+		if !released {
+			close(bh.release)
+		}
+	}()
+	s := openEtcd(t, srv.URL+"/kraken")
+	defer s.Close()
+
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	ctx.Store.Store = s
+	ede := NewEventDispatchEngine(ctx)
+	ctx.SubChan = ede.SubscriptionChan()
+	sdqc := make(chan lib.Query)
+	ctx.Query = *NewQueryEngine(sdqc, make(chan lib.Query))
+	sde := NewStateDifferenceEngine(ctx, sdqc)
+	go ede.Run()
+	go sde.Run()
+
+	close(bh.holed)
+	var ids []string
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 5; i++ {
+			id := fmt.Sprintf("123e4567-e89b-12d3-a456-4266554402%02d", i)
+			ids = append(ids, id)
+			if _, e := ctx.Query.Create(NewNodeWithID(id)); e != nil {
+				done <- e
+				return
+			}
+			if _, e := ctx.Query.Read(NewNodeID(id)); e != nil {
+				done <- e
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case e := <-done:
+		if e != nil {
+			t.Fatal(e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queries were held up by a store that doesn't answer")
+	}
+
+	close(bh.release)
+	released = true
+	for _, id := range ids {
+		for i := 0; ; i++ {
+			if _, ok := f.get("/kraken/cfg/" + id); ok {
+				break
+			}
+			if i > 500 {
+				t.Fatalf("%s wasn't written once the store came back", id)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
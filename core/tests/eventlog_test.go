@@ -0,0 +1,60 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestFileEventLog(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-eventlog")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	l, e := OpenEventLog("file", filepath.Join(dir, "events.log"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer l.Close()
+
+	n1 := "123e4567-e89b-12d3-a456-426655440000"
+	n2 := "123e4567-e89b-12d3-a456-426655440001"
+	base := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, n := range []string{n1, n2, n1} {
+		ts, _ := ptypes.TimestampProto(base.Add(time.Duration(i) * time.Minute))
+		if e = l.Append(&pb.EventRecord{Time: ts, Type: "DISCOVERY", Node: n, Url: n + ":/PhysState"}); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, c := range []struct {
+		name     string
+		node     string
+		from, to time.Time
+		count    int
+	}{
+		{"all", "", time.Time{}, time.Time{}, 3},
+		{"node", n1, time.Time{}, time.Time{}, 2},
+		{"from", "", base.Add(time.Minute), time.Time{}, 2},
+		{"to", "", time.Time{}, base.Add(time.Minute), 2},
+		{"node and range", n1, base.Add(time.Second), base.Add(time.Hour), 1},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			rs, e := l.Query(c.node, c.from, c.to)
+			if e != nil {
+				t.Fatal(e)
+			}
+			if len(rs) != c.count {
+				t.Errorf("expected %d records, got %d", c.count, len(rs))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestFileEventQueue(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-queue")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	q, e := OpenEventQueue("file", dir, "test")
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if e = q.Push(&pb.MutationControl{Module: "test", Id: id}); e != nil {
+			t.Fatal(e)
+		}
+	}
+	q.Ack(1)
+	q.Close()
+
+	// what wasn't acknowledged survives a restart
+	q, e = OpenEventQueue("file", dir, "test")
+	if e != nil {
+		t.Fatal(e)
+	}
+	ms, _ := q.Pending()
+	if len(ms) != 2 || ms[0].Id != "b" || ms[0].Seq != 2 || ms[1].Id != "c" {
+		t.Fatalf("wrong pending mutations: %v", ms)
+	}
+	q.Ack(3)
+	q.Close()
+
+	// numbering carries on after the queue empties
+	q, _ = OpenEventQueue("file", dir, "test")
+	defer q.Close()
+	if ms, _ = q.Pending(); len(ms) != 0 {
+		t.Errorf("acknowledged mutations are pending: %v", ms)
+	}
+	mc := &pb.MutationControl{Module: "test", Id: "d"}
+	q.Push(mc)
+	if mc.Seq != 4 {
+		t.Errorf("expected seq 4, got %d", mc.Seq)
+	}
+}
+
+func TestFileEventQueue_Torn(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-queue")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	q, e := OpenEventQueue("file", dir, "test")
+	if e != nil {
+		t.Fatal(e)
+	}
+	q.Push(&pb.MutationControl{Module: "test", Id: "a"})
+	q.Close()
+	// we crashed while pushing another
+	f, e := os.OpenFile(filepath.Join(dir, "test.queue"), os.O_WRONLY|os.O_APPEND, 0600)
+	if e != nil {
+		t.Fatal(e)
+	}
+	f.WriteString(`+{"module":"test","id":"b`)
+	f.Close()
+
+	q, e = OpenEventQueue("file", dir, "test")
+	if e != nil {
+		t.Fatalf("a torn last line should be dropped: %v", e)
+	}
+	ms, _ := q.Pending()
+	if len(ms) != 1 || ms[0].Id != "a" {
+		t.Fatalf("wrong pending mutations: %v", ms)
+	}
+	// what's pushed now isn't mixed up with what was torn
+	q.Push(&pb.MutationControl{Module: "test", Id: "c"})
+	q.Close()
+	q, e = OpenEventQueue("file", dir, "test")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer q.Close()
+	if ms, _ = q.Pending(); len(ms) != 2 || ms[1].Id != "c" || ms[1].Seq != 2 {
+		t.Errorf("wrong pending mutations: %v", ms)
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestMutationGraphDOT(t *testing.T) {
+	nodes := &pb.MutationNodeList{
+		MutationNodeList: []*pb.MutationNode{
+			{Id: "a", Requires: map[string]string{"/PhysState": "POWER_OFF"}},
+			{Id: "b", Requires: map[string]string{"/PhysState": "POWER_ON"}, Excludes: map[string]string{"/Arch": "\"x\""}},
+		},
+	}
+	edges := &pb.MutationEdgeList{
+		MutationEdgeList: []*pb.MutationEdge{
+			{
+				Id: "e", From: "a", To: "b",
+				Module: "github.com/hpc/kraken/modules/ipmipower", Mutation: "OFFtoON",
+				Mutates: map[string]string{"/PhysState": "POWER_OFF -> POWER_ON"},
+				Timeout: "10s",
+				Cost:    DefaultMutationCost,
+			},
+		},
+	}
+	dot := string(MutationGraphDOT(nodes, edges))
+	for _, want := range []string{
+		"digraph mutations {",
+		`"a" [label="/PhysState = POWER_OFF"];`,
+		`"b" [label="/PhysState = POWER_ON\n/Arch != \"x\""];`,
+		`"a" -> "b" [label="github.com/hpc/kraken/modules/ipmipower: OFFtoON\n/PhysState: POWER_OFF -> POWER_ON\ntimeout: 10s"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("missing %s in:\n%s", want, dot)
+		}
+	}
+}
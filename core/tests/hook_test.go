@@ -0,0 +1,35 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+)
+
+func TestScriptMutationHook(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	ok := NewScriptMutationHook(MutationHook_PRE, "/bin/sh", "-c", `test "$KRAKEN_HOOK:$KRAKEN_MODULE:$KRAKEN_MUTATION" = "pre:test:ONtoOFF" && grep -q "\"id\""`)
+	if e := ok(context.Background(), [2]string{"test", "ONtoOFF"}, n, n); e != nil {
+		t.Errorf("hook failed: %v", e)
+	}
+	fail := NewScriptMutationHook(MutationHook_POST, "/bin/sh", "-c", "exit 1")
+	if e := fail(context.Background(), [2]string{"test", "ONtoOFF"}, n, n); e == nil {
+		t.Error("failing hook succeeded")
+	}
+}
+
+func TestScriptMutationHook_Timeout(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	hung := NewScriptMutationHook(MutationHook_PRE, "/bin/sh", "-c", "exec sleep 10")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if e := hung(ctx, [2]string{"test", "ONtoOFF"}, n, n); e == nil {
+		t.Error("hook that ran past its deadline succeeded")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("hook ran for %v after its deadline", d)
+	}
+}
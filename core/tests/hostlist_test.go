@@ -0,0 +1,34 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+)
+
+func TestExpandHostlist(t *testing.T) {
+	tests := []struct {
+		hl     string
+		expect []string
+		err    bool
+	}{
+		{"login1", []string{"login1"}, false},
+		{"node[001-003,7],login1", []string{"node001", "node002", "node003", "node7", "login1"}, false},
+		{"rack[1-2]n[1-2]", []string{"rack1n1", "rack1n2", "rack2n1", "rack2n2"}, false},
+		{"node[08-10].cluster", []string{"node08.cluster", "node09.cluster", "node10.cluster"}, false},
+		{"node[3-1]", nil, true},
+		{"node[1-2", nil, true},
+		{"node[0-99999999]", nil, true},
+	}
+	for _, tt := range tests {
+		r, e := ExpandHostlist(tt.hl)
+		if (e != nil) != tt.err {
+			t.Errorf("%s: unexpected error: %v", tt.hl, e)
+			continue
+		}
+		if !tt.err && !reflect.DeepEqual(r, tt.expect) {
+			t.Errorf("%s: got %v, expected %v", tt.hl, r, tt.expect)
+		}
+	}
+}
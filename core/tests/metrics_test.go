@@ -0,0 +1,26 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 10})
+	for _, v := range []float64{0.5, 1, 2, 10, 11, 100} {
+		h.Observe(v)
+	}
+	p := h.Proto()
+	if !reflect.DeepEqual(p.Counts, []uint64{2, 2, 2}) {
+		t.Errorf("wrong bucket counts: %v", p.Counts)
+	}
+	if p.Count != 6 || p.Sum != 124.5 {
+		t.Errorf("wrong count/sum: %d/%f", p.Count, p.Sum)
+	}
+	h.Observe(3)
+	if p.Counts[1] != 2 {
+		t.Error("proto wasn't a copy")
+	}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+type queueModule struct{}
+
+func (queueModule) Name() string { return "queuetest" }
+
+// pending reads what's left in a module's file queue
+func pending(t *testing.T, dir string) (ids []string) {
+	q, e := OpenEventQueue("file", dir, "queuetest")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer q.Close()
+	ms, _ := q.Pending()
+	for _, mc := range ms {
+		ids = append(ids, NewNodeIDFromBinary(mc.GetCfg().GetId()).String())
+	}
+	return
+}
+
+// queueServer runs an API server with a file queue in dir, and registers the queuetest module's mutations.
+// Only wanted's mutation path is on queuetest:on.  mutate emits a mutation, and waits for it to be queued.
+func queueServer(t *testing.T, dir, wanted string) (sock string, mutate func(id string)) {
+	Registry.RegisterMutations(queueModule{}, map[string]lib.StateMutation{
+		"on": NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {reflect.ValueOf(pb.Node_POWER_OFF), reflect.ValueOf(pb.Node_POWER_ON)},
+			},
+			map[string]reflect.Value{},
+			map[string]reflect.Value{},
+			lib.StateMutationContext_CHILD,
+			time.Second,
+			[3]string{"queuetest", "/PhysState", "PHYS_HANG"},
+		),
+	})
+
+	sock = filepath.Join(dir, "api.sock")
+	l, e := net.Listen("unix", sock)
+	if e != nil {
+		t.Fatal(e)
+	}
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440001")}
+	ctx.RPC.UNIXListener = l
+	ctx.EventQueue = ContextEventQueue{Backend: "file", Location: dir}
+	ede := NewEventDispatchEngine(ctx)
+	ctx.SubChan = ede.SubscriptionChan()
+	sdqc, smqc := make(chan lib.Query), make(chan lib.Query)
+	ctx.Query = *NewQueryEngine(sdqc, smqc)
+	// stand in for the SME: only wanted's mutation path is still on queuetest:on
+	go func() {
+		for q := range smqc {
+			if NewNodeIDFromURL(q.URL()).String() != wanted {
+				q.ResponseChan() <- NewQueryResponse([]reflect.Value{reflect.ValueOf(pb.MutationPath{})}, fmt.Errorf("Mutation path is nil"))
+				continue
+			}
+			p := pb.MutationPath{Chain: []*pb.MutationEdge{{Module: "queuetest", Mutation: "on"}}}
+			q.ResponseChan() <- NewQueryResponse([]reflect.Value{reflect.ValueOf(p)}, nil)
+		}
+	}()
+	go ede.Run()
+	go NewAPIServer(ctx).Run()
+
+	em := NewEventEmitter(lib.Event_STATE_MUTATION)
+	em.Subscribe("test", ede.EventChan())
+	// waiting for each to be queued means they're queued in order
+	mutate = func(id string) {
+		queued := len(pending(t, dir))
+		n := NewNodeWithID(id)
+		em.EmitOne(NewEvent(lib.Event_STATE_MUTATION, id, &MutationEvent{
+			Type:     MutationEvent_MUTATE,
+			NodeCfg:  n,
+			NodeDsc:  n,
+			Mutation: [2]string{"queuetest", "on"},
+		}))
+		for i := 0; len(pending(t, dir)) <= queued; i++ {
+			if i > 500 {
+				t.Fatalf("mutation for %s wasn't queued", id)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	// the server needs a moment to start
+	time.Sleep(100 * time.Millisecond)
+	return
+}
+
+// nextMutation waits for a mutation on c
+func nextMutation(t *testing.T, c <-chan lib.Event) *MutationEvent {
+	select {
+	case v := <-c:
+		return v.Data().(*MutationEvent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a mutation")
+	}
+	return nil
+}
+
+// TestQueuedMutationAck checks that stale queued mutations aren't resent, and mutations are only acknowledged once they're done
+func TestQueuedMutationAck(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-queue")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	stale, wanted := "123e4567-e89b-12d3-a456-426655440020", "123e4567-e89b-12d3-a456-426655440021"
+	a, b := "123e4567-e89b-12d3-a456-426655440022", "123e4567-e89b-12d3-a456-426655440023"
+	sock, mutate := queueServer(t, dir, wanted)
+	defer delete(Registry.Mutations, "queuetest")
+
+	// these are queued before the module connects
+	mutate(stale)
+	mutate(wanted)
+
+	c, e := NewAPIClient("unix:"+sock).MutationInit("test", "queuetest")
+	if e != nil {
+		t.Fatal(e)
+	}
+	next := func() *MutationEvent { return nextMutation(t, c) }
+	if me := next(); me.NodeCfg.ID().String() != wanted {
+		t.Fatalf("got a mutation for %s, expected %s; %s's should have been dropped", me.NodeCfg.ID().String(), wanted, stale)
+	} else {
+		me.Done()
+	}
+	if p := pending(t, dir); len(p) != 0 {
+		t.Fatalf("mutations still queued after they were done: %v", p)
+	}
+
+	mutate(a)
+	mutate(b)
+	ma, mb := next(), next()
+	mb.Done()
+	if p := pending(t, dir); len(p) != 2 {
+		t.Fatalf("acknowledging %s shouldn't acknowledge %s, which isn't done yet: %v", b, a, p)
+	}
+	ma.Done()
+	if p := pending(t, dir); len(p) != 0 {
+		t.Fatalf("mutations still queued after they were done: %v", p)
+	}
+}
+
+// TestMutationDrain checks that draining a module waits for the mutations it has, and stops giving it more
+func TestMutationDrain(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-drain")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	first, second := "123e4567-e89b-12d3-a456-426655440030", "123e4567-e89b-12d3-a456-426655440031"
+	sock, mutate := queueServer(t, dir, first)
+	defer delete(Registry.Mutations, "queuetest")
+
+	api := NewAPIClient("unix:" + sock)
+	c, e := api.MutationInit("test", "queuetest")
+	if e != nil {
+		t.Fatal(e)
+	}
+	mutate(first)
+	me := nextMutation(t, c)
+
+	drained := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		drained <- api.Drain(ctx)
+	}()
+	select {
+	case e := <-drained:
+		t.Fatalf("drain finished before the mutation was done: %v", e)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if me.Context.Err() != nil {
+		t.Fatal("draining interrupted the mutation in progress")
+	}
+	me.Done()
+	select {
+	case e := <-drained:
+		if e != nil {
+			t.Fatal(e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain didn't finish once the mutation was done")
+	}
+
+	// this one stays queued for the next time the module starts
+	mutate(second)
+	select {
+	case v := <-c:
+		t.Fatalf("got a mutation after draining: %v", v.Data())
+	case <-time.After(200 * time.Millisecond):
+	}
+	if p := pending(t, dir); len(p) != 1 || p[0] != second {
+		t.Fatalf("expected only %s to be queued, got: %v", second, p)
+	}
+}
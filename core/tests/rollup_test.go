@@ -0,0 +1,52 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestRollups(t *testing.T) {
+	var cfg, dsc []lib.Node
+	for _, n := range []struct {
+		id    string
+		loc   string
+		state pb.Node_PhysState
+	}{
+		{"123e4567-e89b-12d3-a456-426655440001", "rack1/chassis1", pb.Node_POWER_ON},
+		{"123e4567-e89b-12d3-a456-426655440002", "rack1/chassis2", pb.Node_POWER_OFF},
+		{"123e4567-e89b-12d3-a456-426655440003", "rack2", pb.Node_POWER_ON},
+	} {
+		c := NewNodeWithID(n.id)
+		c.SetValue("/Location", reflect.ValueOf(n.loc))
+		cfg = append(cfg, c)
+		d := NewNodeWithID(n.id)
+		d.SetValue("/PhysState", reflect.ValueOf(n.state))
+		dsc = append(dsc, d)
+	}
+	// a node we haven't discovered anything about
+	cfg = append(cfg, NewNodeWithID("123e4567-e89b-12d3-a456-426655440004"))
+
+	rs := Rollups(cfg, dsc, "")
+	locs := []string{}
+	for _, r := range rs {
+		locs = append(locs, r.Location)
+	}
+	if !reflect.DeepEqual(locs, []string{"", "rack1", "rack1/chassis1", "rack1/chassis2", "rack2"}) {
+		t.Fatalf("wrong rollup locations: %v", locs)
+	}
+	if rs[0].Nodes != 4 || rs[0].PhysState["POWER_ON"] != 2 || rs[0].PhysState["PHYS_UNKNOWN"] != 1 {
+		t.Errorf("wrong cluster rollup: %v", rs[0])
+	}
+	if rs[1].Nodes != 2 || rs[1].PhysState["POWER_ON"] != 1 || rs[1].PhysState["POWER_OFF"] != 1 {
+		t.Errorf("wrong rack1 rollup: %v", rs[1])
+	}
+
+	rs = Rollups(cfg, dsc, "/rack1/")
+	if len(rs) != 3 || rs[0].Location != "rack1" || rs[0].Nodes != 2 {
+		t.Errorf("wrong rollups below rack1: %v", rs)
+	}
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestParseCron(t *testing.T) {
+	c, e := ParseCron("0 22 * * 1-5")
+	if e != nil {
+		t.Fatal(e)
+	}
+	tests := []struct {
+		t     time.Time
+		match bool
+	}{
+		{time.Date(2019, 3, 4, 22, 0, 0, 0, time.Local), true},  // Monday
+		{time.Date(2019, 3, 4, 22, 1, 0, 0, time.Local), false}, // wrong minute
+		{time.Date(2019, 3, 3, 22, 0, 0, 0, time.Local), false}, // Sunday
+	}
+	for _, test := range tests {
+		if c.Match(test.t) != test.match {
+			t.Errorf("%s: expected match to be %t", test.t, test.match)
+		}
+	}
+	if c, _ = ParseCron("*/15 * * * *"); !c.Match(time.Date(2019, 3, 4, 1, 45, 0, 0, time.Local)) {
+		t.Error("*/15 didn't match :45")
+	}
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *"} {
+		if _, e := ParseCron(bad); e == nil {
+			t.Errorf("bad cron expression parsed: %s", bad)
+		}
+	}
+}
+
+func TestValueFromString(t *testing.T) {
+	v, e := ValueFromString(reflect.TypeOf(pb.Node_PHYS_UNKNOWN), "POWER_OFF")
+	if e != nil || v.Interface() != pb.Node_POWER_OFF {
+		t.Errorf("enum: %v, %v", v, e)
+	}
+	v, e = ValueFromString(reflect.TypeOf(true), "true")
+	if e != nil || v.Interface() != true {
+		t.Errorf("bool: %v, %v", v, e)
+	}
+	v, e = ValueFromString(reflect.TypeOf(uint32(0)), "42")
+	if e != nil || v.Interface() != uint32(42) {
+		t.Errorf("uint32: %v, %v", v, e)
+	}
+	if _, e = ValueFromString(reflect.TypeOf(pb.Node_PHYS_UNKNOWN), "NOT_A_STATE"); e == nil {
+		t.Error("bad enum value parsed")
+	}
+}
@@ -0,0 +1,293 @@
+package core
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestStateDifferenceEngine_Transact(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+
+	e := sde.Transact(map[string]reflect.Value{
+		lib.NodeURLJoin(id, "/PhysState"): reflect.ValueOf(pb.Node_POWER_ON),
+		lib.NodeURLJoin(id, "/Platform"):  reflect.ValueOf("powerman"),
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	n, _ := sde.Read(NewNodeID(id))
+	if v, _ := n.GetValue("/PhysState"); v.Interface() != pb.Node_POWER_ON {
+		t.Errorf("transaction didn't set /PhysState: %v", v)
+	}
+	if v, _ := n.GetValue("/Platform"); v.String() != "powerman" {
+		t.Errorf("transaction didn't set /Platform: %v", v)
+	}
+
+	// all or nothing
+	e = sde.Transact(map[string]reflect.Value{
+		lib.NodeURLJoin(id, "/PhysState"):   reflect.ValueOf(pb.Node_POWER_OFF),
+		lib.NodeURLJoin(id, "/NoSuchField"): reflect.ValueOf("x"),
+	})
+	if e == nil {
+		t.Error("expected a bad transaction to fail")
+	}
+	n, _ = sde.Read(NewNodeID(id))
+	if v, _ := n.GetValue("/PhysState"); v.Interface() != pb.Node_POWER_ON {
+		t.Errorf("failed transaction changed /PhysState: %v", v)
+	}
+
+	// slices can't be compared with ==
+	groups := lib.NodeURLJoin(id, "/Groups")
+	for _, g := range [][]string{{"a"}, {"b"}, {"b"}} {
+		if e = sde.Transact(map[string]reflect.Value{groups: reflect.ValueOf(g)}); e != nil {
+			t.Fatalf("transaction of /Groups %v failed: %v", g, e)
+		}
+	}
+	n, _ = sde.Read(NewNodeID(id))
+	if v, _ := n.GetValue("/Groups"); !reflect.DeepEqual(v.Interface(), []string{"b"}) {
+		t.Errorf("transaction didn't set /Groups: %v", v)
+	}
+
+	// setting a value it already has isn't a change, and doesn't fail
+	if e = sde.Transact(map[string]reflect.Value{lib.NodeURLJoin(id, "/Platform"): reflect.ValueOf("powerman")}); e != nil {
+		t.Errorf("transaction of an unchanged value failed: %v", e)
+	}
+}
+
+func TestStateDifferenceEngine_Discover(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+	Registry.Discoverables["sdetest"] = map[string]map[string]reflect.Value{
+		"/PhysState": {
+			"on":  reflect.ValueOf(pb.Node_POWER_ON),
+			"off": reflect.ValueOf(pb.Node_POWER_OFF),
+		},
+	}
+	defer delete(Registry.Discoverables, "sdetest")
+	url := lib.NodeURLJoin(id, "/PhysState")
+	polled := time.Now()
+
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "on"}); e != nil {
+		t.Fatal(e)
+	}
+	// a poll that started before we saw it come on
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "off", Time: polled}); e == nil {
+		t.Error("out of order discovery was accepted")
+	}
+	if v, _ := sde.GetValueDsc(url); v.Interface() != pb.Node_POWER_ON {
+		t.Errorf("out of order discovery changed /PhysState: %v", v)
+	}
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "off", Time: time.Now()}); e != nil {
+		t.Error(e)
+	}
+	if v, _ := sde.GetValueDsc(url); v.Interface() != pb.Node_POWER_OFF {
+		t.Errorf("discovery didn't set /PhysState: %v", v)
+	}
+}
+
+// TestStateDifferenceEngine_DiscoverTimes checks that a time in the future doesn't hold off later discoveries,
+// and that a node's discovery times go with it
+func TestStateDifferenceEngine_DiscoverTimes(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+	Registry.Discoverables["sdetest"] = map[string]map[string]reflect.Value{
+		"/PhysState": {
+			"on":  reflect.ValueOf(pb.Node_POWER_ON),
+			"off": reflect.ValueOf(pb.Node_POWER_OFF),
+		},
+	}
+	defer delete(Registry.Discoverables, "sdetest")
+	url := lib.NodeURLJoin(id, "/PhysState")
+
+	// a module whose clock is an hour fast
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "on", Time: time.Now().Add(time.Hour)}); e != nil {
+		t.Fatal(e)
+	}
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "off", Time: time.Now()}); e != nil {
+		t.Errorf("a discovery from the future held off a later one: %v", e)
+	}
+
+	seen := time.Now()
+	sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "on", Time: seen})
+	sde.DeleteByID(NewNodeID(id))
+	sde.Create(NewNodeWithID(id))
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "off", Time: seen.Add(-time.Second)}); e != nil {
+		t.Errorf("a new node with the same ID got the old one's discovery time: %v", e)
+	}
+}
+
+// TestStateDifferenceEngine_ModuleDiscoverTimes sends discoveries the way a polling module does, through the API,
+// and checks that one from a poll that started before the last accepted discovery is ignored
+func TestStateDifferenceEngine_ModuleDiscoverTimes(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-discover")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "api.sock")
+	l, e := net.Listen("unix", sock)
+	if e != nil {
+		t.Fatal(e)
+	}
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	ctx.RPC.UNIXListener = l
+	ede := NewEventDispatchEngine(ctx)
+	ctx.SubChan = ede.SubscriptionChan()
+	sdqc := make(chan lib.Query)
+	ctx.Query = *NewQueryEngine(sdqc, make(chan lib.Query))
+	sde := NewStateDifferenceEngine(ctx, sdqc)
+	api := NewAPIServer(ctx)
+	api.Subscribe("API", ede.EventChan())
+	go ede.Run()
+	go sde.Run()
+	go api.Run()
+	// the server needs a moment to start
+	time.Sleep(100 * time.Millisecond)
+
+	Registry.Discoverables["discovertest"] = map[string]map[string]reflect.Value{
+		"/PhysState": {
+			"POWER_ON":  reflect.ValueOf(pb.Node_POWER_ON),
+			"POWER_OFF": reflect.ValueOf(pb.Node_POWER_OFF),
+		},
+	}
+	defer delete(Registry.Discoverables, "discovertest")
+	node, other := "123e4567-e89b-12d3-a456-426655440040", "123e4567-e89b-12d3-a456-426655440041"
+	for _, id := range []string{node, other} {
+		if _, e := ctx.Query.Create(NewNodeWithID(id)); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	dchan, e := NewAPIClient("unix:" + sock).DiscoveryInit()
+	if e != nil {
+		t.Fatal(e)
+	}
+	discover := func(id, vid string, at time.Time) {
+		url := lib.NodeURLJoin(id, "/PhysState")
+		dchan <- NewEvent(lib.Event_DISCOVERY, url, &DiscoveryEvent{Module: "discovertest", URL: url, ValueID: vid, Time: at})
+	}
+	physState := func(id string) interface{} {
+		n, e := ctx.Query.ReadDsc(NewNodeID(id))
+		if e != nil {
+			return nil
+		}
+		v, _ := n.GetValue("/PhysState")
+		return v.Interface()
+	}
+	// waitFor waits for id's discovered PhysState to be s
+	waitFor := func(id string, s pb.Node_PhysState) {
+		for i := 0; ; i++ {
+			if physState(id) == s {
+				return
+			}
+			if i > 500 {
+				t.Fatalf("%s was never discovered to be %s", id, s)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	on := time.Now()
+	discover(node, "POWER_ON", on)
+	waitFor(node, pb.Node_POWER_ON)
+	// a poll that was sent before the node was powered on, but answered after
+	discover(node, "POWER_OFF", on.Add(-time.Second))
+	// discoveries are handled in order, so once other's is in, node's has been handled
+	discover(other, "POWER_ON", time.Now())
+	waitFor(other, pb.Node_POWER_ON)
+	if s := physState(node); s != pb.Node_POWER_ON {
+		t.Errorf("an out of date poll undid a newer discovery: %s is %v", node, s)
+	}
+	discover(node, "POWER_OFF", time.Now())
+	waitFor(node, pb.Node_POWER_OFF)
+}
+
+func TestStateDifferenceEngine_ExpireStale(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	ctx.SDE.StaleTTL = map[string]time.Duration{"/PhysState": time.Minute}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+	Registry.Discoverables["sdetest"] = map[string]map[string]reflect.Value{
+		"/PhysState": {"on": reflect.ValueOf(pb.Node_POWER_ON)},
+	}
+	defer delete(Registry.Discoverables, "sdetest")
+	url := lib.NodeURLJoin(id, "/PhysState")
+	seen := time.Now()
+
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "on", Time: seen}); e != nil {
+		t.Fatal(e)
+	}
+	if r := sde.ExpireStale(seen.Add(30 * time.Second)); len(r) != 0 {
+		t.Errorf("fresh value expired: %v", r)
+	}
+	if r := sde.ExpireStale(seen.Add(2 * time.Minute)); len(r) != 1 || r[0] != url {
+		t.Errorf("stale value didn't expire: %v", r)
+	}
+	if v, _ := sde.GetValueDsc(url); v.Interface() != pb.Node_PHYS_UNKNOWN {
+		t.Errorf("stale /PhysState wasn't made unknown: %v", v)
+	}
+}
+
+// TestStateDifferenceEngine_ExpireStaleFrozen checks that only the leader expires values, and values that can't be compared with == expire
+func TestStateDifferenceEngine_ExpireStaleFrozen(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	ctx.SDE.StaleTTL = map[string]time.Duration{"/Groups": time.Minute}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	frozen := true
+	sde.SetFrozen(func() bool { return frozen })
+	id := "123e4567-e89b-12d3-a456-426655440000"
+	sde.Create(NewNodeWithID(id))
+	Registry.Discoverables["sdetest"] = map[string]map[string]reflect.Value{
+		"/Groups": {"rack1": reflect.ValueOf([]string{"rack1"})},
+	}
+	defer delete(Registry.Discoverables, "sdetest")
+	url := lib.NodeURLJoin(id, "/Groups")
+	seen := time.Now()
+
+	if e := sde.Discover(&DiscoveryEvent{Module: "sdetest", URL: url, ValueID: "rack1", Time: seen}); e != nil {
+		t.Fatal(e)
+	}
+	if r := sde.ExpireStale(seen.Add(2 * time.Minute)); len(r) != 0 {
+		t.Errorf("stale value expired on a standby: %v", r)
+	}
+	frozen = false
+	if r := sde.ExpireStale(seen.Add(2 * time.Minute)); len(r) != 1 || r[0] != url {
+		t.Errorf("stale value didn't expire once we lead: %v", r)
+	}
+	if v, _ := sde.GetValueDsc(url); v.Len() != 0 {
+		t.Errorf("stale /Groups wasn't cleared: %v", v)
+	}
+}
+
+func TestStateDifferenceEngine_DeleteAll(t *testing.T) {
+	ctx := Context{Self: NewNodeID("123e4567-e89b-12d3-a456-426655440009")}
+	sde := NewStateDifferenceEngine(ctx, make(chan lib.Query))
+	sde.Create(NewNodeWithID("123e4567-e89b-12d3-a456-426655440000"))
+	if _, e := sde.DeleteAll(); e != nil {
+		t.Fatal(e)
+	}
+	// both states are emptied
+	if ns, _ := sde.ReadAll(); len(ns) != 0 {
+		t.Errorf("expected no cfg nodes, got %d", len(ns))
+	}
+	if ns, _ := sde.ReadAllDsc(); len(ns) != 0 {
+		t.Errorf("expected no dsc nodes, got %d", len(ns))
+	}
+}
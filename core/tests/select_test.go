@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestNodeSelector(t *testing.T) {
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	n.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_OFF))
+	n.SetValue("/Platform", reflect.ValueOf("powerman"))
+	n.SetValue("/MutationCosts/0/Module", reflect.ValueOf("ipmipower"))
+	n.SetValue("/MutationCosts/1/Module", reflect.ValueOf("pipower"))
+
+	for _, c := range []struct {
+		expr  string
+		match bool
+	}{
+		{"", true},
+		{"/PhysState == POWER_OFF", true},
+		{"/PhysState == POWER_OFF && /Platform == powerman", true},
+		{`/PhysState == POWER_OFF && /Platform == "powerman"`, true},
+		{"/PhysState == POWER_ON && /Platform == powerman", false},
+		{"/PhysState == POWER_ON || /Platform == powerman", true},
+		{"/PhysState != POWER_ON", true},
+		{"/MutationCosts/*/Module == pipower", true},
+		{"/MutationCosts/*/Module != pipower", false},
+		{"/NoSuchField == x", false},
+	} {
+		s, e := ParseNodeSelector(c.expr)
+		if e != nil {
+			t.Errorf("%s: %v", c.expr, e)
+			continue
+		}
+		if s.Match(n) != c.match {
+			t.Errorf("%s: expected match to be %v", c.expr, c.match)
+		}
+	}
+
+	if _, e := ParseNodeSelector("/PhysState"); e == nil {
+		t.Error("expected an error parsing a term without a comparison")
+	}
+
+	us := ExpandURL(n, "/MutationCosts/*/Module")
+	if !reflect.DeepEqual(us, []string{"/MutationCosts/0/Module", "/MutationCosts/1/Module"}) {
+		t.Errorf("unexpected expansion: %v", us)
+	}
+	vs := SelectValues(n, []string{"/Platform", "/MutationCosts/*/Module"})
+	if vs["/Platform"] != "powerman" || vs["/MutationCosts/1/Module"] != "pipower" || len(vs) != 3 {
+		t.Errorf("unexpected values: %v", vs)
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	mk := func(id, name string, ps pb.Node_PhysState) lib.Node {
+		n := NewNodeWithID(id)
+		n.SetValue("/Nodename", reflect.ValueOf(name))
+		n.SetValue("/PhysState", reflect.ValueOf(ps))
+		return n
+	}
+	a := mk("123e4567-e89b-12d3-a456-426655440001", "b", pb.Node_POWER_ON)
+	b := mk("123e4567-e89b-12d3-a456-426655440002", "a", pb.Node_POWER_OFF)
+	c := mk("123e4567-e89b-12d3-a456-426655440003", "c", pb.Node_POWER_OFF)
+	for _, tt := range []struct {
+		url    string
+		desc   bool
+		expect []lib.Node
+	}{
+		{"/Nodename", false, []lib.Node{b, a, c}},
+		{"/Nodename", true, []lib.Node{c, a, b}},
+		{"/PhysState", false, []lib.Node{b, c, a}}, // POWER_OFF < POWER_ON, ties by ID
+	} {
+		ns := []lib.Node{c, a, b}
+		SortNodes(ns, tt.url, tt.desc)
+		for i := range ns {
+			if ns[i] != tt.expect[i] {
+				t.Errorf("%s (desc: %v): wrong order at %d", tt.url, tt.desc, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+type depModule struct {
+	name string
+	deps []string
+}
+
+func (m depModule) Name() string           { return m.name }
+func (m depModule) Dependencies() []string { return m.deps }
+
+func TestServiceOrder(t *testing.T) {
+	for _, m := range []depModule{
+		{"test/web", []string{"test/websocket"}},
+		{"test/websocket", []string{"test/http"}},
+		{"test/http", nil},
+		{"test/loop1", []string{"test/loop2"}},
+		{"test/loop2", []string{"test/loop1"}},
+	} {
+		Registry.RegisterModule(m)
+		defer delete(Registry.Modules, m.name)
+	}
+	srvs := []lib.ServiceInstance{
+		NewServiceInstance("web", "test/web", nil, nil),
+		NewServiceInstance("loop1", "test/loop1", nil, nil),
+		NewServiceInstance("websocket", "test/websocket", nil, nil),
+		NewServiceInstance("http", "test/http", nil, nil),
+		NewServiceInstance("loop2", "test/loop2", nil, nil),
+	}
+	ids := []string{}
+	for _, s := range ServiceOrder(srvs) {
+		ids = append(ids, s.ID())
+	}
+	expect := []string{"http", "websocket", "web", "loop1", "loop2"}
+	for i := range expect {
+		if i >= len(ids) || ids[i] != expect[i] {
+			t.Fatalf("wrong service order: %v, expected %v", ids, expect)
+		}
+	}
+}
+
+func TestServiceManager_StopTimeout(t *testing.T) {
+	sm := NewServiceManager("", 100*time.Millisecond)
+	si := NewServiceInstance("sleeper", "test/sleeper", nil, nil)
+	sm.AddService(si)
+	// this never calls in, so it can't be asked to stop; it has to be killed
+	cmd := exec.Command("sleep", "10")
+	if e := cmd.Start(); e != nil {
+		t.Skipf("can't run sleep: %v", e)
+	}
+	si.SetCmd(cmd)
+	si.SetState(lib.Service_RUN)
+	start := time.Now()
+	if e := sm.StopService("sleeper"); e != nil {
+		t.Fatalf("failed to stop service: %v", e)
+	}
+	if d := time.Since(start); d < 100*time.Millisecond || d > 5*time.Second {
+		t.Errorf("service was stopped in %v, expected ~100ms", d)
+	}
+	if si.GetState() != lib.Service_STOP {
+		t.Errorf("service is still running")
+	}
+}
+
+// a failingService runs something that exits right away, rather than ourself
+type failingService struct {
+	*ServiceInstance
+	exe string
+}
+
+func (s failingService) Exe() string { return s.exe }
+
+func TestServiceManager_Restart(t *testing.T) {
+	exe := "/bin/false"
+	if _, e := os.Stat(exe); e != nil {
+		t.Skipf("can't run %s: %v", exe, e)
+	}
+	defer func(limit uint32, backoff time.Duration) {
+		ServiceRestartLimit, ServiceRestartBackoff = limit, backoff
+	}(ServiceRestartLimit, ServiceRestartBackoff)
+	ServiceRestartLimit, ServiceRestartBackoff = 2, 10*time.Millisecond
+
+	sm := NewServiceManager("", 100*time.Millisecond)
+	si := failingService{NewServiceInstance("crasher", "test/crasher", nil, nil), exe}
+	sm.AddService(si)
+	if st := sm.Status(); len(st) != 1 || st[0].Status != pb.ServiceStatus_STOPPED {
+		t.Fatalf("service should be stopped before it's run: %v", st)
+	}
+	if e := sm.RunService("crasher"); e != nil {
+		t.Fatalf("failed to run service: %v", e)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		st := sm.Status()[0]
+		if st.Status == pb.ServiceStatus_CRASHED {
+			if st.Restarts != 2 {
+				t.Errorf("service was restarted %d times, expected 2", st.Restarts)
+			}
+			if st.LastExit != "exit status 1" {
+				t.Errorf("wrong last exit: %s", st.LastExit)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("service was never given up on: %v", st)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if si.State() != lib.Service_ERROR {
+		t.Errorf("crashed service should be in ERROR, is %v", si.State())
+	}
+}
+
+func TestServiceInstance_Discover(t *testing.T) {
+	si := NewServiceInstance("poller", "test/poller", nil, nil)
+	// not called in yet, so this shouldn't block
+	si.Discover([]string{"123e4567-e89b-12d3-a456-426655440000"})
+	c := make(chan lib.ServiceControl, 1)
+	si.SetCtl(c)
+	si.Discover([]string{"123e4567-e89b-12d3-a456-426655440000"})
+	select {
+	case ctl := <-c:
+		if ctl.Command != lib.ServiceControl_DISCOVER || len(ctl.Nodes) != 1 {
+			t.Errorf("wrong control: %v", ctl)
+		}
+	default:
+		t.Fatalf("no control was sent")
+	}
+}
+
+func TestServiceManager_StatusWhileSyncing(t *testing.T) {
+	sm := NewServiceManager("", 100*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			sm.AddService(NewServiceInstance("churn", "test/churn", nil, nil))
+			sm.DelService("churn")
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		// these read the services while they're added and deleted; go test -race catches it if they aren't guarded
+		sm.Status()
+		sm.GetServiceIDs()
+		sm.Service("churn")
+	}
+}
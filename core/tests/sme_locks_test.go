@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// runEngines starts an SDE and a running, thawed SME with module's mutations between POWER_OFF and POWER_ON.
+// Nobody answers the mutations, so they time out, and are retried.
+// State has to be changed with the QueryEngine, so it's changed by the running SDE.
+func runEngines(self, module string, cs ContextSME) (*QueryEngine, *StateMutationEngine) {
+	ctx := Context{Self: NewNodeID(self), SME: cs}
+	ctx.SME.RootSpec = DefaultRootSpec()
+	ede := NewEventDispatchEngine(ctx)
+	ctx.SubChan = ede.SubscriptionChan()
+	sdqc, smqc := make(chan lib.Query), make(chan lib.Query)
+	ctx.Query = *NewQueryEngine(sdqc, smqc)
+	sde := NewStateDifferenceEngine(ctx, sdqc)
+	sme := NewStateMutationEngine(ctx, smqc)
+	for id, m := range map[string][2]pb.Node_PhysState{
+		"uk":  {pb.Node_PHYS_UNKNOWN, pb.Node_POWER_OFF},
+		"on":  {pb.Node_POWER_OFF, pb.Node_POWER_ON},
+		"off": {pb.Node_POWER_ON, pb.Node_POWER_OFF},
+	} {
+		mut := NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {reflect.ValueOf(m[0]), reflect.ValueOf(m[1])},
+			},
+			map[string]reflect.Value{},
+			map[string]reflect.Value{},
+			lib.StateMutationContext_CHILD,
+			time.Millisecond,
+			[3]string{module, "/PhysState", "PHYS_HANG"},
+		)
+		mut.SetRetry(3, time.Millisecond)
+		sme.RegisterMutation(module, id, mut)
+	}
+	sde.Subscribe("SDE", ede.EventChan())
+	sme.Subscribe("SME", ede.EventChan())
+	go ede.Run()
+	go sde.Run()
+	go sme.Run()
+	// the SME only answers queries once it's built its graph
+	ctx.Query.ReadMutationEdges("")
+	sme.Thaw()
+	return &ctx.Query, sme
+}
+
+// addChild adds a node we're the parent of, so we mutate it, discovered as POWER_OFF
+func addChild(q *QueryEngine, self, id string) {
+	n := NewNodeWithID(id)
+	n.SetValue("/ParentId", reflect.ValueOf(NewNodeID(self).Binary()))
+	q.Create(n)
+	d, _ := q.ReadDsc(NewNodeID(id))
+	d.SetValue("/PhysState", reflect.ValueOf(pb.Node_POWER_OFF))
+	q.UpdateDsc(d)
+}
+
+// responsive fails the test if the SME's active mutations can't be locked, i.e. it's deadlocked
+func responsive(t *testing.T, sme *StateMutationEngine) {
+	done := make(chan struct{})
+	go func() {
+		sme.Frozen()
+		sme.Convergence()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("state mutation engine deadlocked")
+	}
+}
+
+// cfgStorm repeatedly changes nodes' configurations, so each change starts a new chain
+// while the last one's timers, retries and hooks are still going
+func cfgStorm(q *QueryEngine, ids []string) {
+	states := []pb.Node_PhysState{pb.Node_POWER_ON, pb.Node_POWER_OFF}
+	for i := 0; i < 400; i++ {
+		for _, id := range ids {
+			q.SetValue(lib.NodeURLJoin(id, "/PhysState"), reflect.ValueOf(states[(i/2)%2]))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// addChildren adds n children of self
+func addChildren(q *QueryEngine, self string, n int) (ids []string) {
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("123e4567-e89b-12d3-a456-4266554401%02d", i)
+		addChild(q, self, id)
+		ids = append(ids, id)
+	}
+	return
+}
+
+// TestStateMutationEngine_RetryDuringCfgUpdate changes nodes' configurations while their mutations are being retried
+func TestStateMutationEngine_RetryDuringCfgUpdate(t *testing.T) {
+	self := "123e4567-e89b-12d3-a456-426655440010"
+	q, sme := runEngines(self, "test", ContextSME{})
+	cfgStorm(q, addChildren(q, self, 20))
+	responsive(t, sme)
+}
+
+// TestStateMutationEngine_HooksDuringCfgUpdate changes nodes' configurations while pre hooks run,
+// and throttled mutations are handed each other's slots
+func TestStateMutationEngine_HooksDuringCfgUpdate(t *testing.T) {
+	self := "123e4567-e89b-12d3-a456-426655440011"
+	Registry.RegisterMutationHook(MutationHook_PRE, "locks", func(ctx context.Context, mutation [2]string, cfg, dsc lib.Node) error {
+		time.Sleep(100 * time.Microsecond)
+		return nil
+	})
+	q, sme := runEngines(self, "locks", ContextSME{Throttles: map[string]int{"locks": 3}})
+	cfgStorm(q, addChildren(q, self, 20))
+	responsive(t, sme)
+}
+
+// TestStateMutationEngine_ReconcileDuringCfgUpdate changes nodes' configurations while they're being reconciled
+func TestStateMutationEngine_ReconcileDuringCfgUpdate(t *testing.T) {
+	self := "123e4567-e89b-12d3-a456-426655440012"
+	q, sme := runEngines(self, "test", ContextSME{Reconcile: time.Millisecond})
+	cfgStorm(q, addChildren(q, self, 20))
+	responsive(t, sme)
+}
+
+// TestStateMutationEngine_ConvergenceDuringCfgUpdate reads convergence while nodes' configurations change
+func TestStateMutationEngine_ConvergenceDuringCfgUpdate(t *testing.T) {
+	self := "123e4567-e89b-12d3-a456-426655440013"
+	q, sme := runEngines(self, "test", ContextSME{})
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				sme.Convergence()
+			}
+		}
+	}()
+	cfgStorm(q, addChildren(q, self, 20))
+	close(stop)
+	responsive(t, sme)
+}
@@ -358,3 +358,49 @@ func TestStateMutationEngine(t *testing.T) {
 	})
 }
 */
+
+func TestStateMutation_Cost(t *testing.T) {
+	m := NewStateMutation(
+		map[string][2]reflect.Value{
+			"/PhysState": {
+				reflect.ValueOf(pb.Node_POWER_OFF),
+				reflect.ValueOf(pb.Node_POWER_ON),
+			},
+		},
+		map[string]reflect.Value{},
+		map[string]reflect.Value{},
+		lib.StateMutationContext_CHILD,
+		time.Second*10,
+		[3]string{"", "", ""},
+	)
+	if m.Cost() != DefaultMutationCost {
+		t.Errorf("default cost incorrect: %d != %d", m.Cost(), DefaultMutationCost)
+	}
+	m.SetCost(10)
+	if m.Cost() != 10 {
+		t.Errorf("SetCost failed: %d != 10", m.Cost())
+	}
+}
+
+func TestStateMutation_Retry(t *testing.T) {
+	m := NewStateMutation(
+		map[string][2]reflect.Value{
+			"/PhysState": {
+				reflect.ValueOf(pb.Node_POWER_OFF),
+				reflect.ValueOf(pb.Node_POWER_ON),
+			},
+		},
+		map[string]reflect.Value{},
+		map[string]reflect.Value{},
+		lib.StateMutationContext_CHILD,
+		time.Second*10,
+		[3]string{"", "", ""},
+	)
+	if m.Retries() != 0 {
+		t.Errorf("default retries incorrect: %d != 0", m.Retries())
+	}
+	m.SetRetry(3, time.Second)
+	if m.Retries() != 3 || m.RetryBackoff() != time.Second {
+		t.Errorf("SetRetry failed: %d, %s", m.Retries(), m.RetryBackoff())
+	}
+}
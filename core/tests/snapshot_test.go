@@ -0,0 +1,44 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+)
+
+func TestSnapshot(t *testing.T) {
+	var ns []lib.Node
+	for i, id := range []string{"123e4567-e89b-12d3-a456-426655440000", "123e4567-e89b-12d3-a456-426655440001"} {
+		n := NewNodeWithID(id)
+		n.SetValue("/Nodename", reflect.ValueOf(fmt.Sprintf("testing%d", i)))
+		ns = append(ns, n)
+	}
+
+	var b bytes.Buffer
+	if e := WriteSnapshot(&b, ns); e != nil {
+		t.Fatal(e)
+	}
+	rs, e := ReadSnapshot(&b)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(rs) != len(ns) {
+		t.Fatalf("expected %d nodes, got %d", len(ns), len(rs))
+	}
+	for i := range ns {
+		if !rs[i].ID().Equal(ns[i].ID()) {
+			t.Errorf("ID mismatch: %s != %s", rs[i].ID().String(), ns[i].ID().String())
+		}
+		if d, _ := ns[i].Diff(rs[i], ""); len(d) != 0 {
+			t.Errorf("node %d changed in snapshot: %v", i, d)
+		}
+	}
+
+	if _, e = ReadSnapshot(bytes.NewBufferString("not a snapshot")); e == nil {
+		t.Error("expected an error reading a bad snapshot")
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+)
+
+func TestFileStateStore(t *testing.T) {
+	dir, e := ioutil.TempDir("", "kraken-store")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	s, e := OpenStateStore("file", dir)
+	if e != nil {
+		t.Fatal(e)
+	}
+	n := NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	n.SetValue("/Nodename", reflect.ValueOf("testing"))
+	if e = s.Save(false, n); e != nil {
+		t.Fatal(e)
+	}
+
+	t.Run("load", func(t *testing.T) {
+		ns, e := s.Load(false)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(ns) != 1 {
+			t.Fatalf("expected 1 node, got %d", len(ns))
+		}
+		if !ns[0].ID().Equal(n.ID()) {
+			t.Errorf("ID mismatch: %s != %s", ns[0].ID().String(), n.ID().String())
+		}
+		v, e := ns[0].GetValue("/Nodename")
+		if e != nil || v.String() != "testing" {
+			t.Errorf("value mismatch: %v, %v", v, e)
+		}
+		if ns, _ = s.Load(true); len(ns) != 0 {
+			t.Errorf("expected no dsc nodes, got %d", len(ns))
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// e.g. we crashed while it was being written
+		if e := ioutil.WriteFile(filepath.Join(dir, "cfg", "123e4567-e89b-12d3-a456-426655440001"), nil, 0600); e != nil {
+			t.Fatal(e)
+		}
+		defer os.Remove(filepath.Join(dir, "cfg", "123e4567-e89b-12d3-a456-426655440001"))
+		ns, e := s.Load(false)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if len(ns) != 1 || !ns[0].ID().Equal(n.ID()) {
+			t.Errorf("expected only %s, got %d nodes", n.ID().String(), len(ns))
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if e := s.Delete(false, n.ID()); e != nil {
+			t.Fatal(e)
+		}
+		if e := s.Delete(false, n.ID()); e != nil {
+			t.Errorf("deleting a missing node should not fail: %v", e)
+		}
+		if ns, _ := s.Load(false); len(ns) != 0 {
+			t.Errorf("expected no nodes after delete, got %d", len(ns))
+		}
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		if _, e := OpenStateStore("nope", dir); e == nil {
+			t.Error("expected an error for an unknown backend")
+		}
+	})
+}
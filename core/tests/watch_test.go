@@ -0,0 +1,38 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/hpc/kraken/core"
+	pb "github.com/hpc/kraken/core/proto"
+)
+
+func TestWatchMatch(t *testing.T) {
+	n := "123e4567-e89b-12d3-a456-426655440000"
+	sce := &StateChangeEvent{Type: StateChange_UPDATE, URL: n + ":/Services/restapi/State"}
+	c := NewStateChange(sce, reflect.ValueOf(pb.ServiceInstance_RUN))
+	if c.Node != n || c.Url != "/Services/restapi/State" || c.Type != "UPDATE" || c.Value != "RUN" {
+		t.Fatalf("unexpected state change: %v", c)
+	}
+	for _, w := range []struct {
+		name  string
+		req   *pb.WatchRequest
+		match bool
+	}{
+		{"everything", &pb.WatchRequest{}, true},
+		{"node", &pb.WatchRequest{Node: n}, true},
+		{"other node", &pb.WatchRequest{Node: "123e4567-e89b-12d3-a456-426655440001"}, false},
+		{"url", &pb.WatchRequest{Url: "/Services/restapi/State"}, true},
+		{"url prefix", &pb.WatchRequest{Url: "/Services/"}, true},
+		{"partial url element", &pb.WatchRequest{Url: "/Serv"}, false},
+		{"value", &pb.WatchRequest{Url: "/Services", Value: "RUN"}, true},
+		{"other value", &pb.WatchRequest{Value: "STOP"}, false},
+		{"types", &pb.WatchRequest{Types: []string{"CFG_UPDATE", "UPDATE"}}, true},
+		{"other types", &pb.WatchRequest{Types: []string{"CFG_UPDATE"}}, false},
+	} {
+		if WatchMatch(w.req, c) != w.match {
+			t.Errorf("%s: expected match to be %v", w.name, w.match)
+		}
+	}
+}
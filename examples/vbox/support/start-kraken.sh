@@ -1,3 +1,3 @@
 #!/bin/bash
 
-/home/vagrant/kraken -log 7 -ip 10.11.12.1 -ipapi 192.168.57.10 >> /home/vagrant/kraken.log 2>&1 &
\ No newline at end of file
+/home/vagrant/kraken -log 7 -ip 10.11.12.1 -ipapi 192.168.57.10 -apinoauth >> /home/vagrant/kraken.log 2>&1 &
\ No newline at end of file
@@ -17,6 +17,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hpc/kraken/core"
 	"github.com/hpc/kraken/lib"
@@ -49,18 +52,43 @@ func main() {
 	idstr := flag.String("id", "123e4567-e89b-12d3-a456-426655440000", "specify a UUID for this node")
 	ip := flag.String("ip", "127.0.0.1", "what is my IP (for communications and listening)")
 	ipapi := flag.String("ipapi", "127.0.0.1", "what IP to use for the ReST API")
-	parent := flag.String("parent", "", "IP adddress of parent")
+	apitokens := flag.String("apitokens", "", "comma separated list of bearer tokens the ReST API accepts")
+	apicert := flag.String("apicert", "", "serve the ReST API over HTTPS with this PEM certificate file (needs -apikey)")
+	apikey := flag.String("apikey", "", "PEM private key file for -apicert")
+	apiclientca := flag.String("apiclientca", "", "require ReST API clients to present a certificate signed by a CA in this PEM file (mTLS)")
+	apinoauth := flag.Bool("apinoauth", false, "let anyone use the ReST API without authenticating (not recommended)")
+	apigraphql := flag.Bool("apigraphql", false, "serve read only GraphQL queries at /graphql on the ReST API")
+	apibasepath := flag.String("apibasepath", "", "serve the ReST API under this path, e.g. /kraken, for a reverse proxy that forwards it")
+	apicors := flag.String("apicors", "", "comma separated list of origins that browsers can make cross-origin ReST API requests from, or * for any (default none)")
+	apiproxies := flag.String("apiproxies", "", "comma separated list of addresses or CIDRs of reverse proxies whose X-Forwarded-* headers the ReST API trusts")
+	apiproxyuser := flag.String("apiproxyuser", "", "header, e.g. X-Forwarded-User, that names the user a trusted proxy authenticated ReST API requests as")
+	parent := flag.String("parent", "", "IP adddress of parent, or a comma separated list of parents to fail over between (in order of preference)")
 	llevel := flag.Int("log", 3, "set the log level (0-9)")
+	rollback := flag.Bool("rollback", false, "if a chain of mutations fails part way, mutate the node back to where it started")
+	store := flag.String("store", "", "persist state across restarts, as backend:location (e.g. file:/var/lib/kraken or etcd:http://etcd:2379/kraken)")
+	reconcile := flag.Duration("reconcile", 0, "how often to check for nodes that have drifted from their configuration and mutate them back (e.g. 5m); 0 disables")
+	archive := flag.String("archive", "", "directory to archive decommissioned nodes in before they're removed")
+	stale := flag.String("stale", "", "revert discovered values to unknown if they aren't rediscovered in time, as a comma separated list of url=ttl (e.g. /PhysState=5m)")
+	throttle := flag.String("throttle", "", "cap concurrently executing mutations, as a comma separated list of module[:mutation]=max")
+	hook := flag.String("hook", "", "run scripts before/after mutations, as a comma separated list of pre|post:module[:mutation]=script")
+	restore := flag.String("restore", "", "replace the configuration state with a snapshot file on startup")
+	ha := flag.Bool("ha", false, "run as one of several redundant head nodes sharing an etcd -store and -id; only the elected leader mutates state")
+	eventqueue := flag.String("eventqueue", "", "queue mutations for modules until they acknowledge them, as backend[:location] (e.g. memory, or file:/var/lib/kraken/queues)")
+	stoptime := flag.Duration("stoptime", core.DefaultStopTimeout, "how long modules get to stop gracefully before they're killed")
+	eventlog := flag.String("eventlog", "", "record events to an event log, as backend:location (e.g. file:/var/log/kraken/events.log)")
+	auditlog := flag.String("auditlog", "", "record who changed what through the APIs to an audit log, as backend:location (e.g. file:/var/log/kraken/audit.log)")
 	flag.Parse()
 
 	parents := []string{}
 	if len(*parent) > 0 {
-		parents = append(parents, *parent)
-		ip := net.ParseIP(*parent)
-		if ip == nil {
-			fmt.Printf("bad parent IP: %s\n", *parent)
-			flag.PrintDefaults()
-			return
+		for _, p := range strings.Split(*parent, ",") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				fmt.Printf("bad parent IP: %s\n", p)
+				flag.PrintDefaults()
+				return
+			}
+			parents = append(parents, p)
 		}
 	}
 
@@ -75,6 +103,99 @@ func main() {
 	discs := make(map[string]map[string]reflect.Value)
 
 	k := core.NewKraken(*idstr, *ip, parents, lib.LoggerLevel(*llevel))
+	if len(*store) > 0 {
+		sp := strings.SplitN(*store, ":", 2)
+		if len(sp) != 2 {
+			fmt.Printf("bad state store: %s\n", *store)
+			flag.PrintDefaults()
+			return
+		}
+		k.Ctx.Store.Backend, k.Ctx.Store.Location = sp[0], sp[1]
+	}
+	k.Ctx.Store.HA = *ha
+	if len(*eventlog) > 0 {
+		sp := strings.SplitN(*eventlog, ":", 2)
+		if len(sp) != 2 {
+			fmt.Printf("bad event log: %s\n", *eventlog)
+			flag.PrintDefaults()
+			return
+		}
+		k.Ctx.EventLog.Backend, k.Ctx.EventLog.Location = sp[0], sp[1]
+	}
+	if len(*auditlog) > 0 {
+		sp := strings.SplitN(*auditlog, ":", 2)
+		if len(sp) != 2 {
+			fmt.Printf("bad audit log: %s\n", *auditlog)
+			flag.PrintDefaults()
+			return
+		}
+		k.Ctx.AuditLog.Backend, k.Ctx.AuditLog.Location = sp[0], sp[1]
+	}
+	if len(*stale) > 0 {
+		k.Ctx.SDE.StaleTTL = make(map[string]time.Duration)
+		for _, s := range strings.Split(*stale, ",") {
+			sp := strings.SplitN(s, "=", 2)
+			var ttl time.Duration
+			if len(sp) == 2 {
+				ttl, e = time.ParseDuration(sp[1])
+			}
+			if len(sp) != 2 || e != nil || ttl <= 0 {
+				fmt.Printf("bad stale TTL: %s\n", s)
+				flag.PrintDefaults()
+				return
+			}
+			k.Ctx.SDE.StaleTTL[sp[0]] = ttl
+		}
+	}
+	if len(*eventqueue) > 0 {
+		sp := strings.SplitN(*eventqueue, ":", 2)
+		k.Ctx.EventQueue.Backend = sp[0]
+		if len(sp) == 2 {
+			k.Ctx.EventQueue.Location = sp[1]
+		}
+	}
+	k.Ctx.StopTime = *stoptime
+	k.Ctx.SME.Rollback = *rollback
+	k.Ctx.SME.Reconcile = *reconcile
+	k.Ctx.SME.Archive = *archive
+	if len(*throttle) > 0 {
+		k.Ctx.SME.Throttles = make(map[string]int)
+		for _, t := range strings.Split(*throttle, ",") {
+			sp := strings.SplitN(t, "=", 2)
+			var max int
+			if len(sp) == 2 {
+				max, e = strconv.Atoi(sp[1])
+			}
+			if len(sp) != 2 || e != nil || max < 1 {
+				fmt.Printf("bad throttle: %s\n", t)
+				flag.PrintDefaults()
+				return
+			}
+			k.Ctx.SME.Throttles[sp[0]] = max
+		}
+	}
+	if len(*hook) > 0 {
+		points := map[string]core.MutationHookPoint{"pre": core.MutationHook_PRE, "post": core.MutationHook_POST}
+		for _, h := range strings.Split(*hook, ",") {
+			sp := strings.SplitN(h, "=", 2)
+			var wm []string
+			if len(sp) == 2 {
+				wm = strings.SplitN(sp[0], ":", 2)
+			}
+			if len(sp) != 2 || len(wm) != 2 {
+				fmt.Printf("bad hook: %s\n", h)
+				flag.PrintDefaults()
+				return
+			}
+			p, ok := points[wm[0]]
+			if !ok {
+				fmt.Printf("bad hook: %s\n", h)
+				flag.PrintDefaults()
+				return
+			}
+			core.Registry.RegisterMutationHook(p, wm[1], core.NewScriptMutationHook(p, sp[1]))
+		}
+	}
 
 	// inject service instances
 	// & declare mutations/discoveries for each
@@ -154,6 +275,22 @@ func main() {
 
 	k.Release()
 	qe := core.NewQueryEngine(k.Sde.QueryChan(), k.Sme.QueryChan())
+	if len(*restore) > 0 {
+		f, e := os.Open(*restore)
+		if e != nil {
+			k.Logf(lib.LLCRITICAL, "could not open snapshot: %v", e)
+			os.Exit(1)
+		}
+		ns, e := core.ReadSnapshot(f)
+		f.Close()
+		if e == nil {
+			_, e = qe.Restore(ns)
+		}
+		if e != nil {
+			k.Logf(lib.LLCRITICAL, "could not restore snapshot: %v", e)
+			os.Exit(1)
+		}
+	}
 	// we'll modify ourselves a bit to get running
 	self, _ := qe.Read(k.Ctx.Self)
 
@@ -161,8 +298,29 @@ func main() {
 	if len(parents) == 0 {
 		restapi := self.GetService("restapi")
 		cfg := &pbr.RestAPIConfig{
-			Addr: *ipapi,
-			Port: 3141,
+			Addr:     *ipapi,
+			Port:     3141,
+			Auth:     &pbr.RestAPIAuth{Disabled: *apinoauth},
+			Graphql:  *apigraphql,
+			BasePath: *apibasepath,
+			Proxy:    &pbr.RestAPIProxy{UserHeader: *apiproxyuser},
+		}
+		if len(*apitokens) > 0 {
+			cfg.Auth.Tokens = strings.Split(*apitokens, ",")
+		}
+		if len(*apicors) > 0 {
+			cfg.CorsOrigins = strings.Split(*apicors, ",")
+		}
+		if len(*apiproxies) > 0 {
+			cfg.Proxy.Trusted = strings.Split(*apiproxies, ",")
+		}
+		if len(*apicert) > 0 || len(*apikey) > 0 {
+			cfg.Tls = &pbr.RestAPITLS{
+				Cert:              *apicert,
+				Key:               *apikey,
+				ClientCa:          *apiclientca,
+				RequireClientCert: len(*apiclientca) > 0,
+			}
 		}
 		any, _ := ptypes.MarshalAny(cfg)
 		restapi.SetState(lib.Service_RUN)
@@ -197,7 +355,8 @@ func main() {
 	// subscribe our listener
 	k.Ctx.SubChan <- sclist
 
-	k.Sme.Thaw()
+	k.Lead()
+	k.Watchdog()
 	// wait forever
 	for {
 		select {
@@ -10,6 +10,7 @@
 package lib
 
 import (
+	"context"
 	"os/exec"
 	"reflect"
 	"time"
@@ -88,6 +89,15 @@ type Node interface {
 	GetServices() []ServiceInstance
 	HasService(id string) bool
 
+	Frozen() bool
+	Priority() pb.Node_Priority
+	Lifecycle() pb.Node_Lifecycle
+
+	AddGroup(group string)
+	DelGroup(group string)
+	GetGroups() []string
+	InGroup(group string) bool
+
 	Diff(node Node, prefix string) (diff []string, e error)
 	MergeDiff(m Node, diff []string) (changes []string, e error)
 	Merge(m Node, prefix string) (changes []string, e error)
@@ -287,21 +297,39 @@ const (
 	Query_MUTATIONNODES
 	Query_MUTATIONEDGES
 	Query_MUTATIONPATH
+	Query_MUTATIONPLAN
+	Query_RESTORE
+	Query_READGROUP
+	Query_UPDATEGROUP
+	Query_SELECT
+	Query_TRANSACT
+	Query_MUTATIONMETRICS
+	Query_ROLLUPS
+	Query_CONVERGENCE
 )
 
 var QueryTypeMap = map[QueryType]QueryEngineType{
-	Query_CREATE:        Query_SDE,
-	Query_READ:          Query_SDE,
-	Query_UPDATE:        Query_SDE,
-	Query_DELETE:        Query_SDE,
-	Query_READALL:       Query_SDE,
-	Query_DELETEALL:     Query_SDE,
-	Query_GETVALUE:      Query_SDE,
-	Query_SETVALUE:      Query_SDE,
-	Query_RESPONSE:      Query_SDE,
-	Query_MUTATIONNODES: Query_SME,
-	Query_MUTATIONEDGES: Query_SME,
-	Query_MUTATIONPATH:  Query_SME,
+	Query_CREATE:          Query_SDE,
+	Query_READ:            Query_SDE,
+	Query_UPDATE:          Query_SDE,
+	Query_DELETE:          Query_SDE,
+	Query_READALL:         Query_SDE,
+	Query_DELETEALL:       Query_SDE,
+	Query_GETVALUE:        Query_SDE,
+	Query_SETVALUE:        Query_SDE,
+	Query_RESPONSE:        Query_SDE,
+	Query_MUTATIONNODES:   Query_SME,
+	Query_MUTATIONEDGES:   Query_SME,
+	Query_MUTATIONPATH:    Query_SME,
+	Query_MUTATIONPLAN:    Query_SME,
+	Query_RESTORE:         Query_SDE,
+	Query_READGROUP:       Query_SDE,
+	Query_UPDATEGROUP:     Query_SDE,
+	Query_SELECT:          Query_SDE,
+	Query_TRANSACT:        Query_SDE,
+	Query_MUTATIONMETRICS: Query_SME,
+	Query_ROLLUPS:         Query_SDE,
+	Query_CONVERGENCE:     Query_SME,
 }
 
 type QueryState uint8
@@ -370,6 +398,9 @@ type StateMutation interface {
 	SpecCompatWithMutators(StateSpec, map[string]uint32) bool
 	Timeout() time.Duration
 	FailTo() [3]string // discover address: module:url:value_id
+	Cost() uint32      // relative cost of the mutation; the cheapest path is taken
+	Retries() uint32   // how many times to retry the mutation after a timeout before failing
+	RetryBackoff() time.Duration
 }
 
 type StateMutationEngine interface {
@@ -448,25 +479,30 @@ const (
 type ServiceControl_Command pb.ServiceControl_Command
 
 const (
-	ServiceControl_STOP   ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_STOP)
-	ServiceControl_UPDATE ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_UPDATE)
-	ServiceControl_INIT   ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_INIT)
+	ServiceControl_STOP     ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_STOP)
+	ServiceControl_UPDATE   ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_UPDATE)
+	ServiceControl_INIT     ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_INIT)
+	ServiceControl_DISCOVER ServiceControl_Command = ServiceControl_Command(pb.ServiceControl_DISCOVER)
 )
 
 type ServiceControl struct {
-	Command ServiceControl_Command
-	Config  *any.Any
+	Command  ServiceControl_Command
+	Config   *any.Any
+	Deadline time.Time // STOP: when the service will be killed if it hasn't exited
+	Nodes    []string  // DISCOVER: IDs of the nodes to re-discover; all of them if empty
 }
 type ServiceInstance interface {
 	ID() string
 	State() ServiceState
 	SetState(ServiceState)
 	GetState() ServiceState // GetState tries to discover the real state on a node running the service
+	Ready() bool            // Ready reports whether the service is running, and has called in to the API
 	Module() string
 	Exe() string // services take two arguments - connect string, and instance ID
 	Cmd() *exec.Cmd
 	SetCmd(*exec.Cmd)
-	Stop()
+	Stop(deadline time.Time) // Stop asks the service to exit by deadline
+	Discover(nodes []string) // Discover asks the service to re-discover nodes now (all of them if nodes is empty)
 	SetCtl(chan<- ServiceControl)
 	Config() *any.Any
 	UpdateConfig(*any.Any)
@@ -486,6 +522,8 @@ type ServiceManager interface {
 	StopService(id string) error
 
 	SyncNode(n Node) map[string]ServiceState
+
+	Status() []*pb.ServiceStatus // how the process of each service is doing
 }
 
 /*
@@ -516,6 +554,14 @@ type ModuleWithConfig interface {
 	ConfigURL() string
 }
 
+// ModuleWithConfigValidation is a module that can check a config before it's applied.
+// Configs changed through the API (e.g. the ReST API's /cfg/module) are checked with ValidateConfig before they're sent to UpdateConfig,
+// so the error can be given to whoever made the change.
+type ModuleWithConfigValidation interface {
+	ModuleWithConfig
+	ValidateConfig(proto.Message) error
+}
+
 type ModuleWithMutations interface {
 	Module
 	SetMutationChan(<-chan Event)
@@ -526,6 +572,30 @@ type ModuleWithDiscovery interface {
 	SetDiscoveryChan(chan<- Event)
 }
 
+// ModuleWithRediscover is a discovering module that can re-discover on demand, rather than waiting for its next poll.
+// Rediscover is called with the IDs of the nodes to re-discover, or none to re-discover everything it would poll.
+type ModuleWithRediscover interface {
+	ModuleWithDiscovery
+	Rediscover(nodes []string)
+}
+
+// ModuleWithDependencies is a module that needs other modules to be running before it can start.
+// A service instance of the module is only started once there is a ready service instance of each of its dependencies.
+type ModuleWithDependencies interface {
+	Module
+	Dependencies() []string // names of the modules we depend on
+}
+
+// ModuleWithShutdown is a module that can stop gracefully.
+// When its service is stopped, Shutdown is called with a context that ends at the deadline the module will be killed at.
+// Shutdown should stop taking new mutations, finish the ones in flight, and return once the module won't send any more discoveries.
+// Modules that take mutations but don't implement this get until the deadline to call Done on the mutations they have.
+// If it returns an error (e.g. ctx.Err()), the module exits without waiting for its discoveries to be sent.
+type ModuleWithShutdown interface {
+	ModuleSelfService
+	Shutdown(ctx context.Context) error
+}
+
 type APIClient interface {
 	Logger
 	Self() NodeID
@@ -542,6 +612,26 @@ type APIClient interface {
 	QueryNodeMutationNodes(string) (pb.MutationNodeList, error)
 	QueryNodeMutationEdges(string) (pb.MutationEdgeList, error)
 	QueryNodeMutationPath(string) (pb.MutationPath, error)
+	QueryMutationPlan(Node) (pb.MutationPath, error)
+	QueryEventLog(string, time.Time, time.Time) ([]*pb.EventRecord, error)
+	QueryAudit([]*pb.AuditRecord) error
+	QueryAuditLog(*pb.AuditLogQuery) ([]*pb.AuditRecord, error)
+	QueryServiceStatus() ([]*pb.ServiceStatus, error)
+	QueryConvergence() ([]*pb.NodeConvergence, error)
+	QueryMutationMetrics() (pb.MutationMetrics, error)
+	QueryDecommission(string, map[string]string, bool) (Node, error)
+	QueryRollups(string) ([]*pb.Rollup, error)
+	QueryDiscover([]string, string) ([]string, error)
 	QueryDeleteAll() ([]Node, error)
+	QueryRestore([]Node) ([]Node, error)
+	QueryReadGroup(string) ([]Node, error)
+	QueryReadGroupDsc(string) ([]Node, error)
+	QueryUpdateGroup(string, Node) ([]Node, error)
+	QueryTransact(map[string]reflect.Value) error
+	QueryTransactDsc(map[string]reflect.Value) error
+	QuerySelect(string) ([]Node, error)
+	QuerySelectDsc(string) ([]Node, error)
+	Watch(*pb.WatchRequest) (<-chan *pb.StateChange, error)
+	WatchContext(context.Context, *pb.WatchRequest) (<-chan *pb.StateChange, error)
 	ServiceInit(string, string) (<-chan ServiceControl, error)
 }
@@ -57,7 +57,8 @@ func diffStruct(a, b reflect.Value, pre string) (r []string, e error) {
 	}
 	for i := 0; i < a.NumField(); i++ {
 		f := a.Type().Field(i)
-		if f.Name == "Extensions" || f.Name == "Services" || f.Name == "Children" || f.Name == "Parents" || strings.HasPrefix(f.Name, "XXX_") {
+		// MutationCosts are only consulted when a path is found, so they don't need state change events
+		if f.Name == "Extensions" || f.Name == "Services" || f.Name == "Children" || f.Name == "Parents" || f.Name == "MutationCosts" || strings.HasPrefix(f.Name, "XXX_") {
 			continue
 		}
 		s, e := diffAny(a.Field(i), b.Field(i), URLPush(pre, f.Name))
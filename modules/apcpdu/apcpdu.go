@@ -0,0 +1,503 @@
+/* apcpdu.go: mutations for nodes powered through APC rack PDU outlets, using the PDU's CLI
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/apcpdu.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = apcpdu.
+ * Nodes are mapped to a PDU and outlet by the value found at NameUrl.
+ */
+
+package apcpdu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/apcpdu/proto"
+)
+
+const (
+	PlatformString string = "apcpdu"
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s", // logging in to the CLI is slow, and we may wait for another session
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+//////////////////
+// APCPDU Object /
+////////////////
+
+// APCPDU provides a power on/off interface to APC rack PDUs through their SSH or telnet CLI
+type APCPDU struct {
+	api        lib.APIClient
+	cfg        *pb.APCPDUConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	locks      map[string]*sync.Mutex // PDU name -> lock; PDUs only allow one CLI session at a time
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*APCPDU)(nil)
+
+// Name returns the FQDN of the module
+func (*APCPDU) Name() string { return "github.com/hpc/kraken/modules/apcpdu" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*APCPDU)(nil)
+var _ lib.ModuleWithConfigValidation = (*APCPDU)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*APCPDU) NewConfig() proto.Message {
+	r := &pb.APCPDUConfig{
+		NameUrl: "/Nodename",
+		Pdus: map[string]*pb.APCPDU{
+			"pdu0": {
+				Host:     "localhost",
+				Protocol: "ssh",
+				Username: "apc",
+				Password: "apc",
+				Prompt:   "apc>",
+				Timeout:  "10s",
+			},
+		},
+		Outlets:         map[string]*pb.APCOutlet{},
+		PollingInterval: "60s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (ap *APCPDU) UpdateConfig(cfg proto.Message) (e error) {
+	if apcfg, ok := cfg.(*pb.APCPDUConfig); ok {
+		ap.cfg = apcfg
+		if ap.pollTicker != nil {
+			ap.pollTicker.Stop()
+			dur, _ := time.ParseDuration(ap.cfg.GetPollingInterval())
+			ap.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, each PDU's host, port, protocol and timeout, and that outlets are on known PDUs
+func (*APCPDU) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.APCPDUConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, pdu := range c.GetPdus() {
+		if pdu.GetHost() == "" {
+			return fmt.Errorf("PDU %s has no host", name)
+		}
+		if pdu.GetPort() < 0 || pdu.GetPort() > 65535 {
+			return fmt.Errorf("PDU %s has an invalid port: %d", name, pdu.GetPort())
+		}
+		if pdu.GetProtocol() != "ssh" && pdu.GetProtocol() != "telnet" {
+			return fmt.Errorf("PDU %s has an unknown protocol: %q", name, pdu.GetProtocol())
+		}
+		if pdu.GetTimeout() != "" {
+			if dur, e := time.ParseDuration(pdu.GetTimeout()); e != nil || dur <= 0 {
+				return fmt.Errorf("PDU %s has an invalid timeout: %q", name, pdu.GetTimeout())
+			}
+		}
+	}
+	for name, o := range c.GetOutlets() {
+		if _, ok := c.GetPdus()[o.GetPdu()]; !ok {
+			return fmt.Errorf("outlet %s is on an unknown PDU: %s", name, o.GetPdu())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*APCPDU) ConfigURL() string {
+	cfg := &pb.APCPDUConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*APCPDU)(nil)
+var _ lib.ModuleWithDiscovery = (*APCPDU)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (ap *APCPDU) SetMutationChan(c <-chan lib.Event) { ap.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (ap *APCPDU) SetDiscoveryChan(c chan<- lib.Event) { ap.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*APCPDU)(nil)
+
+// Entry is the module's executable entrypoint
+func (ap *APCPDU) Entry() {
+	url := lib.NodeURLJoin(ap.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "apcpdu"), "State"))
+	ap.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ap.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(ap.cfg.GetPollingInterval())
+	ap.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-ap.pollTicker.C:
+			go ap.discoverAll()
+			break
+		case m := <-ap.mchan: // mutation request
+			go ap.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (ap *APCPDU) Init(api lib.APIClient) {
+	ap.api = api
+	ap.cfg = ap.NewConfig().(*pb.APCPDUConfig)
+	ap.locks = make(map[string]*sync.Mutex)
+}
+
+// Stop should perform a graceful exit
+func (ap *APCPDU) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (ap *APCPDU) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		ap.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name
+	vs := me.NodeCfg.GetValues([]string{ap.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		ap.api.Logf(lib.LLERROR, "could not get outlet name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[ap.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			ap.outletDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			ap.outletSet(me.Context, name, true, me.NodeCfg.ID())
+		case "ONtoOFF":
+			ap.outletSet(me.Context, name, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			ap.outletSet(me.Context, name, false, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			ap.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which closes its PDU session
+		break
+	}
+}
+
+// outlet resolves a node name to its PDU name and outlet number
+func (ap *APCPDU) outlet(name string) (pdu string, outlet int, e error) {
+	o, ok := ap.cfg.Outlets[name]
+	if !ok {
+		e = fmt.Errorf("no outlet configured for node: %s", name)
+		return
+	}
+	if _, ok = ap.cfg.Pdus[o.Pdu]; !ok {
+		e = fmt.Errorf("node %s references unknown PDU: %s", name, o.Pdu)
+		return
+	}
+	return o.Pdu, int(o.Outlet), nil
+}
+
+// session logs in to a PDU, holding its lock until the session is closed with the returned function.
+// If ctx is canceled, the session's connection is closed, so whatever it's waiting for fails.
+func (ap *APCPDU) session(ctx context.Context, pdu string) (s *apcSession, done func(), e error) {
+	ap.mutex.Lock()
+	l, ok := ap.locks[pdu]
+	if !ok {
+		l = &sync.Mutex{}
+		ap.locks[pdu] = l
+	}
+	ap.mutex.Unlock()
+	l.Lock()
+	// we may have waited a while for the lock
+	if e = ctx.Err(); e != nil {
+		l.Unlock()
+		return nil, nil, e
+	}
+	if s, e = apcLogin(ap.cfg.Pdus[pdu]); e != nil {
+		l.Unlock()
+		return nil, nil, fmt.Errorf("failed to log in to PDU %s: %v", pdu, e)
+	}
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.x.c.Close()
+		case <-stop:
+		}
+	}()
+	return s, func() { close(stop); s.Close(); l.Unlock() }, nil
+}
+
+func (ap *APCPDU) outletDiscover(name string, id lib.NodeID) {
+	pdu, outlet, e := ap.outlet(name)
+	if e != nil {
+		ap.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	s, done, e := ap.session(context.Background(), pdu)
+	if e != nil {
+		ap.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	start := time.Now()
+	st, e := s.Status()
+	done()
+	if e != nil {
+		ap.api.Logf(lib.LLERROR, "failed to read outlet state for %s: %v", name, e)
+		return
+	}
+	ap.report(id, st, outlet, start)
+}
+
+// report emits the state of an outlet from an olStatus result that was read at at
+func (ap *APCPDU) report(id lib.NodeID, st map[int]bool, outlet int, at time.Time) {
+	on, ok := st[outlet]
+	switch {
+	case !ok:
+		ap.discover(id, "PHYS_UNKNOWN", at)
+	case on:
+		ap.discover(id, "POWER_ON", at)
+	default:
+		ap.discover(id, "POWER_OFF", at)
+	}
+}
+
+// outletSet switches a node's outlet, and reports state on success
+// if the mutation is interrupted, we report whatever state the outlet was left in instead
+func (ap *APCPDU) outletSet(ctx context.Context, name string, on bool, id lib.NodeID) {
+	pdu, outlet, e := ap.outlet(name)
+	if e != nil {
+		ap.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	s, done, e := ap.session(ctx, pdu)
+	if e == nil {
+		e = s.Set(outlet, on)
+		done()
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			ap.api.Logf(lib.LLINFO, "interrupted: switching outlet for %s", name)
+			ap.outletDiscover(name, id)
+			return
+		}
+		ap.api.Logf(lib.LLERROR, "failed to switch outlet for %s: %v", name, e)
+		return
+	}
+	if on {
+		ap.discover(id, "POWER_ON", time.Now())
+	} else {
+		ap.discover(id, "POWER_OFF", time.Now())
+	}
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (ap *APCPDU) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ap.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	ap.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// logging in is slow, so we read all outlets of a PDU in one session
+func (ap *APCPDU) discoverAll() {
+	ap.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := ap.api.QueryReadAll()
+	if e != nil {
+		ap.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	type node struct {
+		id     lib.NodeID
+		outlet int
+	}
+	byPDU := make(map[string][]node)
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", ap.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			ap.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete outlet info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		pdu, outlet, e := ap.outlet(vs[ap.cfg.GetNameUrl()].String())
+		if e != nil {
+			ap.api.Logf(lib.LLERROR, "%v", e)
+			continue
+		}
+		byPDU[pdu] = append(byPDU[pdu], node{id: n.ID(), outlet: outlet})
+	}
+	for pdu, nodes := range byPDU {
+		s, done, e := ap.session(context.Background(), pdu)
+		if e != nil {
+			ap.api.Logf(lib.LLERROR, "%v", e)
+			continue
+		}
+		start := time.Now()
+		st, e := s.Status()
+		done()
+		if e != nil {
+			ap.api.Logf(lib.LLERROR, "failed to read outlet state on PDU %s: %v", pdu, e)
+			continue
+		}
+		for _, n := range nodes {
+			ap.report(n.id, st, n.outlet, start)
+		}
+	}
+}
+
+// initialization
+func init() {
+	module := &APCPDU{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/apcpdu/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("apcpdu", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,135 @@
+/* cli.go: drives the APC rack PDU command line interface
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package apcpdu
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/apcpdu/proto"
+)
+
+var (
+	reUser   = regexp.MustCompile(`User Name\s*:`)
+	rePass   = regexp.MustCompile(`Password\s*:`)
+	reResult = regexp.MustCompile(`E(\d{3}):\s*([^\r\n]*)`)
+	// e.g. " 3: Outlet 3: On" or " 3: node003: Off"
+	reStatus = regexp.MustCompile(`(?m)^\s*(\d+):.*:\s*(On|Off)\s*$`)
+)
+
+// apcSession is a logged in CLI session
+type apcSession struct {
+	x      *expecter
+	prompt *regexp.Regexp
+}
+
+// apcLogin connects to a PDU and waits for the CLI prompt
+func apcLogin(pdu *pb.APCPDU) (s *apcSession, e error) {
+	timeout, _ := time.ParseDuration(pdu.Timeout)
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	port := int(pdu.Port)
+	s = &apcSession{prompt: regexp.MustCompile(regexp.QuoteMeta(pdu.Prompt))}
+	switch pdu.Protocol {
+	case "ssh":
+		if port == 0 {
+			port = 22
+		}
+		addr := pdu.Host + ":" + strconv.Itoa(port)
+		if s.x, e = expectSSH(addr, pdu.Username, pdu.Password, pdu.HostKey, timeout); e != nil {
+			return nil, e
+		}
+	case "telnet":
+		if port == 0 {
+			port = 23
+		}
+		addr := pdu.Host + ":" + strconv.Itoa(port)
+		if s.x, e = expectTelnet(addr, timeout); e != nil {
+			return nil, e
+		}
+		// telnet logs in at the CLI
+		if _, _, e = s.x.Expect(reUser); e == nil {
+			if e = s.x.Send(pdu.Username + "\r"); e == nil {
+				if _, _, e = s.x.Expect(rePass); e == nil {
+					e = s.x.Send(pdu.Password + "\r")
+				}
+			}
+		}
+		if e != nil {
+			s.x.Close()
+			return nil, fmt.Errorf("telnet login failed: %v", e)
+		}
+	default:
+		return nil, fmt.Errorf("unknown protocol: %s", pdu.Protocol)
+	}
+	// a failed telnet login asks for the user name again
+	i, _, e := s.x.Expect(s.prompt, reUser)
+	if e != nil || i != 0 {
+		s.x.Close()
+		if e == nil {
+			e = fmt.Errorf("login incorrect")
+		}
+		return nil, fmt.Errorf("waiting for prompt: %v", e)
+	}
+	return
+}
+
+// Command runs a CLI command and returns its output
+// APC commands answer with a result code, anything but E000 is an error
+func (s *apcSession) Command(cmd string) (out string, e error) {
+	if e = s.x.Send(cmd + "\r"); e != nil {
+		return
+	}
+	if _, out, e = s.x.Expect(s.prompt); e != nil {
+		return
+	}
+	m := reResult.FindStringSubmatch(out)
+	if m == nil {
+		return out, fmt.Errorf("%s: no result code in output", cmd)
+	}
+	if m[1] != "000" {
+		return out, fmt.Errorf("%s: E%s: %s", cmd, m[1], strings.TrimSpace(m[2]))
+	}
+	return
+}
+
+// Status returns the state of every outlet, true meaning on
+func (s *apcSession) Status() (st map[int]bool, e error) {
+	out, e := s.Command("olStatus all")
+	if e != nil {
+		return
+	}
+	st = make(map[int]bool)
+	for _, m := range reStatus.FindAllStringSubmatch(out, -1) {
+		o, _ := strconv.Atoi(m[1])
+		st[o] = m[2] == "On"
+	}
+	return
+}
+
+// Set switches an outlet on or off
+func (s *apcSession) Set(outlet int, on bool) (e error) {
+	cmd := "olOff "
+	if on {
+		cmd = "olOn "
+	}
+	_, e = s.Command(cmd + strconv.Itoa(outlet))
+	return
+}
+
+// Close logs out
+func (s *apcSession) Close() {
+	s.x.Send("exit\r")
+	s.x.Close()
+}
@@ -0,0 +1,272 @@
+/* expect.go: a small expect-style layer for driving interactive CLIs over SSH or telnet
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package apcpdu
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// expecter reads a session's output in the background, so Expect can time out
+type expecter struct {
+	w       io.Writer
+	c       io.Closer
+	out     chan []byte
+	done    chan struct{}
+	buf     []byte
+	timeout time.Duration
+}
+
+func newExpecter(r io.Reader, w io.Writer, c io.Closer, timeout time.Duration) *expecter {
+	x := &expecter{
+		w:       w,
+		c:       c,
+		out:     make(chan []byte),
+		done:    make(chan struct{}),
+		timeout: timeout,
+	}
+	go func() {
+		for {
+			b := make([]byte, 1024)
+			n, e := r.Read(b)
+			if n > 0 {
+				select {
+				case x.out <- b[:n]:
+				case <-x.done: // nobody will read it
+					return
+				}
+			}
+			if e != nil {
+				close(x.out)
+				return
+			}
+		}
+	}()
+	return x
+}
+
+// Expect waits for output matching one of res
+// it returns the index of the regexp that matched first in the output, and everything before the match
+func (x *expecter) Expect(res ...*regexp.Regexp) (i int, before string, e error) {
+	timer := time.NewTimer(x.timeout)
+	defer timer.Stop()
+	for {
+		i, start, end := -1, len(x.buf), 0
+		for j, re := range res {
+			if m := re.FindIndex(x.buf); m != nil && m[0] < start {
+				i, start, end = j, m[0], m[1]
+			}
+		}
+		if i >= 0 {
+			before = string(x.buf[:start])
+			x.buf = x.buf[end:]
+			return i, before, nil
+		}
+		select {
+		case b, ok := <-x.out:
+			if !ok {
+				return -1, string(x.buf), io.EOF
+			}
+			x.buf = append(x.buf, b...)
+		case <-timer.C:
+			return -1, string(x.buf), fmt.Errorf("timed out waiting for %v", res)
+		}
+	}
+}
+
+// Send writes s to the session
+func (x *expecter) Send(s string) (e error) {
+	_, e = io.WriteString(x.w, s)
+	return
+}
+
+// Close ends the session
+func (x *expecter) Close() error {
+	close(x.done)
+	return x.c.Close()
+}
+
+////////////
+// SSH    /
+//////////
+
+// sshCloser closes both the session and its client
+type sshCloser struct {
+	s *ssh.Session
+	c *ssh.Client
+}
+
+func (sc *sshCloser) Close() error {
+	sc.s.Close()
+	return sc.c.Close()
+}
+
+// expectSSH opens an interactive shell over SSH
+// hostKey is in authorized_keys format; if it is empty the host key isn't checked
+func expectSSH(addr, user, pass, hostKey string, timeout time.Duration) (x *expecter, e error) {
+	hkc := ssh.InsecureIgnoreHostKey()
+	if hostKey != "" {
+		var k ssh.PublicKey
+		if k, _, _, _, e = ssh.ParseAuthorizedKey([]byte(hostKey)); e != nil {
+			return nil, fmt.Errorf("invalid host key: %v", e)
+		}
+		hkc = ssh.FixedHostKey(k)
+	}
+	cfg := &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(pass),
+			// some PDU firmware only offers keyboard-interactive
+			ssh.KeyboardInteractive(func(_, _ string, qs []string, _ []bool) ([]string, error) {
+				a := make([]string, len(qs))
+				for i := range a {
+					a[i] = pass
+				}
+				return a, nil
+			}),
+		},
+		HostKeyCallback: hkc,
+		Timeout:         timeout,
+	}
+	c, e := ssh.Dial("tcp", addr, cfg)
+	if e != nil {
+		return
+	}
+	s, e := c.NewSession()
+	if e != nil {
+		c.Close()
+		return
+	}
+	w, e := s.StdinPipe()
+	if e != nil {
+		s.Close()
+		c.Close()
+		return
+	}
+	r, e := s.StdoutPipe()
+	if e != nil {
+		s.Close()
+		c.Close()
+		return
+	}
+	if e = s.RequestPty("vt100", 24, 200, ssh.TerminalModes{ssh.ECHO: 0}); e == nil {
+		e = s.Shell()
+	}
+	if e != nil {
+		s.Close()
+		c.Close()
+		return
+	}
+	return newExpecter(r, w, &sshCloser{s: s, c: c}, timeout), nil
+}
+
+//////////////
+// Telnet   /
+////////////
+
+// telnet protocol bytes
+const (
+	tnSE   byte = 240
+	tnSB   byte = 250
+	tnWILL byte = 251
+	tnWONT byte = 252
+	tnDO   byte = 253
+	tnDONT byte = 254
+	tnIAC  byte = 255
+
+	tnOptEcho byte = 1
+	tnOptSGA  byte = 3
+)
+
+// telnetConn strips telnet negotiation from what it reads, and refuses every option
+// except letting the server echo and suppress go-ahead
+type telnetConn struct {
+	net.Conn
+	state int // 0: data, 1: IAC, 2: command, 3: subnegotiation, 4: IAC in subnegotiation
+	cmd   byte
+}
+
+func (t *telnetConn) Read(b []byte) (n int, e error) {
+	raw := make([]byte, len(b))
+	for n == 0 && e == nil {
+		var m int
+		m, e = t.Conn.Read(raw)
+		for _, c := range raw[:m] {
+			switch t.state {
+			case 0:
+				if c == tnIAC {
+					t.state = 1
+				} else {
+					b[n] = c
+					n++
+				}
+			case 1:
+				switch c {
+				case tnIAC: // escaped 255
+					b[n] = c
+					n++
+					t.state = 0
+				case tnWILL, tnWONT, tnDO, tnDONT:
+					t.cmd = c
+					t.state = 2
+				case tnSB:
+					t.state = 3
+				default:
+					t.state = 0
+				}
+			case 2:
+				t.reply(t.cmd, c)
+				t.state = 0
+			case 3:
+				if c == tnIAC {
+					t.state = 4
+				}
+			case 4:
+				if c == tnSE {
+					t.state = 0
+				} else {
+					t.state = 3
+				}
+			}
+		}
+	}
+	return
+}
+
+func (t *telnetConn) reply(cmd, opt byte) {
+	var r byte
+	switch cmd {
+	case tnWILL:
+		r = tnDONT
+		if opt == tnOptEcho || opt == tnOptSGA {
+			r = tnDO
+		}
+	case tnDO:
+		r = tnWONT
+	default: // WONT and DONT need no answer
+		return
+	}
+	t.Conn.Write([]byte{tnIAC, r, opt})
+}
+
+// expectTelnet opens a telnet session
+func expectTelnet(addr string, timeout time.Duration) (x *expecter, e error) {
+	c, e := net.DialTimeout("tcp", addr, timeout)
+	if e != nil {
+		return
+	}
+	t := &telnetConn{Conn: c}
+	return newExpecter(t, t, t, timeout), nil
+}
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: apcpdu.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type APCPDUConfig struct {
+	Pdus                 map[string]*APCPDU    `protobuf:"bytes,1,rep,name=pdus,proto3" json:"pdus,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Outlets              map[string]*APCOutlet `protobuf:"bytes,2,rep,name=outlets,proto3" json:"outlets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                `protobuf:"bytes,3,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                `protobuf:"bytes,4,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *APCPDUConfig) Reset()         { *m = APCPDUConfig{} }
+func (m *APCPDUConfig) String() string { return proto.CompactTextString(m) }
+func (*APCPDUConfig) ProtoMessage()    {}
+func (*APCPDUConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_apcpdu_09badc1c873d7d2a, []int{0}
+}
+func (m *APCPDUConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APCPDUConfig.Unmarshal(m, b)
+}
+func (m *APCPDUConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APCPDUConfig.Marshal(b, m, deterministic)
+}
+func (dst *APCPDUConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APCPDUConfig.Merge(dst, src)
+}
+func (m *APCPDUConfig) XXX_Size() int {
+	return xxx_messageInfo_APCPDUConfig.Size(m)
+}
+func (m *APCPDUConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_APCPDUConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APCPDUConfig proto.InternalMessageInfo
+
+func (m *APCPDUConfig) GetPdus() map[string]*APCPDU {
+	if m != nil {
+		return m.Pdus
+	}
+	return nil
+}
+
+func (m *APCPDUConfig) GetOutlets() map[string]*APCOutlet {
+	if m != nil {
+		return m.Outlets
+	}
+	return nil
+}
+
+func (m *APCPDUConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *APCPDUConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type APCPDU struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Protocol             string   `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	HostKey              string   `protobuf:"bytes,6,opt,name=host_key,json=hostKey,proto3" json:"host_key,omitempty"`
+	Prompt               string   `protobuf:"bytes,7,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	Timeout              string   `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APCPDU) Reset()         { *m = APCPDU{} }
+func (m *APCPDU) String() string { return proto.CompactTextString(m) }
+func (*APCPDU) ProtoMessage()    {}
+func (*APCPDU) Descriptor() ([]byte, []int) {
+	return fileDescriptor_apcpdu_09badc1c873d7d2a, []int{1}
+}
+func (m *APCPDU) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APCPDU.Unmarshal(m, b)
+}
+func (m *APCPDU) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APCPDU.Marshal(b, m, deterministic)
+}
+func (dst *APCPDU) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APCPDU.Merge(dst, src)
+}
+func (m *APCPDU) XXX_Size() int {
+	return xxx_messageInfo_APCPDU.Size(m)
+}
+func (m *APCPDU) XXX_DiscardUnknown() {
+	xxx_messageInfo_APCPDU.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APCPDU proto.InternalMessageInfo
+
+func (m *APCPDU) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *APCPDU) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *APCPDU) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *APCPDU) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *APCPDU) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *APCPDU) GetHostKey() string {
+	if m != nil {
+		return m.HostKey
+	}
+	return ""
+}
+
+func (m *APCPDU) GetPrompt() string {
+	if m != nil {
+		return m.Prompt
+	}
+	return ""
+}
+
+func (m *APCPDU) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+type APCOutlet struct {
+	Pdu                  string   `protobuf:"bytes,1,opt,name=pdu,proto3" json:"pdu,omitempty"`
+	Outlet               int32    `protobuf:"varint,2,opt,name=outlet,proto3" json:"outlet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APCOutlet) Reset()         { *m = APCOutlet{} }
+func (m *APCOutlet) String() string { return proto.CompactTextString(m) }
+func (*APCOutlet) ProtoMessage()    {}
+func (*APCOutlet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_apcpdu_09badc1c873d7d2a, []int{2}
+}
+func (m *APCOutlet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APCOutlet.Unmarshal(m, b)
+}
+func (m *APCOutlet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APCOutlet.Marshal(b, m, deterministic)
+}
+func (dst *APCOutlet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APCOutlet.Merge(dst, src)
+}
+func (m *APCOutlet) XXX_Size() int {
+	return xxx_messageInfo_APCOutlet.Size(m)
+}
+func (m *APCOutlet) XXX_DiscardUnknown() {
+	xxx_messageInfo_APCOutlet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APCOutlet proto.InternalMessageInfo
+
+func (m *APCOutlet) GetPdu() string {
+	if m != nil {
+		return m.Pdu
+	}
+	return ""
+}
+
+func (m *APCOutlet) GetOutlet() int32 {
+	if m != nil {
+		return m.Outlet
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*APCPDUConfig)(nil), "proto.APCPDUConfig")
+	proto.RegisterMapType((map[string]*APCPDU)(nil), "proto.APCPDUConfig.PdusEntry")
+	proto.RegisterMapType((map[string]*APCOutlet)(nil), "proto.APCPDUConfig.OutletsEntry")
+	proto.RegisterType((*APCPDU)(nil), "proto.APCPDU")
+	proto.RegisterType((*APCOutlet)(nil), "proto.APCOutlet")
+}
+
+func init() { proto.RegisterFile("apcpdu.proto", fileDescriptor_apcpdu_09badc1c873d7d2a) }
+
+var fileDescriptor_apcpdu_09badc1c873d7d2a = []byte{
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0x5f, 0x4b, 0x3a, 0x41,
+	0x14, 0x65, 0x57, 0x77, 0x57, 0xaf, 0xfe, 0xf8, 0xc9, 0x3c, 0xc4, 0x24, 0x04, 0x8b, 0x41, 0xd8,
+	0x8b, 0x90, 0x11, 0x84, 0x6f, 0x61, 0x05, 0x51, 0x90, 0x2c, 0xf8, 0x2c, 0x9b, 0x3b, 0xd9, 0xd2,
+	0xb8, 0x33, 0xcc, 0x1f, 0xc3, 0x8f, 0xd9, 0x63, 0xdf, 0x26, 0xe6, 0x8f, 0x9b, 0x82, 0x4f, 0x7b,
+	0xcf, 0xbd, 0xe7, 0x9c, 0xb9, 0xe7, 0x2e, 0x74, 0x73, 0xbe, 0xe4, 0x85, 0x1e, 0x71, 0xc1, 0x14,
+	0x43, 0x91, 0xfd, 0x0c, 0x7e, 0x42, 0xe8, 0xde, 0xcd, 0xa6, 0xb3, 0xfb, 0xf9, 0x94, 0x55, 0xef,
+	0xe5, 0x0a, 0x5d, 0x41, 0x93, 0x17, 0x5a, 0xe2, 0x20, 0x6d, 0x0c, 0x3b, 0xe3, 0x33, 0xc7, 0x1e,
+	0xed, 0x53, 0x46, 0xb3, 0x42, 0xcb, 0x87, 0x4a, 0x89, 0x6d, 0x66, 0xa9, 0x68, 0x02, 0x09, 0xd3,
+	0x8a, 0x12, 0x25, 0x71, 0x68, 0x55, 0xe9, 0x31, 0xd5, 0xab, 0xa3, 0x38, 0xe1, 0x4e, 0x80, 0x2e,
+	0xa1, 0xc7, 0x19, 0xa5, 0x65, 0xb5, 0x5a, 0x94, 0x95, 0x22, 0x62, 0x93, 0x53, 0xdc, 0x48, 0x83,
+	0x61, 0x3b, 0xfb, 0xef, 0xfb, 0x4f, 0xbe, 0x8d, 0x4e, 0xa1, 0x55, 0xe5, 0x6b, 0xb2, 0xd0, 0x82,
+	0xe2, 0xa6, 0xa5, 0x24, 0x06, 0xcf, 0x05, 0xed, 0x3f, 0x42, 0xbb, 0x5e, 0x0a, 0xf5, 0xa0, 0xf1,
+	0x49, 0xb6, 0x38, 0xb0, 0x14, 0x53, 0xa2, 0x73, 0x88, 0x36, 0x39, 0xd5, 0x04, 0x87, 0x69, 0x30,
+	0xec, 0x8c, 0xff, 0x1d, 0xac, 0x97, 0xb9, 0xd9, 0x24, 0xbc, 0x0d, 0xfa, 0x2f, 0xd0, 0xdd, 0x5f,
+	0xf3, 0x88, 0xd5, 0xc5, 0xa1, 0x55, 0xef, 0xcf, 0xca, 0x09, 0xf7, 0xdc, 0x06, 0xdf, 0x01, 0xc4,
+	0xee, 0x0d, 0x84, 0xa0, 0xf9, 0xc1, 0xa4, 0xf2, 0x4e, 0xb6, 0x36, 0x3d, 0xce, 0x84, 0xb2, 0x4e,
+	0x51, 0x66, 0x6b, 0xd4, 0x87, 0x96, 0x35, 0x5c, 0xb2, 0xdd, 0x19, 0x6a, 0x6c, 0x66, 0x5a, 0x12,
+	0x61, 0x32, 0xfb, 0xfc, 0x35, 0xb6, 0xba, 0x5c, 0xca, 0x2f, 0x26, 0x0a, 0x1c, 0x79, 0x9d, 0xc7,
+	0xe6, 0x6e, 0xe6, 0xbd, 0x85, 0x49, 0x12, 0xbb, 0xbb, 0x19, 0xfc, 0x4c, 0xb6, 0xe8, 0x04, 0x62,
+	0x2e, 0xd8, 0x9a, 0x2b, 0x9c, 0xd8, 0x81, 0x47, 0x08, 0x43, 0xa2, 0xca, 0x35, 0x61, 0x5a, 0xe1,
+	0x96, 0x53, 0x78, 0x38, 0xb8, 0x81, 0x76, 0x9d, 0xd5, 0x9c, 0x87, 0x17, 0x7a, 0x77, 0x1e, 0x5e,
+	0x68, 0x63, 0xe8, 0xfe, 0xac, 0x4f, 0xe5, 0xd1, 0x5b, 0x6c, 0x53, 0x5c, 0xff, 0x0e, 0x00, 0xb5,
+	0x9e, 0xc5, 0xd9, 0x84, 0x02, 0x00, 0x00,
+}
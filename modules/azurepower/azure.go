@@ -0,0 +1,202 @@
+/* azure.go: a minimal client for Azure Resource Manager virtual machine power control
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package azurepower
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ARMAPI      string = "https://management.azure.com"
+	ARMScope    string = "https://management.azure.com/.default"
+	LoginAPI    string = "https://login.microsoftonline.com"
+	ComputeAPIV string = "2023-03-01"
+)
+
+type azureClient struct {
+	tenant  string
+	id      string
+	secret  string
+	sub     string
+	client  *http.Client
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newAzureClient(tenant, id, secret, sub string) *azureClient {
+	return &azureClient{
+		tenant: tenant,
+		id:     id,
+		secret: secret,
+		sub:    sub,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// getToken returns a valid access token, using the client credentials grant if needed
+func (c *azureClient) getToken() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.token != "" && time.Now().Add(time.Minute).Before(c.expires) {
+		return c.token, nil
+	}
+	v := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.id},
+		"client_secret": {c.secret},
+		"scope":         {ARMScope},
+	}
+	resp, e := c.client.PostForm(LoginAPI+"/"+url.PathEscape(c.tenant)+"/oauth2/v2.0/token", v)
+	if e != nil {
+		return "", e
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	var r struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error_description"`
+	}
+	json.Unmarshal(rb, &r)
+	if resp.StatusCode != 200 {
+		if r.Error != "" {
+			return "", fmt.Errorf("token request failed: %s", r.Error)
+		}
+		return "", fmt.Errorf("token request failed: HTTP %v", resp.StatusCode)
+	}
+	c.token = r.AccessToken
+	c.expires = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// do makes an authenticated request
+// u is either relative to the subscription, or an absolute URL (e.g. a nextLink)
+// the request is abandoned if ctx is canceled
+func (c *azureClient) do(ctx context.Context, method, u string, r interface{}) (e error) {
+	tok, e := c.getToken()
+	if e != nil {
+		return
+	}
+	if !strings.HasPrefix(u, "https://") {
+		u = ARMAPI + "/subscriptions/" + url.PathEscape(c.sub) + u
+	}
+	req, e := http.NewRequest(method, u, nil)
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	// power actions are asynchronous and answer 202
+	if resp.StatusCode != 200 && resp.StatusCode != 202 {
+		var ae struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(rb, &ae) == nil && ae.Error.Code != "" {
+			return fmt.Errorf("%s: %s", ae.Error.Code, ae.Error.Message)
+		}
+		return fmt.Errorf("%s %s: HTTP %v", method, u, resp.StatusCode)
+	}
+	if r != nil && len(rb) > 0 {
+		return json.Unmarshal(rb, r)
+	}
+	return
+}
+
+func vmPath(rg, name string) string {
+	return "/resourceGroups/" + url.PathEscape(rg) +
+		"/providers/Microsoft.Compute/virtualMachines/" + url.PathEscape(name)
+}
+
+// Action runs a power action on a VM: start, powerOff or deallocate
+// skipShutdown makes powerOff skip the graceful guest shutdown
+func (c *azureClient) Action(ctx context.Context, rg, name, action string, skipShutdown bool) error {
+	q := "?api-version=" + ComputeAPIV
+	if action == "powerOff" && skipShutdown {
+		q += "&skipShutdown=true"
+	}
+	return c.do(ctx, http.MethodPost, vmPath(rg, name)+"/"+action+q, nil)
+}
+
+type azureStatus struct {
+	Code string `json:"code"`
+}
+
+// powerState picks the PowerState/... code out of an instance view's statuses
+func powerState(ss []azureStatus) string {
+	for _, s := range ss {
+		if strings.HasPrefix(s.Code, "PowerState/") {
+			return strings.TrimPrefix(s.Code, "PowerState/")
+		}
+	}
+	return ""
+}
+
+// Status returns a VM's power state from its instance view, e.g. running or deallocated
+func (c *azureClient) Status(ctx context.Context, rg, name string) (string, error) {
+	var r struct {
+		Statuses []azureStatus `json:"statuses"`
+	}
+	if e := c.do(ctx, http.MethodGet, vmPath(rg, name)+"/instanceView?api-version="+ComputeAPIV, &r); e != nil {
+		return "", e
+	}
+	return powerState(r.Statuses), nil
+}
+
+// StatusAll returns the power state of every VM in the subscription
+// keys are "resourcegroup/name" in lower case, since Azure resource names are case-insensitive
+func (c *azureClient) StatusAll() (map[string]string, error) {
+	st := make(map[string]string)
+	u := "/providers/Microsoft.Compute/virtualMachines?api-version=" + ComputeAPIV + "&statusOnly=true"
+	for u != "" {
+		var r struct {
+			Value []struct {
+				ID         string `json:"id"`
+				Name       string `json:"name"`
+				Properties struct {
+					InstanceView struct {
+						Statuses []azureStatus `json:"statuses"`
+					} `json:"instanceView"`
+				} `json:"properties"`
+			} `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if e := c.do(context.Background(), http.MethodGet, u, &r); e != nil {
+			return nil, e
+		}
+		for _, v := range r.Value {
+			// IDs look like /subscriptions/<sub>/resourceGroups/<rg>/providers/...
+			p := strings.Split(v.ID, "/")
+			if len(p) < 5 {
+				continue
+			}
+			st[strings.ToLower(p[4]+"/"+v.Name)] = powerState(v.Properties.InstanceView.Statuses)
+		}
+		u = r.NextLink
+	}
+	return st, nil
+}
@@ -0,0 +1,452 @@
+/* azurepower.go: mutations for Azure virtual machines using the Resource Manager API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/azurepower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = azure.
+ */
+
+package azurepower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/azurepower/proto"
+)
+
+const (
+	PlatformString string = "azure"
+
+	// transitionWait is how long we poll a VM for its target state, it matches the mutation timeouts
+	transitionWait = 300 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "300s", // Azure VMs often take a few minutes to change state
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "300s", // deallocating is usually the slowest
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "300s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+//////////////////
+// Azure Object /
+////////////////
+
+// Azure provides a power on/off/deallocate interface to Azure virtual machines
+type Azure struct {
+	api        lib.APIClient
+	cfg        *pb.AzureConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	azure      *azureClient
+	azureCfg   *pb.AzureConfig // the config azure was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Azure)(nil)
+
+// Name returns the FQDN of the module
+func (*Azure) Name() string { return "github.com/hpc/kraken/modules/azurepower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Azure)(nil)
+var _ lib.ModuleWithConfigValidation = (*Azure)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Azure) NewConfig() proto.Message {
+	r := &pb.AzureConfig{
+		Vms:             map[string]*pb.AzureVM{},
+		NameUrl:         "/Nodename",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (az *Azure) UpdateConfig(cfg proto.Message) (e error) {
+	if acfg, ok := cfg.(*pb.AzureConfig); ok {
+		az.cfg = acfg
+		if az.pollTicker != nil {
+			az.pollTicker.Stop()
+			dur, _ := time.ParseDuration(az.cfg.GetPollingInterval())
+			az.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig makes sure VMs are polled on a positive interval
+func (*Azure) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.AzureConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Azure) ConfigURL() string {
+	cfg := &pb.AzureConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Azure)(nil)
+var _ lib.ModuleWithDiscovery = (*Azure)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (az *Azure) SetMutationChan(c <-chan lib.Event) { az.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (az *Azure) SetDiscoveryChan(c chan<- lib.Event) { az.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Azure)(nil)
+
+// Entry is the module's executable entrypoint
+func (az *Azure) Entry() {
+	url := lib.NodeURLJoin(az.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "azurepower"), "State"))
+	az.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  az.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(az.cfg.GetPollingInterval())
+	az.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-az.pollTicker.C:
+			go az.discoverAll()
+			break
+		case m := <-az.mchan: // mutation request
+			go az.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (az *Azure) Init(api lib.APIClient) {
+	az.api = api
+	az.cfg = az.NewConfig().(*pb.AzureConfig)
+}
+
+// Stop should perform a graceful exit
+func (az *Azure) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (az *Azure) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		az.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	vm, ok := az.vm(me.NodeCfg)
+	if !ok {
+		az.api.Logf(lib.LLERROR, "no Azure VM configured for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			az.vmDiscover(vm, me.NodeCfg.ID())
+		case "OFFtoON":
+			az.vmControl(me.Context, vm, "start", false, me.NodeCfg.ID())
+		case "ONtoOFF":
+			if az.cfg.GetDeallocate() {
+				az.vmControl(me.Context, vm, "deallocate", false, me.NodeCfg.ID())
+			} else {
+				az.vmControl(me.Context, vm, "powerOff", false, me.NodeCfg.ID())
+			}
+		case "HANGtoOFF":
+			az.vmControl(me.Context, vm, "powerOff", true, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			az.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// vm finds the configured VM for a node by its name_url value
+func (az *Azure) vm(n lib.Node) (*pb.AzureVM, bool) {
+	v, e := n.GetValue(az.cfg.GetNameUrl())
+	if e != nil {
+		return nil, false
+	}
+	vm, ok := az.cfg.GetVms()[v.String()]
+	if !ok || vm.GetResourceGroup() == "" || vm.GetName() == "" {
+		return nil, false
+	}
+	return vm, true
+}
+
+// client returns the Azure client, creating it if the config has changed
+func (az *Azure) client() *azureClient {
+	az.mutex.Lock()
+	defer az.mutex.Unlock()
+	if az.azure == nil || az.azureCfg != az.cfg {
+		az.azure = newAzureClient(az.cfg.GetTenantId(), az.cfg.GetClientId(), az.cfg.GetClientSecret(), az.cfg.GetSubscriptionId())
+		az.azureCfg = az.cfg
+	}
+	return az.azure
+}
+
+// vmState maps an Azure power state to a PhysState value ID
+// a deallocated VM is off as far as we're concerned
+func vmState(s string) string {
+	switch s {
+	case "running":
+		return "POWER_ON"
+	case "stopped", "deallocated":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (az *Azure) vmDiscover(vm *pb.AzureVM, id lib.NodeID) {
+	start := time.Now()
+	s, e := az.client().Status(context.Background(), vm.GetResourceGroup(), vm.GetName())
+	if e != nil {
+		az.api.Logf(lib.LLERROR, "failed to get power state of VM %s: %v", vm.GetName(), e)
+		return
+	}
+	az.discover(id, vmState(s), start)
+}
+
+// vmControl runs a power action on a VM, and waits for it to get where it's going
+// if the mutation is interrupted, we report whatever state the VM was left in instead
+func (az *Azure) vmControl(ctx context.Context, vm *pb.AzureVM, action string, skipShutdown bool, id lib.NodeID) {
+	c := az.client()
+	if e := c.Action(ctx, vm.GetResourceGroup(), vm.GetName(), action, skipShutdown); e != nil {
+		if ctx.Err() != nil {
+			az.api.Logf(lib.LLINFO, "interrupted: %s of VM %s", action, vm.GetName())
+			az.vmDiscover(vm, id)
+			return
+		}
+		az.api.Logf(lib.LLERROR, "failed to %s VM %s: %v", action, vm.GetName(), e)
+		return
+	}
+	target := map[string]string{
+		"start":      "running",
+		"powerOff":   "stopped",
+		"deallocate": "deallocated",
+	}[action]
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			az.api.Logf(lib.LLINFO, "interrupted: waiting for VM %s to reach %s", vm.GetName(), target)
+			az.vmDiscover(vm, id)
+			return
+		}
+		at := time.Now()
+		s, e := c.Status(ctx, vm.GetResourceGroup(), vm.GetName())
+		if e != nil {
+			az.api.Logf(lib.LLDEBUG, "failed to get power state of VM %s: %v", vm.GetName(), e)
+			continue
+		}
+		if s == target {
+			az.discover(id, vmState(s), at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	az.api.Logf(lib.LLERROR, "VM %s did not reach %s after %s", vm.GetName(), target, transitionWait.String())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (az *Azure) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  az.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	az.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// one list call gets the power state of every VM in the subscription
+func (az *Azure) discoverAll() {
+	az.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := az.api.QueryReadAll()
+	if e != nil {
+		az.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	vms := make(map[string]lib.NodeID)
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform"})
+		if len(vs) != 1 || vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		vm, ok := az.vm(n)
+		if !ok {
+			az.api.Logf(lib.LLDEBUG, "skipping node %s, no Azure VM configured", n.ID().String())
+			continue
+		}
+		vms[strings.ToLower(vm.GetResourceGroup()+"/"+vm.GetName())] = n.ID()
+	}
+	if len(vms) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := az.client().StatusAll()
+	if e != nil {
+		az.api.Logf(lib.LLERROR, "failed to list VMs: %v", e)
+		return
+	}
+	for k, id := range vms {
+		s, ok := st[k]
+		if !ok {
+			az.api.Logf(lib.LLERROR, "VM %s not found", k)
+			continue
+		}
+		az.discover(id, vmState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &Azure{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/azurepower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("azurepower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
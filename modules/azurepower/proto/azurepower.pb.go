@@ -0,0 +1,191 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: azurepower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type AzureConfig struct {
+	TenantId             string              `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ClientId             string              `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret         string              `protobuf:"bytes,3,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	SubscriptionId       string              `protobuf:"bytes,4,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Vms                  map[string]*AzureVM `protobuf:"bytes,5,rep,name=vms,proto3" json:"vms,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string              `protobuf:"bytes,6,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string              `protobuf:"bytes,7,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	Deallocate           bool                `protobuf:"varint,8,opt,name=deallocate,proto3" json:"deallocate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AzureConfig) Reset()         { *m = AzureConfig{} }
+func (m *AzureConfig) String() string { return proto.CompactTextString(m) }
+func (*AzureConfig) ProtoMessage()    {}
+func (*AzureConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_azurepower_a48ad39394fc225b, []int{0}
+}
+func (m *AzureConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AzureConfig.Unmarshal(m, b)
+}
+func (m *AzureConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AzureConfig.Marshal(b, m, deterministic)
+}
+func (dst *AzureConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AzureConfig.Merge(dst, src)
+}
+func (m *AzureConfig) XXX_Size() int {
+	return xxx_messageInfo_AzureConfig.Size(m)
+}
+func (m *AzureConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_AzureConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AzureConfig proto.InternalMessageInfo
+
+func (m *AzureConfig) GetTenantId() string {
+	if m != nil {
+		return m.TenantId
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetClientSecret() string {
+	if m != nil {
+		return m.ClientSecret
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetSubscriptionId() string {
+	if m != nil {
+		return m.SubscriptionId
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetVms() map[string]*AzureVM {
+	if m != nil {
+		return m.Vms
+	}
+	return nil
+}
+
+func (m *AzureConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *AzureConfig) GetDeallocate() bool {
+	if m != nil {
+		return m.Deallocate
+	}
+	return false
+}
+
+type AzureVM struct {
+	ResourceGroup        string   `protobuf:"bytes,1,opt,name=resource_group,json=resourceGroup,proto3" json:"resource_group,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AzureVM) Reset()         { *m = AzureVM{} }
+func (m *AzureVM) String() string { return proto.CompactTextString(m) }
+func (*AzureVM) ProtoMessage()    {}
+func (*AzureVM) Descriptor() ([]byte, []int) {
+	return fileDescriptor_azurepower_a48ad39394fc225b, []int{1}
+}
+func (m *AzureVM) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AzureVM.Unmarshal(m, b)
+}
+func (m *AzureVM) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AzureVM.Marshal(b, m, deterministic)
+}
+func (dst *AzureVM) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AzureVM.Merge(dst, src)
+}
+func (m *AzureVM) XXX_Size() int {
+	return xxx_messageInfo_AzureVM.Size(m)
+}
+func (m *AzureVM) XXX_DiscardUnknown() {
+	xxx_messageInfo_AzureVM.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AzureVM proto.InternalMessageInfo
+
+func (m *AzureVM) GetResourceGroup() string {
+	if m != nil {
+		return m.ResourceGroup
+	}
+	return ""
+}
+
+func (m *AzureVM) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*AzureConfig)(nil), "proto.AzureConfig")
+	proto.RegisterMapType((map[string]*AzureVM)(nil), "proto.AzureConfig.VmsEntry")
+	proto.RegisterType((*AzureVM)(nil), "proto.AzureVM")
+}
+
+func init() { proto.RegisterFile("azurepower.proto", fileDescriptor_azurepower_a48ad39394fc225b) }
+
+var fileDescriptor_azurepower_a48ad39394fc225b = []byte{
+	// 316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0x4b, 0x4b, 0xc3, 0x40,
+	0x14, 0x85, 0x49, 0xd3, 0x47, 0x7a, 0x6b, 0x1f, 0xcc, 0x2a, 0x5a, 0x90, 0x52, 0x15, 0xeb, 0xc2,
+	0x2e, 0xea, 0x46, 0xdc, 0x89, 0x2f, 0xb2, 0x70, 0x13, 0xb1, 0xdb, 0x30, 0x4d, 0xae, 0x65, 0x70,
+	0x3a, 0x13, 0x26, 0x93, 0x4a, 0xfd, 0xd9, 0xfe, 0x02, 0x99, 0x47, 0x21, 0xab, 0xdc, 0x7c, 0xe7,
+	0x70, 0xe6, 0x70, 0x60, 0x42, 0x7f, 0x6b, 0x85, 0xa5, 0xfc, 0x41, 0xb5, 0x2c, 0x95, 0xd4, 0x92,
+	0x74, 0xec, 0x67, 0xfe, 0xd7, 0x82, 0xc1, 0xa3, 0xd1, 0x9e, 0xa4, 0xf8, 0x62, 0x5b, 0x32, 0x85,
+	0xbe, 0x46, 0x41, 0x85, 0xce, 0x58, 0x11, 0x07, 0xb3, 0x60, 0xd1, 0x4f, 0x23, 0x07, 0x92, 0xc2,
+	0x88, 0x39, 0x67, 0xe8, 0xc4, 0x96, 0x13, 0x1d, 0x48, 0x0a, 0x72, 0x01, 0x43, 0x2f, 0x56, 0x98,
+	0x2b, 0xd4, 0x71, 0x68, 0x0d, 0x27, 0x0e, 0x7e, 0x58, 0x46, 0xae, 0x61, 0x5c, 0xd5, 0x9b, 0x2a,
+	0x57, 0xac, 0xd4, 0x4c, 0x0a, 0x93, 0xd3, 0xb6, 0xb6, 0x51, 0x13, 0x27, 0x05, 0xb9, 0x85, 0x70,
+	0xbf, 0xab, 0xe2, 0xce, 0x2c, 0x5c, 0x0c, 0x56, 0x53, 0xd7, 0x79, 0xd9, 0x28, 0xba, 0x5c, 0xef,
+	0xaa, 0x17, 0xa1, 0xd5, 0x21, 0x35, 0x3e, 0x72, 0x03, 0x93, 0x52, 0x72, 0xce, 0xc4, 0x36, 0x63,
+	0x42, 0xa3, 0xda, 0x53, 0x1e, 0x77, 0x6d, 0xf0, 0xd8, 0xf3, 0xc4, 0x63, 0x72, 0x0a, 0x91, 0xa0,
+	0x3b, 0xcc, 0x6a, 0xc5, 0xe3, 0x9e, 0xb5, 0xf4, 0xcc, 0xff, 0xa7, 0xe2, 0xe4, 0x1c, 0xa0, 0x40,
+	0xca, 0xb9, 0xcc, 0xa9, 0xc6, 0x38, 0x9a, 0x05, 0x8b, 0x28, 0x6d, 0x90, 0xb3, 0x57, 0x88, 0x8e,
+	0xcf, 0x92, 0x09, 0x84, 0xdf, 0x78, 0xf0, 0x13, 0x99, 0x93, 0x5c, 0x42, 0x67, 0x4f, 0x79, 0x8d,
+	0x76, 0x99, 0xc1, 0x6a, 0xd4, 0x2c, 0xbd, 0x7e, 0x4f, 0x9d, 0xf8, 0xd0, 0xba, 0x0f, 0xe6, 0xcf,
+	0xd0, 0xf3, 0x94, 0x5c, 0xc1, 0x48, 0x61, 0x25, 0x6b, 0x95, 0x63, 0xb6, 0x55, 0xb2, 0x2e, 0x7d,
+	0xe2, 0xf0, 0x48, 0xdf, 0x0c, 0x24, 0x04, 0xda, 0xa6, 0xa4, 0x1f, 0xdd, 0xde, 0x9b, 0xae, 0xcd,
+	0xbf, 0xfb, 0x1f, 0x00, 0xdd, 0x35, 0x3f, 0x1a, 0xdc, 0x01, 0x00, 0x00,
+}
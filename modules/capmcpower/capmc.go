@@ -0,0 +1,145 @@
+/* capmc.go: a minimal client for the Cray CAPMC power API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package capmcpower
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/capmcpower/proto"
+)
+
+type capmcClient struct {
+	url       string
+	tokenFile string
+	client    *http.Client
+}
+
+func newCAPMCClient(cfg *pb.CAPMCConfig) *capmcClient {
+	return &capmcClient{
+		url:       strings.TrimRight(cfg.GetUrl(), "/"),
+		tokenFile: cfg.GetTokenFile(),
+		client: &http.Client{
+			Timeout: 120 * time.Second, // CAPMC holds the request until the controllers answer
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.GetInsecure()},
+				IdleConnTimeout: 90 * time.Second,
+			},
+		},
+	}
+}
+
+// capmcResp is the common part of every CAPMC response
+// per xname failures are listed in Xnames
+type capmcResp struct {
+	E      int    `json:"e"`
+	ErrMsg string `json:"err_msg"`
+	Xnames []struct {
+		Xname  string `json:"xname"`
+		E      int    `json:"e"`
+		ErrMsg string `json:"err_msg"`
+	} `json:"xnames"`
+}
+
+// call posts req to a CAPMC API and decodes the response into r
+func (c *capmcClient) call(api string, req interface{}, r interface{}) (e error) {
+	b, _ := json.Marshal(req)
+	hr, e := http.NewRequest(http.MethodPost, c.url+"/"+api, bytes.NewReader(b))
+	if e != nil {
+		return
+	}
+	hr.Header.Set("Content-Type", "application/json")
+	if c.tokenFile != "" {
+		var tok []byte
+		if tok, e = ioutil.ReadFile(c.tokenFile); e != nil {
+			return
+		}
+		hr.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(tok)))
+	}
+	resp, e := c.client.Do(hr)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	// CAPMC reports partial failure with a non-zero e and a 200, but some errors come with other codes
+	if e = json.Unmarshal(rb, r); e != nil {
+		return fmt.Errorf("%s: HTTP %v: %s", api, resp.StatusCode, strings.TrimSpace(string(rb)))
+	}
+	return
+}
+
+// control turns xnames on or off, returning the xnames that failed with their errors
+func (c *capmcClient) control(api string, req map[string]interface{}) (failed map[string]string, e error) {
+	var r capmcResp
+	if e = c.call(api, req, &r); e != nil {
+		return
+	}
+	failed = make(map[string]string)
+	for _, x := range r.Xnames {
+		if x.E != 0 {
+			failed[x.Xname] = x.ErrMsg
+		}
+	}
+	if r.E != 0 && len(failed) == 0 { // the whole call failed
+		return nil, fmt.Errorf("%s: %s", api, r.ErrMsg)
+	}
+	return
+}
+
+// On powers on xnames
+func (c *capmcClient) On(xnames []string) (map[string]string, error) {
+	return c.control("xname_on", map[string]interface{}{
+		"xnames": xnames,
+		"reason": "kraken",
+	})
+}
+
+// Off powers off xnames; force skips the graceful shutdown
+func (c *capmcClient) Off(xnames []string, force bool) (map[string]string, error) {
+	return c.control("xname_off", map[string]interface{}{
+		"xnames": xnames,
+		"reason": "kraken",
+		"force":  force,
+	})
+}
+
+// Status returns "on", "off" or "undefined" for each xname CAPMC knows about
+func (c *capmcClient) Status(xnames []string) (st map[string]string, e error) {
+	var r struct {
+		capmcResp
+		On        []string `json:"on"`
+		Off       []string `json:"off"`
+		Undefined []string `json:"undefined"`
+	}
+	if e = c.call("get_xname_status", map[string]interface{}{"xnames": xnames}, &r); e != nil {
+		return
+	}
+	if r.E != 0 && len(r.On)+len(r.Off)+len(r.Undefined) == 0 {
+		return nil, fmt.Errorf("get_xname_status: %s", r.ErrMsg)
+	}
+	st = make(map[string]string)
+	for _, x := range r.On {
+		st[x] = "on"
+	}
+	for _, x := range r.Off {
+		st[x] = "off"
+	}
+	for _, x := range r.Undefined {
+		st[x] = "undefined"
+	}
+	return
+}
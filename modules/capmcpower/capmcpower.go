@@ -0,0 +1,503 @@
+/* capmcpower.go: mutations for Cray EX nodes using the CAPMC API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/capmcpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = capmc.
+ */
+
+package capmcpower
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/capmcpower/proto"
+)
+
+const (
+	PlatformString string = "capmc"
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "180s", // includes the batch window
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "180s", // graceful shutdown, includes the batch window
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+//////////////////
+// CAPMC Object /
+////////////////
+
+// CAPMC provides a power on/off interface to Cray EX nodes through CAPMC
+type CAPMC struct {
+	api        lib.APIClient
+	cfg        *pb.CAPMCConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	queue      map[string][]capmcReq // op -> pending requests
+	capmc      *capmcClient          // built with the config, under mutex; see client
+}
+
+// capmcReq is a queued power request
+type capmcReq struct {
+	xname string
+	id    lib.NodeID
+	me    *core.MutationEvent // done once the request has been sent
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*CAPMC)(nil)
+
+// Name returns the FQDN of the module
+func (*CAPMC) Name() string { return "github.com/hpc/kraken/modules/capmcpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*CAPMC)(nil)
+var _ lib.ModuleWithConfigValidation = (*CAPMC)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*CAPMC) NewConfig() proto.Message {
+	r := &pb.CAPMCConfig{
+		Url:             "https://api-gw-service-nmn.local/apis/capmc/capmc/v1",
+		TokenFile:       "/etc/kraken/capmc.token",
+		XnameUrl:        "type.googleapis.com/proto.CAPMC/Xname",
+		PollingInterval: "30s",
+		BatchWindow:     "1s",
+		BatchSize:       1000,
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (cp *CAPMC) UpdateConfig(cfg proto.Message) (e error) {
+	if ccfg, ok := cfg.(*pb.CAPMCConfig); ok {
+		cp.mutex.Lock()
+		old := cp.capmc
+		cp.cfg, cp.capmc = ccfg, newCAPMCClient(ccfg)
+		cp.mutex.Unlock()
+		if old != nil {
+			old.client.CloseIdleConnections()
+		}
+		if cp.pollTicker != nil {
+			cp.pollTicker.Stop()
+			dur, _ := time.ParseDuration(cp.cfg.GetPollingInterval())
+			cp.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig wants a CAPMC URL, a positive poll interval, a batch window of 0 or more and a batch size of at least 1
+func (*CAPMC) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.CAPMCConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if u, e := url.Parse(c.GetUrl()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid CAPMC URL: %q", c.GetUrl())
+	}
+	if dur, e := time.ParseDuration(c.GetBatchWindow()); e != nil || dur < 0 {
+		return fmt.Errorf("invalid batch window: %q", c.GetBatchWindow())
+	}
+	if c.GetBatchSize() < 1 {
+		return fmt.Errorf("invalid batch size: %d", c.GetBatchSize())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*CAPMC) ConfigURL() string {
+	cfg := &pb.CAPMCConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*CAPMC)(nil)
+var _ lib.ModuleWithDiscovery = (*CAPMC)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (cp *CAPMC) SetMutationChan(c <-chan lib.Event) { cp.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (cp *CAPMC) SetDiscoveryChan(c chan<- lib.Event) { cp.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*CAPMC)(nil)
+
+// Entry is the module's executable entrypoint
+func (cp *CAPMC) Entry() {
+	url := lib.NodeURLJoin(cp.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "capmcpower"), "State"))
+	cp.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  cp.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(cp.cfg.GetPollingInterval())
+	cp.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-cp.pollTicker.C:
+			go cp.discoverAll()
+			break
+		case m := <-cp.mchan: // mutation request
+			go cp.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (cp *CAPMC) Init(api lib.APIClient) {
+	cp.api = api
+	cp.cfg = cp.NewConfig().(*pb.CAPMCConfig)
+	cp.capmc = newCAPMCClient(cp.cfg)
+	cp.queue = make(map[string][]capmcReq)
+}
+
+// Stop should perform a graceful exit
+func (cp *CAPMC) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (cp *CAPMC) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		cp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	queued := false
+	defer func() {
+		if !queued { // queued requests are done once they're flushed
+			me.Done()
+		}
+	}()
+	// extract the mutating node's xname
+	vs := me.NodeCfg.GetValues([]string{cp.cfg.GetXnameUrl()})
+	if len(vs) != 1 {
+		cp.api.Logf(lib.LLERROR, "could not get xname for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	r := capmcReq{
+		xname: vs[cp.cfg.GetXnameUrl()].String(),
+		id:    me.NodeCfg.ID(),
+		me:    me,
+	}
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			cp.discoverXnames([]capmcReq{r})
+		case "OFFtoON":
+			cp.enqueue("on", r)
+			queued = true
+		case "ONtoOFF":
+			cp.enqueue("off", r)
+			queued = true
+		case "HANGtoOFF":
+			cp.enqueue("forceoff", r)
+			queued = true
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			cp.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, so it's dropped if it's still queued
+		// batches that have been sent have other nodes in them, so we let them finish
+		break
+	}
+}
+
+// client gets the CAPMC client for the current config
+func (cp *CAPMC) client() *capmcClient {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	return cp.capmc
+}
+
+// enqueue adds a request to the op's queue
+// the first request in an empty queue schedules a flush after the batch window
+func (cp *CAPMC) enqueue(op string, r capmcReq) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+	if len(cp.queue[op]) == 0 {
+		dur, _ := time.ParseDuration(cp.cfg.GetBatchWindow())
+		time.AfterFunc(dur, func() { cp.flush(op) })
+	}
+	cp.queue[op] = append(cp.queue[op], r)
+}
+
+// flush sends everything queued for op, batch_size xnames per call
+func (cp *CAPMC) flush(op string) {
+	cp.mutex.Lock()
+	rs := cp.queue[op]
+	delete(cp.queue, op)
+	cp.mutex.Unlock()
+	defer func() {
+		for _, r := range rs {
+			r.me.Done()
+		}
+	}()
+	var send []capmcReq
+	for _, r := range rs {
+		if r.me.Context.Err() != nil {
+			cp.api.Logf(lib.LLINFO, "interrupted: CAPMC %s for %s", op, r.xname)
+			continue
+		}
+		send = append(send, r)
+	}
+
+	c := cp.client()
+	for _, b := range cp.batches(send) {
+		xnames := make([]string, len(b))
+		for i, r := range b {
+			xnames[i] = r.xname
+		}
+		var failed map[string]string
+		var e error
+		vid := "POWER_OFF"
+		switch op {
+		case "on":
+			failed, e = c.On(xnames)
+			vid = "POWER_ON"
+		case "off":
+			failed, e = c.Off(xnames, false)
+		case "forceoff":
+			failed, e = c.Off(xnames, true)
+		}
+		if e != nil {
+			// the SME will fail these to PHYS_HANG
+			cp.api.Logf(lib.LLERROR, "CAPMC %s failed for %d xnames: %v", op, len(xnames), e)
+			continue
+		}
+		for _, r := range b {
+			if msg, ok := failed[r.xname]; ok {
+				cp.api.Logf(lib.LLERROR, "CAPMC %s failed for %s: %s", op, r.xname, msg)
+				continue
+			}
+			cp.discover(r.id, vid, time.Now())
+		}
+	}
+}
+
+// batches splits requests into groups of at most batch_size
+func (cp *CAPMC) batches(rs []capmcReq) (r [][]capmcReq) {
+	size := int(cp.cfg.GetBatchSize())
+	if size <= 0 {
+		size = len(rs)
+	}
+	for len(rs) > size {
+		r = append(r, rs[:size])
+		rs = rs[size:]
+	}
+	if len(rs) > 0 {
+		r = append(r, rs)
+	}
+	return
+}
+
+// xnameState maps a CAPMC status to a PhysState value ID
+func xnameState(s string) string {
+	switch s {
+	case "on":
+		return "POWER_ON"
+	case "off":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+// discoverXnames gets the status of xnames in batches and reports them
+func (cp *CAPMC) discoverXnames(rs []capmcReq) {
+	c := cp.client()
+	for _, b := range cp.batches(rs) {
+		xnames := make([]string, len(b))
+		for i, r := range b {
+			xnames[i] = r.xname
+		}
+		start := time.Now()
+		st, e := c.Status(xnames)
+		if e != nil {
+			cp.api.Logf(lib.LLERROR, "CAPMC status failed for %d xnames: %v", len(xnames), e)
+			continue
+		}
+		for _, r := range b {
+			s, ok := st[r.xname]
+			if !ok {
+				cp.api.Logf(lib.LLERROR, "CAPMC did not report status for %s", r.xname)
+				continue
+			}
+			cp.discover(r.id, xnameState(s), start)
+		}
+	}
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (cp *CAPMC) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  cp.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	cp.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (cp *CAPMC) discoverAll() {
+	cp.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := cp.api.QueryReadAll()
+	if e != nil {
+		cp.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	rs := []capmcReq{}
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", cp.cfg.GetXnameUrl()})
+		if len(vs) != 2 {
+			cp.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete CAPMC info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		rs = append(rs, capmcReq{xname: vs[cp.cfg.GetXnameUrl()].String(), id: n.ID()})
+	}
+	cp.discoverXnames(rs)
+}
+
+// initialization
+func init() {
+	module := &CAPMC{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/capmcpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("capmcpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
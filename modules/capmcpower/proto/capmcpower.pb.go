@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: capmcpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CAPMCConfig struct {
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	TokenFile            string   `protobuf:"bytes,2,opt,name=token_file,json=tokenFile,proto3" json:"token_file,omitempty"`
+	Insecure             bool     `protobuf:"varint,3,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	PollingInterval      string   `protobuf:"bytes,4,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	XnameUrl             string   `protobuf:"bytes,5,opt,name=xname_url,json=xnameUrl,proto3" json:"xname_url,omitempty"`
+	BatchWindow          string   `protobuf:"bytes,6,opt,name=batch_window,json=batchWindow,proto3" json:"batch_window,omitempty"`
+	BatchSize            int32    `protobuf:"varint,7,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CAPMCConfig) Reset()         { *m = CAPMCConfig{} }
+func (m *CAPMCConfig) String() string { return proto.CompactTextString(m) }
+func (*CAPMCConfig) ProtoMessage()    {}
+func (*CAPMCConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_capmcpower_aa0ea1156d9e9ed2, []int{0}
+}
+func (m *CAPMCConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CAPMCConfig.Unmarshal(m, b)
+}
+func (m *CAPMCConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CAPMCConfig.Marshal(b, m, deterministic)
+}
+func (dst *CAPMCConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CAPMCConfig.Merge(dst, src)
+}
+func (m *CAPMCConfig) XXX_Size() int {
+	return xxx_messageInfo_CAPMCConfig.Size(m)
+}
+func (m *CAPMCConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_CAPMCConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CAPMCConfig proto.InternalMessageInfo
+
+func (m *CAPMCConfig) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CAPMCConfig) GetTokenFile() string {
+	if m != nil {
+		return m.TokenFile
+	}
+	return ""
+}
+
+func (m *CAPMCConfig) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *CAPMCConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *CAPMCConfig) GetXnameUrl() string {
+	if m != nil {
+		return m.XnameUrl
+	}
+	return ""
+}
+
+func (m *CAPMCConfig) GetBatchWindow() string {
+	if m != nil {
+		return m.BatchWindow
+	}
+	return ""
+}
+
+func (m *CAPMCConfig) GetBatchSize() int32 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*CAPMCConfig)(nil), "proto.CAPMCConfig")
+}
+
+func init() { proto.RegisterFile("capmcpower.proto", fileDescriptor_capmcpower_aa0ea1156d9e9ed2) }
+
+var fileDescriptor_capmcpower_aa0ea1156d9e9ed2 = []byte{
+	// 220 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3c, 0xcf, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x06, 0x60, 0x62, 0xdd, 0xba, 0x99, 0x0a, 0x2e, 0x39, 0x05, 0x45, 0x58, 0x3d, 0xad, 0x17,
+	0x2f, 0x3e, 0x81, 0x2c, 0x08, 0x1e, 0x04, 0x59, 0x11, 0x8f, 0x21, 0x8d, 0xd3, 0x3a, 0x98, 0x26,
+	0x21, 0x4d, 0x5d, 0xe9, 0xf3, 0xfa, 0x20, 0xb2, 0x63, 0xf1, 0x94, 0xe4, 0xfb, 0x7f, 0x92, 0x0c,
+	0x34, 0xce, 0xa6, 0x8d, 0x4b, 0x71, 0xc4, 0x7c, 0x9b, 0x72, 0x2c, 0x51, 0x55, 0xbc, 0x5c, 0xff,
+	0x08, 0x58, 0xf4, 0xf7, 0xcf, 0x4f, 0x7d, 0x1f, 0xc3, 0x8a, 0xd6, 0xaa, 0x81, 0xd9, 0x2e, 0x7b,
+	0x2d, 0x5a, 0xd1, 0xc9, 0x61, 0xda, 0xaa, 0x4b, 0x80, 0x12, 0x3f, 0x31, 0x98, 0x15, 0x79, 0xd4,
+	0x47, 0x1c, 0x48, 0x96, 0x07, 0xf2, 0xa8, 0xce, 0xa1, 0xa6, 0xb0, 0x45, 0xb7, 0xcb, 0xa8, 0x67,
+	0xad, 0xe8, 0xea, 0xe1, 0xff, 0xac, 0x6e, 0xa0, 0x49, 0xd1, 0x7b, 0x0a, 0x6b, 0x43, 0xa1, 0x60,
+	0xfe, 0xb2, 0x5e, 0x1f, 0xf3, 0x05, 0x67, 0x07, 0x7f, 0x3c, 0xb0, 0xba, 0x00, 0xf9, 0x1d, 0xec,
+	0x06, 0xcd, 0xf4, 0x7a, 0xc5, 0x9d, 0x9a, 0xe1, 0x35, 0x7b, 0x75, 0x05, 0xa7, 0x4b, 0x5b, 0xdc,
+	0x87, 0x19, 0x29, 0xbc, 0xc7, 0x51, 0xcf, 0x39, 0x5f, 0xb0, 0xbd, 0x31, 0x4d, 0xbf, 0xfc, 0xab,
+	0x6c, 0x69, 0x8f, 0xfa, 0xa4, 0x15, 0x5d, 0x35, 0x48, 0x96, 0x17, 0xda, 0xe3, 0x72, 0xce, 0xd3,
+	0xde, 0xfd, 0x0e, 0x00, 0xb5, 0x97, 0x68, 0x99, 0x08, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,441 @@
+/* dockerpower.go: mutations for docker containers using the docker CLI
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/dockerpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = docker.
+ */
+
+package dockerpower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/dockerpower/proto"
+)
+
+const (
+	PlatformString string = "docker"
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "10s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s", // docker stop waits stop_timeout before it kills
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+///////////////////
+// Docker Object /
+/////////////////
+
+// Docker provides a power on/off interface to docker containers
+type Docker struct {
+	api        lib.APIClient
+	cfg        *pb.DockerConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Docker)(nil)
+
+// Name returns the FQDN of the module
+func (*Docker) Name() string { return "github.com/hpc/kraken/modules/dockerpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Docker)(nil)
+var _ lib.ModuleWithConfigValidation = (*Docker)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Docker) NewConfig() proto.Message {
+	r := &pb.DockerConfig{
+		Docker:          "/usr/bin/docker",
+		NameUrl:         "type.googleapis.com/proto.Docker/Name",
+		PollingInterval: "10s",
+		StopTimeout:     10,
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (dk *Docker) UpdateConfig(cfg proto.Message) (e error) {
+	if dkcfg, ok := cfg.(*pb.DockerConfig); ok {
+		dk.cfg = dkcfg
+		if dk.pollTicker != nil {
+			dk.pollTicker.Stop()
+			dur, _ := time.ParseDuration(dk.cfg.GetPollingInterval())
+			dk.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval and the stop timeout
+func (*Docker) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.DockerConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if c.GetStopTimeout() < 0 {
+		return fmt.Errorf("invalid stop timeout: %d", c.GetStopTimeout())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Docker) ConfigURL() string {
+	cfg := &pb.DockerConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Docker)(nil)
+var _ lib.ModuleWithDiscovery = (*Docker)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (dk *Docker) SetMutationChan(c <-chan lib.Event) { dk.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (dk *Docker) SetDiscoveryChan(c chan<- lib.Event) { dk.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Docker)(nil)
+
+// Entry is the module's executable entrypoint
+func (dk *Docker) Entry() {
+	url := lib.NodeURLJoin(dk.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "dockerpower"), "State"))
+	dk.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  dk.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(dk.cfg.GetPollingInterval())
+	dk.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-dk.pollTicker.C:
+			go dk.discoverAll()
+			break
+		case m := <-dk.mchan: // mutation request
+			go dk.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (dk *Docker) Init(api lib.APIClient) {
+	dk.api = api
+	dk.cfg = dk.NewConfig().(*pb.DockerConfig)
+}
+
+// Stop should perform a graceful exit
+func (dk *Docker) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (dk *Docker) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		dk.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's container name
+	vs := me.NodeCfg.GetValues([]string{dk.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		dk.api.Logf(lib.LLERROR, "could not get container name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[dk.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			dk.ctrDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			dk.ctrControl(me.Context, name, me.NodeCfg.ID(), "POWER_ON", "start", name)
+		case "ONtoOFF":
+			dk.ctrControl(me.Context, name, me.NodeCfg.ID(), "POWER_OFF", "stop", "-t", strconv.Itoa(int(dk.cfg.GetStopTimeout())), name)
+		case "HANGtoOFF":
+			dk.ctrControl(me.Context, name, me.NodeCfg.ID(), "POWER_OFF", "kill", name)
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			dk.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any docker command in progress
+		break
+	}
+}
+
+// docker runs a docker command and returns its trimmed output
+// the command is killed if ctx is canceled
+func (dk *Docker) docker(ctx context.Context, args ...string) (string, error) {
+	if h := dk.cfg.GetHost(); h != "" {
+		args = append([]string{"-H", h}, args...)
+	}
+	cmd := exec.CommandContext(ctx, dk.cfg.GetDocker(), args...)
+	out, e := cmd.CombinedOutput()
+	if e != nil {
+		return strings.TrimSpace(string(out)), fmt.Errorf("docker %s failed: %v: %s", strings.Join(args, " "), e, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ctrState maps a docker container status to a PhysState value ID
+func ctrState(s string) string {
+	switch s {
+	case "running", "paused", "restarting":
+		return "POWER_ON"
+	case "created", "exited", "dead":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+// inspect gets the status of containers by name
+// containers that don't exist are left out of the result
+func (dk *Docker) inspect(names ...string) (map[string]string, error) {
+	/* example output
+	 * /node1|running
+	 * /node2|exited
+	 */
+	out, e := dk.docker(context.Background(), append([]string{"inspect", "-f", "{{.Name}}|{{.State.Status}}"}, names...)...)
+	st := make(map[string]string)
+	for _, l := range strings.Split(out, "\n") {
+		f := strings.SplitN(l, "|", 2)
+		if len(f) != 2 || !strings.HasPrefix(f[0], "/") {
+			continue
+		}
+		st[strings.TrimPrefix(f[0], "/")] = f[1]
+	}
+	// docker inspect fails if any name is missing, but still reports the rest
+	if e != nil && len(st) == 0 {
+		return nil, e
+	}
+	return st, nil
+}
+
+func (dk *Docker) ctrDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	st, e := dk.inspect(name)
+	if e != nil {
+		dk.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	s, ok := st[name]
+	if !ok {
+		dk.api.Logf(lib.LLERROR, "container %s not found", name)
+		return
+	}
+	dk.discover(id, ctrState(s), start)
+}
+
+// ctrControl runs a docker command and reports vid on success
+// if the mutation is interrupted, we report whatever state the container was left in instead
+func (dk *Docker) ctrControl(ctx context.Context, name string, id lib.NodeID, vid string, args ...string) {
+	if _, e := dk.docker(ctx, args...); e != nil {
+		if ctx.Err() != nil {
+			dk.api.Logf(lib.LLINFO, "interrupted: docker %s", strings.Join(args, " "))
+			dk.ctrDiscover(name, id)
+			return
+		}
+		dk.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	dk.discover(id, vid, time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (dk *Docker) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  dk.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	dk.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// all containers are inspected with one docker call
+func (dk *Docker) discoverAll() {
+	dk.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := dk.api.QueryReadAll()
+	if e != nil {
+		dk.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	idmap := make(map[string]lib.NodeID)
+	names := []string{}
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", dk.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			dk.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete docker info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		name := vs[dk.cfg.GetNameUrl()].String()
+		idmap[name] = n.ID()
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := dk.inspect(names...)
+	if e != nil {
+		dk.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	for _, n := range names {
+		s, ok := st[n]
+		if !ok {
+			dk.api.Logf(lib.LLERROR, "container %s not found", n)
+			continue
+		}
+		dk.discover(idmap[n], ctrState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &Docker{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/dockerpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("dockerpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
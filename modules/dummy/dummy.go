@@ -12,6 +12,7 @@
 package dummy
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -32,6 +33,7 @@ var _ lib.ModuleSelfService = (*Dummy)(nil)
 var _ lib.ModuleWithConfig = (*Dummy)(nil)
 var _ lib.ModuleWithDiscovery = (*Dummy)(nil)
 var _ lib.ModuleWithMutations = (*Dummy)(nil)
+var _ lib.ModuleWithShutdown = (*Dummy)(nil)
 
 var muts = map[string][2]string{
 	"dumFail":   {"", "fail"},     // we need some in arrow to our failure mode or it won't get built into the graph
@@ -66,6 +68,8 @@ type Dummy struct {
 	dchan  chan<- lib.Event
 	mchan  <-chan lib.Event
 	timers map[string]*time.Timer
+	muts   map[string]*core.MutationEvent // the mutations the timers are for
+	stop   chan chan struct{}
 }
 
 /*
@@ -77,6 +81,7 @@ type Dummy struct {
 // and a config in place before this is called.
 func (d *Dummy) Entry() {
 	d.timers = make(map[string]*time.Timer)
+	d.muts = make(map[string]*core.MutationEvent)
 	if d.cfg == nil {
 		d.cfg = d.NewConfig().(*pb.DummyConfig)
 	}
@@ -116,12 +121,24 @@ func (d *Dummy) Entry() {
 		},
 	)
 
+	var stopped chan struct{}
 	for {
 		select {
 		case dv := <-dtimer:
 			// time to discover something!
 			fmt.Printf("discovered: %s\n", dv.Data().(*core.DiscoveryEvent).ValueID)
+			url := dv.Data().(*core.DiscoveryEvent).URL
+			delete(d.timers, url)
 			d.dchan <- dv
+			if me, ok := d.muts[url]; ok {
+				me.Done()
+				delete(d.muts, url)
+			}
+			break
+		case stopped = <-d.stop:
+			// take no more mutations
+			fmt.Printf("stopping, waiting for %d mutations\n", len(d.timers))
+			d.mchan = nil
 			break
 		case mv := <-d.mchan:
 			// got a mutation
@@ -129,12 +146,14 @@ func (d *Dummy) Entry() {
 			if data.Type == corepb.MutationControl_MUTATE {
 				if data.Mutation[0] != d.Name() {
 					fmt.Printf("got a mutation intended for someone else: %s\n", data.Mutation[0])
+					data.Done()
 					continue
 				}
 				var val [2]string
 				var ok bool
 				if val, ok = muts[data.Mutation[1]]; !ok {
 					fmt.Printf("got an unknown requested mutation: %s\n", data.Mutation[1])
+					data.Done()
 					continue
 				}
 				url := lib.NodeURLJoin(data.NodeCfg.ID().String(), "/Platform")
@@ -148,13 +167,30 @@ func (d *Dummy) Entry() {
 						ValueID: val[1],
 					},
 				)
-				d.timers[data.NodeCfg.ID().String()] = time.AfterFunc(3*time.Second, func() { dtimer <- v })
+				if t, ok := d.timers[url]; ok {
+					// replaced by this one
+					t.Stop()
+					d.muts[url].Done()
+				}
+				d.timers[url] = time.AfterFunc(3*time.Second, func() { dtimer <- v })
+				d.muts[url] = data
 			} else {
+				url := lib.NodeURLJoin(data.NodeCfg.ID().String(), "/Platform")
 				fmt.Printf("got an interrupt for %s, stopping discovery timer\n", data.NodeCfg.ID().String())
-				d.timers[data.NodeCfg.ID().String()].Stop()
+				if t, ok := d.timers[url]; ok {
+					t.Stop()
+					delete(d.timers, url)
+					d.muts[url].Done()
+					delete(d.muts, url)
+				}
+				data.Done()
 			}
 			break
 		}
+		if stopped != nil && len(d.timers) == 0 {
+			close(stopped)
+			select {} // we're about to exit
+		}
 	}
 }
 
@@ -165,6 +201,23 @@ func (d *Dummy) Stop() {
 	os.Exit(0)
 }
 
+// Shutdown waits for the mutations we're doing to be discovered.
+// Modules that implement it get to stop gracefully, before they're killed.
+func (d *Dummy) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	select {
+	case d.stop <- stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Name needs to return a uniquely identifying string.
 // Every module must have a name.
 // The URL to the module is a good choice.
@@ -203,6 +256,9 @@ func (d *Dummy) SetMutationChan(c <-chan lib.Event) {
 func (d *Dummy) Init(api lib.APIClient) {
 	d.api = api
 	d.i = 0
+	if d.stop == nil {
+		d.stop = make(chan chan struct{})
+	}
 }
 
 func (d *Dummy) NewConfig() proto.Message {
@@ -0,0 +1,187 @@
+/* ec2.go: a minimal client for the EC2 query API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package ec2power
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ec2APIVersion = "2016-11-15"
+
+// ec2Client signs requests with AWS signature version 4
+type ec2Client struct {
+	endpoint string
+	region   string
+	akid     string
+	secret   string
+	token    string
+	client   *http.Client
+}
+
+// ec2Error is the error document EC2 returns
+type ec2Error struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+}
+
+// ec2Instances is the part of a DescribeInstancesResponse we care about
+type ec2Instances struct {
+	Reservations []struct {
+		Instances []struct {
+			InstanceID string `xml:"instanceId"`
+			State      string `xml:"instanceState>name"`
+		} `xml:"instancesSet>item"`
+	} `xml:"reservationSet>item"`
+	NextToken string `xml:"nextToken"`
+}
+
+// call performs an EC2 action, and unmarshals the XML response into r (if not nil)
+// the request is abandoned if ctx is canceled
+func (c *ec2Client) call(ctx context.Context, action string, params url.Values, r interface{}) (e error) {
+	params.Set("Action", action)
+	params.Set("Version", ec2APIVersion)
+	body := params.Encode()
+
+	u, e := url.Parse(c.endpoint)
+	if e != nil {
+		return
+	}
+	req, e := http.NewRequest(http.MethodPost, c.endpoint, strings.NewReader(body))
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	c.sign(req, u.Host, []byte(body), time.Now().UTC())
+
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return
+	}
+	if resp.StatusCode != 200 {
+		var ee ec2Error
+		if xml.Unmarshal(rb, &ee) == nil && len(ee.Errors) > 0 {
+			return fmt.Errorf("%s: %s: %s", action, ee.Errors[0].Code, ee.Errors[0].Message)
+		}
+		return fmt.Errorf("%s: HTTP %v", action, resp.StatusCode)
+	}
+	if r != nil {
+		return xml.Unmarshal(rb, r)
+	}
+	return
+}
+
+// sign adds SigV4 headers to req
+func (c *ec2Client) sign(req *http.Request, host string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("Host", host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	headers := "host:" + host + "\nx-amz-date:" + amzDate + "\n"
+	signed := "host;x-amz-date"
+	if c.token != "" {
+		req.Header.Set("X-Amz-Security-Token", c.token)
+		headers += "x-amz-security-token:" + c.token + "\n"
+		signed += ";x-amz-security-token"
+	}
+	creq := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		headers,
+		signed,
+		hexSHA256(body),
+	}, "\n")
+	scope := date + "/" + c.region + "/ec2/aws4_request"
+	sts := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(creq))
+	k := hmacSHA256([]byte("AWS4"+c.secret), date)
+	k = hmacSHA256(k, c.region)
+	k = hmacSHA256(k, "ec2")
+	k = hmacSHA256(k, "aws4_request")
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+c.akid+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+hex.EncodeToString(hmacSHA256(k, sts)))
+}
+
+// instanceParams builds the InstanceId.N parameters
+func instanceParams(ids []string) url.Values {
+	v := url.Values{}
+	for i, id := range ids {
+		v.Set("InstanceId."+strconv.Itoa(i+1), id)
+	}
+	return v
+}
+
+// Start starts instances
+func (c *ec2Client) Start(ctx context.Context, ids ...string) error {
+	return c.call(ctx, "StartInstances", instanceParams(ids), nil)
+}
+
+// Stop stops instances, force skips the guest's clean shutdown
+func (c *ec2Client) Stop(ctx context.Context, force bool, ids ...string) error {
+	v := instanceParams(ids)
+	if force {
+		v.Set("Force", "true")
+	}
+	return c.call(ctx, "StopInstances", v, nil)
+}
+
+// Describe returns the state name of each instance, e.g. "running"
+func (c *ec2Client) Describe(ctx context.Context, ids ...string) (map[string]string, error) {
+	r := make(map[string]string)
+	token := ""
+	for {
+		v := instanceParams(ids)
+		if token != "" {
+			v.Set("NextToken", token)
+		}
+		var di ec2Instances
+		if e := c.call(ctx, "DescribeInstances", v, &di); e != nil {
+			return nil, e
+		}
+		for _, rs := range di.Reservations {
+			for _, i := range rs.Instances {
+				r[i.InstanceID] = i.State
+			}
+		}
+		if di.NextToken == "" {
+			return r, nil
+		}
+		token = di.NextToken
+	}
+}
+
+func hexSHA256(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
@@ -0,0 +1,489 @@
+/* ec2power.go: mutations for AWS EC2 instances using the EC2 API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/ec2power.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = ec2.
+ */
+
+package ec2power
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/ec2power/proto"
+)
+
+const (
+	PlatformString string = "ec2"
+
+	// transitionWait is how long we poll an instance for its target state, it matches the mutation timeouts
+	transitionWait = 120 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "120s", // instances can take a while to leave "pending"
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////
+// EC2 Object /
+//////////////
+
+// EC2 provides a power on/off interface to AWS EC2 instances
+type EC2 struct {
+	api        lib.APIClient
+	cfg        *pb.EC2Config
+	ec2        *ec2Client // built with the config; see client
+	mutex      sync.Mutex // guards ec2
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*EC2)(nil)
+
+// Name returns the FQDN of the module
+func (*EC2) Name() string { return "github.com/hpc/kraken/modules/ec2power" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*EC2)(nil)
+var _ lib.ModuleWithConfigValidation = (*EC2)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*EC2) NewConfig() proto.Message {
+	r := &pb.EC2Config{
+		Region:          "us-east-1",
+		InstanceUrl:     "type.googleapis.com/proto.EC2/InstanceId",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (ec *EC2) UpdateConfig(cfg proto.Message) (e error) {
+	if ecfg, ok := cfg.(*pb.EC2Config); ok {
+		ec.mutex.Lock()
+		ec.cfg, ec.ec2 = ecfg, newEC2Client(ecfg)
+		ec.mutex.Unlock()
+		if ec.pollTicker != nil {
+			ec.pollTicker.Stop()
+			dur, _ := time.ParseDuration(ec.cfg.GetPollingInterval())
+			ec.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, and the endpoint URL if one is set
+func (*EC2) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.EC2Config)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if c.GetEndpoint() != "" {
+		if u, e := url.Parse(c.GetEndpoint()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid endpoint: %q", c.GetEndpoint())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*EC2) ConfigURL() string {
+	cfg := &pb.EC2Config{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*EC2)(nil)
+var _ lib.ModuleWithDiscovery = (*EC2)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (ec *EC2) SetMutationChan(c <-chan lib.Event) { ec.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (ec *EC2) SetDiscoveryChan(c chan<- lib.Event) { ec.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*EC2)(nil)
+
+// Entry is the module's executable entrypoint
+func (ec *EC2) Entry() {
+	url := lib.NodeURLJoin(ec.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "ec2power"), "State"))
+	ec.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ec.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(ec.cfg.GetPollingInterval())
+	ec.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-ec.pollTicker.C:
+			go ec.discoverAll()
+			break
+		case m := <-ec.mchan: // mutation request
+			go ec.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (ec *EC2) Init(api lib.APIClient) {
+	ec.api = api
+	ec.cfg = ec.NewConfig().(*pb.EC2Config)
+	ec.ec2 = newEC2Client(ec.cfg)
+}
+
+// Stop should perform a graceful exit
+func (ec *EC2) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (ec *EC2) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		ec.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's instance ID
+	vs := me.NodeCfg.GetValues([]string{ec.cfg.GetInstanceUrl()})
+	if len(vs) != 1 {
+		ec.api.Logf(lib.LLERROR, "could not get EC2 instance ID for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	iid := vs[ec.cfg.GetInstanceUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			ec.instanceDiscover(iid, me.NodeCfg.ID())
+		case "OFFtoON":
+			ec.instanceStart(me.Context, iid, me.NodeCfg.ID())
+		case "ONtoOFF":
+			ec.instanceStop(me.Context, iid, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			ec.instanceStop(me.Context, iid, true, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			ec.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// client gets the EC2 client for the current config
+func (ec *EC2) client() *ec2Client {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	return ec.ec2
+}
+
+// newEC2Client builds an EC2 client from a config, falling back to the usual AWS environment variables
+func newEC2Client(cfg *pb.EC2Config) *ec2Client {
+	c := &ec2Client{
+		endpoint: cfg.GetEndpoint(),
+		region:   cfg.GetRegion(),
+		akid:     cfg.GetAccessKeyId(),
+		secret:   cfg.GetSecretAccessKey(),
+		token:    cfg.GetSessionToken(),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if c.endpoint == "" {
+		c.endpoint = "https://ec2." + c.region + ".amazonaws.com/"
+	}
+	if c.akid == "" {
+		c.akid = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if c.secret == "" {
+		c.secret = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if c.token == "" {
+		c.token = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	return c
+}
+
+// instanceState maps an EC2 instance state name to a PhysState value ID
+// transitional states are reported as unknown
+func instanceState(s string) string {
+	switch s {
+	case "running":
+		return "POWER_ON"
+	case "stopped":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (ec *EC2) instanceDiscover(iid string, id lib.NodeID) {
+	start := time.Now()
+	st, e := ec.client().Describe(context.Background(), iid)
+	if e != nil {
+		ec.api.Logf(lib.LLERROR, "failed to describe instance %s: %v", iid, e)
+		return
+	}
+	s, ok := st[iid]
+	if !ok {
+		ec.api.Logf(lib.LLERROR, "instance %s not found", iid)
+		return
+	}
+	ec.discover(id, instanceState(s), start)
+}
+
+// instanceStart starts an instance, and waits for it to be running
+// if the mutation is interrupted, we report whatever state the instance was left in instead
+func (ec *EC2) instanceStart(ctx context.Context, iid string, id lib.NodeID) {
+	c := ec.client()
+	if e := c.Start(ctx, iid); e != nil {
+		if ctx.Err() != nil {
+			ec.api.Logf(lib.LLINFO, "interrupted: starting instance %s", iid)
+			ec.instanceDiscover(iid, id)
+			return
+		}
+		ec.api.Logf(lib.LLERROR, "failed to start instance %s: %v", iid, e)
+		return
+	}
+	ec.instanceWait(ctx, c, iid, "running", id)
+}
+
+// instanceStop stops an instance, and waits for it to be stopped
+// if the mutation is interrupted, we report whatever state the instance was left in instead
+func (ec *EC2) instanceStop(ctx context.Context, iid string, force bool, id lib.NodeID) {
+	c := ec.client()
+	if e := c.Stop(ctx, force, iid); e != nil {
+		if ctx.Err() != nil {
+			ec.api.Logf(lib.LLINFO, "interrupted: stopping instance %s", iid)
+			ec.instanceDiscover(iid, id)
+			return
+		}
+		ec.api.Logf(lib.LLERROR, "failed to stop instance %s: %v", iid, e)
+		return
+	}
+	ec.instanceWait(ctx, c, iid, "stopped", id)
+}
+
+// instanceWait polls an instance until it reaches the target state, then reports it
+func (ec *EC2) instanceWait(ctx context.Context, c *ec2Client, iid, target string, id lib.NodeID) {
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			ec.api.Logf(lib.LLINFO, "interrupted: waiting for instance %s to be %s", iid, target)
+			ec.instanceDiscover(iid, id)
+			return
+		}
+		at := time.Now()
+		st, e := c.Describe(ctx, iid)
+		if e != nil {
+			ec.api.Logf(lib.LLDEBUG, "failed to describe instance %s: %v", iid, e)
+			continue
+		}
+		if st[iid] == target {
+			ec.discover(id, instanceState(target), at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	ec.api.Logf(lib.LLERROR, "instance %s did not reach %s after %s", iid, target, transitionWait.String())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (ec *EC2) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ec.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	ec.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// all instances are described in one call
+func (ec *EC2) discoverAll() {
+	ec.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := ec.api.QueryReadAll()
+	if e != nil {
+		ec.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	idmap := make(map[string]lib.NodeID)
+	iids := []string{}
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", ec.cfg.GetInstanceUrl()})
+		if len(vs) != 2 {
+			ec.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete EC2 info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		iid := vs[ec.cfg.GetInstanceUrl()].String()
+		idmap[iid] = n.ID()
+		iids = append(iids, iid)
+	}
+	if len(iids) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := ec.client().Describe(context.Background(), iids...)
+	if e != nil {
+		ec.api.Logf(lib.LLERROR, "failed to describe instances: %v", e)
+		return
+	}
+	for _, iid := range iids {
+		s, ok := st[iid]
+		if !ok {
+			ec.api.Logf(lib.LLERROR, "instance %s not found", iid)
+			continue
+		}
+		ec.discover(idmap[iid], instanceState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &EC2{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/ec2power/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("ec2power", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
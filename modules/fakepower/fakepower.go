@@ -0,0 +1,408 @@
+/* fakepower.go: simulated power control for testing mutation chains at scale
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/fakepower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = fake.
+ * There is no hardware; power state lives in an in-memory model, so thousands of
+ * nodes can be run through the SME without touching anything real.
+ */
+
+package fakepower
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/fakepower/proto"
+)
+
+const (
+	PlatformString string = "fake"
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s", // latencies need to stay under these
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+/////////////////////
+// FakePower Object /
+///////////////////
+
+// FakePower simulates a power control backend
+type FakePower struct {
+	api        lib.APIClient
+	cfg        *pb.FakePowerConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	state      map[string]cpb.Node_PhysState // node ID -> simulated power state
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*FakePower)(nil)
+
+// Name returns the FQDN of the module
+func (*FakePower) Name() string { return "github.com/hpc/kraken/modules/fakepower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*FakePower)(nil)
+var _ lib.ModuleWithConfigValidation = (*FakePower)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*FakePower) NewConfig() proto.Message {
+	r := &pb.FakePowerConfig{
+		PollingInterval: "10s",
+		InitialState:    "POWER_OFF",
+		OnLatency:       "2s",
+		OffLatency:      "1s",
+		Jitter:          "1s",
+		FailureRate:     0,
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (fp *FakePower) UpdateConfig(cfg proto.Message) (e error) {
+	if fcfg, ok := cfg.(*pb.FakePowerConfig); ok {
+		fp.cfg = fcfg
+		if fp.pollTicker != nil {
+			fp.pollTicker.Stop()
+			dur, _ := time.ParseDuration(fp.cfg.GetPollingInterval())
+			fp.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks that the simulated BMCs are polled on a positive interval
+func (*FakePower) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.FakePowerConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*FakePower) ConfigURL() string {
+	cfg := &pb.FakePowerConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*FakePower)(nil)
+var _ lib.ModuleWithDiscovery = (*FakePower)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (fp *FakePower) SetMutationChan(c <-chan lib.Event) { fp.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (fp *FakePower) SetDiscoveryChan(c chan<- lib.Event) { fp.dchan = c }
+
+/*
+ * lib.ModuleWithRediscover
+ */
+var _ lib.ModuleWithRediscover = (*FakePower)(nil)
+
+// Rediscover polls the power state of nodes (or every node) now, rather than waiting for the next poll
+func (fp *FakePower) Rediscover(nodes []string) { fp.discoverNodes(nodes) }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*FakePower)(nil)
+
+// Entry is the module's executable entrypoint
+func (fp *FakePower) Entry() {
+	url := lib.NodeURLJoin(fp.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "fakepower"), "State"))
+	fp.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  fp.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(fp.cfg.GetPollingInterval())
+	fp.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-fp.pollTicker.C:
+			go fp.discoverAll()
+			break
+		case m := <-fp.mchan: // mutation request
+			go fp.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (fp *FakePower) Init(api lib.APIClient) {
+	fp.api = api
+	fp.cfg = fp.NewConfig().(*pb.FakePowerConfig)
+	fp.state = make(map[string]cpb.Node_PhysState)
+}
+
+// Stop should perform a graceful exit
+func (fp *FakePower) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (fp *FakePower) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		fp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	id := me.NodeCfg.ID()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			fp.discover(id, cpb.Node_PhysState_name[int32(fp.get(id))], time.Now())
+		case "OFFtoON":
+			fp.transition(me.Context, id, cpb.Node_POWER_ON, fp.cfg.GetOnLatency())
+		case "ONtoOFF":
+			fp.transition(me.Context, id, cpb.Node_POWER_OFF, fp.cfg.GetOffLatency())
+		case "HANGtoOFF":
+			fp.transition(me.Context, id, cpb.Node_POWER_OFF, fp.cfg.GetOffLatency())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			fp.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which ends any transition in progress
+		break
+	}
+}
+
+// get returns the simulated state of a node, making one up if we haven't seen it before
+func (fp *FakePower) get(id lib.NodeID) cpb.Node_PhysState {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	s, ok := fp.state[id.String()]
+	if !ok {
+		switch fp.cfg.GetInitialState() {
+		case "POWER_ON":
+			s = cpb.Node_POWER_ON
+		case "random":
+			s = cpb.Node_POWER_OFF
+			if rand.Intn(2) == 1 {
+				s = cpb.Node_POWER_ON
+			}
+		default:
+			s = cpb.Node_POWER_OFF
+		}
+		fp.state[id.String()] = s
+	}
+	return s
+}
+
+func (fp *FakePower) set(id lib.NodeID, s cpb.Node_PhysState) {
+	fp.mutex.Lock()
+	fp.state[id.String()] = s
+	fp.mutex.Unlock()
+}
+
+// transition simulates a power change: after the latency it either succeeds,
+// or (with probability failure_rate) leaves the node hung and reports nothing.
+// If ctx is canceled first, the node is left as it was, and we report that.
+func (fp *FakePower) transition(ctx context.Context, id lib.NodeID, to cpb.Node_PhysState, latency string) {
+	d, _ := time.ParseDuration(latency)
+	if j, _ := time.ParseDuration(fp.cfg.GetJitter()); j > 0 {
+		d += time.Duration(rand.Int63n(int64(j)))
+	}
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+		fp.api.Logf(lib.LLINFO, "interrupted: transition of %s to %s", id.String(), to.String())
+		fp.discover(id, cpb.Node_PhysState_name[int32(fp.get(id))], time.Now())
+		return
+	}
+	if rand.Float64() < fp.cfg.GetFailureRate() {
+		// the SME will time out and fail us to PHYS_HANG, which is what we'll report from now on
+		fp.set(id, cpb.Node_PHYS_HANG)
+		fp.api.Logf(lib.LLDEBUG, "simulating a failed mutation to %s for %s", to.String(), id.String())
+		return
+	}
+	fp.set(id, to)
+	fp.discover(id, cpb.Node_PhysState_name[int32(to)], time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (fp *FakePower) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  fp.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	fp.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (fp *FakePower) discoverAll() {
+	fp.api.Log(lib.LLDEBUG, "polling for node state")
+	fp.discoverNodes(nil)
+}
+
+// discoverNodes discovers the power state of the nodes with IDs ids, or of every node if ids is empty
+func (fp *FakePower) discoverNodes(ids []string) {
+	var ns []lib.Node
+	var e error
+	if len(ids) == 0 {
+		if ns, e = fp.api.QueryReadAll(); e != nil {
+			fp.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+			return
+		}
+	}
+	for _, id := range ids {
+		n, e := fp.api.QueryRead(id)
+		if e != nil {
+			fp.api.Logf(lib.LLERROR, "node query failed for %s: %v", id, e)
+			continue
+		}
+		ns = append(ns, n)
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform"})
+		if len(vs) != 1 || vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		fp.discover(n.ID(), cpb.Node_PhysState_name[int32(fp.get(n.ID()))], time.Now())
+	}
+}
+
+// initialization
+func init() {
+	module := &FakePower{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/fakepower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("fakepower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,245 @@
+/* gce.go: a minimal client for the Compute Engine API with service account auth
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package gcepower
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	GCEAPI      string = "https://compute.googleapis.com/compute/v1"
+	GCEScope    string = "https://www.googleapis.com/auth/compute"
+	MetadataURL string = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// gceKey is the part of a service account JSON key we need
+type gceKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type gceClient struct {
+	project string
+	key     *gceKey
+	rsa     *rsa.PrivateKey
+	client  *http.Client
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newGCEClient loads the service account key, if there is one
+func newGCEClient(project, credFile string) (c *gceClient, e error) {
+	c = &gceClient{
+		project: project,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	if credFile == "" {
+		return
+	}
+	b, e := ioutil.ReadFile(credFile)
+	if e != nil {
+		return nil, e
+	}
+	c.key = &gceKey{}
+	if e = json.Unmarshal(b, c.key); e != nil {
+		return nil, fmt.Errorf("invalid credentials file: %v", e)
+	}
+	blk, _ := pem.Decode([]byte(c.key.PrivateKey))
+	if blk == nil {
+		return nil, fmt.Errorf("no private key in credentials file")
+	}
+	k, e := x509.ParsePKCS8PrivateKey(blk.Bytes)
+	if e != nil {
+		return nil, fmt.Errorf("invalid private key: %v", e)
+	}
+	var ok bool
+	if c.rsa, ok = k.(*rsa.PrivateKey); !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return
+}
+
+// getToken returns a valid access token, fetching a new one if needed
+func (c *gceClient) getToken() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.token != "" && time.Now().Add(time.Minute).Before(c.expires) {
+		return c.token, nil
+	}
+	var req *http.Request
+	var e error
+	if c.key == nil {
+		req, e = http.NewRequest(http.MethodGet, MetadataURL, nil)
+		if e != nil {
+			return "", e
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+	} else {
+		var jwt string
+		if jwt, e = c.jwt(); e != nil {
+			return "", e
+		}
+		v := url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {jwt},
+		}
+		req, e = http.NewRequest(http.MethodPost, c.key.TokenURI, strings.NewReader(v.Encode()))
+		if e != nil {
+			return "", e
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return "", e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		rb, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("token request failed: HTTP %v: %s", resp.StatusCode, strings.TrimSpace(string(rb)))
+	}
+	var r struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if e = json.NewDecoder(resp.Body).Decode(&r); e != nil {
+		return "", e
+	}
+	c.token = r.AccessToken
+	c.expires = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// jwt makes a signed RS256 assertion for the token endpoint
+func (c *gceClient) jwt() (string, error) {
+	now := time.Now().Unix()
+	hdr, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": c.key.PrivateKeyID})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   c.key.ClientEmail,
+		"scope": GCEScope,
+		"aud":   c.key.TokenURI,
+		"iat":   now,
+		"exp":   now + 3600,
+	})
+	enc := base64.RawURLEncoding
+	signing := enc.EncodeToString(hdr) + "." + enc.EncodeToString(claims)
+	h := sha256.Sum256([]byte(signing))
+	sig, e := rsa.SignPKCS1v15(rand.Reader, c.rsa, crypto.SHA256, h[:])
+	if e != nil {
+		return "", e
+	}
+	return signing + "." + enc.EncodeToString(sig), nil
+}
+
+// do makes an authenticated request relative to the project's URL
+// the request is abandoned if ctx is canceled
+func (c *gceClient) do(ctx context.Context, method, path string, r interface{}) (e error) {
+	tok, e := c.getToken()
+	if e != nil {
+		return
+	}
+	req, e := http.NewRequest(method, GCEAPI+"/projects/"+url.PathEscape(c.project)+path, nil)
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+tok)
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		var ge struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(rb, &ge) == nil && ge.Error.Message != "" {
+			return fmt.Errorf("%s %s: %s", method, path, ge.Error.Message)
+		}
+		return fmt.Errorf("%s %s: HTTP %v", method, path, resp.StatusCode)
+	}
+	if r != nil {
+		return json.Unmarshal(rb, r)
+	}
+	return
+}
+
+func instancePath(zone, name string) string {
+	return "/zones/" + url.PathEscape(zone) + "/instances/" + url.PathEscape(name)
+}
+
+// Start starts an instance; it returns once the operation is accepted
+func (c *gceClient) Start(ctx context.Context, zone, name string) error {
+	return c.do(ctx, http.MethodPost, instancePath(zone, name)+"/start", nil)
+}
+
+// Stop stops an instance; it returns once the operation is accepted
+func (c *gceClient) Stop(ctx context.Context, zone, name string) error {
+	return c.do(ctx, http.MethodPost, instancePath(zone, name)+"/stop", nil)
+}
+
+// Status returns an instance's status, e.g. RUNNING or TERMINATED
+func (c *gceClient) Status(ctx context.Context, zone, name string) (string, error) {
+	var r struct {
+		Status string `json:"status"`
+	}
+	e := c.do(ctx, http.MethodGet, instancePath(zone, name), &r)
+	return r.Status, e
+}
+
+// StatusAll returns the status of every instance in a zone, by name
+func (c *gceClient) StatusAll(zone string) (map[string]string, error) {
+	st := make(map[string]string)
+	tok := ""
+	for {
+		var r struct {
+			Items []struct {
+				Name   string `json:"name"`
+				Status string `json:"status"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		path := "/zones/" + url.PathEscape(zone) + "/instances"
+		if tok != "" {
+			path += "?pageToken=" + url.QueryEscape(tok)
+		}
+		if e := c.do(context.Background(), http.MethodGet, path, &r); e != nil {
+			return nil, e
+		}
+		for _, i := range r.Items {
+			st[i.Name] = i.Status
+		}
+		if r.NextPageToken == "" {
+			return st, nil
+		}
+		tok = r.NextPageToken
+	}
+}
@@ -0,0 +1,475 @@
+/* gcepower.go: mutations for Google Compute Engine instances using the Compute API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/gcepower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = gce.
+ */
+
+package gcepower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/gcepower/proto"
+)
+
+const (
+	PlatformString string = "gce"
+
+	// transitionWait is how long we poll an instance for its target state, it matches the mutation timeouts
+	transitionWait = 120 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "120s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s", // GCE forces a stop after 90s
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////
+// GCE Object /
+//////////////
+
+// GCE provides a power on/off interface to Compute Engine instances
+type GCE struct {
+	api        lib.APIClient
+	cfg        *pb.GCEConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	gce        *gceClient
+	gceCfg     *pb.GCEConfig // the config gce was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*GCE)(nil)
+
+// Name returns the FQDN of the module
+func (*GCE) Name() string { return "github.com/hpc/kraken/modules/gcepower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*GCE)(nil)
+var _ lib.ModuleWithConfigValidation = (*GCE)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*GCE) NewConfig() proto.Message {
+	r := &pb.GCEConfig{
+		Zone:            "us-central1-a",
+		InstanceUrl:     "type.googleapis.com/proto.GCE/Instance",
+		ZoneUrl:         "type.googleapis.com/proto.GCE/Zone",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (gc *GCE) UpdateConfig(cfg proto.Message) (e error) {
+	if gcfg, ok := cfg.(*pb.GCEConfig); ok {
+		gc.cfg = gcfg
+		if gc.pollTicker != nil {
+			gc.pollTicker.Stop()
+			dur, _ := time.ParseDuration(gc.cfg.GetPollingInterval())
+			gc.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig makes sure instances are polled on a positive interval
+func (*GCE) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.GCEConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*GCE) ConfigURL() string {
+	cfg := &pb.GCEConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*GCE)(nil)
+var _ lib.ModuleWithDiscovery = (*GCE)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (gc *GCE) SetMutationChan(c <-chan lib.Event) { gc.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (gc *GCE) SetDiscoveryChan(c chan<- lib.Event) { gc.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*GCE)(nil)
+
+// Entry is the module's executable entrypoint
+func (gc *GCE) Entry() {
+	url := lib.NodeURLJoin(gc.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "gcepower"), "State"))
+	gc.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  gc.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(gc.cfg.GetPollingInterval())
+	gc.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-gc.pollTicker.C:
+			go gc.discoverAll()
+			break
+		case m := <-gc.mchan: // mutation request
+			go gc.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (gc *GCE) Init(api lib.APIClient) {
+	gc.api = api
+	gc.cfg = gc.NewConfig().(*pb.GCEConfig)
+}
+
+// Stop should perform a graceful exit
+func (gc *GCE) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (gc *GCE) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		gc.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	zone, name, ok := gc.instance(me.NodeCfg)
+	if !ok {
+		gc.api.Logf(lib.LLERROR, "could not get GCE instance name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			gc.instanceDiscover(zone, name, me.NodeCfg.ID())
+		case "OFFtoON":
+			gc.instanceControl(me.Context, zone, name, true, me.NodeCfg.ID())
+		case "ONtoOFF":
+			gc.instanceControl(me.Context, zone, name, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			gc.instanceControl(me.Context, zone, name, false, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			gc.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// instance gets a node's zone and instance name
+// the zone comes from zone_url if the node has it, otherwise the config
+func (gc *GCE) instance(n lib.Node) (zone, name string, ok bool) {
+	vs := n.GetValues([]string{gc.cfg.GetInstanceUrl(), gc.cfg.GetZoneUrl()})
+	v, ok := vs[gc.cfg.GetInstanceUrl()]
+	if !ok || v.String() == "" {
+		return "", "", false
+	}
+	zone = gc.cfg.GetZone()
+	if z, ok := vs[gc.cfg.GetZoneUrl()]; ok && z.String() != "" {
+		zone = z.String()
+	}
+	return zone, v.String(), true
+}
+
+// client returns the GCE client, creating it if the config has changed
+func (gc *GCE) client() (*gceClient, error) {
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+	if gc.gce == nil || gc.gceCfg != gc.cfg {
+		c, e := newGCEClient(gc.cfg.GetProject(), gc.cfg.GetCredentialsFile())
+		if e != nil {
+			return nil, e
+		}
+		gc.gce = c
+		gc.gceCfg = gc.cfg
+	}
+	return gc.gce, nil
+}
+
+// instanceState maps a GCE instance status to a PhysState value ID
+// TERMINATED is what GCE calls a stopped instance
+func instanceState(s string) string {
+	switch s {
+	case "RUNNING":
+		return "POWER_ON"
+	case "TERMINATED", "STOPPED":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (gc *GCE) instanceDiscover(zone, name string, id lib.NodeID) {
+	c, e := gc.client()
+	if e != nil {
+		gc.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	start := time.Now()
+	s, e := c.Status(context.Background(), zone, name)
+	if e != nil {
+		gc.api.Logf(lib.LLERROR, "failed to get status of instance %s: %v", name, e)
+		return
+	}
+	gc.discover(id, instanceState(s), start)
+}
+
+// instanceControl starts or stops an instance, and waits for it to get there
+// if the mutation is interrupted, we report whatever state the instance was left in instead
+func (gc *GCE) instanceControl(ctx context.Context, zone, name string, on bool, id lib.NodeID) {
+	c, e := gc.client()
+	if e != nil {
+		gc.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	target := "TERMINATED"
+	if on {
+		target = "RUNNING"
+		e = c.Start(ctx, zone, name)
+	} else {
+		e = c.Stop(ctx, zone, name)
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			gc.api.Logf(lib.LLINFO, "interrupted: changing power of instance %s", name)
+			gc.instanceDiscover(zone, name, id)
+			return
+		}
+		gc.api.Logf(lib.LLERROR, "failed to change power of instance %s: %v", name, e)
+		return
+	}
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			gc.api.Logf(lib.LLINFO, "interrupted: waiting for instance %s to be %s", name, target)
+			gc.instanceDiscover(zone, name, id)
+			return
+		}
+		at := time.Now()
+		s, e := c.Status(ctx, zone, name)
+		if e != nil {
+			gc.api.Logf(lib.LLDEBUG, "failed to get status of instance %s: %v", name, e)
+			continue
+		}
+		if s == target {
+			gc.discover(id, instanceState(s), at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	gc.api.Logf(lib.LLERROR, "instance %s did not reach %s after %s", name, target, transitionWait.String())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (gc *GCE) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  gc.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	gc.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// instances are listed once per zone
+func (gc *GCE) discoverAll() {
+	gc.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := gc.api.QueryReadAll()
+	if e != nil {
+		gc.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	byZone := make(map[string]map[string]lib.NodeID)
+
+	// build lists
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform"})
+		if len(vs) != 1 || vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		zone, name, ok := gc.instance(n)
+		if !ok {
+			gc.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete GCE info", n.ID().String())
+			continue
+		}
+		if _, ok := byZone[zone]; !ok {
+			byZone[zone] = make(map[string]lib.NodeID)
+		}
+		byZone[zone][name] = n.ID()
+	}
+	if len(byZone) == 0 {
+		return
+	}
+
+	c, e := gc.client()
+	if e != nil {
+		gc.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	for zone, names := range byZone {
+		start := time.Now()
+		st, e := c.StatusAll(zone)
+		if e != nil {
+			gc.api.Logf(lib.LLERROR, "failed to list instances in %s: %v", zone, e)
+			continue
+		}
+		for name, id := range names {
+			s, ok := st[name]
+			if !ok {
+				gc.api.Logf(lib.LLERROR, "instance %s not found in %s", name, zone)
+				continue
+			}
+			gc.discover(id, instanceState(s), start)
+		}
+	}
+}
+
+// initialization
+func init() {
+	module := &GCE{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/gcepower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("gcepower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,102 @@
+/* audit.go: records who changed what through the gRPC API in Kraken's audit log
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"path"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// auditTargets are the calls that change state, and how to get the IDs of the nodes each of them could change
+var auditTargets = map[string]func(req interface{}) []string{
+	"/kraken.v1.Kraken/CreateNode": func(req interface{}) []string {
+		return []string{core.NewNodeFromMessage(req.(*cpb.Node)).ID().String()}
+	},
+	"/kraken.v1.Kraken/UpdateNode": func(req interface{}) []string {
+		return []string{core.NewNodeFromMessage(req.(*cpb.Node)).ID().String()}
+	},
+	"/kraken.v1.Kraken/DeleteNode": func(req interface{}) []string {
+		return []string{req.(*cpb.NodeRequest).GetId()}
+	},
+	"/kraken.v1.Kraken/SetValues": func(req interface{}) (ids []string) {
+		for _, v := range req.(*cpb.SetValuesRequest).GetValues() {
+			ids = append(ids, v.GetId())
+		}
+		return
+	},
+	"/kraken.v1.Kraken/AddToGroup": func(req interface{}) []string {
+		return req.(*cpb.GroupRequest).GetIds()
+	},
+	"/kraken.v1.Kraken/RemoveFromGroup": func(req interface{}) []string {
+		return req.(*cpb.GroupRequest).GetIds()
+	},
+}
+
+// audit makes a call that changes state, and records who made it, from where, what it changed on each node, and how it went.
+// Calls that weren't authorized are recorded too.
+func (g *GRPCAPI) audit(ctx context.Context, req interface{}, method, user string, call func() (interface{}, error)) (interface{}, error) {
+	ids := auditTargets[method](req)
+	before := g.auditRead(ids)
+	now := ptypes.TimestampNow()
+	rsp, e := call()
+
+	rec := cpb.AuditRecord{
+		Time:   now,
+		User:   user,
+		Module: "grpcapi",
+		Action: path.Base(method),
+		Result: "ok",
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		rec.Source = p.Addr.String()
+	}
+	if e != nil {
+		s, _ := status.FromError(e)
+		rec.Result = s.Code().String() + ": " + s.Message()
+	}
+	after := g.auditRead(ids)
+	var rs []*cpb.AuditRecord
+	for _, id := range ids {
+		cs := core.NewAuditChanges(before[id], after[id])
+		if len(cs) == 0 {
+			continue
+		}
+		nr := rec
+		nr.Node, nr.Changes = id, cs
+		rs = append(rs, &nr)
+	}
+	g.api.Logf(lib.LLINFO, "audit: %s by %s from %s changed %d node(s): %s", rec.Action, rec.User, rec.Source, len(rs), rec.Result)
+	if len(rs) == 0 {
+		// it's still worth knowing who tried
+		rs = append(rs, &rec)
+	}
+	if ae := g.api.QueryAudit(rs); ae != nil {
+		g.api.Logf(lib.LLERROR, "failed to record %s by %s in the audit log: %v", rec.Action, rec.User, ae)
+	}
+	return rsp, e
+}
+
+// auditRead gets the configuration state of nodes, by ID; nodes that don't exist are left out
+func (g *GRPCAPI) auditRead(ids []string) map[string]lib.Node {
+	ns := make(map[string]lib.Node)
+	for _, id := range ids {
+		if n, e := g.api.QueryRead(id); e == nil && n != nil {
+			ns[id] = n
+		}
+	}
+	return ns
+}
@@ -0,0 +1,462 @@
+/* grpcapi.go: serves the external gRPC API (kraken.v1.Kraken), for automation that would rather not go through JSON
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/grpcapi/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+var _ lib.Module = (*GRPCAPI)(nil)
+var _ lib.ModuleSelfService = (*GRPCAPI)(nil)
+var _ lib.ModuleWithConfig = (*GRPCAPI)(nil)
+var _ lib.ModuleWithConfigValidation = (*GRPCAPI)(nil)
+var _ lib.ModuleWithShutdown = (*GRPCAPI)(nil)
+var _ cpb.KrakenServer = (*GRPCAPI)(nil)
+
+// GRPCAPI serves kraken.v1.Kraken by passing calls on to the (internal) API
+type GRPCAPI struct {
+	cfg      *pb.GRPCAPIConfig
+	api      lib.APIClient
+	mutex    sync.Mutex
+	srv      *grpc.Server
+	stopping bool
+}
+
+/*
+ * lib.Module
+ */
+
+func (g *GRPCAPI) Name() string { return "github.com/hpc/kraken/modules/grpcapi" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+
+func (g *GRPCAPI) NewConfig() proto.Message {
+	return &pb.GRPCAPIConfig{
+		Addr: "127.0.0.1",
+		Port: 31416,
+	}
+}
+
+func (g *GRPCAPI) UpdateConfig(cfg proto.Message) (e error) {
+	gc, ok := cfg.(*pb.GRPCAPIConfig)
+	if !ok {
+		return fmt.Errorf("wrong config type")
+	}
+	g.mutex.Lock()
+	g.cfg = gc
+	srv := g.srv
+	g.mutex.Unlock()
+	if srv != nil {
+		srv.Stop() // we just stop, entry will (re)start
+	}
+	return
+}
+
+func (g *GRPCAPI) ConfigURL() string {
+	a, _ := ptypes.MarshalAny(g.NewConfig())
+	return a.GetTypeUrl()
+}
+
+// ValidateConfig rejects ports outside 1-65535, and TLS cert/key/CA files that don't load
+func (g *GRPCAPI) ValidateConfig(cfg proto.Message) (e error) {
+	gc, ok := cfg.(*pb.GRPCAPIConfig)
+	if !ok {
+		return fmt.Errorf("wrong config type")
+	}
+	if gc.GetPort() < 1 || gc.GetPort() > 65535 {
+		return fmt.Errorf("invalid port: %d", gc.GetPort())
+	}
+	if tc := gc.GetTls(); tc.GetCert() != "" || tc.GetKey() != "" || tc.GetClientCa() != "" {
+		if _, e = tlsConfig(tc); e != nil {
+			return fmt.Errorf("bad TLS config: %v", e)
+		}
+	}
+	return
+}
+
+/*
+ * lib.ModuleSelfService
+ */
+
+func (g *GRPCAPI) Init(api lib.APIClient) {
+	g.api = api
+	if g.cfg == nil {
+		g.cfg = g.NewConfig().(*pb.GRPCAPIConfig)
+	}
+}
+
+func (g *GRPCAPI) Entry() {
+	for {
+		g.serve()
+		g.mutex.Lock()
+		stopping := g.stopping
+		g.mutex.Unlock()
+		if stopping {
+			select {} // we're about to exit
+		}
+	}
+}
+
+func (g *GRPCAPI) Stop() { os.Exit(0) }
+
+// Shutdown lets the calls we're handling finish, but ends streams (e.g. Watch) at the deadline
+func (g *GRPCAPI) Shutdown(ctx context.Context) error {
+	g.mutex.Lock()
+	g.stopping = true
+	srv := g.srv
+	g.mutex.Unlock()
+	if srv == nil {
+		return nil
+	}
+	done := make(chan bool)
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// serve serves the API until the server is stopped
+func (g *GRPCAPI) serve() {
+	g.mutex.Lock()
+	cfg := g.cfg
+	g.mutex.Unlock()
+	if !cfg.GetAuthDisabled() && len(cfg.GetTokens()) == 0 {
+		g.api.Log(lib.LLWARNING, "grpcapi has no tokens, so every call will be rejected")
+	}
+	if cfg.GetAuthDisabled() {
+		g.api.Log(lib.LLWARNING, "grpcapi authentication is disabled")
+	}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+			user, e := authorize(ctx, cfg)
+			call := func() (interface{}, error) {
+				if e != nil {
+					return nil, e
+				}
+				return h(ctx, req)
+			}
+			if _, ok := auditTargets[info.FullMethod]; ok {
+				return g.audit(ctx, req, info.FullMethod, user, call)
+			}
+			return call()
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if _, e := authorize(ss.Context(), cfg); e != nil {
+				return e
+			}
+			return h(srv, ss)
+		}),
+	}
+	tc := cfg.GetTls()
+	if tc.GetCert() != "" || tc.GetKey() != "" {
+		c, e := tlsConfig(tc)
+		if e != nil {
+			g.api.Logf(lib.LLERROR, "bad TLS config: %v", e)
+			time.Sleep(time.Second) // don't spin if we can't start
+			return
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(c)))
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.GetAddr(), cfg.GetPort())
+	l, e := net.Listen("tcp", addr)
+	if e != nil {
+		g.api.Logf(lib.LLERROR, "grpcapi couldn't listen: %v", e)
+		time.Sleep(time.Second) // don't spin if we can't start
+		return
+	}
+	srv := grpc.NewServer(opts...)
+	cpb.RegisterKrakenServer(srv, g)
+	reflection.Register(srv)
+	g.mutex.Lock()
+	g.srv = srv
+	g.mutex.Unlock()
+	g.api.Logf(lib.LLINFO, "grpcapi is listening on: %s", addr)
+	if e = srv.Serve(l); e != nil {
+		g.api.Logf(lib.LLNOTICE, "grpcapi stopped: %v", e)
+	}
+	g.mutex.Lock()
+	g.srv = nil
+	g.mutex.Unlock()
+	g.api.Log(lib.LLNOTICE, "grpcapi listener stopped")
+}
+
+// authorize checks a call's bearer token, and gets who it's from
+func authorize(ctx context.Context, cfg *pb.GRPCAPIConfig) (user string, e error) {
+	if cfg.GetAuthDisabled() {
+		return "anonymous", nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, a := range md.Get("authorization") {
+		if !strings.HasPrefix(a, "Bearer ") {
+			continue
+		}
+		token := []byte(strings.TrimPrefix(a, "Bearer "))
+		for _, t := range cfg.GetTokens() {
+			if subtle.ConstantTimeCompare(token, []byte(t)) == 1 {
+				return tokenName(token), nil
+			}
+		}
+	}
+	return "anonymous", status.Error(codes.Unauthenticated, "a valid bearer token is required")
+}
+
+// tokenName names a token, so what's done with it can be told apart from what's done with other tokens, without giving it away
+func tokenName(t []byte) string {
+	h := sha256.Sum256(t)
+	return "token:" + hex.EncodeToString(h[:4])
+}
+
+// tlsConfig makes the TLS config for serving with tc, requiring client certificates if there's a client CA
+func tlsConfig(tc *pb.GRPCAPITLS) (*tls.Config, error) {
+	if tc.GetCert() == "" || tc.GetKey() == "" {
+		return nil, fmt.Errorf("TLS needs both a cert and a key")
+	}
+	kp, e := tls.LoadX509KeyPair(tc.GetCert(), tc.GetKey())
+	if e != nil {
+		return nil, e
+	}
+	c := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{kp}}
+	if tc.GetClientCa() == "" {
+		return c, nil
+	}
+	b, e := ioutil.ReadFile(tc.GetClientCa())
+	if e != nil {
+		return nil, fmt.Errorf("could not read client CA: %v", e)
+	}
+	c.ClientCAs = x509.NewCertPool()
+	if !c.ClientCAs.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates in client CA: %s", tc.GetClientCa())
+	}
+	c.ClientAuth = tls.RequireAndVerifyClientCert
+	return c, nil
+}
+
+/*
+ * cpb.KrakenServer
+ */
+
+func (g *GRPCAPI) GetNode(ctx context.Context, in *cpb.NodeRequest) (*cpb.Node, error) {
+	var n lib.Node
+	var e error
+	if in.State == cpb.StateKind_DSC {
+		n, e = g.api.QueryReadDsc(in.Id)
+	} else {
+		n, e = g.api.QueryRead(in.Id)
+	}
+	if e != nil || n == nil {
+		return nil, status.Errorf(codes.NotFound, "no such node: %s", in.Id)
+	}
+	return n.Message().(*cpb.Node), nil
+}
+
+func (g *GRPCAPI) ListNodes(ctx context.Context, in *cpb.ListNodesRequest) (*cpb.NodeList, error) {
+	dsc := in.State == cpb.StateKind_DSC
+	var ns []lib.Node
+	var e error
+	switch {
+	case in.Select != "" && dsc:
+		ns, e = g.api.QuerySelectDsc(in.Select)
+	case in.Select != "":
+		ns, e = g.api.QuerySelect(in.Select)
+	case in.Group != "" && dsc:
+		ns, e = g.api.QueryReadGroupDsc(in.Group)
+	case in.Group != "":
+		ns, e = g.api.QueryReadGroup(in.Group)
+	case dsc:
+		ns, e = g.api.QueryReadAllDsc()
+	default:
+		ns, e = g.api.QueryReadAll()
+	}
+	if e != nil {
+		return nil, status.Error(codes.InvalidArgument, e.Error())
+	}
+	out := &cpb.NodeList{}
+	for _, n := range ns {
+		if in.Group != "" && !n.InGroup(in.Group) {
+			continue
+		}
+		out.Nodes = append(out.Nodes, n.Message().(*cpb.Node))
+	}
+	return out, nil
+}
+
+func (g *GRPCAPI) CreateNode(ctx context.Context, in *cpb.Node) (*cpb.Node, error) {
+	n, e := g.api.QueryCreate(core.NewNodeFromMessage(in))
+	if e != nil {
+		return nil, status.Error(codes.AlreadyExists, e.Error())
+	}
+	return n.Message().(*cpb.Node), nil
+}
+
+func (g *GRPCAPI) UpdateNode(ctx context.Context, in *cpb.Node) (*cpb.Node, error) {
+	n, e := g.api.QueryUpdate(core.NewNodeFromMessage(in))
+	if e != nil {
+		return nil, status.Error(codes.FailedPrecondition, e.Error())
+	}
+	return n.Message().(*cpb.Node), nil
+}
+
+func (g *GRPCAPI) DeleteNode(ctx context.Context, in *cpb.NodeRequest) (*cpb.Node, error) {
+	n, e := g.api.QueryDelete(in.Id)
+	if e != nil || n == nil {
+		return nil, status.Errorf(codes.NotFound, "no such node: %s", in.Id)
+	}
+	return n.Message().(*cpb.Node), nil
+}
+
+func (g *GRPCAPI) SetValues(ctx context.Context, in *cpb.SetValuesRequest) (*empty.Empty, error) {
+	if len(in.Values) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "nothing to set")
+	}
+	vs := make(map[string]reflect.Value)
+	for _, v := range in.Values {
+		n, e := g.api.QueryRead(v.Id)
+		if e != nil || n == nil {
+			return nil, status.Errorf(codes.NotFound, "no such node: %s", v.Id)
+		}
+		cur, e := n.GetValue(v.Url)
+		if e == nil {
+			vs[lib.NodeURLJoin(v.Id, v.Url)], e = core.ValueFromString(cur.Type(), v.Value)
+		}
+		if e != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s:%s: %v", v.Id, v.Url, e)
+		}
+	}
+	if e := g.api.QueryTransact(vs); e != nil {
+		return nil, status.Error(codes.FailedPrecondition, e.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (g *GRPCAPI) GetMutationPath(ctx context.Context, in *cpb.NodeRequest) (*cpb.MutationPath, error) {
+	mp, e := g.api.QueryNodeMutationPath(in.Id)
+	if e != nil {
+		return nil, status.Error(codes.NotFound, e.Error())
+	}
+	return &mp, nil
+}
+
+func (g *GRPCAPI) ListGroups(ctx context.Context, in *empty.Empty) (*cpb.GroupList, error) {
+	ns, e := g.api.QueryReadAll()
+	if e != nil {
+		return nil, status.Error(codes.Internal, e.Error())
+	}
+	out := &cpb.GroupList{Groups: make(map[string]*cpb.GroupMembers)}
+	for _, n := range ns {
+		for _, gr := range n.GetGroups() {
+			if out.Groups[gr] == nil {
+				out.Groups[gr] = &cpb.GroupMembers{}
+			}
+			out.Groups[gr].Ids = append(out.Groups[gr].Ids, n.ID().String())
+		}
+	}
+	return out, nil
+}
+
+func (g *GRPCAPI) AddToGroup(ctx context.Context, in *cpb.GroupRequest) (*cpb.NodeList, error) {
+	return g.changeGroup(in, lib.Node.AddGroup)
+}
+
+func (g *GRPCAPI) RemoveFromGroup(ctx context.Context, in *cpb.GroupRequest) (*cpb.NodeList, error) {
+	return g.changeGroup(in, lib.Node.DelGroup)
+}
+
+// changeGroup makes change (adding or removing the group) to each of the nodes in a GroupRequest
+func (g *GRPCAPI) changeGroup(in *cpb.GroupRequest, change func(lib.Node, string)) (*cpb.NodeList, error) {
+	if in.Group == "" || len(in.Ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "need a group, and nodes")
+	}
+	ns := []lib.Node{}
+	for _, id := range in.Ids {
+		n, e := g.api.QueryRead(id)
+		if e != nil || n == nil {
+			return nil, status.Errorf(codes.NotFound, "no such node: %s", id)
+		}
+		ns = append(ns, n)
+	}
+	out := &cpb.NodeList{}
+	for _, n := range ns {
+		change(n, in.Group)
+		n, e := g.api.QueryUpdate(n)
+		if e != nil {
+			return nil, status.Error(codes.FailedPrecondition, e.Error())
+		}
+		out.Nodes = append(out.Nodes, n.Message().(*cpb.Node))
+	}
+	return out, nil
+}
+
+func (g *GRPCAPI) Watch(in *cpb.WatchRequest, stream cpb.Kraken_WatchServer) error {
+	c, e := g.api.WatchContext(stream.Context(), in)
+	if e != nil {
+		return status.Error(codes.Unavailable, e.Error())
+	}
+	for sc := range c {
+		if e = stream.Send(sc); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// initialization
+func init() {
+	module := &GRPCAPI{}
+	core.Registry.RegisterModule(module)
+	si := core.NewServiceInstance(
+		"grpcapi",
+		module.Name(),
+		module.Entry,
+		nil,
+	)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{
+		si.ID(): si,
+	})
+}
@@ -0,0 +1,169 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: grpcapi.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type GRPCAPIConfig struct {
+	Addr                 string      `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Port                 int32       `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Tokens               []string    `protobuf:"bytes,3,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	AuthDisabled         bool        `protobuf:"varint,4,opt,name=auth_disabled,json=authDisabled,proto3" json:"auth_disabled,omitempty"`
+	Tls                  *GRPCAPITLS `protobuf:"bytes,5,opt,name=tls,proto3" json:"tls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GRPCAPIConfig) Reset()         { *m = GRPCAPIConfig{} }
+func (m *GRPCAPIConfig) String() string { return proto.CompactTextString(m) }
+func (*GRPCAPIConfig) ProtoMessage()    {}
+func (*GRPCAPIConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_grpcapi_e3577710baf4a2ca, []int{0}
+}
+func (m *GRPCAPIConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GRPCAPIConfig.Unmarshal(m, b)
+}
+func (m *GRPCAPIConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GRPCAPIConfig.Marshal(b, m, deterministic)
+}
+func (dst *GRPCAPIConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GRPCAPIConfig.Merge(dst, src)
+}
+func (m *GRPCAPIConfig) XXX_Size() int {
+	return xxx_messageInfo_GRPCAPIConfig.Size(m)
+}
+func (m *GRPCAPIConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_GRPCAPIConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GRPCAPIConfig proto.InternalMessageInfo
+
+func (m *GRPCAPIConfig) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *GRPCAPIConfig) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *GRPCAPIConfig) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *GRPCAPIConfig) GetAuthDisabled() bool {
+	if m != nil {
+		return m.AuthDisabled
+	}
+	return false
+}
+
+func (m *GRPCAPIConfig) GetTls() *GRPCAPITLS {
+	if m != nil {
+		return m.Tls
+	}
+	return nil
+}
+
+// If a cert and key are set, the API is served over TLS
+type GRPCAPITLS struct {
+	Cert                 string   `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ClientCa             string   `protobuf:"bytes,3,opt,name=client_ca,json=clientCa,proto3" json:"client_ca,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GRPCAPITLS) Reset()         { *m = GRPCAPITLS{} }
+func (m *GRPCAPITLS) String() string { return proto.CompactTextString(m) }
+func (*GRPCAPITLS) ProtoMessage()    {}
+func (*GRPCAPITLS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_grpcapi_e3577710baf4a2ca, []int{1}
+}
+func (m *GRPCAPITLS) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GRPCAPITLS.Unmarshal(m, b)
+}
+func (m *GRPCAPITLS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GRPCAPITLS.Marshal(b, m, deterministic)
+}
+func (dst *GRPCAPITLS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GRPCAPITLS.Merge(dst, src)
+}
+func (m *GRPCAPITLS) XXX_Size() int {
+	return xxx_messageInfo_GRPCAPITLS.Size(m)
+}
+func (m *GRPCAPITLS) XXX_DiscardUnknown() {
+	xxx_messageInfo_GRPCAPITLS.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GRPCAPITLS proto.InternalMessageInfo
+
+func (m *GRPCAPITLS) GetCert() string {
+	if m != nil {
+		return m.Cert
+	}
+	return ""
+}
+
+func (m *GRPCAPITLS) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GRPCAPITLS) GetClientCa() string {
+	if m != nil {
+		return m.ClientCa
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GRPCAPIConfig)(nil), "proto.GRPCAPIConfig")
+	proto.RegisterType((*GRPCAPITLS)(nil), "proto.GRPCAPITLS")
+}
+
+func init() { proto.RegisterFile("grpcapi.proto", fileDescriptor_grpcapi_e3577710baf4a2ca) }
+
+var fileDescriptor_grpcapi_e3577710baf4a2ca = []byte{
+	// 215 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x8e, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x40, 0x89, 0xe9, 0x96, 0x66, 0x74, 0x41, 0xe7, 0x20, 0x01, 0x2f, 0xa1, 0xbd, 0xe4, 0xd4,
+	0x83, 0x7e, 0x81, 0xac, 0x20, 0x82, 0x60, 0x89, 0xde, 0x4b, 0x9a, 0xc4, 0x1a, 0xba, 0x6c, 0x42,
+	0x36, 0x1e, 0xfc, 0x14, 0xff, 0x56, 0x92, 0x5d, 0xe9, 0x69, 0xde, 0xbc, 0x39, 0xcc, 0x83, 0xf6,
+	0x98, 0xa2, 0xd1, 0xd1, 0x6f, 0x63, 0x0a, 0x39, 0x60, 0x53, 0xc7, 0xfa, 0x97, 0x40, 0xfb, 0xac,
+	0x76, 0xdd, 0xe3, 0xee, 0xa5, 0x0b, 0xc3, 0xa7, 0x3f, 0x22, 0xc2, 0x42, 0x5b, 0x9b, 0x38, 0x11,
+	0x44, 0x32, 0x55, 0xb9, 0xb8, 0x18, 0x52, 0xe6, 0x17, 0x82, 0xc8, 0x46, 0x55, 0xc6, 0x5b, 0x58,
+	0xe6, 0x70, 0x72, 0xc3, 0xc8, 0xa9, 0xa0, 0x92, 0xa9, 0x79, 0xc3, 0x0d, 0xb4, 0xfa, 0x3b, 0x7f,
+	0xed, 0xad, 0x1f, 0xf5, 0xa1, 0x77, 0x96, 0x2f, 0x04, 0x91, 0x2b, 0x75, 0x55, 0xe4, 0xd3, 0xec,
+	0x70, 0x03, 0x34, 0xf7, 0x23, 0x6f, 0x04, 0x91, 0x97, 0xf7, 0x37, 0x53, 0xd2, 0x76, 0xee, 0xf8,
+	0x78, 0x7d, 0x57, 0xe5, 0xba, 0x7e, 0x03, 0x38, 0xab, 0xd2, 0x60, 0x5c, 0xca, 0xff, 0x5d, 0x85,
+	0xf1, 0x1a, 0xe8, 0xc9, 0xfd, 0xd4, 0x2c, 0xa6, 0x0a, 0xe2, 0x1d, 0x30, 0xd3, 0x7b, 0x37, 0xe4,
+	0xbd, 0xd1, 0x9c, 0x56, 0xbf, 0x9a, 0x44, 0xa7, 0x0f, 0xcb, 0xfa, 0xe7, 0xe1, 0x6f, 0x00, 0xc5,
+	0x96, 0x70, 0x08, 0x0b, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,538 @@
+/* idracpower.go: mutations for Dell iDRAC BMCs using the iDRAC Redfish API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/idracpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = idrac.
+ */
+
+package idracpower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/idracpower/proto"
+)
+
+const (
+	PlatformString string = "idrac"
+
+	RFSystems string = "/redfish/v1/Systems/"
+	RFReset   string = "/Actions/ComputerSystem.Reset"
+	// DefaultSystem is the system ID of every iDRAC we know of
+	DefaultSystem string = "System.Embedded.1"
+
+	// shutdownWait is how long we wait for a graceful shutdown, it matches the ONtoOFF timeout
+	shutdownWait = 120 * time.Second
+)
+
+// idracSystem is the part of a Redfish ComputerSystem we care about
+type idracSystem struct {
+	PowerState string `json:"PowerState"`
+	Actions    struct {
+		Reset struct {
+			Allowed []string `json:"ResetType@Redfish.AllowableValues"`
+		} `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s", // graceful shutdown
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"HANGtoON": { // forced power cycle, so a hung node doesn't have to go through OFF
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+//////////////////
+// IDRAC Object /
+////////////////
+
+// IDRAC provides a power on/off interface to Dell iDRAC BMCs
+type IDRAC struct {
+	api        lib.APIClient
+	cfg        *pb.IDRACConfig
+	clients    map[bool]*http.Client // by whether the iDRAC's certificate is verified; see newClients
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*IDRAC)(nil)
+
+// Name returns the FQDN of the module
+func (*IDRAC) Name() string { return "github.com/hpc/kraken/modules/idracpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*IDRAC)(nil)
+var _ lib.ModuleWithConfigValidation = (*IDRAC)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*IDRAC) NewConfig() proto.Message {
+	r := &pb.IDRACConfig{
+		Nodes:           map[string]*pb.IDRACNode{},
+		NameUrl:         "/Nodename",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (dr *IDRAC) UpdateConfig(cfg proto.Message) (e error) {
+	if dcfg, ok := cfg.(*pb.IDRACConfig); ok {
+		dr.cfg = dcfg
+		if dr.pollTicker != nil {
+			dr.pollTicker.Stop()
+			dur, _ := time.ParseDuration(dr.cfg.GetPollingInterval())
+			dr.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, and that every node has an iDRAC address
+func (*IDRAC) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.IDRACConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, n := range c.GetNodes() {
+		if n.GetAddress() == "" {
+			return fmt.Errorf("node %s has no iDRAC address", name)
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*IDRAC) ConfigURL() string {
+	cfg := &pb.IDRACConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*IDRAC)(nil)
+var _ lib.ModuleWithDiscovery = (*IDRAC)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (dr *IDRAC) SetMutationChan(c <-chan lib.Event) { dr.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (dr *IDRAC) SetDiscoveryChan(c chan<- lib.Event) { dr.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*IDRAC)(nil)
+
+// Entry is the module's executable entrypoint
+func (dr *IDRAC) Entry() {
+	url := lib.NodeURLJoin(dr.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "idracpower"), "State"))
+	dr.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  dr.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(dr.cfg.GetPollingInterval())
+	dr.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-dr.pollTicker.C:
+			go dr.discoverAll()
+			break
+		case m := <-dr.mchan: // mutation request
+			go dr.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (dr *IDRAC) Init(api lib.APIClient) {
+	dr.api = api
+	dr.cfg = dr.NewConfig().(*pb.IDRACConfig)
+	dr.clients = newClients()
+}
+
+// Stop should perform a graceful exit
+func (dr *IDRAC) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (dr *IDRAC) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		dr.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name, which selects its iDRAC
+	vs := me.NodeCfg.GetValues([]string{dr.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		dr.api.Logf(lib.LLERROR, "could not get iDRAC name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[dr.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			dr.nodeDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			dr.nodeReset(me.Context, name, "On", me.NodeCfg.ID())
+		case "ONtoOFF":
+			dr.nodeShutdown(me.Context, name, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			dr.nodeReset(me.Context, name, "ForceOff", me.NodeCfg.ID())
+		case "HANGtoON":
+			dr.nodeCycle(me.Context, name, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			dr.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// newClients makes the HTTP clients requests are made with, so they reuse connections;
+// there's one that verifies certificates, and one that doesn't (for iDRACs that are Insecure)
+func newClients() map[bool]*http.Client {
+	r := make(map[bool]*http.Client)
+	for _, insecure := range []bool{false, true} {
+		r[insecure] = &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		}
+	}
+	return r
+}
+
+// request makes an authenticated request against a node's system on its iDRAC
+// path is relative to the system, e.g. RFReset; the request is abandoned if ctx is canceled
+func (dr *IDRAC) request(ctx context.Context, name, method, path string, body interface{}) (rb []byte, e error) {
+	n, ok := dr.cfg.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("cannot control power for node with no iDRAC: %s", name)
+	}
+	sys := n.SystemId
+	if sys == "" {
+		sys = DefaultSystem
+	}
+	var rd io.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		rd = bytes.NewReader(b)
+	}
+	req, e := http.NewRequest(method, "https://"+n.Address+RFSystems+sys+path, rd)
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(n.Username, n.Password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, e := dr.clients[n.Insecure].Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ = ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s on iDRAC for %s: HTTP %v: %s", method, sys+path, name, resp.StatusCode, string(rb))
+	}
+	return
+}
+
+func (dr *IDRAC) system(ctx context.Context, name string) (s *idracSystem, e error) {
+	rb, e := dr.request(ctx, name, http.MethodGet, "", nil)
+	if e != nil {
+		return
+	}
+	s = &idracSystem{}
+	e = json.Unmarshal(rb, s)
+	return
+}
+
+// powerState maps a Redfish PowerState to a PhysState value ID
+func powerState(s string) string {
+	switch s {
+	case "On":
+		return "POWER_ON"
+	case "Off":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (dr *IDRAC) nodeDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	s, e := dr.system(context.Background(), name)
+	if e != nil {
+		dr.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	dr.discover(id, powerState(s.PowerState), start)
+}
+
+// nodeReset issues a reset of type rtype and reports the state it leads to
+// if the mutation is interrupted, we report whatever state the node was left in instead
+func (dr *IDRAC) nodeReset(ctx context.Context, name, rtype string, id lib.NodeID) {
+	if _, e := dr.request(ctx, name, http.MethodPost, RFReset, map[string]string{"ResetType": rtype}); e != nil {
+		if ctx.Err() != nil {
+			dr.api.Logf(lib.LLINFO, "interrupted: %s reset of %s", rtype, name)
+			dr.nodeDiscover(name, id)
+			return
+		}
+		dr.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	vid := "POWER_OFF"
+	if rtype == "On" {
+		vid = "POWER_ON"
+	}
+	dr.discover(id, vid, time.Now())
+}
+
+// nodeShutdown asks the OS to shut down and waits for it to do so
+func (dr *IDRAC) nodeShutdown(ctx context.Context, name string, id lib.NodeID) {
+	if _, e := dr.request(ctx, name, http.MethodPost, RFReset, map[string]string{"ResetType": "GracefulShutdown"}); e != nil {
+		if ctx.Err() != nil {
+			dr.api.Logf(lib.LLINFO, "interrupted: shutdown of %s", name)
+			dr.nodeDiscover(name, id)
+			return
+		}
+		dr.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	deadline := time.Now().Add(shutdownWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			dr.api.Logf(lib.LLINFO, "interrupted: waiting for %s to shut down", name)
+			dr.nodeDiscover(name, id)
+			return
+		}
+		at := time.Now()
+		s, e := dr.system(ctx, name)
+		if e != nil {
+			dr.api.Logf(lib.LLDEBUG, "%v", e)
+			continue
+		}
+		if s.PowerState == "Off" {
+			dr.discover(id, "POWER_OFF", at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG, and HANGtoOFF will force it
+	dr.api.Logf(lib.LLERROR, "node %s did not shut down after %s", name, shutdownWait.String())
+}
+
+// nodeCycle forces a power cycle
+// older iDRAC firmware doesn't offer PowerCycle, so we use ForceRestart there
+func (dr *IDRAC) nodeCycle(ctx context.Context, name string, id lib.NodeID) {
+	s, e := dr.system(ctx, name)
+	if e != nil {
+		dr.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	rtype := "ForceRestart"
+	for _, a := range s.Actions.Reset.Allowed {
+		if a == "PowerCycle" {
+			rtype = a
+			break
+		}
+	}
+	if s.PowerState == "Off" { // nothing to cycle
+		rtype = "On"
+	}
+	if _, e := dr.request(ctx, name, http.MethodPost, RFReset, map[string]string{"ResetType": rtype}); e != nil {
+		if ctx.Err() != nil {
+			dr.api.Logf(lib.LLINFO, "interrupted: power cycle of %s", name)
+			dr.nodeDiscover(name, id)
+			return
+		}
+		dr.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	dr.discover(id, "POWER_ON", time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (dr *IDRAC) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  dr.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	dr.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (dr *IDRAC) discoverAll() {
+	dr.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := dr.api.QueryReadAll()
+	if e != nil {
+		dr.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", dr.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			dr.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete iDRAC info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		// every node has its own iDRAC, so there's nothing to batch here
+		go dr.nodeDiscover(vs[dr.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &IDRAC{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/idracpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("idracpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,172 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: idracpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type IDRACConfig struct {
+	Nodes                map[string]*IDRACNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                `protobuf:"bytes,2,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                `protobuf:"bytes,3,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *IDRACConfig) Reset()         { *m = IDRACConfig{} }
+func (m *IDRACConfig) String() string { return proto.CompactTextString(m) }
+func (*IDRACConfig) ProtoMessage()    {}
+func (*IDRACConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_idracpower_69c9e5d03ccef8d7, []int{0}
+}
+func (m *IDRACConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IDRACConfig.Unmarshal(m, b)
+}
+func (m *IDRACConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IDRACConfig.Marshal(b, m, deterministic)
+}
+func (dst *IDRACConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IDRACConfig.Merge(dst, src)
+}
+func (m *IDRACConfig) XXX_Size() int {
+	return xxx_messageInfo_IDRACConfig.Size(m)
+}
+func (m *IDRACConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_IDRACConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IDRACConfig proto.InternalMessageInfo
+
+func (m *IDRACConfig) GetNodes() map[string]*IDRACNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *IDRACConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *IDRACConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type IDRACNode struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool     `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	SystemId             string   `protobuf:"bytes,5,opt,name=system_id,json=systemId,proto3" json:"system_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IDRACNode) Reset()         { *m = IDRACNode{} }
+func (m *IDRACNode) String() string { return proto.CompactTextString(m) }
+func (*IDRACNode) ProtoMessage()    {}
+func (*IDRACNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_idracpower_69c9e5d03ccef8d7, []int{1}
+}
+func (m *IDRACNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IDRACNode.Unmarshal(m, b)
+}
+func (m *IDRACNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IDRACNode.Marshal(b, m, deterministic)
+}
+func (dst *IDRACNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IDRACNode.Merge(dst, src)
+}
+func (m *IDRACNode) XXX_Size() int {
+	return xxx_messageInfo_IDRACNode.Size(m)
+}
+func (m *IDRACNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_IDRACNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IDRACNode proto.InternalMessageInfo
+
+func (m *IDRACNode) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *IDRACNode) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *IDRACNode) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *IDRACNode) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *IDRACNode) GetSystemId() string {
+	if m != nil {
+		return m.SystemId
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*IDRACConfig)(nil), "proto.IDRACConfig")
+	proto.RegisterMapType((map[string]*IDRACNode)(nil), "proto.IDRACConfig.NodesEntry")
+	proto.RegisterType((*IDRACNode)(nil), "proto.IDRACNode")
+}
+
+func init() { proto.RegisterFile("idracpower.proto", fileDescriptor_idracpower_69c9e5d03ccef8d7) }
+
+var fileDescriptor_idracpower_69c9e5d03ccef8d7 = []byte{
+	// 272 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8e, 0xbd, 0x4e, 0xc3, 0x30,
+	0x14, 0x85, 0xe5, 0x96, 0xd0, 0xe4, 0x66, 0x20, 0xf2, 0x64, 0x8a, 0x90, 0xa2, 0x0e, 0x28, 0x2c,
+	0x19, 0xda, 0x05, 0xb1, 0xa1, 0xc2, 0x10, 0x06, 0x86, 0x48, 0xcc, 0x91, 0xa9, 0x4d, 0x65, 0xe1,
+	0xda, 0xd1, 0x75, 0xd2, 0x2a, 0x2f, 0xc2, 0x93, 0xf1, 0x40, 0xc8, 0x49, 0x08, 0x9d, 0xec, 0xf3,
+	0x73, 0x8f, 0x3e, 0x48, 0x94, 0x40, 0xbe, 0xab, 0xed, 0x49, 0x62, 0x5e, 0xa3, 0x6d, 0x2c, 0x0d,
+	0xfa, 0x67, 0xf5, 0x43, 0x20, 0x2e, 0x9e, 0xcb, 0xa7, 0xed, 0xd6, 0x9a, 0x4f, 0xb5, 0xa7, 0x1b,
+	0x08, 0x8c, 0x15, 0xd2, 0x31, 0x92, 0xce, 0xb3, 0x78, 0x7d, 0x3b, 0xb4, 0xf3, 0xb3, 0x4a, 0xfe,
+	0xe6, 0xf3, 0x17, 0xd3, 0x60, 0x57, 0x0e, 0x5d, 0x7a, 0x0f, 0x49, 0x6d, 0xb5, 0x56, 0x66, 0x5f,
+	0x29, 0xd3, 0x48, 0x3c, 0x72, 0xcd, 0x66, 0x29, 0xc9, 0xa2, 0xf2, 0x6a, 0xf4, 0x8b, 0xd1, 0xa6,
+	0xd7, 0x10, 0x1a, 0x7e, 0x90, 0x55, 0x8b, 0x9a, 0xcd, 0xfb, 0xca, 0xc2, 0xeb, 0x77, 0xd4, 0xcb,
+	0x57, 0x80, 0xff, 0x69, 0x9a, 0xc0, 0xfc, 0x4b, 0x76, 0x8c, 0xf4, 0x1d, 0xff, 0xa5, 0x77, 0x10,
+	0x1c, 0xb9, 0x6e, 0x65, 0x3f, 0x1d, 0xaf, 0x93, 0x73, 0x34, 0x7f, 0x58, 0x0e, 0xf1, 0xe3, 0xec,
+	0x81, 0xac, 0xbe, 0x09, 0x44, 0x53, 0x40, 0x19, 0x2c, 0xb8, 0x10, 0x28, 0x9d, 0x1b, 0xf7, 0xfe,
+	0x24, 0x5d, 0x42, 0xd8, 0x3a, 0x89, 0x1e, 0x61, 0x24, 0x9e, 0xb4, 0xcf, 0x6a, 0xee, 0xdc, 0xc9,
+	0xa2, 0x18, 0x51, 0x27, 0xed, 0x33, 0x65, 0x9c, 0xdc, 0xb5, 0x28, 0xd9, 0x45, 0x4a, 0xb2, 0xb0,
+	0x9c, 0x34, 0xbd, 0x81, 0xc8, 0x75, 0xae, 0x91, 0x87, 0x4a, 0x09, 0x16, 0x0c, 0x87, 0x83, 0x51,
+	0x88, 0x8f, 0xcb, 0x1e, 0x7a, 0xf3, 0x3b, 0x00, 0x1c, 0x26, 0x74, 0x5e, 0x91, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,543 @@
+/* ilopower.go: mutations for HPE iLO 4/5 BMCs using the iLO Redfish API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/ilopower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = ilo.
+ */
+
+package ilopower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/ilopower/proto"
+)
+
+const (
+	PlatformString string = "ilo"
+
+	// iLO only ever has one system
+	ILOSystem string = "/redfish/v1/Systems/1/"
+	ILOReset  string = ILOSystem + "Actions/ComputerSystem.Reset/"
+
+	// shutdownWait is how long we wait for the OS to honor a power button press, it matches the ONtoOFF timeout
+	shutdownWait = 120 * time.Second
+	// holdWait is how long we wait for a press and hold to take effect
+	holdWait = 30 * time.Second
+)
+
+// iloSystem is the part of an iLO ComputerSystem we care about
+// iLO 5 puts its extensions under Oem.Hpe, iLO 4 under Oem.Hp
+type iloSystem struct {
+	PowerState string `json:"PowerState"`
+	Oem        struct {
+		Hpe iloOem `json:"Hpe"`
+		Hp  iloOem `json:"Hp"`
+	} `json:"Oem"`
+}
+
+type iloOem struct {
+	Actions map[string]struct {
+		Target string `json:"target"`
+	} `json:"Actions"`
+}
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s", // a button press asks the OS to shut down
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s", // press and hold
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////
+// ILO Object /
+//////////////
+
+// ILO provides a power on/off interface to HPE iLO BMCs
+type ILO struct {
+	api        lib.APIClient
+	cfg        *pb.ILOConfig
+	clients    map[bool]*http.Client // by whether the iLO's certificate is verified; see newClients
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*ILO)(nil)
+
+// Name returns the FQDN of the module
+func (*ILO) Name() string { return "github.com/hpc/kraken/modules/ilopower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*ILO)(nil)
+var _ lib.ModuleWithConfigValidation = (*ILO)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*ILO) NewConfig() proto.Message {
+	r := &pb.ILOConfig{
+		Nodes:           map[string]*pb.ILONode{},
+		Credentials:     map[string]*pb.ILOCredential{},
+		NameUrl:         "/Nodename",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (il *ILO) UpdateConfig(cfg proto.Message) (e error) {
+	if icfg, ok := cfg.(*pb.ILOConfig); ok {
+		il.cfg = icfg
+		if il.pollTicker != nil {
+			il.pollTicker.Stop()
+			dur, _ := time.ParseDuration(il.cfg.GetPollingInterval())
+			il.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, and that every node has an iLO address and known credentials
+func (*ILO) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.ILOConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, n := range c.GetNodes() {
+		if n.GetAddress() == "" {
+			return fmt.Errorf("node %s has no iLO address", name)
+		}
+		if _, ok := c.GetCredentials()[n.GetCredential()]; !ok {
+			return fmt.Errorf("node %s has unknown credentials: %s", name, n.GetCredential())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*ILO) ConfigURL() string {
+	cfg := &pb.ILOConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*ILO)(nil)
+var _ lib.ModuleWithDiscovery = (*ILO)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (il *ILO) SetMutationChan(c <-chan lib.Event) { il.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (il *ILO) SetDiscoveryChan(c chan<- lib.Event) { il.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*ILO)(nil)
+
+// Entry is the module's executable entrypoint
+func (il *ILO) Entry() {
+	url := lib.NodeURLJoin(il.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "ilopower"), "State"))
+	il.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  il.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(il.cfg.GetPollingInterval())
+	il.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-il.pollTicker.C:
+			go il.discoverAll()
+			break
+		case m := <-il.mchan: // mutation request
+			go il.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (il *ILO) Init(api lib.APIClient) {
+	il.api = api
+	il.cfg = il.NewConfig().(*pb.ILOConfig)
+	il.clients = newClients()
+}
+
+// Stop should perform a graceful exit
+func (il *ILO) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (il *ILO) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		il.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name, which selects its iLO
+	vs := me.NodeCfg.GetValues([]string{il.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		il.api.Logf(lib.LLERROR, "could not get iLO name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[il.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			il.nodeDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			il.nodeOn(me.Context, name, me.NodeCfg.ID())
+		case "ONtoOFF":
+			il.nodePress(me.Context, name, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			il.nodeHold(me.Context, name, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			il.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// newClients makes the HTTP clients requests are made with, so they reuse connections;
+// there's one that verifies certificates, and one that doesn't (for iLOs that are Insecure)
+func newClients() map[bool]*http.Client {
+	r := make(map[bool]*http.Client)
+	for _, insecure := range []bool{false, true} {
+		r[insecure] = &http.Client{
+			Timeout: 30 * time.Second, // iLO 4 can be slow
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		}
+	}
+	return r
+}
+
+// request makes an authenticated request against a node's iLO; it's abandoned if ctx is canceled
+func (il *ILO) request(ctx context.Context, name, method, path string, body interface{}) (rb []byte, e error) {
+	n, ok := il.cfg.Nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("cannot control power for node with no iLO: %s", name)
+	}
+	cred, ok := il.cfg.Credentials[n.Credential]
+	if !ok {
+		return nil, fmt.Errorf("iLO for %s references unknown credential: %s", name, n.Credential)
+	}
+	var rd io.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		rd = bytes.NewReader(b)
+	}
+	req, e := http.NewRequest(method, "https://"+n.Address+path, rd)
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(cred.Username, cred.Password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, e := il.clients[n.Insecure].Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ = ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s on iLO for %s: HTTP %v: %s", method, path, name, resp.StatusCode, string(rb))
+	}
+	return
+}
+
+func (il *ILO) system(ctx context.Context, name string) (s *iloSystem, e error) {
+	rb, e := il.request(ctx, name, http.MethodGet, ILOSystem, nil)
+	if e != nil {
+		return
+	}
+	s = &iloSystem{}
+	e = json.Unmarshal(rb, s)
+	return
+}
+
+// powerState maps a Redfish PowerState to a PhysState value ID
+func powerState(s string) string {
+	switch s {
+	case "On":
+		return "POWER_ON"
+	case "Off":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+// waitOff polls until the system reports Off, and reports it
+// if the mutation is interrupted, we report whatever state the node was left in instead
+func (il *ILO) waitOff(ctx context.Context, name string, wait time.Duration, id lib.NodeID) {
+	deadline := time.Now().Add(wait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			il.api.Logf(lib.LLINFO, "interrupted: waiting for %s to power off", name)
+			il.nodeDiscover(name, id)
+			return
+		}
+		at := time.Now()
+		s, e := il.system(ctx, name)
+		if e != nil {
+			il.api.Logf(lib.LLDEBUG, "%v", e)
+			continue
+		}
+		if s.PowerState == "Off" {
+			il.discover(id, "POWER_OFF", at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	il.api.Logf(lib.LLERROR, "node %s did not power off after %s", name, wait.String())
+}
+
+func (il *ILO) nodeDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	s, e := il.system(context.Background(), name)
+	if e != nil {
+		il.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	il.discover(id, powerState(s.PowerState), start)
+}
+
+func (il *ILO) nodeOn(ctx context.Context, name string, id lib.NodeID) {
+	if _, e := il.request(ctx, name, http.MethodPost, ILOReset, map[string]string{"ResetType": "On"}); e != nil {
+		if ctx.Err() != nil {
+			il.api.Logf(lib.LLINFO, "interrupted: powering on %s", name)
+			il.nodeDiscover(name, id)
+			return
+		}
+		il.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	il.discover(id, "POWER_ON", time.Now())
+}
+
+// nodePress presses the virtual power button, which asks the OS to shut down
+func (il *ILO) nodePress(ctx context.Context, name string, id lib.NodeID) {
+	if _, e := il.request(ctx, name, http.MethodPost, ILOReset, map[string]string{"ResetType": "PushPowerButton"}); e != nil {
+		if ctx.Err() != nil {
+			il.api.Logf(lib.LLINFO, "interrupted: pressing power button of %s", name)
+			il.nodeDiscover(name, id)
+			return
+		}
+		il.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	il.waitOff(ctx, name, shutdownWait, id)
+}
+
+// nodeHold presses and holds the virtual power button, which powers off regardless of the OS
+// if the iLO doesn't advertise the OEM power button action, we fall back to ForceOff
+func (il *ILO) nodeHold(ctx context.Context, name string, id lib.NodeID) {
+	s, e := il.system(ctx, name)
+	if e != nil {
+		il.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	target := ""
+	if a, ok := s.Oem.Hpe.Actions["#HpeComputerSystemExt.PowerButton"]; ok {
+		target = a.Target
+	} else if a, ok := s.Oem.Hp.Actions["#HpComputerSystemExt.PowerButton"]; ok {
+		target = a.Target
+	}
+	if target != "" {
+		_, e = il.request(ctx, name, http.MethodPost, target, map[string]string{"PushType": "PressAndHold"})
+	} else {
+		il.api.Logf(lib.LLDEBUG, "iLO for %s has no press and hold action, using ForceOff", name)
+		_, e = il.request(ctx, name, http.MethodPost, ILOReset, map[string]string{"ResetType": "ForceOff"})
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			il.api.Logf(lib.LLINFO, "interrupted: holding power button of %s", name)
+			il.nodeDiscover(name, id)
+			return
+		}
+		il.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	il.waitOff(ctx, name, holdWait, id)
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (il *ILO) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  il.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	il.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (il *ILO) discoverAll() {
+	il.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := il.api.QueryReadAll()
+	if e != nil {
+		il.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", il.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			il.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete iLO info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		// every node has its own iLO, so there's nothing to batch here
+		go il.nodeDiscover(vs[il.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &ILO{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/ilopower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("ilopower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ilopower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ILOConfig struct {
+	Nodes                map[string]*ILONode       `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Credentials          map[string]*ILOCredential `protobuf:"bytes,2,rep,name=credentials,proto3" json:"credentials,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                    `protobuf:"bytes,3,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                    `protobuf:"bytes,4,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ILOConfig) Reset()         { *m = ILOConfig{} }
+func (m *ILOConfig) String() string { return proto.CompactTextString(m) }
+func (*ILOConfig) ProtoMessage()    {}
+func (*ILOConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ilopower_5706ad2f391e3cfd, []int{0}
+}
+func (m *ILOConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ILOConfig.Unmarshal(m, b)
+}
+func (m *ILOConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ILOConfig.Marshal(b, m, deterministic)
+}
+func (dst *ILOConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ILOConfig.Merge(dst, src)
+}
+func (m *ILOConfig) XXX_Size() int {
+	return xxx_messageInfo_ILOConfig.Size(m)
+}
+func (m *ILOConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ILOConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ILOConfig proto.InternalMessageInfo
+
+func (m *ILOConfig) GetNodes() map[string]*ILONode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *ILOConfig) GetCredentials() map[string]*ILOCredential {
+	if m != nil {
+		return m.Credentials
+	}
+	return nil
+}
+
+func (m *ILOConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *ILOConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type ILONode struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Credential           string   `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	Insecure             bool     `protobuf:"varint,3,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ILONode) Reset()         { *m = ILONode{} }
+func (m *ILONode) String() string { return proto.CompactTextString(m) }
+func (*ILONode) ProtoMessage()    {}
+func (*ILONode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ilopower_5706ad2f391e3cfd, []int{1}
+}
+func (m *ILONode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ILONode.Unmarshal(m, b)
+}
+func (m *ILONode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ILONode.Marshal(b, m, deterministic)
+}
+func (dst *ILONode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ILONode.Merge(dst, src)
+}
+func (m *ILONode) XXX_Size() int {
+	return xxx_messageInfo_ILONode.Size(m)
+}
+func (m *ILONode) XXX_DiscardUnknown() {
+	xxx_messageInfo_ILONode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ILONode proto.InternalMessageInfo
+
+func (m *ILONode) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ILONode) GetCredential() string {
+	if m != nil {
+		return m.Credential
+	}
+	return ""
+}
+
+func (m *ILONode) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+type ILOCredential struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ILOCredential) Reset()         { *m = ILOCredential{} }
+func (m *ILOCredential) String() string { return proto.CompactTextString(m) }
+func (*ILOCredential) ProtoMessage()    {}
+func (*ILOCredential) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ilopower_5706ad2f391e3cfd, []int{2}
+}
+func (m *ILOCredential) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ILOCredential.Unmarshal(m, b)
+}
+func (m *ILOCredential) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ILOCredential.Marshal(b, m, deterministic)
+}
+func (dst *ILOCredential) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ILOCredential.Merge(dst, src)
+}
+func (m *ILOCredential) XXX_Size() int {
+	return xxx_messageInfo_ILOCredential.Size(m)
+}
+func (m *ILOCredential) XXX_DiscardUnknown() {
+	xxx_messageInfo_ILOCredential.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ILOCredential proto.InternalMessageInfo
+
+func (m *ILOCredential) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *ILOCredential) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ILOConfig)(nil), "proto.ILOConfig")
+	proto.RegisterMapType((map[string]*ILONode)(nil), "proto.ILOConfig.NodesEntry")
+	proto.RegisterMapType((map[string]*ILOCredential)(nil), "proto.ILOConfig.CredentialsEntry")
+	proto.RegisterType((*ILONode)(nil), "proto.ILONode")
+	proto.RegisterType((*ILOCredential)(nil), "proto.ILOCredential")
+}
+
+func init() { proto.RegisterFile("ilopower.proto", fileDescriptor_ilopower_5706ad2f391e3cfd) }
+
+var fileDescriptor_ilopower_5706ad2f391e3cfd = []byte{
+	// 312 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x50, 0x4f, 0x4b, 0xc3, 0x30,
+	0x14, 0xa7, 0x9d, 0x73, 0xdb, 0x1b, 0xce, 0x11, 0x3c, 0xc4, 0x09, 0x32, 0x87, 0x87, 0xe9, 0x61,
+	0xe0, 0xbc, 0x88, 0xd7, 0x22, 0x3a, 0x10, 0x85, 0xa2, 0xe7, 0x12, 0xd7, 0xe7, 0x08, 0xc6, 0xa4,
+	0x24, 0xed, 0xc6, 0x3e, 0xb2, 0xdf, 0x42, 0x92, 0xc6, 0xb6, 0x53, 0x4f, 0xc9, 0xef, 0xbd, 0xdf,
+	0x9f, 0xf7, 0x1e, 0x0c, 0xb8, 0x50, 0x99, 0xda, 0xa0, 0x9e, 0x65, 0x5a, 0xe5, 0x8a, 0xb4, 0xdd,
+	0x33, 0xf9, 0x0a, 0xa1, 0xb7, 0x78, 0x7c, 0x8e, 0x94, 0x7c, 0xe7, 0x2b, 0x72, 0x05, 0x6d, 0xa9,
+	0x52, 0x34, 0x34, 0x18, 0xb7, 0xa6, 0xfd, 0xf9, 0x49, 0xc9, 0x9d, 0x55, 0x84, 0xd9, 0x93, 0xed,
+	0xde, 0xc9, 0x5c, 0x6f, 0xe3, 0x92, 0x49, 0x22, 0xe8, 0x2f, 0x35, 0xa6, 0x28, 0x73, 0xce, 0x84,
+	0xa1, 0xa1, 0x13, 0x9e, 0xfd, 0x11, 0x46, 0x35, 0xa7, 0x94, 0x37, 0x55, 0xe4, 0x02, 0x86, 0x99,
+	0x12, 0x82, 0xcb, 0x55, 0xc2, 0x65, 0x8e, 0x7a, 0xcd, 0x04, 0x6d, 0x8d, 0x83, 0x69, 0x2f, 0x3e,
+	0xf4, 0xf5, 0x85, 0x2f, 0x93, 0x63, 0xe8, 0x4a, 0xf6, 0x89, 0x49, 0xa1, 0x05, 0xdd, 0x73, 0x94,
+	0x8e, 0xc5, 0xaf, 0x5a, 0x8c, 0x1e, 0x00, 0xea, 0xf9, 0xc8, 0x10, 0x5a, 0x1f, 0xb8, 0xa5, 0x81,
+	0xe3, 0xd8, 0x2f, 0x39, 0x87, 0xf6, 0x9a, 0x89, 0x02, 0x69, 0x38, 0x0e, 0xa6, 0xfd, 0xf9, 0xa0,
+	0x1e, 0xd2, 0xca, 0xe2, 0xb2, 0x79, 0x1b, 0xde, 0x04, 0xa3, 0x17, 0x18, 0xfe, 0x1e, 0xf8, 0x1f,
+	0xbf, 0xcb, 0x5d, 0xbf, 0xa3, 0xc6, 0xd2, 0x95, 0xb8, 0xe1, 0x3a, 0x49, 0xa0, 0xe3, 0xb3, 0x08,
+	0x85, 0x0e, 0x4b, 0x53, 0x8d, 0xc6, 0x78, 0xc3, 0x1f, 0x48, 0x4e, 0x01, 0xea, 0xcb, 0x38, 0xe7,
+	0x5e, 0xdc, 0xa8, 0x90, 0x11, 0x74, 0xb9, 0x34, 0xb8, 0x2c, 0x34, 0xba, 0x13, 0x75, 0xe3, 0x0a,
+	0x4f, 0xee, 0xe1, 0x60, 0x27, 0xdc, 0x92, 0x0b, 0x83, 0xda, 0x1e, 0xc8, 0xe7, 0x54, 0xd8, 0xf6,
+	0x32, 0x66, 0xcc, 0x46, 0xe9, 0xd4, 0xc7, 0x54, 0xf8, 0x6d, 0xdf, 0x6d, 0x72, 0xfd, 0x3d, 0x00,
+	0xb3, 0xea, 0x5b, 0xff, 0x35, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,408 @@
+/* ipmi.go: a minimal IPMI v2.0 RMCP+ (lanplus) client
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+/*
+ * Only what we need for power control is implemented: session setup with
+ * cipher suite 3 (RAKP-HMAC-SHA1, HMAC-SHA1-96, AES-CBC-128), and raw
+ * request/response of IPMI messages over that session.
+ */
+
+package ipmipower
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// IPMI network functions & commands we use
+const (
+	netFnChassis byte = 0x00
+	netFnApp     byte = 0x06
+
+	cmdGetChassisStatus    byte = 0x01
+	cmdChassisControl      byte = 0x02
+	cmdSetSessionPrivilege byte = 0x3b
+	cmdCloseSession        byte = 0x3c
+
+	ChassisPowerDown  byte = 0x00
+	ChassisPowerUp    byte = 0x01
+	ChassisPowerCycle byte = 0x02
+	ChassisHardReset  byte = 0x03
+	ChassisSoftOff    byte = 0x05
+)
+
+// RMCP+ payload types
+const (
+	payloadIPMI         byte = 0x00
+	payloadOpenSessReq  byte = 0x10
+	payloadOpenSessResp byte = 0x11
+	payloadRAKP1        byte = 0x12
+	payloadRAKP2        byte = 0x13
+	payloadRAKP3        byte = 0x14
+	payloadRAKP4        byte = 0x15
+
+	payloadEncrypted     byte = 0x80
+	payloadAuthenticated byte = 0x40
+)
+
+const (
+	privAdmin    byte = 0x04
+	bmcAddr      byte = 0x20
+	remoteAddr   byte = 0x81
+	ipmiTimeout       = 2 * time.Second
+	ipmiRetries       = 3
+	integrityLen      = 12 // HMAC-SHA1-96
+)
+
+var rmcpHeader = []byte{0x06, 0x00, 0xff, 0x07}
+
+// ipmiSession is an established RMCP+ session with a BMC
+type ipmiSession struct {
+	conn  net.Conn
+	done  chan struct{} // closed with the session
+	sidC  uint32        // remote console (our) session ID
+	sidM  uint32        // managed system (BMC) session ID
+	seq   uint32        // session sequence number
+	rqSeq byte          // IPMI message sequence number
+	k1    []byte        // integrity key
+	k2    []byte        // confidentiality key
+}
+
+// ipmiOpen dials a BMC and establishes an authenticated, encrypted session.
+// If ctx is canceled, the connection is closed, so anything waiting on the BMC fails.
+func ipmiOpen(ctx context.Context, addr, user, pass string) (s *ipmiSession, e error) {
+	var d net.Dialer
+	conn, e := d.DialContext(ctx, "udp", addr)
+	if e != nil {
+		return
+	}
+	s = &ipmiSession{conn: conn, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-s.done:
+		}
+	}()
+	if e = s.handshake(user, pass); e != nil {
+		s.closeConn()
+		return nil, e
+	}
+	// sessions start out at USER privilege, chassis control requires more
+	if _, e = s.Request(netFnApp, cmdSetSessionPrivilege, []byte{privAdmin}); e != nil {
+		s.closeConn()
+		return nil, e
+	}
+	return
+}
+
+// Close ends the session on the BMC and closes the connection
+func (s *ipmiSession) Close() {
+	sid := make([]byte, 4)
+	binary.LittleEndian.PutUint32(sid, s.sidM)
+	s.Request(netFnApp, cmdCloseSession, sid)
+	s.closeConn()
+}
+
+// closeConn closes the connection, and stops watching for cancelation
+func (s *ipmiSession) closeConn() {
+	close(s.done)
+	s.conn.Close()
+}
+
+// Request sends an IPMI command and returns the response data, without the completion code
+func (s *ipmiSession) Request(netFn, cmd byte, data []byte) (r []byte, e error) {
+	s.rqSeq = (s.rqSeq + 1) & 0x3f
+	msg := []byte{bmcAddr, netFn << 2}
+	msg = append(msg, checksum(msg))
+	body := append([]byte{remoteAddr, s.rqSeq << 2, cmd}, data...)
+	msg = append(msg, body...)
+	msg = append(msg, checksum(body))
+
+	for i := 0; i < ipmiRetries; i++ {
+		s.seq++
+		if _, e = s.conn.Write(s.wrap(msg)); e != nil {
+			return
+		}
+		var rsp []byte
+		for {
+			rsp, e = s.recv(payloadIPMI)
+			if e != nil {
+				break
+			}
+			// rqAddr, netFn/rqLUN, cksum, rsAddr, rqSeq/rsLUN, cmd, cc, data..., cksum
+			// anything that doesn't match is a stale response to an earlier request
+			if len(rsp) >= 8 && rsp[4]>>2 == s.rqSeq && rsp[5] == cmd {
+				break
+			}
+		}
+		if e != nil {
+			if ne, ok := e.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return
+		}
+		if rsp[6] != 0 {
+			return nil, fmt.Errorf("IPMI command 0x%02x failed with completion code 0x%02x", cmd, rsp[6])
+		}
+		return rsp[7 : len(rsp)-1], nil
+	}
+	return nil, fmt.Errorf("timed out waiting for IPMI response")
+}
+
+// ChassisPowerOn reports whether the chassis power is on
+func (s *ipmiSession) ChassisPowerOn() (on bool, e error) {
+	r, e := s.Request(netFnChassis, cmdGetChassisStatus, nil)
+	if e != nil {
+		return
+	}
+	if len(r) < 1 {
+		return false, fmt.Errorf("short chassis status response")
+	}
+	return r[0]&0x01 != 0, nil
+}
+
+// ChassisControl issues a chassis control command, e.g. ChassisPowerUp
+func (s *ipmiSession) ChassisControl(c byte) (e error) {
+	_, e = s.Request(netFnChassis, cmdChassisControl, []byte{c})
+	return
+}
+
+////////////////////////
+// Session internals /
+//////////////////////
+
+func (s *ipmiSession) handshake(user, pass string) (e error) {
+	if len(user) > 16 {
+		return fmt.Errorf("IPMI username too long")
+	}
+	var b [4]byte
+	if _, e = rand.Read(b[:]); e != nil {
+		return
+	}
+	s.sidC = binary.LittleEndian.Uint32(b[:]) | 1
+
+	// Open Session
+	req := []byte{0, privAdmin, 0, 0}
+	req = appendU32(req, s.sidC)
+	req = append(req, 0x00, 0, 0, 0x08, 0x01, 0, 0, 0) // auth: RAKP-HMAC-SHA1
+	req = append(req, 0x01, 0, 0, 0x08, 0x01, 0, 0, 0) // integrity: HMAC-SHA1-96
+	req = append(req, 0x02, 0, 0, 0x08, 0x01, 0, 0, 0) // confidentiality: AES-CBC-128
+	rsp, e := s.exchange(payloadOpenSessReq, payloadOpenSessResp, req)
+	if e != nil {
+		return
+	}
+	if len(rsp) < 12 {
+		return fmt.Errorf("short open session response")
+	}
+	if rsp[1] != 0 {
+		return fmt.Errorf("open session rejected with status 0x%02x", rsp[1])
+	}
+	s.sidM = binary.LittleEndian.Uint32(rsp[8:12])
+
+	// RAKP 1/2
+	rm := make([]byte, 16)
+	if _, e = rand.Read(rm); e != nil {
+		return
+	}
+	role := privAdmin | 0x10 // name-only lookup
+	req = []byte{0, 0, 0, 0}
+	req = appendU32(req, s.sidM)
+	req = append(req, rm...)
+	req = append(req, role, 0, 0, byte(len(user)))
+	req = append(req, user...)
+	rsp, e = s.exchange(payloadRAKP1, payloadRAKP2, req)
+	if e != nil {
+		return
+	}
+	if len(rsp) >= 2 && rsp[1] != 0 {
+		return fmt.Errorf("RAKP 2 returned status 0x%02x, check username/password", rsp[1])
+	}
+	if len(rsp) < 60 {
+		return fmt.Errorf("short RAKP 2 message")
+	}
+	rc := rsp[8:24]
+	guid := rsp[24:40]
+	kuid := []byte(pass)
+
+	// verify the BMC knows our password
+	m := appendU32(nil, s.sidC)
+	m = appendU32(m, s.sidM)
+	m = append(m, rm...)
+	m = append(m, rc...)
+	m = append(m, guid...)
+	m = append(m, role, byte(len(user)))
+	m = append(m, user...)
+	if !hmac.Equal(hmacSHA1(kuid, m), rsp[40:60]) {
+		return fmt.Errorf("RAKP 2 key exchange code mismatch, check username/password")
+	}
+
+	// derive session keys
+	m = append(append([]byte{}, rm...), rc...)
+	m = append(m, role, byte(len(user)))
+	m = append(m, user...)
+	sik := hmacSHA1(kuid, m)
+	s.k1 = hmacSHA1(sik, bytes.Repeat([]byte{0x01}, 20))
+	s.k2 = hmacSHA1(sik, bytes.Repeat([]byte{0x02}, 20))[:16]
+
+	// RAKP 3/4
+	m = append([]byte{}, rc...)
+	m = appendU32(m, s.sidC)
+	m = append(m, role, byte(len(user)))
+	m = append(m, user...)
+	req = []byte{0, 0, 0, 0}
+	req = appendU32(req, s.sidM)
+	req = append(req, hmacSHA1(kuid, m)...)
+	rsp, e = s.exchange(payloadRAKP3, payloadRAKP4, req)
+	if e != nil {
+		return
+	}
+	if len(rsp) >= 2 && rsp[1] != 0 {
+		return fmt.Errorf("RAKP 4 returned status 0x%02x", rsp[1])
+	}
+	if len(rsp) < 8+integrityLen {
+		return fmt.Errorf("short RAKP 4 message")
+	}
+	m = append([]byte{}, rm...)
+	m = appendU32(m, s.sidM)
+	m = append(m, guid...)
+	if !hmac.Equal(hmacSHA1(sik, m)[:integrityLen], rsp[8:8+integrityLen]) {
+		return fmt.Errorf("RAKP 4 integrity check mismatch")
+	}
+	return
+}
+
+// exchange sends an unauthenticated session-setup payload and waits for its reply
+func (s *ipmiSession) exchange(ptype, rtype byte, payload []byte) (r []byte, e error) {
+	pkt := append([]byte{}, rmcpHeader...)
+	pkt = append(pkt, 0x06, ptype)
+	pkt = appendU32(pkt, 0)
+	pkt = appendU32(pkt, 0)
+	pkt = append(pkt, byte(len(payload)), byte(len(payload)>>8))
+	pkt = append(pkt, payload...)
+	for i := 0; i < ipmiRetries; i++ {
+		if _, e = s.conn.Write(pkt); e != nil {
+			return
+		}
+		r, e = s.recv(rtype)
+		if ne, ok := e.(net.Error); ok && ne.Timeout() {
+			continue
+		}
+		return
+	}
+	return nil, fmt.Errorf("timed out waiting for BMC")
+}
+
+// wrap builds an authenticated, encrypted RMCP+ packet around an IPMI message
+func (s *ipmiSession) wrap(msg []byte) []byte {
+	// AES-CBC-128 with the IPMI pad scheme: 1, 2, 3, ..., pad length
+	padLen := (16 - (len(msg)+1)%16) % 16
+	plain := append([]byte{}, msg...)
+	for i := 1; i <= padLen; i++ {
+		plain = append(plain, byte(i))
+	}
+	plain = append(plain, byte(padLen))
+	iv := make([]byte, aes.BlockSize)
+	rand.Read(iv)
+	block, _ := aes.NewCipher(s.k2)
+	enc := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(enc, plain)
+	payload := append(iv, enc...)
+
+	sess := []byte{0x06, payloadIPMI | payloadEncrypted | payloadAuthenticated}
+	sess = appendU32(sess, s.sidM)
+	sess = appendU32(sess, s.seq)
+	sess = append(sess, byte(len(payload)), byte(len(payload)>>8))
+	sess = append(sess, payload...)
+	// integrity pad so that everything through next header is 4-byte aligned
+	ipad := (4 - (len(sess)+2)%4) % 4
+	sess = append(sess, bytes.Repeat([]byte{0xff}, ipad)...)
+	sess = append(sess, byte(ipad), 0x07)
+	sess = append(sess, hmacSHA1(s.k1, sess)[:integrityLen]...)
+	return append(append([]byte{}, rmcpHeader...), sess...)
+}
+
+// recv reads packets until one of payload type ptype arrives and returns its (decrypted) payload
+func (s *ipmiSession) recv(ptype byte) (r []byte, e error) {
+	buf := make([]byte, 1024)
+	s.conn.SetReadDeadline(time.Now().Add(ipmiTimeout))
+	for {
+		var n int
+		if n, e = s.conn.Read(buf); e != nil {
+			return
+		}
+		pkt := buf[:n]
+		// RMCP header + authtype, payload type, sid, seq, length
+		if n < 16 || pkt[3] != 0x07 || pkt[4] != 0x06 {
+			continue
+		}
+		pt := pkt[5]
+		if pt&0x3f != ptype {
+			continue
+		}
+		plen := int(binary.LittleEndian.Uint16(pkt[14:16]))
+		if 16+plen > n {
+			continue
+		}
+		payload := pkt[16 : 16+plen]
+		if pt&payloadAuthenticated != 0 {
+			if s.k1 == nil || n < 16+plen+2+integrityLen {
+				continue
+			}
+			signed := pkt[4 : n-integrityLen]
+			if !hmac.Equal(hmacSHA1(s.k1, signed)[:integrityLen], pkt[n-integrityLen:]) {
+				continue
+			}
+		}
+		if pt&payloadEncrypted != 0 {
+			if s.k2 == nil || plen < 2*aes.BlockSize || plen%aes.BlockSize != 0 {
+				continue
+			}
+			block, _ := aes.NewCipher(s.k2)
+			plain := make([]byte, plen-aes.BlockSize)
+			cipher.NewCBCDecrypter(block, payload[:aes.BlockSize]).CryptBlocks(plain, payload[aes.BlockSize:])
+			padLen := int(plain[len(plain)-1])
+			if padLen+1 > len(plain) {
+				continue
+			}
+			payload = plain[:len(plain)-padLen-1]
+		}
+		return append([]byte{}, payload...), nil
+	}
+}
+
+func hmacSHA1(key, msg []byte) []byte {
+	h := hmac.New(sha1.New, key)
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	var u [4]byte
+	binary.LittleEndian.PutUint32(u[:], v)
+	return append(b, u[:]...)
+}
+
+// checksum is the IPMI 2's complement checksum
+func checksum(b []byte) byte {
+	var c byte
+	for _, v := range b {
+		c += v
+	}
+	return -c
+}
@@ -0,0 +1,411 @@
+/* ipmipower.go: mutations for BMCs using IPMI v2.0 chassis commands over lanplus
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/ipmipower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = ipmi.
+ * BMCs are looked up in the config by the value found at NameUrl.
+ */
+
+package ipmipower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/ipmipower/proto"
+)
+
+const (
+	PlatformString string = "ipmi"
+	DefaultPort    int32  = 623
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "15s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "15s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "15s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "15s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// IPMI Object /
+//////////////////
+
+// IPMI provides a power on/off interface to IPMI BMCs
+type IPMI struct {
+	api        lib.APIClient
+	cfg        *pb.IPMIConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*IPMI)(nil)
+
+// Name returns the FQDN of the module
+func (*IPMI) Name() string { return "github.com/hpc/kraken/modules/ipmipower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*IPMI)(nil)
+var _ lib.ModuleWithConfigValidation = (*IPMI)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*IPMI) NewConfig() proto.Message {
+	r := &pb.IPMIConfig{
+		NameUrl:         "/Nodename",
+		Bmcs:            map[string]*pb.IPMIBmc{},
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (ip *IPMI) UpdateConfig(cfg proto.Message) (e error) {
+	if ipcfg, ok := cfg.(*pb.IPMIConfig); ok {
+		ip.cfg = ipcfg
+		if ip.pollTicker != nil {
+			ip.pollTicker.Stop()
+			dur, _ := time.ParseDuration(ip.cfg.GetPollingInterval())
+			ip.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig rejects a polling interval that isn't a positive duration
+func (*IPMI) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.IPMIConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*IPMI) ConfigURL() string {
+	cfg := &pb.IPMIConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*IPMI)(nil)
+var _ lib.ModuleWithDiscovery = (*IPMI)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (ip *IPMI) SetMutationChan(c <-chan lib.Event) { ip.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (ip *IPMI) SetDiscoveryChan(c chan<- lib.Event) { ip.dchan = c }
+
+/*
+ * lib.ModuleWithRediscover
+ */
+var _ lib.ModuleWithRediscover = (*IPMI)(nil)
+
+// Rediscover polls the power state of nodes (or every node) now, rather than waiting for the next poll
+func (ip *IPMI) Rediscover(nodes []string) { ip.discoverNodes(nodes) }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*IPMI)(nil)
+
+// Entry is the module's executable entrypoint
+func (ip *IPMI) Entry() {
+	url := lib.NodeURLJoin(ip.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "ipmipower"), "State"))
+	ip.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ip.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(ip.cfg.GetPollingInterval())
+	ip.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-ip.pollTicker.C:
+			go ip.discoverAll()
+			break
+		case m := <-ip.mchan: // mutation request
+			go ip.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (ip *IPMI) Init(api lib.APIClient) {
+	ip.api = api
+	ip.cfg = ip.NewConfig().(*pb.IPMIConfig)
+}
+
+// Stop should perform a graceful exit
+func (ip *IPMI) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (ip *IPMI) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		ip.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name
+	vs := me.NodeCfg.GetValues([]string{ip.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		ip.api.Logf(lib.LLERROR, "could not get BMC name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[ip.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			ip.nodeDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			ip.nodeControl(me.Context, name, ChassisPowerUp, cpb.Node_POWER_ON, me.NodeCfg.ID())
+		case "ONtoOFF":
+			ip.nodeControl(me.Context, name, ChassisPowerDown, cpb.Node_POWER_OFF, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			ip.nodeControl(me.Context, name, ChassisPowerDown, cpb.Node_POWER_OFF, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			ip.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which closes its IPMI session
+		break
+	}
+}
+
+// session opens an IPMI session to the BMC of the named node; it's closed if ctx is canceled
+func (ip *IPMI) session(ctx context.Context, name string) (*ipmiSession, error) {
+	bmc, ok := ip.cfg.Bmcs[name]
+	if !ok {
+		return nil, fmt.Errorf("no BMC configured for node: %s", name)
+	}
+	port := bmc.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return ipmiOpen(ctx, bmc.Ip+":"+strconv.Itoa(int(port)), bmc.Username, bmc.Password)
+}
+
+func (ip *IPMI) nodeDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	s, e := ip.session(context.Background(), name)
+	if e != nil {
+		ip.api.Logf(lib.LLERROR, "failed to open IPMI session for %s: %v", name, e)
+		return
+	}
+	defer s.Close()
+	on, e := s.ChassisPowerOn()
+	if e != nil {
+		ip.api.Logf(lib.LLERROR, "chassis power status failed for %s: %v", name, e)
+		return
+	}
+	vid := "POWER_OFF"
+	if on {
+		vid = "POWER_ON"
+	}
+	ip.discover(id, vid, start)
+}
+
+// nodeControl sends a chassis control command, and reports state on success
+// if the mutation is interrupted, we report whatever state the node was left in instead
+func (ip *IPMI) nodeControl(ctx context.Context, name string, c byte, state cpb.Node_PhysState, id lib.NodeID) {
+	s, e := ip.session(ctx, name)
+	if e == nil {
+		defer s.Close()
+		e = s.ChassisControl(c)
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			ip.api.Logf(lib.LLINFO, "interrupted: chassis control of %s", name)
+			ip.nodeDiscover(name, id)
+			return
+		}
+		ip.api.Logf(lib.LLERROR, "chassis control failed for %s: %v", name, e)
+		return
+	}
+	ip.discover(id, cpb.Node_PhysState_name[int32(state)], time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (ip *IPMI) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ip.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	ip.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (ip *IPMI) discoverAll() {
+	ip.api.Log(lib.LLDEBUG, "polling for node state")
+	ip.discoverNodes(nil)
+}
+
+// discoverNodes discovers the power state of the nodes with IDs ids, or of every node if ids is empty
+func (ip *IPMI) discoverNodes(ids []string) {
+	var ns []lib.Node
+	var e error
+	if len(ids) == 0 {
+		if ns, e = ip.api.QueryReadAll(); e != nil {
+			ip.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+			return
+		}
+	}
+	for _, id := range ids {
+		n, e := ip.api.QueryRead(id)
+		if e != nil {
+			ip.api.Logf(lib.LLERROR, "node query failed for %s: %v", id, e)
+			continue
+		}
+		ns = append(ns, n)
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", ip.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			ip.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete IPMI info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		go ip.nodeDiscover(vs[ip.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &IPMI{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/ipmipower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("ipmipower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
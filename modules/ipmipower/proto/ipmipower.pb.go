@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ipmipower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type IPMIConfig struct {
+	Bmcs                 map[string]*IPMIBmc `protobuf:"bytes,1,rep,name=bmcs,proto3" json:"bmcs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string              `protobuf:"bytes,2,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string              `protobuf:"bytes,3,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *IPMIConfig) Reset()         { *m = IPMIConfig{} }
+func (m *IPMIConfig) String() string { return proto.CompactTextString(m) }
+func (*IPMIConfig) ProtoMessage()    {}
+func (*IPMIConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ipmipower_df8f07aee99253ed, []int{0}
+}
+func (m *IPMIConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IPMIConfig.Unmarshal(m, b)
+}
+func (m *IPMIConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IPMIConfig.Marshal(b, m, deterministic)
+}
+func (dst *IPMIConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IPMIConfig.Merge(dst, src)
+}
+func (m *IPMIConfig) XXX_Size() int {
+	return xxx_messageInfo_IPMIConfig.Size(m)
+}
+func (m *IPMIConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_IPMIConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IPMIConfig proto.InternalMessageInfo
+
+func (m *IPMIConfig) GetBmcs() map[string]*IPMIBmc {
+	if m != nil {
+		return m.Bmcs
+	}
+	return nil
+}
+
+func (m *IPMIConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *IPMIConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type IPMIBmc struct {
+	Ip                   string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IPMIBmc) Reset()         { *m = IPMIBmc{} }
+func (m *IPMIBmc) String() string { return proto.CompactTextString(m) }
+func (*IPMIBmc) ProtoMessage()    {}
+func (*IPMIBmc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ipmipower_df8f07aee99253ed, []int{1}
+}
+func (m *IPMIBmc) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IPMIBmc.Unmarshal(m, b)
+}
+func (m *IPMIBmc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IPMIBmc.Marshal(b, m, deterministic)
+}
+func (dst *IPMIBmc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IPMIBmc.Merge(dst, src)
+}
+func (m *IPMIBmc) XXX_Size() int {
+	return xxx_messageInfo_IPMIBmc.Size(m)
+}
+func (m *IPMIBmc) XXX_DiscardUnknown() {
+	xxx_messageInfo_IPMIBmc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IPMIBmc proto.InternalMessageInfo
+
+func (m *IPMIBmc) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *IPMIBmc) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *IPMIBmc) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *IPMIBmc) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*IPMIConfig)(nil), "proto.IPMIConfig")
+	proto.RegisterMapType((map[string]*IPMIBmc)(nil), "proto.IPMIConfig.BmcsEntry")
+	proto.RegisterType((*IPMIBmc)(nil), "proto.IPMIBmc")
+}
+
+func init() { proto.RegisterFile("ipmipower.proto", fileDescriptor_ipmipower_df8f07aee99253ed) }
+
+var fileDescriptor_ipmipower_df8f07aee99253ed = []byte{
+	// 249 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8e, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0xc9, 0x3f, 0xdb, 0x4e, 0xa1, 0x2d, 0x73, 0x5a, 0xeb, 0xa5, 0x14, 0x0f, 0xf5, 0x12,
+	0xa1, 0x5e, 0xc4, 0x63, 0x45, 0x24, 0x07, 0x41, 0x02, 0x9e, 0x4b, 0x1a, 0xd7, 0xb2, 0xb8, 0xff,
+	0x98, 0x4d, 0x5a, 0xfa, 0x1d, 0xfd, 0x50, 0x92, 0xcd, 0x5a, 0x7b, 0x9a, 0x79, 0xef, 0xfd, 0x78,
+	0x3c, 0x98, 0x0a, 0xab, 0x84, 0x35, 0x47, 0x4e, 0xb9, 0x25, 0xd3, 0x18, 0xcc, 0xfc, 0x59, 0xfe,
+	0x44, 0x00, 0xc5, 0xfb, 0x5b, 0xf1, 0x6c, 0xf4, 0x97, 0xd8, 0xe3, 0x3d, 0xa4, 0x3b, 0x55, 0x3b,
+	0x16, 0x2d, 0x92, 0xd5, 0x78, 0x7d, 0xd3, 0xb3, 0xf9, 0x3f, 0x90, 0x6f, 0x54, 0xed, 0x5e, 0x74,
+	0x43, 0xa7, 0xd2, 0x83, 0x78, 0x07, 0x33, 0x6b, 0xa4, 0x14, 0x7a, 0xbf, 0x15, 0xba, 0xe1, 0x74,
+	0xa8, 0x24, 0x8b, 0x17, 0xd1, 0x6a, 0x54, 0x4e, 0x83, 0x5f, 0x04, 0x1b, 0xaf, 0x61, 0xa8, 0x2b,
+	0xc5, 0xb7, 0x2d, 0x49, 0x96, 0x78, 0x64, 0xd0, 0xe9, 0x0f, 0x92, 0xf3, 0x57, 0x18, 0x9d, 0x8b,
+	0x71, 0x06, 0xc9, 0x37, 0x3f, 0xb1, 0xc8, 0x23, 0xdd, 0x8b, 0xb7, 0x90, 0x1d, 0x2a, 0xd9, 0x72,
+	0xdf, 0x3c, 0x5e, 0x4f, 0x2e, 0x66, 0x6d, 0x54, 0x5d, 0xf6, 0xe1, 0x53, 0xfc, 0x18, 0x2d, 0x39,
+	0x0c, 0x82, 0x8b, 0x13, 0x88, 0x85, 0x0d, 0x2d, 0xb1, 0xb0, 0x88, 0x90, 0x5a, 0x43, 0x8d, 0xef,
+	0xc8, 0x4a, 0xff, 0xe3, 0x1c, 0x86, 0xad, 0xe3, 0xd4, 0xcd, 0x08, 0x93, 0xce, 0xba, 0xcb, 0x6c,
+	0xe5, 0xdc, 0xd1, 0xd0, 0x27, 0x4b, 0xfb, 0xec, 0x4f, 0xef, 0xae, 0xfc, 0x80, 0x87, 0xdf, 0x01,
+	0x00, 0x19, 0x13, 0x64, 0x43, 0x56, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,244 @@
+/* kube.go: a minimal client for the kubernetes pods API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package kubepower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/kubepower/proto"
+)
+
+const (
+	// managedLabel marks the pods we create, so we can list and watch only those
+	managedLabel = "app.kubernetes.io/managed-by"
+	managedValue = "kraken"
+)
+
+// kubePod is the part of a pod document we care about
+type kubePod struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Status struct {
+		Phase      string `json:"phase"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// Ready reports whether the pod's Ready condition is true
+func (p *kubePod) Ready() bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == "Ready" {
+			return c.Status == "True"
+		}
+	}
+	return false
+}
+
+// kubeEvent is a watch event
+type kubeEvent struct {
+	Type   string  `json:"type"`
+	Object kubePod `json:"object"`
+}
+
+// errNotFound is returned when the API says 404
+var errNotFound = fmt.Errorf("not found")
+
+type kubeClient struct {
+	server    string
+	tokenFile string
+	namespace string
+	template  string
+	client    *http.Client
+	stream    *http.Client // no timeout, for watches
+}
+
+func newKubeClient(cfg *pb.KubeConfig) (c *kubeClient, e error) {
+	tc := &tls.Config{InsecureSkipVerify: cfg.GetInsecure()}
+	if cfg.GetCaFile() != "" {
+		var ca []byte
+		if ca, e = ioutil.ReadFile(cfg.GetCaFile()); e != nil {
+			return
+		}
+		tc.RootCAs = x509.NewCertPool()
+		if !tc.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.GetCaFile())
+		}
+	}
+	tr := &http.Transport{TLSClientConfig: tc}
+	c = &kubeClient{
+		server:    strings.TrimRight(cfg.GetApiServer(), "/"),
+		tokenFile: cfg.GetTokenFile(),
+		namespace: cfg.GetNamespace(),
+		template:  cfg.GetPodTemplate(),
+		client:    &http.Client{Transport: tr, Timeout: 30 * time.Second},
+		stream:    &http.Client{Transport: tr},
+	}
+	return
+}
+
+// request builds an authenticated request for a path under the namespace's pods
+func (c *kubeClient) request(method, path string, body []byte) (req *http.Request, e error) {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	u := c.server + "/api/v1/namespaces/" + url.PathEscape(c.namespace) + "/pods" + path
+	if req, e = http.NewRequest(method, u, rd); e != nil {
+		return
+	}
+	if c.tokenFile != "" {
+		var tok []byte
+		if tok, e = ioutil.ReadFile(c.tokenFile); e != nil {
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(tok)))
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return
+}
+
+// do makes a request, and unmarshals the response into r (if not nil); it's abandoned if ctx is canceled
+func (c *kubeClient) do(ctx context.Context, method, path string, body []byte, r interface{}) (e error) {
+	req, e := c.request(method, path, body)
+	if e != nil {
+		return
+	}
+	resp, e := c.client.Do(req.WithContext(ctx))
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("%s pods%s: HTTP %v: %s", method, path, resp.StatusCode, strings.TrimSpace(string(rb)))
+	}
+	if r != nil {
+		return json.Unmarshal(rb, r)
+	}
+	return
+}
+
+// Create makes a pod named name from the template
+func (c *kubeClient) Create(ctx context.Context, name string) (e error) {
+	var pod map[string]interface{}
+	if e = json.Unmarshal([]byte(c.template), &pod); e != nil {
+		return fmt.Errorf("invalid pod template: %v", e)
+	}
+	md, _ := pod["metadata"].(map[string]interface{})
+	if md == nil {
+		md = make(map[string]interface{})
+	}
+	labels, _ := md["labels"].(map[string]interface{})
+	if labels == nil {
+		labels = make(map[string]interface{})
+	}
+	labels[managedLabel] = managedValue
+	md["labels"] = labels
+	md["name"] = name
+	delete(md, "generateName")
+	pod["metadata"] = md
+	pod["apiVersion"] = "v1"
+	pod["kind"] = "Pod"
+	b, _ := json.Marshal(pod)
+	return c.do(ctx, http.MethodPost, "", b, nil)
+}
+
+// Delete deletes a pod; force deletes it without a grace period
+func (c *kubeClient) Delete(ctx context.Context, name string, force bool) error {
+	var b []byte
+	if force {
+		b = []byte(`{"kind":"DeleteOptions","apiVersion":"v1","gracePeriodSeconds":0}`)
+	}
+	return c.do(ctx, http.MethodDelete, "/"+url.PathEscape(name), b, nil)
+}
+
+// Get gets a single pod
+func (c *kubeClient) Get(ctx context.Context, name string) (p *kubePod, e error) {
+	p = &kubePod{}
+	e = c.do(ctx, http.MethodGet, "/"+url.PathEscape(name), nil, p)
+	return
+}
+
+// List returns all of the pods we manage, and the list's resourceVersion
+func (c *kubeClient) List() (pods map[string]*kubePod, rv string, e error) {
+	var r struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Items []kubePod `json:"items"`
+	}
+	q := url.Values{"labelSelector": {managedLabel + "=" + managedValue}}
+	if e = c.do(context.Background(), http.MethodGet, "?"+q.Encode(), nil, &r); e != nil {
+		return
+	}
+	pods = make(map[string]*kubePod)
+	for i := range r.Items {
+		pods[r.Items[i].Metadata.Name] = &r.Items[i]
+	}
+	return pods, r.Metadata.ResourceVersion, nil
+}
+
+// Watch streams changes to the pods we manage to f, starting after resourceVersion rv
+// it returns when the stream ends
+func (c *kubeClient) Watch(rv string, f func(*kubeEvent)) (e error) {
+	q := url.Values{
+		"labelSelector":   {managedLabel + "=" + managedValue},
+		"watch":           {"true"},
+		"resourceVersion": {rv},
+	}
+	req, e := c.request(http.MethodGet, "?"+q.Encode(), nil)
+	if e != nil {
+		return
+	}
+	resp, e := c.stream.Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("watch pods: HTTP %v", resp.StatusCode)
+	}
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev kubeEvent
+		if e = dec.Decode(&ev); e != nil {
+			if e == io.EOF {
+				e = nil
+			}
+			return
+		}
+		if ev.Type == "ERROR" { // usually "resource version too old"
+			return fmt.Errorf("watch pods: error event")
+		}
+		f(&ev)
+	}
+}
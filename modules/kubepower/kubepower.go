@@ -0,0 +1,548 @@
+/* kubepower.go: mutations for kubernetes pods acting as nodes
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/kubepower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = kubernetes.
+ */
+
+package kubepower
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/kubepower/proto"
+)
+
+const (
+	PlatformString string = "kubernetes"
+
+	// deleteWait is how long we wait for a deleted pod to go away, it matches the ONtoOFF timeout
+	deleteWait = 60 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "60s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "60s", // pods get their termination grace period
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+/////////////////
+// Kube Object /
+///////////////
+
+// Kube provides a power on/off interface to kubernetes pods
+type Kube struct {
+	api        lib.APIClient
+	cfg        *pb.KubeConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	nodes      map[string]lib.NodeID // pod name -> node, for the watch
+	kc         *kubeClient
+	kcCfg      *pb.KubeConfig // the config kc was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Kube)(nil)
+
+// Name returns the FQDN of the module
+func (*Kube) Name() string { return "github.com/hpc/kraken/modules/kubepower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Kube)(nil)
+var _ lib.ModuleWithConfigValidation = (*Kube)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Kube) NewConfig() proto.Message {
+	r := &pb.KubeConfig{
+		ApiServer:       "https://kubernetes.default.svc",
+		TokenFile:       "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		CaFile:          "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		Namespace:       "default",
+		PodTemplate:     `{"spec":{"containers":[{"name":"node","image":"busybox","command":["sleep","infinity"]}]}}`,
+		NameUrl:         "type.googleapis.com/proto.Kube/PodName",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (kp *Kube) UpdateConfig(cfg proto.Message) (e error) {
+	if kcfg, ok := cfg.(*pb.KubeConfig); ok {
+		kp.cfg = kcfg
+		if kp.pollTicker != nil {
+			kp.pollTicker.Stop()
+			dur, _ := time.ParseDuration(kp.cfg.GetPollingInterval())
+			kp.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig wants an http(s) API server and a positive poll interval
+func (*Kube) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.KubeConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if u, e := url.Parse(c.GetApiServer()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid API server: %q", c.GetApiServer())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Kube) ConfigURL() string {
+	cfg := &pb.KubeConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Kube)(nil)
+var _ lib.ModuleWithDiscovery = (*Kube)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (kp *Kube) SetMutationChan(c <-chan lib.Event) { kp.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (kp *Kube) SetDiscoveryChan(c chan<- lib.Event) { kp.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Kube)(nil)
+
+// Entry is the module's executable entrypoint
+func (kp *Kube) Entry() {
+	url := lib.NodeURLJoin(kp.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "kubepower"), "State"))
+	kp.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  kp.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(kp.cfg.GetPollingInterval())
+	kp.pollTicker = time.NewTicker(dur)
+
+	// pod changes come in through a watch, polling catches up if the watch is down
+	go kp.watch()
+
+	// main loop
+	for {
+		select {
+		case <-kp.pollTicker.C:
+			go kp.discoverAll()
+			break
+		case m := <-kp.mchan: // mutation request
+			go kp.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (kp *Kube) Init(api lib.APIClient) {
+	kp.api = api
+	kp.cfg = kp.NewConfig().(*pb.KubeConfig)
+	kp.nodes = make(map[string]lib.NodeID)
+}
+
+// Stop should perform a graceful exit
+func (kp *Kube) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (kp *Kube) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		kp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's pod name
+	vs := me.NodeCfg.GetValues([]string{kp.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		kp.api.Logf(lib.LLERROR, "could not get pod name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[kp.cfg.GetNameUrl()].String()
+	kp.mutex.Lock()
+	kp.nodes[name] = me.NodeCfg.ID()
+	kp.mutex.Unlock()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			kp.podDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			kp.podStart(me.Context, name, me.NodeCfg.ID())
+		case "ONtoOFF":
+			kp.podStop(me.Context, name, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			kp.podStop(me.Context, name, true, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			kp.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// client returns the kubernetes client, creating it if the config has changed
+func (kp *Kube) client() (*kubeClient, error) {
+	kp.mutex.Lock()
+	defer kp.mutex.Unlock()
+	if kp.kc == nil || kp.kcCfg != kp.cfg {
+		kc, e := newKubeClient(kp.cfg)
+		if e != nil {
+			return nil, e
+		}
+		kp.kc = kc
+		kp.kcCfg = kp.cfg
+	}
+	return kp.kc, nil
+}
+
+// podState maps a pod (or its absence) to PhysState and RunState value IDs
+func podState(p *kubePod) (phys, run string) {
+	if p == nil {
+		return "POWER_OFF", "RUN_UK"
+	}
+	switch p.Status.Phase {
+	case "Pending":
+		return "POWER_ON", "NODE_INIT"
+	case "Running":
+		if p.Ready() {
+			return "POWER_ON", "NODE_SYNC"
+		}
+		return "POWER_ON", "NODE_INIT"
+	case "Succeeded":
+		return "POWER_OFF", "RUN_UK"
+	case "Failed": // HANGtoOFF will clean it up
+		return "PHYS_HANG", "NODE_ERROR"
+	}
+	return "PHYS_UNKNOWN", "RUN_UK"
+}
+
+// report emits discovery for a pod (or its absence), as it was at at
+func (kp *Kube) report(id lib.NodeID, p *kubePod, at time.Time) {
+	phys, run := podState(p)
+	kp.discover(id, "/PhysState", phys, at)
+	kp.discover(id, "/RunState", run, at)
+}
+
+func (kp *Kube) podDiscover(name string, id lib.NodeID) {
+	c, e := kp.client()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	start := time.Now()
+	p, e := c.Get(context.Background(), name)
+	switch e {
+	case nil:
+		kp.report(id, p, start)
+	case errNotFound:
+		kp.report(id, nil, start)
+	default:
+		kp.api.Logf(lib.LLERROR, "failed to get pod %s: %v", name, e)
+	}
+}
+
+// podStart creates a node's pod
+// if the mutation is interrupted, we report whatever state the pod was left in instead
+func (kp *Kube) podStart(ctx context.Context, name string, id lib.NodeID) {
+	c, e := kp.client()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	// a pod that ran to completion still holds its name
+	if p, e := c.Get(ctx, name); e == nil && (p.Status.Phase == "Succeeded" || p.Status.Phase == "Failed") {
+		if e = c.Delete(ctx, name, true); e != nil {
+			kp.api.Logf(lib.LLERROR, "failed to delete finished pod %s: %v", name, e)
+			return
+		}
+		if !kp.podGone(ctx, c, name, id) {
+			return
+		}
+	}
+	if e = c.Create(ctx, name); e != nil {
+		if ctx.Err() != nil {
+			kp.api.Logf(lib.LLINFO, "interrupted: creating pod %s", name)
+			kp.podDiscover(name, id)
+			return
+		}
+		kp.api.Logf(lib.LLERROR, "failed to create pod %s: %v", name, e)
+		return
+	}
+	// the watch will tell us when it's running
+	kp.discover(id, "/PhysState", "POWER_ON", time.Now())
+}
+
+// podStop deletes a node's pod, and waits for it to be gone
+// if the mutation is interrupted, we report whatever state the pod was left in instead
+func (kp *Kube) podStop(ctx context.Context, name string, force bool, id lib.NodeID) {
+	c, e := kp.client()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	if e = c.Delete(ctx, name, force); e != nil && e != errNotFound {
+		if ctx.Err() != nil {
+			kp.api.Logf(lib.LLINFO, "interrupted: deleting pod %s", name)
+			kp.podDiscover(name, id)
+			return
+		}
+		kp.api.Logf(lib.LLERROR, "failed to delete pod %s: %v", name, e)
+		return
+	}
+	if kp.podGone(ctx, c, name, id) {
+		kp.report(id, nil, time.Now())
+	}
+}
+
+// podGone waits for a deleted pod to disappear
+// if ctx is canceled first, it reports the pod's state, and gives up
+func (kp *Kube) podGone(ctx context.Context, c *kubeClient, name string, id lib.NodeID) bool {
+	deadline := time.Now().Add(deleteWait)
+	for time.Now().Before(deadline) {
+		if _, e := c.Get(ctx, name); e == errNotFound {
+			return true
+		}
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			kp.api.Logf(lib.LLINFO, "interrupted: waiting for pod %s to be deleted", name)
+			kp.podDiscover(name, id)
+			return false
+		}
+	}
+	// the SME will fail us to PHYS_HANG, and HANGtoOFF will force it
+	kp.api.Logf(lib.LLERROR, "pod %s was not deleted after %s", name, deleteWait.String())
+	return false
+}
+
+func (kp *Kube) discover(id lib.NodeID, url, vid string, at time.Time) {
+	url = lib.NodeURLJoin(id.String(), url)
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  kp.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	kp.dchan <- v
+}
+
+// watch follows changes to our pods for as long as we run
+func (kp *Kube) watch() {
+	for {
+		c, e := kp.client()
+		if e == nil {
+			var rv string
+			if _, rv, e = c.List(); e == nil {
+				e = c.Watch(rv, kp.handlePodEvent)
+			}
+		}
+		if e != nil {
+			kp.api.Logf(lib.LLERROR, "pod watch failed: %v", e)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (kp *Kube) handlePodEvent(ev *kubeEvent) {
+	kp.mutex.Lock()
+	id, ok := kp.nodes[ev.Object.Metadata.Name]
+	kp.mutex.Unlock()
+	if !ok { // not one of ours, or discoverAll hasn't seen it yet
+		return
+	}
+	switch ev.Type {
+	case "ADDED", "MODIFIED":
+		kp.report(id, &ev.Object, time.Now())
+	case "DELETED":
+		kp.report(id, nil, time.Now())
+	}
+}
+
+// discoverAll is used to do polling discovery of power state
+// all of our pods are listed in one call
+func (kp *Kube) discoverAll() {
+	kp.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := kp.api.QueryReadAll()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	idmap := make(map[string]lib.NodeID)
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", kp.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			kp.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete kubernetes info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		idmap[vs[kp.cfg.GetNameUrl()].String()] = n.ID()
+	}
+	kp.mutex.Lock()
+	kp.nodes = idmap
+	kp.mutex.Unlock()
+	if len(idmap) == 0 {
+		return
+	}
+
+	c, e := kp.client()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	start := time.Now()
+	pods, _, e := c.List()
+	if e != nil {
+		kp.api.Logf(lib.LLERROR, "failed to list pods: %v", e)
+		return
+	}
+	for name, id := range idmap {
+		kp.report(id, pods[name], start) // a missing pod is off
+	}
+}
+
+// initialization
+func init() {
+	module := &Kube{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK":     reflect.ValueOf(cpb.Node_UNKNOWN),
+		"NODE_INIT":  reflect.ValueOf(cpb.Node_INIT),
+		"NODE_SYNC":  reflect.ValueOf(cpb.Node_SYNC),
+		"NODE_ERROR": reflect.ValueOf(cpb.Node_ERROR),
+	}
+	discovers["/Services/kubepower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("kubepower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,138 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: kubepower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type KubeConfig struct {
+	ApiServer            string   `protobuf:"bytes,1,opt,name=api_server,json=apiServer,proto3" json:"api_server,omitempty"`
+	TokenFile            string   `protobuf:"bytes,2,opt,name=token_file,json=tokenFile,proto3" json:"token_file,omitempty"`
+	CaFile               string   `protobuf:"bytes,3,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	Insecure             bool     `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	PodTemplate          string   `protobuf:"bytes,6,opt,name=pod_template,json=podTemplate,proto3" json:"pod_template,omitempty"`
+	PollingInterval      string   `protobuf:"bytes,7,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string   `protobuf:"bytes,8,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KubeConfig) Reset()         { *m = KubeConfig{} }
+func (m *KubeConfig) String() string { return proto.CompactTextString(m) }
+func (*KubeConfig) ProtoMessage()    {}
+func (*KubeConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_kubepower_d2dcdb83a1a570dd, []int{0}
+}
+func (m *KubeConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KubeConfig.Unmarshal(m, b)
+}
+func (m *KubeConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KubeConfig.Marshal(b, m, deterministic)
+}
+func (dst *KubeConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KubeConfig.Merge(dst, src)
+}
+func (m *KubeConfig) XXX_Size() int {
+	return xxx_messageInfo_KubeConfig.Size(m)
+}
+func (m *KubeConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_KubeConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KubeConfig proto.InternalMessageInfo
+
+func (m *KubeConfig) GetApiServer() string {
+	if m != nil {
+		return m.ApiServer
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetTokenFile() string {
+	if m != nil {
+		return m.TokenFile
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetCaFile() string {
+	if m != nil {
+		return m.CaFile
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *KubeConfig) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetPodTemplate() string {
+	if m != nil {
+		return m.PodTemplate
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *KubeConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*KubeConfig)(nil), "proto.KubeConfig")
+}
+
+func init() { proto.RegisterFile("kubepower.proto", fileDescriptor_kubepower_d2dcdb83a1a570dd) }
+
+var fileDescriptor_kubepower_d2dcdb83a1a570dd = []byte{
+	// 228 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3c, 0x90, 0xcf, 0x4a, 0x03, 0x31,
+	0x10, 0xc6, 0xd9, 0x6a, 0xf7, 0xcf, 0x28, 0x54, 0x72, 0x31, 0x8a, 0x42, 0xf5, 0x54, 0x2f, 0x5e,
+	0x7c, 0x04, 0x41, 0x10, 0x6f, 0x55, 0xcf, 0x21, 0xbb, 0x9d, 0x96, 0xd0, 0x34, 0x19, 0x66, 0x93,
+	0xfa, 0x06, 0x3e, 0x77, 0xd9, 0xd9, 0xa5, 0xa7, 0xe1, 0xfb, 0xfd, 0x3e, 0xbe, 0xc3, 0xc0, 0x62,
+	0x9f, 0x5b, 0xa4, 0xf8, 0x87, 0xfc, 0x4a, 0x1c, 0x53, 0x54, 0x73, 0x39, 0xcf, 0xff, 0x33, 0x80,
+	0xaf, 0xdc, 0xe2, 0x7b, 0x0c, 0x5b, 0xb7, 0x53, 0x8f, 0x00, 0x96, 0x9c, 0xe9, 0x91, 0x8f, 0xc8,
+	0xba, 0x58, 0x16, 0xab, 0x66, 0xdd, 0x58, 0x72, 0xdf, 0x02, 0x06, 0x9d, 0xe2, 0x1e, 0x83, 0xd9,
+	0x3a, 0x8f, 0x7a, 0x36, 0x6a, 0x21, 0x1f, 0xce, 0xa3, 0xba, 0x85, 0xaa, 0xb3, 0xa3, 0xbb, 0x10,
+	0x57, 0x76, 0x56, 0xc4, 0x3d, 0xd4, 0x2e, 0xf4, 0xd8, 0x65, 0x46, 0x7d, 0xb9, 0x2c, 0x56, 0xf5,
+	0xfa, 0x9c, 0xd5, 0x03, 0x34, 0xc1, 0x1e, 0xb0, 0x27, 0xdb, 0xa1, 0x9e, 0x8f, 0x93, 0x67, 0xa0,
+	0x9e, 0xe0, 0x9a, 0xe2, 0xc6, 0x24, 0x3c, 0x90, 0xb7, 0x09, 0x75, 0x29, 0x85, 0x2b, 0x8a, 0x9b,
+	0x9f, 0x09, 0xa9, 0x17, 0xb8, 0xa1, 0xe8, 0xbd, 0x0b, 0x3b, 0xe3, 0x42, 0x42, 0x3e, 0x5a, 0xaf,
+	0x2b, 0xa9, 0x2d, 0x26, 0xfe, 0x39, 0x61, 0x75, 0x07, 0xf5, 0x30, 0x6d, 0x32, 0x7b, 0x5d, 0x4b,
+	0xa5, 0x1a, 0xf2, 0x2f, 0xfb, 0xb6, 0x94, 0x7f, 0xbc, 0x9d, 0x06, 0x00, 0xe8, 0x9f, 0x5e, 0x92,
+	0x29, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,487 @@
+/* libvirtpower.go: mutations for libvirt (KVM/QEMU) domains using virsh
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/libvirtpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = libvirt.
+ */
+
+package libvirtpower
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/libvirtpower/proto"
+)
+
+const (
+	PlatformString string = "libvirt"
+
+	// shutdownWait is how long we wait for a guest to shut itself down, it matches the ONtoOFF timeout
+	shutdownWait = 60 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "10s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "60s", // guests get a chance to shut down cleanly
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// Libvirt Object /
+//////////////////
+
+// Libvirt provides a power on/off interface to libvirt domains
+type Libvirt struct {
+	api        lib.APIClient
+	cfg        *pb.LibvirtConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Libvirt)(nil)
+
+// Name returns the FQDN of the module
+func (*Libvirt) Name() string { return "github.com/hpc/kraken/modules/libvirtpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Libvirt)(nil)
+var _ lib.ModuleWithConfigValidation = (*Libvirt)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Libvirt) NewConfig() proto.Message {
+	r := &pb.LibvirtConfig{
+		ServerUrl: "type.googleapis.com/proto.Libvirt/Host",
+		NameUrl:   "type.googleapis.com/proto.Libvirt/Domain",
+		Hosts: map[string]*pb.LibvirtHost{
+			"local": {
+				Name: "local",
+				Uri:  "qemu:///system",
+			},
+		},
+		PollingInterval: "30s",
+		Virsh:           "/usr/bin/virsh",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (lv *Libvirt) UpdateConfig(cfg proto.Message) (e error) {
+	if lvcfg, ok := cfg.(*pb.LibvirtConfig); ok {
+		lv.cfg = lvcfg
+		if lv.pollTicker != nil {
+			lv.pollTicker.Stop()
+			dur, _ := time.ParseDuration(lv.cfg.GetPollingInterval())
+			lv.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, and that every host has a URI
+func (*Libvirt) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.LibvirtConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, h := range c.GetHosts() {
+		if h.GetUri() == "" {
+			return fmt.Errorf("host %s has no URI", name)
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Libvirt) ConfigURL() string {
+	cfg := &pb.LibvirtConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Libvirt)(nil)
+var _ lib.ModuleWithDiscovery = (*Libvirt)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (lv *Libvirt) SetMutationChan(c <-chan lib.Event) { lv.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (lv *Libvirt) SetDiscoveryChan(c chan<- lib.Event) { lv.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Libvirt)(nil)
+
+// Entry is the module's executable entrypoint
+func (lv *Libvirt) Entry() {
+	url := lib.NodeURLJoin(lv.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "libvirtpower"), "State"))
+	lv.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  lv.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(lv.cfg.GetPollingInterval())
+	lv.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-lv.pollTicker.C:
+			go lv.discoverAll()
+			break
+		case m := <-lv.mchan: // mutation request
+			go lv.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (lv *Libvirt) Init(api lib.APIClient) {
+	lv.api = api
+	lv.cfg = lv.NewConfig().(*pb.LibvirtConfig)
+}
+
+// Stop should perform a graceful exit
+func (lv *Libvirt) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (lv *Libvirt) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		lv.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's domain and host
+	vs := me.NodeCfg.GetValues([]string{lv.cfg.GetNameUrl(), lv.cfg.GetServerUrl()})
+	if len(vs) != 2 {
+		lv.api.Logf(lib.LLERROR, "could not get domain and/or libvirt host for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[lv.cfg.GetNameUrl()].String()
+	srv := vs[lv.cfg.GetServerUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			lv.domDiscover(srv, name, me.NodeCfg.ID())
+		case "OFFtoON":
+			lv.domStart(me.Context, srv, name, me.NodeCfg.ID())
+		case "ONtoOFF":
+			lv.domShutdown(me.Context, srv, name, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			lv.domDestroy(me.Context, srv, name, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			lv.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any virsh command (or shutdown wait) in progress
+		break
+	}
+}
+
+// virsh runs a virsh command against the named host and returns its trimmed output
+// the command is killed if ctx is canceled
+func (lv *Libvirt) virsh(ctx context.Context, srvName string, args ...string) (string, error) {
+	srv, ok := lv.cfg.Hosts[srvName]
+	if !ok {
+		return "", fmt.Errorf("cannot control power for unknown libvirt host: %s", srvName)
+	}
+	cmd := exec.CommandContext(ctx, lv.cfg.GetVirsh(), append([]string{"-c", srv.Uri}, args...)...)
+	out, e := cmd.CombinedOutput()
+	if e != nil {
+		return "", fmt.Errorf("virsh %s failed: %v: %s", strings.Join(args, " "), e, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// domState maps a virsh domstate to a PhysState value ID
+func domState(s string) string {
+	switch s {
+	case "running", "paused", "in shutdown", "pmsuspended":
+		return "POWER_ON"
+	case "shut off", "crashed":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (lv *Libvirt) domDiscover(srvName, name string, id lib.NodeID) {
+	start := time.Now()
+	s, e := lv.virsh(context.Background(), srvName, "domstate", name)
+	if e != nil {
+		lv.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	lv.discover(id, domState(s), start)
+}
+
+// interrupted reports whatever state an interrupted mutation left the domain in
+func (lv *Libvirt) interrupted(srvName, name string, id lib.NodeID) {
+	lv.api.Logf(lib.LLINFO, "interrupted: mutation of domain %s", name)
+	lv.domDiscover(srvName, name, id)
+}
+
+func (lv *Libvirt) domStart(ctx context.Context, srvName, name string, id lib.NodeID) {
+	if _, e := lv.virsh(ctx, srvName, "start", name); e != nil {
+		if ctx.Err() != nil {
+			lv.interrupted(srvName, name, id)
+			return
+		}
+		lv.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	lv.discover(id, "POWER_ON", time.Now())
+}
+
+// domShutdown asks the guest to shut down and waits for it to do so
+func (lv *Libvirt) domShutdown(ctx context.Context, srvName, name string, id lib.NodeID) {
+	if _, e := lv.virsh(ctx, srvName, "shutdown", name); e != nil {
+		if ctx.Err() != nil {
+			lv.interrupted(srvName, name, id)
+			return
+		}
+		lv.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	deadline := time.Now().Add(shutdownWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			lv.interrupted(srvName, name, id)
+			return
+		case <-time.After(2 * time.Second):
+		}
+		at := time.Now()
+		s, e := lv.virsh(ctx, srvName, "domstate", name)
+		if e != nil {
+			lv.api.Logf(lib.LLDEBUG, "%v", e)
+			continue
+		}
+		if domState(s) == "POWER_OFF" {
+			lv.discover(id, "POWER_OFF", at)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG, and HANGtoOFF will destroy it
+	lv.api.Logf(lib.LLERROR, "domain %s did not shut down after %s", name, shutdownWait.String())
+}
+
+func (lv *Libvirt) domDestroy(ctx context.Context, srvName, name string, id lib.NodeID) {
+	if _, e := lv.virsh(ctx, srvName, "destroy", name); e != nil {
+		if ctx.Err() != nil {
+			lv.interrupted(srvName, name, id)
+			return
+		}
+		lv.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	lv.discover(id, "POWER_OFF", time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (lv *Libvirt) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  lv.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	lv.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// we list all domains once per host rather than asking about each one
+func (lv *Libvirt) discoverAll() {
+	lv.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := lv.api.QueryReadAll()
+	if e != nil {
+		lv.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	idmap := make(map[string]lib.NodeID)
+	bySrv := make(map[string][]string)
+
+	// build lists
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", lv.cfg.GetNameUrl(), lv.cfg.GetServerUrl()})
+		if len(vs) != 3 {
+			lv.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete libvirt info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		name := vs[lv.cfg.GetNameUrl()].String()
+		srv := vs[lv.cfg.GetServerUrl()].String()
+		idmap[srv+"/"+name] = n.ID()
+		bySrv[srv] = append(bySrv[srv], name)
+	}
+
+	for s, names := range bySrv {
+		/* example output
+		 *  Id   Name    State
+		 * ------------------------
+		 *  1    node1   running
+		 *  -    node2   shut off
+		 */
+		start := time.Now()
+		out, e := lv.virsh(context.Background(), s, "list", "--all")
+		if e != nil {
+			lv.api.Logf(lib.LLERROR, "%v", e)
+			continue
+		}
+		states := make(map[string]string)
+		for _, l := range strings.Split(out, "\n") {
+			f := strings.Fields(l)
+			if len(f) < 3 || f[0] == "Id" || strings.HasPrefix(f[0], "--") {
+				continue
+			}
+			states[f[1]] = strings.Join(f[2:], " ")
+		}
+		for _, n := range names {
+			st, ok := states[n]
+			if !ok {
+				lv.api.Logf(lib.LLERROR, "domain %s not found on libvirt host %s", n, s)
+				continue
+			}
+			lv.discover(idmap[s+"/"+n], domState(st), start)
+		}
+	}
+}
+
+// initialization
+func init() {
+	module := &Libvirt{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/libvirtpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("libvirtpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
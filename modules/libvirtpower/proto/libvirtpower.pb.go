@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: libvirtpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type LibvirtConfig struct {
+	Hosts                map[string]*LibvirtHost `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                  `protobuf:"bytes,2,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                  `protobuf:"bytes,3,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	ServerUrl            string                  `protobuf:"bytes,4,opt,name=server_url,json=serverUrl,proto3" json:"server_url,omitempty"`
+	Virsh                string                  `protobuf:"bytes,5,opt,name=virsh,proto3" json:"virsh,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *LibvirtConfig) Reset()         { *m = LibvirtConfig{} }
+func (m *LibvirtConfig) String() string { return proto.CompactTextString(m) }
+func (*LibvirtConfig) ProtoMessage()    {}
+func (*LibvirtConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_libvirtpower_f6a8aa77d7dde7a1, []int{0}
+}
+func (m *LibvirtConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LibvirtConfig.Unmarshal(m, b)
+}
+func (m *LibvirtConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LibvirtConfig.Marshal(b, m, deterministic)
+}
+func (dst *LibvirtConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LibvirtConfig.Merge(dst, src)
+}
+func (m *LibvirtConfig) XXX_Size() int {
+	return xxx_messageInfo_LibvirtConfig.Size(m)
+}
+func (m *LibvirtConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_LibvirtConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LibvirtConfig proto.InternalMessageInfo
+
+func (m *LibvirtConfig) GetHosts() map[string]*LibvirtHost {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+func (m *LibvirtConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *LibvirtConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *LibvirtConfig) GetServerUrl() string {
+	if m != nil {
+		return m.ServerUrl
+	}
+	return ""
+}
+
+func (m *LibvirtConfig) GetVirsh() string {
+	if m != nil {
+		return m.Virsh
+	}
+	return ""
+}
+
+type LibvirtHost struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Uri                  string   `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LibvirtHost) Reset()         { *m = LibvirtHost{} }
+func (m *LibvirtHost) String() string { return proto.CompactTextString(m) }
+func (*LibvirtHost) ProtoMessage()    {}
+func (*LibvirtHost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_libvirtpower_f6a8aa77d7dde7a1, []int{1}
+}
+func (m *LibvirtHost) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LibvirtHost.Unmarshal(m, b)
+}
+func (m *LibvirtHost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LibvirtHost.Marshal(b, m, deterministic)
+}
+func (dst *LibvirtHost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LibvirtHost.Merge(dst, src)
+}
+func (m *LibvirtHost) XXX_Size() int {
+	return xxx_messageInfo_LibvirtHost.Size(m)
+}
+func (m *LibvirtHost) XXX_DiscardUnknown() {
+	xxx_messageInfo_LibvirtHost.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LibvirtHost proto.InternalMessageInfo
+
+func (m *LibvirtHost) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LibvirtHost) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*LibvirtConfig)(nil), "proto.LibvirtConfig")
+	proto.RegisterMapType((map[string]*LibvirtHost)(nil), "proto.LibvirtConfig.HostsEntry")
+	proto.RegisterType((*LibvirtHost)(nil), "proto.LibvirtHost")
+}
+
+func init() { proto.RegisterFile("libvirtpower.proto", fileDescriptor_libvirtpower_f6a8aa77d7dde7a1) }
+
+var fileDescriptor_libvirtpower_f6a8aa77d7dde7a1 = []byte{
+	// 247 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x8f, 0xc1, 0x4a, 0xc3, 0x40,
+	0x10, 0x86, 0x49, 0xd2, 0x55, 0x3b, 0x41, 0x2c, 0x83, 0x87, 0x55, 0x10, 0x4b, 0x4f, 0xf1, 0x92,
+	0x43, 0x8b, 0x20, 0x5e, 0x45, 0x50, 0xe8, 0x29, 0xe0, 0xb9, 0xa4, 0xb0, 0xb6, 0x8b, 0xeb, 0x6e,
+	0x98, 0x6c, 0x56, 0xfa, 0x14, 0xbe, 0xb2, 0xec, 0x6c, 0xc0, 0xf6, 0x94, 0x99, 0xf9, 0xbe, 0xcc,
+	0xfe, 0x03, 0x68, 0xf4, 0x36, 0x68, 0xf2, 0x9d, 0xfb, 0x51, 0x54, 0x77, 0xe4, 0xbc, 0x43, 0xc1,
+	0x9f, 0xc5, 0x6f, 0x0e, 0x97, 0xeb, 0x44, 0x5f, 0x9c, 0xfd, 0xd4, 0x3b, 0x7c, 0x04, 0xb1, 0x77,
+	0xbd, 0xef, 0x65, 0x36, 0x2f, 0xaa, 0x72, 0x79, 0x9f, 0xfc, 0xfa, 0x44, 0xaa, 0xdf, 0xa2, 0xf1,
+	0x6a, 0x3d, 0x1d, 0x9a, 0x64, 0xe3, 0x03, 0xcc, 0x3a, 0x67, 0x8c, 0xb6, 0xbb, 0x8d, 0xb6, 0x5e,
+	0x51, 0x68, 0x8d, 0xcc, 0xe7, 0x59, 0x35, 0x6d, 0xae, 0xc6, 0xf9, 0xfb, 0x38, 0xc6, 0x1b, 0xb8,
+	0xb0, 0xed, 0xb7, 0xda, 0x0c, 0x64, 0x64, 0xc1, 0xca, 0x79, 0xec, 0x3f, 0xc8, 0xe0, 0x1d, 0x40,
+	0xaf, 0x28, 0x28, 0x62, 0x38, 0x61, 0x38, 0x4d, 0x93, 0x88, 0xaf, 0x41, 0x04, 0x4d, 0xfd, 0x5e,
+	0x0a, 0x26, 0xa9, 0xb9, 0x5d, 0x03, 0xfc, 0xe7, 0xc1, 0x19, 0x14, 0x5f, 0xea, 0x20, 0x33, 0x36,
+	0x62, 0x89, 0x15, 0x88, 0xd0, 0x9a, 0x41, 0x71, 0x9e, 0x72, 0x89, 0xa7, 0x17, 0xc5, 0x5f, 0x9b,
+	0x24, 0x3c, 0xe7, 0x4f, 0xd9, 0x62, 0x05, 0xe5, 0x11, 0x41, 0x84, 0x49, 0x0c, 0x37, 0xee, 0xe3,
+	0x3a, 0x3e, 0x31, 0x90, 0x1e, 0xcf, 0x8b, 0xe5, 0xf6, 0x8c, 0x57, 0xae, 0xfe, 0x06, 0x00, 0x30,
+	0x8c, 0x4e, 0xcf, 0x6a, 0x01, 0x00, 0x00,
+}
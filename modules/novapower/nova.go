@@ -0,0 +1,259 @@
+/* nova.go: a minimal keystone v3 / nova client
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package novapower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/novapower/proto"
+)
+
+// novaClient holds a keystone token and the compute endpoint it found in the catalog
+type novaClient struct {
+	auth     *pb.KeystoneAuth
+	region   string
+	iface    string
+	client   *http.Client
+	mutex    sync.Mutex
+	token    string
+	expires  time.Time
+	endpoint string
+}
+
+func newNovaClient(cfg *pb.NovaConfig) *novaClient {
+	return &novaClient{
+		auth:   cfg.GetAuth(),
+		region: cfg.GetRegion(),
+		iface:  cfg.GetInterface(),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.GetInsecure()},
+			},
+		},
+	}
+}
+
+// keystone token request/response documents
+type ksAuthReq struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User ksUser `json:"user"`
+			} `json:"password"`
+		} `json:"identity"`
+		Scope struct {
+			Project ksProject `json:"project"`
+		} `json:"scope"`
+	} `json:"auth"`
+}
+
+type ksDomain struct {
+	Name string `json:"name"`
+}
+
+type ksUser struct {
+	Name     string   `json:"name"`
+	Domain   ksDomain `json:"domain"`
+	Password string   `json:"password"`
+}
+
+type ksProject struct {
+	Name   string   `json:"name"`
+	Domain ksDomain `json:"domain"`
+}
+
+type ksAuthResp struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Catalog   []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// login gets a project scoped token and finds the compute endpoint
+// must be called with the mutex held
+func (c *novaClient) login() (e error) {
+	var ar ksAuthReq
+	ar.Auth.Identity.Methods = []string{"password"}
+	ar.Auth.Identity.Password.User = ksUser{
+		Name:     c.auth.GetUsername(),
+		Domain:   ksDomain{Name: c.auth.GetUserDomainName()},
+		Password: c.auth.GetPassword(),
+	}
+	ar.Auth.Scope.Project = ksProject{
+		Name:   c.auth.GetProjectName(),
+		Domain: ksDomain{Name: c.auth.GetProjectDomainName()},
+	}
+	b, _ := json.Marshal(ar)
+	resp, e := c.client.Post(strings.TrimRight(c.auth.GetAuthUrl(), "/")+"/auth/tokens", "application/json", bytes.NewReader(b))
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("keystone authentication failed: HTTP %v", resp.StatusCode)
+	}
+	var r ksAuthResp
+	if e = json.NewDecoder(resp.Body).Decode(&r); e != nil {
+		return
+	}
+	endpoint := ""
+	for _, s := range r.Token.Catalog {
+		if s.Type != "compute" {
+			continue
+		}
+		for _, ep := range s.Endpoints {
+			if ep.Interface == c.iface && (c.region == "" || ep.Region == c.region) {
+				endpoint = ep.URL
+				break
+			}
+		}
+	}
+	if endpoint == "" {
+		return fmt.Errorf("no %s compute endpoint found in region %q", c.iface, c.region)
+	}
+	c.token = resp.Header.Get("X-Subject-Token")
+	c.expires = r.Token.ExpiresAt
+	c.endpoint = strings.TrimRight(endpoint, "/")
+	return
+}
+
+// do makes an authenticated request against the compute endpoint
+// path is relative to the endpoint; a 401 causes one re-authentication
+// the request is abandoned if ctx is canceled
+func (c *novaClient) do(ctx context.Context, method, path string, body interface{}, r interface{}) (e error) {
+	var b []byte
+	if body != nil {
+		b, _ = json.Marshal(body)
+	}
+	for try := 0; try < 2; try++ {
+		c.mutex.Lock()
+		if c.token == "" || time.Now().Add(time.Minute).After(c.expires) {
+			if e = c.login(); e != nil {
+				c.mutex.Unlock()
+				return
+			}
+		}
+		token, endpoint := c.token, c.endpoint
+		c.mutex.Unlock()
+
+		var rd io.Reader
+		if b != nil {
+			rd = bytes.NewReader(b)
+		}
+		var req *http.Request
+		if req, e = http.NewRequest(method, endpoint+path, rd); e != nil {
+			return
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("X-Auth-Token", token)
+		req.Header.Set("Accept", "application/json")
+		if b != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		var resp *http.Response
+		if resp, e = c.client.Do(req); e != nil {
+			return
+		}
+		rb, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			c.mutex.Lock()
+			c.token = ""
+			c.mutex.Unlock()
+			e = fmt.Errorf("%s %s: unauthorized", method, path)
+			continue
+		case resp.StatusCode >= 300:
+			return fmt.Errorf("%s %s: HTTP %v: %s", method, path, resp.StatusCode, strings.TrimSpace(string(rb)))
+		}
+		if r != nil {
+			return json.Unmarshal(rb, r)
+		}
+		return nil
+	}
+	return
+}
+
+// novaServer is the part of a server document we care about
+type novaServer struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// Action posts a server action, e.g. {"os-start": null}
+func (c *novaClient) Action(ctx context.Context, id string, action map[string]interface{}) error {
+	return c.do(ctx, http.MethodPost, "/servers/"+id+"/action", action, nil)
+}
+
+// Status gets the status of a single server
+func (c *novaClient) Status(ctx context.Context, id string) (string, error) {
+	var r struct {
+		Server novaServer `json:"server"`
+	}
+	if e := c.do(ctx, http.MethodGet, "/servers/"+id, nil, &r); e != nil {
+		return "", e
+	}
+	return r.Server.Status, nil
+}
+
+// StatusAll returns the status of every server visible to the project
+func (c *novaClient) StatusAll() (map[string]string, error) {
+	st := make(map[string]string)
+	path := "/servers/detail"
+	for {
+		var r struct {
+			Servers []novaServer `json:"servers"`
+			Links   []struct {
+				Rel  string `json:"rel"`
+				Href string `json:"href"`
+			} `json:"servers_links"`
+		}
+		if e := c.do(context.Background(), http.MethodGet, path, nil, &r); e != nil {
+			return nil, e
+		}
+		for _, s := range r.Servers {
+			st[s.ID] = s.Status
+		}
+		next := ""
+		for _, l := range r.Links {
+			if l.Rel == "next" {
+				next = l.Href
+			}
+		}
+		if next == "" || len(r.Servers) == 0 {
+			return st, nil
+		}
+		// links are absolute; keep only the part after /servers
+		i := strings.Index(next, "/servers/detail")
+		if i < 0 {
+			return st, nil
+		}
+		path = next[i:]
+	}
+}
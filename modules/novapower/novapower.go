@@ -0,0 +1,489 @@
+/* novapower.go: mutations for OpenStack Nova servers using the compute API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/novapower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = openstack.
+ */
+
+package novapower
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/novapower/proto"
+)
+
+const (
+	PlatformString string = "openstack"
+
+	// transitionWait is how long we poll a server for its target status, it matches the mutation timeouts
+	transitionWait = 120 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "120s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "240s", // hard reboot, then stop
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////
+// Nova Object /
+//////////////
+
+// Nova provides a power on/off interface to OpenStack Nova servers
+type Nova struct {
+	api        lib.APIClient
+	cfg        *pb.NovaConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	nova       *novaClient
+	novaCfg    *pb.NovaConfig // the config nova was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Nova)(nil)
+
+// Name returns the FQDN of the module
+func (*Nova) Name() string { return "github.com/hpc/kraken/modules/novapower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Nova)(nil)
+var _ lib.ModuleWithConfigValidation = (*Nova)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Nova) NewConfig() proto.Message {
+	r := &pb.NovaConfig{
+		Auth: &pb.KeystoneAuth{
+			AuthUrl:           "http://localhost:5000/v3",
+			UserDomainName:    "Default",
+			ProjectDomainName: "Default",
+		},
+		Region:          "RegionOne",
+		Interface:       "public",
+		ServerUrl:       "type.googleapis.com/proto.Nova/ServerId",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (nc *Nova) UpdateConfig(cfg proto.Message) (e error) {
+	if ncfg, ok := cfg.(*pb.NovaConfig); ok {
+		nc.cfg = ncfg
+		if nc.pollTicker != nil {
+			nc.pollTicker.Stop()
+			dur, _ := time.ParseDuration(nc.cfg.GetPollingInterval())
+			nc.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig wants an http(s) keystone auth URL and a positive poll interval
+func (*Nova) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.NovaConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if u, e := url.Parse(c.GetAuth().GetAuthUrl()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid keystone URL: %q", c.GetAuth().GetAuthUrl())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Nova) ConfigURL() string {
+	cfg := &pb.NovaConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Nova)(nil)
+var _ lib.ModuleWithDiscovery = (*Nova)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (nc *Nova) SetMutationChan(c <-chan lib.Event) { nc.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (nc *Nova) SetDiscoveryChan(c chan<- lib.Event) { nc.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Nova)(nil)
+
+// Entry is the module's executable entrypoint
+func (nc *Nova) Entry() {
+	url := lib.NodeURLJoin(nc.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "novapower"), "State"))
+	nc.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  nc.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(nc.cfg.GetPollingInterval())
+	nc.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-nc.pollTicker.C:
+			go nc.discoverAll()
+			break
+		case m := <-nc.mchan: // mutation request
+			go nc.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (nc *Nova) Init(api lib.APIClient) {
+	nc.api = api
+	nc.cfg = nc.NewConfig().(*pb.NovaConfig)
+}
+
+// Stop should perform a graceful exit
+func (nc *Nova) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (nc *Nova) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		nc.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's server ID
+	vs := me.NodeCfg.GetValues([]string{nc.cfg.GetServerUrl()})
+	if len(vs) != 1 {
+		nc.api.Logf(lib.LLERROR, "could not get Nova server ID for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	sid := vs[nc.cfg.GetServerUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			nc.serverDiscover(sid, me.NodeCfg.ID())
+		case "OFFtoON":
+			nc.serverStart(me.Context, sid, me.NodeCfg.ID())
+		case "ONtoOFF":
+			nc.serverStop(me.Context, sid, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			nc.serverReset(me.Context, sid, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			nc.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// client returns the Nova client, creating it if the config has changed
+func (nc *Nova) client() *novaClient {
+	nc.mutex.Lock()
+	defer nc.mutex.Unlock()
+	if nc.nova == nil || nc.novaCfg != nc.cfg {
+		nc.nova = newNovaClient(nc.cfg)
+		nc.novaCfg = nc.cfg
+	}
+	return nc.nova
+}
+
+// serverState maps a Nova server status to a PhysState value ID
+// transitional and error statuses are reported as unknown
+func serverState(s string) string {
+	switch s {
+	case "ACTIVE":
+		return "POWER_ON"
+	case "SHUTOFF":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (nc *Nova) serverDiscover(sid string, id lib.NodeID) {
+	start := time.Now()
+	s, e := nc.client().Status(context.Background(), sid)
+	if e != nil {
+		nc.api.Logf(lib.LLERROR, "failed to get status of server %s: %v", sid, e)
+		return
+	}
+	nc.discover(id, serverState(s), start)
+}
+
+// serverStart starts a server, and waits for it to be active
+// if the mutation is interrupted, we report whatever state the server was left in instead
+func (nc *Nova) serverStart(ctx context.Context, sid string, id lib.NodeID) {
+	c := nc.client()
+	if e := c.Action(ctx, sid, map[string]interface{}{"os-start": nil}); e != nil {
+		if ctx.Err() != nil {
+			nc.api.Logf(lib.LLINFO, "interrupted: starting server %s", sid)
+			nc.serverDiscover(sid, id)
+			return
+		}
+		nc.api.Logf(lib.LLERROR, "failed to start server %s: %v", sid, e)
+		return
+	}
+	if nc.serverWait(ctx, c, sid, "ACTIVE", id) {
+		nc.discover(id, "POWER_ON", time.Now())
+	}
+}
+
+// serverStop stops a server, and waits for it to be shut off
+// if the mutation is interrupted, we report whatever state the server was left in instead
+func (nc *Nova) serverStop(ctx context.Context, sid string, id lib.NodeID) {
+	c := nc.client()
+	if e := c.Action(ctx, sid, map[string]interface{}{"os-stop": nil}); e != nil {
+		if ctx.Err() != nil {
+			nc.api.Logf(lib.LLINFO, "interrupted: stopping server %s", sid)
+			nc.serverDiscover(sid, id)
+			return
+		}
+		nc.api.Logf(lib.LLERROR, "failed to stop server %s: %v", sid, e)
+		return
+	}
+	if nc.serverWait(ctx, c, sid, "SHUTOFF", id) {
+		nc.discover(id, "POWER_OFF", time.Now())
+	}
+}
+
+// serverReset recovers a hung server
+// Nova won't stop a server that isn't ACTIVE, so we hard reboot it first
+func (nc *Nova) serverReset(ctx context.Context, sid string, id lib.NodeID) {
+	c := nc.client()
+	start := time.Now()
+	if s, e := c.Status(ctx, sid); e == nil && s == "SHUTOFF" {
+		nc.discover(id, "POWER_OFF", start)
+		return
+	}
+	if e := c.Action(ctx, sid, map[string]interface{}{"reboot": map[string]string{"type": "HARD"}}); e != nil {
+		if ctx.Err() != nil {
+			nc.api.Logf(lib.LLINFO, "interrupted: rebooting server %s", sid)
+			nc.serverDiscover(sid, id)
+			return
+		}
+		nc.api.Logf(lib.LLERROR, "failed to reboot server %s: %v", sid, e)
+		return
+	}
+	if !nc.serverWait(ctx, c, sid, "ACTIVE", id) {
+		return
+	}
+	nc.serverStop(ctx, sid, id)
+}
+
+// serverWait polls a server until it reaches the target status
+// if ctx is canceled first, it reports the server's state, and gives up
+func (nc *Nova) serverWait(ctx context.Context, c *novaClient, sid, target string, id lib.NodeID) bool {
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			nc.api.Logf(lib.LLINFO, "interrupted: waiting for server %s to be %s", sid, target)
+			nc.serverDiscover(sid, id)
+			return false
+		}
+		s, e := c.Status(ctx, sid)
+		if e != nil {
+			nc.api.Logf(lib.LLDEBUG, "failed to get status of server %s: %v", sid, e)
+			continue
+		}
+		if s == target {
+			return true
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	nc.api.Logf(lib.LLERROR, "server %s did not reach %s after %s", sid, target, transitionWait.String())
+	return false
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (nc *Nova) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  nc.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	nc.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// we list all servers once rather than asking about each one
+func (nc *Nova) discoverAll() {
+	nc.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := nc.api.QueryReadAll()
+	if e != nil {
+		nc.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	idmap := make(map[string]lib.NodeID)
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", nc.cfg.GetServerUrl()})
+		if len(vs) != 2 {
+			nc.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete Nova info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		idmap[vs[nc.cfg.GetServerUrl()].String()] = n.ID()
+	}
+	if len(idmap) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := nc.client().StatusAll()
+	if e != nil {
+		nc.api.Logf(lib.LLERROR, "failed to list servers: %v", e)
+		return
+	}
+	for sid, id := range idmap {
+		s, ok := st[sid]
+		if !ok {
+			nc.api.Logf(lib.LLERROR, "server %s not found", sid)
+			continue
+		}
+		nc.discover(id, serverState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &Nova{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/novapower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("novapower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
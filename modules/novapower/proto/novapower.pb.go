@@ -0,0 +1,205 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: novapower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type NovaConfig struct {
+	Auth                 *KeystoneAuth `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	Region               string        `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Interface            string        `protobuf:"bytes,3,opt,name=interface,proto3" json:"interface,omitempty"`
+	Insecure             bool          `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	PollingInterval      string        `protobuf:"bytes,5,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	ServerUrl            string        `protobuf:"bytes,6,opt,name=server_url,json=serverUrl,proto3" json:"server_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *NovaConfig) Reset()         { *m = NovaConfig{} }
+func (m *NovaConfig) String() string { return proto.CompactTextString(m) }
+func (*NovaConfig) ProtoMessage()    {}
+func (*NovaConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_novapower_9bd7de74143a7716, []int{0}
+}
+func (m *NovaConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NovaConfig.Unmarshal(m, b)
+}
+func (m *NovaConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NovaConfig.Marshal(b, m, deterministic)
+}
+func (dst *NovaConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NovaConfig.Merge(dst, src)
+}
+func (m *NovaConfig) XXX_Size() int {
+	return xxx_messageInfo_NovaConfig.Size(m)
+}
+func (m *NovaConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_NovaConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NovaConfig proto.InternalMessageInfo
+
+func (m *NovaConfig) GetAuth() *KeystoneAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *NovaConfig) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *NovaConfig) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *NovaConfig) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *NovaConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *NovaConfig) GetServerUrl() string {
+	if m != nil {
+		return m.ServerUrl
+	}
+	return ""
+}
+
+type KeystoneAuth struct {
+	AuthUrl              string   `protobuf:"bytes,1,opt,name=auth_url,json=authUrl,proto3" json:"auth_url,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	UserDomainName       string   `protobuf:"bytes,4,opt,name=user_domain_name,json=userDomainName,proto3" json:"user_domain_name,omitempty"`
+	ProjectName          string   `protobuf:"bytes,5,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`
+	ProjectDomainName    string   `protobuf:"bytes,6,opt,name=project_domain_name,json=projectDomainName,proto3" json:"project_domain_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeystoneAuth) Reset()         { *m = KeystoneAuth{} }
+func (m *KeystoneAuth) String() string { return proto.CompactTextString(m) }
+func (*KeystoneAuth) ProtoMessage()    {}
+func (*KeystoneAuth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_novapower_9bd7de74143a7716, []int{1}
+}
+func (m *KeystoneAuth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeystoneAuth.Unmarshal(m, b)
+}
+func (m *KeystoneAuth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeystoneAuth.Marshal(b, m, deterministic)
+}
+func (dst *KeystoneAuth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeystoneAuth.Merge(dst, src)
+}
+func (m *KeystoneAuth) XXX_Size() int {
+	return xxx_messageInfo_KeystoneAuth.Size(m)
+}
+func (m *KeystoneAuth) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeystoneAuth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeystoneAuth proto.InternalMessageInfo
+
+func (m *KeystoneAuth) GetAuthUrl() string {
+	if m != nil {
+		return m.AuthUrl
+	}
+	return ""
+}
+
+func (m *KeystoneAuth) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *KeystoneAuth) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *KeystoneAuth) GetUserDomainName() string {
+	if m != nil {
+		return m.UserDomainName
+	}
+	return ""
+}
+
+func (m *KeystoneAuth) GetProjectName() string {
+	if m != nil {
+		return m.ProjectName
+	}
+	return ""
+}
+
+func (m *KeystoneAuth) GetProjectDomainName() string {
+	if m != nil {
+		return m.ProjectDomainName
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*NovaConfig)(nil), "proto.NovaConfig")
+	proto.RegisterType((*KeystoneAuth)(nil), "proto.KeystoneAuth")
+}
+
+func init() { proto.RegisterFile("novapower.proto", fileDescriptor_novapower_9bd7de74143a7716) }
+
+var fileDescriptor_novapower_9bd7de74143a7716 = []byte{
+	// 300 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0x4f, 0x4e, 0x32, 0x41,
+	0x10, 0xc5, 0xd3, 0xdf, 0x07, 0x08, 0x05, 0x11, 0x6c, 0x12, 0x33, 0x1a, 0x4d, 0x90, 0x8d, 0xe3,
+	0x86, 0x85, 0x9e, 0xc0, 0xe8, 0xc6, 0x98, 0xb0, 0x20, 0x61, 0x3d, 0x69, 0xa1, 0x80, 0x31, 0x43,
+	0xd7, 0xa4, 0xe6, 0x0f, 0xf1, 0x98, 0x5e, 0xc0, 0xb3, 0x98, 0xae, 0x6e, 0x07, 0x56, 0x93, 0xf7,
+	0xea, 0x57, 0x6f, 0xea, 0xa5, 0x61, 0x68, 0xa9, 0x36, 0x39, 0x1d, 0x90, 0x67, 0x39, 0x53, 0x49,
+	0xba, 0x2d, 0x9f, 0xe9, 0xb7, 0x02, 0x98, 0x53, 0x6d, 0x5e, 0xc8, 0x6e, 0xd2, 0xad, 0xbe, 0x87,
+	0x96, 0xa9, 0xca, 0x5d, 0xa4, 0x26, 0x2a, 0xee, 0x3f, 0x8e, 0x3d, 0x3b, 0x7b, 0xc7, 0xaf, 0xa2,
+	0x24, 0x8b, 0xcf, 0x55, 0xb9, 0x5b, 0x08, 0xa0, 0x2f, 0xa1, 0xc3, 0xb8, 0x4d, 0xc9, 0x46, 0xff,
+	0x26, 0x2a, 0xee, 0x2d, 0x82, 0xd2, 0x37, 0xd0, 0x4b, 0x6d, 0x89, 0xbc, 0x31, 0x2b, 0x8c, 0xfe,
+	0xcb, 0xe8, 0x68, 0xe8, 0x6b, 0xe8, 0xa6, 0xb6, 0xc0, 0x55, 0xc5, 0x18, 0xb5, 0x26, 0x2a, 0xee,
+	0x2e, 0x1a, 0xad, 0x1f, 0x60, 0x94, 0x53, 0x96, 0xa5, 0x76, 0x9b, 0xc8, 0x42, 0x6d, 0xb2, 0xa8,
+	0x2d, 0x01, 0xc3, 0xe0, 0xbf, 0x05, 0x5b, 0xdf, 0x02, 0x14, 0xc8, 0x35, 0x72, 0x52, 0x71, 0x16,
+	0x75, 0xfc, 0x5f, 0xbc, 0xb3, 0xe4, 0x6c, 0xfa, 0xa3, 0x60, 0x70, 0x7a, 0xb2, 0xbe, 0x82, 0xae,
+	0x3b, 0x5a, 0x68, 0x25, 0xf4, 0x99, 0xd3, 0x4b, 0xce, 0xdc, 0x45, 0x55, 0x81, 0x6c, 0xcd, 0x1e,
+	0x43, 0x93, 0x46, 0xbb, 0x59, 0x6e, 0x8a, 0xe2, 0x40, 0xbc, 0x0e, 0x55, 0x1a, 0xad, 0x63, 0x18,
+	0x39, 0x2e, 0x59, 0xd3, 0xde, 0xa4, 0x36, 0x91, 0xfd, 0x96, 0x30, 0xe7, 0xce, 0x7f, 0x15, 0x7b,
+	0xee, 0x52, 0xee, 0x60, 0x90, 0x33, 0x7d, 0xe2, 0xaa, 0xf4, 0x94, 0xef, 0xd4, 0x0f, 0x9e, 0x20,
+	0x33, 0x18, 0xff, 0x21, 0xa7, 0x79, 0xbe, 0xd8, 0x45, 0x18, 0x1d, 0x23, 0x3f, 0x3a, 0xf2, 0x2c,
+	0x4f, 0xbf, 0x03, 0x00, 0x0b, 0xcf, 0xaf, 0x7b, 0xd5, 0x01, 0x00, 0x00,
+}
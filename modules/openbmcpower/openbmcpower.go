@@ -0,0 +1,585 @@
+/* openbmcpower.go: mutations for OpenBMC hosts using the OpenBMC D-Bus REST interface
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/openbmcpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = openbmc.
+ * BMCs are looked up in the config by the value found at NameUrl.
+ */
+
+package openbmcpower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/openbmcpower/proto"
+)
+
+const (
+	OBMCLogin       string = "/login"
+	OBMCLogout      string = "/logout"
+	OBMCChassisAttr string = "/xyz/openbmc_project/state/chassis0/attr/"
+	OBMCHostAttr    string = "/xyz/openbmc_project/state/host0/attr/"
+	PlatformString  string = "openbmc"
+
+	chassisStatePrefix string = "xyz.openbmc_project.State.Chassis.PowerState."
+	chassisTransPrefix string = "xyz.openbmc_project.State.Chassis.Transition."
+	hostTransPrefix    string = "xyz.openbmc_project.State.Host.Transition."
+
+	// transitionWait is how long we watch for a transition to complete, it matches our longest mutation timeout
+	transitionWait = 120 * time.Second
+)
+
+// obmcData is the envelope the OpenBMC REST server uses for requests and responses
+type obmcData struct {
+	Data    interface{} `json:"data"`
+	Message string      `json:"message,omitempty"`
+	Status  string      `json:"status,omitempty"`
+}
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "15s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "60s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s", // the host gets a chance to shut down cleanly
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "60s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// OBMC Object /
+//////////////////
+
+// OBMC provides a power on/off interface to OpenBMC
+type OBMC struct {
+	api        lib.APIClient
+	cfg        *pb.OBMCConfig
+	sessions   map[string]*obmcSession // by node name; rebuilt with the config
+	mutex      sync.Mutex              // guards sessions
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*OBMC)(nil)
+
+// Name returns the FQDN of the module
+func (*OBMC) Name() string { return "github.com/hpc/kraken/modules/openbmcpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*OBMC)(nil)
+var _ lib.ModuleWithConfigValidation = (*OBMC)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*OBMC) NewConfig() proto.Message {
+	r := &pb.OBMCConfig{
+		NameUrl:         "/Nodename",
+		Bmcs:            map[string]*pb.OBMCBmc{},
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (ob *OBMC) UpdateConfig(cfg proto.Message) (e error) {
+	if obcfg, ok := cfg.(*pb.OBMCConfig); ok {
+		ob.mutex.Lock()
+		old := ob.sessions
+		ob.cfg, ob.sessions = obcfg, newSessions(obcfg)
+		ob.mutex.Unlock()
+		for _, s := range old {
+			go s.close()
+		}
+		if ob.pollTicker != nil {
+			ob.pollTicker.Stop()
+			dur, _ := time.ParseDuration(ob.cfg.GetPollingInterval())
+			ob.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, and that every BMC has an IP and a valid port
+func (*OBMC) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.OBMCConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, bmc := range c.GetBmcs() {
+		if bmc.GetIp() == "" {
+			return fmt.Errorf("BMC %s has no IP", name)
+		}
+		if bmc.GetPort() < 0 || bmc.GetPort() > 65535 {
+			return fmt.Errorf("BMC %s has an invalid port: %d", name, bmc.GetPort())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*OBMC) ConfigURL() string {
+	cfg := &pb.OBMCConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*OBMC)(nil)
+var _ lib.ModuleWithDiscovery = (*OBMC)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (ob *OBMC) SetMutationChan(c <-chan lib.Event) { ob.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (ob *OBMC) SetDiscoveryChan(c chan<- lib.Event) { ob.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*OBMC)(nil)
+
+// Entry is the module's executable entrypoint
+func (ob *OBMC) Entry() {
+	url := lib.NodeURLJoin(ob.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "openbmcpower"), "State"))
+	ob.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ob.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(ob.cfg.GetPollingInterval())
+	ob.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-ob.pollTicker.C:
+			go ob.discoverAll()
+			break
+		case m := <-ob.mchan: // mutation request
+			go ob.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (ob *OBMC) Init(api lib.APIClient) {
+	ob.api = api
+	ob.cfg = ob.NewConfig().(*pb.OBMCConfig)
+	ob.sessions = newSessions(ob.cfg)
+}
+
+// Stop should perform a graceful exit; we log out of the BMCs first, so our sessions don't take up their slots
+func (ob *OBMC) Stop() {
+	ob.mutex.Lock()
+	for _, s := range ob.sessions {
+		s.close()
+	}
+	ob.mutex.Unlock()
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (ob *OBMC) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		ob.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name
+	vs := me.NodeCfg.GetValues([]string{ob.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		ob.api.Logf(lib.LLERROR, "could not get BMC name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[ob.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			ob.nodeDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			ob.nodeTransition(me.Context, name, OBMCHostAttr+"RequestedHostTransition", hostTransPrefix+"On", "On", me.NodeCfg.ID())
+		case "ONtoOFF": // ask the host to shut down; OpenBMC forces it off if that takes too long
+			ob.nodeTransition(me.Context, name, OBMCHostAttr+"RequestedHostTransition", hostTransPrefix+"Off", "Off", me.NodeCfg.ID())
+		case "HANGtoOFF": // no point asking a hung host, cut chassis power
+			ob.nodeTransition(me.Context, name, OBMCChassisAttr+"RequestedPowerTransition", chassisTransPrefix+"Off", "Off", me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			ob.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// obmcSession is a session with one BMC.  It logs in when it's first used, and again if the BMC forgets it.
+type obmcSession struct {
+	base     string
+	username string
+	password string
+	client   *http.Client
+	mutex    sync.Mutex // held while logging in or out
+	loggedIn bool
+}
+
+// newSessions makes a session for the BMC of each node in cfg
+func newSessions(cfg *pb.OBMCConfig) map[string]*obmcSession {
+	r := make(map[string]*obmcSession)
+	for name, bmc := range cfg.Bmcs {
+		port := bmc.Port
+		if port == 0 {
+			port = 443
+		}
+		jar, _ := cookiejar.New(nil)
+		r[name] = &obmcSession{
+			base:     "https://" + bmc.Ip + ":" + strconv.Itoa(int(port)),
+			username: bmc.Username,
+			password: bmc.Password,
+			client: &http.Client{
+				Timeout: 10 * time.Second,
+				Jar:     jar,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: bmc.Insecure},
+				},
+			},
+		}
+	}
+	return r
+}
+
+// session gets the session with the BMC of the named node
+func (ob *OBMC) session(name string) (*obmcSession, error) {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+	s, ok := ob.sessions[name]
+	if !ok {
+		return nil, fmt.Errorf("no BMC configured for node: %s", name)
+	}
+	return s, nil
+}
+
+// do makes a request with data wrapped in the OpenBMC envelope, and returns the unwrapped response data
+// We log in first if we need to, and log in again if the BMC has forgotten our session.
+// The request is abandoned if ctx is canceled.
+func (s *obmcSession) do(ctx context.Context, method, path string, data interface{}) (r interface{}, e error) {
+	if e = s.login(ctx, false); e != nil {
+		return
+	}
+	r, code, e := s.request(ctx, method, path, data)
+	if code == http.StatusUnauthorized {
+		if e = s.login(ctx, true); e != nil {
+			return
+		}
+		r, _, e = s.request(ctx, method, path, data)
+	}
+	return
+}
+
+// login logs in, unless we already have (and aren't forced to again)
+func (s *obmcSession) login(ctx context.Context, force bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.loggedIn && !force {
+		return nil
+	}
+	s.loggedIn = false
+	if _, _, e := s.request(ctx, http.MethodPost, OBMCLogin, []string{s.username, s.password}); e != nil {
+		return fmt.Errorf("login failed: %v", e)
+	}
+	s.loggedIn = true
+	return nil
+}
+
+// close logs out, if we're logged in, and closes the session's connections
+func (s *obmcSession) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.loggedIn {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		s.request(ctx, http.MethodPost, OBMCLogout, []string{})
+		cancel()
+		s.loggedIn = false
+	}
+	s.client.CloseIdleConnections()
+}
+
+// request makes one request (see do); code is the HTTP status, if we got one
+func (s *obmcSession) request(ctx context.Context, method, path string, data interface{}) (r interface{}, code int, e error) {
+	var body []byte
+	if data != nil {
+		body, _ = json.Marshal(obmcData{Data: data})
+	}
+	req, e := http.NewRequest(method, s.base+path, bytes.NewReader(body))
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	code = resp.StatusCode
+	rb, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return
+	}
+	var rs obmcData
+	if e = json.Unmarshal(rb, &rs); e != nil {
+		return nil, code, fmt.Errorf("error unmarshaling json: %v", e)
+	}
+	if resp.StatusCode != 200 || rs.Status == "error" {
+		return nil, code, fmt.Errorf("HTTP %v: %s", resp.StatusCode, rs.Message)
+	}
+	return rs.Data, code, nil
+}
+
+// powerState returns the short chassis power state, e.g. "On"
+func (s *obmcSession) powerState(ctx context.Context) (string, error) {
+	d, e := s.do(ctx, http.MethodGet, OBMCChassisAttr+"CurrentPowerState", nil)
+	if e != nil {
+		return "", e
+	}
+	ps, ok := d.(string)
+	if !ok || len(ps) < len(chassisStatePrefix) {
+		return "", fmt.Errorf("unexpected power state: %v", d)
+	}
+	return ps[len(chassisStatePrefix):], nil
+}
+
+func (ob *OBMC) nodeDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	c, e := ob.session(name)
+	if e != nil {
+		ob.api.Logf(lib.LLERROR, "could not reach BMC for %s: %v", name, e)
+		return
+	}
+	s, e := c.powerState(context.Background())
+	if e != nil {
+		ob.api.Logf(lib.LLERROR, "could not get power state for %s: %v", name, e)
+		return
+	}
+	var vid string
+	switch s {
+	case "On":
+		vid = "POWER_ON"
+	case "Off":
+		vid = "POWER_OFF"
+	default:
+		vid = "PHYS_UNKNOWN"
+	}
+	ob.discover(id, vid, start)
+}
+
+// nodeTransition requests a state transition and waits for the chassis to reach want
+// if the mutation is interrupted, we report whatever state the node was left in instead
+func (ob *OBMC) nodeTransition(ctx context.Context, name, attr, trans, want string, id lib.NodeID) {
+	c, e := ob.session(name)
+	if e == nil {
+		_, e = c.do(ctx, http.MethodPut, attr, trans)
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			ob.api.Logf(lib.LLINFO, "interrupted: transition %s for %s", trans, name)
+			ob.nodeDiscover(name, id)
+			return
+		}
+		ob.api.Logf(lib.LLERROR, "transition %s failed for %s: %v", trans, name, e)
+		return
+	}
+	// transitions are asynchronous, so wait for the chassis to get there
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			ob.api.Logf(lib.LLINFO, "interrupted: waiting for %s to reach power state %s", name, want)
+			ob.nodeDiscover(name, id)
+			return
+		}
+		at := time.Now()
+		s, e := c.powerState(ctx)
+		if e != nil {
+			ob.api.Logf(lib.LLDEBUG, "could not get power state for %s: %v", name, e)
+			continue
+		}
+		if s != want {
+			continue
+		}
+		if want == "On" {
+			ob.discover(id, "POWER_ON", at)
+		} else {
+			ob.discover(id, "POWER_OFF", at)
+		}
+		return
+	}
+	ob.api.Logf(lib.LLERROR, "%s did not reach power state %s after %s", name, want, transitionWait.String())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (ob *OBMC) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  ob.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	ob.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (ob *OBMC) discoverAll() {
+	ob.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := ob.api.QueryReadAll()
+	if e != nil {
+		ob.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", ob.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			ob.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete OpenBMC info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		go ob.nodeDiscover(vs[ob.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &OBMC{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/openbmcpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("openbmcpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
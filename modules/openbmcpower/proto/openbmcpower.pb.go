@@ -0,0 +1,172 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: openbmcpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type OBMCConfig struct {
+	Bmcs                 map[string]*OBMCBmc `protobuf:"bytes,1,rep,name=bmcs,proto3" json:"bmcs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string              `protobuf:"bytes,2,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string              `protobuf:"bytes,3,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *OBMCConfig) Reset()         { *m = OBMCConfig{} }
+func (m *OBMCConfig) String() string { return proto.CompactTextString(m) }
+func (*OBMCConfig) ProtoMessage()    {}
+func (*OBMCConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_openbmcpower_87233980ac309a21, []int{0}
+}
+func (m *OBMCConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OBMCConfig.Unmarshal(m, b)
+}
+func (m *OBMCConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OBMCConfig.Marshal(b, m, deterministic)
+}
+func (dst *OBMCConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OBMCConfig.Merge(dst, src)
+}
+func (m *OBMCConfig) XXX_Size() int {
+	return xxx_messageInfo_OBMCConfig.Size(m)
+}
+func (m *OBMCConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_OBMCConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OBMCConfig proto.InternalMessageInfo
+
+func (m *OBMCConfig) GetBmcs() map[string]*OBMCBmc {
+	if m != nil {
+		return m.Bmcs
+	}
+	return nil
+}
+
+func (m *OBMCConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *OBMCConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type OBMCBmc struct {
+	Ip                   string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool     `protobuf:"varint,5,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OBMCBmc) Reset()         { *m = OBMCBmc{} }
+func (m *OBMCBmc) String() string { return proto.CompactTextString(m) }
+func (*OBMCBmc) ProtoMessage()    {}
+func (*OBMCBmc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_openbmcpower_87233980ac309a21, []int{1}
+}
+func (m *OBMCBmc) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OBMCBmc.Unmarshal(m, b)
+}
+func (m *OBMCBmc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OBMCBmc.Marshal(b, m, deterministic)
+}
+func (dst *OBMCBmc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OBMCBmc.Merge(dst, src)
+}
+func (m *OBMCBmc) XXX_Size() int {
+	return xxx_messageInfo_OBMCBmc.Size(m)
+}
+func (m *OBMCBmc) XXX_DiscardUnknown() {
+	xxx_messageInfo_OBMCBmc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OBMCBmc proto.InternalMessageInfo
+
+func (m *OBMCBmc) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *OBMCBmc) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *OBMCBmc) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *OBMCBmc) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *OBMCBmc) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*OBMCConfig)(nil), "proto.OBMCConfig")
+	proto.RegisterMapType((map[string]*OBMCBmc)(nil), "proto.OBMCConfig.BmcsEntry")
+	proto.RegisterType((*OBMCBmc)(nil), "proto.OBMCBmc")
+}
+
+func init() { proto.RegisterFile("openbmcpower.proto", fileDescriptor_openbmcpower_87233980ac309a21) }
+
+var fileDescriptor_openbmcpower_87233980ac309a21 = []byte{
+	// 268 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8e, 0xcf, 0x4a, 0xf3, 0x40,
+	0x14, 0xc5, 0x99, 0xfc, 0xf9, 0x9a, 0xde, 0x42, 0xbf, 0x72, 0x57, 0x63, 0xdd, 0x84, 0xe2, 0x22,
+	0x6e, 0x22, 0xd4, 0x8d, 0xb8, 0x4c, 0x11, 0x71, 0x21, 0x42, 0xc0, 0x75, 0x49, 0xe2, 0x58, 0x06,
+	0x27, 0x33, 0xc3, 0x4c, 0xd2, 0xd2, 0xa5, 0xef, 0xe7, 0x43, 0x49, 0x26, 0x63, 0x74, 0x75, 0xef,
+	0x39, 0xe7, 0xc7, 0xe1, 0x00, 0x2a, 0xcd, 0x64, 0xdd, 0x36, 0x5a, 0x9d, 0x98, 0xc9, 0xb5, 0x51,
+	0x9d, 0xc2, 0xd8, 0x9d, 0xcd, 0x17, 0x01, 0x78, 0x29, 0x9e, 0x77, 0x3b, 0x25, 0xdf, 0xf9, 0x01,
+	0x6f, 0x20, 0xaa, 0xdb, 0xc6, 0x52, 0x92, 0x86, 0xd9, 0x62, 0x7b, 0x39, 0xb2, 0xf9, 0x2f, 0x90,
+	0x17, 0x6d, 0x63, 0x1f, 0x64, 0x67, 0xce, 0xa5, 0x03, 0xf1, 0x1a, 0x56, 0x5a, 0x09, 0xc1, 0xe5,
+	0x61, 0xcf, 0x65, 0xc7, 0xcc, 0xb1, 0x12, 0x34, 0x48, 0x49, 0x36, 0x2f, 0xff, 0x7b, 0xff, 0xc9,
+	0xdb, 0x78, 0x01, 0x89, 0xac, 0x5a, 0xb6, 0xef, 0x8d, 0xa0, 0xa1, 0x43, 0x66, 0x83, 0x7e, 0x35,
+	0x62, 0xfd, 0x08, 0xf3, 0xa9, 0x18, 0x57, 0x10, 0x7e, 0xb0, 0x33, 0x25, 0x0e, 0x19, 0x5e, 0xbc,
+	0x82, 0xf8, 0x58, 0x89, 0x9e, 0xb9, 0xe6, 0xc5, 0x76, 0xf9, 0x67, 0x56, 0xd1, 0x36, 0xe5, 0x18,
+	0xde, 0x07, 0x77, 0x64, 0xf3, 0x49, 0x60, 0xe6, 0x6d, 0x5c, 0x42, 0xc0, 0xb5, 0xaf, 0x09, 0xb8,
+	0x46, 0x84, 0x48, 0x2b, 0xd3, 0xb9, 0x92, 0xb8, 0x74, 0x3f, 0xae, 0x21, 0xe9, 0x2d, 0x33, 0xc3,
+	0x0e, 0xbf, 0x69, 0xd2, 0x43, 0xa6, 0x2b, 0x6b, 0x4f, 0xca, 0xbc, 0xd1, 0x68, 0xcc, 0x7e, 0xf4,
+	0x90, 0x71, 0x69, 0x59, 0xd3, 0x1b, 0x46, 0xe3, 0x94, 0x64, 0x49, 0x39, 0xe9, 0xfa, 0x9f, 0x5b,
+	0x77, 0xfb, 0x3d, 0x00, 0x1a, 0x09, 0xb4, 0xe6, 0x76, 0x01, 0x00, 0x00,
+}
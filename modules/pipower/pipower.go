@@ -105,7 +105,8 @@ var excs = map[string]reflect.Value{}
 type PiPower struct {
 	api    lib.APIClient
 	mutex  *sync.Mutex
-	queue  map[string][2]string // map[<nodename>][<mutation>, <nodeidstr>]
+	queue  map[string][2]string           // map[<nodename>][<mutation>, <nodeidstr>]
+	events map[string]*core.MutationEvent // the queued mutations, done once they're fired
 	cfg    *pb.PiPowerConfig
 	mchan  <-chan lib.Event
 	dchan  chan<- lib.Event
@@ -214,6 +215,7 @@ func (pp *PiPower) Init(api lib.APIClient) {
 	pp.api = api
 	pp.mutex = &sync.Mutex{}
 	pp.queue = make(map[string][2]string)
+	pp.events = make(map[string]*core.MutationEvent)
 	pp.cfg = pp.NewConfig().(*pb.PiPowerConfig)
 }
 
@@ -259,7 +261,14 @@ func (pp *PiPower) fireChanges() {
 		}
 	}
 	pp.queue = make(map[string][2]string)
+	events := pp.events
+	pp.events = make(map[string]*core.MutationEvent)
 	pp.mutex.Unlock()
+	defer func() {
+		for _, me := range events {
+			me.Done()
+		}
+	}()
 	for c := range on {
 		pp.fire(c, on[c], "/state/on", idmap)
 	}
@@ -279,6 +288,7 @@ func (pp *PiPower) fire(c string, ns []string, cmd string, idmap map[string]stri
 	addr := srv.Ip + ":" + strconv.Itoa(int(srv.Port))
 	nlist := strings.Join(ns, ",")
 	url := "http://" + addr + "/nodes/" + nlist + cmd
+	start := time.Now()
 	resp, e := http.Get(url)
 	if e != nil {
 		pp.api.Logf(lib.LLERROR, "http GET to API failed: %v", e)
@@ -309,6 +319,7 @@ func (pp *PiPower) fire(c string, ns []string, cmd string, idmap map[string]stri
 				Module:  pp.Name(),
 				URL:     url,
 				ValueID: vid,
+				Time:    start,
 			},
 		)
 		pp.dchan <- v
@@ -320,6 +331,12 @@ func (pp *PiPower) handleMutation(m lib.Event) {
 		pp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
 	}
 	me := m.Data().(*core.MutationEvent)
+	queued := false
+	defer func() {
+		if !queued { // queued mutations are done once they're fired
+			me.Done()
+		}
+	}()
 	//nodename := me.NodeCfg.Message().(*cpb.Node).Nodename
 	vs := me.NodeCfg.GetValues([]string{ChassisURL, RankURL})
 	// we make a speciall "nodename" consisting of <chassis>n<rank> to key by
@@ -341,7 +358,12 @@ func (pp *PiPower) handleMutation(m lib.Event) {
 		case "HANGtoOFF":
 			pp.mutex.Lock()
 			pp.queue[nodename] = [2]string{me.Mutation[1], me.NodeCfg.ID().String()}
+			if old, ok := pp.events[nodename]; ok {
+				old.Done() // replaced before it was fired
+			}
+			pp.events[nodename] = me
 			pp.mutex.Unlock()
+			queued = true
 			/*
 					url := lib.NodeURLJoin(me.NodeCfg.ID().String(), "/RunState")
 					ev := core.NewEvent(
@@ -376,6 +398,10 @@ func (pp *PiPower) handleMutation(m lib.Event) {
 	case core.MutationEvent_INTERRUPT:
 		pp.mutex.Lock()
 		delete(pp.queue, nodename)
+		if old, ok := pp.events[nodename]; ok {
+			old.Done()
+			delete(pp.events, nodename)
+		}
 		pp.mutex.Unlock()
 		break
 	}
@@ -289,6 +289,7 @@ func (px *PiPXE) Stop() {
 //////////////////////
 
 func (px *PiPXE) handleMutation(m *core.MutationEvent) {
+	defer m.Done()
 	switch m.Type {
 	case core.MutationEvent_MUTATE:
 		switch m.Mutation[1] {
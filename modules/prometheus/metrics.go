@@ -0,0 +1,203 @@
+/* metrics.go: collects kraken's metrics from the API, and writes them in the Prometheus text format
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// An exposition is metrics in the Prometheus text format
+type exposition struct {
+	bytes.Buffer
+}
+
+// family starts a metric family; its samples have to follow it
+func (x *exposition) family(name, typ, help string) {
+	fmt.Fprintf(x, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample; labels are name, value pairs
+func (x *exposition) sample(name string, v float64, labels ...string) {
+	x.WriteString(name)
+	if len(labels) > 0 {
+		ls := []string{}
+		for i := 0; i+1 < len(labels); i += 2 {
+			ls = append(ls, labels[i]+`="`+labelEscaper.Replace(labels[i+1])+`"`)
+		}
+		x.WriteString("{" + strings.Join(ls, ",") + "}")
+	}
+	x.WriteString(" " + formatValue(v) + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// histogram writes a histogram; kraken's buckets aren't cumulative, Prometheus' are
+func (x *exposition) histogram(name string, h *cpb.Histogram) {
+	var n uint64
+	for i, b := range h.GetBounds() {
+		if i < len(h.GetCounts()) {
+			n += h.GetCounts()[i]
+		}
+		x.sample(name+"_bucket", float64(n), "le", formatValue(b))
+	}
+	x.sample(name+"_bucket", float64(h.GetCount()), "le", "+Inf")
+	x.sample(name+"_sum", h.GetSum())
+	x.sample(name+"_count", float64(h.GetCount()))
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sortedNames gets the names of an enum, in order of their values
+func sortedNames(names map[int32]string) (r []string) {
+	vs := []int{}
+	for v := range names {
+		vs = append(vs, int(v))
+	}
+	sort.Ints(vs)
+	for _, v := range vs {
+		r = append(r, names[int32(v)])
+	}
+	return
+}
+
+// collect gets the metrics from the API.  If a query fails, its metrics are left out, and kraken_up is 0.
+func (p *Prometheus) collect(x *exposition) {
+	up := true
+	failed := func(what string, e error) bool {
+		if e == nil {
+			return false
+		}
+		p.api.Logf(lib.LLERROR, "prometheus couldn't get %s: %v", what, e)
+		up = false
+		return true
+	}
+
+	// nodes, by state
+	if rs, e := p.api.QueryRollups(""); !failed("node states", e) {
+		cluster := &cpb.Rollup{}
+		for _, r := range rs {
+			if r.GetLocation() == "" {
+				cluster = r
+			}
+		}
+		x.family("kraken_nodes", "gauge", "Nodes kraken knows about.")
+		x.sample("kraken_nodes", float64(cluster.GetNodes()))
+		x.family("kraken_nodes_phys_state", "gauge", "Nodes by their discovered PhysState.")
+		for _, s := range sortedNames(cpb.Node_PhysState_name) {
+			x.sample("kraken_nodes_phys_state", float64(cluster.GetPhysState()[s]), "state", s)
+		}
+		x.family("kraken_nodes_run_state", "gauge", "Nodes by their discovered RunState.")
+		for _, s := range sortedNames(cpb.Node_RunState_name) {
+			x.sample("kraken_nodes_run_state", float64(cluster.GetRunState()[s]), "state", s)
+		}
+	}
+
+	// convergence
+	if ncs, e := p.api.QueryConvergence(); !failed("convergence", e) {
+		var converged, diverged, stuck int
+		now := time.Now()
+		x.family("kraken_node_diverged_seconds", "gauge", "How long a node's discovered state hasn't matched its configuration; only nodes that don't match are listed.")
+		for _, nc := range ncs {
+			if nc.GetConverged() {
+				converged++
+				continue
+			}
+			diverged++
+			if !nc.GetMutating() && !nc.GetFrozen() {
+				stuck++
+			}
+			if t, e := ptypes.Timestamp(nc.GetDiverged()); nc.GetDiverged() != nil && e == nil {
+				x.sample("kraken_node_diverged_seconds", now.Sub(t).Seconds(), "id", nc.GetId(), "nodename", nc.GetNodename())
+			}
+		}
+		x.family("kraken_nodes_converged", "gauge", "Nodes whose discovered state matches their configuration.")
+		x.sample("kraken_nodes_converged", float64(converged))
+		x.family("kraken_nodes_diverged", "gauge", "Nodes whose discovered state doesn't match their configuration.")
+		x.sample("kraken_nodes_diverged", float64(diverged))
+		x.family("kraken_nodes_stuck", "gauge", "Nodes whose discovered state doesn't match their configuration, that kraken isn't mutating (and aren't frozen).")
+		x.sample("kraken_nodes_stuck", float64(stuck))
+	}
+
+	// mutations and discoveries
+	if mm, e := p.api.QueryMutationMetrics(); !failed("mutation metrics", e) {
+		if t, e := ptypes.Timestamp(mm.GetSince()); mm.GetSince() != nil && e == nil {
+			x.family("kraken_start_time_seconds", "gauge", "When kraken started counting, in seconds since the epoch.")
+			x.sample("kraken_start_time_seconds", float64(t.UnixNano())/1e9)
+		}
+		for _, c := range []struct {
+			name, help string
+			v          func(*cpb.MutationEdgeMetrics) uint64
+		}{
+			{"kraken_mutations_started_total", "Mutations started.", (*cpb.MutationEdgeMetrics).GetStarted},
+			{"kraken_mutations_succeeded_total", "Mutations that succeeded.", (*cpb.MutationEdgeMetrics).GetSucceeded},
+			{"kraken_mutations_failed_total", "Mutations that failed (timed out, or were interrupted).", (*cpb.MutationEdgeMetrics).GetFailed},
+			{"kraken_mutations_retried_total", "Mutations that were retried.", (*cpb.MutationEdgeMetrics).GetRetried},
+		} {
+			x.family(c.name, "counter", c.help)
+			for _, em := range mm.GetEdges() {
+				x.sample(c.name, float64(c.v(em)), "module", em.GetModule(), "mutation", em.GetMutation())
+			}
+		}
+		x.family("kraken_mutations_active", "gauge", "Mutation chains in progress.")
+		x.sample("kraken_mutations_active", float64(mm.GetActive()))
+		x.family("kraken_converge_seconds", "histogram", "Time from starting a mutation chain on a node to completing it.")
+		x.histogram("kraken_converge_seconds", mm.GetConverge())
+		x.family("kraken_discoveries_total", "counter", "Discovered changes to URLs that mutate.")
+		x.sample("kraken_discoveries_total", float64(mm.GetDiscoveries()))
+	}
+
+	// services
+	if ss, e := p.api.QueryServiceStatus(); !failed("service status", e) {
+		x.family("kraken_service_up", "gauge", "Whether a service is running (and has called in).")
+		for _, s := range ss {
+			x.sample("kraken_service_up", boolValue(s.GetStatus() == cpb.ServiceStatus_RUNNING), "id", s.GetId(), "module", s.GetModule())
+		}
+		x.family("kraken_service_status", "gauge", "A service's status; 1 for the status it's in.")
+		for _, s := range ss {
+			for _, st := range sortedNames(cpb.ServiceStatus_Status_name) {
+				x.sample("kraken_service_status", boolValue(s.GetStatus().String() == st), "id", s.GetId(), "module", s.GetModule(), "status", st)
+			}
+		}
+		x.family("kraken_service_restarts", "gauge", "Times a service has been restarted since it last stayed up.")
+		for _, s := range ss {
+			x.sample("kraken_service_restarts", float64(s.GetRestarts()), "id", s.GetId(), "module", s.GetModule())
+		}
+	}
+
+	x.family("kraken_up", "gauge", "Whether kraken answered every query for these metrics.")
+	x.sample("kraken_up", boolValue(up))
+}
@@ -0,0 +1,222 @@
+/* prometheus.go: serves metrics about kraken (node states, mutations, discoveries, services and convergence) for Prometheus to scrape
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/prometheus.proto
+
+package prometheus
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/prometheus/proto"
+)
+
+var _ lib.Module = (*Prometheus)(nil)
+var _ lib.ModuleSelfService = (*Prometheus)(nil)
+var _ lib.ModuleWithConfig = (*Prometheus)(nil)
+var _ lib.ModuleWithConfigValidation = (*Prometheus)(nil)
+var _ lib.ModuleWithShutdown = (*Prometheus)(nil)
+
+// Prometheus serves metrics in the Prometheus text format; they're collected from the API on each scrape
+type Prometheus struct {
+	cfg      *pb.PrometheusConfig
+	api      lib.APIClient
+	mutex    sync.Mutex
+	srv      *http.Server
+	stopping bool
+}
+
+/*
+ * lib.Module
+ */
+
+func (p *Prometheus) Name() string { return "github.com/hpc/kraken/modules/prometheus" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+
+func (p *Prometheus) NewConfig() proto.Message {
+	return &pb.PrometheusConfig{
+		Addr: "127.0.0.1",
+		Port: 3142,
+		Path: "/metrics",
+	}
+}
+
+func (p *Prometheus) UpdateConfig(cfg proto.Message) (e error) {
+	pc, ok := cfg.(*pb.PrometheusConfig)
+	if !ok {
+		return fmt.Errorf("wrong config type")
+	}
+	p.mutex.Lock()
+	p.cfg = pc
+	srv := p.srv
+	p.mutex.Unlock()
+	if srv != nil {
+		srv.Close() // we just stop, entry will (re)start
+	}
+	return
+}
+
+func (p *Prometheus) ConfigURL() string {
+	a, _ := ptypes.MarshalAny(p.NewConfig())
+	return a.GetTypeUrl()
+}
+
+// ValidateConfig rejects ports outside 1-65535, and a metrics path that isn't absolute
+func (p *Prometheus) ValidateConfig(cfg proto.Message) (e error) {
+	pc, ok := cfg.(*pb.PrometheusConfig)
+	if !ok {
+		return fmt.Errorf("wrong config type")
+	}
+	if pc.GetPort() < 1 || pc.GetPort() > 65535 {
+		return fmt.Errorf("invalid port: %d", pc.GetPort())
+	}
+	if pc.GetPath() != "" && !strings.HasPrefix(pc.GetPath(), "/") {
+		return fmt.Errorf("path must start with /: %s", pc.GetPath())
+	}
+	return
+}
+
+/*
+ * lib.ModuleSelfService
+ */
+
+func (p *Prometheus) Init(api lib.APIClient) {
+	p.api = api
+	if p.cfg == nil {
+		p.cfg = p.NewConfig().(*pb.PrometheusConfig)
+	}
+}
+
+func (p *Prometheus) Entry() {
+	for {
+		p.serve()
+		p.mutex.Lock()
+		stopping := p.stopping
+		p.mutex.Unlock()
+		if stopping {
+			select {} // we're about to exit
+		}
+	}
+}
+
+func (p *Prometheus) Stop() { os.Exit(0) }
+
+// Shutdown stops the listener once the scrapes it's handling are done
+func (p *Prometheus) Shutdown(ctx context.Context) error {
+	p.mutex.Lock()
+	p.stopping = true
+	srv := p.srv
+	p.mutex.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// serve serves metrics until the server is closed
+func (p *Prometheus) serve() {
+	p.mutex.Lock()
+	cfg := p.cfg
+	p.mutex.Unlock()
+	path := cfg.GetPath()
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, p.authorize(cfg, http.HandlerFunc(p.metrics)))
+	addr := fmt.Sprintf("%s:%d", cfg.GetAddr(), cfg.GetPort())
+	l, e := net.Listen("tcp", addr)
+	if e != nil {
+		p.api.Logf(lib.LLERROR, "prometheus couldn't listen: %v", e)
+		time.Sleep(time.Second) // don't spin if we can't start
+		return
+	}
+	srv := &http.Server{Handler: mux}
+	p.mutex.Lock()
+	p.srv = srv
+	p.mutex.Unlock()
+	p.api.Logf(lib.LLINFO, "prometheus is serving metrics at: %s%s", addr, path)
+	if e = srv.Serve(l); e != http.ErrServerClosed {
+		p.api.Logf(lib.LLNOTICE, "prometheus stopped: %v", e)
+	}
+	p.mutex.Lock()
+	p.srv = nil
+	p.mutex.Unlock()
+	p.api.Log(lib.LLNOTICE, "prometheus listener stopped")
+}
+
+// authorize only passes on scrapes with one of the config's bearer tokens, if it has any
+func (p *Prometheus) authorize(cfg *pb.PrometheusConfig, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(cfg.GetTokens()) == 0 {
+			h.ServeHTTP(w, req)
+			return
+		}
+		auth := req.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") {
+			token := []byte(strings.TrimPrefix(auth, "Bearer "))
+			for _, t := range cfg.GetTokens() {
+				if subtle.ConstantTimeCompare(token, []byte(t)) == 1 {
+					h.ServeHTTP(w, req)
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+	})
+}
+
+// metrics collects and writes the metrics
+func (p *Prometheus) metrics(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	if req.Method != "GET" && req.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	x := &exposition{}
+	p.collect(x)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(x.Bytes())
+}
+
+// initialization
+func init() {
+	module := &Prometheus{}
+	core.Registry.RegisterModule(module)
+	si := core.NewServiceInstance(
+		"prometheus",
+		module.Name(),
+		module.Entry,
+		nil,
+	)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{
+		si.ID(): si,
+	})
+}
@@ -0,0 +1,99 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: prometheus.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type PrometheusConfig struct {
+	Addr                 string   `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Tokens               []string `protobuf:"bytes,4,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrometheusConfig) Reset()         { *m = PrometheusConfig{} }
+func (m *PrometheusConfig) String() string { return proto.CompactTextString(m) }
+func (*PrometheusConfig) ProtoMessage()    {}
+func (*PrometheusConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_prometheus_9b110e7aba576321, []int{0}
+}
+func (m *PrometheusConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrometheusConfig.Unmarshal(m, b)
+}
+func (m *PrometheusConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrometheusConfig.Marshal(b, m, deterministic)
+}
+func (dst *PrometheusConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrometheusConfig.Merge(dst, src)
+}
+func (m *PrometheusConfig) XXX_Size() int {
+	return xxx_messageInfo_PrometheusConfig.Size(m)
+}
+func (m *PrometheusConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrometheusConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrometheusConfig proto.InternalMessageInfo
+
+func (m *PrometheusConfig) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *PrometheusConfig) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *PrometheusConfig) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PrometheusConfig) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PrometheusConfig)(nil), "proto.PrometheusConfig")
+}
+
+func init() { proto.RegisterFile("prometheus.proto", fileDescriptor_prometheus_9b110e7aba576321) }
+
+var fileDescriptor_prometheus_9b110e7aba576321 = []byte{
+	// 121 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x28, 0x28, 0xca, 0xcf,
+	0x4d, 0x2d, 0xc9, 0x48, 0x2d, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53,
+	0x4a, 0x69, 0x5c, 0x02, 0x01, 0x70, 0x29, 0xe7, 0xfc, 0xbc, 0xb4, 0xcc, 0x74, 0x21, 0x21, 0x2e,
+	0x96, 0xc4, 0x94, 0x94, 0x22, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x30, 0x1b, 0x24, 0x56,
+	0x90, 0x5f, 0x54, 0x22, 0xc1, 0xa4, 0xc0, 0xa8, 0xc1, 0x1a, 0x04, 0x66, 0x83, 0xc5, 0x12, 0x4b,
+	0x32, 0x24, 0x98, 0x21, 0xea, 0x40, 0x6c, 0x21, 0x31, 0x2e, 0xb6, 0x92, 0xfc, 0xec, 0xd4, 0xbc,
+	0x62, 0x09, 0x16, 0x05, 0x66, 0x0d, 0xce, 0x20, 0x28, 0x2f, 0x89, 0x0d, 0x6c, 0x9d, 0x31, 0x60,
+	0x00, 0xe5, 0x28, 0x87, 0x7b, 0x89, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,199 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proxmoxpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ProxmoxConfig struct {
+	Url                  string                   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Username             string                   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string                   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool                     `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Guests               map[string]*ProxmoxGuest `protobuf:"bytes,5,rep,name=guests,proto3" json:"guests,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                   `protobuf:"bytes,6,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                   `protobuf:"bytes,7,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	ShutdownTimeout      int32                    `protobuf:"varint,8,opt,name=shutdown_timeout,json=shutdownTimeout,proto3" json:"shutdown_timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ProxmoxConfig) Reset()         { *m = ProxmoxConfig{} }
+func (m *ProxmoxConfig) String() string { return proto.CompactTextString(m) }
+func (*ProxmoxConfig) ProtoMessage()    {}
+func (*ProxmoxConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proxmoxpower_74e0224a63da3bf5, []int{0}
+}
+func (m *ProxmoxConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProxmoxConfig.Unmarshal(m, b)
+}
+func (m *ProxmoxConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProxmoxConfig.Marshal(b, m, deterministic)
+}
+func (dst *ProxmoxConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxmoxConfig.Merge(dst, src)
+}
+func (m *ProxmoxConfig) XXX_Size() int {
+	return xxx_messageInfo_ProxmoxConfig.Size(m)
+}
+func (m *ProxmoxConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxmoxConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProxmoxConfig proto.InternalMessageInfo
+
+func (m *ProxmoxConfig) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *ProxmoxConfig) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *ProxmoxConfig) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *ProxmoxConfig) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *ProxmoxConfig) GetGuests() map[string]*ProxmoxGuest {
+	if m != nil {
+		return m.Guests
+	}
+	return nil
+}
+
+func (m *ProxmoxConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *ProxmoxConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *ProxmoxConfig) GetShutdownTimeout() int32 {
+	if m != nil {
+		return m.ShutdownTimeout
+	}
+	return 0
+}
+
+type ProxmoxGuest struct {
+	Node                 string   `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Vmid                 uint32   `protobuf:"varint,2,opt,name=vmid,proto3" json:"vmid,omitempty"`
+	Type                 string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProxmoxGuest) Reset()         { *m = ProxmoxGuest{} }
+func (m *ProxmoxGuest) String() string { return proto.CompactTextString(m) }
+func (*ProxmoxGuest) ProtoMessage()    {}
+func (*ProxmoxGuest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_proxmoxpower_74e0224a63da3bf5, []int{1}
+}
+func (m *ProxmoxGuest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProxmoxGuest.Unmarshal(m, b)
+}
+func (m *ProxmoxGuest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProxmoxGuest.Marshal(b, m, deterministic)
+}
+func (dst *ProxmoxGuest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxmoxGuest.Merge(dst, src)
+}
+func (m *ProxmoxGuest) XXX_Size() int {
+	return xxx_messageInfo_ProxmoxGuest.Size(m)
+}
+func (m *ProxmoxGuest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxmoxGuest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProxmoxGuest proto.InternalMessageInfo
+
+func (m *ProxmoxGuest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *ProxmoxGuest) GetVmid() uint32 {
+	if m != nil {
+		return m.Vmid
+	}
+	return 0
+}
+
+func (m *ProxmoxGuest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ProxmoxConfig)(nil), "proto.ProxmoxConfig")
+	proto.RegisterMapType((map[string]*ProxmoxGuest)(nil), "proto.ProxmoxConfig.GuestsEntry")
+	proto.RegisterType((*ProxmoxGuest)(nil), "proto.ProxmoxGuest")
+}
+
+func init() { proto.RegisterFile("proxmoxpower.proto", fileDescriptor_proxmoxpower_74e0224a63da3bf5) }
+
+var fileDescriptor_proxmoxpower_74e0224a63da3bf5 = []byte{
+	// 306 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x50, 0xbd, 0x6e, 0xf2, 0x30,
+	0x14, 0x55, 0x80, 0x40, 0x3e, 0xf3, 0x21, 0x90, 0xbb, 0xb8, 0x4c, 0x11, 0x53, 0x58, 0x18, 0xe8,
+	0x82, 0xba, 0x56, 0x55, 0xd5, 0x0e, 0x55, 0x65, 0xb5, 0x33, 0x4a, 0x9b, 0x5b, 0x6a, 0xe1, 0xd8,
+	0x96, 0x7f, 0x80, 0xbc, 0x75, 0x1f, 0xa1, 0xb2, 0x9d, 0x20, 0x98, 0x72, 0xcf, 0x4f, 0xae, 0xcf,
+	0x3d, 0x08, 0x2b, 0x2d, 0x4f, 0xb5, 0x3c, 0x29, 0x79, 0x04, 0xbd, 0x52, 0x5a, 0x5a, 0x89, 0xd3,
+	0xf0, 0x59, 0xfc, 0xf6, 0xd0, 0xe4, 0x2d, 0xaa, 0x0f, 0x52, 0x7c, 0xb3, 0x1d, 0x9e, 0xa1, 0xbe,
+	0xd3, 0x9c, 0x24, 0x79, 0x52, 0xfc, 0xa3, 0x7e, 0xc4, 0x73, 0x94, 0x39, 0x03, 0x5a, 0x94, 0x35,
+	0x90, 0x5e, 0xa0, 0xcf, 0xd8, 0x6b, 0xaa, 0x34, 0xe6, 0x28, 0x75, 0x45, 0xfa, 0x51, 0xeb, 0xb0,
+	0xd7, 0x98, 0x30, 0xf0, 0xe5, 0x34, 0x90, 0x41, 0x9e, 0x14, 0x19, 0x3d, 0x63, 0xbc, 0x41, 0xc3,
+	0x9d, 0x03, 0x63, 0x0d, 0x49, 0xf3, 0x7e, 0x31, 0x5e, 0xe7, 0x31, 0xd6, 0xea, 0x2a, 0xcb, 0xea,
+	0x29, 0x58, 0x1e, 0x85, 0xd5, 0x0d, 0x6d, 0xfd, 0x78, 0x89, 0x66, 0x4a, 0x72, 0xce, 0xc4, 0x6e,
+	0xcb, 0x84, 0x05, 0x7d, 0x28, 0x39, 0x19, 0x86, 0x97, 0xa7, 0x2d, 0xff, 0xdc, 0xd2, 0xf8, 0x16,
+	0x65, 0x3e, 0xe4, 0xd6, 0xdf, 0x33, 0x0a, 0x96, 0x91, 0xc7, 0x1f, 0x9a, 0xfb, 0x2d, 0xe6, 0xc7,
+	0xd9, 0x4a, 0x1e, 0xc5, 0xd6, 0xb2, 0x1a, 0xa4, 0xb3, 0x24, 0xcb, 0x93, 0x22, 0xa5, 0xd3, 0x8e,
+	0x7f, 0x8f, 0xf4, 0xfc, 0x15, 0x8d, 0x2f, 0x72, 0xf8, 0x7e, 0xf6, 0xd0, 0x74, 0xfd, 0xec, 0xa1,
+	0xc1, 0x4b, 0x94, 0x1e, 0x4a, 0xee, 0x62, 0x39, 0xe3, 0xf5, 0xcd, 0xf5, 0x29, 0xe1, 0x5f, 0x1a,
+	0x1d, 0xf7, 0xbd, 0x4d, 0xb2, 0x78, 0x41, 0xff, 0x2f, 0x25, 0x8c, 0xd1, 0x40, 0xc8, 0x0a, 0xda,
+	0x8d, 0x61, 0xf6, 0xdc, 0xa1, 0x66, 0x55, 0xd8, 0x38, 0xa1, 0x61, 0xf6, 0x9c, 0x6d, 0x14, 0xb4,
+	0x35, 0x87, 0xf9, 0x73, 0x18, 0x9e, 0xba, 0xfb, 0x1b, 0x00, 0x89, 0x7c, 0x9a, 0xf4, 0xe2, 0x01,
+	0x00, 0x00,
+}
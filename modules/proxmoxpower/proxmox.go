@@ -0,0 +1,164 @@
+/* proxmox.go: a minimal client for the Proxmox VE API with ticket auth
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package proxmoxpower
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/proxmoxpower/proto"
+)
+
+// tickets are good for two hours, we renew a bit early
+const ticketLife = 90 * time.Minute
+
+type pveClient struct {
+	url     string
+	user    string
+	pass    string
+	client  *http.Client
+	mutex   sync.Mutex
+	ticket  string
+	csrf    string
+	expires time.Time
+}
+
+func newPVEClient(u, user, pass string, insecure bool) *pveClient {
+	return &pveClient{
+		url:  strings.TrimRight(u, "/") + "/api2/json",
+		user: user,
+		pass: pass,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		},
+	}
+}
+
+// login gets a new ticket if ours is missing or stale
+func (c *pveClient) login(force bool) (ticket, csrf string, e error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !force && c.ticket != "" && time.Now().Before(c.expires) {
+		return c.ticket, c.csrf, nil
+	}
+	v := url.Values{
+		"username": {c.user},
+		"password": {c.pass},
+	}
+	resp, e := c.client.PostForm(c.url+"/access/ticket", v)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", "", fmt.Errorf("login failed: %s", resp.Status)
+	}
+	var r struct {
+		Data struct {
+			Ticket string `json:"ticket"`
+			CSRF   string `json:"CSRFPreventionToken"`
+		} `json:"data"`
+	}
+	if e = json.NewDecoder(resp.Body).Decode(&r); e != nil {
+		return
+	}
+	c.ticket, c.csrf = r.Data.Ticket, r.Data.CSRF
+	c.expires = time.Now().Add(ticketLife)
+	return c.ticket, c.csrf, nil
+}
+
+// do makes an authenticated request, logging in again once if the ticket was rejected
+// the response's data member is decoded into r; the request is abandoned if ctx is canceled
+func (c *pveClient) do(ctx context.Context, method, path string, form url.Values, r interface{}) error {
+	for retry := 0; ; retry++ {
+		ticket, csrf, e := c.login(retry > 0)
+		if e != nil {
+			return e
+		}
+		req, e := http.NewRequest(method, c.url+path, strings.NewReader(form.Encode()))
+		if e != nil {
+			return e
+		}
+		req = req.WithContext(ctx)
+		req.AddCookie(&http.Cookie{Name: "PVEAuthCookie", Value: ticket})
+		if method != http.MethodGet {
+			req.Header.Set("CSRFPreventionToken", csrf)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		resp, e := c.client.Do(req)
+		if e != nil {
+			return e
+		}
+		rb, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == 401 && retry == 0 {
+			continue
+		}
+		if resp.StatusCode != 200 {
+			// PVE puts the reason in the status line
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		if r == nil {
+			return nil
+		}
+		d := struct {
+			Data interface{} `json:"data"`
+		}{Data: r}
+		return json.Unmarshal(rb, &d)
+	}
+}
+
+func guestPath(g *pb.ProxmoxGuest) string {
+	return "/nodes/" + url.PathEscape(g.GetNode()) + "/" + g.GetType() + "/" + strconv.FormatUint(uint64(g.GetVmid()), 10)
+}
+
+// Action runs a status action (start, stop, shutdown, reset, reboot) on a guest
+// it returns once PVE has started the task
+func (c *pveClient) Action(ctx context.Context, g *pb.ProxmoxGuest, action string, form url.Values) error {
+	return c.do(ctx, http.MethodPost, guestPath(g)+"/status/"+action, form, nil)
+}
+
+// Status returns a guest's status, e.g. running or stopped
+func (c *pveClient) Status(ctx context.Context, g *pb.ProxmoxGuest) (string, error) {
+	var r struct {
+		Status string `json:"status"`
+	}
+	e := c.do(ctx, http.MethodGet, guestPath(g)+"/status/current", nil, &r)
+	return r.Status, e
+}
+
+// StatusAll returns the status of every guest in the cluster, by vmid
+// vmids are unique across a cluster, so this also finds guests that have migrated
+func (c *pveClient) StatusAll() (map[uint32]string, error) {
+	var r []struct {
+		VMID   uint32 `json:"vmid"`
+		Status string `json:"status"`
+	}
+	if e := c.do(context.Background(), http.MethodGet, "/cluster/resources?type=vm", nil, &r); e != nil {
+		return nil, e
+	}
+	st := make(map[uint32]string)
+	for _, g := range r {
+		st[g.VMID] = g.Status
+	}
+	return st, nil
+}
@@ -0,0 +1,512 @@
+/* proxmoxpower.go: mutations for Proxmox VE virtual machines and containers
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/proxmoxpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = proxmox.
+ */
+
+package proxmoxpower
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/proxmoxpower/proto"
+)
+
+const (
+	PlatformString string = "proxmox"
+
+	// transitionWait is how long we poll a guest for its target state, it matches the mutation timeouts
+	transitionWait = 120 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "120s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s", // this should be more than shutdown_timeout
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "120s",
+	},
+	"HANGtoON": { // reset, so a hung guest doesn't have to go through OFF
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_ON,
+		timeout: "120s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// Proxmox Object /
+//////////////////
+
+// Proxmox provides a power on/off/reset interface to Proxmox VE guests
+type Proxmox struct {
+	api        lib.APIClient
+	cfg        *pb.ProxmoxConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	pve        *pveClient
+	pveCfg     *pb.ProxmoxConfig // the config pve was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*Proxmox)(nil)
+
+// Name returns the FQDN of the module
+func (*Proxmox) Name() string { return "github.com/hpc/kraken/modules/proxmoxpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*Proxmox)(nil)
+var _ lib.ModuleWithConfigValidation = (*Proxmox)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*Proxmox) NewConfig() proto.Message {
+	r := &pb.ProxmoxConfig{
+		Guests:          map[string]*pb.ProxmoxGuest{},
+		ShutdownTimeout: 60,
+		NameUrl:         "/Nodename",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (px *Proxmox) UpdateConfig(cfg proto.Message) (e error) {
+	if pcfg, ok := cfg.(*pb.ProxmoxConfig); ok {
+		px.cfg = pcfg
+		if px.pollTicker != nil {
+			px.pollTicker.Stop()
+			dur, _ := time.ParseDuration(px.cfg.GetPollingInterval())
+			px.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig wants an http(s) PVE URL, a positive poll interval and a shutdown timeout (seconds) of 0 or more
+func (*Proxmox) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.ProxmoxConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if u, e := url.Parse(c.GetUrl()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid PVE URL: %q", c.GetUrl())
+	}
+	if c.GetShutdownTimeout() < 0 {
+		return fmt.Errorf("invalid shutdown timeout: %d", c.GetShutdownTimeout())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*Proxmox) ConfigURL() string {
+	cfg := &pb.ProxmoxConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*Proxmox)(nil)
+var _ lib.ModuleWithDiscovery = (*Proxmox)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (px *Proxmox) SetMutationChan(c <-chan lib.Event) { px.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (px *Proxmox) SetDiscoveryChan(c chan<- lib.Event) { px.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*Proxmox)(nil)
+
+// Entry is the module's executable entrypoint
+func (px *Proxmox) Entry() {
+	url := lib.NodeURLJoin(px.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "proxmoxpower"), "State"))
+	px.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  px.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(px.cfg.GetPollingInterval())
+	px.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-px.pollTicker.C:
+			go px.discoverAll()
+			break
+		case m := <-px.mchan: // mutation request
+			go px.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (px *Proxmox) Init(api lib.APIClient) {
+	px.api = api
+	px.cfg = px.NewConfig().(*pb.ProxmoxConfig)
+}
+
+// Stop should perform a graceful exit
+func (px *Proxmox) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (px *Proxmox) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		px.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	g, ok := px.guest(me.NodeCfg)
+	if !ok {
+		px.api.Logf(lib.LLERROR, "no Proxmox guest configured for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			px.guestDiscover(g, me.NodeCfg.ID())
+		case "OFFtoON":
+			px.guestControl(me.Context, g, "start", nil, "running", me.NodeCfg.ID())
+		case "ONtoOFF":
+			// a clean shutdown, which PVE turns into a stop if it takes too long
+			form := url.Values{
+				"forceStop": {"1"},
+				"timeout":   {strconv.Itoa(int(px.cfg.GetShutdownTimeout()))},
+			}
+			px.guestControl(me.Context, g, "shutdown", form, "stopped", me.NodeCfg.ID())
+		case "HANGtoOFF":
+			px.guestControl(me.Context, g, "stop", nil, "stopped", me.NodeCfg.ID())
+		case "HANGtoON":
+			px.guestReset(me.Context, g, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			px.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// guest finds the configured guest for a node by its name_url value
+func (px *Proxmox) guest(n lib.Node) (*pb.ProxmoxGuest, bool) {
+	v, e := n.GetValue(px.cfg.GetNameUrl())
+	if e != nil {
+		return nil, false
+	}
+	g, ok := px.cfg.GetGuests()[v.String()]
+	if !ok || g.GetNode() == "" || g.GetVmid() == 0 {
+		return nil, false
+	}
+	switch g.GetType() {
+	case "qemu", "lxc":
+	default:
+		return nil, false
+	}
+	return g, true
+}
+
+// client returns the PVE client, creating it if the config has changed
+func (px *Proxmox) client() *pveClient {
+	px.mutex.Lock()
+	defer px.mutex.Unlock()
+	if px.pve == nil || px.pveCfg != px.cfg {
+		px.pve = newPVEClient(px.cfg.GetUrl(), px.cfg.GetUsername(), px.cfg.GetPassword(), px.cfg.GetInsecure())
+		px.pveCfg = px.cfg
+	}
+	return px.pve
+}
+
+// guestState maps a PVE guest status to a PhysState value ID
+func guestState(s string) string {
+	switch s {
+	case "running":
+		return "POWER_ON"
+	case "stopped":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (px *Proxmox) guestDiscover(g *pb.ProxmoxGuest, id lib.NodeID) {
+	start := time.Now()
+	s, e := px.client().Status(context.Background(), g)
+	if e != nil {
+		px.api.Logf(lib.LLERROR, "failed to get status of guest %d: %v", g.GetVmid(), e)
+		return
+	}
+	px.discover(id, guestState(s), start)
+}
+
+// guestControl runs a status action on a guest, and waits for it to reach target
+// if the mutation is interrupted, we report whatever state the guest was left in instead
+func (px *Proxmox) guestControl(ctx context.Context, g *pb.ProxmoxGuest, action string, form url.Values, target string, id lib.NodeID) {
+	c := px.client()
+	if e := c.Action(ctx, g, action, form); e != nil {
+		if ctx.Err() != nil {
+			px.api.Logf(lib.LLINFO, "interrupted: %s of guest %d", action, g.GetVmid())
+			px.guestDiscover(g, id)
+			return
+		}
+		px.api.Logf(lib.LLERROR, "failed to %s guest %d: %v", action, g.GetVmid(), e)
+		return
+	}
+	if px.guestWait(ctx, g, target, id) {
+		px.discover(id, guestState(target), time.Now())
+	}
+}
+
+// guestReset resets a hung guest
+// containers can't be reset, so they get stopped and started
+func (px *Proxmox) guestReset(ctx context.Context, g *pb.ProxmoxGuest, id lib.NodeID) {
+	c := px.client()
+	s, e := c.Status(ctx, g)
+	if e != nil {
+		px.api.Logf(lib.LLERROR, "failed to get status of guest %d: %v", g.GetVmid(), e)
+		return
+	}
+	switch {
+	case s != "running":
+		e = c.Action(ctx, g, "start", nil)
+	case g.GetType() == "qemu":
+		e = c.Action(ctx, g, "reset", nil)
+	default:
+		// we don't report the stop, the mutation is from PHYS_HANG to POWER_ON
+		if e = c.Action(ctx, g, "stop", nil); e == nil {
+			if !px.guestWait(ctx, g, "stopped", id) {
+				return
+			}
+			e = c.Action(ctx, g, "start", nil)
+		}
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			px.api.Logf(lib.LLINFO, "interrupted: reset of guest %d", g.GetVmid())
+			px.guestDiscover(g, id)
+			return
+		}
+		px.api.Logf(lib.LLERROR, "failed to reset guest %d: %v", g.GetVmid(), e)
+		return
+	}
+	if px.guestWait(ctx, g, "running", id) {
+		px.discover(id, "POWER_ON", time.Now())
+	}
+}
+
+// guestWait polls a guest until it reaches target
+// if ctx is canceled first, it reports the guest's state, and gives up
+func (px *Proxmox) guestWait(ctx context.Context, g *pb.ProxmoxGuest, target string, id lib.NodeID) bool {
+	c := px.client()
+	deadline := time.Now().Add(transitionWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			px.api.Logf(lib.LLINFO, "interrupted: waiting for guest %d to be %s", g.GetVmid(), target)
+			px.guestDiscover(g, id)
+			return false
+		}
+		s, e := c.Status(ctx, g)
+		if e != nil {
+			px.api.Logf(lib.LLDEBUG, "failed to get status of guest %d: %v", g.GetVmid(), e)
+			continue
+		}
+		if s == target {
+			return true
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	px.api.Logf(lib.LLERROR, "guest %d did not reach %s after %s", g.GetVmid(), target, transitionWait.String())
+	return false
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (px *Proxmox) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  px.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	px.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// one cluster resources call gets the status of every guest
+func (px *Proxmox) discoverAll() {
+	px.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := px.api.QueryReadAll()
+	if e != nil {
+		px.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	guests := make(map[uint32]lib.NodeID)
+
+	// build list
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform"})
+		if len(vs) != 1 || vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		g, ok := px.guest(n)
+		if !ok {
+			px.api.Logf(lib.LLDEBUG, "skipping node %s, no Proxmox guest configured", n.ID().String())
+			continue
+		}
+		guests[g.GetVmid()] = n.ID()
+	}
+	if len(guests) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := px.client().StatusAll()
+	if e != nil {
+		px.api.Logf(lib.LLERROR, "failed to list guests: %v", e)
+		return
+	}
+	for vmid, id := range guests {
+		s, ok := st[vmid]
+		if !ok {
+			px.api.Logf(lib.LLERROR, "guest %d not found", vmid)
+			continue
+		}
+		px.discover(id, guestState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &Proxmox{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/proxmoxpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("proxmoxpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
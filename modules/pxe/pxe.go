@@ -281,6 +281,7 @@ func (px *PXE) Stop() {
 //////////////////////
 
 func (px *PXE) handleMutation(m *core.MutationEvent) {
+	defer m.Done()
 	switch m.Type {
 	case core.MutationEvent_MUTATE:
 		switch m.Mutation[1] {
@@ -0,0 +1,137 @@
+/* jsonrpc.go: a minimal client for the Raritan PX JSON-RPC API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package raritanpdu
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/raritanpdu/proto"
+)
+
+// PDUResource is the well known resource ID of the PDU object
+const PDUResource string = "/model/pdu/0"
+
+// outlet power states, pdumodel.Outlet.PowerState
+const (
+	psOff = 0
+	psOn  = 1
+)
+
+var rpcID int64
+
+// rpcClients are what calls are made with, so they reuse connections to the PDUs;
+// there's one that verifies certificates, and one that doesn't (for PDUs that are Insecure)
+var rpcClients = map[bool]*http.Client{
+	false: newRPCClient(false),
+	true:  newRPCClient(true),
+}
+
+func newRPCClient(insecure bool) *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+		},
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      int64       `json:"id"`
+}
+
+type rpcResponse struct {
+	Result *struct {
+		Ret json.RawMessage `json:"_ret_"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// rpcCall calls method on the resource rid of a PDU, and unmarshals the return value into r (if not nil)
+// the call is abandoned if ctx is canceled
+func rpcCall(ctx context.Context, pdu *pb.RaritanPDU, rid, method string, params interface{}, r interface{}) (e error) {
+	if params == nil {
+		params = struct{}{}
+	}
+	b, _ := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      atomic.AddInt64(&rpcID, 1),
+	})
+	req, e := http.NewRequest(http.MethodPost, "https://"+pdu.Host+rid, bytes.NewReader(b))
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(pdu.Username, pdu.Password)
+	req.Header.Set("Content-Type", "application/json")
+	resp, e := rpcClients[pdu.Insecure].Do(req)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("%s %s: HTTP %v", rid, method, resp.StatusCode)
+	}
+	var rr rpcResponse
+	if e = json.Unmarshal(rb, &rr); e != nil {
+		return
+	}
+	if rr.Error != nil {
+		return fmt.Errorf("%s %s: %s (%d)", rid, method, rr.Error.Message, rr.Error.Code)
+	}
+	if r != nil && rr.Result != nil {
+		return json.Unmarshal(rr.Result.Ret, r)
+	}
+	return
+}
+
+// rpcOutlets lists the resource IDs of a PDU's outlets, in outlet order
+func rpcOutlets(pdu *pb.RaritanPDU) (rids []string, e error) {
+	var r []struct {
+		Rid string `json:"rid"`
+	}
+	if e = rpcCall(context.Background(), pdu, PDUResource, "getOutlets", nil, &r); e != nil {
+		return
+	}
+	for _, o := range r {
+		rids = append(rids, o.Rid)
+	}
+	return
+}
+
+// rpcGetState returns an outlet's power state
+func rpcGetState(pdu *pb.RaritanPDU, rid string) (ps int, e error) {
+	var r struct {
+		PowerState int `json:"powerState"`
+	}
+	e = rpcCall(context.Background(), pdu, rid, "getState", nil, &r)
+	return r.PowerState, e
+}
+
+// rpcSetState switches an outlet
+func rpcSetState(ctx context.Context, pdu *pb.RaritanPDU, rid string, ps int) error {
+	return rpcCall(ctx, pdu, rid, "setPowerState", map[string]int{"pstate": ps}, nil)
+}
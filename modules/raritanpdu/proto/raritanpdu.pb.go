@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: raritanpdu.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RaritanPDUConfig struct {
+	Pdus                 map[string]*RaritanPDU    `protobuf:"bytes,1,rep,name=pdus,proto3" json:"pdus,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Outlets              map[string]*RaritanOutlet `protobuf:"bytes,2,rep,name=outlets,proto3" json:"outlets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                    `protobuf:"bytes,3,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                    `protobuf:"bytes,4,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	OffDelay             string                    `protobuf:"bytes,5,opt,name=off_delay,json=offDelay,proto3" json:"off_delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *RaritanPDUConfig) Reset()         { *m = RaritanPDUConfig{} }
+func (m *RaritanPDUConfig) String() string { return proto.CompactTextString(m) }
+func (*RaritanPDUConfig) ProtoMessage()    {}
+func (*RaritanPDUConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_raritanpdu_41bdcd5aa1c39789, []int{0}
+}
+func (m *RaritanPDUConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaritanPDUConfig.Unmarshal(m, b)
+}
+func (m *RaritanPDUConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaritanPDUConfig.Marshal(b, m, deterministic)
+}
+func (dst *RaritanPDUConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaritanPDUConfig.Merge(dst, src)
+}
+func (m *RaritanPDUConfig) XXX_Size() int {
+	return xxx_messageInfo_RaritanPDUConfig.Size(m)
+}
+func (m *RaritanPDUConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaritanPDUConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaritanPDUConfig proto.InternalMessageInfo
+
+func (m *RaritanPDUConfig) GetPdus() map[string]*RaritanPDU {
+	if m != nil {
+		return m.Pdus
+	}
+	return nil
+}
+
+func (m *RaritanPDUConfig) GetOutlets() map[string]*RaritanOutlet {
+	if m != nil {
+		return m.Outlets
+	}
+	return nil
+}
+
+func (m *RaritanPDUConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *RaritanPDUConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *RaritanPDUConfig) GetOffDelay() string {
+	if m != nil {
+		return m.OffDelay
+	}
+	return ""
+}
+
+type RaritanPDU struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool     `protobuf:"varint,4,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RaritanPDU) Reset()         { *m = RaritanPDU{} }
+func (m *RaritanPDU) String() string { return proto.CompactTextString(m) }
+func (*RaritanPDU) ProtoMessage()    {}
+func (*RaritanPDU) Descriptor() ([]byte, []int) {
+	return fileDescriptor_raritanpdu_41bdcd5aa1c39789, []int{1}
+}
+func (m *RaritanPDU) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaritanPDU.Unmarshal(m, b)
+}
+func (m *RaritanPDU) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaritanPDU.Marshal(b, m, deterministic)
+}
+func (dst *RaritanPDU) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaritanPDU.Merge(dst, src)
+}
+func (m *RaritanPDU) XXX_Size() int {
+	return xxx_messageInfo_RaritanPDU.Size(m)
+}
+func (m *RaritanPDU) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaritanPDU.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaritanPDU proto.InternalMessageInfo
+
+func (m *RaritanPDU) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *RaritanPDU) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RaritanPDU) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *RaritanPDU) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+type RaritanOutlet struct {
+	Pdu                  string   `protobuf:"bytes,1,opt,name=pdu,proto3" json:"pdu,omitempty"`
+	Outlet               int32    `protobuf:"varint,2,opt,name=outlet,proto3" json:"outlet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RaritanOutlet) Reset()         { *m = RaritanOutlet{} }
+func (m *RaritanOutlet) String() string { return proto.CompactTextString(m) }
+func (*RaritanOutlet) ProtoMessage()    {}
+func (*RaritanOutlet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_raritanpdu_41bdcd5aa1c39789, []int{2}
+}
+func (m *RaritanOutlet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaritanOutlet.Unmarshal(m, b)
+}
+func (m *RaritanOutlet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaritanOutlet.Marshal(b, m, deterministic)
+}
+func (dst *RaritanOutlet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaritanOutlet.Merge(dst, src)
+}
+func (m *RaritanOutlet) XXX_Size() int {
+	return xxx_messageInfo_RaritanOutlet.Size(m)
+}
+func (m *RaritanOutlet) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaritanOutlet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaritanOutlet proto.InternalMessageInfo
+
+func (m *RaritanOutlet) GetPdu() string {
+	if m != nil {
+		return m.Pdu
+	}
+	return ""
+}
+
+func (m *RaritanOutlet) GetOutlet() int32 {
+	if m != nil {
+		return m.Outlet
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*RaritanPDUConfig)(nil), "proto.RaritanPDUConfig")
+	proto.RegisterMapType((map[string]*RaritanPDU)(nil), "proto.RaritanPDUConfig.PdusEntry")
+	proto.RegisterMapType((map[string]*RaritanOutlet)(nil), "proto.RaritanPDUConfig.OutletsEntry")
+	proto.RegisterType((*RaritanPDU)(nil), "proto.RaritanPDU")
+	proto.RegisterType((*RaritanOutlet)(nil), "proto.RaritanOutlet")
+}
+
+func init() { proto.RegisterFile("raritanpdu.proto", fileDescriptor_raritanpdu_41bdcd5aa1c39789) }
+
+var fileDescriptor_raritanpdu_41bdcd5aa1c39789 = []byte{
+	// 342 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xcb, 0x4e, 0xe3, 0x30,
+	0x14, 0x55, 0x9a, 0xa6, 0x4d, 0x6e, 0x67, 0x34, 0x19, 0x6b, 0x34, 0x0a, 0x65, 0x53, 0x2a, 0x24,
+	0x0a, 0x8b, 0x2e, 0x8a, 0x90, 0x80, 0x05, 0x1b, 0xca, 0x02, 0x36, 0x54, 0x91, 0xba, 0xae, 0x02,
+	0x76, 0x4a, 0x84, 0xb1, 0x2d, 0x3f, 0x8a, 0xfa, 0x0b, 0x7c, 0x35, 0xb2, 0x9d, 0x86, 0x96, 0xc7,
+	0x2a, 0x3e, 0x3e, 0x0f, 0xdf, 0x73, 0x03, 0xa9, 0x2c, 0x64, 0xa5, 0x0b, 0x26, 0xb0, 0x19, 0x0b,
+	0xc9, 0x35, 0x47, 0x91, 0xfb, 0x0c, 0xdf, 0x42, 0x48, 0x73, 0xcf, 0xcd, 0xa6, 0xf3, 0x6b, 0xce,
+	0xca, 0x6a, 0x89, 0xce, 0xa0, 0x2d, 0xb0, 0x51, 0x59, 0x30, 0x08, 0x47, 0xbd, 0xc9, 0x81, 0x77,
+	0x8c, 0x3f, 0xcb, 0xc6, 0x33, 0x6c, 0xd4, 0x0d, 0xd3, 0x72, 0x9d, 0x3b, 0x39, 0xba, 0x82, 0x2e,
+	0x37, 0x9a, 0x12, 0xad, 0xb2, 0x96, 0x73, 0x1e, 0xfe, 0xe4, 0xbc, 0xf7, 0x32, 0x6f, 0xde, 0x98,
+	0xd0, 0x31, 0xa4, 0x82, 0x53, 0x5a, 0xb1, 0xe5, 0xa2, 0x62, 0x9a, 0xc8, 0x55, 0x41, 0xb3, 0x70,
+	0x10, 0x8c, 0x92, 0xfc, 0x4f, 0x7d, 0x7f, 0x5b, 0x5f, 0xa3, 0x3d, 0x88, 0x59, 0xf1, 0x42, 0x16,
+	0x46, 0xd2, 0xac, 0xed, 0x24, 0x5d, 0x8b, 0xe7, 0x92, 0xa2, 0x7d, 0x48, 0x78, 0x59, 0x2e, 0x30,
+	0xa1, 0xc5, 0x3a, 0x8b, 0x1c, 0x17, 0xf3, 0xb2, 0x9c, 0x5a, 0xdc, 0xbf, 0x83, 0xa4, 0x99, 0x1a,
+	0xa5, 0x10, 0x3e, 0x93, 0x75, 0x16, 0x38, 0x8d, 0x3d, 0xa2, 0x23, 0x88, 0x56, 0x05, 0x35, 0x24,
+	0x6b, 0x0d, 0x82, 0x51, 0x6f, 0xf2, 0xf7, 0xcb, 0xfc, 0xb9, 0xe7, 0x2f, 0x5b, 0xe7, 0x41, 0x7f,
+	0x06, 0xbf, 0xb6, 0x7b, 0x7c, 0x13, 0x77, 0xb2, 0x1b, 0xf7, 0x6f, 0x37, 0xce, 0x9b, 0xb7, 0x12,
+	0x87, 0x1a, 0xe0, 0xe3, 0x29, 0x84, 0xa0, 0xfd, 0xc4, 0x95, 0xae, 0x03, 0xdd, 0x19, 0xf5, 0x21,
+	0x36, 0x8a, 0x48, 0xdb, 0xd5, 0x85, 0x26, 0x79, 0x83, 0x2d, 0x27, 0x0a, 0xa5, 0x5e, 0xb9, 0xc4,
+	0xf5, 0xda, 0x1a, 0x6c, 0xb9, 0x8a, 0x29, 0xf2, 0x68, 0x24, 0x71, 0xfb, 0x8a, 0xf3, 0x06, 0x0f,
+	0x2f, 0xe0, 0xf7, 0xce, 0x44, 0xb6, 0x88, 0xc0, 0x66, 0x53, 0x44, 0x60, 0x83, 0xfe, 0x43, 0xc7,
+	0xff, 0x24, 0xf7, 0x68, 0x94, 0xd7, 0xe8, 0xa1, 0xe3, 0x0a, 0x9d, 0xbe, 0x0f, 0x00, 0xd3, 0xdb,
+	0x2f, 0x0e, 0x5f, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,488 @@
+/* raritanpdu.go: mutations for nodes powered through Raritan PX PDU outlets
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/raritanpdu.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = raritan.
+ * Nodes are mapped to a PDU and outlet by the value found at NameUrl.
+ */
+
+package raritanpdu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/raritanpdu/proto"
+)
+
+const (
+	PlatformString string = "raritan"
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "10s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"HANGtoON": { // outlet power cycle, so a hung node doesn't have to go through OFF
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_ON,
+		timeout: "60s", // includes off_delay
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+/////////////////////
+// RaritanPDU Object /
+///////////////////
+
+// RaritanPDU provides a power on/off interface to Raritan PX PDUs
+type RaritanPDU struct {
+	api        lib.APIClient
+	cfg        *pb.RaritanPDUConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	rids       map[string][]string // PDU name -> outlet resource IDs
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*RaritanPDU)(nil)
+
+// Name returns the FQDN of the module
+func (*RaritanPDU) Name() string { return "github.com/hpc/kraken/modules/raritanpdu" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*RaritanPDU)(nil)
+var _ lib.ModuleWithConfigValidation = (*RaritanPDU)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*RaritanPDU) NewConfig() proto.Message {
+	r := &pb.RaritanPDUConfig{
+		NameUrl: "/Nodename",
+		Pdus: map[string]*pb.RaritanPDU{
+			"pdu0": {
+				Host:     "localhost",
+				Username: "admin",
+				Password: "raritan",
+				Insecure: true,
+			},
+		},
+		Outlets:         map[string]*pb.RaritanOutlet{},
+		PollingInterval: "30s",
+		OffDelay:        "5s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (rp *RaritanPDU) UpdateConfig(cfg proto.Message) (e error) {
+	if rpcfg, ok := cfg.(*pb.RaritanPDUConfig); ok {
+		rp.cfg = rpcfg
+		rp.mutex.Lock()
+		rp.rids = make(map[string][]string) // PDUs may have changed
+		rp.mutex.Unlock()
+		if rp.pollTicker != nil {
+			rp.pollTicker.Stop()
+			dur, _ := time.ParseDuration(rp.cfg.GetPollingInterval())
+			rp.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval and off delay, that PDUs have hosts, and that outlets are numbered and on known PDUs
+func (*RaritanPDU) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.RaritanPDUConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if dur, e := time.ParseDuration(c.GetOffDelay()); e != nil || dur < 0 {
+		return fmt.Errorf("invalid off delay: %q", c.GetOffDelay())
+	}
+	for name, pdu := range c.GetPdus() {
+		if pdu.GetHost() == "" {
+			return fmt.Errorf("PDU %s has no host", name)
+		}
+	}
+	for name, o := range c.GetOutlets() {
+		if _, ok := c.GetPdus()[o.GetPdu()]; !ok {
+			return fmt.Errorf("outlet %s is on an unknown PDU: %s", name, o.GetPdu())
+		}
+		if o.GetOutlet() < 1 {
+			return fmt.Errorf("outlet %s has an invalid outlet number: %d", name, o.GetOutlet())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*RaritanPDU) ConfigURL() string {
+	cfg := &pb.RaritanPDUConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*RaritanPDU)(nil)
+var _ lib.ModuleWithDiscovery = (*RaritanPDU)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (rp *RaritanPDU) SetMutationChan(c <-chan lib.Event) { rp.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (rp *RaritanPDU) SetDiscoveryChan(c chan<- lib.Event) { rp.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*RaritanPDU)(nil)
+
+// Entry is the module's executable entrypoint
+func (rp *RaritanPDU) Entry() {
+	url := lib.NodeURLJoin(rp.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "raritanpdu"), "State"))
+	rp.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rp.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(rp.cfg.GetPollingInterval())
+	rp.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-rp.pollTicker.C:
+			go rp.discoverAll()
+			break
+		case m := <-rp.mchan: // mutation request
+			go rp.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (rp *RaritanPDU) Init(api lib.APIClient) {
+	rp.api = api
+	rp.cfg = rp.NewConfig().(*pb.RaritanPDUConfig)
+	rp.rids = make(map[string][]string)
+}
+
+// Stop should perform a graceful exit
+func (rp *RaritanPDU) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (rp *RaritanPDU) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		rp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name
+	vs := me.NodeCfg.GetValues([]string{rp.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		rp.api.Logf(lib.LLERROR, "could not get outlet name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[rp.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			rp.outletDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			rp.outletSet(me.Context, name, true, me.NodeCfg.ID())
+		case "ONtoOFF":
+			rp.outletSet(me.Context, name, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			rp.outletSet(me.Context, name, false, me.NodeCfg.ID())
+		case "HANGtoON":
+			rp.outletCycle(me.Context, name, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			rp.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// outlet resolves a node name to its PDU and the outlet's resource ID
+// outlet resource IDs are looked up once per PDU
+func (rp *RaritanPDU) outlet(name string) (pdu *pb.RaritanPDU, rid string, e error) {
+	o, ok := rp.cfg.Outlets[name]
+	if !ok {
+		e = fmt.Errorf("no outlet configured for node: %s", name)
+		return
+	}
+	if pdu, ok = rp.cfg.Pdus[o.Pdu]; !ok {
+		e = fmt.Errorf("node %s references unknown PDU: %s", name, o.Pdu)
+		return
+	}
+	rp.mutex.Lock()
+	rids, ok := rp.rids[o.Pdu]
+	rp.mutex.Unlock()
+	if !ok {
+		if rids, e = rpcOutlets(pdu); e != nil {
+			return
+		}
+		rp.mutex.Lock()
+		rp.rids[o.Pdu] = rids
+		rp.mutex.Unlock()
+	}
+	if o.Outlet < 1 || int(o.Outlet) > len(rids) {
+		e = fmt.Errorf("PDU %s has no outlet %d", o.Pdu, o.Outlet)
+		return
+	}
+	rid = rids[o.Outlet-1]
+	return
+}
+
+func (rp *RaritanPDU) outletDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	pdu, rid, e := rp.outlet(name)
+	if e != nil {
+		rp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	ps, e := rpcGetState(pdu, rid)
+	if e != nil {
+		rp.api.Logf(lib.LLERROR, "failed to read outlet state for %s: %v", name, e)
+		return
+	}
+	var vid string
+	switch ps {
+	case psOn:
+		vid = "POWER_ON"
+	case psOff:
+		vid = "POWER_OFF"
+	default:
+		vid = "PHYS_UNKNOWN"
+	}
+	rp.discover(id, vid, start)
+}
+
+// outletSet switches a node's outlet, and reports state on success
+// if the mutation is interrupted, we report whatever state the outlet was left in instead
+func (rp *RaritanPDU) outletSet(ctx context.Context, name string, on bool, id lib.NodeID) {
+	pdu, rid, e := rp.outlet(name)
+	if e != nil {
+		rp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	ps, vid := psOff, "POWER_OFF"
+	if on {
+		ps, vid = psOn, "POWER_ON"
+	}
+	if e = rpcSetState(ctx, pdu, rid, ps); e != nil {
+		if ctx.Err() != nil {
+			rp.api.Logf(lib.LLINFO, "interrupted: switching outlet for %s", name)
+			rp.outletDiscover(name, id)
+			return
+		}
+		rp.api.Logf(lib.LLERROR, "failed to switch outlet for %s: %v", name, e)
+		return
+	}
+	rp.discover(id, vid, time.Now())
+}
+
+// outletCycle switches an outlet off, waits off_delay, and switches it back on
+// we do this ourselves rather than with cyclePowerState so the delay is ours, not the PDU's
+func (rp *RaritanPDU) outletCycle(ctx context.Context, name string, id lib.NodeID) {
+	pdu, rid, e := rp.outlet(name)
+	if e != nil {
+		rp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	if e = rpcSetState(ctx, pdu, rid, psOff); e != nil {
+		if ctx.Err() != nil {
+			rp.api.Logf(lib.LLINFO, "interrupted: switching outlet off for %s", name)
+			rp.outletDiscover(name, id)
+			return
+		}
+		rp.api.Logf(lib.LLERROR, "failed to switch outlet off for %s: %v", name, e)
+		return
+	}
+	delay, _ := time.ParseDuration(rp.cfg.GetOffDelay())
+	select {
+	case <-time.After(delay):
+		e = rpcSetState(ctx, pdu, rid, psOn)
+	case <-ctx.Done():
+		e = ctx.Err()
+	}
+	if e != nil {
+		if ctx.Err() != nil {
+			rp.api.Logf(lib.LLINFO, "interrupted: switching outlet back on for %s", name)
+			rp.outletDiscover(name, id)
+			return
+		}
+		// the SME will fail us to PHYS_HANG, but the outlet is off now
+		rp.api.Logf(lib.LLERROR, "failed to switch outlet back on for %s: %v", name, e)
+		return
+	}
+	rp.discover(id, "POWER_ON", time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (rp *RaritanPDU) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rp.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	rp.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (rp *RaritanPDU) discoverAll() {
+	rp.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := rp.api.QueryReadAll()
+	if e != nil {
+		rp.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", rp.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			rp.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete outlet info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		// one small request per outlet; PDUs handle this fine serially
+		rp.outletDiscover(vs[rp.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &RaritanPDU{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/raritanpdu/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("raritanpdu", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: redfishevents.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RFEConfig struct {
+	Servers              map[string]*RFEServer `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Addr                 string                `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Port                 int32                 `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Destination          string                `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	TlsCert              string                `protobuf:"bytes,5,opt,name=tls_cert,json=tlsCert,proto3" json:"tls_cert,omitempty"`
+	TlsKey               string                `protobuf:"bytes,6,opt,name=tls_key,json=tlsKey,proto3" json:"tls_key,omitempty"`
+	EventTypes           []string              `protobuf:"bytes,7,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	ResubscribeInterval  string                `protobuf:"bytes,8,opt,name=resubscribe_interval,json=resubscribeInterval,proto3" json:"resubscribe_interval,omitempty"`
+	CriticalState        string                `protobuf:"bytes,9,opt,name=critical_state,json=criticalState,proto3" json:"critical_state,omitempty"`
+	NameUrl              string                `protobuf:"bytes,10,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	ServerUrl            string                `protobuf:"bytes,11,opt,name=server_url,json=serverUrl,proto3" json:"server_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *RFEConfig) Reset()         { *m = RFEConfig{} }
+func (m *RFEConfig) String() string { return proto.CompactTextString(m) }
+func (*RFEConfig) ProtoMessage()    {}
+func (*RFEConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_redfishevents_c01d0629a4250ed9, []int{0}
+}
+func (m *RFEConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RFEConfig.Unmarshal(m, b)
+}
+func (m *RFEConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RFEConfig.Marshal(b, m, deterministic)
+}
+func (dst *RFEConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RFEConfig.Merge(dst, src)
+}
+func (m *RFEConfig) XXX_Size() int {
+	return xxx_messageInfo_RFEConfig.Size(m)
+}
+func (m *RFEConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_RFEConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RFEConfig proto.InternalMessageInfo
+
+func (m *RFEConfig) GetServers() map[string]*RFEServer {
+	if m != nil {
+		return m.Servers
+	}
+	return nil
+}
+
+func (m *RFEConfig) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *RFEConfig) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetTlsCert() string {
+	if m != nil {
+		return m.TlsCert
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetTlsKey() string {
+	if m != nil {
+		return m.TlsKey
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetEventTypes() []string {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+func (m *RFEConfig) GetResubscribeInterval() string {
+	if m != nil {
+		return m.ResubscribeInterval
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetCriticalState() string {
+	if m != nil {
+		return m.CriticalState
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *RFEConfig) GetServerUrl() string {
+	if m != nil {
+		return m.ServerUrl
+	}
+	return ""
+}
+
+type RFEServer struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ip                   string   `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port                 int32    `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool     `protobuf:"varint,6,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RFEServer) Reset()         { *m = RFEServer{} }
+func (m *RFEServer) String() string { return proto.CompactTextString(m) }
+func (*RFEServer) ProtoMessage()    {}
+func (*RFEServer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_redfishevents_c01d0629a4250ed9, []int{1}
+}
+func (m *RFEServer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RFEServer.Unmarshal(m, b)
+}
+func (m *RFEServer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RFEServer.Marshal(b, m, deterministic)
+}
+func (dst *RFEServer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RFEServer.Merge(dst, src)
+}
+func (m *RFEServer) XXX_Size() int {
+	return xxx_messageInfo_RFEServer.Size(m)
+}
+func (m *RFEServer) XXX_DiscardUnknown() {
+	xxx_messageInfo_RFEServer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RFEServer proto.InternalMessageInfo
+
+func (m *RFEServer) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RFEServer) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *RFEServer) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *RFEServer) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RFEServer) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *RFEServer) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*RFEConfig)(nil), "proto.RFEConfig")
+	proto.RegisterMapType((map[string]*RFEServer)(nil), "proto.RFEConfig.ServersEntry")
+	proto.RegisterType((*RFEServer)(nil), "proto.RFEServer")
+}
+
+func init() { proto.RegisterFile("redfishevents.proto", fileDescriptor_redfishevents_c01d0629a4250ed9) }
+
+var fileDescriptor_redfishevents_c01d0629a4250ed9 = []byte{
+	// 392 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x8a, 0xd4, 0x40,
+	0x10, 0xc6, 0xc9, 0x64, 0xe7, 0x4f, 0x2a, 0xba, 0x2c, 0xbd, 0x82, 0xed, 0xc2, 0x62, 0x58, 0x50,
+	0xe6, 0x34, 0xe0, 0x7a, 0x50, 0xbc, 0x2e, 0x2b, 0x88, 0x9e, 0xb2, 0xee, 0x39, 0x64, 0x92, 0x5a,
+	0x6d, 0x6c, 0x93, 0x50, 0x55, 0x19, 0x99, 0x27, 0xf1, 0x89, 0x7c, 0x2f, 0xe9, 0xea, 0xd9, 0x38,
+	0x07, 0x4f, 0x5d, 0xf5, 0xfd, 0xbe, 0x6e, 0xaa, 0xeb, 0x83, 0x73, 0xc2, 0xf6, 0xc1, 0xf1, 0x77,
+	0xdc, 0x61, 0x27, 0xbc, 0x19, 0xa8, 0x97, 0xde, 0xcc, 0xf5, 0xb8, 0xfa, 0x93, 0x42, 0x56, 0x7e,
+	0xbc, 0xbd, 0xe9, 0xbb, 0x07, 0xf7, 0xcd, 0xbc, 0x83, 0x25, 0x23, 0xed, 0x90, 0xd8, 0x26, 0x45,
+	0xba, 0xce, 0xaf, 0x2f, 0xa3, 0x7b, 0x33, 0x59, 0x36, 0x77, 0x91, 0xdf, 0x76, 0x42, 0xfb, 0xf2,
+	0xd1, 0x6d, 0x0c, 0x9c, 0xd4, 0x6d, 0x4b, 0x76, 0x56, 0x24, 0xeb, 0xac, 0xd4, 0x3a, 0x68, 0x43,
+	0x4f, 0x62, 0xd3, 0x22, 0x59, 0xcf, 0x4b, 0xad, 0x4d, 0x01, 0x79, 0x8b, 0x2c, 0xae, 0xab, 0xc5,
+	0xf5, 0x9d, 0x3d, 0x51, 0xfb, 0xb1, 0x64, 0x5e, 0xc0, 0x4a, 0x3c, 0x57, 0x0d, 0x92, 0xd8, 0xb9,
+	0xe2, 0xa5, 0x78, 0xbe, 0x41, 0x12, 0xf3, 0x1c, 0x42, 0x59, 0xfd, 0xc0, 0xbd, 0x5d, 0x28, 0x59,
+	0x88, 0xe7, 0xcf, 0xb8, 0x37, 0x2f, 0x21, 0xd7, 0xbf, 0x55, 0xb2, 0x1f, 0x90, 0xed, 0xb2, 0x48,
+	0xd7, 0x59, 0x09, 0x2a, 0x7d, 0x0d, 0x8a, 0x79, 0x03, 0xcf, 0x08, 0x79, 0xdc, 0x72, 0x43, 0x6e,
+	0x8b, 0x95, 0xeb, 0x04, 0x69, 0x57, 0x7b, 0xbb, 0xd2, 0x67, 0xce, 0x8f, 0xd8, 0xa7, 0x03, 0x32,
+	0xaf, 0xe0, 0xb4, 0x21, 0x27, 0xae, 0xa9, 0x7d, 0xc5, 0x52, 0x0b, 0xda, 0x4c, 0xcd, 0x4f, 0x1f,
+	0xd5, 0xbb, 0x20, 0x86, 0x71, 0xbb, 0xfa, 0x27, 0x56, 0x23, 0x79, 0x0b, 0x71, 0xdc, 0xd0, 0xdf,
+	0x93, 0x37, 0x97, 0x00, 0x71, 0x3d, 0x0a, 0x73, 0x85, 0x59, 0x54, 0xee, 0xc9, 0x5f, 0x7c, 0x81,
+	0x27, 0xc7, 0xbb, 0x34, 0x67, 0x90, 0x86, 0x9f, 0x25, 0xea, 0x0b, 0xa5, 0x79, 0x0d, 0xf3, 0x5d,
+	0xed, 0x47, 0xd4, 0xad, 0xe6, 0xd7, 0x67, 0xff, 0xb2, 0x88, 0x17, 0xcb, 0x88, 0x3f, 0xcc, 0xde,
+	0x27, 0x57, 0xbf, 0x13, 0xc8, 0x26, 0x10, 0x56, 0x1f, 0xa6, 0x38, 0x3c, 0xa6, 0xb5, 0x39, 0x85,
+	0x99, 0x1b, 0x0e, 0x01, 0xcd, 0xdc, 0xf0, 0xdf, 0x78, 0x2e, 0x60, 0x35, 0x32, 0x92, 0xde, 0x8d,
+	0xd9, 0x4c, 0x7d, 0x60, 0x43, 0xcd, 0xfc, 0xab, 0xa7, 0xf6, 0x10, 0xcc, 0xd4, 0x07, 0xe6, 0x3a,
+	0xc6, 0x66, 0x24, 0xd4, 0x68, 0x56, 0xe5, 0xd4, 0x6f, 0x17, 0x3a, 0xf5, 0xdb, 0xbf, 0x03, 0x00,
+	0x62, 0xc3, 0x75, 0x73, 0x86, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,548 @@
+/* redfishevents.go: discovers power and health changes from Redfish event subscriptions, rather than by polling
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/redfishevents.proto
+
+/*
+ * This module subscribes to the EventService of each BMC, and listens for the events they post.
+ * Events for a node make us re-read its PowerState, so changes are discovered as they happen.
+ * Critical events can optionally be discovered as a PhysState (e.g. PHYS_ERROR).
+ * Like redfishpower, it's restricted to Platform = redfish, and uses the same NameUrl and ServerUrl.
+ */
+
+package redfishevents
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/redfishevents/proto"
+)
+
+const (
+	RFSystems      string = "/redfish/v1/Systems"
+	PlatformString string = "redfish"
+	EventsPath     string = "/redfish/events/" // BMCs post to <destination>/redfish/events/<bmc>
+)
+
+// maxEventSize limits how much of a post we'll read
+const maxEventSize = 1 << 20
+
+// rfSystem is the part of a Redfish ComputerSystem we care about
+type rfSystem struct {
+	Id         string `json:"Id,omitempty"`
+	PowerState string `json:"PowerState,omitempty"`
+}
+
+// rfLink is a Redfish reference to another resource
+type rfLink struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// rfEvent is the part of a Redfish Event we care about
+type rfEvent struct {
+	Context string          `json:"Context,omitempty"`
+	Events  []rfEventRecord `json:"Events"`
+}
+
+// rfEventRecord is one of the events in an rfEvent
+type rfEventRecord struct {
+	EventType         string `json:"EventType,omitempty"`
+	EventId           string `json:"EventId,omitempty"`
+	Severity          string `json:"Severity,omitempty"` // deprecated for MessageSeverity, but older BMCs only send this
+	MessageSeverity   string `json:"MessageSeverity,omitempty"`
+	Message           string `json:"Message,omitempty"`
+	MessageId         string `json:"MessageId,omitempty"`
+	OriginOfCondition rfLink `json:"OriginOfCondition,omitempty"`
+}
+
+// severity is OK, Warning or Critical
+func (r *rfEventRecord) severity() string {
+	if r.MessageSeverity != "" {
+		return r.MessageSeverity
+	}
+	return r.Severity
+}
+
+// rfNode is a node managed through a BMC
+type rfNode struct {
+	id       lib.NodeID
+	name     string // its Redfish system ID
+	nodename string
+}
+
+////////////////////
+// RFE Object /
+//////////////////
+
+// RFE discovers node state from the events Redfish BMCs post to it
+type RFE struct {
+	api      lib.APIClient
+	cfg      *pb.RFEConfig
+	clients  map[bool]*http.Client // by whether the BMC's certificate is verified; see newClients
+	dchan    chan<- lib.Event
+	mutex    sync.Mutex
+	srv      *http.Server
+	stopping bool
+	context  string            // the subscription context, so we know events are for our subscriptions
+	subs     map[string]string // subscription URIs, by BMC
+	resub    chan struct{}
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*RFE)(nil)
+
+// Name returns the FQDN of the module
+func (*RFE) Name() string { return "github.com/hpc/kraken/modules/redfishevents" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*RFE)(nil)
+var _ lib.ModuleWithConfigValidation = (*RFE)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*RFE) NewConfig() proto.Message {
+	r := &pb.RFEConfig{
+		ServerUrl:           "type.googleapis.com/proto.Redfish/Bmc",
+		NameUrl:             "type.googleapis.com/proto.Redfish/SystemId",
+		Servers:             map[string]*pb.RFEServer{},
+		Addr:                "0.0.0.0",
+		Port:                3143,
+		EventTypes:          []string{"Alert", "StatusChange"},
+		ResubscribeInterval: "5m",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (rf *RFE) UpdateConfig(cfg proto.Message) (e error) {
+	rfcfg, ok := cfg.(*pb.RFEConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	rf.mutex.Lock()
+	rf.cfg = rfcfg
+	srv := rf.srv
+	rf.mutex.Unlock()
+	if srv != nil {
+		srv.Close() // we just stop, entry will (re)start
+	}
+	// BMCs or our destination may have changed
+	select {
+	case rf.resub <- struct{}{}:
+	default:
+	}
+	return
+}
+
+// ValidateConfig checks a config before it's applied
+func (*RFE) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.RFEConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if c.GetPort() < 1 || c.GetPort() > 65535 {
+		return fmt.Errorf("invalid port: %d", c.GetPort())
+	}
+	if len(c.GetServers()) > 0 {
+		u, e := url.Parse(c.GetDestination())
+		if e != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid destination, BMCs need a URL to post events to: %q", c.GetDestination())
+		}
+	}
+	if (c.GetTlsCert() == "") != (c.GetTlsKey() == "") {
+		return fmt.Errorf("tls_cert and tls_key must be set together")
+	}
+	if dur, e := time.ParseDuration(c.GetResubscribeInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid resubscribe interval: %q", c.GetResubscribeInterval())
+	}
+	if s := c.GetCriticalState(); s != "" {
+		if _, ok := cpb.Node_PhysState_value[s]; !ok {
+			return fmt.Errorf("invalid critical state: %q", s)
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*RFE) ConfigURL() string {
+	cfg := &pb.RFEConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithDiscovery = (*RFE)(nil)
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (rf *RFE) SetDiscoveryChan(c chan<- lib.Event) { rf.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*RFE)(nil)
+var _ lib.ModuleWithShutdown = (*RFE)(nil)
+
+// Entry is the module's executable entrypoint
+func (rf *RFE) Entry() {
+	url := lib.NodeURLJoin(rf.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "redfishevents"), "State"))
+	rf.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rf.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	go rf.subscriber()
+
+	// main loop
+	for {
+		rf.serve()
+		rf.mutex.Lock()
+		stopping := rf.stopping
+		rf.mutex.Unlock()
+		if stopping {
+			select {} // we're about to exit
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (rf *RFE) Init(api lib.APIClient) {
+	rf.api = api
+	if rf.cfg == nil {
+		rf.cfg = rf.NewConfig().(*pb.RFEConfig)
+	}
+	rf.clients = newClients()
+	rf.subs = make(map[string]string)
+	rf.resub = make(chan struct{}, 1)
+	b := make([]byte, 16)
+	rand.Read(b)
+	rf.context = "kraken-" + hex.EncodeToString(b)
+}
+
+// Stop should perform a graceful exit
+func (rf *RFE) Stop() {
+	os.Exit(0)
+}
+
+// Shutdown removes our subscriptions, so BMCs stop posting to us, and stops the listener
+func (rf *RFE) Shutdown(ctx context.Context) error {
+	rf.mutex.Lock()
+	rf.stopping = true
+	srv := rf.srv
+	subs := make(map[string]string)
+	for bmc, uri := range rf.subs {
+		subs[bmc] = uri
+	}
+	rf.mutex.Unlock()
+	for bmc, uri := range subs {
+		if e := rf.unsubscribe(bmc, uri); e != nil {
+			rf.api.Logf(lib.LLERROR, "failed to remove redfish event subscription on %s: %v", bmc, e)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+// config gets the current config
+func (rf *RFE) config() *pb.RFEConfig {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	return rf.cfg
+}
+
+// serve listens for events until the server is closed
+func (rf *RFE) serve() {
+	cfg := rf.config()
+	mux := http.NewServeMux()
+	mux.HandleFunc(EventsPath, rf.handleEvent)
+	addr := fmt.Sprintf("%s:%d", cfg.GetAddr(), cfg.GetPort())
+	l, e := net.Listen("tcp", addr)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "redfishevents couldn't listen: %v", e)
+		time.Sleep(time.Second) // don't spin if we can't start
+		return
+	}
+	srv := &http.Server{Handler: mux}
+	rf.mutex.Lock()
+	rf.srv = srv
+	rf.mutex.Unlock()
+	rf.api.Logf(lib.LLINFO, "redfishevents is listening for events at: %s%s", addr, EventsPath)
+	if cfg.GetTlsCert() != "" {
+		e = srv.ServeTLS(l, cfg.GetTlsCert(), cfg.GetTlsKey())
+	} else {
+		e = srv.Serve(l)
+	}
+	if e != http.ErrServerClosed {
+		rf.api.Logf(lib.LLNOTICE, "redfishevents stopped: %v", e)
+	}
+	rf.mutex.Lock()
+	rf.srv = nil
+	rf.mutex.Unlock()
+	rf.api.Log(lib.LLNOTICE, "redfishevents listener stopped")
+}
+
+// handleEvent takes an event a BMC posted; we answer right away, BMCs don't wait long
+func (rf *RFE) handleEvent(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	bmc := path.Base(req.URL.Path)
+	if _, ok := rf.config().GetServers()[bmc]; !ok {
+		http.Error(w, "unknown BMC", http.StatusNotFound)
+		return
+	}
+	body, e := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxEventSize))
+	if e != nil {
+		http.Error(w, e.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	var ev rfEvent
+	if e = json.Unmarshal(body, &ev); e != nil {
+		rf.api.Logf(lib.LLDEBUG, "bad redfish event from %s: %v", bmc, e)
+		http.Error(w, e.Error(), http.StatusBadRequest)
+		return
+	}
+	if ev.Context != rf.context {
+		rf.api.Logf(lib.LLWARNING, "ignoring redfish event for %s with a context that isn't ours: %q", bmc, ev.Context)
+		http.Error(w, "unknown subscription", http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	go rf.processEvents(bmc, ev.Events)
+}
+
+// processEvents discovers the state of the nodes events are for
+func (rf *RFE) processEvents(bmc string, evs []rfEventRecord) {
+	cfg := rf.config()
+	nodes, e := rf.bmcNodes(bmc)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "node query failed: %v", e)
+		return
+	}
+	refresh := make(map[string]*rfNode)
+	critical := make(map[string]bool)
+	for _, ev := range evs {
+		var n *rfNode
+		if strings.HasPrefix(ev.OriginOfCondition.ODataID, RFSystems+"/") {
+			name, _ := lib.URLShift(strings.TrimPrefix(ev.OriginOfCondition.ODataID, RFSystems))
+			n = nodes[name]
+		} else if len(nodes) == 1 {
+			// e.g. a chassis sensor; with one system, it's the one
+			for _, only := range nodes {
+				n = only
+			}
+		}
+		if n == nil {
+			rf.api.Logf(lib.LLDEBUG, "redfish event from %s isn't for a node we know: %s %s: %s", bmc, ev.EventType, ev.MessageId, ev.OriginOfCondition.ODataID)
+			continue
+		}
+		msg := fmt.Sprintf("redfish event from %s for %s (%s): %s %s: %s", bmc, n.nodename, n.id.String(), ev.EventType, ev.MessageId, ev.Message)
+		switch ev.severity() {
+		case "Critical":
+			rf.api.Log(lib.LLERROR, msg)
+			if cfg.GetCriticalState() != "" {
+				critical[n.name] = true
+				rf.discover(n.id, cfg.GetCriticalState())
+			}
+		case "Warning":
+			rf.api.Log(lib.LLWARNING, msg)
+		default:
+			rf.api.Log(lib.LLINFO, msg)
+		}
+		refresh[n.name] = n
+	}
+	for name, n := range refresh {
+		if critical[name] {
+			// don't undo discovering the critical state
+			continue
+		}
+		go rf.sysDiscover(bmc, n)
+	}
+}
+
+// bmcNodes gets the nodes managed through a BMC, by their Redfish system ID
+func (rf *RFE) bmcNodes(bmc string) (r map[string]*rfNode, e error) {
+	cfg := rf.config()
+	ns, e := rf.api.QueryReadAll()
+	if e != nil {
+		return
+	}
+	r = make(map[string]*rfNode)
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", cfg.GetNameUrl(), cfg.GetServerUrl()})
+		if len(vs) != 3 {
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString || vs[cfg.GetServerUrl()].String() != bmc {
+			continue
+		}
+		name := vs[cfg.GetNameUrl()].String()
+		nodename, _ := n.GetValue("/Nodename")
+		r[name] = &rfNode{id: n.ID(), name: name, nodename: nodename.String()}
+	}
+	return
+}
+
+// discoverBMC discovers the power state of every node managed through a BMC, e.g. after events may have been missed
+func (rf *RFE) discoverBMC(bmc string) {
+	nodes, e := rf.bmcNodes(bmc)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "node query failed: %v", e)
+		return
+	}
+	for _, n := range nodes {
+		go rf.sysDiscover(bmc, n)
+	}
+}
+
+// discover sends a PhysState discovery for a node
+func (rf *RFE) discover(id lib.NodeID, vid string) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	rf.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rf.Name(),
+			URL:     url,
+			ValueID: vid,
+		},
+	)
+}
+
+// request makes an authenticated request against a BMC
+func (rf *RFE) request(srvName, method, path string, body []byte) (resp *http.Response, e error) {
+	srv, ok := rf.config().GetServers()[srvName]
+	if !ok {
+		return nil, fmt.Errorf("unknown BMC: %s", srvName)
+	}
+	addr := srv.Ip + ":" + strconv.Itoa(int(srv.Port))
+	req, e := http.NewRequest(method, "https://"+addr+path, bytes.NewReader(body))
+	if e != nil {
+		return
+	}
+	req.SetBasicAuth(srv.Username, srv.Password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return rf.clients[srv.Insecure].Do(req)
+}
+
+// newClients makes the HTTP clients requests are made with, so they reuse connections;
+// there's one that verifies certificates, and one that doesn't (for BMCs that are Insecure)
+func newClients() map[bool]*http.Client {
+	r := make(map[bool]*http.Client)
+	for _, insecure := range []bool{false, true} {
+		r[insecure] = &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		}
+	}
+	return r
+}
+
+// sysDiscover reads the power state of a node from its BMC
+func (rf *RFE) sysDiscover(bmc string, n *rfNode) {
+	resp, e := rf.request(bmc, http.MethodGet, RFSystems+"/"+n.name, nil)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error dialing BMC: %v", e)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		rf.api.Logf(lib.LLERROR, "error dialing BMC: HTTP %v", resp.StatusCode)
+		return
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error reading BMC response body: %v", e)
+		return
+	}
+	var rs rfSystem
+	e = json.Unmarshal(body, &rs)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error unmarshaling json: %v", e)
+		return
+	}
+	var vid string
+	switch rs.PowerState {
+	case "Off":
+		vid = "POWER_OFF"
+	case "On":
+		vid = "POWER_ON"
+	default: // PoweringOn, PoweringOff, or missing
+		vid = "PHYS_UNKNOWN"
+	}
+	rf.discover(n.id, vid)
+}
+
+// initialization
+func init() {
+	module := &RFE{}
+	discovers := make(map[string]map[string]reflect.Value)
+	// we discover power states, and critical_state can be any of them
+	discovers["/PhysState"] = make(map[string]reflect.Value)
+	for v, s := range cpb.Node_PhysState_name {
+		discovers["/PhysState"][s] = reflect.ValueOf(cpb.Node_PhysState(v))
+	}
+	discovers["/Services/redfishevents/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("redfishevents", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+}
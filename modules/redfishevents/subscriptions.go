@@ -0,0 +1,208 @@
+/* subscriptions.go: keeps our event subscriptions on each BMC's Redfish EventService
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package redfishevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hpc/kraken/lib"
+)
+
+const RFSubscriptions string = "/redfish/v1/EventService/Subscriptions"
+
+// rfSubscription is the part of a Redfish EventDestination we care about
+type rfSubscription struct {
+	ODataID     string   `json:"@odata.id,omitempty"`
+	Destination string   `json:"Destination"`
+	Protocol    string   `json:"Protocol,omitempty"`
+	Context     string   `json:"Context,omitempty"`
+	EventTypes  []string `json:"EventTypes,omitempty"`
+}
+
+// rfCollection is a Redfish resource collection
+type rfCollection struct {
+	Members []rfLink `json:"Members"`
+}
+
+// subscriber keeps our subscriptions, checking them every resubscribe interval, or when the config changes
+func (rf *RFE) subscriber() {
+	for {
+		rf.subscribeAll()
+		dur, _ := time.ParseDuration(rf.config().GetResubscribeInterval())
+		select {
+		case <-time.After(dur):
+		case <-rf.resub:
+		}
+		rf.mutex.Lock()
+		stopping := rf.stopping
+		rf.mutex.Unlock()
+		if stopping {
+			return
+		}
+	}
+}
+
+// subscribeAll makes sure every BMC has our subscription, and waits until they've been checked
+func (rf *RFE) subscribeAll() {
+	cfg := rf.config()
+	rf.mutex.Lock()
+	// BMCs that were removed from the config; we don't have their credentials anymore, so we just forget them
+	for bmc := range rf.subs {
+		if _, ok := cfg.GetServers()[bmc]; !ok {
+			delete(rf.subs, bmc)
+		}
+	}
+	rf.mutex.Unlock()
+	var wg sync.WaitGroup
+	for bmc := range cfg.GetServers() {
+		wg.Add(1)
+		go func(bmc string) {
+			defer wg.Done()
+			rf.ensure(bmc)
+		}(bmc)
+	}
+	wg.Wait()
+}
+
+// destination is where a BMC should post its events
+func (rf *RFE) destination(bmc string) string {
+	return strings.TrimSuffix(rf.config().GetDestination(), "/") + EventsPath + url.PathEscape(bmc)
+}
+
+// ensure makes sure a BMC has our subscription, and subscribes if it doesn't
+func (rf *RFE) ensure(bmc string) {
+	dest := rf.destination(bmc)
+	rf.mutex.Lock()
+	uri, ok := rf.subs[bmc]
+	rf.mutex.Unlock()
+	if ok {
+		s, e := rf.getSubscription(bmc, uri)
+		if e == nil && s.Destination == dest && s.Context == rf.context {
+			return
+		}
+		rf.api.Logf(lib.LLNOTICE, "redfish event subscription on %s is gone, resubscribing", bmc)
+	}
+	rf.mutex.Lock()
+	stopping := rf.stopping
+	rf.mutex.Unlock()
+	if stopping {
+		return
+	}
+	uri, e := rf.subscribe(bmc, dest)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "failed to subscribe to redfish events on %s: %v", bmc, e)
+		return
+	}
+	rf.mutex.Lock()
+	rf.subs[bmc] = uri
+	rf.mutex.Unlock()
+	rf.api.Logf(lib.LLINFO, "subscribed to redfish events on %s: %s", bmc, uri)
+	// we may have missed changes while we weren't subscribed
+	rf.discoverBMC(bmc)
+}
+
+// subscribe removes stale subscriptions of ours, e.g. from before we restarted, and makes a new one
+func (rf *RFE) subscribe(bmc, dest string) (uri string, e error) {
+	if e = rf.removeStale(bmc, dest); e != nil {
+		return
+	}
+	body, _ := json.Marshal(rfSubscription{
+		Destination: dest,
+		Protocol:    "Redfish",
+		Context:     rf.context,
+		EventTypes:  rf.config().GetEventTypes(),
+	})
+	resp, e := rf.request(bmc, http.MethodPost, RFSubscriptions, body)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	rb, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("HTTP %v: %s", resp.StatusCode, string(rb))
+	}
+	// the new subscription is in Location, but some BMCs only give it in the body
+	if l := resp.Header.Get("Location"); l != "" {
+		if u, e := url.Parse(l); e == nil {
+			return u.Path, nil
+		}
+	}
+	var s rfSubscription
+	if e = json.Unmarshal(rb, &s); e != nil || s.ODataID == "" {
+		return "", fmt.Errorf("BMC didn't say where the subscription is")
+	}
+	return s.ODataID, nil
+}
+
+// removeStale deletes the subscriptions on a BMC that post to dest
+func (rf *RFE) removeStale(bmc, dest string) (e error) {
+	resp, e := rf.request(bmc, http.MethodGet, RFSubscriptions, nil)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("listing subscriptions: HTTP %v", resp.StatusCode)
+	}
+	var c rfCollection
+	if e = json.NewDecoder(resp.Body).Decode(&c); e != nil {
+		return
+	}
+	for _, m := range c.Members {
+		s, e := rf.getSubscription(bmc, m.ODataID)
+		if e != nil || s.Destination != dest {
+			continue
+		}
+		if e = rf.unsubscribe(bmc, m.ODataID); e != nil {
+			rf.api.Logf(lib.LLERROR, "failed to remove stale redfish event subscription on %s: %v", bmc, e)
+		}
+	}
+	return nil
+}
+
+// getSubscription reads a subscription
+func (rf *RFE) getSubscription(bmc, uri string) (s *rfSubscription, e error) {
+	resp, e := rf.request(bmc, http.MethodGet, uri, nil)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %v", resp.StatusCode)
+	}
+	s = &rfSubscription{}
+	e = json.NewDecoder(resp.Body).Decode(s)
+	return
+}
+
+// unsubscribe deletes a subscription
+func (rf *RFE) unsubscribe(bmc, uri string) (e error) {
+	resp, e := rf.request(bmc, http.MethodDelete, uri, nil)
+	if e != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if (resp.StatusCode < 200 || resp.StatusCode > 299) && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("HTTP %v", resp.StatusCode)
+	}
+	rf.mutex.Lock()
+	if rf.subs[bmc] == uri {
+		delete(rf.subs, bmc)
+	}
+	rf.mutex.Unlock()
+	return
+}
@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: redfishpower.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RFPConfig struct {
+	Servers              map[string]*RFPServer `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                `protobuf:"bytes,2,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                `protobuf:"bytes,3,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	ServerUrl            string                `protobuf:"bytes,4,opt,name=server_url,json=serverUrl,proto3" json:"server_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *RFPConfig) Reset()         { *m = RFPConfig{} }
+func (m *RFPConfig) String() string { return proto.CompactTextString(m) }
+func (*RFPConfig) ProtoMessage()    {}
+func (*RFPConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_redfishpower_d644afe490ab4f6b, []int{0}
+}
+func (m *RFPConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RFPConfig.Unmarshal(m, b)
+}
+func (m *RFPConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RFPConfig.Marshal(b, m, deterministic)
+}
+func (dst *RFPConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RFPConfig.Merge(dst, src)
+}
+func (m *RFPConfig) XXX_Size() int {
+	return xxx_messageInfo_RFPConfig.Size(m)
+}
+func (m *RFPConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_RFPConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RFPConfig proto.InternalMessageInfo
+
+func (m *RFPConfig) GetServers() map[string]*RFPServer {
+	if m != nil {
+		return m.Servers
+	}
+	return nil
+}
+
+func (m *RFPConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *RFPConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+func (m *RFPConfig) GetServerUrl() string {
+	if m != nil {
+		return m.ServerUrl
+	}
+	return ""
+}
+
+type RFPServer struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ip                   string   `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port                 int32    `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Insecure             bool     `protobuf:"varint,6,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RFPServer) Reset()         { *m = RFPServer{} }
+func (m *RFPServer) String() string { return proto.CompactTextString(m) }
+func (*RFPServer) ProtoMessage()    {}
+func (*RFPServer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_redfishpower_d644afe490ab4f6b, []int{1}
+}
+func (m *RFPServer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RFPServer.Unmarshal(m, b)
+}
+func (m *RFPServer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RFPServer.Marshal(b, m, deterministic)
+}
+func (dst *RFPServer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RFPServer.Merge(dst, src)
+}
+func (m *RFPServer) XXX_Size() int {
+	return xxx_messageInfo_RFPServer.Size(m)
+}
+func (m *RFPServer) XXX_DiscardUnknown() {
+	xxx_messageInfo_RFPServer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RFPServer proto.InternalMessageInfo
+
+func (m *RFPServer) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RFPServer) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *RFPServer) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *RFPServer) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RFPServer) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *RFPServer) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*RFPConfig)(nil), "proto.RFPConfig")
+	proto.RegisterMapType((map[string]*RFPServer)(nil), "proto.RFPConfig.ServersEntry")
+	proto.RegisterType((*RFPServer)(nil), "proto.RFPServer")
+}
+
+func init() { proto.RegisterFile("redfishpower.proto", fileDescriptor_redfishpower_d644afe490ab4f6b) }
+
+var fileDescriptor_redfishpower_d644afe490ab4f6b = []byte{
+	// 288 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8f, 0x4f, 0x4b, 0x33, 0x31,
+	0x10, 0xc6, 0xc9, 0xb6, 0xdb, 0x3f, 0xd3, 0x97, 0xd7, 0x32, 0xa7, 0x58, 0x28, 0x94, 0x1e, 0xa4,
+	0x5e, 0x7a, 0xa8, 0x07, 0xc5, 0xab, 0x28, 0x08, 0x1e, 0x24, 0xd2, 0x73, 0x59, 0x6d, 0x5a, 0x83,
+	0x31, 0x09, 0x93, 0xdd, 0x2d, 0xfd, 0x24, 0x7e, 0x54, 0xaf, 0x92, 0x64, 0xbb, 0x7a, 0xca, 0x3c,
+	0xf3, 0x7b, 0x32, 0xf3, 0x0c, 0x20, 0xc9, 0xed, 0x4e, 0xf9, 0x77, 0x67, 0x0f, 0x92, 0x96, 0x8e,
+	0x6c, 0x69, 0x31, 0x8f, 0xcf, 0xfc, 0x9b, 0xc1, 0x50, 0x3c, 0x3c, 0xdf, 0x59, 0xb3, 0x53, 0x7b,
+	0xbc, 0x86, 0xbe, 0x97, 0x54, 0x4b, 0xf2, 0x9c, 0xcd, 0x3a, 0x8b, 0xd1, 0x6a, 0x9a, 0xdc, 0xcb,
+	0xd6, 0xb2, 0x7c, 0x49, 0xfc, 0xde, 0x94, 0x74, 0x14, 0x27, 0x37, 0x5e, 0xc2, 0xd8, 0x59, 0xad,
+	0x95, 0xd9, 0x6f, 0x94, 0x29, 0x25, 0xd5, 0x85, 0xe6, 0xd9, 0x8c, 0x2d, 0x86, 0xe2, 0xac, 0xe9,
+	0x3f, 0x36, 0x6d, 0x3c, 0x87, 0x81, 0x29, 0x3e, 0xe5, 0xa6, 0x22, 0xcd, 0x3b, 0xd1, 0xd2, 0x0f,
+	0x7a, 0x4d, 0x1a, 0xa7, 0x00, 0x69, 0x60, 0x84, 0xdd, 0x08, 0x87, 0xa9, 0xb3, 0x26, 0x3d, 0x79,
+	0x82, 0x7f, 0x7f, 0xb7, 0xe3, 0x18, 0x3a, 0x1f, 0xf2, 0xc8, 0x59, 0xf4, 0x85, 0x12, 0x2f, 0x20,
+	0xaf, 0x0b, 0x5d, 0xc9, 0xb8, 0x7b, 0xb4, 0x1a, 0xff, 0xa6, 0x4f, 0x1f, 0x45, 0xc2, 0xb7, 0xd9,
+	0x0d, 0x9b, 0x7f, 0xa5, 0xcb, 0x13, 0x40, 0x84, 0x6e, 0x48, 0xd1, 0x0c, 0x8b, 0x35, 0xfe, 0x87,
+	0x4c, 0xb9, 0xe6, 0x8c, 0x4c, 0xb9, 0xe0, 0x71, 0x96, 0xca, 0x98, 0x3a, 0x17, 0xb1, 0xc6, 0x09,
+	0x0c, 0x2a, 0x2f, 0x29, 0xfe, 0x4d, 0x81, 0x5b, 0x1d, 0x98, 0x2b, 0xbc, 0x3f, 0x58, 0xda, 0xf2,
+	0x3c, 0xb1, 0x93, 0x0e, 0x4c, 0x19, 0x2f, 0xdf, 0x2a, 0x92, 0xbc, 0x37, 0x63, 0x8b, 0x81, 0x68,
+	0xf5, 0x6b, 0x2f, 0xa6, 0xbe, 0xfa, 0x19, 0x00, 0x20, 0xbb, 0xb4, 0x96, 0xb7, 0x01, 0x00, 0x00,
+}
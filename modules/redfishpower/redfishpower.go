@@ -0,0 +1,494 @@
+/* redfishpower.go: mutations for BMCs that speak the DMTF Redfish REST API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/redfishpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = redfish.
+ * Each node needs a Redfish system ID (NameUrl) and the name of its BMC (ServerUrl).
+ */
+
+package redfishpower
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/redfishpower/proto"
+)
+
+const (
+	RFSystems      string = "/redfish/v1/Systems"
+	RFReset        string = "/Actions/ComputerSystem.Reset"
+	PlatformString string = "redfish"
+)
+
+// rfSystem is the part of a Redfish ComputerSystem we care about
+type rfSystem struct {
+	Id         string `json:"Id,omitempty"`
+	PowerState string `json:"PowerState,omitempty"`
+}
+
+// rfReset is the body of a ComputerSystem.Reset action
+type rfReset struct {
+	ResetType string `json:"ResetType"`
+}
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "30s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// RFP Object /
+//////////////////
+
+// RFP provides a power on/off interface to Redfish BMCs
+type RFP struct {
+	api        lib.APIClient
+	cfg        *pb.RFPConfig
+	clients    map[string]*http.Client // by BMC; rebuilt with the config
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*RFP)(nil)
+
+// Name returns the FQDN of the module
+func (*RFP) Name() string { return "github.com/hpc/kraken/modules/redfishpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*RFP)(nil)
+var _ lib.ModuleWithConfigValidation = (*RFP)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*RFP) NewConfig() proto.Message {
+	r := &pb.RFPConfig{
+		ServerUrl: "type.googleapis.com/proto.Redfish/Bmc",
+		NameUrl:   "type.googleapis.com/proto.Redfish/SystemId",
+		Servers: map[string]*pb.RFPServer{
+			"bmc": {
+				Name:     "bmc",
+				Ip:       "localhost",
+				Port:     443,
+				Username: "root",
+				Password: "",
+				Insecure: true,
+			},
+		},
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (rf *RFP) UpdateConfig(cfg proto.Message) (e error) {
+	if rfcfg, ok := cfg.(*pb.RFPConfig); ok {
+		old := rf.clients
+		rf.cfg, rf.clients = rfcfg, newClients(rfcfg)
+		for _, c := range old {
+			c.CloseIdleConnections()
+		}
+		if rf.pollTicker != nil {
+			rf.pollTicker.Stop()
+			dur, _ := time.ParseDuration(rf.cfg.GetPollingInterval())
+			rf.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig makes sure the polling interval is a positive duration
+func (*RFP) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.RFPConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*RFP) ConfigURL() string {
+	cfg := &pb.RFPConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*RFP)(nil)
+var _ lib.ModuleWithDiscovery = (*RFP)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (rf *RFP) SetMutationChan(c <-chan lib.Event) { rf.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (rf *RFP) SetDiscoveryChan(c chan<- lib.Event) { rf.dchan = c }
+
+/*
+ * lib.ModuleWithRediscover
+ */
+var _ lib.ModuleWithRediscover = (*RFP)(nil)
+
+// Rediscover polls the power state of nodes (or every node) now, rather than waiting for the next poll
+func (rf *RFP) Rediscover(nodes []string) { rf.discoverNodes(nodes) }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*RFP)(nil)
+
+// Entry is the module's executable entrypoint
+func (rf *RFP) Entry() {
+	url := lib.NodeURLJoin(rf.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "redfishpower"), "State"))
+	rf.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rf.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(rf.cfg.GetPollingInterval())
+	rf.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-rf.pollTicker.C:
+			go rf.discoverAll()
+			break
+		case m := <-rf.mchan: // mutation request
+			go rf.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (rf *RFP) Init(api lib.APIClient) {
+	rf.api = api
+	rf.cfg = rf.NewConfig().(*pb.RFPConfig)
+	rf.clients = newClients(rf.cfg)
+}
+
+// Stop should perform a graceful exit
+func (rf *RFP) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (rf *RFP) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		rf.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's system id and bmc
+	vs := me.NodeCfg.GetValues([]string{rf.cfg.GetNameUrl(), rf.cfg.GetServerUrl()})
+	if len(vs) != 2 {
+		rf.api.Logf(lib.LLERROR, "could not get system ID and/or BMC for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[rf.cfg.GetNameUrl()].String()
+	srv := vs[rf.cfg.GetServerUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			rf.sysDiscover(srv, name, me.NodeCfg.ID())
+		case "OFFtoON":
+			rf.sysReset(me.Context, srv, name, "On", cpb.Node_POWER_ON, me.NodeCfg.ID())
+		case "ONtoOFF":
+			rf.sysReset(me.Context, srv, name, "ForceOff", cpb.Node_POWER_OFF, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			rf.sysReset(me.Context, srv, name, "ForceOff", cpb.Node_POWER_OFF, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			rf.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request in progress
+		break
+	}
+}
+
+// request makes an authenticated request against a BMC; it's abandoned if ctx is canceled
+func (rf *RFP) request(ctx context.Context, srvName, method, path string, body []byte) (resp *http.Response, e error) {
+	srv, ok := rf.cfg.Servers[srvName]
+	client, cok := rf.clients[srvName]
+	if !ok || !cok {
+		return nil, fmt.Errorf("cannot control power for unknown BMC: %s", srvName)
+	}
+	addr := srv.Ip + ":" + strconv.Itoa(int(srv.Port))
+	req, e := http.NewRequest(method, "https://"+addr+path, bytes.NewReader(body))
+	if e != nil {
+		return
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(srv.Username, srv.Password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return client.Do(req)
+}
+
+// newClients makes an HTTP client for each BMC in cfg, so requests to a BMC reuse its connections
+func newClients(cfg *pb.RFPConfig) map[string]*http.Client {
+	r := make(map[string]*http.Client)
+	for name, srv := range cfg.Servers {
+		r[name] = &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: srv.Insecure},
+			},
+		}
+	}
+	return r
+}
+
+func (rf *RFP) sysDiscover(srvName, name string, id lib.NodeID) {
+	start := time.Now()
+	resp, e := rf.request(context.Background(), srvName, http.MethodGet, RFSystems+"/"+name, nil)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error dialing BMC: %v", e)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		rf.api.Logf(lib.LLERROR, "error dialing BMC: HTTP %v", resp.StatusCode)
+		return
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error reading BMC response body: %v", e)
+		return
+	}
+	var rs rfSystem
+	e = json.Unmarshal(body, &rs)
+	if e != nil {
+		rf.api.Logf(lib.LLERROR, "error unmarshaling json: %v", e)
+		return
+	}
+	var vid string
+	switch rs.PowerState {
+	case "Off":
+		vid = "POWER_OFF"
+	case "On":
+		vid = "POWER_ON"
+	default: // PoweringOn, PoweringOff, or missing
+		vid = "PHYS_UNKNOWN"
+	}
+
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rf.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    start,
+		},
+	)
+	rf.dchan <- v
+}
+
+// sysReset issues a ComputerSystem.Reset of type rtype and reports state on success
+// if the mutation is interrupted, we report whatever state the system was left in instead
+func (rf *RFP) sysReset(ctx context.Context, srvName, name, rtype string, state cpb.Node_PhysState, id lib.NodeID) {
+	body, _ := json.Marshal(rfReset{ResetType: rtype})
+	resp, e := rf.request(ctx, srvName, http.MethodPost, RFSystems+"/"+name+RFReset, body)
+	if e != nil {
+		if ctx.Err() != nil {
+			rf.api.Logf(lib.LLINFO, "interrupted: redfish reset %s of %s", rtype, name)
+			rf.sysDiscover(srvName, name, id)
+			return
+		}
+		rf.api.Logf(lib.LLERROR, "error dialing BMC: %v", e)
+		return
+	}
+	defer resp.Body.Close()
+	// BMCs answer with any of 200, 202 or 204 on success
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		rb, _ := ioutil.ReadAll(resp.Body)
+		rf.api.Logf(lib.LLERROR, "redfish reset %s failed for %s: HTTP %v: %s", rtype, name, resp.StatusCode, string(rb))
+		return
+	}
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  rf.Name(),
+			URL:     url,
+			ValueID: cpb.Node_PhysState_name[int32(state)],
+		},
+	)
+	rf.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// Note: this is probably not extremely efficient for large systems
+func (rf *RFP) discoverAll() {
+	rf.api.Log(lib.LLDEBUG, "polling for node state")
+	rf.discoverNodes(nil)
+}
+
+// discoverNodes discovers the power state of the nodes with IDs ids, or of every node if ids is empty
+func (rf *RFP) discoverNodes(ids []string) {
+	var ns []lib.Node
+	var e error
+	if len(ids) == 0 {
+		if ns, e = rf.api.QueryReadAll(); e != nil {
+			rf.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+			return
+		}
+	}
+	for _, id := range ids {
+		n, e := rf.api.QueryRead(id)
+		if e != nil {
+			rf.api.Logf(lib.LLERROR, "node query failed for %s: %v", id, e)
+			continue
+		}
+		ns = append(ns, n)
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", rf.cfg.GetNameUrl(), rf.cfg.GetServerUrl()})
+		if len(vs) != 3 {
+			rf.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete Redfish info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		// every node has its own BMC session, so there's nothing to batch here
+		go rf.sysDiscover(vs[rf.cfg.GetServerUrl()].String(), vs[rf.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &RFP{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/redfishpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("redfishpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
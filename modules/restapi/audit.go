@@ -0,0 +1,231 @@
+/* audit.go: records who changed what through the ReST API in Kraken's audit log
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// unaudited are the routes that aren't GETs, but don't change anything
+var unaudited = map[string]bool{
+	"POST /graphql":    true,
+	"POST /graph/plan": true,
+}
+
+// auditActions are routes that are audited, but don't change state themselves, so there's nothing to compare
+var auditActions = map[string]bool{
+	"POST /dsc/discover": true,
+}
+
+// auditBodyMax is how much of an error response is kept as the result
+const auditBodyMax = 256
+
+// An auditWriter remembers the status of a response, and the start of its body if it's an error
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *auditWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status >= 300 && w.body.Len() < auditBodyMax {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// result describes how the request went
+func (w *auditWriter) result() string {
+	if w.status == 0 || w.status < 300 {
+		return "ok"
+	}
+	r := fmt.Sprintf("%d %s", w.status, http.StatusText(w.status))
+	if b := strings.TrimSpace(w.body.String()); b != "" {
+		if len(b) > auditBodyMax {
+			b = b[:auditBodyMax]
+		}
+		r += ": " + b
+	}
+	return r
+}
+
+// audit records requests that change state in the audit log: who made them, from where, what they changed on each node, and how they went.
+// Requests that are denied are recorded too.
+func (r *RestAPI) audit(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t := ""
+		if route := mux.CurrentRoute(req); route != nil {
+			t, _ = route.GetPathTemplate()
+		}
+		action := req.Method + " " + versionTemplate(t)
+		if req.Method == "GET" || req.Method == "OPTIONS" || unaudited[action] {
+			h.ServeHTTP(w, req)
+			return
+		}
+		dsc := strings.HasPrefix(versionTemplate(t), "/dsc/")
+		var ids []string
+		var before map[string]lib.Node
+		if !auditActions[action] {
+			ids = r.auditTargets(req, t)
+			before = r.auditRead(ids, dsc)
+		}
+		now, _ := ptypes.TimestampProto(time.Now())
+		aw := &auditWriter{ResponseWriter: w}
+		h.ServeHTTP(aw, req)
+
+		p, _ := req.Context().Value(principalKey{}).(*principal)
+		rec := cpb.AuditRecord{
+			Time:   now,
+			User:   "anonymous",
+			Source: req.RemoteAddr,
+			Module: "restapi",
+			Action: action,
+			Result: aw.result(),
+		}
+		if p != nil && p.name != "" {
+			rec.User = p.name
+		}
+		var rs []*cpb.AuditRecord
+		if !auditActions[action] {
+			after := r.auditRead(ids, dsc)
+			for id := range after {
+				if _, ok := before[id]; !ok {
+					before[id] = nil
+				}
+			}
+			for id, old := range before {
+				cs := core.NewAuditChanges(old, after[id])
+				if len(cs) == 0 {
+					continue
+				}
+				nr := rec
+				nr.Node, nr.Changes = id, cs
+				rs = append(rs, &nr)
+			}
+		}
+		r.api.Logf(lib.LLINFO, "audit: %s by %s from %s changed %d node(s): %s", action, rec.User, rec.Source, len(rs), rec.Result)
+		if len(rs) == 0 {
+			// it's still worth knowing who tried
+			rec.Node = mux.Vars(req)["id"]
+			rs = append(rs, &rec)
+		}
+		if e := r.api.QueryAudit(rs); e != nil {
+			r.api.Logf(lib.LLERROR, "failed to record %s by %s in the audit log: %v", action, rec.User, e)
+		}
+	})
+}
+
+// auditTargets works out which nodes a request could change: the one it names, the ones in its body, or the ones in the group it names.
+// If it's none of those (e.g. for /cfg/bulk), it could be any of them, and it's nil.
+func (r *RestAPI) auditTargets(req *http.Request, t string) (ids []string) {
+	vars := mux.Vars(req)
+	if id, ok := vars["id"]; ok {
+		ids = append(ids, id)
+	}
+	for _, n := range bodyNodes(req) {
+		if !n.ID().Nil() {
+			ids = append(ids, n.ID().String())
+		}
+	}
+	if strings.Contains(t, "/group/{name}") {
+		ns, _ := r.api.QueryReadGroup(vars["name"])
+		for _, n := range ns {
+			ids = append(ids, n.ID().String())
+		}
+	}
+	return
+}
+
+// auditRead gets nodes' configuration (or discovered) state by ID; nil ids gets every node
+func (r *RestAPI) auditRead(ids []string, dsc bool) map[string]lib.Node {
+	ns := make(map[string]lib.Node)
+	if ids == nil {
+		all, _ := r.api.QueryReadAll()
+		if dsc {
+			all, _ = r.api.QueryReadAllDsc()
+		}
+		for _, n := range all {
+			ns[n.ID().String()] = n
+		}
+		return ns
+	}
+	for _, id := range ids {
+		read := r.api.QueryRead
+		if dsc {
+			read = r.api.QueryReadDsc
+		}
+		if n, e := read(id); e == nil && n != nil {
+			ns[n.ID().String()] = n
+		}
+	}
+	return ns
+}
+
+// readAuditLog gets audit log records, by node, user and time
+func (r *RestAPI) readAuditLog(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	q := &cpb.AuditLogQuery{
+		Node: req.URL.Query().Get("node"),
+		User: req.URL.Query().Get("user"),
+	}
+	var e error
+	for _, b := range []struct {
+		param string
+		ts    **timestamp.Timestamp
+	}{{"from", &q.From}, {"to", &q.To}} {
+		s := req.URL.Query().Get(b.param)
+		if s == "" {
+			continue
+		}
+		var t time.Time
+		if t, e = time.Parse(time.RFC3339, s); e == nil {
+			*b.ts, e = ptypes.TimestampProto(t)
+		}
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+	}
+	rs, e := r.api.QueryAuditLog(q)
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	b, e := core.MarshalJSON(&cpb.AuditRecordList{Records: rs})
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	w.Write(b)
+}
@@ -0,0 +1,292 @@
+/* auth.go: authenticates requests to the ReST API with bearer tokens, or JWTs (see rbac.go for what they can do)
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+// unauthenticated are the paths anyone can GET, e.g. for health checks
+var unauthenticated = map[string]bool{
+	"/health":               true,
+	"/healthz":              true,
+	"/readyz":               true,
+	"/swagger.json":         true,
+	apiV1 + "/health":       true,
+	apiV1 + "/healthz":      true,
+	apiV1 + "/readyz":       true,
+	apiV1 + "/swagger.json": true,
+}
+
+// jwtHashes are the hashes used by each JWT algorithm we support
+var jwtHashes = map[string]crypto.Hash{
+	"HS256": crypto.SHA256, "HS384": crypto.SHA384, "HS512": crypto.SHA512,
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// An authenticator checks requests against a RestAPIAuth config
+type authenticator struct {
+	disabled bool
+	tokens   [][]byte
+	users    map[string]*pb.RestAPIUser // by name
+	issuer   string
+	audience string
+	secrets  [][]byte
+	rsaKeys  []*rsa.PublicKey
+	ecKeys   []*ecdsa.PublicKey
+}
+
+// newAuthenticator creates an authenticator for cfg; with no cfg, nothing can authenticate.
+// Keys that can't be parsed are left out, and reported in e.
+func newAuthenticator(cfg *pb.RestAPIAuth) (a *authenticator, e error) {
+	a = &authenticator{users: make(map[string]*pb.RestAPIUser)}
+	if cfg == nil {
+		return
+	}
+	a.disabled = cfg.GetDisabled()
+	for _, t := range cfg.GetTokens() {
+		if t != "" {
+			a.tokens = append(a.tokens, []byte(t))
+		}
+	}
+	for _, u := range cfg.GetUsers() {
+		a.users[u.GetName()] = u
+	}
+	jwt := cfg.GetJwt()
+	if jwt == nil {
+		return
+	}
+	a.issuer = jwt.GetIssuer()
+	a.audience = jwt.GetAudience()
+	for _, s := range jwt.GetSecrets() {
+		if s != "" {
+			a.secrets = append(a.secrets, []byte(s))
+		}
+	}
+	for i, k := range jwt.GetPublicKeys() {
+		b, _ := pem.Decode([]byte(k))
+		if b == nil {
+			e = fmt.Errorf("public key %d is not PEM encoded", i)
+			continue
+		}
+		pk, err := x509.ParsePKIXPublicKey(b.Bytes)
+		if err != nil {
+			e = fmt.Errorf("could not parse public key %d: %v", i, err)
+			continue
+		}
+		switch pk := pk.(type) {
+		case *rsa.PublicKey:
+			a.rsaKeys = append(a.rsaKeys, pk)
+		case *ecdsa.PublicKey:
+			a.ecKeys = append(a.ecKeys, pk)
+		default:
+			e = fmt.Errorf("public key %d is not an RSA or ECDSA key", i)
+		}
+	}
+	return
+}
+
+// authenticate finds who a bearer token is for: one of our tokens, a user's token, or a valid JWT
+func (a *authenticator) authenticate(token string, now time.Time) (*principal, error) {
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+			return &principal{name: tokenName(t), all: true}, nil
+		}
+	}
+	for _, u := range a.users {
+		if u.GetToken() != "" && subtle.ConstantTimeCompare([]byte(u.GetToken()), []byte(token)) == 1 {
+			return &principal{name: u.GetName(), grants: u.GetGrants()}, nil
+		}
+	}
+	if strings.Count(token, ".") != 2 {
+		return nil, fmt.Errorf("unknown token")
+	}
+	sub, e := a.validateJWT(token, now)
+	if e != nil {
+		return nil, e
+	}
+	return a.user(sub), nil
+}
+
+// user gets who someone that was authenticated elsewhere (e.g. by a JWT's issuer, or a proxy) is.
+// They have full access if there are no users; someone we don't know gets in, but can't do anything.
+func (a *authenticator) user(name string) *principal {
+	if len(a.users) == 0 {
+		return &principal{name: name, all: true}
+	}
+	return &principal{name: name, grants: a.users[name].GetGrants()}
+}
+
+// tokenName names a static token, so what's done with it can be told apart from what's done with other tokens, without giving it away
+func tokenName(t []byte) string {
+	h := sha256.Sum256(t)
+	return "token:" + hex.EncodeToString(h[:4])
+}
+
+// validateJWT checks a JWT's signature, and its iss, aud, exp and nbf claims; it gets the sub claim
+func (a *authenticator) validateJWT(token string, now time.Time) (sub string, e error) {
+	parts := strings.Split(token, ".")
+	var hdr struct {
+		Alg string `json:"alg"`
+	}
+	if e = decodeJWTPart(parts[0], &hdr); e != nil {
+		return "", fmt.Errorf("bad JWT header: %v", e)
+	}
+	h, ok := jwtHashes[hdr.Alg]
+	if !ok {
+		return "", fmt.Errorf("unsupported JWT algorithm: %s", hdr.Alg)
+	}
+	sig, e := base64.RawURLEncoding.DecodeString(parts[2])
+	if e != nil {
+		return "", fmt.Errorf("bad JWT signature: %v", e)
+	}
+	if !a.verify(hdr.Alg[:2], h, parts[0]+"."+parts[1], sig) {
+		return "", fmt.Errorf("JWT signature did not verify")
+	}
+	var claims struct {
+		Sub string          `json:"sub"`
+		Iss string          `json:"iss"`
+		Aud json.RawMessage `json:"aud"`
+		Exp *int64          `json:"exp"`
+		Nbf *int64          `json:"nbf"`
+	}
+	if e = decodeJWTPart(parts[1], &claims); e != nil {
+		return "", fmt.Errorf("bad JWT claims: %v", e)
+	}
+	if claims.Exp != nil && now.Unix() >= *claims.Exp {
+		return "", fmt.Errorf("JWT has expired")
+	}
+	if claims.Nbf != nil && now.Unix() < *claims.Nbf {
+		return "", fmt.Errorf("JWT is not valid yet")
+	}
+	if a.issuer != "" && claims.Iss != a.issuer {
+		return "", fmt.Errorf("JWT has the wrong issuer: %s", claims.Iss)
+	}
+	if a.audience != "" {
+		// aud can be a string, or a list of them
+		var auds []string
+		if e := json.Unmarshal(claims.Aud, &auds); e != nil {
+			var aud string
+			json.Unmarshal(claims.Aud, &aud)
+			auds = []string{aud}
+		}
+		found := false
+		for _, aud := range auds {
+			if aud == a.audience {
+				found = true
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("JWT is not for this audience")
+		}
+	}
+	return claims.Sub, nil
+}
+
+// verify checks sig over signed with our keys of the kind alg needs (HS, RS or ES)
+// Only trying the keys the algorithm calls for means a token can't pick how its own signature is checked.
+func (a *authenticator) verify(kind string, h crypto.Hash, signed string, sig []byte) bool {
+	hs := h.New()
+	hs.Write([]byte(signed))
+	digest := hs.Sum(nil)
+	switch kind {
+	case "HS":
+		for _, s := range a.secrets {
+			m := hmac.New(h.New, s)
+			m.Write([]byte(signed))
+			if hmac.Equal(m.Sum(nil), sig) {
+				return true
+			}
+		}
+	case "RS":
+		for _, k := range a.rsaKeys {
+			if rsa.VerifyPKCS1v15(k, h, digest, sig) == nil {
+				return true
+			}
+		}
+	case "ES":
+		// the signature is r and s, each the size of the curve
+		for _, k := range a.ecKeys {
+			size := (k.Curve.Params().BitSize + 7) / 8
+			if len(sig) != 2*size {
+				continue
+			}
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			if ecdsa.Verify(k, digest, r, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, e := base64.RawURLEncoding.DecodeString(part)
+	if e != nil {
+		return e
+	}
+	return json.Unmarshal(b, v)
+}
+
+// authHandler only passes requests that authenticate on to h, with who they're from in their context.
+// Requests that a trusted proxy authenticated are from the user it says they are.
+func (r *RestAPI) authHandler(a *authenticator, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if a.disabled || (req.Method == "GET" && unauthenticated[req.URL.Path]) {
+			h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), principalKey{}, &principal{all: true})))
+			return
+		}
+		if f, _ := req.Context().Value(forwardingKey{}).(*forwarding); f != nil && f.user != "" {
+			h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), principalKey{}, a.user(f.user))))
+			return
+		}
+		auth := req.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		p, e := a.authenticate(strings.TrimPrefix(auth, "Bearer "), time.Now())
+		if e != nil {
+			r.api.Logf(lib.LLNOTICE, "rejected request from %s for %s: %v", req.RemoteAddr, req.URL.Path, e)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), principalKey{}, p)))
+	})
+}
+
+// health lets load balancers, etc. know we're up
+func (r *RestAPI) health(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,214 @@
+package restapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+var (
+	testRSAKey *rsa.PrivateKey
+	testECKey  *ecdsa.PrivateKey
+)
+
+func init() {
+	var e error
+	if testRSAKey, e = rsa.GenerateKey(rand.Reader, 2048); e != nil {
+		panic(e)
+	}
+	if testECKey, e = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); e != nil {
+		panic(e)
+	}
+}
+
+func publicPEM(t *testing.T, k interface{}) string {
+	b, e := x509.MarshalPKIXPublicKey(k)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+// makeJWT builds a JWT with alg and claims, signed by sign
+func makeJWT(alg string, claims map[string]interface{}, sign func(signed string) []byte) string {
+	enc := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": alg, "typ": "JWT"}) + "." + enc(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed))
+}
+
+func signHS(secret []byte) func(string) []byte {
+	return func(signed string) []byte {
+		m := hmac.New(sha256.New, secret)
+		m.Write([]byte(signed))
+		return m.Sum(nil)
+	}
+}
+
+func signRS(signed string) []byte {
+	d := sha256.Sum256([]byte(signed))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, testRSAKey, crypto.SHA256, d[:])
+	return sig
+}
+
+// signES signs the way JWTs should be: r and s, each padded to the size of the curve
+func signES(signed string) []byte {
+	d := sha256.Sum256([]byte(signed))
+	r, s, _ := ecdsa.Sign(rand.Reader, testECKey, d[:])
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig
+}
+
+// signESDER signs the way some libraries get wrong, with an ASN.1 signature
+func signESDER(signed string) []byte {
+	d := sha256.Sum256([]byte(signed))
+	r, s, _ := ecdsa.Sign(rand.Reader, testECKey, d[:])
+	sig, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	return sig
+}
+
+func TestValidateJWT(t *testing.T) {
+	now := time.Now()
+	secret := []byte("sekrit")
+	rsaPEM, ecPEM := publicPEM(t, &testRSAKey.PublicKey), publicPEM(t, &testECKey.PublicKey)
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "alice", "iss": "https://idp", "aud": "kraken", "exp": now.Add(time.Hour).Unix()}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	hmacOnly := &pb.RestAPIJWT{Issuer: "https://idp", Audience: "kraken", Secrets: []string{string(secret)}}
+	rsaOnly := &pb.RestAPIJWT{PublicKeys: []string{rsaPEM}}
+	ecOnly := &pb.RestAPIJWT{PublicKeys: []string{ecPEM}}
+
+	tests := []struct {
+		name  string
+		jwt   *pb.RestAPIJWT
+		token string
+		ok    bool
+	}{
+		{"HS256", hmacOnly, makeJWT("HS256", claims(nil), signHS(secret)), true},
+		{"RS256", rsaOnly, makeJWT("RS256", claims(nil), signRS), true},
+		{"ES256", ecOnly, makeJWT("ES256", claims(nil), signES), true},
+		{"wrong secret", hmacOnly, makeJWT("HS256", claims(nil), signHS([]byte("guess"))), false},
+		{"tampered claims", hmacOnly, func() string {
+			p := strings.Split(makeJWT("HS256", claims(nil), signHS(secret)), ".")
+			forged := strings.Split(makeJWT("HS256", claims(map[string]interface{}{"sub": "root"}), signHS(nil)), ".")
+			return p[0] + "." + forged[1] + "." + p[2]
+		}(), false},
+		// alg confusion: HMAC with the public key as the secret
+		{"HS256 against an RSA key", rsaOnly, makeJWT("HS256", claims(nil), signHS([]byte(rsaPEM))), false},
+		{"RS256 against a secret", hmacOnly, makeJWT("RS256", claims(nil), signRS), false},
+		{"none", hmacOnly, makeJWT("none", claims(nil), func(string) []byte { return nil }), false},
+		{"None", rsaOnly, makeJWT("None", claims(nil), func(string) []byte { return nil }), false},
+		{"expired", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"exp": now.Add(-time.Minute).Unix()}), signHS(secret)), false},
+		{"expires now", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"exp": now.Unix()}), signHS(secret)), false},
+		{"not valid yet", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"nbf": now.Add(time.Minute).Unix()}), signHS(secret)), false},
+		{"valid since now", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"nbf": now.Unix()}), signHS(secret)), true},
+		{"wrong issuer", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"iss": "https://evil"}), signHS(secret)), false},
+		{"no issuer", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"iss": nil}), signHS(secret)), false},
+		{"aud list", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"aud": []string{"other", "kraken"}}), signHS(secret)), true},
+		{"aud list without us", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"aud": []string{"other"}}), signHS(secret)), false},
+		{"wrong aud", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"aud": "other"}), signHS(secret)), false},
+		{"no aud", hmacOnly, makeJWT("HS256", claims(map[string]interface{}{"aud": nil}), signHS(secret)), false},
+		{"ES256 with an ASN.1 signature", ecOnly, makeJWT("ES256", claims(nil), signESDER), false},
+		{"ES256 with a short signature", ecOnly, makeJWT("ES256", claims(nil), func(s string) []byte { return signES(s)[:63] }), false},
+		{"ES256 with an empty signature", ecOnly, makeJWT("ES256", claims(nil), func(string) []byte { return nil }), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, e := newAuthenticator(&pb.RestAPIAuth{Jwt: tt.jwt})
+			if e != nil {
+				t.Fatal(e)
+			}
+			sub, e := a.validateJWT(tt.token, now)
+			if tt.ok && (e != nil || sub != "alice") {
+				t.Errorf("expected alice, got %q: %v", sub, e)
+			}
+			if !tt.ok && e == nil {
+				t.Errorf("token was accepted for %q", sub)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Now()
+	secret := []byte("sekrit")
+	a, e := newAuthenticator(&pb.RestAPIAuth{
+		Tokens: []string{"static-token"},
+		Users: []*pb.RestAPIUser{
+			{Name: "bob", Token: "bobs-token", Grants: []*pb.RestAPIGrant{{Role: pb.RestAPIGrant_POWER}}},
+			{Name: "alice", Grants: []*pb.RestAPIGrant{{Role: pb.RestAPIGrant_READ}}},
+		},
+		Jwt: &pb.RestAPIJWT{Secrets: []string{string(secret)}},
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	jwt := func(sub string) string {
+		return makeJWT("HS256", map[string]interface{}{"sub": sub, "exp": now.Add(time.Hour).Unix()}, signHS(secret))
+	}
+	tests := []struct {
+		name  string
+		token string
+		user  string // empty if it shouldn't authenticate
+		all   bool
+		roles int
+	}{
+		{"static token", "static-token", tokenName([]byte("static-token")), true, 0},
+		{"user token", "bobs-token", "bob", false, 1},
+		{"JWT for a user", jwt("alice"), "alice", false, 1},
+		{"JWT for someone else", jwt("mallory"), "mallory", false, 0},
+		// tokens only match in full
+		{"prefix of a token", "static-tok", "", false, 0},
+		{"token with more on the end", "static-token2", "", false, 0},
+		{"prefix of a user token", "bobs", "", false, 0},
+		{"empty", "", "", false, 0},
+		{"garbage", "a.b.c", "", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, e := a.authenticate(tt.token, now)
+			if tt.user == "" {
+				if e == nil {
+					t.Errorf("authenticated as %s", p.name)
+				}
+				return
+			}
+			if e != nil {
+				t.Fatal(e)
+			}
+			if p.name != tt.user || p.all != tt.all || len(p.grants) != tt.roles {
+				t.Errorf("expected %s (all: %v, %d grants), got %s (all: %v, %d grants)", tt.user, tt.all, tt.roles, p.name, p.all, len(p.grants))
+			}
+		})
+	}
+	if strings.Contains(tokenName([]byte("static-token")), "static") {
+		t.Error("token names give the token away")
+	}
+}
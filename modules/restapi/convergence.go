@@ -0,0 +1,95 @@
+/* convergence.go: a cluster convergence report, of which nodes' discovered states don't match their configurations, how, and for how long
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+// A ConvergenceReport is whether each node's discovered state matches its configuration, e.g. for a NOC to poll
+type ConvergenceReport struct {
+	Nodes     int               `json:"nodes"`
+	Converged int               `json:"converged"`
+	Diverged  int               `json:"diverged"`
+	Report    []NodeConvergence `json:"report"` // sorted by ID
+}
+
+// A NodeConvergence is whether a node's discovered state matches its configuration, in the values that mutations change
+type NodeConvergence struct {
+	ID              string            `json:"id"`
+	Nodename        string            `json:"nodename"`
+	Converged       bool              `json:"converged"`
+	Diff            map[string]string `json:"diff,omitempty"`            // URLs that don't match, as "dsc -> cfg" (as in NodeStatus)
+	DivergedSince   string            `json:"divergedSince,omitempty"`   // when it stopped matching, RFC3339; no earlier than when kraken started
+	DivergedFor     string            `json:"divergedFor,omitempty"`     // how long it hasn't matched, e.g. 3m2s
+	DivergedSeconds int64             `json:"divergedSeconds,omitempty"` // the same, in seconds
+	Mutating        bool              `json:"mutating"`                  // kraken is mutating it to match
+	Frozen          bool              `json:"frozen"`                    // it's frozen, so kraken won't
+}
+
+// readConvergence gets the convergence report; with diverged=true, only nodes that don't match are listed
+func (r *RestAPI) readConvergence(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	only := false
+	if s := req.URL.Query().Get("diverged"); s != "" {
+		var e error
+		if only, e = strconv.ParseBool(s); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("diverged must be true or false"))
+			return
+		}
+	}
+	ncs, e := r.api.QueryConvergence()
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	now := time.Now()
+	cr := ConvergenceReport{Report: []NodeConvergence{}}
+	for _, nc := range ncs {
+		cr.Nodes++
+		if nc.Converged {
+			cr.Converged++
+			if only {
+				continue
+			}
+		} else {
+			cr.Diverged++
+		}
+		n := NodeConvergence{
+			ID:        nc.Id,
+			Nodename:  nc.Nodename,
+			Converged: nc.Converged,
+			Mutating:  nc.Mutating,
+			Frozen:    nc.Frozen,
+		}
+		if len(nc.Deltas) > 0 {
+			n.Diff = make(map[string]string)
+			for _, d := range nc.Deltas {
+				n.Diff[d.Url] = d.Dsc + " -> " + d.Cfg
+			}
+		}
+		if t, e := ptypes.Timestamp(nc.Diverged); nc.Diverged != nil && e == nil {
+			d := now.Sub(t).Round(time.Second)
+			n.DivergedSince = t.Format(time.RFC3339)
+			n.DivergedFor = d.String()
+			n.DivergedSeconds = int64(d.Seconds())
+		}
+		cr.Report = append(cr.Report, n)
+	}
+	b, _ := json.Marshal(cr)
+	w.Write(b)
+}
@@ -0,0 +1,31 @@
+package restapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allow   string
+	}{
+		{"none configured", nil, "https://evil.example.com", ""},
+		{"listed origin", []string{"https://dash.example.com"}, "https://dash.example.com", "https://dash.example.com"},
+		{"unlisted origin", []string{"https://dash.example.com"}, "https://evil.example.com", ""},
+		{"explicit any", []string{"*"}, "https://evil.example.com", "*"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/cfg/nodes", nil)
+		req.Header.Set("Origin", tt.origin)
+		w := httptest.NewRecorder()
+		corsHandler(tt.origins, ok).ServeHTTP(w, req)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allow {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q, expected %q", tt.name, got, tt.allow)
+		}
+	}
+}
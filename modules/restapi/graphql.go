@@ -0,0 +1,845 @@
+/* graphql.go: an optional GraphQL endpoint, for reading nodes, their extensions, services and mutations in one round trip
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+/*
+ * The schema is read only, and follows the protobuf JSON of nodes (see core.MarshalJSON):
+ *
+ *	type Query {
+ *		nodes(state: State = CFG, group: String, select: String, sort: String, offset: Int, limit: Int): [Node]
+ *		node(id: String!, state: State = CFG): Node
+ *	}
+ *	enum State { CFG DSC }
+ *
+ * Node has the fields of a protobuf Node, with id and parentId as UUID strings, and:
+ *
+ *	cfg: Node           # the node's configuration state
+ *	dsc: Node           # the node's discovered state
+ *	mutation: MutationPath  # the mutations underway, or null
+ *
+ * Extensions, and services' configs, are protobuf Anys; they're returned whole, as JSON.
+ * Nodes the requester can't READ are left out.  Fragments, variables and @skip/@include work; introspection doesn't.
+ */
+
+var (
+	nodeType         = reflect.TypeOf(cpb.Node{})
+	mutationPathType = reflect.TypeOf(cpb.MutationPath{})
+)
+
+// A graphQLRequest is a query, as it's posted
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQL answers GraphQL queries, from the query URL parameter (GET) or a JSON request body (POST)
+func (r *RestAPI) graphQL(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	if !r.cfg.GetGraphql() {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var gr graphQLRequest
+	if req.Method == "GET" {
+		q := req.URL.Query()
+		gr.Query = q.Get("query")
+		gr.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if e := json.Unmarshal([]byte(v), &gr.Variables); e != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(e.Error()))
+				return
+			}
+		}
+	} else if e := json.NewDecoder(req.Body).Decode(&gr); e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	p, _ := req.Context().Value(principalKey{}).(*principal)
+	b, _ := json.Marshal(r.execGraphQL(p, gr.Query, gr.OperationName, gr.Variables))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// execGraphQL runs a query for p, and gets its response: the data, and any errors
+func (r *RestAPI) execGraphQL(p *principal, query, opName string, vars map[string]interface{}) gqlObject {
+	fail := func(f string, a ...interface{}) gqlObject {
+		return gqlObject{{"errors", []gqlObject{{{"message", fmt.Sprintf(f, a...)}}}}}
+	}
+	doc, e := gqlParse(query)
+	if e != nil {
+		return fail("syntax error: %v", e)
+	}
+	var op *gqlOperation
+	for _, o := range doc.ops {
+		if (opName == "" && len(doc.ops) == 1) || o.name == opName {
+			op = o
+		}
+	}
+	switch {
+	case op == nil && opName == "":
+		return fail("an operationName is required for a document with more than one operation")
+	case op == nil:
+		return fail("unknown operation: %s", opName)
+	case op.kind != "query":
+		return fail("only queries are supported; changes are made through the ReST API")
+	}
+	x := &gqlExec{r: r, p: p, doc: doc, vars: op.vars}
+	for k, v := range vars {
+		x.vars[k] = v
+	}
+	rsp := gqlObject{{"data", x.query(op.sel)}}
+	if len(x.errs) > 0 {
+		rsp = append(rsp, gqlPair{"errors", x.errs})
+	}
+	return rsp
+}
+
+// A gqlExec is the state of one query
+type gqlExec struct {
+	r    *RestAPI
+	p    *principal
+	doc  *gqlDocument
+	vars map[string]interface{}
+	errs []gqlObject
+}
+
+func (x *gqlExec) fail(f string, a ...interface{}) {
+	x.errs = append(x.errs, gqlObject{{"message", fmt.Sprintf(f, a...)}})
+}
+
+// query resolves the fields of the Query type
+func (x *gqlExec) query(sel []*gqlSelection) gqlObject {
+	obj := gqlObject{}
+	for _, f := range x.fields(sel, "Query") {
+		var v interface{}
+		switch f.name {
+		case "__typename":
+			v = "Query"
+		case "nodes":
+			if !x.selects(nodeType, f) {
+				break
+			}
+			ns, e := x.nodes(f)
+			if e != nil {
+				x.fail("nodes: %v", e)
+				break
+			}
+			l := []interface{}{}
+			for _, n := range ns {
+				l = append(l, x.node(n, f.sel))
+			}
+			v = l
+		case "node":
+			if !x.selects(nodeType, f) {
+				break
+			}
+			id, _ := x.arg(f, "id").(string)
+			if id == "" {
+				x.fail("node: an id is required")
+				break
+			}
+			n, e := x.read(id, x.arg(f, "state") == "DSC")
+			if e == nil && n != nil {
+				v = x.node(n, f.sel)
+			}
+		default:
+			x.fail("cannot query field %q on type \"Query\"", f.name)
+		}
+		obj = append(obj, gqlPair{f.key(), v})
+	}
+	return obj
+}
+
+// nodes gets the nodes a nodes field asks for, that we're allowed to read
+func (x *gqlExec) nodes(f *gqlSelection) (ns []lib.Node, e error) {
+	dsc := x.arg(f, "state") == "DSC"
+	group, _ := x.arg(f, "group").(string)
+	switch {
+	case group != "" && dsc:
+		ns, e = x.r.api.QueryReadGroupDsc(group)
+	case group != "":
+		ns, e = x.r.api.QueryReadGroup(group)
+	case dsc:
+		ns, e = x.r.api.QueryReadAllDsc()
+	default:
+		ns, e = x.r.api.QueryReadAll()
+	}
+	if e != nil {
+		return
+	}
+	var sel *core.NodeSelector
+	if s, _ := x.arg(f, "select").(string); s != "" {
+		if sel, e = core.ParseNodeSelector(s); e != nil {
+			return nil, e
+		}
+	}
+	var m []lib.Node
+	for _, n := range ns {
+		if x.p.canNode(pb.RestAPIGrant_READ, n) && (sel == nil || sel.Match(n)) {
+			m = append(m, n)
+		}
+	}
+	ns = m
+	if s, _ := x.arg(f, "sort").(string); s != "" {
+		core.SortNodes(ns, strings.TrimPrefix(s, "-"), strings.HasPrefix(s, "-"))
+	}
+	if o, ok := x.arg(f, "offset").(int64); ok && o > 0 {
+		if o > int64(len(ns)) {
+			o = int64(len(ns))
+		}
+		ns = ns[o:]
+	}
+	if l, ok := x.arg(f, "limit").(int64); ok && l >= 0 && l < int64(len(ns)) {
+		ns = ns[:l]
+	}
+	return
+}
+
+// read gets a node's configuration or discovered state, if we're allowed to read it
+func (x *gqlExec) read(id string, dsc bool) (lib.Node, error) {
+	n, e := x.r.api.QueryRead(id)
+	if e != nil || n == nil || !x.p.canNode(pb.RestAPIGrant_READ, n) {
+		return nil, e
+	}
+	if dsc {
+		return x.r.api.QueryReadDsc(id)
+	}
+	return n, nil
+}
+
+// node resolves the fields of a Node
+func (x *gqlExec) node(n lib.Node, sel []*gqlSelection) gqlObject {
+	var m map[string]interface{}
+	json.Unmarshal(n.JSON(), &m)
+	obj := gqlObject{}
+	for _, f := range x.fields(sel, "Node") {
+		var v interface{}
+		switch f.name {
+		case "__typename":
+			v = "Node"
+		case "id":
+			v = n.ID().String()
+		case "parentId":
+			if m["parentId"] != nil {
+				v = n.ParentID().String()
+			}
+		case "cfg", "dsc":
+			if !x.selects(nodeType, f) {
+				break
+			}
+			if o, e := x.read(n.ID().String(), f.name == "dsc"); e == nil && o != nil {
+				v = x.node(o, f.sel)
+			}
+		case "mutation":
+			mp, e := x.r.api.QueryNodeMutationPath(n.ID().String())
+			if e != nil {
+				// it isn't mutating
+				v = x.value(mutationPathType, nil, f)
+				break
+			}
+			v = x.value(mutationPathType, gqlJSON(&mp), f)
+		default:
+			v = x.field(nodeType, m, f)
+		}
+		obj = append(obj, gqlPair{f.key(), v})
+	}
+	return obj
+}
+
+// field resolves a field of a message of type t, from its protobuf JSON in m
+func (x *gqlExec) field(t reflect.Type, m map[string]interface{}, f *gqlSelection) interface{} {
+	if f.name == "__typename" {
+		return t.Name()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath == "" && !strings.HasPrefix(sf.Name, "XXX_") && fieldName(sf, true) == f.name {
+			return x.value(sf.Type, m[f.name], f)
+		}
+	}
+	x.fail("cannot query field %q on type %q", f.name, t.Name())
+	return nil
+}
+
+// value resolves v, the protobuf JSON of a value of type t, with f's selection
+func (x *gqlExec) value(t reflect.Type, v interface{}, f *gqlSelection) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		vs, _ := v.([]interface{})
+		l := []interface{}{}
+		if !x.selects(t.Elem(), f) {
+			return nil
+		}
+		for _, e := range vs {
+			l = append(l, x.value(t.Elem(), e, f))
+		}
+		return l
+	}
+	if !x.selects(t, f) {
+		return nil
+	}
+	if t.Kind() == reflect.Struct && t != anyType && t != timestampType {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj := gqlObject{}
+		for _, sf := range x.fields(f.sel, t.Name()) {
+			obj = append(obj, gqlPair{sf.key(), x.field(t, m, sf)})
+		}
+		return obj
+	}
+	if v == nil {
+		return gqlZero(t)
+	}
+	return v
+}
+
+// selects checks that f has a selection if (and only if) t is an object type
+func (x *gqlExec) selects(t reflect.Type, f *gqlSelection) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	object := t.Kind() == reflect.Struct && t != anyType && t != timestampType
+	if object && len(f.sel) == 0 {
+		x.fail("field %q of type %q must have a selection of subfields", f.name, t.Name())
+		return false
+	}
+	if !object && len(f.sel) > 0 {
+		x.fail("field %q must not have a selection since it has no subfields", f.name)
+		return false
+	}
+	return true
+}
+
+// fields flattens fragments out of a selection on type typ, and merges fields with the same response key
+func (x *gqlExec) fields(sel []*gqlSelection, typ string) (fs []*gqlSelection) {
+	seen := make(map[string]*gqlSelection)
+	var walk func([]*gqlSelection, int)
+	walk = func(sel []*gqlSelection, depth int) {
+		if depth > 32 {
+			x.fail("fragments are nested too deeply")
+			return
+		}
+		for _, s := range sel {
+			if !x.included(s) {
+				continue
+			}
+			switch {
+			case s.fragment != "":
+				fr, ok := x.doc.frags[s.fragment]
+				if !ok {
+					x.fail("unknown fragment %q", s.fragment)
+				} else if fr.on == typ {
+					walk(fr.sel, depth+1)
+				}
+			case s.inline:
+				if s.on == "" || s.on == typ {
+					walk(s.sel, depth+1)
+				}
+			default:
+				if f, ok := seen[s.key()]; ok {
+					f.sel = append(f.sel, s.sel...)
+					continue
+				}
+				f := *s
+				f.sel = append([]*gqlSelection{}, s.sel...)
+				seen[s.key()] = &f
+				fs = append(fs, &f)
+			}
+		}
+	}
+	walk(sel, 0)
+	return
+}
+
+// included evaluates @skip and @include
+func (x *gqlExec) included(s *gqlSelection) bool {
+	for _, d := range s.directives {
+		v, _ := x.resolve(d.args["if"]).(bool)
+		if (d.name == "skip" && v) || (d.name == "include" && !v) {
+			return false
+		}
+	}
+	return true
+}
+
+// arg gets an argument of a field, with variables and enums resolved
+func (x *gqlExec) arg(f *gqlSelection, name string) interface{} {
+	return x.resolve(f.args[name])
+}
+
+func (x *gqlExec) resolve(v interface{}) interface{} {
+	switch v := v.(type) {
+	case gqlVar:
+		r := x.vars[string(v)]
+		if n, ok := r.(float64); ok && n == float64(int64(n)) {
+			// JSON variables are float64s
+			return int64(n)
+		}
+		return r
+	case gqlEnum:
+		return string(v)
+	case []interface{}:
+		l := []interface{}{}
+		for _, e := range v {
+			l = append(l, x.resolve(e))
+		}
+		return l
+	}
+	return v
+}
+
+// gqlJSON gets the protobuf JSON of m, as generic values
+func gqlJSON(m proto.Message) (v map[string]interface{}) {
+	b, _ := core.MarshalJSON(m)
+	json.Unmarshal(b, &v)
+	return
+}
+
+// gqlZero is the value of a field protobuf JSON leaves out because it's the default
+func gqlZero(t reflect.Type) interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return ""
+	case reflect.Bool:
+		return false
+	case reflect.Int32:
+		if m := proto.EnumValueMap(t.String()); m != nil {
+			for n, v := range m {
+				if v == 0 {
+					return n
+				}
+			}
+		}
+		return 0
+	case reflect.Int64, reflect.Uint64:
+		return "0"
+	case reflect.Uint32, reflect.Float32, reflect.Float64:
+		return 0
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return ""
+		}
+		return []interface{}{}
+	case reflect.Map:
+		return map[string]interface{}{}
+	}
+	return nil
+}
+
+// A gqlObject is a JSON object that keeps the order of its fields, as GraphQL responses do
+type gqlObject []gqlPair
+
+type gqlPair struct {
+	key string
+	val interface{}
+}
+
+func (o gqlObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, p := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		k, _ := json.Marshal(p.key)
+		v, e := json.Marshal(p.val)
+		if e != nil {
+			return nil, e
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+//////////////
+// Parsing /
+////////////
+
+// A gqlDocument is a parsed GraphQL document
+type gqlDocument struct {
+	ops   []*gqlOperation
+	frags map[string]*gqlFragment
+}
+
+type gqlOperation struct {
+	kind string // query, mutation or subscription
+	name string
+	vars map[string]interface{} // variables' defaults
+	sel  []*gqlSelection
+}
+
+type gqlFragment struct {
+	on  string
+	sel []*gqlSelection
+}
+
+// A gqlSelection is a field, a fragment spread or an inline fragment
+type gqlSelection struct {
+	alias      string
+	name       string
+	args       map[string]interface{}
+	directives []gqlDirective
+	sel        []*gqlSelection
+	fragment   string // the name of a spread fragment
+	inline     bool
+	on         string // the type condition of an inline fragment
+}
+
+// key is the name a field has in the response
+func (s *gqlSelection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type gqlDirective struct {
+	name string
+	args map[string]interface{}
+}
+
+// gqlVar and gqlEnum are variable references and enum values in a document
+type gqlVar string
+type gqlEnum string
+
+type gqlToken struct {
+	kind byte // 'n'ame, 's'tring, '0' (number), 'p'unctuator, or 0 at the end
+	s    string
+}
+
+// gqlLex splits a document into tokens; commas are insignificant, as in GraphQL
+func gqlLex(src string) (ts []gqlToken, e error) {
+	isName := func(c byte, first bool) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "..."):
+			ts = append(ts, gqlToken{'p', "..."})
+			i += 3
+		case strings.IndexByte("{}()[]:!$=@", c) >= 0:
+			ts = append(ts, gqlToken{'p', string(c)})
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			var s string
+			if e = json.Unmarshal([]byte(src[i:j+1]), &s); e != nil {
+				return nil, fmt.Errorf("bad string: %s", src[i:j+1])
+			}
+			ts = append(ts, gqlToken{'s', s})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(src) && strings.IndexByte("0123456789.eE+-", src[j]) >= 0 {
+				j++
+			}
+			ts = append(ts, gqlToken{'0', src[i:j]})
+			i = j
+		case isName(c, true):
+			j := i + 1
+			for j < len(src) && isName(src[j], false) {
+				j++
+			}
+			ts = append(ts, gqlToken{'n', src[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return append(ts, gqlToken{}), nil
+}
+
+// gqlSyntaxError is panicked by the parser, and recovered by gqlParse
+type gqlSyntaxError struct{ error }
+
+type gqlParser struct {
+	ts []gqlToken
+	i  int
+}
+
+// gqlParse parses a GraphQL document
+func gqlParse(src string) (doc *gqlDocument, e error) {
+	ts, e := gqlLex(src)
+	if e != nil {
+		return nil, e
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(gqlSyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, e = nil, se.error
+		}
+	}()
+	p := &gqlParser{ts: ts}
+	doc = &gqlDocument{frags: make(map[string]*gqlFragment)}
+	for p.peek().kind != 0 {
+		switch {
+		case p.is("{"):
+			doc.ops = append(doc.ops, &gqlOperation{kind: "query", vars: map[string]interface{}{}, sel: p.selectionSet()})
+		case p.is("query") || p.is("mutation") || p.is("subscription"):
+			op := &gqlOperation{kind: p.next().s, vars: map[string]interface{}{}}
+			if p.peek().kind == 'n' {
+				op.name = p.next().s
+			}
+			if p.is("(") {
+				p.varDefs(op.vars)
+			}
+			p.directives()
+			op.sel = p.selectionSet()
+			doc.ops = append(doc.ops, op)
+		case p.is("fragment"):
+			p.next()
+			name := p.name()
+			p.expect("on")
+			fr := &gqlFragment{on: p.name()}
+			p.directives()
+			fr.sel = p.selectionSet()
+			doc.frags[name] = fr
+		default:
+			p.fail("unexpected %q", p.peek().s)
+		}
+	}
+	return
+}
+
+func (p *gqlParser) fail(f string, a ...interface{}) {
+	panic(gqlSyntaxError{fmt.Errorf(f, a...)})
+}
+
+func (p *gqlParser) peek() gqlToken { return p.ts[p.i] }
+
+func (p *gqlParser) next() gqlToken {
+	t := p.ts[p.i]
+	if t.kind != 0 {
+		p.i++
+	}
+	return t
+}
+
+// is reports whether the next token is the punctuator or name s
+func (p *gqlParser) is(s string) bool {
+	t := p.peek()
+	return (t.kind == 'p' || t.kind == 'n') && t.s == s
+}
+
+func (p *gqlParser) expect(s string) {
+	if !p.is(s) {
+		if p.peek().kind == 0 {
+			p.fail("expected %q, found the end of the document", s)
+		}
+		p.fail("expected %q, found %q", s, p.peek().s)
+	}
+	p.next()
+}
+
+func (p *gqlParser) name() string {
+	t := p.next()
+	if t.kind != 'n' {
+		p.fail("expected a name, found %q", t.s)
+	}
+	return t.s
+}
+
+// varDefs parses variable definitions, e.g. ($id: String!, $state: State = CFG), and keeps their defaults
+func (p *gqlParser) varDefs(vars map[string]interface{}) {
+	p.expect("(")
+	for !p.is(")") {
+		p.expect("$")
+		name := p.name()
+		p.expect(":")
+		p.typeRef()
+		if p.is("=") {
+			p.next()
+			vars[name] = p.value()
+		}
+		p.directives()
+	}
+	p.next()
+}
+
+// typeRef parses (and ignores) a type, e.g. [String!]!
+func (p *gqlParser) typeRef() {
+	if p.is("[") {
+		p.next()
+		p.typeRef()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+	if p.is("!") {
+		p.next()
+	}
+}
+
+func (p *gqlParser) selectionSet() (sel []*gqlSelection) {
+	p.expect("{")
+	for !p.is("}") {
+		if p.peek().kind == 0 {
+			p.fail("expected \"}\", found the end of the document")
+		}
+		sel = append(sel, p.selection())
+	}
+	p.next()
+	if len(sel) == 0 {
+		p.fail("empty selection set")
+	}
+	return
+}
+
+func (p *gqlParser) selection() *gqlSelection {
+	s := &gqlSelection{}
+	if p.is("...") {
+		p.next()
+		switch {
+		case p.is("on"):
+			p.next()
+			s.inline, s.on = true, p.name()
+		case p.peek().kind == 'n':
+			s.fragment = p.name()
+			s.directives = p.directives()
+			return s
+		default:
+			s.inline = true
+		}
+		s.directives = p.directives()
+		s.sel = p.selectionSet()
+		return s
+	}
+	s.name = p.name()
+	if p.is(":") {
+		p.next()
+		s.alias, s.name = s.name, p.name()
+	}
+	s.args = p.args()
+	s.directives = p.directives()
+	if p.is("{") {
+		s.sel = p.selectionSet()
+	}
+	return s
+}
+
+func (p *gqlParser) args() map[string]interface{} {
+	args := make(map[string]interface{})
+	if !p.is("(") {
+		return args
+	}
+	p.next()
+	for !p.is(")") {
+		name := p.name()
+		p.expect(":")
+		args[name] = p.value()
+	}
+	p.next()
+	return args
+}
+
+func (p *gqlParser) directives() (ds []gqlDirective) {
+	for p.is("@") {
+		p.next()
+		name := p.name()
+		ds = append(ds, gqlDirective{name: name, args: p.args()})
+	}
+	return
+}
+
+func (p *gqlParser) value() interface{} {
+	t := p.next()
+	switch t.kind {
+	case 's':
+		return t.s
+	case '0':
+		if i, e := strconv.ParseInt(t.s, 10, 64); e == nil {
+			return i
+		}
+		f, e := strconv.ParseFloat(t.s, 64)
+		if e != nil {
+			p.fail("bad number: %s", t.s)
+		}
+		return f
+	case 'n':
+		switch t.s {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return gqlEnum(t.s)
+	case 'p':
+		switch t.s {
+		case "$":
+			return gqlVar(p.name())
+		case "[":
+			l := []interface{}{}
+			for !p.is("]") {
+				if p.peek().kind == 0 {
+					p.fail("expected \"]\", found the end of the document")
+				}
+				l = append(l, p.value())
+			}
+			p.next()
+			return l
+		case "{":
+			m := make(map[string]interface{})
+			for !p.is("}") {
+				name := p.name()
+				p.expect(":")
+				m[name] = p.value()
+			}
+			p.next()
+			return m
+		}
+	}
+	p.fail("expected a value, found %q", t.s)
+	return nil
+}
@@ -0,0 +1,134 @@
+/* healthz.go: liveness and readiness probes, for Kubernetes, load balancers, etc.
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// probeTimeout is how long the core gets to answer a probe
+const probeTimeout = 5 * time.Second
+
+// A HealthStatus is how kraken is doing.  Its services are only listed by /readyz.
+type HealthStatus struct {
+	Status   string          `json:"status"`           // ok, or failing
+	Reason   string          `json:"reason,omitempty"` // why it's failing
+	Services []ServiceHealth `json:"services,omitempty"`
+}
+
+// A ServiceHealth is how the process of a service is doing
+type ServiceHealth struct {
+	ID       string `json:"id"`
+	Module   string `json:"module"`
+	Status   string `json:"status"` // STOPPED, STARTING, RUNNING, RESTARTING or CRASHED
+	Pid      int32  `json:"pid,omitempty"`
+	Restarts uint32 `json:"restarts"`
+	LastExit string `json:"lastExit,omitempty"` // how it last exited when it shouldn't have
+	Since    string `json:"since,omitempty"`    // when it got to its status, RFC3339
+}
+
+// probe checks that the core is answering queries, and gets ourself and the status of our services
+func (r *RestAPI) probe() (self lib.Node, ss []*cpb.ServiceStatus, e error) {
+	type result struct {
+		self lib.Node
+		ss   []*cpb.ServiceStatus
+		e    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		if res.self, res.e = r.api.QueryRead(r.api.Self().String()); res.e == nil {
+			res.ss, res.e = r.api.QueryServiceStatus()
+		}
+		done <- res
+	}()
+	select {
+	case res := <-done:
+		return res.self, res.ss, res.e
+	case <-time.After(probeTimeout):
+		return nil, nil, fmt.Errorf("core did not answer in %v", probeTimeout)
+	}
+}
+
+// writeHealth writes h, as 200 if it's ok, or 503 if it isn't
+func writeHealth(w http.ResponseWriter, h *HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if h.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	b, _ := json.Marshal(h)
+	w.Write(b)
+}
+
+// healthz is the liveness probe: the core is up, and answering queries.
+// Services that have crashed don't make us fail it; restarting kraken isn't the way to fix them.
+func (r *RestAPI) healthz(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	h := &HealthStatus{Status: "ok"}
+	if _, _, e := r.probe(); e != nil {
+		h.Status, h.Reason = "failing", e.Error()
+	}
+	writeHealth(w, h)
+}
+
+// readyz is the readiness probe: we're live, and every service that's configured to run is running (i.e. has called in)
+func (r *RestAPI) readyz(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	h := &HealthStatus{Status: "ok"}
+	self, ss, e := r.probe()
+	if e != nil {
+		h.Status, h.Reason = "failing", e.Error()
+		writeHealth(w, h)
+		return
+	}
+	run := make(map[string]bool)
+	for _, s := range self.GetServices() {
+		run[s.ID()] = s.State() == lib.Service_RUN
+	}
+	waiting := []string{}
+	for _, s := range ss {
+		sh := ServiceHealth{
+			ID:       s.Id,
+			Module:   s.Module,
+			Status:   s.Status.String(),
+			Pid:      s.Pid,
+			Restarts: s.Restarts,
+			LastExit: s.LastExit,
+		}
+		if t, e := ptypes.Timestamp(s.Since); e == nil {
+			sh.Since = t.Format(time.RFC3339)
+		}
+		h.Services = append(h.Services, sh)
+		if run[s.Id] && s.Status != cpb.ServiceStatus_RUNNING {
+			waiting = append(waiting, fmt.Sprintf("%s is %s", s.Id, s.Status))
+		}
+		delete(run, s.Id)
+	}
+	// services we haven't even added yet
+	for id, ok := range run {
+		if ok {
+			waiting = append(waiting, fmt.Sprintf("%s is %s", id, cpb.ServiceStatus_STOPPED))
+		}
+	}
+	if len(waiting) > 0 {
+		sort.Strings(waiting)
+		h.Status, h.Reason = "failing", fmt.Sprintf("services that should be running aren't: %v", waiting)
+	}
+	writeHealth(w, h)
+}
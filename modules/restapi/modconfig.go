@@ -0,0 +1,169 @@
+/* modconfig.go: read and change the config of the modules running on this node, without restarting them
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+)
+
+// A ModuleConfig is the config of a module's service on this node
+type ModuleConfig struct {
+	Module  string          `json:"module"`  // the module's full name
+	Service string          `json:"service"` // the ID of the service instance that runs it
+	Config  json.RawMessage `json:"config"`  // the module's config proto, as JSON
+}
+
+// readModuleConfigs lists the config of every module with a config that has a service on this node
+func (r *RestAPI) readModuleConfigs(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	self, e := r.api.QueryRead(r.api.Self().String())
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	mcs := []ModuleConfig{}
+	for _, si := range self.GetServices() {
+		if _, ok := core.Registry.Modules[si.Module()].(lib.ModuleWithConfig); !ok {
+			continue
+		}
+		mc, e := moduleConfig(si)
+		if e != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(e.Error()))
+			return
+		}
+		mcs = append(mcs, mc)
+	}
+	sort.Slice(mcs, func(i, j int) bool { return mcs[i].Service < mcs[j].Service })
+	b, _ := json.Marshal(mcs)
+	w.Write(b)
+}
+
+// readModuleConfig gets a module's config, by module name or service ID
+func (r *RestAPI) readModuleConfig(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	_, si, e := r.moduleService(params["name"])
+	if e != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	mc, e := moduleConfig(si)
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	b, _ := json.Marshal(mc)
+	w.Write(b)
+}
+
+// updateModuleConfig replaces a module's config with the (JSON) config proto in the body; fields that aren't given get their defaults.
+// The config is checked by the module (see lib.ModuleWithConfigValidation), then set on its service, which sends it to the module's UpdateConfig.
+func (r *RestAPI) updateModuleConfig(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	self, si, e := r.moduleService(params["name"])
+	if e != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	m := core.Registry.Modules[si.Module()].(lib.ModuleWithConfig)
+	cfg := m.NewConfig()
+	body, e := ioutil.ReadAll(req.Body)
+	if e == nil {
+		e = core.UnmarshalJSON(body, cfg)
+	}
+	if mv, ok := m.(lib.ModuleWithConfigValidation); ok && e == nil {
+		e = mv.ValidateConfig(cfg)
+	}
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	any, e := ptypes.MarshalAny(cfg)
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	si.UpdateConfig(any)
+	if _, e = r.api.QueryUpdate(self); e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	r.api.Logf(lib.LLINFO, "updated config of %s", si.ID())
+	mc, e := moduleConfig(si)
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	b, _ := json.Marshal(mc)
+	w.Write(b)
+}
+
+// moduleService finds the service on this node that runs a module with a config.
+// name can be the module's full name, the last element of it (e.g. ipmipower), or the service's ID.
+func (r *RestAPI) moduleService(name string) (self lib.Node, si lib.ServiceInstance, e error) {
+	if self, e = r.api.QueryRead(r.api.Self().String()); e != nil {
+		return
+	}
+	for _, s := range self.GetServices() {
+		if s.ID() != name && s.Module() != name && path.Base(s.Module()) != name {
+			continue
+		}
+		if _, ok := core.Registry.Modules[s.Module()].(lib.ModuleWithConfig); !ok {
+			return nil, nil, fmt.Errorf("module has no config: %s", s.Module())
+		}
+		if si != nil {
+			return nil, nil, fmt.Errorf("more than one service runs %s; use the service ID", name)
+		}
+		si = s
+	}
+	if si == nil {
+		return nil, nil, fmt.Errorf("no service on this node runs module: %s", name)
+	}
+	return
+}
+
+// moduleConfig gets the config of a service, or its module's default config if it hasn't been set
+func moduleConfig(si lib.ServiceInstance) (mc ModuleConfig, e error) {
+	mc = ModuleConfig{Module: si.Module(), Service: si.ID()}
+	var cfg proto.Message
+	if any := si.Config(); any != nil {
+		if cfg, e = core.Registry.Resolve(any.GetTypeUrl()); e != nil {
+			return
+		}
+		if e = ptypes.UnmarshalAny(any, cfg); e != nil {
+			return
+		}
+	} else {
+		cfg = core.Registry.Modules[si.Module()].(lib.ModuleWithConfig).NewConfig()
+	}
+	mc.Config, e = core.MarshalJSON(cfg)
+	return
+}
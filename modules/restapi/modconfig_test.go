@@ -0,0 +1,78 @@
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+	_ "github.com/hpc/kraken/modules/capmcpower"
+)
+
+const capmcModule = "github.com/hpc/kraken/modules/capmcpower"
+
+// modconfigAPI is a node that runs capmcpower (as every node does, once it's registered)
+type modconfigAPI struct {
+	rbacAPI
+	self *core.Node
+}
+
+func (a *modconfigAPI) Self() lib.NodeID { return a.self.ID() }
+
+func (a *modconfigAPI) QueryUpdate(n lib.Node) (lib.Node, error) { return n, nil }
+
+func newModconfigAPI() *modconfigAPI {
+	self := core.NewNodeWithID("123e4567-e89b-12d3-a456-426655440000")
+	// the service instance is shared by every node, so start it over
+	self.GetService("capmcpower").UpdateConfig(nil)
+	return &modconfigAPI{
+		rbacAPI: rbacAPI{nodes: map[string]lib.Node{self.ID().String(): self}},
+		self:    self,
+	}
+}
+
+func TestUpdateModuleConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"good config", `{"url": "https://capmc", "pollingInterval": "10s", "batchWindow": "1s", "batchSize": 10}`, http.StatusOK},
+		{"zero polling interval", `{"url": "https://capmc", "pollingInterval": "0s", "batchWindow": "1s", "batchSize": 10}`, http.StatusBadRequest},
+		{"negative polling interval", `{"url": "https://capmc", "pollingInterval": "-10s", "batchWindow": "1s", "batchSize": 10}`, http.StatusBadRequest},
+		{"unparsable polling interval", `{"url": "https://capmc", "pollingInterval": "often", "batchWindow": "1s", "batchSize": 10}`, http.StatusBadRequest},
+		{"bad url", `{"url": "capmc", "pollingInterval": "10s", "batchWindow": "1s", "batchSize": 10}`, http.StatusBadRequest},
+		{"not a config", `{"pollingInterval": `, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newModconfigAPI()
+			r := &RestAPI{api: api}
+			req := httptest.NewRequest("PUT", "/cfg/module/capmcpower", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"name": "capmcpower"})
+			w := httptest.NewRecorder()
+			r.updateModuleConfig(w, req)
+			if w.Code != tt.code {
+				t.Fatalf("expected HTTP %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			cfg := api.self.GetService("capmcpower").Config()
+			if tt.code != http.StatusOK {
+				if cfg != nil {
+					t.Error("a bad config was set on the service")
+				}
+				return
+			}
+			var mc ModuleConfig
+			if e := json.Unmarshal(w.Body.Bytes(), &mc); e != nil {
+				t.Fatal(e)
+			}
+			if cfg == nil || !strings.Contains(string(mc.Config), `"10s"`) {
+				t.Errorf("the config wasn't set on the service: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+/* openapi.go: generates an OpenAPI 3 specification of the ReST API from its routes
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+)
+
+// An apiBody describes a request or response body by an example of it
+type apiBody struct {
+	v      interface{} // a value of the body's type
+	pbjson bool        // it's protobuf JSON (see core.MarshalJSON), rather than encoding/json
+	mime   string      // defaults to application/json
+}
+
+// An apiRoute documents a route
+type apiRoute struct {
+	summary string
+	query   []string // query parameters (see apiParams)
+	body    *apiBody
+	resp    *apiBody
+}
+
+func pbBody(v interface{}) *apiBody   { return &apiBody{v: v, pbjson: true} }
+func jsonBody(v interface{}) *apiBody { return &apiBody{v: v} }
+
+var textBody = &apiBody{v: "", mime: "text/plain"}
+
+// listParams shape node lists (see writeNodes)
+var listParams = []string{"q", "sort", "offset", "limit", "fields"}
+
+// apiParams describes the query parameters routes can take
+var apiParams = map[string]string{
+	"q":             "a selector expression, e.g. /PhysState == POWER_OFF",
+	"sort":          "a URL to sort by, e.g. /Nodename; prefix it with - for descending order",
+	"offset":        "skip this many nodes of the (sorted) list",
+	"limit":         "return at most this many nodes; the length of the whole list is in the X-Total-Count header",
+	"fields":        "a comma separated list of URLs (which may have wildcards); only those values are returned, as a map of node ID to URL to value",
+	"from":          "only events at or after this RFC3339 time",
+	"to":            "only events before this RFC3339 time",
+	"node":          "only for this node (by ID)",
+	"user":          "only by this user",
+	"url":           "only changes to this URL, or below it",
+	"value":         "only changes to this value",
+	"types":         "a comma separated list of the types of change to send, e.g. UPDATE,CFG_UPDATE",
+	"diverged":      "only nodes whose discovered state doesn't match their configuration (true or false)",
+	"query":         "a GraphQL query",
+	"operationName": "the operation to run, if the query has more than one",
+	"variables":     "a JSON object of the query's variables",
+}
+
+// apiRoutes documents each route, by method and path template (as in routeAccess)
+var apiRoutes = map[string]apiRoute{
+	"GET /health":                      {"Check that the ReST API is up", nil, nil, textBody},
+	"GET /healthz":                     {"Liveness probe: check that kraken is up, and answering queries (503 if it isn't)", nil, nil, jsonBody(&HealthStatus{})},
+	"GET /readyz":                      {"Readiness probe: check that kraken is up, and the services it should be running are (503 if they aren't)", nil, nil, jsonBody(&HealthStatus{})},
+	"GET /swagger.json":                {"Get this specification", nil, nil, jsonBody(map[string]interface{}{})},
+	"GET /cfg/nodes":                   {"List nodes' configuration state", listParams, nil, pbBody(&cpb.NodeList{})},
+	"PUT /cfg/nodes":                   {"Update several nodes' configuration state", nil, pbBody(&cpb.NodeList{}), pbBody(&cpb.NodeList{})},
+	"POST /cfg/nodes":                  {"Create several nodes", nil, pbBody(&cpb.NodeList{}), pbBody(&cpb.NodeList{})},
+	"GET /dsc/nodes":                   {"List nodes' discovered state", listParams, nil, pbBody(&cpb.NodeList{})},
+	"PUT /dsc/nodes":                   {"Update several nodes' discovered state", nil, pbBody(&cpb.NodeList{}), pbBody(&cpb.NodeList{})},
+	"GET /cfg/snapshot":                {"Download a snapshot of the configuration state", nil, nil, pbBody(&cpb.NodeList{})},
+	"POST /cfg/restore":                {"Replace the configuration state with a snapshot", nil, pbBody(&cpb.NodeList{}), textBody},
+	"GET /cfg/groups":                  {"List groups, and the IDs of the nodes in them", nil, nil, jsonBody(map[string][]string{})},
+	"GET /cfg/group/{name}/nodes":      {"List the configuration state of the nodes in a group", listParams, nil, pbBody(&cpb.NodeList{})},
+	"PUT /cfg/group/{name}/nodes":      {"Set the values set in a (partial) node on every node in a group", nil, pbBody(&cpb.Node{}), pbBody(&cpb.NodeList{})},
+	"GET /dsc/group/{name}/nodes":      {"List the discovered state of the nodes in a group", listParams, nil, pbBody(&cpb.NodeList{})},
+	"GET /cfg/select":                  {"List the configuration state of the nodes that match a selector", listParams, nil, pbBody(&cpb.NodeList{})},
+	"GET /dsc/select":                  {"List the discovered state of the nodes that match a selector", listParams, nil, pbBody(&cpb.NodeList{})},
+	"POST /cfg/bulk":                   {"Change nodes picked by hostlist, group or selector", nil, jsonBody(&BulkRequest{}), jsonBody(&BulkResponse{})},
+	"GET /cfg/modules":                 {"List the config of each module running on this node", nil, nil, jsonBody([]ModuleConfig{})},
+	"GET /cfg/module/{name:.*}":        {"Get a module's config, by module name or service ID", nil, nil, jsonBody(&ModuleConfig{})},
+	"PUT /cfg/module/{name:.*}":        {"Replace a module's config, without restarting it", nil, jsonBody(map[string]interface{}{}), jsonBody(&ModuleConfig{})},
+	"GET /cfg/node/{id}":               {"Get a node's configuration state", nil, nil, pbBody(&cpb.Node{})},
+	"POST /cfg/node":                   {"Create a node", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"POST /cfg/node/{id}":              {"Create a node", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"PUT /cfg/node":                    {"Update a node's configuration state", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"PUT /cfg/node/{id}":               {"Update a node's configuration state", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"DELETE /cfg/node/{id}":            {"Delete a node", nil, nil, pbBody(&cpb.Node{})},
+	"POST /cfg/node/{id}/decommission": {"Start decommissioning a node", nil, jsonBody(&cpb.DecommissionRequest{}), pbBody(&cpb.Node{})},
+	"GET /dsc/node/{id}":               {"Get a node's discovered state", nil, nil, pbBody(&cpb.Node{})},
+	"PUT /dsc/node":                    {"Update a node's discovered state", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"PUT /dsc/node/{id}":               {"Update a node's discovered state", nil, pbBody(&cpb.Node{}), pbBody(&cpb.Node{})},
+	"POST /dsc/discover":               {"Ask modules to re-discover nodes now, rather than at their next poll", nil, jsonBody(&DiscoverRequest{}), jsonBody(&DiscoverResponse{})},
+	"GET /dsc/rollup":                  {"Summarize the discovered state of the whole cluster, by location", nil, nil, pbBody(&cpb.RollupList{})},
+	"GET /dsc/rollup/{location:.*}":    {"Summarize the discovered state of a location, and the locations below it", nil, nil, pbBody(&cpb.RollupList{})},
+	"GET /graph/json":                  {"Get the mutation graph", nil, nil, jsonBody(&GraphJson{})},
+	"GET /graph/dot":                   {"Get the mutation graph in graphviz format", nil, nil, &apiBody{v: "", mime: "text/vnd.graphviz"}},
+	"GET /graph/node/{id}/json":        {"Get the mutation graph for a node", nil, nil, jsonBody(&GraphJson{})},
+	"GET /graph/node/{id}/dot":         {"Get the mutation graph for a node in graphviz format", nil, nil, &apiBody{v: "", mime: "text/vnd.graphviz"}},
+	"GET /graph/node/{id}/plan":        {"Get the mutations that would get a node to its configuration state", nil, nil, jsonBody(&cpb.MutationPath{})},
+	"GET /graph/node/{id}/status":      {"Get where a node is in the mutation graph, and why (e.g. why it's stuck)", nil, nil, jsonBody(&NodeStatus{})},
+	"POST /graph/plan":                 {"Get the mutations that would get a node to a (posted) configuration; nothing is changed", nil, pbBody(&cpb.Node{}), jsonBody(&cpb.MutationPath{})},
+	"GET /graph/metrics":               {"Get mutation metrics", nil, nil, pbBody(&cpb.MutationMetrics{})},
+	"GET /graph/convergence":           {"Get a convergence report: whether each node's discovered state matches its configuration, how it doesn't, and for how long", []string{"diverged"}, nil, jsonBody(&ConvergenceReport{})},
+	"GET /log/events":                  {"Read the event log", []string{"from", "to"}, nil, pbBody(&cpb.EventRecordList{})},
+	"GET /log/node/{id}/events":        {"Read the event log for a node", []string{"from", "to"}, nil, pbBody(&cpb.EventRecordList{})},
+	"GET /log/audit":                   {"Read the audit log of who changed what through the APIs", []string{"node", "user", "from", "to"}, nil, pbBody(&cpb.AuditRecordList{})},
+	"GET /watch":                       {"Stream state changes as server-sent events", []string{"node", "url", "value", "types"}, nil, &apiBody{v: "", mime: "text/event-stream"}},
+	"GET /graphql":                     {"Run a GraphQL query (if it's enabled)", []string{"query", "operationName", "variables"}, nil, jsonBody(map[string]interface{}{})},
+	"POST /graphql":                    {"Run a GraphQL query (if it's enabled)", nil, jsonBody(&graphQLRequest{}), jsonBody(map[string]interface{}{})},
+}
+
+// pathVar matches route variables, which can have a pattern, e.g. {location:.*}
+var pathVar = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// openAPI generates the specification from our routes, for the API at base
+func (r *RestAPI) openAPI(base string) map[string]interface{} {
+	g := &schemaGen{comps: make(map[string]interface{})}
+	paths := make(map[string]map[string]interface{})
+	r.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		full, e := route.GetPathTemplate()
+		if e != nil || !strings.HasPrefix(full, apiV1+"/") {
+			// the unversioned paths are deprecated aliases, and aren't documented
+			return nil
+		}
+		t := versionTemplate(full)
+		ms, _ := route.GetMethods()
+		path := pathVar.ReplaceAllString(t, "{$1}")
+		for _, m := range ms {
+			doc, ok := apiRoutes[m+" "+t]
+			if !ok {
+				doc = apiRoute{summary: "undocumented"}
+			}
+			op := map[string]interface{}{
+				"summary":   doc.summary,
+				"responses": map[string]interface{}{"200": g.response(doc.resp)},
+			}
+			params := []interface{}{}
+			for _, v := range pathVar.FindAllStringSubmatch(t, -1) {
+				params = append(params, map[string]interface{}{
+					"name": v[1], "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"},
+				})
+			}
+			for _, q := range doc.query {
+				params = append(params, map[string]interface{}{
+					"name": q, "in": "query", "description": apiParams[q], "schema": map[string]interface{}{"type": "string"},
+				})
+			}
+			if len(params) > 0 {
+				op["parameters"] = params
+			}
+			if doc.body != nil {
+				op["requestBody"] = map[string]interface{}{"content": g.content(doc.body)}
+			}
+			if m == "GET" && unauthenticated[full] {
+				op["security"] = []interface{}{}
+			}
+			if paths[path] == nil {
+				paths[path] = make(map[string]interface{})
+			}
+			paths[path][strings.ToLower(m)] = op
+		}
+		return nil
+	})
+	// module configs go in services' configs
+	for _, m := range core.Registry.Modules {
+		if mc, ok := m.(lib.ModuleWithConfig); ok {
+			g.schema(reflect.TypeOf(mc.NewConfig()), true)
+		}
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "Kraken ReST API",
+			"version": "1",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": base + apiV1}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": g.comps,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearer": []string{}}},
+	}
+}
+
+// readOpenAPI serves the specification
+func (r *RestAPI) readOpenAPI(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	b, _ := json.MarshalIndent(r.openAPI(forwardedBase(req)), "", "  ")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// A schemaGen makes JSON schemas for Go types, collecting the schemas of named structs as components
+type schemaGen struct {
+	comps map[string]interface{}
+}
+
+func (g *schemaGen) response(b *apiBody) map[string]interface{} {
+	r := map[string]interface{}{"description": "OK"}
+	if b != nil {
+		r["content"] = g.content(b)
+	}
+	return r
+}
+
+func (g *schemaGen) content(b *apiBody) map[string]interface{} {
+	mime := b.mime
+	if mime == "" {
+		mime = "application/json"
+	}
+	return map[string]interface{}{mime: map[string]interface{}{"schema": g.schema(reflect.TypeOf(b.v), b.pbjson)}}
+}
+
+var (
+	anyType       = reflect.TypeOf(any.Any{})
+	timestampType = reflect.TypeOf(timestamp.Timestamp{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema gets the schema for t, as protobuf JSON if pbjson is set
+func (g *schemaGen) schema(t reflect.Type, pbjson bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		switch {
+		case t == anyType:
+			return map[string]interface{}{
+				"type":                 "object",
+				"description":          "a protobuf Any: the message's @type URL, and its fields (e.g. a module config in components)",
+				"properties":           map[string]interface{}{"@type": map[string]interface{}{"type": "string"}},
+				"additionalProperties": true,
+			}
+		case t == timestampType && pbjson:
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		case t.Name() == "":
+			return g.object(t, pbjson)
+		}
+		if _, ok := g.comps[t.Name()]; !ok {
+			g.comps[t.Name()] = nil // we might refer to ourselves
+			g.comps[t.Name()] = g.object(t, pbjson)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice:
+		if t == rawJSONType {
+			return map[string]interface{}{"description": "JSON, e.g. a node as protobuf JSON"}
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem(), pbjson)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem(), pbjson)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Int32:
+		if m := proto.EnumValueMap(t.String()); m != nil && pbjson {
+			// protobuf JSON has enums by name
+			names := []string{}
+			for n := range m {
+				names = append(names, n)
+			}
+			sort.Slice(names, func(i, j int) bool { return m[names[i]] < m[names[j]] })
+			return map[string]interface{}{"type": "string", "enum": names}
+		}
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		if pbjson {
+			// protobuf JSON has 64 bit integers as strings
+			return map[string]interface{}{"type": "string", "format": "int64"}
+		}
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	}
+	return map[string]interface{}{}
+}
+
+// object gets the schema of a struct's fields
+func (g *schemaGen) object(t reflect.Type, pbjson bool) map[string]interface{} {
+	props := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+			continue
+		}
+		if name := fieldName(f, pbjson); name != "" {
+			props[name] = g.schema(f.Type, pbjson)
+		}
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// fieldName gets the name a field has in JSON; protobuf JSON uses the json= name from the protobuf tag
+func fieldName(f reflect.StructField, pbjson bool) string {
+	if tag := f.Tag.Get("protobuf"); pbjson && tag != "" {
+		name := ""
+		for _, p := range strings.Split(tag, ",") {
+			if strings.HasPrefix(p, "name=") && name == "" {
+				name = strings.TrimPrefix(p, "name=")
+			}
+			if strings.HasPrefix(p, "json=") {
+				name = strings.TrimPrefix(p, "json=")
+			}
+		}
+		return name
+	}
+	tag := strings.Split(f.Tag.Get("json"), ",")[0]
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return tag
+}
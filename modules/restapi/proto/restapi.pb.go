@@ -18,19 +18,51 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type RestAPIGrant_Role int32
+
+const (
+	RestAPIGrant_READ   RestAPIGrant_Role = 0
+	RestAPIGrant_POWER  RestAPIGrant_Role = 1
+	RestAPIGrant_CONFIG RestAPIGrant_Role = 2
+)
+
+var RestAPIGrant_Role_name = map[int32]string{
+	0: "READ",
+	1: "POWER",
+	2: "CONFIG",
+}
+var RestAPIGrant_Role_value = map[string]int32{
+	"READ":   0,
+	"POWER":  1,
+	"CONFIG": 2,
+}
+
+func (x RestAPIGrant_Role) String() string {
+	return proto.EnumName(RestAPIGrant_Role_name, int32(x))
+}
+func (RestAPIGrant_Role) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{5, 0}
+}
+
 type RestAPIConfig struct {
-	Addr                 string   `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
-	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Addr                 string        `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Port                 int32         `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Auth                 *RestAPIAuth  `protobuf:"bytes,3,opt,name=auth,proto3" json:"auth,omitempty"`
+	Tls                  *RestAPITLS   `protobuf:"bytes,4,opt,name=tls,proto3" json:"tls,omitempty"`
+	Graphql              bool          `protobuf:"varint,5,opt,name=graphql,proto3" json:"graphql,omitempty"`
+	BasePath             string        `protobuf:"bytes,6,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
+	CorsOrigins          []string      `protobuf:"bytes,7,rep,name=cors_origins,json=corsOrigins,proto3" json:"cors_origins,omitempty"`
+	Proxy                *RestAPIProxy `protobuf:"bytes,8,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *RestAPIConfig) Reset()         { *m = RestAPIConfig{} }
 func (m *RestAPIConfig) String() string { return proto.CompactTextString(m) }
 func (*RestAPIConfig) ProtoMessage()    {}
 func (*RestAPIConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_restapi_904346250fab119c, []int{0}
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{0}
 }
 func (m *RestAPIConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RestAPIConfig.Unmarshal(m, b)
@@ -64,18 +96,435 @@ func (m *RestAPIConfig) GetPort() int32 {
 	return 0
 }
 
+func (m *RestAPIConfig) GetAuth() *RestAPIAuth {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *RestAPIConfig) GetTls() *RestAPITLS {
+	if m != nil {
+		return m.Tls
+	}
+	return nil
+}
+
+func (m *RestAPIConfig) GetGraphql() bool {
+	if m != nil {
+		return m.Graphql
+	}
+	return false
+}
+
+func (m *RestAPIConfig) GetBasePath() string {
+	if m != nil {
+		return m.BasePath
+	}
+	return ""
+}
+
+func (m *RestAPIConfig) GetCorsOrigins() []string {
+	if m != nil {
+		return m.CorsOrigins
+	}
+	return nil
+}
+
+func (m *RestAPIConfig) GetProxy() *RestAPIProxy {
+	if m != nil {
+		return m.Proxy
+	}
+	return nil
+}
+
+// A RestAPIProxy says which reverse proxies in front of the ReST API to trust.
+// Requests from them have their X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix headers believed.
+type RestAPIProxy struct {
+	Trusted              []string `protobuf:"bytes,1,rep,name=trusted,proto3" json:"trusted,omitempty"`
+	UserHeader           string   `protobuf:"bytes,2,opt,name=user_header,json=userHeader,proto3" json:"user_header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestAPIProxy) Reset()         { *m = RestAPIProxy{} }
+func (m *RestAPIProxy) String() string { return proto.CompactTextString(m) }
+func (*RestAPIProxy) ProtoMessage()    {}
+func (*RestAPIProxy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{1}
+}
+func (m *RestAPIProxy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPIProxy.Unmarshal(m, b)
+}
+func (m *RestAPIProxy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPIProxy.Marshal(b, m, deterministic)
+}
+func (dst *RestAPIProxy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPIProxy.Merge(dst, src)
+}
+func (m *RestAPIProxy) XXX_Size() int {
+	return xxx_messageInfo_RestAPIProxy.Size(m)
+}
+func (m *RestAPIProxy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPIProxy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPIProxy proto.InternalMessageInfo
+
+func (m *RestAPIProxy) GetTrusted() []string {
+	if m != nil {
+		return m.Trusted
+	}
+	return nil
+}
+
+func (m *RestAPIProxy) GetUserHeader() string {
+	if m != nil {
+		return m.UserHeader
+	}
+	return ""
+}
+
+// If a cert and key are set, the ReST API is served over HTTPS
+type RestAPITLS struct {
+	Cert                 string   `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ClientCa             string   `protobuf:"bytes,3,opt,name=client_ca,json=clientCa,proto3" json:"client_ca,omitempty"`
+	RequireClientCert    bool     `protobuf:"varint,4,opt,name=require_client_cert,json=requireClientCert,proto3" json:"require_client_cert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestAPITLS) Reset()         { *m = RestAPITLS{} }
+func (m *RestAPITLS) String() string { return proto.CompactTextString(m) }
+func (*RestAPITLS) ProtoMessage()    {}
+func (*RestAPITLS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{2}
+}
+func (m *RestAPITLS) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPITLS.Unmarshal(m, b)
+}
+func (m *RestAPITLS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPITLS.Marshal(b, m, deterministic)
+}
+func (dst *RestAPITLS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPITLS.Merge(dst, src)
+}
+func (m *RestAPITLS) XXX_Size() int {
+	return xxx_messageInfo_RestAPITLS.Size(m)
+}
+func (m *RestAPITLS) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPITLS.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPITLS proto.InternalMessageInfo
+
+func (m *RestAPITLS) GetCert() string {
+	if m != nil {
+		return m.Cert
+	}
+	return ""
+}
+
+func (m *RestAPITLS) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *RestAPITLS) GetClientCa() string {
+	if m != nil {
+		return m.ClientCa
+	}
+	return ""
+}
+
+func (m *RestAPITLS) GetRequireClientCert() bool {
+	if m != nil {
+		return m.RequireClientCert
+	}
+	return false
+}
+
+// Requests must authenticate with an "Authorization: Bearer <token>" header, where the token is one of tokens, a user's token, or a JWT that jwt validates.
+// Unauthenticated requests are rejected, except for health checks (/health).
+type RestAPIAuth struct {
+	Disabled             bool           `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	Tokens               []string       `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	Jwt                  *RestAPIJWT    `protobuf:"bytes,3,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	Users                []*RestAPIUser `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RestAPIAuth) Reset()         { *m = RestAPIAuth{} }
+func (m *RestAPIAuth) String() string { return proto.CompactTextString(m) }
+func (*RestAPIAuth) ProtoMessage()    {}
+func (*RestAPIAuth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{3}
+}
+func (m *RestAPIAuth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPIAuth.Unmarshal(m, b)
+}
+func (m *RestAPIAuth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPIAuth.Marshal(b, m, deterministic)
+}
+func (dst *RestAPIAuth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPIAuth.Merge(dst, src)
+}
+func (m *RestAPIAuth) XXX_Size() int {
+	return xxx_messageInfo_RestAPIAuth.Size(m)
+}
+func (m *RestAPIAuth) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPIAuth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPIAuth proto.InternalMessageInfo
+
+func (m *RestAPIAuth) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *RestAPIAuth) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *RestAPIAuth) GetJwt() *RestAPIJWT {
+	if m != nil {
+		return m.Jwt
+	}
+	return nil
+}
+
+func (m *RestAPIAuth) GetUsers() []*RestAPIUser {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+// A RestAPIUser is granted roles, and authenticates with their token, or a JWT
+type RestAPIUser struct {
+	Name                 string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Token                string          `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	Grants               []*RestAPIGrant `protobuf:"bytes,3,rep,name=grants,proto3" json:"grants,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RestAPIUser) Reset()         { *m = RestAPIUser{} }
+func (m *RestAPIUser) String() string { return proto.CompactTextString(m) }
+func (*RestAPIUser) ProtoMessage()    {}
+func (*RestAPIUser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{4}
+}
+func (m *RestAPIUser) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPIUser.Unmarshal(m, b)
+}
+func (m *RestAPIUser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPIUser.Marshal(b, m, deterministic)
+}
+func (dst *RestAPIUser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPIUser.Merge(dst, src)
+}
+func (m *RestAPIUser) XXX_Size() int {
+	return xxx_messageInfo_RestAPIUser.Size(m)
+}
+func (m *RestAPIUser) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPIUser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPIUser proto.InternalMessageInfo
+
+func (m *RestAPIUser) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestAPIUser) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *RestAPIUser) GetGrants() []*RestAPIGrant {
+	if m != nil {
+		return m.Grants
+	}
+	return nil
+}
+
+// A RestAPIGrant gives a role, for every node or just those in a group
+type RestAPIGrant struct {
+	Role                 RestAPIGrant_Role `protobuf:"varint,1,opt,name=role,proto3,enum=proto.RestAPIGrant_Role" json:"role,omitempty"`
+	Group                string            `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RestAPIGrant) Reset()         { *m = RestAPIGrant{} }
+func (m *RestAPIGrant) String() string { return proto.CompactTextString(m) }
+func (*RestAPIGrant) ProtoMessage()    {}
+func (*RestAPIGrant) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{5}
+}
+func (m *RestAPIGrant) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPIGrant.Unmarshal(m, b)
+}
+func (m *RestAPIGrant) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPIGrant.Marshal(b, m, deterministic)
+}
+func (dst *RestAPIGrant) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPIGrant.Merge(dst, src)
+}
+func (m *RestAPIGrant) XXX_Size() int {
+	return xxx_messageInfo_RestAPIGrant.Size(m)
+}
+func (m *RestAPIGrant) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPIGrant.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPIGrant proto.InternalMessageInfo
+
+func (m *RestAPIGrant) GetRole() RestAPIGrant_Role {
+	if m != nil {
+		return m.Role
+	}
+	return RestAPIGrant_READ
+}
+
+func (m *RestAPIGrant) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type RestAPIJWT struct {
+	Issuer               string   `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Audience             string   `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"`
+	Secrets              []string `protobuf:"bytes,3,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	PublicKeys           []string `protobuf:"bytes,4,rep,name=public_keys,json=publicKeys,proto3" json:"public_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestAPIJWT) Reset()         { *m = RestAPIJWT{} }
+func (m *RestAPIJWT) String() string { return proto.CompactTextString(m) }
+func (*RestAPIJWT) ProtoMessage()    {}
+func (*RestAPIJWT) Descriptor() ([]byte, []int) {
+	return fileDescriptor_restapi_10a2ab57536882dc, []int{6}
+}
+func (m *RestAPIJWT) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestAPIJWT.Unmarshal(m, b)
+}
+func (m *RestAPIJWT) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestAPIJWT.Marshal(b, m, deterministic)
+}
+func (dst *RestAPIJWT) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestAPIJWT.Merge(dst, src)
+}
+func (m *RestAPIJWT) XXX_Size() int {
+	return xxx_messageInfo_RestAPIJWT.Size(m)
+}
+func (m *RestAPIJWT) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestAPIJWT.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestAPIJWT proto.InternalMessageInfo
+
+func (m *RestAPIJWT) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *RestAPIJWT) GetAudience() string {
+	if m != nil {
+		return m.Audience
+	}
+	return ""
+}
+
+func (m *RestAPIJWT) GetSecrets() []string {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func (m *RestAPIJWT) GetPublicKeys() []string {
+	if m != nil {
+		return m.PublicKeys
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RestAPIConfig)(nil), "proto.RestAPIConfig")
+	proto.RegisterType((*RestAPIProxy)(nil), "proto.RestAPIProxy")
+	proto.RegisterType((*RestAPITLS)(nil), "proto.RestAPITLS")
+	proto.RegisterType((*RestAPIAuth)(nil), "proto.RestAPIAuth")
+	proto.RegisterType((*RestAPIUser)(nil), "proto.RestAPIUser")
+	proto.RegisterType((*RestAPIGrant)(nil), "proto.RestAPIGrant")
+	proto.RegisterType((*RestAPIJWT)(nil), "proto.RestAPIJWT")
+	proto.RegisterEnum("proto.RestAPIGrant_Role", RestAPIGrant_Role_name, RestAPIGrant_Role_value)
 }
 
-func init() { proto.RegisterFile("restapi.proto", fileDescriptor_restapi_904346250fab119c) }
+func init() { proto.RegisterFile("restapi.proto", fileDescriptor_restapi_10a2ab57536882dc) }
 
-var fileDescriptor_restapi_904346250fab119c = []byte{
-	// 96 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x4a, 0x2d, 0x2e,
-	0x49, 0x2c, 0xc8, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53, 0x4a, 0xe6, 0x5c,
-	0xbc, 0x41, 0xa9, 0xc5, 0x25, 0x8e, 0x01, 0x9e, 0xce, 0xf9, 0x79, 0x69, 0x99, 0xe9, 0x42, 0x42,
-	0x5c, 0x2c, 0x89, 0x29, 0x29, 0x45, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x60, 0x36, 0x48,
-	0xac, 0x20, 0xbf, 0xa8, 0x44, 0x82, 0x49, 0x81, 0x51, 0x83, 0x35, 0x08, 0xcc, 0x4e, 0x62, 0x03,
-	0xeb, 0x37, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0x50, 0x94, 0x34, 0x66, 0x57, 0x00, 0x00, 0x00,
+var fileDescriptor_restapi_10a2ab57536882dc = []byte{
+	// 559 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x41, 0x6f, 0xd3, 0x4c,
+	0x10, 0xfd, 0x1c, 0x3b, 0x69, 0x3c, 0x69, 0x3f, 0xa5, 0x5b, 0x84, 0x56, 0x70, 0xc0, 0x18, 0x09,
+	0x8c, 0x40, 0x39, 0x94, 0x5f, 0x50, 0x85, 0x52, 0x5a, 0x10, 0x8d, 0x96, 0xa2, 0x1e, 0xad, 0x8d,
+	0x3d, 0xc4, 0xa6, 0xc6, 0x76, 0x77, 0xd7, 0x82, 0xa8, 0x07, 0xce, 0xdc, 0xf8, 0xc9, 0x68, 0xd6,
+	0x76, 0x48, 0xd5, 0x9e, 0x3c, 0xef, 0xcd, 0xdb, 0xd9, 0x99, 0x37, 0x6b, 0xd8, 0x53, 0xa8, 0x8d,
+	0xac, 0xf3, 0x59, 0xad, 0x2a, 0x53, 0xb1, 0xa1, 0xfd, 0x84, 0xbf, 0x07, 0xb0, 0x27, 0x50, 0x9b,
+	0xa3, 0xc5, 0xe9, 0xbc, 0x2a, 0xbf, 0xe6, 0x2b, 0xc6, 0xc0, 0x93, 0x69, 0xaa, 0xb8, 0x13, 0x38,
+	0x91, 0x2f, 0x6c, 0x4c, 0x5c, 0x5d, 0x29, 0xc3, 0x07, 0x81, 0x13, 0x0d, 0x85, 0x8d, 0xd9, 0x73,
+	0xf0, 0x64, 0x63, 0x32, 0xee, 0x06, 0x4e, 0x34, 0x39, 0x64, 0x6d, 0xd9, 0x59, 0x57, 0xeb, 0xa8,
+	0x31, 0x99, 0xb0, 0x79, 0xf6, 0x0c, 0x5c, 0x53, 0x68, 0xee, 0x59, 0xd9, 0xfe, 0x6d, 0xd9, 0xc5,
+	0xc7, 0xcf, 0x82, 0xb2, 0x8c, 0xc3, 0xce, 0x4a, 0xc9, 0x3a, 0xbb, 0x2e, 0xf8, 0x30, 0x70, 0xa2,
+	0xb1, 0xe8, 0x21, 0x7b, 0x0c, 0xfe, 0x52, 0x6a, 0x8c, 0x6b, 0x69, 0x32, 0x3e, 0xb2, 0x3d, 0x8d,
+	0x89, 0x58, 0x48, 0x93, 0xb1, 0xa7, 0xb0, 0x9b, 0x54, 0x4a, 0xc7, 0x95, 0xca, 0x57, 0x79, 0xa9,
+	0xf9, 0x4e, 0xe0, 0x46, 0xbe, 0x98, 0x10, 0x77, 0xde, 0x52, 0xec, 0x25, 0xd0, 0xa4, 0x3f, 0xd7,
+	0x7c, 0x6c, 0x1b, 0x38, 0xb8, 0xdd, 0xc0, 0x82, 0x52, 0xa2, 0x55, 0x84, 0xa7, 0xb0, 0xbb, 0x4d,
+	0x53, 0x53, 0x46, 0x35, 0xda, 0x60, 0xca, 0x1d, 0x5b, 0xb8, 0x87, 0xec, 0x09, 0x4c, 0x1a, 0x8d,
+	0x2a, 0xce, 0x50, 0xa6, 0xa8, 0xac, 0x2d, 0xbe, 0x00, 0xa2, 0xde, 0x5b, 0x26, 0xfc, 0x05, 0xf0,
+	0x6f, 0x44, 0xb2, 0x2f, 0x41, 0x65, 0x7a, 0x4b, 0x29, 0x66, 0x53, 0x70, 0xaf, 0x70, 0xdd, 0x1d,
+	0xa5, 0x90, 0x26, 0x4d, 0x8a, 0x1c, 0x4b, 0x13, 0x27, 0xd2, 0xba, 0xea, 0x8b, 0x71, 0x4b, 0xcc,
+	0x25, 0x9b, 0xc1, 0x81, 0xc2, 0xeb, 0x26, 0x57, 0x18, 0xf7, 0x22, 0xaa, 0xe8, 0x59, 0xb3, 0xf6,
+	0xbb, 0xd4, 0xbc, 0x55, 0xa3, 0x32, 0xe1, 0x1f, 0x07, 0x26, 0x5b, 0xbb, 0x60, 0x8f, 0x60, 0x9c,
+	0xe6, 0x5a, 0x2e, 0x0b, 0x3b, 0x0c, 0x1d, 0xda, 0x60, 0xf6, 0x10, 0x46, 0xa6, 0xba, 0xc2, 0x52,
+	0xf3, 0x81, 0x1d, 0xb3, 0x43, 0xb4, 0xb9, 0x6f, 0x3f, 0x0c, 0x77, 0xef, 0xdb, 0xdc, 0xd9, 0xe5,
+	0x85, 0xa0, 0x2c, 0x8b, 0x60, 0x48, 0x73, 0xd3, 0x82, 0xdd, 0xbb, 0xef, 0xe0, 0x8b, 0x46, 0x25,
+	0x5a, 0x41, 0x98, 0xc2, 0x64, 0x8b, 0x25, 0x53, 0x4a, 0xf9, 0x1d, 0x7b, 0x53, 0x28, 0x66, 0x0f,
+	0x60, 0x68, 0xef, 0xee, 0x6c, 0x69, 0x01, 0x7b, 0x05, 0xa3, 0x95, 0x92, 0xa5, 0xd1, 0xdc, 0x0d,
+	0xdc, 0xbb, 0x3b, 0x3c, 0xa1, 0x9c, 0xe8, 0x24, 0xe1, 0x0d, 0xec, 0x6e, 0xf3, 0xec, 0x35, 0x78,
+	0xaa, 0x2a, 0xda, 0x6b, 0xfe, 0x3f, 0xe4, 0xf7, 0x1c, 0x9d, 0x89, 0xaa, 0x40, 0x61, 0x55, 0xd4,
+	0xc0, 0x4a, 0x55, 0x4d, 0xdd, 0x37, 0x60, 0x41, 0xf8, 0x02, 0x3c, 0xd2, 0xb0, 0x31, 0x78, 0xe2,
+	0xf8, 0xe8, 0xed, 0xf4, 0x3f, 0xe6, 0xc3, 0x70, 0x71, 0x7e, 0x79, 0x2c, 0xa6, 0x0e, 0x03, 0x18,
+	0xcd, 0xcf, 0x3f, 0xbd, 0x3b, 0x3d, 0x99, 0x0e, 0xc2, 0x9b, 0xcd, 0xda, 0xcf, 0x2e, 0x2f, 0xc8,
+	0xd7, 0x5c, 0xeb, 0x06, 0xfb, 0x7f, 0xa9, 0x43, 0xb4, 0x0b, 0xd9, 0xa4, 0x39, 0x96, 0x09, 0x76,
+	0xf7, 0x6c, 0x30, 0xbd, 0x39, 0x8d, 0x89, 0xc2, 0x6e, 0x58, 0x5f, 0xf4, 0x90, 0xde, 0x5c, 0xdd,
+	0x2c, 0x8b, 0x3c, 0x89, 0xaf, 0x70, 0xdd, 0xda, 0xed, 0x0b, 0x68, 0xa9, 0x0f, 0xb8, 0xd6, 0xcb,
+	0x91, 0x1d, 0xed, 0xcd, 0xdf, 0x01, 0x00, 0xdc, 0x94, 0xc0, 0x8a, 0xe9, 0x03, 0x00, 0x00,
 }
@@ -0,0 +1,125 @@
+/* proxy.go: lets the ReST API be served from behind a reverse proxy, e.g. at a base path, or with the proxy doing authentication
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+// A forwarding is how a request got to us, as far as we can tell
+type forwarding struct {
+	base string // the path the API is at, as the client sees it, e.g. /kraken
+	user string // who a trusted proxy authenticated the request as, if it does authentication
+}
+
+type forwardingKey struct{}
+
+// forwardedBase gets the path the API is at for the client that made req
+func forwardedBase(req *http.Request) string {
+	f, _ := req.Context().Value(forwardingKey{}).(*forwarding)
+	if f == nil {
+		return ""
+	}
+	return f.base
+}
+
+// basePath cleans up a base path, e.g. kraken/ becomes /kraken; the root is ""
+func basePath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// trustedNets parses the addresses and CIDRs of trusted proxies
+func trustedNets(pc *pb.RestAPIProxy) (nets []*net.IPNet, e error) {
+	for _, t := range pc.GetTrusted() {
+		if !strings.Contains(t, "/") {
+			ip := net.ParseIP(t)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy address: %s", t)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy CIDR: %s", t)
+		}
+		nets = append(nets, n)
+	}
+	return
+}
+
+// trusted tells if the address (with or without a port) is one of nets
+func trusted(nets []*net.IPNet, addr string) bool {
+	if h, _, e := net.SplitHostPort(addr); e == nil {
+		addr = h
+	}
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyHandler serves h at base, and believes the X-Forwarded-* headers of requests from trusted proxies.
+// The client is the last address in X-Forwarded-For that isn't a trusted proxy, so clients can't pretend to be someone else.
+// Requests outside of base aren't found.
+func proxyHandler(base string, pc *pb.RestAPIProxy, nets []*net.IPNet, h http.Handler) http.Handler {
+	if base != "" {
+		h = http.StripPrefix(base, h)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		f := &forwarding{base: base}
+		if trusted(nets, req.RemoteAddr) {
+			if xff := req.Header.Get("X-Forwarded-For"); xff != "" {
+				addrs := strings.Split(xff, ",")
+				client := ""
+				for i := len(addrs) - 1; i >= 0; i-- {
+					client = strings.TrimSpace(addrs[i])
+					if !trusted(nets, client) {
+						break
+					}
+				}
+				if client != "" {
+					req.RemoteAddr = client
+				}
+			}
+			if p := req.Header.Get("X-Forwarded-Proto"); p == "http" || p == "https" {
+				req.URL.Scheme = p
+			}
+			if host := req.Header.Get("X-Forwarded-Host"); host != "" {
+				req.Host = host
+			}
+			f.base = basePath(req.Header.Get("X-Forwarded-Prefix")) + base
+			if uh := pc.GetUserHeader(); uh != "" {
+				f.user = req.Header.Get(uh)
+			}
+		}
+		h.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), forwardingKey{}, f)))
+	})
+}
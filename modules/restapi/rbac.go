@@ -0,0 +1,250 @@
+/* rbac.go: limits what authenticated users can do with the ReST API to the roles they've been granted
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+// powerURLs are the URLs the POWER role can change
+var powerURLs = map[string]bool{
+	"/PhysState": true,
+}
+
+// A scope is what a request is for, which decides which grants can allow it
+type scope int
+
+const (
+	scopeCluster scope = iota // everything; needs a grant for every node
+	scopeNode                 // the node in the {id} route variable
+	scopeGroup                // the group in the {name} route variable
+	scopeBody                 // the node(s) in the request body
+	scopeHandler              // the handler checks each node itself
+)
+
+// An access is what a route needs.  Changes that could be power operations need POWER if they are, or CONFIG if they aren't.
+type access struct {
+	role  pb.RestAPIGrant_Role
+	scope scope
+	power bool
+}
+
+// routeAccess is what each route (by method and path template) needs.  Routes that aren't listed need cluster wide CONFIG.
+var routeAccess = map[string]access{
+	"GET /health":                      {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /healthz":                     {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /readyz":                      {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /swagger.json":                {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /cfg/nodes":                   {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /dsc/nodes":                   {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /cfg/snapshot":                {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /cfg/groups":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /cfg/select":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /dsc/select":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /dsc/rollup":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /dsc/rollup/{location:.*}":    {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /graph/json":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /graph/dot":                   {pb.RestAPIGrant_READ, scopeCluster, false},
+	"POST /graph/plan":                 {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /graph/metrics":               {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /graph/convergence":           {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /log/events":                  {pb.RestAPIGrant_READ, scopeCluster, false},
+	"GET /cfg/group/{name}/nodes":      {pb.RestAPIGrant_READ, scopeGroup, false},
+	"GET /dsc/group/{name}/nodes":      {pb.RestAPIGrant_READ, scopeGroup, false},
+	"GET /cfg/node/{id}":               {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /dsc/node/{id}":               {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /graph/node/{id}/json":        {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /graph/node/{id}/dot":         {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /graph/node/{id}/plan":        {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /graph/node/{id}/status":      {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /log/node/{id}/events":        {pb.RestAPIGrant_READ, scopeNode, false},
+	"GET /graphql":                     {pb.RestAPIGrant_READ, scopeHandler, false},
+	"GET /watch":                       {pb.RestAPIGrant_READ, scopeHandler, false},
+	"POST /graphql":                    {pb.RestAPIGrant_READ, scopeHandler, false},
+	"PUT /cfg/nodes":                   {pb.RestAPIGrant_CONFIG, scopeBody, true},
+	"PUT /cfg/node":                    {pb.RestAPIGrant_CONFIG, scopeBody, true},
+	"PUT /cfg/node/{id}":               {pb.RestAPIGrant_CONFIG, scopeBody, true},
+	"PUT /cfg/group/{name}/nodes":      {pb.RestAPIGrant_CONFIG, scopeGroup, true},
+	"POST /cfg/bulk":                   {pb.RestAPIGrant_CONFIG, scopeHandler, true},
+	"POST /cfg/nodes":                  {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"POST /cfg/node":                   {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"POST /cfg/node/{id}":              {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"DELETE /cfg/node/{id}":            {pb.RestAPIGrant_CONFIG, scopeNode, false},
+	"POST /cfg/node/{id}/decommission": {pb.RestAPIGrant_CONFIG, scopeNode, false},
+	"PUT /dsc/nodes":                   {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"PUT /dsc/node":                    {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"PUT /dsc/node/{id}":               {pb.RestAPIGrant_CONFIG, scopeBody, false},
+	"POST /cfg/restore":                {pb.RestAPIGrant_CONFIG, scopeCluster, false},
+	"POST /dsc/discover":               {pb.RestAPIGrant_CONFIG, scopeHandler, false},
+	"GET /log/audit":                   {pb.RestAPIGrant_CONFIG, scopeCluster, false},
+	"GET /cfg/modules":                 {pb.RestAPIGrant_CONFIG, scopeCluster, false},
+	"GET /cfg/module/{name:.*}":        {pb.RestAPIGrant_CONFIG, scopeCluster, false},
+	"PUT /cfg/module/{name:.*}":        {pb.RestAPIGrant_CONFIG, scopeCluster, false},
+}
+
+type principalKey struct{}
+
+// A principal is who a request is from, and what they've been granted
+type principal struct {
+	name   string
+	all    bool // full access
+	grants []*pb.RestAPIGrant
+}
+
+// can reports whether p has role (or better) for every node in group; an empty group means every node
+func (p *principal) can(role pb.RestAPIGrant_Role, group string) bool {
+	if p.all {
+		return true
+	}
+	for _, g := range p.grants {
+		if g.GetRole() >= role && (g.GetGroup() == "" || g.GetGroup() == group) {
+			return true
+		}
+	}
+	return false
+}
+
+// canNode reports whether p has role (or better) for n
+func (p *principal) canNode(role pb.RestAPIGrant_Role, n lib.Node) bool {
+	if p.can(role, "") {
+		return true
+	}
+	for _, g := range n.GetGroups() {
+		if p.can(role, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize is router middleware that only passes requests that the principal in their context has the roles for (see routeAccess)
+func (r *RestAPI) authorize(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		p, ok := req.Context().Value(principalKey{}).(*principal)
+		if !ok {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if !p.all && !r.allowed(p, req) {
+			r.api.Logf(lib.LLNOTICE, "denied %s %s to %s", req.Method, req.URL.Path, p.name)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// allowed works out whether p can make req
+func (r *RestAPI) allowed(p *principal, req *http.Request) bool {
+	a := access{pb.RestAPIGrant_CONFIG, scopeCluster, false}
+	if route := mux.CurrentRoute(req); route != nil {
+		if t, e := route.GetPathTemplate(); e == nil {
+			if ra, ok := routeAccess[req.Method+" "+versionTemplate(t)]; ok {
+				a = ra
+			}
+		}
+	}
+	vars := mux.Vars(req)
+	switch a.scope {
+	case scopeCluster:
+		return p.can(a.role, "")
+	case scopeNode:
+		n, e := r.api.QueryRead(vars["id"])
+		if e != nil || n == nil {
+			// let it through to get its error, if it could have done it anyway
+			return p.can(a.role, "")
+		}
+		return p.canNode(a.role, n)
+	case scopeGroup:
+		role := a.role
+		if a.power {
+			n := core.NewNodeFromJSON(peekBody(req))
+			if n == nil {
+				return p.can(a.role, vars["name"])
+			}
+			// the URLs a partial node sets are its differences from an empty one
+			d, _ := core.NewNodeFromMessage(&cpb.Node{Id: n.ID().Binary()}).Diff(n, "")
+			role = powerRole(d)
+		}
+		return p.can(role, vars["name"])
+	case scopeBody:
+		ns := bodyNodes(req)
+		if ns == nil {
+			return p.can(a.role, "")
+		}
+		for _, n := range ns {
+			if !r.allowedNode(p, a, n) {
+				return false
+			}
+		}
+		return true
+	case scopeHandler:
+		return true
+	}
+	return false
+}
+
+// allowedNode works out whether p can make the change in n
+// Changes need the role for both the node as it is (if it exists) and as it will be, so no one can give themselves a node by changing its groups.
+func (r *RestAPI) allowedNode(p *principal, a access, n lib.Node) bool {
+	cur, e := r.api.QueryRead(n.ID().String())
+	if e != nil || cur == nil {
+		return p.canNode(a.role, n)
+	}
+	role := a.role
+	if a.power {
+		d, _ := cur.Diff(n, "")
+		role = powerRole(d)
+	}
+	return p.canNode(role, cur) && p.canNode(role, n)
+}
+
+// powerRole is the role needed to change the URLs in diff
+func powerRole(diff []string) pb.RestAPIGrant_Role {
+	for _, u := range diff {
+		if !powerURLs[u] {
+			return pb.RestAPIGrant_CONFIG
+		}
+	}
+	return pb.RestAPIGrant_POWER
+}
+
+// bodyNodes gets the node, or list of nodes, in a request body
+func bodyNodes(req *http.Request) []lib.Node {
+	b := peekBody(req)
+	var pbs cpb.NodeList
+	if e := core.UnmarshalJSON(b, &pbs); e == nil && len(pbs.GetNodes()) > 0 {
+		ns := []lib.Node{}
+		for _, m := range pbs.GetNodes() {
+			ns = append(ns, core.NewNodeFromMessage(m))
+		}
+		return ns
+	}
+	if n := core.NewNodeFromJSON(b); n != nil {
+		return []lib.Node{n}
+	}
+	return nil
+}
+
+// peekBody reads a request's body, and puts it back for the handler
+func peekBody(req *http.Request) []byte {
+	b, _ := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b
+}
@@ -0,0 +1,167 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+// rbacAPI is just enough of an APIClient to authorize requests: it knows some nodes
+type rbacAPI struct {
+	lib.APIClient
+	nodes map[string]lib.Node
+}
+
+func (a *rbacAPI) QueryRead(id string) (lib.Node, error) {
+	if n, ok := a.nodes[id]; ok {
+		return n, nil
+	}
+	return nil, fmt.Errorf("no such node: %s", id)
+}
+
+func (a *rbacAPI) Logf(lib.LoggerLevel, string, ...interface{}) {}
+
+const (
+	rack1Node = "123e4567-e89b-12d3-a456-426655440001"
+	rack2Node = "123e4567-e89b-12d3-a456-426655440002"
+)
+
+func rbacNode(id, group string) *core.Node {
+	n := core.NewNodeWithID(id)
+	n.SetValue("/Groups", reflect.ValueOf([]string{group}))
+	n.SetValue("/PhysState", reflect.ValueOf(cpb.Node_POWER_OFF))
+	return n
+}
+
+// rbacRouter routes some of the API's routes, at both versions, through authorize to a handler that does nothing
+func rbacRouter() *mux.Router {
+	r := &RestAPI{api: &rbacAPI{nodes: map[string]lib.Node{
+		rack1Node: rbacNode(rack1Node, "rack1"),
+		rack2Node: rbacNode(rack2Node, "rack2"),
+	}}}
+	ok := func(w http.ResponseWriter, req *http.Request) {}
+	rt := mux.NewRouter()
+	rt.Use(r.authorize)
+	for _, sr := range []*mux.Router{rt.PathPrefix(apiV1).Subrouter(), rt.NewRoute().Subrouter()} {
+		sr.HandleFunc("/cfg/node/{id}", ok).Methods("GET")
+		sr.HandleFunc("/cfg/node/{id}", ok).Methods("PUT")
+		sr.HandleFunc("/cfg/group/{name}/nodes", ok).Methods("PUT")
+		sr.HandleFunc("/cfg/nodes", ok).Methods("GET")
+		// not in routeAccess
+		sr.HandleFunc("/cfg/unlisted", ok).Methods("POST")
+	}
+	return rt
+}
+
+func grant(role pb.RestAPIGrant_Role, group string) *principal {
+	return &principal{name: "test", grants: []*pb.RestAPIGrant{{Role: role, Group: group}}}
+}
+
+// changed is a node in group, as JSON, after change
+func changed(id, group string, change func(n *core.Node)) string {
+	n := rbacNode(id, group)
+	change(n)
+	return string(n.JSON())
+}
+
+func TestAuthorize(t *testing.T) {
+	power := func(n *core.Node) { n.SetValue("/PhysState", reflect.ValueOf(cpb.Node_POWER_ON)) }
+	rename := func(n *core.Node) { n.SetValue("/Nodename", reflect.ValueOf("renamed")) }
+	powerAndRename := func(n *core.Node) { power(n); rename(n) }
+	toRack := func(g string) func(n *core.Node) {
+		return func(n *core.Node) { n.SetValue("/Groups", reflect.ValueOf([]string{g})) }
+	}
+	node := func(id string) string { return "/cfg/node/" + id }
+
+	tests := []struct {
+		name   string
+		p      *principal
+		method string
+		path   string
+		body   string
+		ok     bool
+	}{
+		// a POWER grant can only change /PhysState
+		{"power on with POWER", grant(pb.RestAPIGrant_POWER, ""), "PUT", node(rack1Node), changed(rack1Node, "rack1", power), true},
+		{"rename with POWER", grant(pb.RestAPIGrant_POWER, ""), "PUT", node(rack1Node), changed(rack1Node, "rack1", rename), false},
+		{"power on and rename with POWER", grant(pb.RestAPIGrant_POWER, ""), "PUT", node(rack1Node), changed(rack1Node, "rack1", powerAndRename), false},
+		{"rename with CONFIG", grant(pb.RestAPIGrant_CONFIG, ""), "PUT", node(rack1Node), changed(rack1Node, "rack1", rename), true},
+		{"power on with READ", grant(pb.RestAPIGrant_READ, ""), "PUT", node(rack1Node), changed(rack1Node, "rack1", power), false},
+		{"power on a group with POWER", grant(pb.RestAPIGrant_POWER, "rack1"), "PUT", "/cfg/group/rack1/nodes", `{"physState": "POWER_ON"}`, true},
+		{"rename a group with POWER", grant(pb.RestAPIGrant_POWER, "rack1"), "PUT", "/cfg/group/rack1/nodes", `{"nodename": "renamed"}`, false},
+		{"power on another group with POWER", grant(pb.RestAPIGrant_POWER, "rack1"), "PUT", "/cfg/group/rack2/nodes", `{"physState": "POWER_ON"}`, false},
+
+		// a group-scoped grant can't move a node into or out of its group
+		{"rename in group", grant(pb.RestAPIGrant_CONFIG, "rack1"), "PUT", node(rack1Node), changed(rack1Node, "rack1", rename), true},
+		{"rename out of group", grant(pb.RestAPIGrant_CONFIG, "rack1"), "PUT", node(rack2Node), changed(rack2Node, "rack2", rename), false},
+		{"move out of group", grant(pb.RestAPIGrant_CONFIG, "rack1"), "PUT", node(rack1Node), changed(rack1Node, "rack1", toRack("rack2")), false},
+		{"move into group", grant(pb.RestAPIGrant_CONFIG, "rack1"), "PUT", node(rack2Node), changed(rack2Node, "rack2", toRack("rack1")), false},
+		{"move between groups with both", &principal{name: "test", grants: []*pb.RestAPIGrant{
+			{Role: pb.RestAPIGrant_CONFIG, Group: "rack1"},
+			{Role: pb.RestAPIGrant_CONFIG, Group: "rack2"},
+		}}, "PUT", node(rack1Node), changed(rack1Node, "rack1", toRack("rack2")), true},
+		{"power on in group", grant(pb.RestAPIGrant_POWER, "rack1"), "PUT", node(rack1Node), changed(rack1Node, "rack1", power), true},
+		{"read in group", grant(pb.RestAPIGrant_READ, "rack1"), "GET", node(rack1Node), "", true},
+		{"read out of group", grant(pb.RestAPIGrant_READ, "rack1"), "GET", node(rack2Node), "", false},
+		{"read everything with a group", grant(pb.RestAPIGrant_READ, "rack1"), "GET", "/cfg/nodes", "", false},
+
+		// routes that aren't in routeAccess need cluster wide CONFIG
+		{"unlisted with READ", grant(pb.RestAPIGrant_READ, ""), "POST", "/cfg/unlisted", "", false},
+		{"unlisted with POWER", grant(pb.RestAPIGrant_POWER, ""), "POST", "/cfg/unlisted", "", false},
+		{"unlisted with group CONFIG", grant(pb.RestAPIGrant_CONFIG, "rack1"), "POST", "/cfg/unlisted", "", false},
+		{"unlisted with CONFIG", grant(pb.RestAPIGrant_CONFIG, ""), "POST", "/cfg/unlisted", "", true},
+
+		{"nothing granted", &principal{name: "test"}, "GET", "/cfg/nodes", "", false},
+		{"full access", &principal{name: "test", all: true}, "POST", "/cfg/unlisted", "", true},
+	}
+	rt := rbacRouter()
+	for _, tt := range tests {
+		// v1 routes resolve to the same access as their unversioned aliases
+		for _, prefix := range []string{"", apiV1} {
+			t.Run(prefix+" "+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, prefix+tt.path, strings.NewReader(tt.body))
+				req = req.WithContext(context.WithValue(req.Context(), principalKey{}, tt.p))
+				w := httptest.NewRecorder()
+				rt.ServeHTTP(w, req)
+				if ok := w.Code == http.StatusOK; ok != tt.ok {
+					t.Errorf("%s %s: expected allowed to be %v, got HTTP %d", tt.method, prefix+tt.path, tt.ok, w.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizeNoPrincipal(t *testing.T) {
+	w := httptest.NewRecorder()
+	rbacRouter().ServeHTTP(w, httptest.NewRequest("GET", "/cfg/nodes", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("a request without a principal got HTTP %d", w.Code)
+	}
+}
+
+func TestPowerRole(t *testing.T) {
+	tests := []struct {
+		diff []string
+		role pb.RestAPIGrant_Role
+	}{
+		{nil, pb.RestAPIGrant_POWER},
+		{[]string{"/PhysState"}, pb.RestAPIGrant_POWER},
+		{[]string{"/Nodename"}, pb.RestAPIGrant_CONFIG},
+		{[]string{"/PhysState", "/RunState"}, pb.RestAPIGrant_CONFIG},
+	}
+	for _, tt := range tests {
+		if r := powerRole(tt.diff); r != tt.role {
+			t.Errorf("%v needs %s, got %s", tt.diff, tt.role, r)
+		}
+	}
+}
@@ -14,10 +14,17 @@ package restapi
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -34,12 +41,64 @@ import (
 var _ lib.Module = (*RestAPI)(nil)
 var _ lib.ModuleSelfService = (*RestAPI)(nil)
 var _ lib.ModuleWithConfig = (*RestAPI)(nil)
+var _ lib.ModuleWithConfigValidation = (*RestAPI)(nil)
+var _ lib.ModuleWithShutdown = (*RestAPI)(nil)
 
 type RestAPI struct {
-	cfg    *pb.RestAPIConfig
-	api    lib.APIClient
-	router *mux.Router
-	srv    *http.Server
+	cfg      *pb.RestAPIConfig
+	api      lib.APIClient
+	router   *mux.Router
+	srv      *http.Server
+	stopping bool
+}
+
+// A BulkRequest makes the same changes to every node that matches all of Hosts, Group and Select
+type BulkRequest struct {
+	Hosts  string            `json:"hosts"`  // a hostlist of nodenames (or IDs), e.g. node[001-100]
+	Group  string            `json:"group"`  // a group name
+	Select string            `json:"select"` // a selector expression, as for /cfg/select
+	Set    map[string]string `json:"set"`    // URLs to set, and their values as they would be printed, e.g. {"/PhysState": "POWER_OFF"}
+}
+
+// A BulkResult is what happened to one of the nodes in a BulkRequest
+type BulkResult struct {
+	ID       string `json:"id"`
+	Nodename string `json:"nodename"`
+	Error    string `json:"error,omitempty"`
+}
+
+// A BulkResponse is what a BulkRequest did
+type BulkResponse struct {
+	Results []BulkResult `json:"results"`
+}
+
+// A DiscoverRequest asks modules to re-discover the nodes that match all of Hosts, Group and Select now, rather than at their next poll.
+// If none of them are given, every node is re-discovered.
+type DiscoverRequest struct {
+	Module string `json:"module"` // only ask this module; every module that can re-discover if empty
+	Hosts  string `json:"hosts"`  // a hostlist of nodenames (or IDs), as for BulkRequest
+	Group  string `json:"group"`  // a group name
+	Select string `json:"select"` // a selector expression, as for /cfg/select
+}
+
+// A DiscoverResponse is who a DiscoverRequest asked to re-discover what
+type DiscoverResponse struct {
+	Services []string `json:"services"` // the IDs of the services that were asked
+	Nodes    []string `json:"nodes"`    // the IDs of the nodes they were asked about; empty for every node
+}
+
+// A NodeStatus is where a node is in the mutation graph, and why, e.g. to tell why a node is stuck
+type NodeStatus struct {
+	ID       string            `json:"id"`
+	Nodename string            `json:"nodename"`
+	Status   string            `json:"status"`            // converged, mutating, throttled, frozen or stuck
+	Reason   string            `json:"reason"`            // why, in words
+	Diff     map[string]string `json:"diff"`              // mutable URLs where the discovered state differs from the configuration, as "dsc -> cfg"
+	Cfg      json.RawMessage   `json:"cfg"`               // the configuration state
+	Dsc      json.RawMessage   `json:"dsc"`               // the discovered state
+	Path     *cpb.MutationPath `json:"path,omitempty"`    // the mutation path being followed, if any
+	Active   *cpb.MutationEdge `json:"active,omitempty"`  // the current mutation in the path
+	Waiting  string            `json:"waiting,omitempty"` // how long the current mutation has been waiting, e.g. 3m2s
 }
 
 type GraphJson struct {
@@ -51,11 +110,23 @@ func (r *RestAPI) Entry() {
 	r.setupRouter()
 	for {
 		r.startServer()
+		if r.stopping {
+			select {} // we're about to exit
+		}
 	}
 }
 
 func (r *RestAPI) Stop() { os.Exit(0) }
 
+// Shutdown stops the listener once the requests it's handling are done
+func (r *RestAPI) Shutdown(ctx context.Context) error {
+	r.stopping = true
+	if r.srv == nil {
+		return nil
+	}
+	return r.srv.Shutdown(ctx)
+}
+
 func (r *RestAPI) Name() string { return "github.com/hpc/kraken/modules/restapi" }
 
 func (r *RestAPI) UpdateConfig(cfg proto.Message) (e error) {
@@ -69,6 +140,32 @@ func (r *RestAPI) UpdateConfig(cfg proto.Message) (e error) {
 	return fmt.Errorf("wrong config type")
 }
 
+// ValidateConfig rejects bad ports, auth settings, base paths, trusted proxy CIDRs and TLS files
+func (r *RestAPI) ValidateConfig(cfg proto.Message) (e error) {
+	rc, ok := cfg.(*pb.RestAPIConfig)
+	if !ok {
+		return fmt.Errorf("wrong config type")
+	}
+	if rc.GetPort() < 1 || rc.GetPort() > 65535 {
+		return fmt.Errorf("invalid port: %d", rc.GetPort())
+	}
+	if _, e = newAuthenticator(rc.GetAuth()); e != nil {
+		return fmt.Errorf("bad auth config: %v", e)
+	}
+	if bp := rc.GetBasePath(); bp != "" && !strings.HasPrefix(bp, "/") {
+		return fmt.Errorf("base path must start with /: %s", bp)
+	}
+	if _, e = trustedNets(rc.GetProxy()); e != nil {
+		return fmt.Errorf("bad proxy config: %v", e)
+	}
+	if tc := rc.GetTls(); tc.GetCert() != "" || tc.GetKey() != "" {
+		if _, e = tlsConfig(tc); e != nil {
+			return fmt.Errorf("bad TLS config: %v", e)
+		}
+	}
+	return
+}
+
 func (r *RestAPI) Init(api lib.APIClient) {
 	r.api = api
 	if r.cfg == nil {
@@ -90,45 +187,152 @@ func (r *RestAPI) ConfigURL() string {
 
 func (r *RestAPI) setupRouter() {
 	r.router = mux.NewRouter()
-	r.router.HandleFunc("/cfg/nodes", r.readAll).Methods("GET")
-	r.router.HandleFunc("/cfg/nodes", r.updateMulti).Methods("PUT")
-	r.router.HandleFunc("/cfg/nodes", r.createMulti).Methods("POST")
-	r.router.HandleFunc("/dsc/nodes", r.readAllDsc).Methods("GET")
-	r.router.HandleFunc("/dsc/nodes", r.updateMultiDsc).Methods("PUT")
-	r.router.HandleFunc("/cfg/node/{id}", r.readNode).Methods("GET")
-	r.router.HandleFunc("/cfg/node", r.createNode).Methods("POST")
-	r.router.HandleFunc("/cfg/node/{id}", r.createNode).Methods("POST")
-	r.router.HandleFunc("/cfg/node/{id}", r.readNode).Methods("GET")
-	r.router.HandleFunc("/cfg/node/{id}", r.deleteNode).Methods("DELETE")
-	r.router.HandleFunc("/dsc/node/{id}", r.readNodeDsc).Methods("GET")
-	r.router.HandleFunc("/cfg/node", r.updateNode).Methods("PUT")
-	r.router.HandleFunc("/cfg/node/{id}", r.updateNode).Methods("PUT")
-	r.router.HandleFunc("/dsc/node", r.updateNodeDsc).Methods("PUT")
-	r.router.HandleFunc("/dsc/node/{id}", r.updateNodeDsc).Methods("PUT")
-	r.router.HandleFunc("/graph/json", r.readGraphJSON).Methods("GET")
-	r.router.HandleFunc("/graph/node/{id}/json", r.readNodeGraphJSON).Methods("GET")
+	r.router.Use(r.audit, r.authorize)
+	r.router.HandleFunc("/health", r.health).Methods("GET")
+	r.router.HandleFunc("/healthz", r.healthz).Methods("GET")
+	r.router.HandleFunc("/readyz", r.readyz).Methods("GET")
+	r.router.HandleFunc("/swagger.json", r.readOpenAPI).Methods("GET")
+	v1 := r.router.PathPrefix(apiV1).Subrouter()
+	v1.HandleFunc("/health", r.health).Methods("GET")
+	v1.HandleFunc("/healthz", r.healthz).Methods("GET")
+	v1.HandleFunc("/readyz", r.readyz).Methods("GET")
+	v1.HandleFunc("/swagger.json", r.readOpenAPI).Methods("GET")
+	r.routes(v1)
+	// the unversioned paths are deprecated aliases of v1
+	legacy := r.router.NewRoute().Subrouter()
+	legacy.Use(deprecated)
+	r.routes(legacy)
+}
+
+// routes adds the API's routes to rt, relative to its version prefix
+func (r *RestAPI) routes(rt *mux.Router) {
+	rt.HandleFunc("/cfg/nodes", r.readAll).Methods("GET")
+	rt.HandleFunc("/cfg/nodes", r.updateMulti).Methods("PUT")
+	rt.HandleFunc("/cfg/nodes", r.createMulti).Methods("POST")
+	rt.HandleFunc("/dsc/nodes", r.readAllDsc).Methods("GET")
+	rt.HandleFunc("/cfg/snapshot", r.readSnapshot).Methods("GET")
+	rt.HandleFunc("/cfg/groups", r.readGroups).Methods("GET")
+	rt.HandleFunc("/cfg/group/{name}/nodes", r.readGroup).Methods("GET")
+	rt.HandleFunc("/cfg/group/{name}/nodes", r.updateGroup).Methods("PUT")
+	rt.HandleFunc("/cfg/bulk", r.updateBulk).Methods("POST")
+	rt.HandleFunc("/dsc/group/{name}/nodes", r.readGroupDsc).Methods("GET")
+	rt.HandleFunc("/cfg/select", r.readSelect).Methods("GET")
+	rt.HandleFunc("/dsc/select", r.readSelectDsc).Methods("GET")
+	rt.HandleFunc("/cfg/restore", r.restore).Methods("POST")
+	rt.HandleFunc("/cfg/modules", r.readModuleConfigs).Methods("GET")
+	rt.HandleFunc("/cfg/module/{name:.*}", r.readModuleConfig).Methods("GET")
+	rt.HandleFunc("/cfg/module/{name:.*}", r.updateModuleConfig).Methods("PUT")
+	rt.HandleFunc("/dsc/nodes", r.updateMultiDsc).Methods("PUT")
+	rt.HandleFunc("/cfg/node/{id}", r.readNode).Methods("GET")
+	rt.HandleFunc("/cfg/node", r.createNode).Methods("POST")
+	rt.HandleFunc("/cfg/node/{id}", r.createNode).Methods("POST")
+	rt.HandleFunc("/cfg/node/{id}", r.readNode).Methods("GET")
+	rt.HandleFunc("/cfg/node/{id}", r.deleteNode).Methods("DELETE")
+	rt.HandleFunc("/cfg/node/{id}/decommission", r.decommissionNode).Methods("POST")
+	rt.HandleFunc("/dsc/node/{id}", r.readNodeDsc).Methods("GET")
+	rt.HandleFunc("/dsc/discover", r.discover).Methods("POST")
+	rt.HandleFunc("/dsc/rollup", r.readRollups).Methods("GET")
+	rt.HandleFunc("/dsc/rollup/{location:.*}", r.readRollups).Methods("GET")
+	rt.HandleFunc("/cfg/node", r.updateNode).Methods("PUT")
+	rt.HandleFunc("/cfg/node/{id}", r.updateNode).Methods("PUT")
+	rt.HandleFunc("/dsc/node", r.updateNodeDsc).Methods("PUT")
+	rt.HandleFunc("/dsc/node/{id}", r.updateNodeDsc).Methods("PUT")
+	rt.HandleFunc("/graph/json", r.readGraphJSON).Methods("GET")
+	rt.HandleFunc("/graph/node/{id}/json", r.readNodeGraphJSON).Methods("GET")
+	rt.HandleFunc("/graph/dot", r.readGraphDOT).Methods("GET")
+	rt.HandleFunc("/graph/node/{id}/dot", r.readNodeGraphDOT).Methods("GET")
+	rt.HandleFunc("/graph/node/{id}/plan", r.readNodePlan).Methods("GET")
+	rt.HandleFunc("/graph/node/{id}/status", r.readNodeStatus).Methods("GET")
+	rt.HandleFunc("/graph/plan", r.readPlan).Methods("POST")
+	rt.HandleFunc("/graph/metrics", r.readMetrics).Methods("GET")
+	rt.HandleFunc("/graph/convergence", r.readConvergence).Methods("GET")
+	rt.HandleFunc("/log/events", r.readEventLog).Methods("GET")
+	rt.HandleFunc("/log/node/{id}/events", r.readEventLog).Methods("GET")
+	rt.HandleFunc("/log/audit", r.readAuditLog).Methods("GET")
+	rt.HandleFunc("/watch", r.watch).Methods("GET")
+	rt.HandleFunc("/graphql", r.graphQL).Methods("GET", "POST")
+}
+
+// corsHandler lets browsers make cross-origin requests to h from origins.
+// With no origins it sends no CORS headers at all; any origin is allowed only if "*" is one of them.
+func corsHandler(origins []string, h http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return h
+	}
+	return handlers.CORS(
+		handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"}),
+		handlers.AllowedOrigins(origins),
+		handlers.AllowedMethods([]string{"PUT", "GET", "POST", "DELETE"}),
+		handlers.ExposedHeaders([]string{"X-Total-Count", "Deprecation", "Link"}),
+	)(h)
 }
 
 func (r *RestAPI) startServer() {
+	auth, e := newAuthenticator(r.cfg.GetAuth())
+	if e != nil {
+		r.api.Logf(lib.LLERROR, "bad auth config: %v", e)
+	}
+	if auth.disabled {
+		r.api.Log(lib.LLWARNING, "restapi authentication is disabled")
+	}
+	nets, e := trustedNets(r.cfg.GetProxy())
+	if e != nil {
+		r.api.Logf(lib.LLERROR, "bad proxy config: %v", e)
+	}
+	h := corsHandler(r.cfg.GetCorsOrigins(), proxyHandler(basePath(r.cfg.GetBasePath()), r.cfg.GetProxy(), nets, r.authHandler(auth, r.router)))
+	// requests' contexts are canceled when the server shuts down, so streams (e.g. /watch) don't hold it up
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	r.srv = &http.Server{
-		Handler: handlers.CORS(
-			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"}),
-			handlers.AllowedOrigins([]string{"*"}),
-			handlers.AllowedMethods([]string{"PUT", "GET", "POST", "DELETE"}),
-		)(r.router),
+		BaseContext:  func(net.Listener) context.Context { return ctx },
+		Handler:      h,
 		Addr:         fmt.Sprintf("%s:%d", r.cfg.Addr, r.cfg.Port),
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
 	}
-	r.api.Logf(lib.LLINFO, "restapi is listening on: %s\n", r.srv.Addr)
-	if e := r.srv.ListenAndServe(); e != nil {
-		if e != http.ErrServerClosed {
-			r.api.Logf(lib.LLNOTICE, "http stopped: %v\n", e)
-		}
+	r.srv.RegisterOnShutdown(cancel)
+	tc := r.cfg.GetTls()
+	if tc.GetCert() == "" && tc.GetKey() == "" {
+		r.api.Logf(lib.LLINFO, "restapi is listening on: %s\n", r.srv.Addr)
+		e = r.srv.ListenAndServe()
+	} else if r.srv.TLSConfig, e = tlsConfig(tc); e == nil {
+		r.api.Logf(lib.LLINFO, "restapi is listening on: %s (TLS)\n", r.srv.Addr)
+		e = r.srv.ListenAndServeTLS(tc.GetCert(), tc.GetKey())
+	}
+	if e != nil && e != http.ErrServerClosed {
+		r.api.Logf(lib.LLNOTICE, "http stopped: %v\n", e)
+		time.Sleep(time.Second) // don't spin if we can't start
 	}
 	r.api.Log(lib.LLNOTICE, "restapi listener stopped")
 }
 
+// tlsConfig makes the TLS config for serving with tc, verifying client certificates if there's a client CA
+func tlsConfig(tc *pb.RestAPITLS) (*tls.Config, error) {
+	if tc.GetCert() == "" || tc.GetKey() == "" {
+		return nil, fmt.Errorf("TLS needs both a cert and a key")
+	}
+	c := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tc.GetClientCa() == "" {
+		if tc.GetRequireClientCert() {
+			return nil, fmt.Errorf("requiring client certificates needs a client CA")
+		}
+		return c, nil
+	}
+	b, e := ioutil.ReadFile(tc.GetClientCa())
+	if e != nil {
+		return nil, fmt.Errorf("could not read client CA: %v", e)
+	}
+	c.ClientCAs = x509.NewCertPool()
+	if !c.ClientCAs.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates in client CA: %s", tc.GetClientCa())
+	}
+	c.ClientAuth = tls.VerifyClientCertIfGiven
+	if tc.GetRequireClientCert() {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return c, nil
+}
+
 func (r *RestAPI) srvStop() {
 	r.api.Log(lib.LLDEBUG, "restapi is shutting down listener")
 	r.srv.Shutdown(context.Background())
@@ -145,31 +349,362 @@ func (r *RestAPI) readAll(w http.ResponseWriter, req *http.Request) {
 		w.Write([]byte(e.Error()))
 		return
 	}
+	r.writeNodes(w, req, ns, true)
+}
+
+func (r *RestAPI) readAllDsc(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	ns, e := r.api.QueryReadAllDsc()
+	if e != nil {
+		w.Write([]byte(e.Error()))
+		return
+	}
+	r.writeNodes(w, req, ns, true)
+}
+
+func (r *RestAPI) readSnapshot(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	ns, e := r.api.QueryReadAll()
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=kraken-snapshot.json")
+	core.WriteSnapshot(w, ns)
+}
+
+func (r *RestAPI) restore(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	ns, e := core.ReadSnapshot(req.Body)
+	if e != nil {
+		r.api.Logf(lib.LLERROR, "unmarshal JSON error: %v", e)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	nns, e := r.api.QueryRestore(ns)
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
 	var rsp cpb.NodeList
-	for _, n := range ns {
+	for _, n := range nns {
 		rsp.Nodes = append(rsp.Nodes, n.Message().(*cpb.Node))
 	}
 	b, _ := core.MarshalJSON(&rsp)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(b)
 }
 
-func (r *RestAPI) readAllDsc(w http.ResponseWriter, req *http.Request) {
+// readGroups lists the nodes in each group, by ID
+func (r *RestAPI) readGroups(w http.ResponseWriter, req *http.Request) {
 	defer req.Body.Close()
-	ns, e := r.api.QueryReadAllDsc()
+	ns, e := r.api.QueryReadAll()
 	if e != nil {
+		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
-	var rsp cpb.NodeList
+	groups := make(map[string][]string)
 	for _, n := range ns {
-		rsp.Nodes = append(rsp.Nodes, n.Message().(*cpb.Node))
+		for _, g := range n.GetGroups() {
+			groups[g] = append(groups[g], n.ID().String())
+		}
+	}
+	b, _ := json.Marshal(groups)
+	w.Write(b)
+}
+
+func (r *RestAPI) readGroup(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	ns, e := r.api.QueryReadGroup(params["name"])
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	r.writeNodes(w, req, ns, true)
+}
+
+func (r *RestAPI) readGroupDsc(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	ns, e := r.api.QueryReadGroupDsc(params["name"])
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	r.writeNodes(w, req, ns, true)
+}
+
+// readSelect gets the configured nodes that match the selector in the q parameter, e.g. ?q=/PhysState == POWER_OFF
+// The list can be shaped as for the other node lists (see writeNodes).
+func (r *RestAPI) readSelect(w http.ResponseWriter, req *http.Request) {
+	r.writeSelect(w, req, r.api.QuerySelect)
+}
+
+// readSelectDsc gets the discovered nodes that match the selector in the q parameter (see readSelect)
+func (r *RestAPI) readSelectDsc(w http.ResponseWriter, req *http.Request) {
+	r.writeSelect(w, req, r.api.QuerySelectDsc)
+}
+
+func (r *RestAPI) writeSelect(w http.ResponseWriter, req *http.Request, sel func(string) ([]lib.Node, error)) {
+	defer req.Body.Close()
+	ns, e := sel(req.URL.Query().Get("q"))
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	r.writeNodes(w, req, ns, false)
+}
+
+// writeNodes writes a list of nodes, shaped by the request's query parameters:
+//
+//	q              only nodes that match a selector expression (if filter is set; see core.NodeSelector)
+//	sort           a URL to sort by (e.g. /Nodename); prefix it with - for descending order
+//	offset, limit  a page of the (sorted) list; paged lists are sorted by ID if they aren't sorted otherwise
+//	fields         a comma separated list of URLs (which may have wildcards); only those values are returned,
+//	               as a map of node ID to URL to value (in the order of the list)
+//
+// The length of the whole list (before paging) is in the X-Total-Count header.
+func (r *RestAPI) writeNodes(w http.ResponseWriter, req *http.Request, ns []lib.Node, filter bool) {
+	query := req.URL.Query()
+	if expr := query.Get("q"); filter && expr != "" {
+		sel, e := core.ParseNodeSelector(expr)
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+		var m []lib.Node
+		for _, n := range ns {
+			if sel.Match(n) {
+				m = append(m, n)
+			}
+		}
+		ns = m
+	}
+	offset, limit := 0, len(ns)
+	for p, v := range map[string]*int{"offset": &offset, "limit": &limit} {
+		s := query.Get(p)
+		if s == "" {
+			continue
+		}
+		i, e := strconv.Atoi(s)
+		if e != nil || i < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("bad %s: %s", p, s)))
+			return
+		}
+		*v = i
+	}
+	if s := query.Get("sort"); s != "" {
+		core.SortNodes(ns, strings.TrimPrefix(s, "-"), strings.HasPrefix(s, "-"))
+	} else if query.Get("offset") != "" || query.Get("limit") != "" {
+		core.SortNodes(ns, "/Id", false)
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(ns)))
+	if offset > len(ns) {
+		offset = len(ns)
+	}
+	if limit > len(ns)-offset {
+		limit = len(ns) - offset
+	}
+	ns = ns[offset : offset+limit]
+	var b []byte
+	if fields := query.Get("fields"); fields != "" {
+		// built by hand to keep the order
+		buf := bytes.NewBufferString("{")
+		for i, n := range ns {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			id, _ := json.Marshal(n.ID().String())
+			vs, _ := json.Marshal(core.SelectValues(n, strings.Split(fields, ",")))
+			buf.Write(id)
+			buf.WriteString(":")
+			buf.Write(vs)
+		}
+		buf.WriteString("}")
+		b = buf.Bytes()
+	} else {
+		var rsp cpb.NodeList
+		for _, n := range ns {
+			rsp.Nodes = append(rsp.Nodes, n.Message().(*cpb.Node))
+		}
+		b, _ = core.MarshalJSON(&rsp)
+	}
+	w.Write(b)
+}
+
+// updateGroup takes a (partial) node, and sets the values it sets on every node in the group
+func (r *RestAPI) updateGroup(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(req.Body)
+	n := core.NewNodeFromJSON(buf.Bytes())
+	if n == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ns, e := r.api.QueryUpdateGroup(params["name"], n)
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	var rsp cpb.NodeList
+	for _, nn := range ns {
+		rsp.Nodes = append(rsp.Nodes, nn.Message().(*cpb.Node))
 	}
 	b, _ := core.MarshalJSON(&rsp)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(b)
 }
 
+// updateBulk makes the changes in a BulkRequest, and reports what happened to each node
+// Each node's changes are made as one transaction, so a node gets all of them or none.
+func (r *RestAPI) updateBulk(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	var br BulkRequest
+	if e := json.NewDecoder(req.Body).Decode(&br); e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	ns, e := r.bulkNodes(br)
+	if e == nil && len(br.Set) == 0 {
+		e = fmt.Errorf("nothing to set")
+	}
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	p, _ := req.Context().Value(principalKey{}).(*principal)
+	urls := []string{}
+	for u := range br.Set {
+		urls = append(urls, u)
+	}
+	role := powerRole(urls)
+	rsp := BulkResponse{Results: []BulkResult{}}
+	for _, n := range ns {
+		name, _ := n.GetValue("/Nodename")
+		res := BulkResult{ID: n.ID().String(), Nodename: name.String()}
+		vs := make(map[string]reflect.Value)
+		for u, s := range br.Set {
+			cur, e := n.GetValue(u)
+			if e == nil {
+				vs[lib.NodeURLJoin(res.ID, u)], e = core.ValueFromString(cur.Type(), s)
+			}
+			if e != nil {
+				res.Error = e.Error()
+				break
+			}
+		}
+		if res.Error == "" && (p == nil || !p.canNode(role, n)) {
+			res.Error = "forbidden"
+		}
+		if res.Error == "" {
+			if e := r.api.QueryTransact(vs); e != nil {
+				res.Error = e.Error()
+			}
+		}
+		rsp.Results = append(rsp.Results, res)
+	}
+	b, _ := json.Marshal(rsp)
+	w.Write(b)
+}
+
+// discover asks modules to re-discover nodes now, e.g. {"module": "ipmipower", "hosts": "node[001-040]"}
+func (r *RestAPI) discover(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	var dr DiscoverRequest
+	if e := json.NewDecoder(req.Body).Decode(&dr); e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	p, _ := req.Context().Value(principalKey{}).(*principal)
+	rsp := DiscoverResponse{Nodes: []string{}}
+	if dr.Hosts == "" && dr.Group == "" && dr.Select == "" {
+		if p == nil || !p.can(pb.RestAPIGrant_CONFIG, "") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	} else {
+		ns, e := r.bulkNodes(BulkRequest{Hosts: dr.Hosts, Group: dr.Group, Select: dr.Select})
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+		if len(ns) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("no nodes matched"))
+			return
+		}
+		for _, n := range ns {
+			if p == nil || !p.canNode(pb.RestAPIGrant_CONFIG, n) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			rsp.Nodes = append(rsp.Nodes, n.ID().String())
+		}
+	}
+	srvs, e := r.api.QueryDiscover(rsp.Nodes, dr.Module)
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	rsp.Services = append([]string{}, srvs...)
+	b, _ := json.Marshal(rsp)
+	w.Write(b)
+}
+
+// bulkNodes gets the nodes that a BulkRequest is for
+func (r *RestAPI) bulkNodes(br BulkRequest) (ns []lib.Node, e error) {
+	if br.Hosts == "" && br.Group == "" && br.Select == "" {
+		return nil, fmt.Errorf("need hosts, a group, or a select expression")
+	}
+	hosts := make(map[string]bool)
+	if br.Hosts != "" {
+		hl, e := core.ExpandHostlist(br.Hosts)
+		if e != nil {
+			return nil, e
+		}
+		for _, h := range hl {
+			hosts[h] = true
+		}
+	}
+	var all []lib.Node
+	if br.Select != "" {
+		all, e = r.api.QuerySelect(br.Select)
+	} else {
+		all, e = r.api.QueryReadAll()
+	}
+	if e != nil {
+		return
+	}
+	for _, n := range all {
+		if br.Group != "" && !n.InGroup(br.Group) {
+			continue
+		}
+		if br.Hosts != "" {
+			if name, _ := n.GetValue("/Nodename"); !hosts[name.String()] && !hosts[n.ID().String()] {
+				continue
+			}
+		}
+		ns = append(ns, n)
+	}
+	return
+}
+
 func (r *RestAPI) readNode(w http.ResponseWriter, req *http.Request) {
 	defer req.Body.Close()
 	params := mux.Vars(req)
@@ -178,7 +713,32 @@ func (r *RestAPI) readNode(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(n.JSON())
+}
+
+// decommissionNode starts retiring a node
+// the body is optional, e.g. {"final": {"/PhysState": "POWER_OFF"}, "remove": true}
+func (r *RestAPI) decommissionNode(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	dr := &cpb.DecommissionRequest{}
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(req.Body)
+	if buf.Len() > 0 {
+		if e := json.Unmarshal(buf.Bytes(), dr); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+	}
+	n, e := r.api.QueryDecommission(params["id"], dr.Final, dr.Remove)
+	if e != nil || n == nil {
+		w.WriteHeader(http.StatusConflict)
+		if e != nil {
+			w.Write([]byte(e.Error()))
+		}
+		return
+	}
 	w.Write(n.JSON())
 }
 
@@ -186,107 +746,346 @@ func (r *RestAPI) readNodeGraphJSON(w http.ResponseWriter, req *http.Request) {
 	defer req.Body.Close()
 	params := mux.Vars(req)
 
-	nodes, e := r.api.QueryNodeMutationNodes(params["id"])
+	graph, e := r.nodeGraph(params["id"])
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
-	edges, e := r.api.QueryNodeMutationEdges(params["id"])
+	jsonGraph, e := json.Marshal(graph)
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
+	r.api.Logf(lib.LLDDDEBUG, "Node filtered graph: %v", string(jsonGraph))
+	w.Write([]byte(string(jsonGraph)))
+}
 
-	path, e := r.api.QueryNodeMutationPath(params["id"])
+func (r *RestAPI) readNodeGraphDOT(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+
+	graph, e := r.nodeGraph(params["id"])
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.Write(core.MutationGraphDOT(
+		&cpb.MutationNodeList{MutationNodeList: graph.Nodes},
+		&cpb.MutationEdgeList{MutationEdgeList: graph.Edges},
+	))
+}
 
-	// Convert edges and nodes slice to maps
-	nodesMap := make(map[string]*cpb.MutationNode)
-	edgesMap := make(map[string]*cpb.MutationEdge)
-	for _, mn := range nodes.MutationNodeList {
-		nodesMap[mn.Id] = mn
+func (r *RestAPI) readGraphJSON(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	graph, e := r.graph()
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
 	}
-	for _, me := range edges.MutationEdgeList {
-		edgesMap[me.Id] = me
+
+	jsonGraph, e := json.Marshal(graph)
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
 	}
+	r.api.Logf(lib.LLDDDEBUG, "Graph: %v", string(jsonGraph))
+	w.Write([]byte(string(jsonGraph)))
+}
 
-	red := "#e74c3c"
-	green := "#89CA78"
+func (r *RestAPI) readGraphDOT(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
 
-	for i, me := range path.Chain {
-		if int64(i) != path.Cur {
-			c := &cpb.EdgeColor{
-				Color:     green,
-				Highlight: green,
-				Inherit:   false,
+	graph, e := r.graph()
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.Write(core.MutationGraphDOT(
+		&cpb.MutationNodeList{MutationNodeList: graph.Nodes},
+		&cpb.MutationEdgeList{MutationEdgeList: graph.Edges},
+	))
+}
+
+// readNodePlan shows the mutations kraken would make to get a node to its current configuration
+func (r *RestAPI) readNodePlan(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	n, e := r.api.QueryRead(params["id"])
+	if e != nil || n == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	r.writePlan(w, n)
+}
+
+// readNodeStatus shows where a node is in the mutation graph, and why (see NodeStatus)
+func (r *RestAPI) readNodeStatus(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	st, e := r.nodeStatus(params["id"])
+	if e != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	b, _ := json.Marshal(st)
+	w.Write(b)
+}
+
+func (r *RestAPI) nodeStatus(id string) (st NodeStatus, e error) {
+	cfg, e := r.api.QueryRead(id)
+	if e != nil || cfg == nil {
+		return st, fmt.Errorf("no such node: %s", id)
+	}
+	dsc, e := r.api.QueryReadDsc(id)
+	if e != nil || dsc == nil {
+		return st, fmt.Errorf("no discovered state for node: %s", id)
+	}
+	name, _ := cfg.GetValue("/Nodename")
+	st = NodeStatus{
+		ID:       cfg.ID().String(),
+		Nodename: name.String(),
+		Diff:     make(map[string]string),
+		Cfg:      cfg.JSON(),
+		Dsc:      dsc.JSON(),
+	}
+	// only differences in URLs that mutations change can be mutated away
+	mutable := make(map[string]bool)
+	if edges, e := r.api.QueryMutationEdges(); e == nil {
+		for _, me := range edges.GetMutationEdgeList() {
+			for u := range me.GetMutates() {
+				mutable[u] = true
 			}
-			edgesMap[me.Id].Color = c
-			nodesMap[me.To].Color = green
-			nodesMap[me.From].Color = green
+		}
+	}
+	diff, _ := dsc.Diff(cfg, "")
+	for _, u := range diff {
+		if mutable[u] {
+			dv, _ := dsc.GetValue(u)
+			cv, _ := cfg.GetValue(u)
+			st.Diff[u] = lib.ValueToString(dv) + " -> " + lib.ValueToString(cv)
+		}
+	}
+	path, pe := r.api.QueryNodeMutationPath(st.ID)
+	mutating := pe == nil && path.GetCur() < int64(len(path.GetChain()))
+	switch {
+	case len(st.Diff) == 0:
+		st.Status, st.Reason = "converged", "the discovered state matches the configuration"
+	case cfg.Frozen():
+		st.Status, st.Reason = "frozen", "the node is frozen (maintenance mode), so kraken won't mutate it"
+	case mutating:
+		st.Path = &path
+		st.Active = path.Chain[path.Cur]
+		if t, e := ptypes.Timestamp(path.GetCurStarted()); e == nil {
+			st.Waiting = time.Since(t).Round(time.Second).String()
+		}
+		step := fmt.Sprintf("%s:%s (step %d of %d)", st.Active.GetModule(), st.Active.GetMutation(), path.Cur+1, len(path.Chain))
+		if path.GetThrottle() != "" {
+			st.Status = "throttled"
+			st.Reason = fmt.Sprintf("waiting for a slot in the %s throttle to start %s", path.GetThrottle(), step)
 		} else {
-			c := &cpb.EdgeColor{
-				Color:     red,
-				Highlight: red,
-				Inherit:   false,
+			st.Status = "mutating"
+			st.Reason = "waiting for " + step + " to be discovered"
+			if path.GetTries() > 0 {
+				st.Reason += fmt.Sprintf(", retried %d times", path.GetTries())
 			}
-			edgesMap[me.Id].Color = c
-			nodesMap[me.To].Color = green
-			nodesMap[me.From].Color = green
 		}
+		if st.Waiting != "" {
+			st.Reason += ", for " + st.Waiting
+		}
+		if path.GetRollback() {
+			st.Reason += "; the chain failed, and is rolling back"
+		}
+	default:
+		st.Status = "stuck"
+		if plan, e := r.api.QueryMutationPlan(cfg); e != nil {
+			st.Reason = fmt.Sprintf("there is no mutation path from the discovered state to the configuration: %v", e)
+		} else {
+			st.Reason = fmt.Sprintf("not mutating, though there is a path of %d mutations; it starts on the next change to the node (or reconciliation)", len(plan.GetChain()))
+		}
+	}
+	return
+}
+
+// readPlan shows the mutations kraken would make to get a node to the configuration that was posted
+// nothing is changed; the posted node should be a complete node, as for PUT /cfg/node
+func (r *RestAPI) readPlan(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(req.Body)
+	n := core.NewNodeFromJSON(buf.Bytes())
+	if n == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
+	r.writePlan(w, n)
+}
 
-	graph := GraphJson{
-		Nodes: nodes.MutationNodeList,
-		Edges: edges.MutationEdgeList,
+func (r *RestAPI) writePlan(w http.ResponseWriter, n lib.Node) {
+	plan, e := r.api.QueryMutationPlan(n)
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	jsonGraph, e := json.Marshal(graph)
+	jsonPlan, e := json.Marshal(plan)
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
-	r.api.Logf(lib.LLDDDEBUG, "Node filtered graph: %v", string(jsonGraph))
-	w.Write([]byte(string(jsonGraph)))
+	w.Write(jsonPlan)
 }
 
-func (r *RestAPI) readGraphJSON(w http.ResponseWriter, req *http.Request) {
+// readRollups gets summaries of the discovered state of nodes at a location (e.g. rack12/chassis3), and below it
+func (r *RestAPI) readRollups(w http.ResponseWriter, req *http.Request) {
 	defer req.Body.Close()
-
-	nodes, e := r.api.QueryMutationNodes()
+	params := mux.Vars(req)
+	rs, e := r.api.QueryRollups(params["location"])
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
-
-	edges, e := r.api.QueryMutationEdges()
+	b, e := core.MarshalJSON(&cpb.RollupList{Rollups: rs})
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
+	w.Write(b)
+}
 
-	graph := GraphJson{
-		Nodes: nodes.MutationNodeList,
-		Edges: edges.MutationEdgeList,
+// readMetrics gets the SME's mutation metrics
+func (r *RestAPI) readMetrics(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	mm, e := r.api.QueryMutationMetrics()
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
 	}
+	b, e := core.MarshalJSON(&mm)
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	w.Write(b)
+}
 
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	jsonGraph, e := json.Marshal(graph)
+// readEventLog gets event log records, optionally limited to a time range with from/to (RFC3339) parameters
+func (r *RestAPI) readEventLog(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	params := mux.Vars(req)
+	var from, to time.Time
+	var e error
+	if s := req.URL.Query().Get("from"); s != "" {
+		if from, e = time.Parse(time.RFC3339, s); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+	}
+	if s := req.URL.Query().Get("to"); s != "" {
+		if to, e = time.Parse(time.RFC3339, s); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(e.Error()))
+			return
+		}
+	}
+	rs, e := r.api.QueryEventLog(params["id"], from, to)
 	if e != nil {
 		w.WriteHeader(http.StatusConflict)
 		w.Write([]byte(e.Error()))
 		return
 	}
-	r.api.Logf(lib.LLDDDEBUG, "Graph: %v", string(jsonGraph))
-	w.Write([]byte(string(jsonGraph)))
+	b, e := core.MarshalJSON(&cpb.EventRecordList{Records: rs})
+	if e != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	w.Write(b)
+}
+
+// graph gets the whole mutation graph
+func (r *RestAPI) graph() (g GraphJson, e error) {
+	nodes, e := r.api.QueryMutationNodes()
+	if e != nil {
+		return
+	}
+	edges, e := r.api.QueryMutationEdges()
+	if e != nil {
+		return
+	}
+	g.Nodes = nodes.MutationNodeList
+	g.Edges = edges.MutationEdgeList
+	return
+}
+
+// nodeGraph gets the part of the mutation graph that applies to a node, with its current path colored
+func (r *RestAPI) nodeGraph(id string) (g GraphJson, e error) {
+	nodes, e := r.api.QueryNodeMutationNodes(id)
+	if e != nil {
+		return
+	}
+	edges, e := r.api.QueryNodeMutationEdges(id)
+	if e != nil {
+		return
+	}
+	path, e := r.api.QueryNodeMutationPath(id)
+	if e != nil {
+		return
+	}
+
+	// Convert edges and nodes slice to maps
+	nodesMap := make(map[string]*cpb.MutationNode)
+	edgesMap := make(map[string]*cpb.MutationEdge)
+	for _, mn := range nodes.MutationNodeList {
+		nodesMap[mn.Id] = mn
+	}
+	for _, me := range edges.MutationEdgeList {
+		edgesMap[me.Id] = me
+	}
+
+	red := "#e74c3c"
+	green := "#89CA78"
+
+	for i, me := range path.Chain {
+		if int64(i) != path.Cur {
+			c := &cpb.EdgeColor{
+				Color:     green,
+				Highlight: green,
+				Inherit:   false,
+			}
+			edgesMap[me.Id].Color = c
+			nodesMap[me.To].Color = green
+			nodesMap[me.From].Color = green
+		} else {
+			c := &cpb.EdgeColor{
+				Color:     red,
+				Highlight: red,
+				Inherit:   false,
+			}
+			edgesMap[me.Id].Color = c
+			nodesMap[me.To].Color = green
+			nodesMap[me.From].Color = green
+		}
+	}
+
+	g.Nodes = nodes.MutationNodeList
+	g.Edges = edges.MutationEdgeList
+	return
 }
 
 func (r *RestAPI) readNodeDsc(w http.ResponseWriter, req *http.Request) {
@@ -297,7 +1096,6 @@ func (r *RestAPI) readNodeDsc(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(n.JSON())
 }
 
@@ -316,7 +1114,6 @@ func (r *RestAPI) updateNode(w http.ResponseWriter, req *http.Request) {
 		w.Write([]byte(e.Error()))
 		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(nn.JSON())
 }
 
@@ -340,7 +1137,6 @@ func (r *RestAPI) updateMulti(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 	b, _ := core.MarshalJSON(&rsp)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(b)
 }
 
@@ -384,7 +1180,6 @@ func (r *RestAPI) updateMultiDsc(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 	b, _ := core.MarshalJSON(&rsp)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(b)
 }
 
@@ -397,7 +1192,6 @@ func (r *RestAPI) deleteNode(w http.ResponseWriter, req *http.Request) {
 		w.Write([]byte(e.Error()))
 		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(n.JSON())
 }
 
@@ -416,7 +1210,6 @@ func (r *RestAPI) createNode(w http.ResponseWriter, req *http.Request) {
 		w.Write([]byte(e.Error()))
 		return
 	}
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(nn.JSON())
 }
 
@@ -440,7 +1233,6 @@ func (r *RestAPI) createMulti(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 	b, _ := core.MarshalJSON(&rsp)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(b)
 }
 
@@ -0,0 +1,51 @@
+package restapi
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/lib"
+)
+
+const (
+	cfgNode = "123e4567-e89b-12d3-a456-426655440010"
+	dscNode = "123e4567-e89b-12d3-a456-426655440020"
+)
+
+// selectAPI answers selections with a different node from each state
+type selectAPI struct {
+	lib.APIClient
+}
+
+func (a *selectAPI) QuerySelect(string) ([]lib.Node, error) {
+	return []lib.Node{core.NewNodeWithID(cfgNode)}, nil
+}
+
+func (a *selectAPI) QuerySelectDsc(string) ([]lib.Node, error) {
+	return []lib.Node{core.NewNodeWithID(dscNode)}, nil
+}
+
+func TestReadSelect(t *testing.T) {
+	r := &RestAPI{api: &selectAPI{}}
+	rt := mux.NewRouter()
+	r.routes(rt.PathPrefix(apiV1).Subrouter())
+	r.routes(rt.NewRoute().Subrouter())
+	for _, prefix := range []string{"", apiV1} {
+		for state, expect := range map[string]string{"cfg": cfgNode, "dsc": dscNode} {
+			// with fields, the nodes are keyed by their IDs
+			path := prefix + "/" + state + "/select?q=/PhysState==POWER_OFF&fields=/Nodename"
+			w := httptest.NewRecorder()
+			rt.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			if w.Code != 200 {
+				t.Errorf("%s: got %d: %s", path, w.Code, w.Body.String())
+				continue
+			}
+			if !strings.Contains(w.Body.String(), expect) {
+				t.Errorf("%s: expected node %s, got %s", path, expect, w.Body.String())
+			}
+		}
+	}
+}
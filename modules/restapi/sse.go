@@ -0,0 +1,104 @@
+/* sse.go: streams state changes to clients as server-sent events
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/restapi/proto"
+)
+
+// watchKeepAlive is how often a comment is sent on an idle stream, so proxies don't time it out
+const watchKeepAlive = 30 * time.Second
+
+// watch streams state changes as server-sent events (text/event-stream), until the client goes away.
+// The query filters changes as a WatchRequest does, e.g. ?node=<id>&url=/PhysState&types=UPDATE,CFG_UPDATE
+// Each event is named by the change's type, and its data is the change as JSON.
+func (r *RestAPI) watch(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+	q := req.URL.Query()
+	wr := &cpb.WatchRequest{
+		Node:  q.Get("node"),
+		Url:   q.Get("url"),
+		Value: q.Get("value"),
+	}
+	if t := q.Get("types"); t != "" {
+		wr.Types = strings.Split(t, ",")
+	}
+	ctx := req.Context()
+	c, e := r.api.WatchContext(ctx, wr)
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(e.Error()))
+		return
+	}
+	rc := http.NewResponseController(w)
+	// the stream outlives the server's write timeout
+	rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // e.g. nginx would hold events back
+	w.WriteHeader(http.StatusOK)
+	if e = rc.Flush(); e != nil {
+		r.api.Logf(lib.LLERROR, "can't stream events: %v", e)
+		return
+	}
+
+	// changes are only sent for nodes the principal can read
+	p, _ := req.Context().Value(principalKey{}).(*principal)
+	all := p != nil && p.can(pb.RestAPIGrant_READ, "")
+	readable := make(map[string]bool)
+	canRead := func(id string) bool {
+		if all {
+			return true
+		}
+		if ok, seen := readable[id]; seen {
+			return ok
+		}
+		n, e := r.api.QueryRead(id)
+		ok := e == nil && n != nil && p != nil && p.canNode(pb.RestAPIGrant_READ, n)
+		readable[id] = ok
+		return ok
+	}
+
+	ka := time.NewTicker(watchKeepAlive)
+	defer ka.Stop()
+	seq := 0
+	for {
+		select {
+		case sc, ok := <-c:
+			if !ok {
+				return
+			}
+			if !canRead(sc.GetNode()) {
+				continue
+			}
+			seq++
+			b, _ := json.Marshal(sc)
+			_, e = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", seq, sc.GetType(), b)
+		case <-ka.C:
+			_, e = fmt.Fprint(w, ": keepalive\n\n")
+		case <-ctx.Done():
+			return
+		}
+		if e == nil {
+			e = rc.Flush()
+		}
+		if e != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+/* version.go: versioning of the ReST API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package restapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// apiV1 is the prefix of version 1 of the API.
+// Within a version, paths, parameters and the JSON names of fields and enum values only get added to, never renamed or removed.
+// Protos that are served by the API have to keep their JSON names, e.g. with the json_name option, if their fields are renamed.
+// Breaking changes get a new version; the old one stays, deprecated, for at least a release.
+const apiV1 = "/api/v1"
+
+// deprecated marks responses to the unversioned paths, which are aliases of v1, as deprecated, and points to their successor
+func deprecated(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+forwardedBase(req)+apiV1+req.URL.EscapedPath()+`>; rel="successor-version"`)
+		h.ServeHTTP(w, req)
+	})
+}
+
+// versionTemplate gets the path template of a route within its version, i.e. without apiV1
+func versionTemplate(t string) string {
+	return strings.TrimPrefix(t, apiV1)
+}
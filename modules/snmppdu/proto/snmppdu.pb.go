@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: snmppdu.proto
+
+package proto
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type SNMPPDUConfig struct {
+	Pdus                 map[string]*SNMPPDU    `protobuf:"bytes,1,rep,name=pdus,proto3" json:"pdus,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Outlets              map[string]*SNMPOutlet `protobuf:"bytes,2,rep,name=outlets,proto3" json:"outlets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	PollingInterval      string                 `protobuf:"bytes,3,opt,name=polling_interval,json=pollingInterval,proto3" json:"polling_interval,omitempty"`
+	NameUrl              string                 `protobuf:"bytes,4,opt,name=name_url,json=nameUrl,proto3" json:"name_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *SNMPPDUConfig) Reset()         { *m = SNMPPDUConfig{} }
+func (m *SNMPPDUConfig) String() string { return proto.CompactTextString(m) }
+func (*SNMPPDUConfig) ProtoMessage()    {}
+func (*SNMPPDUConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_snmppdu_21a5eb0f0d3e2123, []int{0}
+}
+func (m *SNMPPDUConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SNMPPDUConfig.Unmarshal(m, b)
+}
+func (m *SNMPPDUConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SNMPPDUConfig.Marshal(b, m, deterministic)
+}
+func (dst *SNMPPDUConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SNMPPDUConfig.Merge(dst, src)
+}
+func (m *SNMPPDUConfig) XXX_Size() int {
+	return xxx_messageInfo_SNMPPDUConfig.Size(m)
+}
+func (m *SNMPPDUConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_SNMPPDUConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SNMPPDUConfig proto.InternalMessageInfo
+
+func (m *SNMPPDUConfig) GetPdus() map[string]*SNMPPDU {
+	if m != nil {
+		return m.Pdus
+	}
+	return nil
+}
+
+func (m *SNMPPDUConfig) GetOutlets() map[string]*SNMPOutlet {
+	if m != nil {
+		return m.Outlets
+	}
+	return nil
+}
+
+func (m *SNMPPDUConfig) GetPollingInterval() string {
+	if m != nil {
+		return m.PollingInterval
+	}
+	return ""
+}
+
+func (m *SNMPPDUConfig) GetNameUrl() string {
+	if m != nil {
+		return m.NameUrl
+	}
+	return ""
+}
+
+type SNMPPDU struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port                 int32    `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	ReadCommunity        string   `protobuf:"bytes,3,opt,name=read_community,json=readCommunity,proto3" json:"read_community,omitempty"`
+	WriteCommunity       string   `protobuf:"bytes,4,opt,name=write_community,json=writeCommunity,proto3" json:"write_community,omitempty"`
+	Type                 string   `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SNMPPDU) Reset()         { *m = SNMPPDU{} }
+func (m *SNMPPDU) String() string { return proto.CompactTextString(m) }
+func (*SNMPPDU) ProtoMessage()    {}
+func (*SNMPPDU) Descriptor() ([]byte, []int) {
+	return fileDescriptor_snmppdu_21a5eb0f0d3e2123, []int{1}
+}
+func (m *SNMPPDU) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SNMPPDU.Unmarshal(m, b)
+}
+func (m *SNMPPDU) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SNMPPDU.Marshal(b, m, deterministic)
+}
+func (dst *SNMPPDU) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SNMPPDU.Merge(dst, src)
+}
+func (m *SNMPPDU) XXX_Size() int {
+	return xxx_messageInfo_SNMPPDU.Size(m)
+}
+func (m *SNMPPDU) XXX_DiscardUnknown() {
+	xxx_messageInfo_SNMPPDU.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SNMPPDU proto.InternalMessageInfo
+
+func (m *SNMPPDU) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *SNMPPDU) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *SNMPPDU) GetReadCommunity() string {
+	if m != nil {
+		return m.ReadCommunity
+	}
+	return ""
+}
+
+func (m *SNMPPDU) GetWriteCommunity() string {
+	if m != nil {
+		return m.WriteCommunity
+	}
+	return ""
+}
+
+func (m *SNMPPDU) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type SNMPOutlet struct {
+	Pdu                  string   `protobuf:"bytes,1,opt,name=pdu,proto3" json:"pdu,omitempty"`
+	Outlet               int32    `protobuf:"varint,2,opt,name=outlet,proto3" json:"outlet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SNMPOutlet) Reset()         { *m = SNMPOutlet{} }
+func (m *SNMPOutlet) String() string { return proto.CompactTextString(m) }
+func (*SNMPOutlet) ProtoMessage()    {}
+func (*SNMPOutlet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_snmppdu_21a5eb0f0d3e2123, []int{2}
+}
+func (m *SNMPOutlet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SNMPOutlet.Unmarshal(m, b)
+}
+func (m *SNMPOutlet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SNMPOutlet.Marshal(b, m, deterministic)
+}
+func (dst *SNMPOutlet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SNMPOutlet.Merge(dst, src)
+}
+func (m *SNMPOutlet) XXX_Size() int {
+	return xxx_messageInfo_SNMPOutlet.Size(m)
+}
+func (m *SNMPOutlet) XXX_DiscardUnknown() {
+	xxx_messageInfo_SNMPOutlet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SNMPOutlet proto.InternalMessageInfo
+
+func (m *SNMPOutlet) GetPdu() string {
+	if m != nil {
+		return m.Pdu
+	}
+	return ""
+}
+
+func (m *SNMPOutlet) GetOutlet() int32 {
+	if m != nil {
+		return m.Outlet
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SNMPPDUConfig)(nil), "proto.SNMPPDUConfig")
+	proto.RegisterMapType((map[string]*SNMPPDU)(nil), "proto.SNMPPDUConfig.PdusEntry")
+	proto.RegisterMapType((map[string]*SNMPOutlet)(nil), "proto.SNMPPDUConfig.OutletsEntry")
+	proto.RegisterType((*SNMPPDU)(nil), "proto.SNMPPDU")
+	proto.RegisterType((*SNMPOutlet)(nil), "proto.SNMPOutlet")
+}
+
+func init() { proto.RegisterFile("snmppdu.proto", fileDescriptor_snmppdu_21a5eb0f0d3e2123) }
+
+var fileDescriptor_snmppdu_21a5eb0f0d3e2123 = []byte{
+	// 342 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0xcd, 0x4e, 0xe3, 0x30,
+	0x18, 0x54, 0xd2, 0xa4, 0xdd, 0x7e, 0xdd, 0xfe, 0xac, 0x0f, 0x2b, 0xd3, 0x03, 0x2a, 0x15, 0xa8,
+	0xe5, 0xd2, 0x43, 0x91, 0x10, 0x82, 0x63, 0x41, 0x88, 0x43, 0xa1, 0x0a, 0xea, 0xb9, 0x0a, 0xc4,
+	0x94, 0x08, 0xc7, 0xb6, 0x1c, 0xbb, 0x28, 0x0f, 0xc2, 0xeb, 0xf1, 0x2c, 0x28, 0xb6, 0x4b, 0x53,
+	0xa9, 0xa7, 0x8c, 0x27, 0x33, 0xe3, 0xf9, 0x3e, 0x43, 0x3b, 0x67, 0x99, 0x10, 0x89, 0x9e, 0x08,
+	0xc9, 0x15, 0x47, 0xa1, 0xf9, 0x0c, 0xbf, 0x7d, 0x68, 0x3f, 0x3f, 0xce, 0x17, 0x8b, 0xdb, 0xe5,
+	0x8c, 0xb3, 0xb7, 0x74, 0x8d, 0xa6, 0x10, 0x88, 0x44, 0xe7, 0xd8, 0x1b, 0xd4, 0xc6, 0xad, 0xe9,
+	0xb1, 0x95, 0x4f, 0xf6, 0x34, 0x93, 0x45, 0xa2, 0xf3, 0x3b, 0xa6, 0x64, 0x11, 0x19, 0x2d, 0xba,
+	0x81, 0x06, 0xd7, 0x8a, 0x12, 0x95, 0x63, 0xdf, 0xd8, 0x4e, 0x0e, 0xda, 0x9e, 0xac, 0xc6, 0x3a,
+	0xb7, 0x0e, 0x74, 0x0e, 0x3d, 0xc1, 0x29, 0x4d, 0xd9, 0x7a, 0x95, 0x32, 0x45, 0xe4, 0x26, 0xa6,
+	0xb8, 0x36, 0xf0, 0xc6, 0xcd, 0xa8, 0xeb, 0xf8, 0x07, 0x47, 0xa3, 0x23, 0xf8, 0xc3, 0xe2, 0x8c,
+	0xac, 0xb4, 0xa4, 0x38, 0x30, 0x92, 0x46, 0x79, 0x5e, 0x4a, 0xda, 0xbf, 0x87, 0xe6, 0x6f, 0x2b,
+	0xd4, 0x83, 0xda, 0x07, 0x29, 0xb0, 0x67, 0x24, 0x25, 0x44, 0xa7, 0x10, 0x6e, 0x62, 0xaa, 0x09,
+	0xf6, 0x07, 0xde, 0xb8, 0x35, 0xed, 0xec, 0xf7, 0x8b, 0xec, 0xcf, 0x6b, 0xff, 0xca, 0xeb, 0xcf,
+	0xe1, 0x6f, 0xb5, 0xe7, 0x81, 0xac, 0xd1, 0x7e, 0xd6, 0xbf, 0x4a, 0x96, 0x75, 0x56, 0xe2, 0x86,
+	0x5f, 0x1e, 0x34, 0xdc, 0x2d, 0x08, 0x41, 0xf0, 0xce, 0x73, 0xe5, 0xb2, 0x0c, 0x2e, 0x39, 0xc1,
+	0xa5, 0x32, 0x59, 0x61, 0x64, 0x30, 0x3a, 0x83, 0x8e, 0x24, 0x71, 0xb2, 0x7a, 0xe5, 0x59, 0xa6,
+	0x59, 0xaa, 0x0a, 0xb7, 0x8f, 0x76, 0xc9, 0xce, 0xb6, 0x24, 0x1a, 0x41, 0xf7, 0x53, 0xa6, 0x8a,
+	0x54, 0x74, 0x76, 0x29, 0x1d, 0x43, 0xef, 0x84, 0x08, 0x02, 0x55, 0x08, 0x82, 0x43, 0x7b, 0x6f,
+	0x89, 0x87, 0x97, 0x00, 0xbb, 0xc2, 0xe5, 0x90, 0x22, 0xd1, 0xdb, 0x21, 0x45, 0xa2, 0xd1, 0x7f,
+	0xa8, 0xdb, 0x07, 0x72, 0xcd, 0xdc, 0xe9, 0xa5, 0x6e, 0x86, 0xbd, 0xf8, 0x19, 0x00, 0xf2, 0x85,
+	0x5b, 0x42, 0x4f, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,264 @@
+/* snmp.go: a minimal SNMPv2c client for integer GET/SET
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package snmppdu
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BER tags we use
+const (
+	berInteger     byte = 0x02
+	berOctetString byte = 0x04
+	berNull        byte = 0x05
+	berOID         byte = 0x06
+	berSequence    byte = 0x30
+
+	pduGetRequest  byte = 0xa0
+	pduGetResponse byte = 0xa2
+	pduSetRequest  byte = 0xa3
+
+	snmpVersion2c = 1
+	snmpTimeout   = 2 * time.Second
+	snmpRetries   = 3
+)
+
+// snmpGet reads an integer valued OID
+func snmpGet(addr, community, oid string) (int, error) {
+	return snmpRequest(context.Background(), addr, community, pduGetRequest, oid, nil)
+}
+
+// snmpSet writes an integer valued OID; it gives up if ctx is canceled
+func snmpSet(ctx context.Context, addr, community, oid string, v int) (e error) {
+	_, e = snmpRequest(ctx, addr, community, pduSetRequest, oid, &v)
+	return
+}
+
+func snmpRequest(ctx context.Context, addr, community string, ptype byte, oid string, v *int) (r int, e error) {
+	o, e := berEncodeOID(oid)
+	if e != nil {
+		return
+	}
+	val := berTLV(berNull, nil)
+	if v != nil {
+		val = berTLV(berInteger, berEncodeInt(*v))
+	}
+	reqID := rand.Int31()
+	vb := berTLV(berSequence, berTLV(berSequence, append(o, val...)))
+	pdu := berTLV(berInteger, berEncodeInt(int(reqID)))
+	pdu = append(pdu, berTLV(berInteger, berEncodeInt(0))...)
+	pdu = append(pdu, berTLV(berInteger, berEncodeInt(0))...)
+	pdu = append(pdu, vb...)
+	msg := berTLV(berInteger, berEncodeInt(snmpVersion2c))
+	msg = append(msg, berTLV(berOctetString, []byte(community))...)
+	msg = append(msg, berTLV(ptype, pdu)...)
+	msg = berTLV(berSequence, msg)
+
+	var d net.Dialer
+	conn, e := d.DialContext(ctx, "udp", addr)
+	if e != nil {
+		return
+	}
+	defer conn.Close()
+	// closing the connection fails the read we're waiting on
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	buf := make([]byte, 2048)
+	for i := 0; i < snmpRetries; i++ {
+		if _, e = conn.Write(msg); e != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(snmpTimeout))
+		for {
+			var n int
+			n, e = conn.Read(buf)
+			if e != nil {
+				break
+			}
+			var id int32
+			id, r, e = snmpParseResponse(buf[:n])
+			if e != nil || id == reqID {
+				return
+			}
+			// stale response, keep reading
+		}
+		if ne, ok := e.(net.Error); !ok || !ne.Timeout() {
+			return
+		}
+	}
+	return 0, fmt.Errorf("timed out waiting for SNMP response from %s", addr)
+}
+
+// snmpParseResponse extracts the request ID and the first (integer) value of a GetResponse
+func snmpParseResponse(b []byte) (id int32, v int, e error) {
+	msg, _, e := berNext(b, berSequence)
+	if e != nil {
+		return
+	}
+	_, msg, e = berNext(msg, berInteger) // version
+	if e != nil {
+		return
+	}
+	_, msg, e = berNext(msg, berOctetString) // community
+	if e != nil {
+		return
+	}
+	pdu, _, e := berNext(msg, pduGetResponse)
+	if e != nil {
+		return
+	}
+	var f []byte
+	if f, pdu, e = berNext(pdu, berInteger); e != nil {
+		return
+	}
+	id = int32(berDecodeInt(f))
+	if f, pdu, e = berNext(pdu, berInteger); e != nil {
+		return
+	}
+	if es := berDecodeInt(f); es != 0 {
+		return id, 0, fmt.Errorf("SNMP error status %d", es)
+	}
+	if _, pdu, e = berNext(pdu, berInteger); e != nil { // error index
+		return
+	}
+	vbl, _, e := berNext(pdu, berSequence)
+	if e != nil {
+		return
+	}
+	vb, _, e := berNext(vbl, berSequence)
+	if e != nil {
+		return
+	}
+	_, vb, e = berNext(vb, berOID)
+	if e != nil {
+		return
+	}
+	if len(vb) < 2 {
+		return id, 0, fmt.Errorf("short SNMP varbind")
+	}
+	switch vb[0] {
+	case berInteger, 0x41, 0x42: // INTEGER, Counter32, Gauge32
+		f, _, e = berNext(vb, vb[0])
+		v = berDecodeInt(f)
+	default: // noSuchObject, noSuchInstance, etc.
+		e = fmt.Errorf("SNMP varbind has non-integer type 0x%02x", vb[0])
+	}
+	return
+}
+
+////////////////////
+// BER encoding /
+//////////////////
+
+func berTLV(tag byte, v []byte) []byte {
+	r := []byte{tag}
+	l := len(v)
+	switch {
+	case l < 0x80:
+		r = append(r, byte(l))
+	case l < 0x100:
+		r = append(r, 0x81, byte(l))
+	default:
+		r = append(r, 0x82, byte(l>>8), byte(l))
+	}
+	return append(r, v...)
+}
+
+// berNext reads one TLV with the expected tag, returning its value and the remainder
+func berNext(b []byte, tag byte) (v, rest []byte, e error) {
+	if len(b) < 2 {
+		return nil, nil, fmt.Errorf("short BER data")
+	}
+	if b[0] != tag {
+		return nil, nil, fmt.Errorf("expected BER tag 0x%02x, got 0x%02x", tag, b[0])
+	}
+	l := int(b[1])
+	off := 2
+	if l&0x80 != 0 {
+		n := l & 0x7f
+		if n == 0 || n > 2 || len(b) < 2+n {
+			return nil, nil, fmt.Errorf("bad BER length")
+		}
+		l = 0
+		for _, c := range b[2 : 2+n] {
+			l = l<<8 | int(c)
+		}
+		off += n
+	}
+	if len(b) < off+l {
+		return nil, nil, fmt.Errorf("truncated BER data")
+	}
+	return b[off : off+l], b[off+l:], nil
+}
+
+func berEncodeInt(v int) []byte {
+	b := []byte{byte(v)}
+	for {
+		last := b[0]
+		v >>= 8
+		// stop once the remaining bits are pure sign extension
+		if (v == 0 && last&0x80 == 0) || (v == -1 && last&0x80 != 0) {
+			return b
+		}
+		b = append([]byte{byte(v)}, b...)
+	}
+}
+
+func berDecodeInt(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	v := int(int8(b[0]))
+	for _, c := range b[1:] {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func berEncodeOID(oid string) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid OID: %s", oid)
+	}
+	arcs := make([]uint64, len(parts))
+	for i, p := range parts {
+		a, e := strconv.ParseUint(p, 10, 32)
+		if e != nil {
+			return nil, fmt.Errorf("invalid OID: %s", oid)
+		}
+		arcs[i] = a
+	}
+	b := berEncodeArc(arcs[0]*40 + arcs[1])
+	for _, a := range arcs[2:] {
+		b = append(b, berEncodeArc(a)...)
+	}
+	return berTLV(berOID, b), nil
+}
+
+func berEncodeArc(a uint64) []byte {
+	b := []byte{byte(a & 0x7f)}
+	for a >>= 7; a > 0; a >>= 7 {
+		b = append([]byte{byte(a&0x7f) | 0x80}, b...)
+	}
+	return b
+}
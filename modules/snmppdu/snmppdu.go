@@ -0,0 +1,470 @@
+/* snmppdu.go: mutations for nodes powered through SNMP managed PDU outlets
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/snmppdu.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = snmppdu.
+ * Nodes are mapped to a PDU and outlet by the value found at NameUrl.
+ */
+
+package snmppdu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/snmppdu/proto"
+)
+
+const (
+	PlatformString string = "snmppdu"
+	DefaultPort    int32  = 161
+)
+
+// pduProfile describes how to read and switch outlets on a type of PDU
+// all OIDs get the outlet number appended
+type pduProfile struct {
+	statusOID string
+	statusOn  int
+	statusOff int
+	onOID     string
+	onValue   int
+	offOID    string
+	offValue  int
+}
+
+var profiles = map[string]pduProfile{
+	// PowerNet-MIB rPDUOutletStatusOutletState & rPDUOutletControlOutletCommand
+	"apc": {
+		statusOID: ".1.3.6.1.4.1.318.1.1.12.3.5.1.1.4",
+		statusOn:  1,
+		statusOff: 2,
+		onOID:     ".1.3.6.1.4.1.318.1.1.12.3.3.1.1.4",
+		onValue:   1, // immediateOn
+		offOID:    ".1.3.6.1.4.1.318.1.1.12.3.3.1.1.4",
+		offValue:  2, // immediateOff
+	},
+	// EATON-EPDU-MIB outletControlStatus, outletControlOnCmd & outletControlOffCmd (first unit)
+	"eaton": {
+		statusOID: ".1.3.6.1.4.1.534.6.6.7.6.6.1.2.0",
+		statusOn:  1,
+		statusOff: 0,
+		onOID:     ".1.3.6.1.4.1.534.6.6.7.6.6.1.4.0",
+		onValue:   0, // seconds of delay
+		offOID:    ".1.3.6.1.4.1.534.6.6.7.6.6.1.3.0",
+		offValue:  0,
+	},
+}
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "10s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "10s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// SNMPPDU Object /
+//////////////////
+
+// SNMPPDU provides a power on/off interface to SNMP managed PDUs
+type SNMPPDU struct {
+	api        lib.APIClient
+	cfg        *pb.SNMPPDUConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*SNMPPDU)(nil)
+
+// Name returns the FQDN of the module
+func (*SNMPPDU) Name() string { return "github.com/hpc/kraken/modules/snmppdu" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*SNMPPDU)(nil)
+var _ lib.ModuleWithConfigValidation = (*SNMPPDU)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*SNMPPDU) NewConfig() proto.Message {
+	r := &pb.SNMPPDUConfig{
+		NameUrl: "/Nodename",
+		Pdus: map[string]*pb.SNMPPDU{
+			"pdu0": {
+				Host:           "localhost",
+				Port:           DefaultPort,
+				ReadCommunity:  "public",
+				WriteCommunity: "private",
+				Type:           "apc",
+			},
+		},
+		Outlets:         map[string]*pb.SNMPOutlet{},
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (sp *SNMPPDU) UpdateConfig(cfg proto.Message) (e error) {
+	if spcfg, ok := cfg.(*pb.SNMPPDUConfig); ok {
+		sp.cfg = spcfg
+		if sp.pollTicker != nil {
+			sp.pollTicker.Stop()
+			dur, _ := time.ParseDuration(sp.cfg.GetPollingInterval())
+			sp.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks the polling interval, each PDU's host, port and type, and that outlets are on known PDUs
+func (*SNMPPDU) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.SNMPPDUConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	for name, pdu := range c.GetPdus() {
+		if pdu.GetHost() == "" {
+			return fmt.Errorf("PDU %s has no host", name)
+		}
+		if pdu.GetPort() < 0 || pdu.GetPort() > 65535 {
+			return fmt.Errorf("PDU %s has an invalid port: %d", name, pdu.GetPort())
+		}
+		if _, ok := profiles[pdu.GetType()]; !ok {
+			return fmt.Errorf("PDU %s has unknown type: %s", name, pdu.GetType())
+		}
+	}
+	for name, o := range c.GetOutlets() {
+		if _, ok := c.GetPdus()[o.GetPdu()]; !ok {
+			return fmt.Errorf("outlet %s is on an unknown PDU: %s", name, o.GetPdu())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*SNMPPDU) ConfigURL() string {
+	cfg := &pb.SNMPPDUConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*SNMPPDU)(nil)
+var _ lib.ModuleWithDiscovery = (*SNMPPDU)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (sp *SNMPPDU) SetMutationChan(c <-chan lib.Event) { sp.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (sp *SNMPPDU) SetDiscoveryChan(c chan<- lib.Event) { sp.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*SNMPPDU)(nil)
+
+// Entry is the module's executable entrypoint
+func (sp *SNMPPDU) Entry() {
+	url := lib.NodeURLJoin(sp.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "snmppdu"), "State"))
+	sp.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  sp.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(sp.cfg.GetPollingInterval())
+	sp.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-sp.pollTicker.C:
+			go sp.discoverAll()
+			break
+		case m := <-sp.mchan: // mutation request
+			go sp.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (sp *SNMPPDU) Init(api lib.APIClient) {
+	sp.api = api
+	sp.cfg = sp.NewConfig().(*pb.SNMPPDUConfig)
+}
+
+// Stop should perform a graceful exit
+func (sp *SNMPPDU) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (sp *SNMPPDU) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		sp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	// extract the mutating node's name
+	vs := me.NodeCfg.GetValues([]string{sp.cfg.GetNameUrl()})
+	if len(vs) != 1 {
+		sp.api.Logf(lib.LLERROR, "could not get outlet name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := vs[sp.cfg.GetNameUrl()].String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			sp.outletDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			sp.outletSet(me.Context, name, true, me.NodeCfg.ID())
+		case "ONtoOFF":
+			sp.outletSet(me.Context, name, false, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			sp.outletSet(me.Context, name, false, me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			sp.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request in progress
+		break
+	}
+}
+
+// outlet resolves a node name to its PDU, outlet number and PDU profile
+func (sp *SNMPPDU) outlet(name string) (pdu *pb.SNMPPDU, outlet string, prof pduProfile, e error) {
+	o, ok := sp.cfg.Outlets[name]
+	if !ok {
+		e = fmt.Errorf("no outlet configured for node: %s", name)
+		return
+	}
+	if pdu, ok = sp.cfg.Pdus[o.Pdu]; !ok {
+		e = fmt.Errorf("node %s references unknown PDU: %s", name, o.Pdu)
+		return
+	}
+	if prof, ok = profiles[pdu.Type]; !ok {
+		e = fmt.Errorf("PDU %s has unknown type: %s", o.Pdu, pdu.Type)
+		return
+	}
+	outlet = strconv.Itoa(int(o.Outlet))
+	return
+}
+
+func pduAddr(pdu *pb.SNMPPDU) string {
+	port := pdu.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return pdu.Host + ":" + strconv.Itoa(int(port))
+}
+
+func (sp *SNMPPDU) outletDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	pdu, outlet, prof, e := sp.outlet(name)
+	if e != nil {
+		sp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	v, e := snmpGet(pduAddr(pdu), pdu.ReadCommunity, prof.statusOID+"."+outlet)
+	if e != nil {
+		sp.api.Logf(lib.LLERROR, "failed to read outlet state for %s: %v", name, e)
+		return
+	}
+	var vid string
+	switch v {
+	case prof.statusOn:
+		vid = "POWER_ON"
+	case prof.statusOff:
+		vid = "POWER_OFF"
+	default:
+		vid = "PHYS_UNKNOWN"
+	}
+	sp.discover(id, vid, start)
+}
+
+// outletSet switches a node's outlet, and reports state on success
+// if the mutation is interrupted, we report whatever state the outlet was left in instead
+func (sp *SNMPPDU) outletSet(ctx context.Context, name string, on bool, id lib.NodeID) {
+	pdu, outlet, prof, e := sp.outlet(name)
+	if e != nil {
+		sp.api.Logf(lib.LLERROR, "%v", e)
+		return
+	}
+	oid, v, vid := prof.offOID, prof.offValue, "POWER_OFF"
+	if on {
+		oid, v, vid = prof.onOID, prof.onValue, "POWER_ON"
+	}
+	if e = snmpSet(ctx, pduAddr(pdu), pdu.WriteCommunity, oid+"."+outlet, v); e != nil {
+		if ctx.Err() != nil {
+			sp.api.Logf(lib.LLINFO, "interrupted: switching outlet for %s", name)
+			sp.outletDiscover(name, id)
+			return
+		}
+		sp.api.Logf(lib.LLERROR, "failed to switch outlet for %s: %v", name, e)
+		return
+	}
+	sp.discover(id, vid, time.Now())
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (sp *SNMPPDU) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  sp.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	sp.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+func (sp *SNMPPDU) discoverAll() {
+	sp.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := sp.api.QueryReadAll()
+	if e != nil {
+		sp.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	for _, n := range ns {
+		vs := n.GetValues([]string{"/Platform", sp.cfg.GetNameUrl()})
+		if len(vs) != 2 {
+			sp.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete outlet info", n.ID().String())
+			continue
+		}
+		if vs["/Platform"].String() != PlatformString {
+			continue
+		}
+		// one small UDP exchange per outlet; PDUs handle this fine serially
+		sp.outletDiscover(vs[sp.cfg.GetNameUrl()].String(), n.ID())
+	}
+}
+
+// initialization
+func init() {
+	module := &SNMPPDU{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/snmppdu/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("snmppdu", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -17,6 +17,7 @@
 package vboxmanage
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -231,6 +232,7 @@ func (pp *VBM) handleMutation(m lib.Event) {
 		pp.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
 	}
 	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
 	// extract the mutating node's name and server
 	vs := me.NodeCfg.GetValues([]string{pp.cfg.GetNameUrl(), pp.cfg.GetServerUrl()})
 	if len(vs) != 2 {
@@ -244,13 +246,13 @@ func (pp *VBM) handleMutation(m lib.Event) {
 	case core.MutationEvent_MUTATE:
 		switch me.Mutation[1] {
 		case "UKtoOFF": // this just forces discovery
-			go pp.vmDiscover(srv, name, me.NodeCfg.ID())
+			pp.vmDiscover(srv, name, me.NodeCfg.ID())
 		case "OFFtoON":
-			go pp.vmOn(srv, name, me.NodeCfg.ID())
+			pp.vmOn(me.Context, srv, name, me.NodeCfg.ID())
 		case "ONtoOFF":
-			go pp.vmOff(srv, name, me.NodeCfg.ID())
+			pp.vmOff(me.Context, srv, name, me.NodeCfg.ID())
 		case "HANGtoOFF":
-			go pp.vmOff(srv, name, me.NodeCfg.ID())
+			pp.vmOff(me.Context, srv, name, me.NodeCfg.ID())
 			break
 		case "UKtoHANG": // we don't actually do this
 			fallthrough
@@ -259,7 +261,7 @@ func (pp *VBM) handleMutation(m lib.Event) {
 		}
 		break
 	case core.MutationEvent_INTERRUPT:
-		// nothing to do
+		// the mutation's context is canceled, which stops any request in progress
 		break
 	}
 }
@@ -325,7 +327,9 @@ func (pp *VBM) vmDiscover(srvName, name string, id lib.NodeID) {
 	pp.dchan <- v
 }
 
-func (pp *VBM) vmOn(srvName, name string, id lib.NodeID) {
+// vmOn asks the API server to power on a VM
+// if the mutation is interrupted, we report whatever state the VM was left in instead
+func (pp *VBM) vmOn(ctx context.Context, srvName, name string, id lib.NodeID) {
 	srv, ok := pp.cfg.Servers[srvName]
 	if !ok {
 		pp.api.Logf(lib.LLERROR, "cannot control power for unknown API server: %s", srvName)
@@ -334,8 +338,18 @@ func (pp *VBM) vmOn(srvName, name string, id lib.NodeID) {
 	addr := srv.Ip + ":" + strconv.Itoa(int(srv.Port))
 
 	url := "http://" + addr + VBMOn + "/" + name + "?type=headless"
-	resp, e := http.Get(url)
+	req, e := http.NewRequest(http.MethodGet, url, nil)
 	if e != nil {
+		pp.api.Logf(lib.LLERROR, "error creating api request: %v", e)
+		return
+	}
+	resp, e := http.DefaultClient.Do(req.WithContext(ctx))
+	if e != nil {
+		if ctx.Err() != nil {
+			pp.api.Logf(lib.LLINFO, "interrupted: powering on %s", name)
+			pp.vmDiscover(srvName, name, id)
+			return
+		}
 		pp.api.Logf(lib.LLERROR, "error dialing api: %v", e)
 		return
 	}
@@ -389,7 +403,9 @@ func (pp *VBM) vmOn(srvName, name string, id lib.NodeID) {
 	pp.dchan <- v
 }
 
-func (pp *VBM) vmOff(srvName, name string, id lib.NodeID) {
+// vmOff asks the API server to power off a VM
+// if the mutation is interrupted, we report whatever state the VM was left in instead
+func (pp *VBM) vmOff(ctx context.Context, srvName, name string, id lib.NodeID) {
 	srv, ok := pp.cfg.Servers[srvName]
 	if !ok {
 		pp.api.Logf(lib.LLERROR, "cannot control power for unknown API server: %s", srvName)
@@ -398,8 +414,18 @@ func (pp *VBM) vmOff(srvName, name string, id lib.NodeID) {
 	addr := srv.Ip + ":" + strconv.Itoa(int(srv.Port))
 
 	url := "http://" + addr + VBMOff + "/" + name + "/poweroff"
-	resp, e := http.Get(url)
+	req, e := http.NewRequest(http.MethodGet, url, nil)
 	if e != nil {
+		pp.api.Logf(lib.LLERROR, "error creating api request: %v", e)
+		return
+	}
+	resp, e := http.DefaultClient.Do(req.WithContext(ctx))
+	if e != nil {
+		if ctx.Err() != nil {
+			pp.api.Logf(lib.LLINFO, "interrupted: powering off %s", name)
+			pp.vmDiscover(srvName, name, id)
+			return
+		}
 		pp.api.Logf(lib.LLERROR, "error dialing api: %v", e)
 		return
 	}
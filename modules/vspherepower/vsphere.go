@@ -0,0 +1,212 @@
+/* vsphere.go: a minimal client for the vSphere Automation (REST) API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package vspherepower
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type vsClient struct {
+	url     string
+	user    string
+	pass    string
+	client  *http.Client
+	mutex   sync.Mutex
+	session string
+	ids     map[string]string // VM name -> VM ID
+}
+
+func newVSClient(u, user, pass string, insecure bool) *vsClient {
+	return &vsClient{
+		url:  strings.TrimRight(u, "/") + "/api",
+		user: user,
+		pass: pass,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		},
+		ids: make(map[string]string),
+	}
+}
+
+// login gets a session if we don't have one, or force is set
+func (c *vsClient) login(force bool) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !force && c.session != "" {
+		return c.session, nil
+	}
+	req, e := http.NewRequest(http.MethodPost, c.url+"/session", nil)
+	if e != nil {
+		return "", e
+	}
+	req.SetBasicAuth(c.user, c.pass)
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return "", e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 201 && resp.StatusCode != 200 {
+		return "", fmt.Errorf("login failed: %s", resp.Status)
+	}
+	// the session ID is returned as a bare JSON string
+	if e = json.NewDecoder(resp.Body).Decode(&c.session); e != nil {
+		return "", e
+	}
+	return c.session, nil
+}
+
+// do makes an authenticated request, logging in again once if the session has expired
+// the request is abandoned if ctx is canceled
+func (c *vsClient) do(ctx context.Context, method, path string, r interface{}) error {
+	for retry := 0; ; retry++ {
+		s, e := c.login(retry > 0)
+		if e != nil {
+			return e
+		}
+		req, e := http.NewRequest(method, c.url+path, nil)
+		if e != nil {
+			return e
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("vmware-api-session-id", s)
+		resp, e := c.client.Do(req)
+		if e != nil {
+			return e
+		}
+		rb, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == 401 && retry == 0 {
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			var ve struct {
+				ErrorType string `json:"error_type"`
+				Messages  []struct {
+					DefaultMessage string `json:"default_message"`
+				} `json:"messages"`
+			}
+			if json.Unmarshal(rb, &ve) == nil && ve.ErrorType != "" {
+				if len(ve.Messages) > 0 {
+					return fmt.Errorf("%s: %s", ve.ErrorType, ve.Messages[0].DefaultMessage)
+				}
+				return fmt.Errorf("%s", ve.ErrorType)
+			}
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		if r != nil && len(rb) > 0 {
+			return json.Unmarshal(rb, r)
+		}
+		return nil
+	}
+}
+
+type vsVM struct {
+	VM         string `json:"vm"`
+	Name       string `json:"name"`
+	PowerState string `json:"power_state"`
+}
+
+// List returns the power state of VMs, by name
+// if names is empty, every VM is listed
+func (c *vsClient) List(names ...string) (map[string]string, error) {
+	path := "/vcenter/vm"
+	if len(names) > 0 {
+		q := url.Values{"names": names}
+		path += "?" + q.Encode()
+	}
+	var r []vsVM
+	if e := c.do(context.Background(), http.MethodGet, path, &r); e != nil {
+		return nil, e
+	}
+	st := make(map[string]string)
+	c.mutex.Lock()
+	for _, v := range r {
+		st[v.Name] = v.PowerState
+		c.ids[v.Name] = v.VM
+	}
+	c.mutex.Unlock()
+	return st, nil
+}
+
+// id resolves a VM name to its ID
+func (c *vsClient) id(name string) (string, error) {
+	c.mutex.Lock()
+	id, ok := c.ids[name]
+	c.mutex.Unlock()
+	if ok {
+		return id, nil
+	}
+	if _, e := c.List(name); e != nil {
+		return "", e
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if id, ok = c.ids[name]; !ok {
+		return "", fmt.Errorf("no VM named %s", name)
+	}
+	return id, nil
+}
+
+// Power runs a power action on a VM: start, stop, reset or suspend
+func (c *vsClient) Power(ctx context.Context, name, action string) error {
+	id, e := c.id(name)
+	if e != nil {
+		return e
+	}
+	return c.do(ctx, http.MethodPost, "/vcenter/vm/"+url.PathEscape(id)+"/power?action="+action, nil)
+}
+
+// State returns a VM's power state, e.g. POWERED_ON
+func (c *vsClient) State(ctx context.Context, name string) (string, error) {
+	id, e := c.id(name)
+	if e != nil {
+		return "", e
+	}
+	var r struct {
+		State string `json:"state"`
+	}
+	e = c.do(ctx, http.MethodGet, "/vcenter/vm/"+url.PathEscape(id)+"/power", &r)
+	return r.State, e
+}
+
+// GuestReady reports whether VMware Tools in the guest can take a power operation
+func (c *vsClient) GuestReady(ctx context.Context, name string) (bool, error) {
+	id, e := c.id(name)
+	if e != nil {
+		return false, e
+	}
+	var r struct {
+		OperationsReady bool `json:"operations_ready"`
+	}
+	e = c.do(ctx, http.MethodGet, "/vcenter/vm/"+url.PathEscape(id)+"/guest/power", &r)
+	return r.OperationsReady, e
+}
+
+// GuestShutdown asks the guest OS to shut down through VMware Tools
+// it returns right away; the VM powers off when the guest is done
+func (c *vsClient) GuestShutdown(ctx context.Context, name string) error {
+	id, e := c.id(name)
+	if e != nil {
+		return e
+	}
+	return c.do(ctx, http.MethodPost, "/vcenter/vm/"+url.PathEscape(id)+"/guest/power?action=shutdown", nil)
+}
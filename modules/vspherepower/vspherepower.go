@@ -0,0 +1,477 @@
+/* vspherepower.go: mutations for VMware vSphere virtual machines
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/vspherepower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = vsphere.
+ */
+
+package vspherepower
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/vspherepower/proto"
+)
+
+const (
+	PlatformString string = "vsphere"
+
+	// transitionWait is how long we poll a VM for its target state after a power action
+	transitionWait = 60 * time.Second
+)
+
+// ppmut helps us succinctly define our mutations
+type ppmut struct {
+	f       cpb.Node_PhysState // from
+	t       cpb.Node_PhysState // to
+	timeout string             // timeout
+	// everything fails to PHYS_HANG
+}
+
+// our mutation definitions
+// also we discover anything we can migrate to
+var muts = map[string]ppmut{
+	"UKtoOFF": {
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "30s",
+	},
+	"OFFtoON": {
+		f:       cpb.Node_POWER_OFF,
+		t:       cpb.Node_POWER_ON,
+		timeout: "60s",
+	},
+	"ONtoOFF": {
+		f:       cpb.Node_POWER_ON,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "240s", // this should be more than shutdown_timeout + transitionWait
+	},
+	"HANGtoOFF": {
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_OFF,
+		timeout: "60s",
+	},
+	"HANGtoON": { // reset, so a hung VM doesn't have to go through OFF
+		f:       cpb.Node_PHYS_HANG,
+		t:       cpb.Node_POWER_ON,
+		timeout: "60s",
+	},
+	"UKtoHANG": { // this one should never happen; just making sure HANG gets connected in our graph
+		f:       cpb.Node_PHYS_UNKNOWN,
+		t:       cpb.Node_PHYS_HANG,
+		timeout: "0s",
+	},
+}
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////////
+// VSphere Object /
+//////////////////
+
+// VSphere provides a power on/off/reset interface to vSphere VMs
+type VSphere struct {
+	api        lib.APIClient
+	cfg        *pb.VSphereConfig
+	mchan      <-chan lib.Event
+	dchan      chan<- lib.Event
+	pollTicker *time.Ticker
+	mutex      sync.Mutex
+	vs         *vsClient
+	vsCfg      *pb.VSphereConfig // the config vs was built from
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*VSphere)(nil)
+
+// Name returns the FQDN of the module
+func (*VSphere) Name() string { return "github.com/hpc/kraken/modules/vspherepower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*VSphere)(nil)
+var _ lib.ModuleWithConfigValidation = (*VSphere)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*VSphere) NewConfig() proto.Message {
+	r := &pb.VSphereConfig{
+		NameUrl:         "/Nodename",
+		ShutdownTimeout: "120s",
+		PollingInterval: "30s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (vs *VSphere) UpdateConfig(cfg proto.Message) (e error) {
+	if vcfg, ok := cfg.(*pb.VSphereConfig); ok {
+		vs.cfg = vcfg
+		if vs.pollTicker != nil {
+			vs.pollTicker.Stop()
+			dur, _ := time.ParseDuration(vs.cfg.GetPollingInterval())
+			vs.pollTicker = time.NewTicker(dur)
+		}
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig wants an http(s) vCenter URL, and a positive poll interval and shutdown timeout
+func (*VSphere) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.VSphereConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if dur, e := time.ParseDuration(c.GetPollingInterval()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid polling interval: %q", c.GetPollingInterval())
+	}
+	if u, e := url.Parse(c.GetUrl()); e != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid vCenter URL: %q", c.GetUrl())
+	}
+	if dur, e := time.ParseDuration(c.GetShutdownTimeout()); e != nil || dur <= 0 {
+		return fmt.Errorf("invalid shutdown timeout: %q", c.GetShutdownTimeout())
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*VSphere) ConfigURL() string {
+	cfg := &pb.VSphereConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*VSphere)(nil)
+var _ lib.ModuleWithDiscovery = (*VSphere)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (vs *VSphere) SetMutationChan(c <-chan lib.Event) { vs.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (vs *VSphere) SetDiscoveryChan(c chan<- lib.Event) { vs.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*VSphere)(nil)
+
+// Entry is the module's executable entrypoint
+func (vs *VSphere) Entry() {
+	url := lib.NodeURLJoin(vs.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "vspherepower"), "State"))
+	vs.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  vs.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+	// setup a ticker for polling discovery
+	dur, _ := time.ParseDuration(vs.cfg.GetPollingInterval())
+	vs.pollTicker = time.NewTicker(dur)
+
+	// main loop
+	for {
+		select {
+		case <-vs.pollTicker.C:
+			go vs.discoverAll()
+			break
+		case m := <-vs.mchan: // mutation request
+			go vs.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (vs *VSphere) Init(api lib.APIClient) {
+	vs.api = api
+	vs.cfg = vs.NewConfig().(*pb.VSphereConfig)
+}
+
+// Stop should perform a graceful exit
+func (vs *VSphere) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (vs *VSphere) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		vs.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	v, e := me.NodeCfg.GetValue(vs.cfg.GetNameUrl())
+	if e != nil || v.String() == "" {
+		vs.api.Logf(lib.LLERROR, "could not get VM name for node: %s", me.NodeCfg.ID().String())
+		return
+	}
+	name := v.String()
+	// mutation switch
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		switch me.Mutation[1] {
+		case "UKtoOFF": // this just forces discovery
+			vs.vmDiscover(name, me.NodeCfg.ID())
+		case "OFFtoON":
+			vs.vmControl(me.Context, name, "start", "POWERED_ON", me.NodeCfg.ID())
+		case "ONtoOFF":
+			vs.vmShutdown(me.Context, name, me.NodeCfg.ID())
+		case "HANGtoOFF":
+			vs.vmControl(me.Context, name, "stop", "POWERED_OFF", me.NodeCfg.ID())
+		case "HANGtoON":
+			vs.vmControl(me.Context, name, "reset", "POWERED_ON", me.NodeCfg.ID())
+			break
+		case "UKtoHANG": // we don't actually do this
+			fallthrough
+		default:
+			vs.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+		}
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any request or wait in progress
+		break
+	}
+}
+
+// client returns the vSphere client, creating it if the config has changed
+func (vs *VSphere) client() *vsClient {
+	vs.mutex.Lock()
+	defer vs.mutex.Unlock()
+	if vs.vs == nil || vs.vsCfg != vs.cfg {
+		vs.vs = newVSClient(vs.cfg.GetUrl(), vs.cfg.GetUsername(), vs.cfg.GetPassword(), vs.cfg.GetInsecure())
+		vs.vsCfg = vs.cfg
+	}
+	return vs.vs
+}
+
+// vmState maps a vSphere power state to a PhysState value ID
+// a suspended VM isn't running anything, so we call it off; start resumes it
+func vmState(s string) string {
+	switch s {
+	case "POWERED_ON":
+		return "POWER_ON"
+	case "POWERED_OFF", "SUSPENDED":
+		return "POWER_OFF"
+	}
+	return "PHYS_UNKNOWN"
+}
+
+func (vs *VSphere) vmDiscover(name string, id lib.NodeID) {
+	start := time.Now()
+	s, e := vs.client().State(context.Background(), name)
+	if e != nil {
+		vs.api.Logf(lib.LLERROR, "failed to get power state of VM %s: %v", name, e)
+		return
+	}
+	vs.discover(id, vmState(s), start)
+}
+
+// vmControl runs a power action on a VM, and waits for it to reach target
+// if the mutation is interrupted, we report whatever state the VM was left in instead
+func (vs *VSphere) vmControl(ctx context.Context, name, action, target string, id lib.NodeID) {
+	if e := vs.client().Power(ctx, name, action); e != nil {
+		if ctx.Err() != nil {
+			vs.api.Logf(lib.LLINFO, "interrupted: %s of VM %s", action, name)
+			vs.vmDiscover(name, id)
+			return
+		}
+		vs.api.Logf(lib.LLERROR, "failed to %s VM %s: %v", action, name, e)
+		return
+	}
+	if vs.vmWait(ctx, name, target, transitionWait) {
+		vs.discover(id, vmState(target), time.Now())
+	} else if ctx.Err() != nil {
+		vs.api.Logf(lib.LLINFO, "interrupted: waiting for VM %s to reach %s", name, target)
+		vs.vmDiscover(name, id)
+	}
+}
+
+// vmShutdown tries a guest shutdown through VMware Tools first
+// if the tools aren't running, or the guest takes longer than shutdown_timeout, we power off
+func (vs *VSphere) vmShutdown(ctx context.Context, name string, id lib.NodeID) {
+	c := vs.client()
+	ready, e := c.GuestReady(ctx, name)
+	if e != nil {
+		vs.api.Logf(lib.LLDEBUG, "failed to get guest power state of VM %s: %v", name, e)
+	}
+	if ready {
+		if e = c.GuestShutdown(ctx, name); e == nil {
+			wait, _ := time.ParseDuration(vs.cfg.GetShutdownTimeout())
+			if vs.vmWait(ctx, name, "POWERED_OFF", wait) {
+				vs.discover(id, "POWER_OFF", time.Now())
+				return
+			}
+		} else {
+			vs.api.Logf(lib.LLINFO, "guest shutdown of VM %s failed: %v", name, e)
+		}
+	}
+	if ctx.Err() != nil {
+		vs.api.Logf(lib.LLINFO, "interrupted: shutdown of VM %s", name)
+		vs.vmDiscover(name, id)
+		return
+	}
+	vs.api.Logf(lib.LLINFO, "VM %s did not shut down cleanly, powering off", name)
+	vs.vmControl(ctx, name, "stop", "POWERED_OFF", id)
+}
+
+// vmWait polls a VM until it reaches target, or ctx is canceled
+func (vs *VSphere) vmWait(ctx context.Context, name, target string, wait time.Duration) bool {
+	c := vs.client()
+	deadline := time.Now().Add(wait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return false
+		}
+		s, e := c.State(ctx, name)
+		if e != nil {
+			vs.api.Logf(lib.LLDEBUG, "failed to get power state of VM %s: %v", name, e)
+			continue
+		}
+		if s == target {
+			return true
+		}
+	}
+	vs.api.Logf(lib.LLERROR, "VM %s did not reach %s after %s", name, target, wait.String())
+	return false
+}
+
+// discover reports that id's power state was vid at at; the zero time means now
+func (vs *VSphere) discover(id lib.NodeID, vid string, at time.Time) {
+	url := lib.NodeURLJoin(id.String(), "/PhysState")
+	v := core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  vs.Name(),
+			URL:     url,
+			ValueID: vid,
+			Time:    at,
+		},
+	)
+	vs.dchan <- v
+}
+
+// discoverAll is used to do polling discovery of power state
+// one list call gets the power state of every VM
+func (vs *VSphere) discoverAll() {
+	vs.api.Log(lib.LLDEBUG, "polling for node state")
+	ns, e := vs.api.QueryReadAll()
+	if e != nil {
+		vs.api.Logf(lib.LLERROR, "polling node query failed: %v", e)
+		return
+	}
+	vms := make(map[string]lib.NodeID)
+
+	// build list
+	for _, n := range ns {
+		vals := n.GetValues([]string{"/Platform", vs.cfg.GetNameUrl()})
+		if len(vals) != 2 {
+			vs.api.Logf(lib.LLDEBUG, "skipping node %s, doesn't have complete VM info", n.ID().String())
+			continue
+		}
+		if vals["/Platform"].String() != PlatformString {
+			continue
+		}
+		vms[vals[vs.cfg.GetNameUrl()].String()] = n.ID()
+	}
+	if len(vms) == 0 {
+		return
+	}
+
+	start := time.Now()
+	st, e := vs.client().List()
+	if e != nil {
+		vs.api.Logf(lib.LLERROR, "failed to list VMs: %v", e)
+		return
+	}
+	for name, id := range vms {
+		s, ok := st[name]
+		if !ok {
+			vs.api.Logf(lib.LLERROR, "VM %s not found", name)
+			continue
+		}
+		vs.discover(id, vmState(s), start)
+	}
+}
+
+// initialization
+func init() {
+	module := &VSphere{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+	drstate := make(map[string]reflect.Value)
+
+	for m := range muts {
+		dur, _ := time.ParseDuration(muts[m].timeout)
+		mutations[m] = core.NewStateMutation(
+			map[string][2]reflect.Value{
+				"/PhysState": {
+					reflect.ValueOf(muts[m].f),
+					reflect.ValueOf(muts[m].t),
+				},
+			},
+			reqs,
+			excs,
+			lib.StateMutationContext_CHILD,
+			dur,
+			[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+		)
+		drstate[cpb.Node_PhysState_name[int32(muts[m].t)]] = reflect.ValueOf(muts[m].t)
+	}
+	discovers["/PhysState"] = drstate
+	discovers["/PhysState"]["PHYS_UNKNOWN"] = reflect.ValueOf(cpb.Node_PHYS_UNKNOWN)
+	discovers["/RunState"] = map[string]reflect.Value{
+		"RUN_UK": reflect.ValueOf(cpb.Node_UNKNOWN),
+	}
+	discovers["/Services/vspherepower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("vspherepower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
@@ -0,0 +1,313 @@
+/* wolpower.go: powers nodes on with Wake-on-LAN magic packets
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+//go:generate protoc -I ../../core/proto/include -I proto --go_out=plugins=grpc:proto proto/wolpower.proto
+
+/*
+ * This module will manipulate the PhysState state field.
+ * It will be restricted to Platform = wol.
+ * Wake-on-LAN can only turn things on, so this module only provides OFFtoON.
+ * Something else (e.g. an OS level shutdown) needs to provide the way back to POWER_OFF.
+ */
+
+package wolpower
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hpc/kraken/core"
+	cpb "github.com/hpc/kraken/core/proto"
+	"github.com/hpc/kraken/extensions/IPv4"
+	"github.com/hpc/kraken/lib"
+	pb "github.com/hpc/kraken/modules/wolpower/proto"
+)
+
+const (
+	PlatformString string = "wol"
+)
+
+// modify these if you want different requires for mutations
+var reqs = map[string]reflect.Value{
+	"/Platform": reflect.ValueOf(PlatformString),
+}
+
+// modify this if you want excludes
+var excs = map[string]reflect.Value{}
+
+////////////////
+// WOL Object /
+//////////////
+
+// WOL powers nodes on with Wake-on-LAN
+type WOL struct {
+	api   lib.APIClient
+	cfg   *pb.WOLConfig
+	mchan <-chan lib.Event
+	dchan chan<- lib.Event
+}
+
+/*
+ *lib.Module
+ */
+var _ lib.Module = (*WOL)(nil)
+
+// Name returns the FQDN of the module
+func (*WOL) Name() string { return "github.com/hpc/kraken/modules/wolpower" }
+
+/*
+ * lib.ModuleWithConfig
+ */
+var _ lib.ModuleWithConfig = (*WOL)(nil)
+var _ lib.ModuleWithConfigValidation = (*WOL)(nil)
+
+// NewConfig returns a fully initialized default config
+func (*WOL) NewConfig() proto.Message {
+	r := &pb.WOLConfig{
+		MacUrl:      "type.googleapis.com/proto.IPv4OverEthernet/Ifaces/0/Eth/Mac",
+		IpUrl:       "type.googleapis.com/proto.IPv4OverEthernet/Ifaces/0/Ip/Ip",
+		Broadcast:   "255.255.255.255:9",
+		ConfirmPort: 0,
+		ConfirmWait: "300s",
+	}
+	return r
+}
+
+// UpdateConfig updates the running config
+func (w *WOL) UpdateConfig(cfg proto.Message) (e error) {
+	if wcfg, ok := cfg.(*pb.WOLConfig); ok {
+		w.cfg = wcfg
+		return
+	}
+	return fmt.Errorf("invalid config type")
+}
+
+// ValidateConfig checks a config before it's applied; magic packets need a host:port to go to, and confirming POWER_ON a port and a positive wait
+func (*WOL) ValidateConfig(cfg proto.Message) error {
+	c, ok := cfg.(*pb.WOLConfig)
+	if !ok {
+		return fmt.Errorf("invalid config type")
+	}
+	if host, port, e := net.SplitHostPort(c.GetBroadcast()); e != nil || host == "" {
+		return fmt.Errorf("invalid broadcast address: %q", c.GetBroadcast())
+	} else if p, e := strconv.Atoi(port); e != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid broadcast address: %q", c.GetBroadcast())
+	}
+	if c.GetConfirmPort() < 0 || c.GetConfirmPort() > 65535 {
+		return fmt.Errorf("invalid confirm port: %d", c.GetConfirmPort())
+	}
+	if c.GetConfirmPort() > 0 {
+		if dur, e := time.ParseDuration(c.GetConfirmWait()); e != nil || dur <= 0 {
+			return fmt.Errorf("invalid confirm wait: %q", c.GetConfirmWait())
+		}
+	}
+	return nil
+}
+
+// ConfigURL gives the any resolver URL for the config
+func (*WOL) ConfigURL() string {
+	cfg := &pb.WOLConfig{}
+	any, _ := ptypes.MarshalAny(cfg)
+	return any.GetTypeUrl()
+}
+
+/*
+ * lib.ModuleWithMutations & lib.ModuleWithDiscovery
+ */
+var _ lib.ModuleWithMutations = (*WOL)(nil)
+var _ lib.ModuleWithDiscovery = (*WOL)(nil)
+
+// SetMutationChan sets the current mutation channel
+// this is generally done by the API
+func (w *WOL) SetMutationChan(c <-chan lib.Event) { w.mchan = c }
+
+// SetDiscoveryChan sets the current discovery channel
+// this is generally done by the API
+func (w *WOL) SetDiscoveryChan(c chan<- lib.Event) { w.dchan = c }
+
+/*
+ * lib.ModuleSelfService
+ */
+var _ lib.ModuleSelfService = (*WOL)(nil)
+
+// Entry is the module's executable entrypoint
+func (w *WOL) Entry() {
+	url := lib.NodeURLJoin(w.api.Self().String(),
+		lib.URLPush(lib.URLPush("/Services", "wolpower"), "State"))
+	w.dchan <- core.NewEvent(
+		lib.Event_DISCOVERY,
+		url,
+		&core.DiscoveryEvent{
+			Module:  w.Name(),
+			URL:     url,
+			ValueID: "RUN",
+		},
+	)
+
+	// main loop
+	// there's no polling; we can't see power state over the network until the OS is up
+	for {
+		select {
+		case m := <-w.mchan: // mutation request
+			go w.handleMutation(m)
+			break
+		}
+	}
+}
+
+// Init is used to intialize an executable module prior to entrypoint
+func (w *WOL) Init(api lib.APIClient) {
+	w.api = api
+	w.cfg = w.NewConfig().(*pb.WOLConfig)
+}
+
+// Stop should perform a graceful exit
+func (w *WOL) Stop() {
+	os.Exit(0)
+}
+
+////////////////////////
+// Unexported methods /
+//////////////////////
+
+func (w *WOL) handleMutation(m lib.Event) {
+	if m.Type() != lib.Event_STATE_MUTATION {
+		w.api.Log(lib.LLINFO, "got an unexpected event type on mutation channel")
+	}
+	me := m.Data().(*core.MutationEvent)
+	defer me.Done()
+	switch me.Type {
+	case core.MutationEvent_MUTATE:
+		if me.Mutation[1] != "OFFtoON" {
+			w.api.Logf(lib.LLDEBUG, "unexpected event: %s", me.Mutation[1])
+			return
+		}
+		v, e := me.NodeCfg.GetValue(w.cfg.GetMacUrl())
+		if e != nil {
+			w.api.Logf(lib.LLERROR, "could not get MAC address for node %s: %v", me.NodeCfg.ID().String(), e)
+			return
+		}
+		mac := IPv4.BytesToMAC(v.Bytes())
+		if mac == nil {
+			w.api.Logf(lib.LLERROR, "node %s has an invalid MAC address", me.NodeCfg.ID().String())
+			return
+		}
+		if e = w.wake(mac); e != nil {
+			w.api.Logf(lib.LLERROR, "failed to send magic packet to %s: %v", mac.String(), e)
+			return
+		}
+		w.api.Logf(lib.LLDEBUG, "sent magic packet to %s", mac.String())
+		if w.cfg.GetConfirmPort() != 0 {
+			w.confirm(me.Context, me.NodeCfg)
+		}
+		// otherwise, whatever discovers POWER_ON for this node (e.g. a ping or SSH check) finishes the mutation
+		break
+	case core.MutationEvent_INTERRUPT:
+		// the mutation's context is canceled, which stops any wait for confirmation
+		break
+	}
+}
+
+// wake sends a magic packet for mac: six 0xff bytes, then the MAC sixteen times
+func (w *WOL) wake(mac net.HardwareAddr) (e error) {
+	pkt := append(bytes.Repeat([]byte{0xff}, 6), bytes.Repeat(mac, 16)...)
+	conn, e := net.Dial("udp", w.cfg.GetBroadcast())
+	if e != nil {
+		return
+	}
+	defer conn.Close()
+	_, e = conn.Write(pkt)
+	return
+}
+
+// confirm waits for the node to answer on confirm_port, then reports POWER_ON
+// we can't tell what state a node is in otherwise, so if ctx is canceled we just stop waiting
+func (w *WOL) confirm(ctx context.Context, n lib.Node) {
+	v, e := n.GetValue(w.cfg.GetIpUrl())
+	if e != nil {
+		w.api.Logf(lib.LLERROR, "could not get IP for node %s: %v", n.ID().String(), e)
+		return
+	}
+	ip := IPv4.BytesToIP(v.Bytes())
+	if ip == nil {
+		w.api.Logf(lib.LLERROR, "node %s has an invalid IP", n.ID().String())
+		return
+	}
+	addr := net.JoinHostPort(ip.String(), strconv.Itoa(int(w.cfg.GetConfirmPort())))
+	wait, _ := time.ParseDuration(w.cfg.GetConfirmWait())
+	deadline := time.Now().Add(wait)
+	d := net.Dialer{Timeout: 2 * time.Second}
+	for time.Now().Before(deadline) {
+		c, e := d.DialContext(ctx, "tcp", addr)
+		if e == nil {
+			c.Close()
+			url := lib.NodeURLJoin(n.ID().String(), "/PhysState")
+			w.dchan <- core.NewEvent(
+				lib.Event_DISCOVERY,
+				url,
+				&core.DiscoveryEvent{
+					Module:  w.Name(),
+					URL:     url,
+					ValueID: "POWER_ON",
+				},
+			)
+			return
+		}
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			w.api.Logf(lib.LLINFO, "interrupted: waiting for node %s to answer on %s", n.ID().String(), addr)
+			return
+		}
+	}
+	// the SME will fail us to PHYS_HANG
+	w.api.Logf(lib.LLERROR, "node %s did not answer on %s after %s", n.ID().String(), addr, wait.String())
+}
+
+// initialization
+func init() {
+	module := &WOL{}
+	mutations := make(map[string]lib.StateMutation)
+	discovers := make(map[string]map[string]reflect.Value)
+
+	mutations["OFFtoON"] = core.NewStateMutation(
+		map[string][2]reflect.Value{
+			"/PhysState": {
+				reflect.ValueOf(cpb.Node_POWER_OFF),
+				reflect.ValueOf(cpb.Node_POWER_ON),
+			},
+		},
+		reqs,
+		excs,
+		lib.StateMutationContext_CHILD,
+		time.Second*300, // booting far enough to answer takes a while
+		[3]string{module.Name(), "/PhysState", "PHYS_HANG"},
+	)
+	discovers["/PhysState"] = map[string]reflect.Value{
+		"POWER_ON":  reflect.ValueOf(cpb.Node_POWER_ON),
+		"PHYS_HANG": reflect.ValueOf(cpb.Node_PHYS_HANG),
+	}
+	discovers["/Services/wolpower/State"] = map[string]reflect.Value{
+		"RUN": reflect.ValueOf(cpb.ServiceInstance_RUN)}
+	si := core.NewServiceInstance("wolpower", module.Name(), module.Entry, nil)
+
+	// Register it all
+	core.Registry.RegisterModule(module)
+	core.Registry.RegisterServiceInstance(module, map[string]lib.ServiceInstance{si.ID(): si})
+	core.Registry.RegisterDiscoverable(module, discovers)
+	core.Registry.RegisterMutations(module, mutations)
+}
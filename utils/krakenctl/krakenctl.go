@@ -0,0 +1,546 @@
+/* krakenctl.go: a command line tool for common cluster operations through the Kraken ReST API
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hpc/kraken/core"
+	"github.com/hpc/kraken/modules/restapi"
+)
+
+const usage = `Usage: krakenctl [options] <command> [arguments]
+
+Commands:
+  power on|off|cycle [-group <group>] [-select <expr>] [<hostlist>]
+        power nodes on, off or cycle them, e.g. power on node[001-100]
+  state get [-dsc] <hostlist> <url>...
+        print nodes' values, e.g. state get node003 /PhysState
+  state set <hostlist> <url> <value>
+        set a value on nodes, e.g. state set node003 /Arch x86_64
+  discover [-module <module>] [-group <group>] [-select <expr>] [<hostlist>]
+        re-discover nodes now, rather than at the next poll, e.g. discover -module ipmipower node[001-010]
+  status <node>
+        show where a node is in the mutation graph, and why, e.g. why it's stuck
+  nodes ls [-dsc] [-filter <expr>] [-group <group>] [-sort <url>] [-fields <urls>] [-limit <n>]
+        list nodes, e.g. nodes ls -filter '/PhysState == POWER_OFF'
+  cfg apply <file.json>
+        create or update the node (or list of nodes) in a file, as for the ReST API
+  top [-interval <duration>] [-filter <expr>]
+        an interactive dashboard of live node state, that can power nodes and change their state
+
+Options:
+`
+
+// powerStates are the /PhysState values for power commands
+var powerStates = map[string]string{
+	"on":    "POWER_ON",
+	"off":   "POWER_OFF",
+	"cycle": "POWER_CYCLE",
+}
+
+// apiVersion is the prefix of the version of the ReST API we use
+const apiVersion = "/api/v1"
+
+// A client makes requests to the ReST API
+type client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// newClient creates a client for the ReST API at base, e.g. https://kraken:3141
+func newClient(base, token, cacert, cert, key string) (c *client, e error) {
+	c = &client{
+		base:  strings.TrimSuffix(base, "/"),
+		token: token,
+		http:  &http.Client{Timeout: 60 * time.Second},
+	}
+	if cacert == "" && cert == "" {
+		return
+	}
+	tc := &tls.Config{}
+	if cacert != "" {
+		pem, e := ioutil.ReadFile(cacert)
+		if e != nil {
+			return nil, e
+		}
+		tc.RootCAs = x509.NewCertPool()
+		if !tc.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cacert)
+		}
+	}
+	if cert != "" {
+		kp, e := tls.LoadX509KeyPair(cert, key)
+		if e != nil {
+			return nil, e
+		}
+		tc.Certificates = []tls.Certificate{kp}
+	}
+	c.http.Transport = &http.Transport{TLSClientConfig: tc}
+	return
+}
+
+// do makes a request, and gets the response body; responses other than 2xx are errors
+func (c *client) do(method, path string, body []byte) ([]byte, error) {
+	req, e := http.NewRequest(method, c.base+apiVersion+path, bytes.NewReader(body))
+	if e != nil {
+		return nil, e
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, e := c.http.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	defer rsp.Body.Close()
+	b, e := ioutil.ReadAll(rsp.Body)
+	if e != nil {
+		return nil, e
+	}
+	if rsp.StatusCode/100 != 2 {
+		msg := strings.TrimSpace(string(b))
+		if msg == "" {
+			msg = http.StatusText(rsp.StatusCode)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, msg)
+	}
+	return b, nil
+}
+
+// A value is one URL's value on a node
+type value struct {
+	url, val string
+}
+
+// fields gets the values at urls for every node in a list (e.g. /cfg/nodes), in the order of the list
+// The map of node ID to values is decoded a token at a time to keep the order.
+func (c *client) fields(path string, query url.Values, urls []string) (ids []string, vals map[string][]value, e error) {
+	query.Set("fields", strings.Join(urls, ","))
+	b, e := c.do("GET", path+"?"+query.Encode(), nil)
+	if e != nil {
+		return
+	}
+	vals = make(map[string][]value)
+	d := json.NewDecoder(bytes.NewReader(b))
+	if _, e = d.Token(); e != nil {
+		return
+	}
+	for d.More() {
+		t, e := d.Token()
+		if e != nil {
+			return nil, nil, e
+		}
+		id, _ := t.(string)
+		var m map[string]string
+		if e = d.Decode(&m); e != nil {
+			return nil, nil, e
+		}
+		ids = append(ids, id)
+		for u, v := range m {
+			vals[id] = append(vals[id], value{u, v})
+		}
+		sort.Slice(vals[id], func(i, j int) bool { return vals[id][i].url < vals[id][j].url })
+	}
+	return
+}
+
+// names gets the IDs of the nodes named by a hostlist, in the order of the hostlist; nodes can also be given by ID
+func (c *client) names(hostlist string) (ids []string, names map[string]string, e error) {
+	hs, e := core.ExpandHostlist(hostlist)
+	if e != nil {
+		return
+	}
+	all, vals, e := c.fields("/cfg/nodes", url.Values{}, []string{"/Nodename"})
+	if e != nil {
+		return
+	}
+	byName := make(map[string]string)
+	names = make(map[string]string)
+	for _, id := range all {
+		for _, v := range vals[id] {
+			if v.url == "/Nodename" && v.val != "" {
+				byName[v.val] = id
+				names[id] = v.val
+			}
+		}
+		byName[id] = id
+	}
+	for _, h := range hs {
+		id, ok := byName[h]
+		if !ok {
+			return nil, nil, fmt.Errorf("no such node: %s", h)
+		}
+		ids = append(ids, id)
+	}
+	return
+}
+
+// bulk makes a bulk change, and reports what happened to each node
+func (c *client) bulk(br restapi.BulkRequest) error {
+	b, _ := json.Marshal(br)
+	rb, e := c.do("POST", "/cfg/bulk", b)
+	if e != nil {
+		return e
+	}
+	var rsp restapi.BulkResponse
+	if e = json.Unmarshal(rb, &rsp); e != nil {
+		return e
+	}
+	failed := 0
+	for _, r := range rsp.Results {
+		name := r.Nodename
+		if name == "" {
+			name = r.ID
+		}
+		if r.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, r.Error)
+			failed++
+		}
+	}
+	fmt.Printf("changed %d of %d nodes\n", len(rsp.Results)-failed, len(rsp.Results))
+	if failed > 0 {
+		return fmt.Errorf("%d nodes could not be changed", failed)
+	}
+	return nil
+}
+
+func power(c *client, args []string) error {
+	fs := flag.NewFlagSet("power", flag.ExitOnError)
+	group := fs.String("group", "", "power the nodes in a group")
+	sel := fs.String("select", "", "power the nodes that match a selector expression")
+	if len(args) < 1 || powerStates[args[0]] == "" {
+		return fmt.Errorf("usage: power on|off|cycle [-group <group>] [-select <expr>] [<hostlist>]")
+	}
+	fs.Parse(args[1:])
+	br := restapi.BulkRequest{
+		Hosts:  strings.Join(fs.Args(), ","),
+		Group:  *group,
+		Select: *sel,
+		Set:    map[string]string{"/PhysState": powerStates[args[0]]},
+	}
+	if br.Hosts == "" && br.Group == "" && br.Select == "" {
+		return fmt.Errorf("no nodes given; use a hostlist, -group or -select")
+	}
+	return c.bulk(br)
+}
+
+func discover(c *client, args []string) error {
+	fs := flag.NewFlagSet("discover", flag.ExitOnError)
+	module := fs.String("module", "", "only ask this module to re-discover")
+	group := fs.String("group", "", "re-discover the nodes in a group")
+	sel := fs.String("select", "", "re-discover the nodes that match a selector expression")
+	fs.Parse(args)
+	dr := restapi.DiscoverRequest{
+		Module: *module,
+		Hosts:  strings.Join(fs.Args(), ","),
+		Group:  *group,
+		Select: *sel,
+	}
+	b, _ := json.Marshal(dr)
+	rb, e := c.do("POST", "/dsc/discover", b)
+	if e != nil {
+		return e
+	}
+	var rsp restapi.DiscoverResponse
+	if e = json.Unmarshal(rb, &rsp); e != nil {
+		return e
+	}
+	nodes := "all nodes"
+	if len(rsp.Nodes) > 0 {
+		nodes = fmt.Sprintf("%d nodes", len(rsp.Nodes))
+	}
+	if len(rsp.Services) == 0 {
+		return fmt.Errorf("no running modules can re-discover")
+	}
+	fmt.Printf("asked %s to re-discover %s\n", strings.Join(rsp.Services, ", "), nodes)
+	return nil
+}
+
+func state(c *client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: state get|set ...")
+	}
+	switch args[0] {
+	case "get":
+		fs := flag.NewFlagSet("state get", flag.ExitOnError)
+		dsc := fs.Bool("dsc", false, "get discovered values, rather than configured ones")
+		fs.Parse(args[1:])
+		if fs.NArg() < 2 {
+			return fmt.Errorf("usage: state get [-dsc] <hostlist> <url>...")
+		}
+		ids, names, e := c.names(fs.Arg(0))
+		if e != nil {
+			return e
+		}
+		path := "/cfg/nodes"
+		if *dsc {
+			path = "/dsc/nodes"
+		}
+		_, vals, e := c.fields(path, url.Values{}, fs.Args()[1:])
+		if e != nil {
+			return e
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		for _, id := range ids {
+			name := names[id]
+			if name == "" {
+				name = id
+			}
+			for _, v := range vals[id] {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", name, v.url, v.val)
+			}
+		}
+		return tw.Flush()
+	case "set":
+		if len(args) != 4 {
+			return fmt.Errorf("usage: state set <hostlist> <url> <value>")
+		}
+		return c.bulk(restapi.BulkRequest{Hosts: args[1], Set: map[string]string{args[2]: args[3]}})
+	}
+	return fmt.Errorf("unknown state command: %s", args[0])
+}
+
+func status(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: status <node>")
+	}
+	ids, _, e := c.names(args[0])
+	if e != nil {
+		return e
+	}
+	if len(ids) != 1 {
+		return fmt.Errorf("status is for one node at a time")
+	}
+	b, e := c.do("GET", "/graph/node/"+ids[0]+"/status", nil)
+	if e != nil {
+		return e
+	}
+	var st restapi.NodeStatus
+	if e = json.Unmarshal(b, &st); e != nil {
+		return e
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "node:\t%s (%s)\n", st.Nodename, st.ID)
+	fmt.Fprintf(tw, "status:\t%s\n", st.Status)
+	fmt.Fprintf(tw, "reason:\t%s\n", st.Reason)
+	tw.Flush()
+	if len(st.Diff) > 0 {
+		fmt.Println("differences (discovered -> configured):")
+		urls := []string{}
+		for u := range st.Diff {
+			urls = append(urls, u)
+		}
+		sort.Strings(urls)
+		for _, u := range urls {
+			fmt.Fprintf(tw, "  %s\t%s\n", u, st.Diff[u])
+		}
+		tw.Flush()
+	}
+	if st.Path != nil {
+		fmt.Println("mutation path:")
+		for i, me := range st.Path.GetChain() {
+			mark := " "
+			if int64(i) == st.Path.GetCur() {
+				mark = ">"
+			}
+			muts := []string{}
+			for u, m := range me.GetMutates() {
+				muts = append(muts, u+": "+m)
+			}
+			sort.Strings(muts)
+			fmt.Fprintf(tw, "%s %d.\t%s:%s\t%s\n", mark, i+1, me.GetModule(), me.GetMutation(), strings.Join(muts, ", "))
+		}
+		tw.Flush()
+	}
+	return nil
+}
+
+func nodes(c *client, args []string) error {
+	if len(args) < 1 || args[0] != "ls" {
+		return fmt.Errorf("usage: nodes ls [options]")
+	}
+	fs := flag.NewFlagSet("nodes ls", flag.ExitOnError)
+	dsc := fs.Bool("dsc", false, "list discovered state, rather than configured state")
+	filter := fs.String("filter", "", "only list nodes that match a selector expression, e.g. '/PhysState == POWER_OFF'")
+	group := fs.String("group", "", "only list the nodes in a group")
+	sortBy := fs.String("sort", "/Nodename", "sort by a URL; prefix it with - for descending order")
+	fields := fs.String("fields", "/Nodename,/PhysState,/RunState", "comma separated list of URLs to show")
+	limit := fs.Int("limit", 0, "list at most this many nodes")
+	fs.Parse(args[1:])
+	state := "cfg"
+	if *dsc {
+		state = "dsc"
+	}
+	path := "/" + state + "/nodes"
+	if *group != "" {
+		path = "/" + state + "/group/" + url.PathEscape(*group) + "/nodes"
+	}
+	q := url.Values{}
+	if *filter != "" {
+		q.Set("q", *filter)
+	}
+	if *sortBy != "" {
+		q.Set("sort", *sortBy)
+	}
+	if *limit > 0 {
+		q.Set("limit", fmt.Sprint(*limit))
+	}
+	urls := strings.Split(*fields, ",")
+	ids, vals, e := c.fields(path, q, urls)
+	if e != nil {
+		return e
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\t%s\n", strings.Join(urls, "\t"))
+	for _, id := range ids {
+		row := []string{id}
+		for _, u := range urls {
+			var vs []string
+			for _, v := range vals[id] {
+				// wildcards can match more than one URL
+				if v.url == u || (strings.Contains(u, "*") && matchURL(u, v.url)) {
+					vs = append(vs, v.val)
+				}
+			}
+			row = append(row, strings.Join(vs, ","))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// matchURL reports whether url matches pattern, where * in pattern matches one URL element
+func matchURL(pattern, url string) bool {
+	ps, us := strings.Split(pattern, "/"), strings.Split(url, "/")
+	if len(ps) != len(us) {
+		return false
+	}
+	for i := range ps {
+		if ps[i] != "*" && ps[i] != us[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func cfg(c *client, args []string) error {
+	if len(args) != 2 || args[0] != "apply" {
+		return fmt.Errorf("usage: cfg apply <file.json>")
+	}
+	b, e := ioutil.ReadFile(args[1])
+	if e != nil {
+		return e
+	}
+	var list struct {
+		Nodes []json.RawMessage `json:"nodes"`
+	}
+	if e = json.Unmarshal(b, &list); e != nil {
+		return fmt.Errorf("%s: %v", args[1], e)
+	}
+	if list.Nodes == nil {
+		list.Nodes = []json.RawMessage{b}
+	}
+	failed := 0
+	for _, n := range list.Nodes {
+		if e = c.apply(n); e != nil {
+			fmt.Fprintln(os.Stderr, e)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nodes could not be applied", failed, len(list.Nodes))
+	}
+	return nil
+}
+
+// apply creates a node, or updates it if it exists
+func (c *client) apply(n json.RawMessage) error {
+	var node struct {
+		ID       string `json:"id"`
+		Nodename string `json:"nodename"`
+	}
+	json.Unmarshal(n, &node)
+	bid, e := base64.StdEncoding.DecodeString(node.ID)
+	if e != nil || len(bid) != 16 {
+		return fmt.Errorf("node %q does not have a valid id", node.Nodename)
+	}
+	id := core.NewNodeIDFromBinary(bid).String()
+	method, did := "PUT", "updated"
+	if _, e := c.do("GET", "/cfg/node/"+id, nil); e != nil {
+		method, did = "POST", "created"
+	}
+	if _, e := c.do(method, "/cfg/node", n); e != nil {
+		return e
+	}
+	fmt.Printf("%s %s %s\n", did, id, node.Nodename)
+	return nil
+}
+
+func main() {
+	api := os.Getenv("KRAKEN_API")
+	if api == "" {
+		api = "http://127.0.0.1:3141"
+	}
+	flag.StringVar(&api, "api", api, "URL of the Kraken ReST API (or $KRAKEN_API)")
+	token := flag.String("token", os.Getenv("KRAKEN_TOKEN"), "bearer token for the ReST API (or $KRAKEN_TOKEN)")
+	cacert := flag.String("cacert", "", "PEM file of CAs to verify the ReST API's certificate with")
+	cert := flag.String("cert", "", "PEM client certificate file, for mTLS")
+	key := flag.String("key", "", "PEM private key file for -cert")
+	flag.Usage = func() {
+		io.WriteString(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	c, e := newClient(api, *token, *cacert, *cert, *key)
+	if e != nil {
+		fmt.Fprintln(os.Stderr, e)
+		os.Exit(1)
+	}
+	cmds := map[string]func(*client, []string) error{
+		"power":    power,
+		"state":    state,
+		"nodes":    nodes,
+		"cfg":      cfg,
+		"top":      top,
+		"status":   status,
+		"discover": discover,
+	}
+	cmd, ok := cmds[flag.Arg(0)]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if e = cmd(c, flag.Args()[1:]); e != nil {
+		fmt.Fprintln(os.Stderr, e)
+		os.Exit(1)
+	}
+}
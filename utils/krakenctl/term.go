@@ -0,0 +1,49 @@
+/* term.go: puts the terminal in raw mode for krakenctl top (see term_<os>.go for the ioctls)
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func ioctl(fd int, req uint, arg unsafe.Pointer) error {
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(req), uintptr(arg)); e != 0 {
+		return e
+	}
+	return nil
+}
+
+// makeRaw puts the terminal on fd in raw mode: keys are read as they're pressed, and aren't echoed.
+// It gets a function that puts the terminal back how it was.
+func makeRaw(fd int) (restore func(), e error) {
+	var old syscall.Termios
+	if e = ioctl(fd, ioctlGetTermios, unsafe.Pointer(&old)); e != nil {
+		return
+	}
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if e = ioctl(fd, ioctlSetTermios, unsafe.Pointer(&raw)); e != nil {
+		return
+	}
+	return func() { ioctl(fd, ioctlSetTermios, unsafe.Pointer(&old)) }, nil
+}
+
+// winSize gets the size of the terminal on fd
+func winSize(fd int) (width, height int, e error) {
+	var ws struct{ row, col, x, y uint16 }
+	if e = ioctl(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); e != nil {
+		return
+	}
+	return int(ws.col), int(ws.row), nil
+}
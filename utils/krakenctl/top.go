@@ -0,0 +1,408 @@
+/* top.go: krakenctl top, an interactive terminal dashboard of live node state
+ *
+ * Author: J. Lowell Wofford <lowell@lanl.gov>
+ *
+ * This software is open source software available under the BSD-3 license.
+ * Copyright (c) 2018, Triad National Security, LLC
+ * See LICENSE file for details.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hpc/kraken/modules/restapi"
+)
+
+// ANSI escapes; only these are used, so the dashboard works on any terminal, e.g. over SSH
+const (
+	ansiHome    = "\x1b[H"
+	ansiClearLn = "\x1b[K"
+	ansiClearDn = "\x1b[J"
+	ansiReverse = "\x1b[7m"
+	ansiBold    = "\x1b[1m"
+	ansiReset   = "\x1b[0m"
+	ansiHide    = "\x1b[?25l"
+	ansiShow    = "\x1b[?25h"
+	ansiAltOn   = "\x1b[?1049h"
+	ansiAltOff  = "\x1b[?1049l"
+)
+
+// stateColors color PhysStates and RunStates
+var stateColors = map[string]string{
+	"POWER_ON":     "\x1b[32m", // green
+	"POWER_OFF":    "\x1b[34m", // blue
+	"POWER_CYCLE":  "\x1b[33m", // yellow
+	"PHYS_HANG":    "\x1b[31m", // red
+	"PHYS_ERROR":   "\x1b[31m",
+	"PHYS_UNKNOWN": "\x1b[90m", // gray
+	"SYNC":         "\x1b[32m",
+	"INIT":         "\x1b[33m",
+	"ERROR":        "\x1b[31m",
+	"UNKNOWN":      "\x1b[90m",
+}
+
+// A topRow is a node, as the dashboard shows it
+type topRow struct {
+	id, name string
+	cfgPhys  string // the configured PhysState
+	phys     string // the discovered PhysState
+	run      string // the discovered RunState
+}
+
+// A topView is the state of the dashboard
+type topView struct {
+	c        *client
+	api      string
+	filter   string // a selector expression on discovered state
+	rows     []topRow
+	selected map[string]bool // by ID
+	cursor   int
+	scroll   int
+	width    int
+	height   int
+	status   string
+	updated  time.Time
+	// a prompt being answered, e.g. for a filter, or to confirm a change
+	prompt  string
+	input   string
+	onInput func(string)
+}
+
+func top(c *client, args []string) error {
+	fs := flag.NewFlagSet("top", flag.ExitOnError)
+	interval := fs.Duration("interval", 2*time.Second, "how often to refresh")
+	filter := fs.String("filter", "", "only show nodes whose discovered state matches a selector expression")
+	fs.Parse(args)
+	restore, e := makeRaw(int(os.Stdin.Fd()))
+	if e != nil {
+		return fmt.Errorf("top needs a terminal: %v", e)
+	}
+	os.Stdout.WriteString(ansiAltOn + ansiHide)
+	defer func() {
+		os.Stdout.WriteString(ansiShow + ansiAltOff)
+		restore()
+	}()
+
+	v := &topView{c: c, api: c.base, filter: *filter, selected: make(map[string]bool)}
+	keys := make(chan string)
+	go func() {
+		buf := make([]byte, 32)
+		for {
+			n, e := os.Stdin.Read(buf)
+			if e != nil {
+				close(keys)
+				return
+			}
+			keys <- string(buf[:n])
+		}
+	}()
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+
+	v.size()
+	v.refresh()
+	for {
+		v.draw()
+		select {
+		case k, ok := <-keys:
+			if !ok || !v.key(k) {
+				return nil
+			}
+		case <-tick.C:
+			v.refresh()
+		case <-winch:
+			v.size()
+		}
+	}
+}
+
+// size gets the size of the terminal
+func (v *topView) size() {
+	v.width, v.height = 80, 24
+	if w, h, e := winSize(int(os.Stdout.Fd())); e == nil && w > 0 {
+		v.width, v.height = w, h
+	}
+}
+
+// refresh gets the nodes' state
+func (v *topView) refresh() {
+	ids, cfgs, e := v.c.fields("/cfg/nodes", url.Values{}, []string{"/Nodename", "/PhysState"})
+	if e == nil {
+		q := url.Values{}
+		if v.filter != "" {
+			q.Set("q", v.filter)
+		}
+		var dids []string
+		var dscs map[string][]value
+		if dids, dscs, e = v.c.fields("/dsc/nodes", q, []string{"/PhysState", "/RunState"}); e == nil {
+			v.rows = v.rows[:0]
+			for _, id := range dids {
+				r := topRow{id: id, name: id, cfgPhys: "PHYS_UNKNOWN", phys: "PHYS_UNKNOWN", run: "UNKNOWN"}
+				for _, f := range cfgs[id] {
+					switch f.url {
+					case "/Nodename":
+						if f.val != "" {
+							r.name = f.val
+						}
+					case "/PhysState":
+						r.cfgPhys = f.val
+					}
+				}
+				for _, f := range dscs[id] {
+					switch f.url {
+					case "/PhysState":
+						r.phys = f.val
+					case "/RunState":
+						r.run = f.val
+					}
+				}
+				v.rows = append(v.rows, r)
+			}
+			sort.Slice(v.rows, func(i, j int) bool { return v.rows[i].name < v.rows[j].name })
+			// forget selections of nodes that have gone
+			known := make(map[string]bool)
+			for _, id := range ids {
+				known[id] = true
+			}
+			for id := range v.selected {
+				if !known[id] {
+					delete(v.selected, id)
+				}
+			}
+			v.updated = time.Now()
+		}
+	}
+	if e != nil {
+		v.status = e.Error()
+	}
+	if v.cursor >= len(v.rows) {
+		v.cursor = len(v.rows) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// key handles a key press; it returns false to quit
+func (v *topView) key(k string) bool {
+	if v.onInput != nil {
+		return v.promptKey(k)
+	}
+	page := v.height - 5
+	switch k {
+	case "q", "\x03":
+		return false
+	case "\x1b[A", "k":
+		v.cursor--
+	case "\x1b[B", "j":
+		v.cursor++
+	case "\x1b[5~":
+		v.cursor -= page
+	case "\x1b[6~", " ":
+		if k == " " && len(v.rows) > 0 {
+			id := v.rows[v.cursor].id
+			v.selected[id] = !v.selected[id]
+			if !v.selected[id] {
+				delete(v.selected, id)
+			}
+			v.cursor++
+			break
+		}
+		v.cursor += page
+	case "\x1b[H", "g":
+		v.cursor = 0
+	case "\x1b[F", "G":
+		v.cursor = len(v.rows) - 1
+	case "a":
+		for _, r := range v.rows {
+			v.selected[r.id] = true
+		}
+	case "n":
+		v.selected = make(map[string]bool)
+	case "r":
+		v.refresh()
+	case "/":
+		v.ask("filter (e.g. /RunState == ERROR): ", v.filter, func(s string) {
+			v.filter = strings.TrimSpace(s)
+			v.refresh()
+		})
+	case "o", "f", "c":
+		v.power(map[string]string{"o": "on", "f": "off", "c": "cycle"}[k])
+	case "s":
+		ids := v.targets()
+		if len(ids) == 0 {
+			break
+		}
+		v.ask(fmt.Sprintf("set on %d nodes (<url> <value>): ", len(ids)), "", func(s string) {
+			sp := strings.Fields(s)
+			if len(sp) != 2 {
+				v.status = "expected <url> <value>"
+				return
+			}
+			v.change(ids, sp[0], sp[1])
+		})
+	}
+	if v.cursor >= len(v.rows) {
+		v.cursor = len(v.rows) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+	return true
+}
+
+// promptKey edits the answer to a prompt
+func (v *topView) promptKey(k string) bool {
+	switch k {
+	case "\r", "\n":
+		f := v.onInput
+		v.prompt, v.onInput = "", nil
+		f(v.input)
+	case "\x1b", "\x03":
+		v.prompt, v.onInput = "", nil
+	case "\x7f", "\b":
+		if len(v.input) > 0 {
+			v.input = v.input[:len(v.input)-1]
+		}
+	default:
+		if !strings.HasPrefix(k, "\x1b") {
+			v.input += k
+		}
+	}
+	return true
+}
+
+func (v *topView) ask(prompt, input string, f func(string)) {
+	v.prompt, v.input, v.onInput = prompt, input, f
+}
+
+// targets are the selected nodes, or the one under the cursor if none are
+func (v *topView) targets() (ids []string) {
+	for _, r := range v.rows {
+		if v.selected[r.id] {
+			ids = append(ids, r.id)
+		}
+	}
+	if len(ids) == 0 && len(v.rows) > 0 {
+		ids = []string{v.rows[v.cursor].id}
+	}
+	return
+}
+
+// power asks to confirm a power change to the targets, then makes it
+func (v *topView) power(op string) {
+	ids := v.targets()
+	if len(ids) == 0 {
+		return
+	}
+	v.ask(fmt.Sprintf("power %s %d nodes? (y/n) ", op, len(ids)), "", func(s string) {
+		if strings.ToLower(strings.TrimSpace(s)) == "y" {
+			v.change(ids, "/PhysState", powerStates[op])
+		}
+	})
+}
+
+// change sets url to val on nodes, with a bulk request
+func (v *topView) change(ids []string, url, val string) {
+	b, _ := json.Marshal(restapi.BulkRequest{Hosts: strings.Join(ids, ","), Set: map[string]string{url: val}})
+	rb, e := v.c.do("POST", "/cfg/bulk", b)
+	if e != nil {
+		v.status = e.Error()
+		return
+	}
+	var rsp restapi.BulkResponse
+	json.Unmarshal(rb, &rsp)
+	failed := []string{}
+	for _, r := range rsp.Results {
+		if r.Error != "" {
+			failed = append(failed, r.Nodename+": "+r.Error)
+		}
+	}
+	v.status = fmt.Sprintf("set %s=%s on %d of %d nodes", url, val, len(rsp.Results)-len(failed), len(rsp.Results))
+	if len(failed) > 0 {
+		v.status += "; " + strings.Join(failed, "; ")
+	}
+	v.refresh()
+}
+
+// draw redraws the screen, without clearing it first so it doesn't flicker
+func (v *topView) draw() {
+	var buf bytes.Buffer
+	line := func(s string) {
+		buf.WriteString(s + ansiClearLn + "\r\n")
+	}
+	buf.WriteString(ansiHome)
+	hdr := fmt.Sprintf(" kraken %s  nodes: %d  selected: %d  updated: %s", v.api, len(v.rows), len(v.selected), v.updated.Format("15:04:05"))
+	if v.filter != "" {
+		hdr += "  filter: " + v.filter
+	}
+	line(ansiReverse + pad(hdr, v.width) + ansiReset)
+	line(ansiBold + fmt.Sprintf("  %-24s %-14s %-14s %-10s", "NODE", "PHYS (CFG)", "PHYS", "RUN") + ansiReset)
+	rows := v.height - 5
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+rows {
+		v.scroll = v.cursor - rows + 1
+	}
+	for i := v.scroll; i < v.scroll+rows; i++ {
+		if i >= len(v.rows) {
+			line("")
+			continue
+		}
+		r := v.rows[i]
+		mark := " "
+		if v.selected[r.id] {
+			mark = "*"
+		}
+		// a node that's mutating shows where it's going
+		pending := " "
+		if r.cfgPhys != r.phys {
+			pending = ">"
+		}
+		s := fmt.Sprintf("%s %-24s %s%-14s%s%s%s%-14s%s %s%-10s%s", mark, trunc(r.name, 24),
+			stateColors[r.cfgPhys], r.cfgPhys, ansiReset, pending,
+			stateColors[r.phys], r.phys, ansiReset,
+			stateColors[r.run], r.run, ansiReset)
+		if i == v.cursor {
+			s = ansiReverse + strings.Replace(s, ansiReset, ansiReset+ansiReverse, -1) + ansiReset
+		}
+		line(s)
+	}
+	if v.onInput != nil {
+		line(ansiBold + v.prompt + ansiReset + v.input + "_")
+	} else {
+		line(trunc(v.status, v.width))
+	}
+	buf.WriteString(ansiReverse + pad(" ↑/↓ move  space select  a all  n none  o on  f off  c cycle  s set  / filter  r refresh  q quit", v.width) + ansiReset + ansiClearDn)
+	os.Stdout.Write(buf.Bytes())
+}
+
+func pad(s string, w int) string {
+	if n := w - len([]rune(s)); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return trunc(s, w)
+}
+
+func trunc(s string, w int) string {
+	if r := []rune(s); len(r) > w {
+		return string(r[:w])
+	}
+	return s
+}